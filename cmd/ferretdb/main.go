@@ -19,6 +19,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -35,8 +36,11 @@ import (
 	_ "golang.org/x/crypto/x509roots/fallback" // register root TLS certificates for production Docker image
 
 	"github.com/FerretDB/FerretDB/build/version"
+	"github.com/FerretDB/FerretDB/internal/bson2"
 	"github.com/FerretDB/FerretDB/internal/clientconn"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/handler"
+	"github.com/FerretDB/FerretDB/internal/handler/javascript"
 	"github.com/FerretDB/FerretDB/internal/handler/registry"
 	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/internal/util/debug"
@@ -44,6 +48,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/logging"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/systemd"
 	"github.com/FerretDB/FerretDB/internal/util/telemetry"
 )
 
@@ -53,11 +58,14 @@ import (
 // Keep order in sync with documentation.
 var cli struct {
 	Version     bool   `default:"false"           help:"Print version to stdout and exit." env:"-"`
+	PrintURIs   bool   `default:"false"           help:"Print MongoDB URIs for configured listeners to stdout and exit." env:"-" name:"print-uris"`
 	Handler     string `default:"postgresql"      help:"${help_handler}"`
 	Mode        string `default:"${default_mode}" help:"${help_mode}"                      enum:"${enum_mode}"`
 	StateDir    string `default:"."               help:"Process state directory."`
 	ReplSetName string `default:""                help:"Replica set name."`
 
+	ConfigFile kong.ConfigFlag `help:"Configuration file path; reloaded on SIGHUP." env:"-" placeholder:"PATH"`
+
 	Listen struct {
 		Addr        string `default:"127.0.0.1:27017" help:"Listen TCP address."`
 		Unix        string `default:""                help:"Listen Unix domain socket path."`
@@ -65,6 +73,13 @@ var cli struct {
 		TLSCertFile string `default:""                help:"TLS cert file path."`
 		TLSKeyFile  string `default:""                help:"TLS key file path."`
 		TLSCaFile   string `default:""                help:"TLS CA file path."`
+
+		Extra []string `help:"Additional listener URL with its own TLS and auth settings; may be repeated." placeholder:"URL" sep:";"`
+
+		Systemd bool `default:"false" help:"Accept connections on sockets passed by systemd socket activation."`
+
+		DrainTimeout time.Duration `default:"${default_drain_timeout}" help:"Wait for in-flight commands for up to that duration on shutdown."`
+		IdleTimeout  time.Duration `default:"0s"                       help:"Close connections without requests for that duration; 0 to disable."`
 	} `embed:"" prefix:"listen-"`
 
 	Proxy struct {
@@ -76,19 +91,66 @@ var cli struct {
 
 	DebugAddr string `default:"127.0.0.1:8088" help:"Listen address for HTTP handlers for metrics, pprof, etc."`
 
+	Probe struct {
+		LivenessChecks  []string      `default:"listener"         help:"${help_probe_liveness}"  enum:"${enum_probe_checks}"`
+		ReadinessChecks []string      `default:"listener,backend" help:"${help_probe_readiness}" enum:"${enum_probe_checks}"`
+		Timeout         time.Duration `default:"5s"               help:"Timeout for health and readiness checks."`
+	} `embed:"" prefix:"probe-"`
+
 	// see setCLIPlugins
 	kong.Plugins
 
 	Log struct {
-		Level  string `default:"${default_log_level}" help:"${help_log_level}"`
-		Format string `default:"console"              help:"${help_log_format}"                     enum:"${enum_log_format}"`
-		UUID   bool   `default:"false"                help:"Add instance UUID to all log messages." negatable:""`
+		Level         string        `default:"${default_log_level}" help:"${help_log_level}"`
+		Format        string        `default:"console"              help:"${help_log_format}"                          enum:"${enum_log_format}"`
+		UUID          bool          `default:"false"                help:"Add instance UUID to all log messages."      negatable:""`
+		SlowThreshold time.Duration `default:"100ms"                help:"Log queries slower than that; 0 to disable."`
 	} `embed:"" prefix:"log-"`
 
 	MetricsUUID bool `default:"false" help:"Add instance UUID to all metrics." negatable:""`
 
 	Telemetry telemetry.Flag `default:"undecided" help:"Enable or disable basic telemetry. See https://beacon.ferretdb.com."`
 
+	WriteRetry struct {
+		Timeout   time.Duration `default:"0s"  help:"Hold writes for up to that duration while the backend is unavailable; 0 to disable."`
+		MaxWrites int           `default:"100" help:"Maximum number of writes held while the backend is unavailable."`
+	} `embed:"" prefix:"write-retry-"`
+
+	RateLimit struct {
+		Global  float64       `default:"0"  help:"Maximum number of operations per second for all connections; 0 to disable."`
+		Conn    float64       `default:"0"  help:"Maximum number of operations per second for a single connection; 0 to disable."`
+		MaxWait time.Duration `default:"1s" help:"Queue operations over the rate limit for up to that duration, then reject them."`
+	} `embed:"" prefix:"rate-limit-"`
+
+	ImplicitCollection struct {
+		Policy         string            `default:"allow"    help:"${help_implicit_policy}"                                              enum:"${enum_implicit_policy}"`
+		DatabasePolicy map[string]string `default:""         help:"Per-database implicit collection creation policies as db=policy pairs separated by ';'."`
+		Template       string            `default:"template" help:"Name of the collection in the same database to copy options and indexes from."`
+	} `embed:"" prefix:"implicit-collection-"`
+
+	DefaultMaxTime time.Duration `default:"0s" help:"Limit commands without maxTimeMS to that duration; 0 to disable."`
+
+	CursorPrefetchMemory int `default:"64" help:"Prefetch next cursor batches in the background using up to that many MiB; 0 to disable."`
+
+	QueryCache struct {
+		Size int           `default:"0"   help:"Cache results of find commands using up to that many MiB; 0 to disable."`
+		TTL  time.Duration `default:"10s" help:"Drop cached results after that duration; 0 to keep them until writes invalidate them."`
+	} `embed:"" prefix:"query-cache-"`
+
+	BulkWriteConcurrency int `default:"0" help:"Execute unordered insert, update, and delete batches with up to that many concurrent writes; 0 to use the number of CPUs."`
+
+	IncCoalescingWindow time.Duration `default:"0s" help:"Write $inc updates of the same document within that window together; 0 to disable."`
+
+	ArchiveInterval time.Duration `default:"5m" help:"Apply archive policies with that interval; 0 to disable background archiving."`
+
+	DBCheckInterval time.Duration `default:"0s" help:"Check consistency of all databases with that interval; 0 to disable scheduled checks."`
+
+	JavaScript struct {
+		Enabled   bool          `default:"false" help:"Evaluate $function, $accumulator, and $where in a sandboxed JavaScript interpreter."`
+		Timeout   time.Duration `default:"1s"    help:"Interrupt JavaScript code running longer than that; 0 to disable."`
+		MaxMemory int           `default:"64"    help:"Interrupt JavaScript code that grows the heap by more than that many MiB; 0 to disable."`
+	} `embed:"" prefix:"javascript-"`
+
 	Test struct {
 		RecordsDir string `default:"" help:"Testing: directory for record files."`
 
@@ -118,7 +180,8 @@ var cli struct {
 //
 //nolint:lll // some tags are long
 var postgreSQLFlags struct {
-	PostgreSQLURL string `name:"postgresql-url" default:"postgres://127.0.0.1:5432/ferretdb" help:"PostgreSQL URL for 'postgresql' handler."`
+	PostgreSQLURL             string `name:"postgresql-url" default:"postgres://127.0.0.1:5432/ferretdb" help:"PostgreSQL URL for 'postgresql' handler."`
+	PostgreSQLTenantIsolation bool   `name:"postgresql-tenant-isolation" default:"false" help:"Isolate databases of different PostgreSQL roles for 'postgresql' handler."`
 }
 
 // The sqliteFlags struct represents flags that are used by the "sqlite" backend.
@@ -174,28 +237,39 @@ var (
 
 	logFormats = []string{"console", "json"}
 
+	probeChecks = []string{"listener", "backend"}
+
 	kongOptions = []kong.Option{
 		kong.Vars{
-			"default_log_level": defaultLogLevel().String(),
-			"default_mode":      clientconn.AllModes[0],
-
-			"enum_log_format": strings.Join(logFormats, ","),
-			"enum_mode":       strings.Join(clientconn.AllModes, ","),
-
-			"help_handler":    fmt.Sprintf("Backend handler: '%s'.", strings.Join(registry.Handlers(), "', '")),
-			"help_log_format": fmt.Sprintf("Log format: '%s'.", strings.Join(logFormats, "', '")),
-			"help_log_level":  fmt.Sprintf("Log level: '%s'.", strings.Join(logLevels, "', '")),
-			"help_mode":       fmt.Sprintf("Operation mode: '%s'.", strings.Join(clientconn.AllModes, "', '")),
+			"default_drain_timeout": clientconn.DefaultDrainTimeout.String(),
+			"default_log_level":     defaultLogLevel().String(),
+			"default_mode":          clientconn.AllModes[0],
+
+			"enum_implicit_policy": strings.Join(handler.ImplicitCollectionPolicies, ","),
+			"enum_log_format":      strings.Join(logFormats, ","),
+			"enum_mode":            strings.Join(clientconn.AllModes, ","),
+			"enum_probe_checks":    strings.Join(probeChecks, ","),
+
+			"help_handler": fmt.Sprintf("Backend handler: '%s'.", strings.Join(registry.Handlers(), "', '")),
+			"help_implicit_policy": fmt.Sprintf(
+				"Implicit collection creation policy: '%s'.", strings.Join(handler.ImplicitCollectionPolicies, "', '"),
+			),
+			"help_log_format":      fmt.Sprintf("Log format: '%s'.", strings.Join(logFormats, "', '")),
+			"help_log_level":       fmt.Sprintf("Log level: '%s'.", strings.Join(logLevels, "', '")),
+			"help_mode":            fmt.Sprintf("Operation mode: '%s'.", strings.Join(clientconn.AllModes, "', '")),
+			"help_probe_liveness":  fmt.Sprintf("Checks for /healthz debug handler: '%s'.", strings.Join(probeChecks, "', '")),
+			"help_probe_readiness": fmt.Sprintf("Checks for /readyz debug handler: '%s'.", strings.Join(probeChecks, "', '")),
 		},
 		kong.DefaultEnvars("FERRETDB"),
+		kong.Configuration(kong.JSON),
 	}
 )
 
 func main() {
 	setCLIPlugins()
-	kong.Parse(&cli, kongOptions...)
+	kongCtx := kong.Parse(&cli, kongOptions...)
 
-	run()
+	run(flagValues(kongCtx.Model))
 }
 
 // defaultLogLevel returns the default log level.
@@ -305,7 +379,9 @@ func dumpMetrics() {
 }
 
 // run sets up environment based on provided flags and runs FerretDB.
-func run() {
+//
+// Flag values are used to log changes on configuration reload.
+func run(values map[string]string) {
 	// to increase a chance of resource finalizers to spot problems
 	if debugbuild.Enabled {
 		defer func() {
@@ -331,6 +407,14 @@ func run() {
 		return
 	}
 
+	if cli.PrintURIs {
+		if err := printURIs(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
 	// safe to always enable
 	runtime.SetBlockProfileRate(10000)
 
@@ -349,6 +433,11 @@ func run() {
 	go func() {
 		<-ctx.Done()
 		logger.Info("Stopping...")
+
+		if _, err := systemd.Notify("STOPPING=1"); err != nil {
+			logger.Warn("Failed to notify systemd", zap.Error(err))
+		}
+
 		stop()
 	}()
 
@@ -358,13 +447,51 @@ func run() {
 		logger.Sugar().Fatal("--test-disable-pushdown and --test-enable-nested-pushdown should not be set at the same time")
 	}
 
+	if cli.JavaScript.Enabled {
+		javascript.Enable(&javascript.Limits{
+			Timeout:   cli.JavaScript.Timeout,
+			MaxMemory: uint64(cli.JavaScript.MaxMemory) << 20,
+		})
+	}
+
+	var endpoints []*clientconn.Endpoint
+
+	for _, s := range cli.Listen.Extra {
+		e, err := clientconn.ParseEndpoint(s)
+		if err != nil {
+			logger.Sugar().Fatalf("Failed to parse --listen-extra: %s.", err)
+		}
+
+		endpoints = append(endpoints, e)
+	}
+
+	var activated []net.Listener
+
+	if cli.Listen.Systemd {
+		var err error
+		if activated, err = systemd.Listeners(); err != nil {
+			logger.Sugar().Fatalf("Failed to get sockets passed by systemd: %s.", err)
+		}
+
+		if len(activated) == 0 {
+			logger.Sugar().Fatal("--listen-systemd is set, but no sockets were passed by systemd.")
+		}
+	}
+
+	// checks are set below, when handler and listener are created
+	probes := debug.NewProbes(&debug.NewProbesOpts{
+		LivenessChecks:  cli.Probe.LivenessChecks,
+		ReadinessChecks: cli.Probe.ReadinessChecks,
+		Timeout:         cli.Probe.Timeout,
+	})
+
 	// https://github.com/alecthomas/kong/issues/389
 	if cli.DebugAddr != "" && cli.DebugAddr != "-" {
 		wg.Add(1)
 
 		go func() {
 			defer wg.Done()
-			debug.RunHandler(ctx, cli.DebugAddr, metricsRegisterer, logger.Named("debug"))
+			debug.RunHandler(ctx, cli.DebugAddr, metricsRegisterer, probes, logger.Named("debug"))
 		}()
 	}
 
@@ -398,7 +525,32 @@ func run() {
 		TCPHost:       cli.Listen.Addr,
 		ReplSetName:   cli.ReplSetName,
 
-		PostgreSQLURL: postgreSQLFlags.PostgreSQLURL,
+		SlowQueryThreshold: cli.Log.SlowThreshold,
+
+		WriteRetryTimeout:   cli.WriteRetry.Timeout,
+		WriteRetryMaxWrites: cli.WriteRetry.MaxWrites,
+
+		ImplicitCollectionPolicy:           cli.ImplicitCollection.Policy,
+		ImplicitCollectionDatabasePolicies: cli.ImplicitCollection.DatabasePolicy,
+		ImplicitCollectionTemplate:         cli.ImplicitCollection.Template,
+
+		DefaultMaxTime: cli.DefaultMaxTime,
+
+		CursorPrefetchMemory: int64(cli.CursorPrefetchMemory) << 20,
+
+		QueryCacheSize: int64(cli.QueryCache.Size) << 20,
+		QueryCacheTTL:  cli.QueryCache.TTL,
+
+		BulkWriteConcurrency: cli.BulkWriteConcurrency,
+
+		IncCoalescingWindow: cli.IncCoalescingWindow,
+
+		ArchiveInterval: cli.ArchiveInterval,
+
+		DBCheckInterval: cli.DBCheckInterval,
+
+		PostgreSQLURL:             postgreSQLFlags.PostgreSQLURL,
+		PostgreSQLTenantIsolation: postgreSQLFlags.PostgreSQLTenantIsolation,
 
 		SQLiteURL: sqliteFlags.SQLiteURL,
 
@@ -429,11 +581,21 @@ func run() {
 		TLSKeyFile:  cli.Listen.TLSKeyFile,
 		TLSCAFile:   cli.Listen.TLSCaFile,
 
+		Endpoints: endpoints,
+		Activated: activated,
+
 		ProxyAddr:        cli.Proxy.Addr,
 		ProxyTLSCertFile: cli.Proxy.TLSCertFile,
 		ProxyTLSKeyFile:  cli.Proxy.TLSKeyFile,
 		ProxyTLSCAFile:   cli.Proxy.TLSCaFile,
 
+		DrainTimeout: cli.Listen.DrainTimeout,
+		IdleTimeout:  cli.Listen.IdleTimeout,
+
+		RateLimit:        cli.RateLimit.Global,
+		ConnRateLimit:    cli.RateLimit.Conn,
+		RateLimitMaxWait: cli.RateLimit.MaxWait,
+
 		Mode:           clientconn.Mode(cli.Mode),
 		Metrics:        metrics,
 		Handler:        h,
@@ -442,6 +604,36 @@ func run() {
 	})
 
 	metricsRegisterer.MustRegister(l)
+	metricsRegisterer.MustRegister(bson2.Collector())
+
+	probes.Set("backend", h.CheckBackend)
+	probes.Set("listener", l.Check)
+
+	r := &reloader{
+		l:      logger.Named("reload"),
+		h:      h,
+		lis:    l,
+		values: values,
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		ctxutil.SigHup(ctx, r.reload)
+	}()
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		notifyReady(
+			ctx, logger.Named("systemd"),
+			readinessCheck{name: "listener", check: l.Check},
+			readinessCheck{name: "backend", check: h.CheckBackend},
+		)
+	}()
 
 	err = l.Run(ctx)
 	if err == nil || errors.Is(err, context.Canceled) {
@@ -0,0 +1,209 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn"
+	"github.com/FerretDB/FerretDB/internal/handler"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// reloadableFlags contains names of flags that are applied on SIGHUP without restart.
+//
+// Keep in sync with documentation.
+var reloadableFlags = []string{
+	"log-level",
+	"log-slow-threshold",
+	"listen-tls-cert-file",
+	"listen-tls-key-file",
+	"listen-tls-ca-file",
+}
+
+// tlsFlags contains names of reloadable flags for TLS files.
+var tlsFlags = []string{
+	"listen-tls-cert-file",
+	"listen-tls-key-file",
+	"listen-tls-ca-file",
+}
+
+// parseFlags parses command-line arguments, environment variables, and the configuration file
+// into a copy of cli without modifying it.
+//
+// It returns string representations of all flag values, keyed by flag name.
+func parseFlags(args []string) (map[string]string, error) {
+	next := cli
+
+	// parse handler flags into copies too
+	next.Plugins = make(kong.Plugins, len(cli.Plugins))
+	for i, p := range cli.Plugins {
+		next.Plugins[i] = reflect.New(reflect.TypeOf(p).Elem()).Interface()
+	}
+
+	parser, err := kong.New(&next, kongOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = parser.Parse(args); err != nil {
+		return nil, err
+	}
+
+	return flagValues(parser.Model), nil
+}
+
+// flagValues returns string representations of all flag values of the given application, keyed by flag name.
+func flagValues(app *kong.Application) map[string]string {
+	res := map[string]string{}
+
+	for _, group := range app.AllFlags(false) {
+		for _, f := range group {
+			if f.Name == "help" {
+				continue
+			}
+
+			res[f.Name] = fmt.Sprint(f.Target.Interface())
+		}
+	}
+
+	return res
+}
+
+// changedFlags returns sorted names of flags with different values.
+func changedFlags(prev, next map[string]string) []string {
+	var res []string
+
+	for name, v := range next {
+		if pv, ok := prev[name]; !ok || pv != v {
+			res = append(res, name)
+		}
+	}
+
+	slices.Sort(res)
+
+	return res
+}
+
+// reloader applies configuration changes on SIGHUP.
+type reloader struct {
+	l      *zap.Logger
+	h      *handler.Handler
+	lis    *clientconn.Listener
+	values map[string]string // currently applied flag values
+}
+
+// reload re-reads the configuration and applies changes of reloadable flags,
+// logging what changed.
+//
+// Changes of other flags are logged, but not applied.
+// TLS files are re-read even if their paths did not change to pick up renewed certificates.
+func (r *reloader) reload() {
+	r.l.Info("Reloading configuration ...")
+
+	values, err := parseFlags(os.Args[1:])
+	if err != nil {
+		r.l.Error("Failed to reload configuration, keeping the current one", zap.Error(err))
+		return
+	}
+
+	var applied, tlsChanged, restart []string
+
+	for _, name := range changedFlags(r.values, values) {
+		switch {
+		case !slices.Contains(reloadableFlags, name):
+			restart = append(restart, name)
+			continue
+
+		case slices.Contains(tlsFlags, name):
+			tlsChanged = append(tlsChanged, name)
+			continue
+		}
+
+		if err = r.apply(name, values[name]); err != nil {
+			r.l.Error("Failed to apply flag change", zap.String("flag", name), zap.Error(err))
+			continue
+		}
+
+		applied = append(applied, name)
+	}
+
+	// TLS files are loaded together, and only if all of them are valid
+	if r.lis.TLS != "" {
+		err = r.lis.ReloadTLS(values["listen-tls-cert-file"], values["listen-tls-key-file"], values["listen-tls-ca-file"])
+		if err != nil {
+			r.l.Error("Failed to reload TLS files, keeping the current ones", zap.Error(err))
+			tlsChanged = nil
+		}
+	}
+
+	if err = r.lis.ReloadEndpointsTLS(); err != nil {
+		r.l.Error("Failed to reload TLS files of additional listeners, keeping the current ones", zap.Error(err))
+	}
+
+	applied = append(applied, tlsChanged...)
+	slices.Sort(applied)
+
+	for _, name := range applied {
+		r.l.Info("Flag changed", zap.String("flag", name), zap.String("old", r.values[name]), zap.String("new", values[name]))
+		r.values[name] = values[name]
+	}
+
+	if len(restart) > 0 {
+		// values are not logged as they may contain credentials
+		r.l.Warn("Changed flags require restart and were not applied", zap.Strings("flags", restart))
+	}
+
+	r.l.Info("Configuration reloaded.", zap.Int("applied", len(applied)))
+
+	// change level last so the messages above are not suppressed
+	if slices.Contains(applied, "log-level") {
+		logging.SetLevel(must.NotFail(zapcore.ParseLevel(r.values["log-level"])))
+	}
+}
+
+// apply applies a new value of the given reloadable flag except TLS files.
+//
+// Log level is only validated; it is changed by the caller.
+func (r *reloader) apply(name, value string) error {
+	switch name {
+	case "log-level":
+		if _, err := zapcore.ParseLevel(value); err != nil {
+			return err
+		}
+
+	case "log-slow-threshold":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		r.h.SetSlowQueryThreshold(d)
+
+	default:
+		panic(fmt.Sprintf("flag %q can't be applied", name))
+	}
+
+	return nil
+}
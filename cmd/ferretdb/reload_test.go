@@ -0,0 +1,57 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlags(t *testing.T) {
+	t.Parallel()
+
+	f := filepath.Join(t.TempDir(), "ferretdb.json")
+	require.NoError(t, os.WriteFile(f, []byte(`{"log_level": "warn", "logSlowThreshold": "1s"}`), 0o666))
+
+	prev, err := parseFlags(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "100ms", prev["log-slow-threshold"])
+	assert.NotContains(t, prev, "help")
+
+	next, err := parseFlags([]string{"--config-file=" + f})
+	require.NoError(t, err)
+	assert.Equal(t, "warn", next["log-level"])
+	assert.Equal(t, "1s", next["log-slow-threshold"])
+
+	expected := []string{"config-file", "log-level", "log-slow-threshold"}
+	if prev["log-level"] == "warn" {
+		expected = []string{"config-file", "log-slow-threshold"}
+	}
+
+	assert.Equal(t, expected, changedFlags(prev, next))
+
+	// command-line flags take precedence over the configuration file
+	next, err = parseFlags([]string{"--config-file=" + f, "--log-level=error"})
+	require.NoError(t, err)
+	assert.Equal(t, "error", next["log-level"])
+	assert.Equal(t, "1s", next["log-slow-threshold"])
+
+	_, err = parseFlags([]string{"--config-file=" + filepath.Join(t.TempDir(), "missing.json")})
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
+	"github.com/FerretDB/FerretDB/internal/util/systemd"
+)
+
+// readinessCheck represents a named check that should pass before systemd is notified.
+type readinessCheck struct {
+	name  string
+	check func(context.Context) error
+}
+
+// notifyReady notifies systemd that FerretDB is ready once all checks pass in order,
+// retrying them until ctx is canceled.
+//
+// It does nothing if FerretDB is not started by systemd with the "notify" service type.
+func notifyReady(ctx context.Context, logger *zap.Logger, checks ...readinessCheck) {
+	if !systemd.NotifyEnabled() {
+		return
+	}
+
+	var retry int64
+
+	for {
+		var failed string
+
+		for _, c := range checks {
+			checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			err := c.check(checkCtx)
+			cancel()
+
+			if err != nil {
+				if retry == 0 {
+					logger.Info("Waiting for the check to pass before notifying systemd", zap.String("check", c.name), zap.Error(err))
+				}
+
+				failed = c.name
+
+				break
+			}
+		}
+
+		if failed == "" {
+			break
+		}
+
+		if _, err := systemd.Notify("STATUS=Waiting for " + failed); err != nil {
+			logger.Warn("Failed to notify systemd", zap.Error(err))
+		}
+
+		retry++
+		ctxutil.SleepWithJitter(ctx, time.Second, retry)
+
+		if ctx.Err() != nil {
+			return
+		}
+	}
+
+	sent, err := systemd.Notify("READY=1\nSTATUS=Ready")
+
+	switch {
+	case err != nil:
+		logger.Warn("Failed to notify systemd", zap.Error(err))
+	case sent:
+		logger.Info("Notified systemd that FerretDB is ready.")
+	}
+}
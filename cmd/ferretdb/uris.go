@@ -0,0 +1,76 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn"
+)
+
+// printURIs prints MongoDB URIs for all configured listeners.
+//
+// URIs for listeners that require authentication contain USERNAME and PASSWORD placeholders.
+func printURIs(w io.Writer) error {
+	var endpoints []*clientconn.Endpoint
+
+	if cli.Listen.Addr != "" {
+		endpoints = append(endpoints, &clientconn.Endpoint{Network: "tcp", Addr: cli.Listen.Addr})
+	}
+
+	if cli.Listen.Unix != "" {
+		endpoints = append(endpoints, &clientconn.Endpoint{Network: "unix", Addr: cli.Listen.Unix})
+	}
+
+	if cli.Listen.TLS != "" {
+		endpoints = append(endpoints, &clientconn.Endpoint{
+			Network:   "tls",
+			Addr:      cli.Listen.TLS,
+			TLSCAFile: cli.Listen.TLSCaFile,
+		})
+	}
+
+	for _, s := range cli.Listen.Extra {
+		e, err := clientconn.ParseEndpoint(s)
+		if err != nil {
+			return fmt.Errorf("failed to parse --listen-extra: %w", err)
+		}
+
+		endpoints = append(endpoints, e)
+	}
+
+	mechanism := "PLAIN"
+	if cli.Test.EnableNewAuth {
+		mechanism = "SCRAM-SHA-256"
+	}
+
+	for _, e := range endpoints {
+		opts := &clientconn.URIOpts{
+			AuthMechanism: mechanism,
+		}
+
+		if e.RequireAuth {
+			opts.Username = "USERNAME"
+			opts.Password = "PASSWORD"
+		}
+
+		if _, err := fmt.Fprintf(w, "%s: %s\n", e.Network, e.MongoDBURI(opts)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
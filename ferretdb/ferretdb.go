@@ -22,8 +22,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/url"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -35,14 +38,25 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/state"
 )
 
+// Handlers that could be used in Config.
+const (
+	HandlerPostgreSQL = "postgresql"
+	HandlerSQLite     = "sqlite"
+)
+
 // Config represents FerretDB configuration.
+//
+// Embedded FerretDB does not start the debug HTTP handler with metrics, pprof, etc.
 type Config struct {
 	Listener ListenerConfig
 
 	// Logger to use; if nil, it uses the default global logger.
 	Logger *zap.Logger
 
-	// Handler to use; one of `postgresql` or `sqlite`.
+	// slog logger to use instead of Logger; they could not be set at the same time.
+	SlogLogger *slog.Logger
+
+	// Handler to use; one of HandlerPostgreSQL or HandlerSQLite.
 	Handler string
 
 	// PostgreSQL connection string for `postgresql` handler.
@@ -55,6 +69,19 @@ type Config struct {
 	// SQLite URI (directory) for `sqlite` handler.
 	// See https://www.sqlite.org/uri.html.
 	SQLiteURL string // For example: `file:data/`.
+
+	// Process state directory.
+	// If empty, state (including instance UUID) is not persisted.
+	StateDir string
+
+	// Maximum BSON object size in bytes, reported as `maxBsonObjectSize`.
+	// If zero, the default value of 16 MiB is used; the upper limit is 256 MiB.
+	MaxBSONObjectSize int32
+
+	// Maximum wire protocol message size in bytes, reported as `maxMessageSizeBytes`.
+	// It should not be less than MaxBSONObjectSize.
+	// If zero, the larger of 48000000 bytes and three MaxBSONObjectSize values is used; the upper limit is 1 GiB.
+	MaxMessageSize int32
 }
 
 // ListenerConfig represents listener configuration.
@@ -79,6 +106,10 @@ type ListenerConfig struct {
 
 	// Root CA certificate path.
 	TLSCAFile string
+
+	// In-flight commands are allowed to complete for up to that duration on shutdown.
+	// If zero, the default value of 10 seconds is used.
+	DrainTimeout time.Duration
 }
 
 // FerretDB represents an instance of embeddable FerretDB implementation.
@@ -88,6 +119,12 @@ type FerretDB struct {
 	closeBackend func()
 
 	l *clientconn.Listener
+
+	m        sync.Mutex
+	running  bool          // true if Run was called
+	stop     chan struct{} // closed by Close
+	stopOnce sync.Once
+	done     chan struct{} // closed when Run returns
 }
 
 // New creates a new instance of embeddable FerretDB implementation.
@@ -100,7 +137,20 @@ func New(config *Config) (*FerretDB, error) {
 		return nil, errors.New("Listener TCP, Unix and TLS are empty")
 	}
 
-	sp, err := state.NewProvider("")
+	if config.Logger != nil && config.SlogLogger != nil {
+		return nil, errors.New("Logger and SlogLogger should not be set at the same time")
+	}
+
+	var stateFile string
+
+	if config.StateDir != "" {
+		var err error
+		if stateFile, err = filepath.Abs(filepath.Join(config.StateDir, "state.json")); err != nil {
+			return nil, fmt.Errorf("failed to get path for state file: %s", err)
+		}
+	}
+
+	sp, err := state.NewProvider(stateFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct handler: %s", err)
 	}
@@ -117,11 +167,15 @@ func New(config *Config) (*FerretDB, error) {
 
 	metrics := connmetrics.NewListenerMetrics()
 
-	log := config.Logger
-	if log == nil {
+	var log *zap.Logger
+
+	switch {
+	case config.Logger != nil:
+		log = logging.WithHooks(config.Logger)
+	case config.SlogLogger != nil:
+		log = logging.WithHooks(logging.ZapFromSlog(config.SlogLogger))
+	default:
 		log = getGlobalLogger()
-	} else {
-		log = logging.WithHooks(log)
 	}
 
 	h, closeBackend, err := registry.NewHandler(config.Handler, &registry.NewHandlerOpts{
@@ -134,6 +188,9 @@ func New(config *Config) (*FerretDB, error) {
 
 		SQLiteURL: config.SQLiteURL,
 
+		MaxBSONObjectSize: config.MaxBSONObjectSize,
+		MaxMessageSize:    config.MaxMessageSize,
+
 		TestOpts: registry.TestOpts{
 			CappedCleanupPercentage: 10, // handler expects it to be a non-zero value
 		},
@@ -151,6 +208,8 @@ func New(config *Config) (*FerretDB, error) {
 		TLSKeyFile:  config.Listener.TLSKeyFile,
 		TLSCAFile:   config.Listener.TLSCAFile,
 
+		DrainTimeout: config.Listener.DrainTimeout,
+
 		Mode:    clientconn.NormalMode,
 		Metrics: metrics,
 		Handler: h,
@@ -159,21 +218,50 @@ func New(config *Config) (*FerretDB, error) {
 
 	return &FerretDB{
 		config:       config,
-		closeBackend: closeBackend,
+		closeBackend: sync.OnceFunc(closeBackend),
 		l:            l,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
 	}, nil
 }
 
-// Run runs FerretDB until ctx is canceled.
+// Run runs FerretDB until ctx is canceled or [*FerretDB.Close] is called.
 //
 // When this method returns, listener and all connections, as well as handler are closed.
 //
 // It is required to run this method in order to initialize the listeners with their respective
 // IP address and port. Calling methods which require the listener's address (eg: [*FerretDB.MongoDBURI]
 // requires it for configuring its Host URL) before calling this method might result in a deadlock.
+//
+// This method should be called at most once.
 func (f *FerretDB) Run(ctx context.Context) error {
+	f.m.Lock()
+
+	select {
+	case <-f.stop:
+		f.m.Unlock()
+		return errors.New("FerretDB is closed")
+	default:
+	}
+
+	f.running = true
+
+	f.m.Unlock()
+
+	defer close(f.done)
 	defer f.closeBackend()
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-f.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
 	err := f.l.Run(ctx)
 	if errors.Is(err, context.Canceled) {
 		err = nil
@@ -188,6 +276,44 @@ func (f *FerretDB) Run(ctx context.Context) error {
 	return err
 }
 
+// Close gracefully stops FerretDB started by [*FerretDB.Run]:
+// it stops accepting new connections, waits for in-flight commands to complete
+// for up to ListenerConfig.DrainTimeout, and closes the handler and the backend.
+//
+// It returns ctx's error if ctx is canceled or its deadline is exceeded before FerretDB is stopped;
+// in that case, stopping continues in the background.
+//
+// If Run was not called, Close just releases resources.
+// It is safe to call this method multiple times and concurrently with Run.
+func (f *FerretDB) Close(ctx context.Context) error {
+	f.m.Lock()
+
+	running := f.running
+
+	f.stopOnce.Do(func() {
+		close(f.stop)
+
+		// Run will not start, so release resources there
+		if !running {
+			f.l.Handler.Close()
+			f.closeBackend()
+		}
+	})
+
+	f.m.Unlock()
+
+	if !running {
+		return nil
+	}
+
+	select {
+	case <-f.done:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
 // MongoDBURI returns MongoDB URI for this FerretDB instance.
 //
 // TCP's connection string is returned if both TCP and Unix listeners are enabled.
@@ -225,6 +351,49 @@ func (f *FerretDB) MongoDBURI() string {
 	return u.String()
 }
 
+// MongoDBURIs returns MongoDB URIs for all enabled listeners of this FerretDB instance,
+// in the order TLS, TCP, Unix.
+//
+// If username is not empty, URIs contain credentials and authentication parameters.
+// URIs for TLS listener contain tlsCAFile parameter if ListenerConfig.TLSCAFile is set.
+//
+// Like [*FerretDB.MongoDBURI], it should be called after [*FerretDB.Run].
+func (f *FerretDB) MongoDBURIs(username, password string) []string {
+	opts := &clientconn.URIOpts{
+		Username: username,
+		Password: password,
+	}
+
+	var res []string
+
+	if f.config.Listener.TLS != "" {
+		e := &clientconn.Endpoint{
+			Network:   "tls",
+			Addr:      f.l.TLSAddr().String(),
+			TLSCAFile: f.config.Listener.TLSCAFile,
+		}
+		res = append(res, e.MongoDBURI(opts))
+	}
+
+	if f.config.Listener.TCP != "" {
+		e := &clientconn.Endpoint{
+			Network: "tcp",
+			Addr:    f.l.TCPAddr().String(),
+		}
+		res = append(res, e.MongoDBURI(opts))
+	}
+
+	if f.config.Listener.Unix != "" {
+		e := &clientconn.Endpoint{
+			Network: "unix",
+			Addr:    f.l.UnixAddr().String(),
+		}
+		res = append(res, e.MongoDBURI(opts))
+	}
+
+	return res
+}
+
 var (
 	loggerOnce sync.Once
 	logger     *zap.Logger
@@ -18,7 +18,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"log/slog"
+	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/FerretDB/FerretDB/ferretdb"
 )
@@ -139,3 +142,45 @@ func Example_tls() {
 
 	// Output: mongodb://127.0.0.1:17028/?tls=true
 }
+
+func Example_sqlite() {
+	dir, err := os.MkdirTemp("", "ferretdb-example")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	f, err := ferretdb.New(&ferretdb.Config{
+		Listener: ferretdb.ListenerConfig{
+			TCP:          "127.0.0.1:17029",
+			DrainTimeout: time.Second,
+		},
+		SlogLogger: slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})),
+		Handler:    ferretdb.HandlerSQLite,
+		SQLiteURL:  "file:" + dir + "/",
+		StateDir:   dir,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go func() {
+		log.Print(f.Run(context.Background()))
+	}()
+
+	uri := f.MongoDBURI()
+	fmt.Println(uri)
+
+	// Use MongoDB URI as usual.
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// wait for in-flight commands to complete and close FerretDB
+	if err = f.Close(ctx); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output: mongodb://127.0.0.1:17029/
+}
@@ -0,0 +1,179 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ferretdb
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+)
+
+// ValidateMongoDBURI checks that the given MongoDB URI could be used to connect to FerretDB.
+//
+// It returns an error describing the first found problem, if any.
+// The error never contains the password.
+// Options that are not known to affect FerretDB connections are checked only by the MongoDB Go driver's parser.
+func ValidateMongoDBURI(uri string) error {
+	if err := validateMongoDBURI(uri); err != nil {
+		// the error is not wrapped, so the password could not be extracted from it
+		return fmt.Errorf("invalid MongoDB URI: %s", redactPassword(uri, err.Error()))
+	}
+
+	return nil
+}
+
+// validateMongoDBURI implements ValidateMongoDBURI.
+//
+// The URI is parsed by the MongoDB Go driver, so it is checked the same way as by clients.
+func validateMongoDBURI(uri string) error {
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return errors.New("scheme is missing")
+	}
+
+	if scheme != connstring.SchemeMongoDB && scheme != connstring.SchemeMongoDBSRV {
+		return fmt.Errorf("unexpected scheme %q, expected mongodb or mongodb+srv", scheme)
+	}
+
+	srv := scheme == connstring.SchemeMongoDBSRV
+
+	// the driver uses the last value of duplicate options, so they are checked before parsing;
+	// user info is skipped the same way as the driver does that, so the password is not parsed as options
+	hostsAndOptions := rest
+	if i := strings.Index(rest, "@"); i >= 0 {
+		hostsAndOptions = rest[i+1:]
+	}
+
+	_, rawQuery, _ := strings.Cut(hostsAndOptions, "?")
+
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return fmt.Errorf("failed to parse options: %w", err)
+	}
+
+	seen := make(map[string]struct{}, len(q))
+
+	for k, vs := range q {
+		lk := strings.ToLower(k)
+
+		// option names are case-insensitive
+		if _, ok := seen[lk]; ok || len(vs) != 1 {
+			return fmt.Errorf("option %q is set more than once", k)
+		}
+
+		seen[lk] = struct{}{}
+	}
+
+	// the driver looks up DNS records for mongodb+srv URIs, so they are parsed as mongodb URIs;
+	// the driver's validation is done last, so FerretDB-specific errors are returned first
+	cs, err := connstring.Parse(connstring.SchemeMongoDB + "://" + rest)
+	if err != nil {
+		// unescaping errors contain parts of the password
+		if strings.Contains(err.Error(), "invalid password") {
+			return errors.New("failed to unescape password")
+		}
+
+		return err
+	}
+
+	if srv && (len(cs.Hosts) != 1 || strings.LastIndex(cs.Hosts[0], ":") > strings.LastIndex(cs.Hosts[0], "]")) {
+		return errors.New("mongodb+srv scheme requires a single host without port")
+	}
+
+	if strings.ContainsAny(cs.Database, "/\\. \"$") {
+		return fmt.Errorf("database name %q contains invalid characters", cs.Database)
+	}
+
+	// the driver enables TLS if those options are set
+	for _, f := range []struct {
+		name string
+		path string
+		set  bool
+	}{
+		{"tlsCAFile", cs.SSLCaFile, cs.SSLCaFileSet},
+		{"tlsCertificateKeyFile", cs.SSLClientCertificateKeyFile, cs.SSLClientCertificateKeyFileSet},
+	} {
+		if !f.set {
+			continue
+		}
+
+		if _, err := os.Stat(f.path); err != nil {
+			return fmt.Errorf("option %s: %w", f.name, err)
+		}
+	}
+
+	if mechanism := cs.AuthMechanism; mechanism != "" {
+		if err = validateMongoDBURIAuth(cs); err != nil {
+			return err
+		}
+	}
+
+	return cs.Validate()
+}
+
+// validateMongoDBURIAuth checks that the authentication mechanism of MongoDB URI is supported by FerretDB.
+func validateMongoDBURIAuth(cs *connstring.ConnString) error {
+	mechanism := cs.AuthMechanism
+
+	switch mechanism {
+	case "PLAIN", "SCRAM-SHA-1", "SCRAM-SHA-256":
+	default:
+		return fmt.Errorf(
+			"authMechanism %q is not supported by FerretDB, expected PLAIN, SCRAM-SHA-1, or SCRAM-SHA-256",
+			mechanism,
+		)
+	}
+
+	if cs.Username == "" {
+		return fmt.Errorf("authMechanism %s requires username", mechanism)
+	}
+
+	if !cs.PasswordSet {
+		return fmt.Errorf("authMechanism %s requires password", mechanism)
+	}
+
+	return nil
+}
+
+// redactPassword replaces the password of the given URI in the message.
+//
+// The password may contain unescaped '@' characters, so both possible user info parts are checked:
+// up to the first '@' (as the MongoDB Go driver parses it) and up to the last one.
+func redactPassword(uri, msg string) string {
+	_, rest, _ := strings.Cut(uri, "://")
+
+	for _, i := range []int{strings.Index(rest, "@"), strings.LastIndex(rest, "@")} {
+		if i < 0 {
+			continue
+		}
+
+		_, password, ok := strings.Cut(rest[:i], ":")
+		if !ok || password == "" {
+			continue
+		}
+
+		msg = strings.ReplaceAll(msg, password, "xxxxx")
+
+		if unescaped, err := url.PathUnescape(password); err == nil && unescaped != "" {
+			msg = strings.ReplaceAll(msg, unescaped, "xxxxx")
+		}
+	}
+
+	return msg
+}
@@ -1289,6 +1289,7 @@ func TestAggregateCompatMatch(t *testing.T) {
 			pipeline: bson.A{
 				bson.D{{"$match", bson.D{{"v.foo", int32(42)}}}},
 			},
+			resultPushdown: pgPushdown,
 		},
 		"DotNotationArray": {
 			pipeline: bson.A{
@@ -0,0 +1,244 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateSearch(t *testing.T) {
+	t.Parallel()
+
+	if !setup.IsPostgreSQL(t) {
+		t.Skip("Full-text search is supported only by the PostgreSQL backend")
+	}
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"title", "The quick brown fox"}, {"v", bson.D{{"plot", "It jumps over the lazy dog"}}}},
+		bson.D{{"_id", int32(2)}, {"title", "Quick thinking"}, {"v", bson.D{{"plot", "A story about foxes"}}}},
+		bson.D{{"_id", int32(3)}, {"title", "Slow turtle"}},
+	})
+	require.NoError(t, err)
+
+	definition := bson.D{{"mappings", bson.D{
+		{"dynamic", false},
+		{"fields", bson.D{
+			{"title", bson.A{bson.D{{"type", "string"}}, bson.D{{"type", "autocomplete"}}}},
+			{"v", bson.D{{"type", "document"}, {"fields", bson.D{{"plot", bson.D{{"type", "string"}}}}}}},
+		}},
+	}}}
+
+	var res bson.D
+	err = db.RunCommand(ctx, bson.D{
+		{"createSearchIndexes", collection.Name()},
+		{"indexes", bson.A{bson.D{{"name", "default"}, {"definition", definition}}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	expected := bson.D{
+		{"indexesCreated", bson.A{bson.D{{"id", "default"}, {"name", "default"}}}},
+		{"ok", float64(1)},
+	}
+	AssertEqualDocuments(t, expected, res)
+
+	cursor, err := collection.Aggregate(ctx, bson.A{bson.D{{"$listSearchIndexes", bson.D{}}}})
+	require.NoError(t, err)
+
+	var indexes []bson.D
+	require.NoError(t, cursor.All(ctx, &indexes))
+	require.Len(t, indexes, 1)
+
+	expected = bson.D{
+		{"id", "default"},
+		{"name", "default"},
+		{"type", "search"},
+		{"status", "READY"},
+		{"queryable", true},
+		{"latestDefinition", definition},
+	}
+	AssertEqualDocuments(t, expected, indexes[0])
+
+	search := func(t *testing.T, stage bson.D) []any {
+		t.Helper()
+
+		cursor, err := collection.Aggregate(ctx, bson.A{
+			bson.D{{"$search", stage}},
+			bson.D{{"$project", bson.D{{"_id", 1}}}},
+		})
+		require.NoError(t, err)
+
+		var docs []bson.D
+		require.NoError(t, cursor.All(ctx, &docs))
+
+		ids := make([]any, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc[0].Value
+		}
+
+		return ids
+	}
+
+	for name, tc := range map[string]struct {
+		stage    bson.D
+		expected []any
+	}{
+		"Text": {
+			stage:    bson.D{{"text", bson.D{{"query", "quick"}, {"path", "title"}}}},
+			expected: []any{int32(1), int32(2)},
+		},
+		"TextStemming": {
+			stage:    bson.D{{"text", bson.D{{"query", "fox"}, {"path", bson.A{"title", "v.plot"}}}}},
+			expected: []any{int32(1), int32(2)},
+		},
+		"TextAnyTerm": {
+			stage:    bson.D{{"text", bson.D{{"query", "turtle thinking"}, {"path", "title"}}}},
+			expected: []any{int32(2), int32(3)},
+		},
+		"TextNotIndexedPath": {
+			stage:    bson.D{{"text", bson.D{{"query", "quick"}, {"path", "other"}}}},
+			expected: []any{},
+		},
+		"Phrase": {
+			stage:    bson.D{{"phrase", bson.D{{"query", "lazy dog"}, {"path", "v.plot"}}}},
+			expected: []any{int32(1)},
+		},
+		"PhraseWrongOrder": {
+			stage:    bson.D{{"phrase", bson.D{{"query", "dog lazy"}, {"path", "v.plot"}}}},
+			expected: []any{},
+		},
+		"Autocomplete": {
+			stage:    bson.D{{"autocomplete", bson.D{{"query", "thin"}, {"path", "title"}}}},
+			expected: []any{int32(2)},
+		},
+		"IndexNotFound": {
+			stage:    bson.D{{"index", "none"}, {"text", bson.D{{"query", "quick"}, {"path", "title"}}}},
+			expected: []any{},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.ElementsMatch(t, tc.expected, search(t, tc.stage))
+		})
+	}
+
+	t.Run("NotFirstStage", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := collection.Aggregate(ctx, bson.A{
+			bson.D{{"$match", bson.D{}}},
+			bson.D{{"$search", bson.D{{"text", bson.D{{"query", "quick"}, {"path", "title"}}}}}},
+		})
+
+		expected := mongo.CommandError{
+			Code:    40602,
+			Name:    "Location40602",
+			Message: "$search is only valid as the first stage in a pipeline",
+		}
+		AssertEqualCommandError(t, expected, err)
+	})
+
+	t.Run("AutocompleteNotIndexed", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := collection.Aggregate(ctx, bson.A{
+			bson.D{{"$search", bson.D{{"autocomplete", bson.D{{"query", "lazy"}, {"path", "v.plot"}}}}}},
+		})
+
+		expected := mongo.CommandError{
+			Code:    2,
+			Name:    "BadValue",
+			Message: `"autocomplete" index field definition not present at path v.plot`,
+		}
+		AssertEqualCommandError(t, expected, err)
+	})
+}
+
+func TestSearchIndexesCommands(t *testing.T) {
+	t.Parallel()
+
+	if !setup.IsPostgreSQL(t) {
+		t.Skip("Full-text search is supported only by the PostgreSQL backend")
+	}
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "fox"}, {"title", "The quick brown fox"}})
+	require.NoError(t, err)
+
+	dynamic := bson.D{{"mappings", bson.D{{"dynamic", true}}}}
+
+	err = db.RunCommand(ctx, bson.D{
+		{"createSearchIndexes", collection.Name()},
+		{"indexes", bson.A{bson.D{{"name", "dynamic"}, {"definition", dynamic}}}},
+	}).Err()
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"createSearchIndexes", collection.Name()},
+		{"indexes", bson.A{bson.D{{"name", "dynamic"}, {"definition", dynamic}}}},
+	}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    68,
+		Name:    "IndexAlreadyExists",
+		Message: `Search index "dynamic" already exists`,
+	}, err)
+
+	stage := bson.D{{"index", "dynamic"}, {"text", bson.D{{"query", "foxes"}, {"path", "title"}}}}
+
+	cursor, err := collection.Aggregate(ctx, bson.A{bson.D{{"$search", stage}}})
+	require.NoError(t, err)
+
+	var docs []bson.D
+	require.NoError(t, cursor.All(ctx, &docs))
+	require.Len(t, docs, 1)
+
+	static := bson.D{{"mappings", bson.D{{"fields", bson.D{{"other", bson.D{{"type", "string"}}}}}}}}
+
+	err = db.RunCommand(ctx, bson.D{
+		{"updateSearchIndex", collection.Name()},
+		{"name", "dynamic"},
+		{"definition", static},
+	}).Err()
+	require.NoError(t, err)
+
+	cursor, err = collection.Aggregate(ctx, bson.A{bson.D{{"$search", stage}}})
+	require.NoError(t, err)
+
+	require.NoError(t, cursor.All(ctx, &docs))
+	assert.Empty(t, docs)
+
+	err = db.RunCommand(ctx, bson.D{{"dropSearchIndex", collection.Name()}, {"id", "dynamic"}}).Err()
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{{"dropSearchIndex", collection.Name()}, {"name", "dynamic"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    27,
+		Name:    "IndexNotFound",
+		Message: `Search index "dynamic" not found`,
+	}, err)
+}
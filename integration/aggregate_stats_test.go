@@ -87,6 +87,38 @@ func TestAggregateCollStats(t *testing.T) {
 	assert.Equal(t, true, must.NotFail(storageStats.Get("capped")))
 }
 
+func TestAggregateCollStatsSchema(t *testing.T) {
+	setup.SkipForMongoDB(t, "$collStats.schema is FerretDB-specific")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.DocumentsStrings)
+
+	pipeline := bson.A{bson.D{{"$collStats", bson.D{{"schema", bson.D{{"sampleSize", int32(1)}}}}}}}
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	require.NoError(t, err)
+
+	res := FetchAll(t, ctx, cursor)
+	require.Len(t, res, 1)
+
+	schema := must.NotFail(ConvertDocument(t, res[0]).Get("schema")).(*types.Document)
+	assert.Equal(t, int64(1), must.NotFail(schema.Get("sampled")))
+
+	fields := must.NotFail(schema.Get("fields")).(*types.Array)
+	require.NotZero(t, fields.Len())
+	assert.Equal(t, "_id", must.NotFail(must.NotFail(fields.Get(0)).(*types.Document).Get("path")))
+
+	pipeline = bson.A{bson.D{{"$collStats", bson.D{{"schema", int32(1)}}}}}
+
+	_, err = collection.Aggregate(ctx, pipeline)
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    14,
+		Name:    "TypeMismatch",
+		Message: "BSON field '$collStats.schema' is the wrong type 'int', expected type 'object'",
+	}, err)
+}
+
 func TestAggregateCollStatsCommandErrors(t *testing.T) {
 	t.Parallel()
 
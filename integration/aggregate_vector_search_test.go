@@ -0,0 +1,195 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestAggregateVectorSearch(t *testing.T) {
+	t.Parallel()
+
+	if !setup.IsPostgreSQL(t) {
+		t.Skip("Vector search is supported only by the PostgreSQL backend")
+	}
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"genre", "drama"}, {"embedding", bson.A{1.0, 0.0, 0.0}}},
+		bson.D{{"_id", int32(2)}, {"genre", "comedy"}, {"embedding", bson.A{0.9, 0.1, 0.0}}},
+		bson.D{{"_id", int32(3)}, {"genre", "drama"}, {"embedding", bson.A{0.0, 1.0, 0.0}}},
+		bson.D{{"_id", int32(4)}, {"genre", "drama"}, {"embedding", bson.A{int32(0), int32(0), int32(1)}}},
+		bson.D{{"_id", int32(5)}, {"genre", "drama"}, {"embedding", "invalid"}},
+		bson.D{{"_id", int32(6)}, {"genre", "drama"}},
+	})
+	require.NoError(t, err)
+
+	definition := bson.D{{"fields", bson.A{
+		bson.D{{"type", "vector"}, {"path", "embedding"}, {"numDimensions", int32(3)}, {"similarity", "cosine"}},
+		bson.D{{"type", "filter"}, {"path", "genre"}},
+	}}}
+
+	var res bson.D
+	err = db.RunCommand(ctx, bson.D{
+		{"createSearchIndexes", collection.Name()},
+		{"indexes", bson.A{bson.D{{"name", "vector"}, {"type", "vectorSearch"}, {"definition", definition}}}},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	cursor, err := collection.Aggregate(ctx, bson.A{bson.D{{"$listSearchIndexes", bson.D{{"name", "vector"}}}}})
+	require.NoError(t, err)
+
+	var indexes []bson.D
+	require.NoError(t, cursor.All(ctx, &indexes))
+	require.Len(t, indexes, 1)
+
+	expected := bson.D{
+		{"id", "vector"},
+		{"name", "vector"},
+		{"type", "vectorSearch"},
+		{"status", "READY"},
+		{"queryable", true},
+		{"latestDefinition", definition},
+	}
+	AssertEqualDocuments(t, expected, indexes[0])
+
+	for name, tc := range map[string]struct {
+		stage    bson.D
+		expected []any
+		err      *mongo.CommandError
+	}{
+		"Approximate": {
+			stage: bson.D{
+				{"index", "vector"},
+				{"path", "embedding"},
+				{"queryVector", bson.A{1.0, 0.0, 0.0}},
+				{"numCandidates", int32(10)},
+				{"limit", int32(2)},
+			},
+			expected: []any{int32(1), int32(2)},
+		},
+		"Exact": {
+			stage: bson.D{
+				{"index", "vector"},
+				{"path", "embedding"},
+				{"queryVector", bson.A{0.0, 0.0, 1.0}},
+				{"exact", true},
+				{"limit", int32(1)},
+			},
+			expected: []any{int32(4)},
+		},
+		"Filter": {
+			stage: bson.D{
+				{"index", "vector"},
+				{"path", "embedding"},
+				{"queryVector", bson.A{1.0, 0.5, 0.0}},
+				{"numCandidates", int32(10)},
+				{"limit", int32(2)},
+				{"filter", bson.D{{"genre", "drama"}}},
+			},
+			expected: []any{int32(1), int32(3)},
+		},
+		"IndexNotFound": {
+			stage: bson.D{
+				{"index", "other"},
+				{"path", "embedding"},
+				{"queryVector", bson.A{1.0, 0.0, 0.0}},
+				{"numCandidates", int32(10)},
+				{"limit", int32(2)},
+			},
+			expected: []any{},
+		},
+		"Dimensions": {
+			stage: bson.D{
+				{"index", "vector"},
+				{"path", "embedding"},
+				{"queryVector", bson.A{1.0, 0.0}},
+				{"numCandidates", int32(10)},
+				{"limit", int32(2)},
+			},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "vector field is indexed with 3 dimensions but queried with 2",
+			},
+		},
+		"FilterNotIndexed": {
+			stage: bson.D{
+				{"index", "vector"},
+				{"path", "embedding"},
+				{"queryVector", bson.A{1.0, 0.0, 0.0}},
+				{"numCandidates", int32(10)},
+				{"limit", int32(2)},
+				{"filter", bson.D{{"year", int32(2000)}}},
+			},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Path 'year' needs to be indexed as filter",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Aggregate(ctx, bson.A{
+				bson.D{{"$vectorSearch", tc.stage}},
+				bson.D{{"$project", bson.D{{"_id", 1}}}},
+			})
+			if tc.err != nil {
+				AssertEqualCommandError(t, *tc.err, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var docs []bson.D
+			require.NoError(t, cursor.All(ctx, &docs))
+
+			ids := make([]any, len(docs))
+			for i, doc := range docs {
+				ids[i] = doc[0].Value
+			}
+
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+
+	t.Run("NotFirstStage", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := collection.Aggregate(ctx, bson.A{
+			bson.D{{"$match", bson.D{}}},
+			bson.D{{"$vectorSearch", bson.D{}}},
+		})
+
+		expected := mongo.CommandError{
+			Code:    40602,
+			Name:    "Location40602",
+			Message: "$vectorSearch is only valid as the first stage in a pipeline",
+		}
+		AssertEqualCommandError(t, expected, err)
+	})
+}
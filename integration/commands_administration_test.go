@@ -20,6 +20,7 @@ import (
 	"runtime"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -865,6 +866,376 @@ func TestGetParameterCommandAuthenticationMechanisms(t *testing.T) {
 	})
 }
 
+func TestCommandsAdministrationSetParameter(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, &setup.SetupOpts{
+		DatabaseName: "admin",
+	})
+
+	// the server is shared between tests, so parameters are set to their current (default) values
+
+	for name, tc := range map[string]struct {
+		command bson.D // required, command to run
+
+		expected   bson.D              // optional, expected response
+		err        *mongo.CommandError // optional, expected error from MongoDB
+		altMessage string              // optional, alternative error message for FerretDB, ignored if empty
+	}{
+		"CursorTimeoutMillis": {
+			command:  bson.D{{"setParameter", 1}, {"cursorTimeoutMillis", int64(600000)}},
+			expected: bson.D{{"was", int64(600000)}, {"ok", float64(1)}},
+		},
+		"Unrecognized": {
+			command: bson.D{{"setParameter", 1}, {"quiet_other", 1}},
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "attempted to set unrecognized parameter [quiet_other], use help:true to see options ",
+			},
+		},
+		"NoParameters": {
+			command: bson.D{{"setParameter", 1}},
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "no option found to set, use help:true to see options ",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var actual bson.D
+			err := s.Collection.Database().RunCommand(s.Ctx, tc.command).Decode(&actual)
+			if tc.err != nil {
+				AssertEqualAltCommandError(t, *tc.err, tc.altMessage, err)
+				return
+			}
+
+			require.NoError(t, err)
+			AssertEqualDocuments(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("NonAdmin", func(t *testing.T) {
+		t.Parallel()
+
+		err := s.Collection.Database().Client().Database(testutil.DatabaseName(t)).RunCommand(
+			s.Ctx, bson.D{{"setParameter", 1}, {"cursorTimeoutMillis", int64(600000)}},
+		).Err()
+
+		expected := mongo.CommandError{
+			Code:    13,
+			Name:    "Unauthorized",
+			Message: "setParameter may only be run against the admin database.",
+		}
+		AssertEqualCommandError(t, expected, err)
+	})
+
+	t.Run("FeatureFlags", func(t *testing.T) {
+		setup.SkipForMongoDB(t, "FerretDB-specific feature flags")
+
+		t.Parallel()
+
+		var res bson.D
+		err := s.Collection.Database().RunCommand(s.Ctx, bson.D{
+			{"getParameter", 1},
+			{"featureFlagNestedPushdown", 1},
+		}).Decode(&res)
+		require.NoError(t, err)
+
+		doc := ConvertDocument(t, res)
+		current := must.NotFail(must.NotFail(doc.Get("featureFlagNestedPushdown")).(*types.Document).Get("value"))
+
+		var actual bson.D
+		err = s.Collection.Database().RunCommand(s.Ctx, bson.D{
+			{"setParameter", 1},
+			{"featureFlagNestedPushdown", current},
+		}).Decode(&actual)
+		require.NoError(t, err)
+
+		expected := bson.D{{"was", bson.D{{"value", current}}}, {"ok", float64(1)}}
+		AssertEqualDocuments(t, expected, actual)
+
+		err = s.Collection.Database().RunCommand(s.Ctx, bson.D{
+			{"setParameter", 1},
+			{"featureFlagNestedPushdown", "true"},
+		}).Err()
+
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    14,
+			Name:    "TypeMismatch",
+			Message: "Invalid value for parameter featureFlagNestedPushdown: expected type 'bool', got 'string'",
+		}, err)
+	})
+}
+
+func TestCommandsAdministrationCollectionTemplates(t *testing.T) {
+	setup.SkipForMongoDB(t, "FerretDB-specific collection templates")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+	adminDB := db.Client().Database("admin")
+
+	name := testutil.DatabaseName(t)
+
+	err := adminDB.RunCommand(ctx, bson.D{
+		{"createCollectionTemplate", name},
+		{"pattern", db.Name() + ".events_*"},
+		{"indexes", bson.A{bson.D{{"key", bson.D{{"createdAt", -1}}}, {"name", "createdAt_-1"}}}},
+	}).Err()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, adminDB.RunCommand(ctx, bson.D{{"dropCollectionTemplate", name}}).Err())
+	})
+
+	err = adminDB.RunCommand(ctx, bson.D{{"createCollectionTemplate", name}, {"pattern", "*"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    48,
+		Name:    "NamespaceExists",
+		Message: "Collection template " + name + " already exists",
+	}, err)
+
+	var res bson.D
+	err = adminDB.RunCommand(ctx, bson.D{{"listCollectionTemplates", 1}}).Decode(&res)
+	require.NoError(t, err)
+
+	templates := must.NotFail(ConvertDocument(t, res).Get("templates")).(*types.Array)
+	var found bool
+
+	for i := 0; i < templates.Len(); i++ {
+		tmpl := must.NotFail(templates.Get(i)).(*types.Document)
+		if must.NotFail(tmpl.Get("name")) == name {
+			found = true
+			assert.Equal(t, db.Name()+".events_*", must.NotFail(tmpl.Get("pattern")))
+		}
+	}
+
+	assert.True(t, found)
+
+	t.Run("Implicit", func(t *testing.T) {
+		_, err := db.Collection("events_implicit").InsertOne(ctx, bson.D{{"_id", 1}})
+		require.NoError(t, err)
+
+		specs, err := db.Collection("events_implicit").Indexes().ListSpecifications(ctx)
+		require.NoError(t, err)
+		require.Len(t, specs, 2)
+		assert.Equal(t, "createdAt_-1", specs[1].Name)
+	})
+
+	t.Run("Explicit", func(t *testing.T) {
+		err := db.CreateCollection(ctx, "events_explicit")
+		require.NoError(t, err)
+
+		specs, err := db.Collection("events_explicit").Indexes().ListSpecifications(ctx)
+		require.NoError(t, err)
+		require.Len(t, specs, 2)
+		assert.Equal(t, "createdAt_-1", specs[1].Name)
+	})
+
+	t.Run("NotMatching", func(t *testing.T) {
+		err := db.CreateCollection(ctx, "other")
+		require.NoError(t, err)
+
+		specs, err := db.Collection("other").Indexes().ListSpecifications(ctx)
+		require.NoError(t, err)
+		require.Len(t, specs, 1)
+	})
+
+	t.Run("NonAdmin", func(t *testing.T) {
+		err := db.RunCommand(ctx, bson.D{{"listCollectionTemplates", 1}}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    13,
+			Name:    "Unauthorized",
+			Message: "listCollectionTemplates may only be run against the admin database.",
+		}, err)
+	})
+
+	t.Run("DropNonExisting", func(t *testing.T) {
+		err := adminDB.RunCommand(ctx, bson.D{{"dropCollectionTemplate", name + "_none"}}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    26,
+			Name:    "NamespaceNotFound",
+			Message: "Collection template " + name + "_none not found",
+		}, err)
+	})
+}
+
+func TestCommandsAdministrationArchivePolicies(t *testing.T) {
+	setup.SkipForMongoDB(t, "FerretDB-specific archive policies")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	old := primitive.NewDateTimeFromTime(time.Now().Add(-time.Hour))
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", 1}, {"status", "done"}, {"createdAt", old}},
+		bson.D{{"_id", 2}, {"status", "new"}, {"createdAt", old}},
+		bson.D{{"_id", 3}, {"status", "done"}, {"createdAt", primitive.NewDateTimeFromTime(time.Now())}},
+		bson.D{{"_id", 4}, {"status", "done"}},
+	})
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{
+		{"setArchivePolicy", collection.Name()},
+		{"filter", bson.D{{"status", "done"}}},
+		{"field", "createdAt"},
+		{"olderThanSeconds", 60},
+	}).Err()
+	require.NoError(t, err)
+
+	var res bson.D
+	err = db.RunCommand(ctx, bson.D{{"listArchivePolicies", 1}}).Decode(&res)
+	require.NoError(t, err)
+
+	policies := must.NotFail(ConvertDocument(t, res).Get("policies")).(*types.Array)
+	require.Equal(t, 1, policies.Len())
+	assert.Equal(t, collection.Name(), must.NotFail(must.NotFail(policies.Get(0)).(*types.Document).Get("collection")))
+
+	err = db.RunCommand(ctx, bson.D{{"archive", collection.Name()}}).Decode(&res)
+	require.NoError(t, err)
+	AssertEqualDocuments(t, bson.D{{"nArchived", int32(1)}, {"ok", float64(1)}}, res)
+
+	count, err := collection.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+
+	count, err = db.Collection("system.archive."+collection.Name()).CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	t.Run("IncludeInReads", func(t *testing.T) {
+		err := db.RunCommand(ctx, bson.D{
+			{"setArchivePolicy", collection.Name()},
+			{"filter", bson.D{{"status", "done"}}},
+			{"field", "createdAt"},
+			{"olderThanSeconds", 60},
+			{"includeInReads", true},
+		}).Err()
+		require.NoError(t, err)
+
+		cursor, err := collection.Find(ctx, bson.D{{"status", "done"}}, options.Find().SetSort(bson.D{{"_id", 1}}))
+		require.NoError(t, err)
+
+		var docs []bson.D
+		require.NoError(t, cursor.All(ctx, &docs))
+		require.Len(t, docs, 3)
+		assert.Equal(t, int32(1), docs[0][0].Value)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		err := db.RunCommand(ctx, bson.D{{"removeArchivePolicy", collection.Name()}}).Err()
+		require.NoError(t, err)
+
+		err = db.RunCommand(ctx, bson.D{{"removeArchivePolicy", collection.Name()}}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    26,
+			Name:    "NamespaceNotFound",
+			Message: "Archive policy for " + db.Name() + "." + collection.Name() + " not found",
+		}, err)
+
+		count, err := collection.CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+	})
+
+	t.Run("ArchiveCollection", func(t *testing.T) {
+		err := db.RunCommand(ctx, bson.D{
+			{"setArchivePolicy", "system.archive." + collection.Name()},
+			{"field", "createdAt"},
+			{"olderThanSeconds", 60},
+		}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    73,
+			Name:    "InvalidNamespace",
+			Message: "Collection system.archive." + collection.Name() + " is an archive collection",
+		}, err)
+	})
+}
+
+func TestCommandsAdministrationQuotas(t *testing.T) {
+	setup.SkipForMongoDB(t, "FerretDB-specific storage quotas")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	t.Cleanup(func() {
+		_ = db.RunCommand(ctx, bson.D{{"removeQuota", 1}}).Err()
+		_ = db.RunCommand(ctx, bson.D{{"removeQuota", collection.Name()}}).Err()
+	})
+
+	err := db.RunCommand(ctx, bson.D{{"setQuota", 1}, {"maxCollections", 1}}).Err()
+	require.NoError(t, err)
+
+	err = db.RunCommand(ctx, bson.D{{"setQuota", collection.Name()}, {"maxDocuments", 2}}).Err()
+	require.NoError(t, err)
+
+	var res bson.D
+	err = db.RunCommand(ctx, bson.D{{"listQuotas", 1}}).Decode(&res)
+	require.NoError(t, err)
+
+	quotas := must.NotFail(ConvertDocument(t, res).Get("quotas")).(*types.Array)
+	require.Equal(t, 2, quotas.Len())
+	assert.Equal(t, int64(1), must.NotFail(must.NotFail(quotas.Get(0)).(*types.Document).Get("maxCollections")))
+	assert.Equal(t, collection.Name(), must.NotFail(must.NotFail(quotas.Get(1)).(*types.Document).Get("collection")))
+
+	_, err = collection.InsertMany(ctx, []any{bson.D{{"_id", 1}}, bson.D{{"_id", 2}}, bson.D{{"_id", 3}}})
+	AssertEqualWriteError(t, mongo.WriteError{
+		Index:   2,
+		Code:    12501,
+		Message: "quota exceeded: maxDocuments of collection " + db.Name() + "." + collection.Name() + " is 2",
+	}, err)
+
+	count, err := collection.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	_, err = db.Collection(collection.Name()+"_other").InsertOne(ctx, bson.D{{"_id", 1}})
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    12501,
+		Name:    "QuotaExceeded",
+		Message: "quota exceeded: maxCollections of database " + db.Name() + " is 1",
+	}, err)
+
+	err = db.RunCommand(ctx, bson.D{{"dbStats", 1}}).Decode(&res)
+	require.NoError(t, err)
+
+	quota := must.NotFail(ConvertDocument(t, res).Get("quota")).(*types.Document)
+	assert.Equal(t, int64(1), must.NotFail(quota.Get("collections")))
+	assert.Equal(t, int64(2), must.NotFail(quota.Get("documents")))
+
+	err = db.RunCommand(ctx, bson.D{{"setQuota", collection.Name()}, {"maxCollections", 1}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    72,
+		Name:    "InvalidOptions",
+		Message: "maxCollections is supported only for database quotas",
+	}, err)
+
+	t.Run("Remove", func(t *testing.T) {
+		err := db.RunCommand(ctx, bson.D{{"removeQuota", collection.Name()}}).Err()
+		require.NoError(t, err)
+
+		err = db.RunCommand(ctx, bson.D{{"removeQuota", collection.Name()}}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    26,
+			Name:    "NamespaceNotFound",
+			Message: "Quota for " + db.Name() + "." + collection.Name() + " not found",
+		}, err)
+
+		_, err = collection.InsertOne(ctx, bson.D{{"_id", 3}})
+		require.NoError(t, err)
+	})
+}
+
 func TestCommandsAdministrationBuildInfo(t *testing.T) {
 	t.Parallel()
 	ctx, collection := setup.Setup(t)
@@ -1400,6 +1771,17 @@ func TestCommandsAdministrationServerStatus(t *testing.T) {
 
 	assert.WithinDuration(t, time.Now(), must.NotFail(doc.Get("localTime")).(time.Time), 2*time.Second)
 
+	connections, ok := must.NotFail(doc.Get("connections")).(*types.Document)
+	require.True(t, ok)
+	assert.GreaterOrEqual(t, must.NotFail(connections.Get("current")), int32(1))
+	assert.GreaterOrEqual(t, must.NotFail(connections.Get("available")), int32(1))
+	assert.GreaterOrEqual(t, must.NotFail(connections.Get("totalCreated")), int32(1))
+
+	mem, ok := must.NotFail(doc.Get("mem")).(*types.Document)
+	require.True(t, ok)
+	assert.Equal(t, int32(strconv.IntSize), must.NotFail(mem.Get("bits")))
+	assert.GreaterOrEqual(t, must.NotFail(mem.Get("resident")), int32(1))
+
 	catalogStats, ok := must.NotFail(doc.Get("catalogStats")).(*types.Document)
 	assert.True(t, ok)
 
@@ -1457,6 +1839,21 @@ func TestCommandsAdministrationServerStatusMetrics(t *testing.T) {
 			metricsPath:     types.NewStaticPath("metrics", "commands", "update"),
 			expectedNonZero: []string{"failed", "total"},
 		},
+		"Opcounters": {
+			cmds: []bson.D{
+				{{"find", "values"}},
+				{{"ping", int32(1)}},
+			},
+			metricsPath:     types.NewStaticPath("opcounters"),
+			expectedNonZero: []string{"query", "command"},
+		},
+		"Network": {
+			cmds: []bson.D{
+				{{"ping", int32(1)}},
+			},
+			metricsPath:     types.NewStaticPath("network"),
+			expectedNonZero: []string{"bytesIn", "bytesOut", "numRequests"},
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
@@ -1852,6 +2249,93 @@ func TestCommandsAdministrationCompactErrors(t *testing.T) {
 	}
 }
 
+func TestCommandsAdministrationConvertToCapped(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	// each document takes 122 bytes
+	docs := make([]any, 10)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}, {"v", strings.Repeat("x", 100)}}
+	}
+
+	_, err := collection.InsertMany(ctx, docs)
+	require.NoError(t, err)
+
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"v", 1}}})
+	require.NoError(t, err)
+
+	t.Run("Clone", func(t *testing.T) {
+		err = db.RunCommand(ctx, bson.D{
+			{"cloneCollectionAsCapped", collection.Name()},
+			{"toCollection", collection.Name() + "_clone"},
+			{"size", int32(512)},
+		}).Err()
+		require.NoError(t, err)
+
+		clone := db.Collection(collection.Name() + "_clone")
+
+		// the oldest documents are evicted
+		cursor, err := clone.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"$natural", 1}}).SetProjection(bson.D{{"v", 0}}))
+		require.NoError(t, err)
+
+		var res []bson.D
+		require.NoError(t, cursor.All(ctx, &res))
+		assert.Equal(t, []bson.D{{{"_id", int32(6)}}, {{"_id", int32(7)}}, {{"_id", int32(8)}}, {{"_id", int32(9)}}}, res)
+
+		// the source collection is not changed
+		count, err := collection.CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), count)
+
+		err = db.RunCommand(ctx, bson.D{
+			{"cloneCollectionAsCapped", collection.Name()},
+			{"toCollection", collection.Name() + "_clone"},
+			{"size", int32(512)},
+		}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    48,
+			Name:    "NamespaceExists",
+			Message: "collection " + db.Name() + "." + collection.Name() + "_clone already exists",
+		}, err)
+	})
+
+	t.Run("Convert", func(t *testing.T) {
+		err = db.RunCommand(ctx, bson.D{{"convertToCapped", collection.Name()}, {"size", int32(100_000)}}).Err()
+		require.NoError(t, err)
+
+		var res bson.D
+		err = db.RunCommand(ctx, bson.D{{"collStats", collection.Name()}}).Decode(&res)
+		require.NoError(t, err)
+
+		stats := ConvertDocument(t, res)
+		assert.Equal(t, true, must.NotFail(stats.Get("capped")))
+		assert.EqualValues(t, 10, must.NotFail(stats.Get("count")))
+
+		// only the default index is kept
+		indexes, err := collection.Indexes().ListSpecifications(ctx)
+		require.NoError(t, err)
+		require.Len(t, indexes, 1)
+		assert.Equal(t, "_id_", indexes[0].Name)
+
+		err = db.RunCommand(ctx, bson.D{{"convertToCapped", "nonexistent"}, {"size", int32(1000)}}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    26,
+			Name:    "NamespaceNotFound",
+			Message: "source collection " + db.Name() + ".nonexistent does not exist",
+		}, err)
+
+		err = db.RunCommand(ctx, bson.D{{"convertToCapped", collection.Name()}}).Err()
+		AssertEqualCommandError(t, mongo.CommandError{
+			Code:    40414,
+			Name:    "Location40414",
+			Message: "BSON field 'convertToCapped.size' is missing but a required field",
+		}, err)
+	})
+}
+
 func TestCommandsAdministrationCurrentOp(t *testing.T) {
 	t.Parallel()
 
@@ -1871,3 +2355,77 @@ func TestCommandsAdministrationCurrentOp(t *testing.T) {
 	_, ok := must.NotFail(doc.Get("inprog")).(*types.Array)
 	assert.True(t, ok)
 }
+
+func TestCommandsAdministrationFsync(t *testing.T) {
+	// not parallel because fsync lock blocks writes of other tests
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+	adminDB := db.Client().Database("admin")
+
+	var res bson.D
+	err := adminDB.RunCommand(ctx, bson.D{{"fsync", int32(1)}}).Decode(&res)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), must.NotFail(ConvertDocument(t, res).Get("ok")))
+
+	err = db.RunCommand(ctx, bson.D{{"fsync", int32(1)}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    13,
+		Name:    "Unauthorized",
+		Message: "fsync may only be run against the admin database.",
+	}, err)
+
+	err = adminDB.RunCommand(ctx, bson.D{{"fsyncUnlock", int32(1)}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    20,
+		Name:    "IllegalOperation",
+		Message: "fsyncUnlock called when not locked",
+	}, err)
+
+	t.Run("Lock", func(t *testing.T) {
+		err := adminDB.RunCommand(ctx, bson.D{{"fsync", int32(1)}, {"lock", true}}).Decode(&res)
+		require.NoError(t, err)
+
+		unlocked := false
+		t.Cleanup(func() {
+			if !unlocked {
+				_ = adminDB.RunCommand(ctx, bson.D{{"fsyncUnlock", int32(1)}}).Err()
+			}
+		})
+
+		assert.Equal(t, int64(1), must.NotFail(ConvertDocument(t, res).Get("lockCount")))
+
+		err = adminDB.RunCommand(ctx, bson.D{{"currentOp", int32(1)}}).Decode(&res)
+		require.NoError(t, err)
+		assert.Equal(t, true, must.NotFail(ConvertDocument(t, res).Get("fsyncLock")))
+
+		// reads are not blocked
+		_, err = collection.CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+
+		inserted := make(chan error, 1)
+
+		go func() {
+			_, err := collection.InsertOne(ctx, bson.D{{"_id", "fsync"}})
+			inserted <- err
+		}()
+
+		select {
+		case err = <-inserted:
+			t.Fatalf("write was not blocked: %v", err)
+		case <-time.After(500 * time.Millisecond):
+		}
+
+		err = adminDB.RunCommand(ctx, bson.D{{"fsyncUnlock", int32(1)}}).Decode(&res)
+		require.NoError(t, err)
+		unlocked = true
+
+		assert.Equal(t, int64(0), must.NotFail(ConvertDocument(t, res).Get("lockCount")))
+
+		require.NoError(t, <-inserted)
+
+		count, err := collection.CountDocuments(ctx, bson.D{{"_id", "fsync"}})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+	})
+}
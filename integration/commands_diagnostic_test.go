@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,6 +48,92 @@ func TestCommandsDiagnosticConnectionStatus(t *testing.T) {
 	assert.Equal(t, float64(1), ok)
 }
 
+func TestCommandsDiagnosticDBCheck(t *testing.T) {
+	setup.SkipForMongoDB(t, "dbCheck requires a replica set in MongoDB")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.Int32s)
+
+	ns := collection.Database().Name() + "." + collection.Name()
+
+	err := collection.Database().RunCommand(ctx, bson.D{{"dbCheck", collection.Name()}}).Err()
+	require.NoError(t, err)
+
+	healthLog := collection.Database().Client().Database("local").Collection("system.healthlog")
+
+	var stop bson.D
+
+	require.Eventually(t, func() bool {
+		err = healthLog.FindOne(ctx, bson.D{{"namespace", ns}, {"operation", "dbCheckStop"}}).Decode(&stop)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond)
+
+	data := must.NotFail(ConvertDocument(t, stop).Get("data")).(*types.Document)
+	assert.Equal(t, true, must.NotFail(data.Get("success")))
+	assert.Equal(t, int64(0), must.NotFail(data.Get("inconsistencies")))
+
+	var batch bson.D
+	err = healthLog.FindOne(ctx, bson.D{{"namespace", ns}, {"operation", "dbCheckBatch"}}).Decode(&batch)
+	require.NoError(t, err)
+
+	data = must.NotFail(ConvertDocument(t, batch).Get("data")).(*types.Document)
+	assert.Equal(t, "info", must.NotFail(ConvertDocument(t, batch).Get("severity")))
+	assert.Equal(t, int64(len(shareddata.Int32s.Docs())), must.NotFail(data.Get("count")))
+	assert.Len(t, must.NotFail(data.Get("md5")), 32)
+
+	err = collection.Database().RunCommand(ctx, bson.D{{"dbCheck", "nonexistent"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    26,
+		Name:    "NamespaceNotFound",
+		Message: "Collection '" + collection.Database().Name() + ".nonexistent' does not exist.",
+	}, err)
+}
+
+func TestCommandsDiagnosticDiscoverSchema(t *testing.T) {
+	setup.SkipForMongoDB(t, "discoverSchema is FerretDB-specific")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(1)}, {"v", "foo"}, {"e", bson.D{{"a", int32(1)}}}},
+		bson.D{{"_id", int32(2)}, {"v", int64(42)}},
+	})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"discoverSchema", collection.Name()},
+		{"sampleSize", int32(10)},
+	}).Decode(&res)
+	require.NoError(t, err)
+
+	doc := ConvertDocument(t, res)
+	assert.Equal(t, collection.Database().Name()+"."+collection.Name(), must.NotFail(doc.Get("ns")))
+	assert.Equal(t, int64(2), must.NotFail(doc.Get("sampled")))
+
+	fields := must.NotFail(doc.Get("fields")).(*types.Array)
+	require.Equal(t, 4, fields.Len())
+
+	v := must.NotFail(fields.Get(1)).(*types.Document)
+	assert.Equal(t, "v", must.NotFail(v.Get("path")))
+	assert.Equal(t, int64(2), must.NotFail(v.Get("count")))
+	assert.Equal(t, 2, must.NotFail(v.Get("types")).(*types.Array).Len())
+
+	ea := must.NotFail(fields.Get(3)).(*types.Document)
+	assert.Equal(t, "e.a", must.NotFail(ea.Get("path")))
+	assert.Equal(t, 0.5, must.NotFail(ea.Get("probability")))
+
+	err = collection.Database().RunCommand(ctx, bson.D{{"discoverSchema", "nonexistent"}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    26,
+		Name:    "NamespaceNotFound",
+		Message: "Collection '" + collection.Database().Name() + ".nonexistent' does not exist.",
+	}, err)
+}
+
 func TestCommandsDiagnosticExplain(t *testing.T) {
 	t.Parallel()
 	s := setup.SetupWithOpts(t, &setup.SetupOpts{
@@ -246,9 +333,45 @@ func TestCommandsDiagnosticHostInfo(t *testing.T) {
 	assert.Contains(t, keys, "hostname")
 	assert.Contains(t, keys, "cpuAddrSize")
 	assert.Contains(t, keys, "numCores")
+	assert.Contains(t, keys, "memSizeMB")
 	assert.Contains(t, keys, "cpuArch")
 }
 
+func TestCommandsDiagnosticListClientVersions(t *testing.T) {
+	setup.SkipForMongoDB(t, "listClientVersions is FerretDB-specific")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	var res bson.D
+	err := collection.Database().RunCommand(ctx, bson.D{{"listClientVersions", int32(1)}}).Decode(&res)
+	require.NoError(t, err)
+
+	doc := ConvertDocument(t, res)
+	clients := must.NotFail(doc.Get("clients")).(*types.Array)
+
+	var found bool
+
+	for i := 0; i < clients.Len(); i++ {
+		client := must.NotFail(clients.Get(i)).(*types.Document)
+		driver := must.NotFail(client.Get("driver")).(*types.Document)
+
+		if must.NotFail(driver.Get("name")) == "mongo-go-driver" {
+			found = true
+			assert.Positive(t, must.NotFail(client.Get("connections")))
+		}
+	}
+
+	assert.True(t, found, "%s", types.FormatAnyValue(clients))
+
+	err = collection.Database().RunCommand(ctx, bson.D{{"serverStatus", int32(1)}}).Decode(&res)
+	require.NoError(t, err)
+
+	connections := must.NotFail(ConvertDocument(t, res).Get("connections")).(*types.Document)
+	assert.Positive(t, must.NotFail(connections.Get("clients")).(*types.Array).Len())
+}
+
 func TestCommandsDiagnosticListCommands(t *testing.T) {
 	t.Parallel()
 	ctx, collection := setup.Setup(t)
@@ -271,6 +394,79 @@ func TestCommandsDiagnosticListCommands(t *testing.T) {
 	assert.NotEmpty(t, must.NotFail(listCommands.Get("help")).(string))
 }
 
+func TestCommandsDiagnosticSupportedFeatures(t *testing.T) {
+	setup.SkipForMongoDB(t, "supportedFeatures is FerretDB-specific")
+
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	var res bson.D
+	err := collection.Database().RunCommand(ctx, bson.D{{"supportedFeatures", int32(1)}}).Decode(&res)
+	require.NoError(t, err)
+
+	doc := ConvertDocument(t, res)
+	assert.NotEmpty(t, must.NotFail(doc.Get("backend")))
+
+	for _, list := range []string{
+		"commands", "aggregationStages", "aggregationOperators", "accumulators",
+		"queryOperators", "updateOperators", "projectionOperators", "indexTypes",
+	} {
+		arr, ok := must.NotFail(doc.Get(list)).(*types.Array)
+		require.True(t, ok, list)
+		assert.Positive(t, arr.Len(), list)
+	}
+
+	commands := must.NotFail(doc.Get("commands")).(*types.Array)
+	assert.True(t, commands.Contains("find"))
+	assert.True(t, commands.Contains("supportedFeatures"))
+
+	stages := must.NotFail(doc.Get("aggregationStages")).(*types.Array)
+	assert.True(t, stages.Contains("$match"))
+	assert.Equal(t, setup.IsPostgreSQL(t), stages.Contains("$search"))
+}
+
+func TestCommandsDiagnosticTop(t *testing.T) {
+	t.Parallel()
+	ctx, collection := setup.Setup(t, shareddata.Doubles)
+
+	_, err := collection.Find(ctx, bson.D{})
+	require.NoError(t, err)
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", "top"}})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = collection.Database().Client().Database("admin").RunCommand(ctx, bson.D{{"top", 1}}).Decode(&res)
+	require.NoError(t, err)
+
+	actual := ConvertDocument(t, res)
+	assert.Equal(t, float64(1), must.NotFail(actual.Get("ok")))
+
+	totals := must.NotFail(actual.Get("totals")).(*types.Document)
+	assert.Equal(t, "all times in microseconds", must.NotFail(totals.Get("note")))
+
+	usage := must.NotFail(totals.Get(collection.Database().Name() + "." + collection.Name())).(*types.Document)
+
+	for _, key := range []string{"total", "readLock", "writeLock", "queries", "getmore", "insert", "update", "remove", "commands"} {
+		v := must.NotFail(usage.Get(key)).(*types.Document)
+		assert.Equal(t, []string{"time", "count"}, v.Keys(), key)
+	}
+
+	queries := must.NotFail(usage.Get("queries")).(*types.Document)
+	assert.Positive(t, must.NotFail(queries.Get("count")))
+
+	insert := must.NotFail(usage.Get("insert")).(*types.Document)
+	assert.Positive(t, must.NotFail(insert.Get("count")))
+
+	err = collection.Database().RunCommand(ctx, bson.D{{"top", 1}}).Err()
+	AssertEqualCommandError(t, mongo.CommandError{
+		Code:    13,
+		Name:    "Unauthorized",
+		Message: "top may only be run against the admin database.",
+	}, err)
+}
+
 func TestCommandsDiagnosticValidate(t *testing.T) {
 	t.Parallel()
 
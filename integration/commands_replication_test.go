@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
@@ -82,3 +83,58 @@ func TestCommandsReplication(t *testing.T) {
 		})
 	}
 }
+
+func TestCommandsReplicationApplyOps(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	ns := collection.Database().Name() + "." + collection.Name()
+	admin := collection.Database().Client().Database("admin")
+
+	var res bson.D
+	err := admin.RunCommand(ctx, bson.D{{"applyOps", bson.A{
+		bson.D{{"op", "i"}, {"ns", ns}, {"o", bson.D{{"_id", int32(1)}, {"v", "a"}, {"n", int32(1)}}}},
+		bson.D{{"op", "i"}, {"ns", ns}, {"o", bson.D{{"_id", int32(2)}, {"v", "b"}}}},
+		bson.D{{"op", "i"}, {"ns", ns}, {"o", bson.D{{"_id", int32(3)}, {"v", "c"}}}},
+		bson.D{{"op", "u"}, {"ns", ns}, {"o2", bson.D{{"_id", int32(1)}}}, {"o", bson.D{
+			{"$v", int32(2)},
+			{"diff", bson.D{{"d", bson.D{{"n", false}}}, {"u", bson.D{{"v", "aa"}}}}},
+		}}},
+		bson.D{{"op", "u"}, {"ns", ns}, {"o2", bson.D{{"_id", int32(2)}}}, {"o", bson.D{{"$set", bson.D{{"w", int32(2)}}}}}},
+		bson.D{{"op", "d"}, {"ns", ns}, {"o", bson.D{{"_id", int32(3)}}}},
+		bson.D{{"op", "n"}, {"ns", ""}, {"o", bson.D{{"msg", "noop"}}}},
+	}}}).Decode(&res)
+	require.NoError(t, err)
+
+	m := res.Map()
+	assert.Equal(t, int32(7), m["applied"])
+	assert.Equal(t, float64(1), m["ok"])
+
+	cursor, err := collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+	require.NoError(t, err)
+
+	var docs []bson.D
+	require.NoError(t, cursor.All(ctx, &docs))
+
+	expected := []bson.D{
+		{{"_id", int32(1)}, {"v", "aa"}},
+		{{"_id", int32(2)}, {"v", "b"}, {"w", int32(2)}},
+	}
+	assert.Equal(t, expected, docs)
+
+	t.Run("Unsupported", func(t *testing.T) {
+		setup.SkipForMongoDB(t, "MongoDB supports command entries")
+
+		// entries are checked before any of them is applied
+		err = admin.RunCommand(ctx, bson.D{{"applyOps", bson.A{
+			bson.D{{"op", "d"}, {"ns", ns}, {"o", bson.D{{"_id", int32(1)}}}},
+			bson.D{{"op", "c"}, {"ns", collection.Database().Name() + ".$cmd"}, {"o", bson.D{{"drop", collection.Name()}}}},
+		}}}).Err()
+		require.Error(t, err)
+
+		count, err := collection.CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+}
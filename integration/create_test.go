@@ -16,15 +16,18 @@ package integration
 
 import (
 	"fmt"
+	"math"
 	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/AlekSi/pointer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
@@ -356,3 +359,542 @@ func TestCreateCappedCommandInvalidSpec(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateValidator(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	validator := bson.D{{"v", bson.D{{"$gt", int32(0)}}}}
+	opts := options.CreateCollection().SetValidator(validator).SetValidationLevel("moderate")
+	require.NoError(t, db.CreateCollection(ctx, collection.Name(), opts))
+
+	cursor, err := db.ListCollections(ctx, bson.D{{"name", collection.Name()}})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	require.Len(t, res, 1)
+
+	var actual bson.D
+	for _, e := range res[0] {
+		if e.Key == "options" {
+			actual = e.Value.(bson.D)
+		}
+	}
+
+	expected := bson.D{
+		{"validator", validator},
+		{"validationLevel", "moderate"},
+		{"validationAction", "error"},
+	}
+	AssertEqualDocuments(t, expected, actual)
+
+	assertValidationFailure := func(t *testing.T, err error) {
+		t.Helper()
+
+		var we mongo.WriteException
+		require.ErrorAs(t, err, &we)
+		require.Len(t, we.WriteErrors, 1)
+		assert.Equal(t, 121, we.WriteErrors[0].Code)
+		assert.Equal(t, "Document failed validation", we.WriteErrors[0].Message)
+	}
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", "valid"}, {"v", int32(42)}})
+	require.NoError(t, err)
+
+	_, err = collection.InsertOne(ctx, bson.D{{"_id", "invalid"}, {"v", int32(-42)}})
+	assertValidationFailure(t, err)
+
+	_, err = collection.UpdateOne(ctx, bson.D{{"_id", "valid"}}, bson.D{{"$set", bson.D{{"v", int32(-1)}}}})
+	assertValidationFailure(t, err)
+
+	_, err = collection.UpdateOne(ctx, bson.D{{"_id", "upsert"}}, bson.D{{"$set", bson.D{{"v", int32(-1)}}}},
+		options.Update().SetUpsert(true),
+	)
+	assertValidationFailure(t, err)
+
+	err = collection.FindOneAndUpdate(ctx, bson.D{{"_id", "valid"}}, bson.D{{"$set", bson.D{{"v", int32(-1)}}}}).Err()
+
+	var ce mongo.CommandError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, int32(121), ce.Code)
+
+	var doc bson.D
+	require.NoError(t, collection.FindOne(ctx, bson.D{{"_id", "valid"}}).Decode(&doc))
+	AssertEqualDocuments(t, bson.D{{"_id", "valid"}, {"v", int32(42)}}, doc)
+}
+
+func TestCreateTimeSeries(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	tsOpts := options.TimeSeries().SetTimeField("t").SetMetaField("m").SetGranularity("minutes")
+	require.NoError(t, db.CreateCollection(ctx, collection.Name(), options.CreateCollection().SetTimeSeriesOptions(tsOpts)))
+
+	cursor, err := db.ListCollections(ctx, bson.D{{"name", collection.Name()}})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	require.Len(t, res, 1)
+
+	var actual bson.D
+	for _, e := range res[0] {
+		switch e.Key {
+		case "type":
+			assert.Equal(t, "timeseries", e.Value)
+		case "options":
+			actual = e.Value.(bson.D)
+		}
+	}
+
+	expected := bson.D{{"timeseries", bson.D{
+		{"timeField", "t"},
+		{"metaField", "m"},
+		{"granularity", "minutes"},
+		{"bucketMaxSpanSeconds", int32(86400)},
+	}}}
+	AssertEqualDocuments(t, expected, actual)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err = collection.InsertMany(ctx, []any{
+		bson.D{{"t", start}, {"m", "a"}, {"v", int32(1)}},
+		bson.D{{"t", start.Add(time.Minute)}, {"m", "a"}, {"v", int32(2)}},
+		bson.D{{"t", start.Add(time.Minute)}, {"m", "b"}, {"v", int32(3)}},
+	})
+	require.NoError(t, err)
+
+	cursor, err = collection.Find(
+		ctx,
+		bson.D{{"t", bson.D{{"$gte", start.Add(time.Minute)}}}},
+		options.Find().SetSort(bson.D{{"v", 1}}).SetProjection(bson.D{{"_id", 0}}),
+	)
+	require.NoError(t, err)
+
+	expectedDocs := []bson.D{
+		{{"t", primitive.NewDateTimeFromTime(start.Add(time.Minute))}, {"m", "a"}, {"v", int32(2)}},
+		{{"t", primitive.NewDateTimeFromTime(start.Add(time.Minute))}, {"m", "b"}, {"v", int32(3)}},
+	}
+	AssertEqualDocumentsSlice(t, expectedDocs, FetchAll(t, ctx, cursor))
+
+	_, err = collection.InsertOne(ctx, bson.D{{"m", "a"}, {"v", int32(4)}})
+
+	var we mongo.WriteException
+	require.ErrorAs(t, err, &we)
+	require.Len(t, we.WriteErrors, 1)
+	assert.Equal(t, 2, we.WriteErrors[0].Code)
+	assert.Equal(t, "'t' must be present and contain a valid BSON UTC datetime value", we.WriteErrors[0].Message)
+
+	buckets := db.Collection("system.buckets." + collection.Name())
+
+	n, err := buckets.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	if setup.IsMongoDB(t) {
+		// MongoDB compresses buckets
+		return
+	}
+
+	var bucket bson.D
+	require.NoError(t, buckets.FindOne(ctx, bson.D{{"meta", "a"}}).Decode(&bucket))
+
+	// measurement _id values are generated, so they are not checked
+	var data bson.D
+	for _, e := range bucket {
+		if e.Key != "data" {
+			continue
+		}
+
+		for _, f := range e.Value.(bson.D) {
+			if f.Key != "_id" {
+				data = append(data, f)
+			}
+		}
+	}
+
+	expectedData := bson.D{
+		{"t", bson.D{
+			{"0", primitive.NewDateTimeFromTime(start)},
+			{"1", primitive.NewDateTimeFromTime(start.Add(time.Minute))},
+		}},
+		{"v", bson.D{{"0", int32(1)}, {"1", int32(2)}}},
+	}
+	AssertEqualDocuments(t, expectedData, data)
+}
+
+func TestCreateTimeSeriesInvalidSpec(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	for name, tc := range map[string]struct {
+		timeseries bson.D
+		capped     bool
+
+		err *mongo.CommandError
+	}{
+		"MissingTimeField": {
+			timeseries: bson.D{{"metaField", "m"}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field 'create.timeseries.timeField' is missing but a required field",
+			},
+		},
+		"InvalidGranularity": {
+			timeseries: bson.D{{"timeField", "t"}, {"granularity", "days"}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Enumeration value 'days' for field 'create.timeseries.granularity' is not a valid value.",
+			},
+		},
+		"Capped": {
+			timeseries: bson.D{{"timeField", "t"}},
+			capped:     true,
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: "Time-series collections cannot be capped",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := bson.D{{"create", collection.Name() + name}, {"timeseries", tc.timeseries}}
+			if tc.capped {
+				cmd = append(cmd, bson.E{"capped", true}, bson.E{"size", int32(1000)})
+			}
+
+			err := db.RunCommand(ctx, cmd).Err()
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
+
+func TestCreateClustered(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	clusteredIndex := bson.D{{"key", bson.D{{"_id", int32(1)}}}, {"unique", true}}
+	opts := options.CreateCollection().SetClusteredIndex(clusteredIndex)
+	require.NoError(t, db.CreateCollection(ctx, collection.Name(), opts))
+
+	cursor, err := db.ListCollections(ctx, bson.D{{"name", collection.Name()}})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+	require.Len(t, res, 1)
+
+	var actual bson.D
+	for _, e := range res[0] {
+		if e.Key == "options" {
+			actual = e.Value.(bson.D)
+		}
+	}
+
+	expected := bson.D{{"clusteredIndex", bson.D{
+		{"v", int32(2)},
+		{"key", bson.D{{"_id", int32(1)}}},
+		{"name", "_id_"},
+		{"unique", true},
+	}}}
+	AssertEqualDocuments(t, expected, actual)
+
+	cursor, err = collection.Indexes().List(ctx)
+	require.NoError(t, err)
+
+	expectedIndexes := []bson.D{{
+		{"v", int32(2)},
+		{"key", bson.D{{"_id", int32(1)}}},
+		{"name", "_id_"},
+		{"unique", true},
+		{"clustered", true},
+	}}
+	AssertEqualDocumentsSlice(t, expectedIndexes, FetchAll(t, ctx, cursor))
+
+	_, err = collection.InsertMany(ctx, []any{
+		bson.D{{"_id", int32(3)}},
+		bson.D{{"_id", int32(1)}},
+		bson.D{{"_id", int32(2)}},
+	})
+	require.NoError(t, err)
+
+	cursor, err = collection.Find(ctx, bson.D{})
+	require.NoError(t, err)
+
+	expectedDocs := []bson.D{{{"_id", int32(1)}}, {{"_id", int32(2)}}, {{"_id", int32(3)}}}
+	AssertEqualDocumentsSlice(t, expectedDocs, FetchAll(t, ctx, cursor))
+
+	cursor, err = collection.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"$natural", -1}}))
+	require.NoError(t, err)
+
+	expectedDocs = []bson.D{{{"_id", int32(3)}}, {{"_id", int32(2)}}, {{"_id", int32(1)}}}
+	AssertEqualDocumentsSlice(t, expectedDocs, FetchAll(t, ctx, cursor))
+}
+
+func TestCreateClusteredInvalidSpec(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	for name, tc := range map[string]struct {
+		clusteredIndex bson.D
+
+		err *mongo.CommandError
+	}{
+		"NotID": {
+			clusteredIndex: bson.D{{"key", bson.D{{"v", int32(1)}}}, {"unique", true}},
+			err: &mongo.CommandError{
+				Code:    197,
+				Name:    "InvalidIndexSpecificationOption",
+				Message: "The clusteredIndex option is only supported for key: {_id: 1}",
+			},
+		},
+		"NotUnique": {
+			clusteredIndex: bson.D{{"key", bson.D{{"_id", int32(1)}}}, {"unique", false}},
+			err: &mongo.CommandError{
+				Code:    197,
+				Name:    "InvalidIndexSpecificationOption",
+				Message: "The clusteredIndex option requires unique: true",
+			},
+		},
+		"MissingUnique": {
+			clusteredIndex: bson.D{{"key", bson.D{{"_id", int32(1)}}}},
+			err: &mongo.CommandError{
+				Code:    40414,
+				Name:    "Location40414",
+				Message: "BSON field 'create.clusteredIndex.unique' is missing but a required field",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := db.RunCommand(ctx, bson.D{
+				{"create", collection.Name() + name},
+				{"clusteredIndex", tc.clusteredIndex},
+			}).Err()
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
+
+func TestCreateStorageCodec(t *testing.T) {
+	t.Parallel()
+
+	if !setup.IsPostgreSQL(t) {
+		t.Skip("Storage codecs are supported only by the PostgreSQL backend")
+	}
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	docs := []any{
+		bson.D{{"_id", int32(1)}, {"v", "foo"}, {"w", bson.D{{"x", int64(42)}}}, {"z", math.Copysign(0, -1)}},
+		bson.D{{"_id", int32(2)}, {"v", bson.A{"bar", 42.13}}, {"w", primitive.NewDateTimeFromTime(time.Unix(0, 0))}},
+		bson.D{{"_id", int32(3)}, {"w", nil}, {"v", int64(math.MaxInt64)}},
+	}
+
+	for name, tc := range map[string]struct {
+		opts bson.D
+
+		options  bson.D
+		indexErr bool
+	}{
+		"JSONB": {
+			opts: bson.D{{"codec", "jsonb"}},
+		},
+		"BSON": {
+			opts:     bson.D{{"codec", "bson"}},
+			options:  bson.D{{"storageEngine", bson.D{{"postgresql", bson.D{{"codec", "bson"}}}}}},
+			indexErr: true,
+		},
+		"Hybrid": {
+			opts: bson.D{{"codec", "hybrid"}, {"fields", bson.A{"w"}}},
+			options: bson.D{{"storageEngine", bson.D{{"postgresql", bson.D{
+				{"codec", "hybrid"},
+				{"fields", bson.A{"w"}},
+			}}}}},
+			indexErr: true,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cName := collection.Name() + name
+
+			err := db.RunCommand(ctx, bson.D{
+				{"create", cName},
+				{"storageEngine", bson.D{{"postgresql", tc.opts}}},
+			}).Err()
+			require.NoError(t, err)
+
+			cursor, err := db.ListCollections(ctx, bson.D{{"name", cName}})
+			require.NoError(t, err)
+
+			var res []bson.D
+			require.NoError(t, cursor.All(ctx, &res))
+			require.Len(t, res, 1)
+
+			actual := bson.D{}
+			for _, e := range res[0] {
+				if e.Key == "options" {
+					actual = e.Value.(bson.D)
+				}
+			}
+
+			expected := tc.options
+			if expected == nil {
+				expected = bson.D{}
+			}
+			AssertEqualDocuments(t, expected, actual)
+
+			c := db.Collection(cName)
+
+			_, err = c.InsertMany(ctx, docs)
+			require.NoError(t, err)
+
+			cursor, err = c.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{"_id", 1}}))
+			require.NoError(t, err)
+
+			expectedDocs := make([]bson.D, len(docs))
+			for i, doc := range docs {
+				expectedDocs[i] = doc.(bson.D)
+			}
+			AssertEqualDocumentsSlice(t, expectedDocs, FetchAll(t, ctx, cursor))
+
+			for filter, ids := range map[string]struct {
+				filter bson.D
+				ids    []int32
+			}{
+				"ID":        {bson.D{{"_id", int32(2)}}, []int32{2}},
+				"Stored":    {bson.D{{"w.x", int64(42)}}, []int32{1}},
+				"NotStored": {bson.D{{"v", "bar"}}, []int32{2}},
+			} {
+				cursor, err = c.Find(ctx, ids.filter)
+				require.NoError(t, err, filter)
+
+				var actualIDs []int32
+				for _, doc := range FetchAll(t, ctx, cursor) {
+					actualIDs = append(actualIDs, doc[0].Value.(int32))
+				}
+
+				assert.Equal(t, ids.ids, actualIDs, filter)
+			}
+
+			_, err = c.UpdateOne(ctx, bson.D{{"_id", int32(1)}}, bson.D{{"$set", bson.D{{"v", "baz"}}}})
+			require.NoError(t, err)
+
+			var doc bson.D
+			require.NoError(t, c.FindOne(ctx, bson.D{{"v", "baz"}}).Decode(&doc))
+			AssertEqualDocuments(t, bson.D{
+				{"_id", int32(1)}, {"v", "baz"}, {"w", bson.D{{"x", int64(42)}}}, {"z", math.Copysign(0, -1)},
+			}, doc)
+
+			_, err = c.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"v", 1}}})
+			if !tc.indexErr {
+				require.NoError(t, err)
+				return
+			}
+
+			AssertEqualCommandError(t, mongo.CommandError{
+				Code:    67,
+				Name:    "CannotCreateIndex",
+				Message: "Index fields are not supported by the collection's storage codec",
+			}, err)
+
+			_, err = c.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"_id", 1}, {"w", -1}}})
+			if name == "BSON" {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateStorageCodecInvalidSpec(t *testing.T) {
+	t.Parallel()
+
+	if !setup.IsPostgreSQL(t) {
+		t.Skip("Storage codecs are supported only by the PostgreSQL backend")
+	}
+
+	ctx, collection := setup.Setup(t)
+	db := collection.Database()
+
+	for name, tc := range map[string]struct {
+		command bson.D
+
+		err *mongo.CommandError
+	}{
+		"UnknownCodec": {
+			command: bson.D{{"storageEngine", bson.D{{"postgresql", bson.D{{"codec", "xml"}}}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "Enumeration value 'xml' for field 'create.storageEngine.postgresql.codec' is not a valid value.",
+			},
+		},
+		"NotDocument": {
+			command: bson.D{{"storageEngine", bson.D{{"postgresql", "bson"}}}},
+			err: &mongo.CommandError{
+				Code:    14,
+				Name:    "TypeMismatch",
+				Message: "'storageEngine.postgresql' has to be an embedded document.",
+			},
+		},
+		"FieldsWithoutHybrid": {
+			command: bson.D{{"storageEngine", bson.D{{"postgresql", bson.D{{"codec", "bson"}, {"fields", bson.A{"v"}}}}}}},
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: `'create.storageEngine.postgresql.fields' is only supported by the "hybrid" codec`,
+			},
+		},
+		"DottedField": {
+			command: bson.D{{"storageEngine", bson.D{{"postgresql", bson.D{{"codec", "hybrid"}, {"fields", bson.A{"v.w"}}}}}}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: `'create.storageEngine.postgresql.fields' must contain non-empty top-level field names: [ "v.w" ]`,
+			},
+		},
+		"TimeSeries": {
+			command: bson.D{
+				{"timeseries", bson.D{{"timeField", "t"}}},
+				{"storageEngine", bson.D{{"postgresql", bson.D{{"codec", "bson"}}}}},
+			},
+			err: &mongo.CommandError{
+				Code:    72,
+				Name:    "InvalidOptions",
+				Message: `Time-series collections do not support the "bson" storage codec`,
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := db.RunCommand(ctx, append(bson.D{{"create", collection.Name() + name}}, tc.command...)).Err()
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+func TestDiffJavaScript(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertOne(ctx, bson.D{{"_id", "document"}, {"v", int32(42)}})
+	require.NoError(t, err)
+
+	function := bson.D{{"$function", bson.D{
+		{"body", "function(v) { return v * 2; }"},
+		{"args", bson.A{"$v"}},
+		{"lang", "js"},
+	}}}
+
+	accumulator := bson.D{{"$accumulator", bson.D{
+		{"init", "function() { return 0; }"},
+		{"accumulate", "function(state, v) { return state + v; }"},
+		{"accumulateArgs", bson.A{"$v"}},
+		{"merge", "function(a, b) { return a + b; }"},
+		{"lang", "js"},
+	}}}
+
+	javaScriptDisabled := &mongo.CommandError{
+		Code:    31264,
+		Name:    "Location31264",
+		Message: "Cannot run server-side javascript without the javascript engine enabled",
+	}
+
+	for name, tc := range map[string]struct {
+		pipeline bson.A // pipeline for aggregation
+		filter   bson.D // filter for find, used if pipeline is nil
+
+		err *mongo.CommandError // error returned by FerretDB
+	}{
+		"FunctionProject": {
+			pipeline: bson.A{bson.D{{"$project", bson.D{{"v", function}}}}},
+			err:      javaScriptDisabled,
+		},
+		"FunctionAddFields": {
+			pipeline: bson.A{bson.D{{"$addFields", bson.D{{"v", function}}}}},
+			err:      javaScriptDisabled,
+		},
+		"FunctionGroup": {
+			pipeline: bson.A{bson.D{{"$group", bson.D{{"_id", function}}}}},
+			err:      javaScriptDisabled,
+		},
+		"FunctionExpr": {
+			filter: bson.D{{"$expr", function}},
+			err:    javaScriptDisabled,
+		},
+		"Accumulator": {
+			pipeline: bson.A{bson.D{{"$group", bson.D{{"_id", nil}, {"v", accumulator}}}}},
+			err:      javaScriptDisabled,
+		},
+		"Where": {
+			filter: bson.D{{"$where", "this.v == 42"}},
+			err: &mongo.CommandError{
+				Code:    2,
+				Name:    "BadValue",
+				Message: "no globalScriptEngine in $where parsing",
+			},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var cursor *mongo.Cursor
+			var err error
+
+			if tc.pipeline != nil {
+				cursor, err = collection.Aggregate(ctx, tc.pipeline)
+			} else {
+				cursor, err = collection.Find(ctx, tc.filter)
+			}
+
+			if setup.IsMongoDB(t) {
+				require.NoError(t, err)
+				require.NoError(t, cursor.Close(ctx))
+
+				return
+			}
+
+			AssertEqualCommandError(t, *tc.err, err)
+		})
+	}
+}
@@ -374,6 +374,67 @@ func TestFindAndModifyCompatUpdateSet(t *testing.T) {
 	testFindAndModifyCompat(t, testCases)
 }
 
+func TestFindAndModifyCompatUpdatePipeline(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]findAndModifyCompatTestCase{
+		"Set": {
+			command: bson.D{
+				{"query", bson.D{{"_id", "int32"}}},
+				{"update", bson.A{bson.D{{"$set", bson.D{{"v", "foo"}, {"copy", "$v"}}}}}},
+				{"new", true},
+			},
+		},
+		"SetUnset": {
+			command: bson.D{
+				{"query", bson.D{{"_id", "int64"}}},
+				{"update", bson.A{
+					bson.D{{"$set", bson.D{{"old", "$v"}}}},
+					bson.D{{"$unset", "v"}},
+				}},
+				{"new", true},
+			},
+		},
+		"Project": {
+			command: bson.D{
+				{"query", bson.D{{"_id", "string"}}},
+				{"update", bson.A{bson.D{{"$project", bson.D{{"v", 1}}}}}},
+				{"new", true},
+			},
+		},
+		"Empty": {
+			command: bson.D{
+				{"query", bson.D{{"_id", "double"}}},
+				{"update", bson.A{}},
+			},
+		},
+		"Upsert": {
+			command: bson.D{
+				{"query", bson.D{{"_id", "no-such-id"}}},
+				{"update", bson.A{bson.D{{"$set", bson.D{{"v", "foo"}}}}}},
+				{"upsert", true},
+				{"new", true},
+			},
+		},
+		"Match": {
+			command: bson.D{
+				{"query", bson.D{{"_id", "int32"}}},
+				{"update", bson.A{bson.D{{"$match", bson.D{{"v", 42}}}}}},
+			},
+			resultType: emptyResult,
+		},
+		"NotDocument": {
+			command: bson.D{
+				{"query", bson.D{{"_id", "int32"}}},
+				{"update", bson.A{"$set"}},
+			},
+			resultType: emptyResult,
+		},
+	}
+
+	testFindAndModifyCompat(t, testCases)
+}
+
 func TestFindAndModifyCompatUnset(t *testing.T) {
 	t.Parallel()
 
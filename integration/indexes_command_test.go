@@ -24,6 +24,8 @@ import (
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 // TestListIndexesCommandNonExistentNS tests that the listIndexes command returns a particular error
@@ -597,3 +599,34 @@ func TestDropIndexesCommandInvalidCollection(t *testing.T) {
 		})
 	}
 }
+
+func TestReIndexCommand(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t, shareddata.Int32s)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{"v", 1}}})
+	require.NoError(t, err)
+
+	var res bson.D
+	err = collection.Database().RunCommand(ctx, bson.D{{"reIndex", collection.Name()}}).Decode(&res)
+	require.NoError(t, err)
+
+	doc := ConvertDocument(t, res)
+	assert.EqualValues(t, 2, must.NotFail(doc.Get("nIndexesWas")))
+	assert.EqualValues(t, 2, must.NotFail(doc.Get("nIndexes")))
+	assert.Equal(t, float64(1), must.NotFail(doc.Get("ok")))
+
+	indexes := must.NotFail(doc.Get("indexes")).(*types.Array)
+	require.Equal(t, 2, indexes.Len())
+	assert.Equal(t, "_id_", must.NotFail(must.NotFail(indexes.Get(0)).(*types.Document).Get("name")))
+	assert.Equal(t, "v_1", must.NotFail(must.NotFail(indexes.Get(1)).(*types.Document).Get("name")))
+
+	err = collection.Database().RunCommand(ctx, bson.D{{"reIndex", "nonexistentColl"}}).Err()
+	expected := mongo.CommandError{
+		Code:    26,
+		Name:    "NamespaceNotFound",
+		Message: "collection " + collection.Database().Name() + ".nonexistentColl does not exist.",
+	}
+	AssertEqualCommandError(t, expected, err)
+}
@@ -16,12 +16,15 @@ package integration
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
@@ -195,3 +198,46 @@ func TestInsertIDDifferentTypes(t *testing.T) {
 		err,
 	)
 }
+
+func TestInsertCommandUnacknowledged(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	unack := collection.Database().Collection(
+		collection.Name(),
+		options.Collection().SetWriteConcern(writeconcern.Unacknowledged()),
+	)
+
+	// the driver sends unacknowledged writes with moreToCome flag and does not wait for responses
+	docs := make([]any, 100)
+	for i := range docs {
+		docs[i] = bson.D{{"_id", int32(i)}}
+	}
+
+	res, err := unack.InsertMany(ctx, docs)
+	require.NoError(t, err)
+	assert.False(t, res.Acknowledged)
+
+	// duplicate key error is not reported to the client
+	_, err = unack.InsertOne(ctx, bson.D{{"_id", int32(0)}})
+	require.NoError(t, err)
+
+	// the same, but without moreToCome flag; the client waits for the response
+	var cmdRes bson.D
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{"insert", collection.Name()},
+		{"documents", bson.A{bson.D{{"_id", int32(100)}}}},
+		{"writeConcern", bson.D{{"w", int32(0)}}},
+	}).Decode(&cmdRes)
+	require.NoError(t, err)
+	assert.Equal(t, bson.D{{"ok", float64(1)}}, cmdRes)
+
+	// unacknowledged writes could be still in progress, even on other connections
+	require.Eventually(t, func() bool {
+		count, err := collection.CountDocuments(ctx, bson.D{})
+		require.NoError(t, err)
+
+		return count == 101
+	}, 10*time.Second, 50*time.Millisecond)
+}
@@ -89,7 +89,8 @@ func TestQueryArrayCompatDotNotation(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"Field": {
-			filter: bson.D{{"v.array", int32(42)}},
+			filter:         bson.D{{"v.array", int32(42)}},
+			resultPushdown: pgPushdown,
 		},
 		"FieldPosition": {
 			filter: bson.D{{"v.array.0", int32(42)}},
@@ -110,22 +111,28 @@ func TestQueryArrayCompatDotNotation(t *testing.T) {
 			},
 		},
 		"DocumentDotNotationArrayDocumentNoIndex": {
-			filter: bson.D{{"v.foo.bar", "hello"}},
+			filter:         bson.D{{"v.foo.bar", "hello"}},
+			resultPushdown: pgPushdown,
 		},
 		"FieldArrayIndex": {
-			filter: bson.D{{"v.foo[0]", int32(42)}},
+			filter:         bson.D{{"v.foo[0]", int32(42)}},
+			resultPushdown: pgPushdown,
 		},
 		"FieldArrayAsterix": {
-			filter: bson.D{{"v.foo[*]", int32(42)}},
+			filter:         bson.D{{"v.foo[*]", int32(42)}},
+			resultPushdown: pgPushdown,
 		},
 		"FieldAsterix": {
-			filter: bson.D{{"v.*", int32(42)}},
+			filter:         bson.D{{"v.*", int32(42)}},
+			resultPushdown: pgPushdown,
 		},
 		"FieldAt": {
-			filter: bson.D{{"v.@", int32(42)}},
+			filter:         bson.D{{"v.@", int32(42)}},
+			resultPushdown: pgPushdown,
 		},
 		"FieldComma": {
-			filter: bson.D{{"v.f,oo", int32(42)}},
+			filter:         bson.D{{"v.f,oo", int32(42)}},
+			resultPushdown: pgPushdown,
 		},
 	}
 
@@ -240,46 +247,57 @@ func TestQueryArrayCompatAll(t *testing.T) {
 
 	testCases := map[string]queryCompatTestCase{
 		"String": {
-			filter: bson.D{{"v", bson.D{{"$all", bson.A{"foo"}}}}},
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{"foo"}}}}},
+			resultPushdown: pgPushdown,
 		},
 		"StringRepeated": {
-			filter: bson.D{{"v", bson.D{{"$all", bson.A{"foo", "foo", "foo"}}}}},
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{"foo", "foo", "foo"}}}}},
+			resultPushdown: pgPushdown,
 		},
 		"StringEmpty": {
-			filter: bson.D{{"v", bson.D{{"$all", bson.A{""}}}}},
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{""}}}}},
+			resultPushdown: pgPushdown,
 		},
 		"Whole": {
-			filter: bson.D{{"v", bson.D{{"$all", bson.A{int32(42)}}}}},
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{int32(42)}}}}},
+			resultPushdown: pgPushdown,
 		},
 		"WholeNotFound": {
-			filter:     bson.D{{"v", bson.D{{"$all", bson.A{int32(46)}}}}},
-			resultType: emptyResult,
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{int32(46)}}}}},
+			resultType:     emptyResult,
+			resultPushdown: pgPushdown,
 		},
 		"Zero": {
-			filter: bson.D{{"v", bson.D{{"$all", bson.A{0}}}}},
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{0}}}}},
+			resultPushdown: pgPushdown,
 		},
 		"Double": {
-			filter: bson.D{{"v", bson.D{{"$all", bson.A{42.13}}}}},
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{42.13}}}}},
+			resultPushdown: pgPushdown,
 		},
 		"DoubleMax": {
 			filter: bson.D{{"v", bson.D{{"$all", bson.A{math.MaxFloat64}}}}},
 		},
 		"DoubleMin": {
-			filter: bson.D{{"v", bson.D{{"$all", bson.A{math.SmallestNonzeroFloat64}}}}},
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{math.SmallestNonzeroFloat64}}}}},
+			resultPushdown: pgPushdown,
 		},
 		"MultiAll": {
-			filter: bson.D{{"v", bson.D{{"$all", bson.A{"foo", 42}}}}},
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{"foo", 42}}}}},
+			resultPushdown: pgPushdown,
 		},
 		"MultiAllWithNil": {
-			filter: bson.D{{"v", bson.D{{"$all", bson.A{"foo", nil}}}}},
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{"foo", nil}}}}},
+			resultPushdown: pgPushdown,
 		},
 		"Empty": {
 			filter:     bson.D{{"v", bson.D{{"$all", bson.A{}}}}},
 			resultType: emptyResult,
 		},
 		"NotFound": {
-			filter:     bson.D{{"v", bson.D{{"$all", bson.A{"hello"}}}}},
-			resultType: emptyResult,
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{"hello"}}}}},
+			resultType:     emptyResult,
+			resultPushdown: pgPushdown,
 		},
 		"$allNeedsAnArrayInt": {
 			filter:     bson.D{{"v", bson.D{{"$all", 1}}}},
@@ -290,10 +308,12 @@ func TestQueryArrayCompatAll(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"WholeInTheMiddle": {
-			filter: bson.D{{"v", bson.D{{"$all", bson.A{int32(43)}}}}},
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{int32(43)}}}}},
+			resultPushdown: pgPushdown,
 		},
 		"WholeTwoRepeated": {
-			filter: bson.D{{"v", bson.D{{"$all", bson.A{int32(42), int32(43), int32(43), int32(42)}}}}},
+			filter:         bson.D{{"v", bson.D{{"$all", bson.A{int32(42), int32(43), int32(43), int32(42)}}}}},
+			resultPushdown: pgPushdown,
 		},
 		"Nil": {
 			filter: bson.D{{"v", bson.D{{"$all", bson.A{nil}}}}},
@@ -50,11 +50,13 @@ func TestQueryComparisonCompatImplicit(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"DocumentDotNotation": {
-			filter: bson.D{{"v.foo", int32(42)}},
+			filter:         bson.D{{"v.foo", int32(42)}},
+			resultPushdown: pgPushdown,
 		},
 		"DocumentDotNotationNoSuchField": {
-			filter:     bson.D{{"no-such-field.some", 42}},
-			resultType: emptyResult,
+			filter:         bson.D{{"no-such-field.some", 42}},
+			resultType:     emptyResult,
+			resultPushdown: pgPushdown,
 		},
 		"ArrayNoSuchField": {
 			filter:     bson.D{{"no-such-field", bson.A{42}}},
@@ -314,7 +316,8 @@ func TestQueryComparisonCompatEq(t *testing.T) {
 			resultType: emptyResult,
 		},
 		"DocumentDotNotation": {
-			filter: bson.D{{"v.foo", bson.D{{"$eq", int32(42)}}}},
+			filter:         bson.D{{"v.foo", bson.D{{"$eq", int32(42)}}}},
+			resultPushdown: pgPushdown,
 		},
 		"DocumentReverse": {
 			filter: bson.D{{"v", bson.D{
@@ -1380,6 +1383,21 @@ func TestQueryComparisonCompatNe(t *testing.T) {
 			filter:     bson.D{{"v", bson.D{{"$ne", primitive.Regex{Pattern: "foo"}}}}},
 			resultType: emptyResult,
 		},
+		"DocumentDotNotation": {
+			filter: bson.D{{"v.foo", bson.D{{"$ne", int32(42)}}}},
+		},
+		"DocumentDotNotationString": {
+			filter: bson.D{{"v.foo", bson.D{{"$ne", "foo"}}}},
+		},
+		"DocumentDotNotationNoSuchField": {
+			filter: bson.D{{"no-such-field.some", bson.D{{"$ne", int32(42)}}}},
+		},
+		"ArrayDotNotation": {
+			filter: bson.D{{"v.array", bson.D{{"$ne", int32(42)}}}},
+		},
+		"ArrayDotNotationPosition": {
+			filter: bson.D{{"v.array.0", bson.D{{"$ne", int32(42)}}}},
+		},
 		"Document": {
 			filter: bson.D{{"v", bson.D{{"$ne", bson.D{{"foo", int32(42)}, {"42", "foo"}, {"array", bson.A{int32(42), "foo", nil}}}}}}},
 		},
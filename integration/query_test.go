@@ -29,6 +29,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/integration/setup"
 	"github.com/FerretDB/FerretDB/integration/shareddata"
+	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/testutil"
 )
 
@@ -672,7 +673,7 @@ func TestQueryCommandLimitPushDown(t *testing.T) {
 			filter:         bson.D{{"v.foo", 42}},
 			limit:          3,
 			len:            3,
-			filterPushdown: noPushdown,
+			filterPushdown: pgPushdown,
 			limitPushdown:  noPushdown,
 		},
 		"ObjectFilter": {
@@ -710,7 +711,7 @@ func TestQueryCommandLimitPushDown(t *testing.T) {
 			sort:           bson.D{{"_id", 1}},
 			limit:          3,
 			len:            3,
-			filterPushdown: noPushdown,
+			filterPushdown: pgPushdown,
 			limitPushdown:  noPushdown,
 		},
 		"ObjectFilterSort": {
@@ -805,6 +806,96 @@ func TestQueryCommandLimitPushDown(t *testing.T) {
 	}
 }
 
+// TestQueryNestedArrayPushdown checks that filters on arrays of documents return correct results
+// and that explain reports which paths were pushed down.
+func TestQueryNestedArrayPushdown(t *testing.T) {
+	t.Parallel()
+
+	ctx, collection := setup.Setup(t)
+
+	_, err := collection.InsertMany(ctx, []any{
+		bson.D{{"_id", "both"}, {"v", bson.A{
+			bson.D{{"foo", int32(1)}, {"bar", "a"}},
+			bson.D{{"foo", int32(2)}, {"bar", bson.A{"b", "c"}}},
+		}}},
+		bson.D{{"_id", "split"}, {"v", bson.A{
+			bson.D{{"foo", int32(1)}, {"bar", "b"}},
+			bson.D{{"foo", int32(2)}, {"bar", "a"}},
+		}}},
+		bson.D{{"_id", "document"}, {"v", bson.D{{"foo", int32(1)}, {"bar", "a"}}}},
+		bson.D{{"_id", "scalar"}, {"v", int32(1)}},
+	})
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		filter   bson.D
+		expected []any
+		paths    bson.D // expected filterPushdownPaths for PostgreSQL
+	}{
+		"DotNotation": {
+			filter:   bson.D{{"v.foo", int32(1)}},
+			expected: []any{"both", "document", "split"},
+			paths:    bson.D{{"v.foo", true}},
+		},
+		"DotNotationArray": {
+			filter:   bson.D{{"v.bar", "c"}},
+			expected: []any{"both"},
+			paths:    bson.D{{"v.bar", true}},
+		},
+		"ElemMatch": {
+			filter:   bson.D{{"v", bson.D{{"$elemMatch", bson.D{{"foo", int32(1)}, {"bar", "a"}}}}}},
+			expected: []any{"both"},
+			paths:    bson.D{{"v", true}},
+		},
+		"ElemMatchGt": {
+			filter:   bson.D{{"v", bson.D{{"$elemMatch", bson.D{{"foo", bson.D{{"$gt", int32(1)}}}}}}}},
+			expected: []any{"both", "split"},
+			paths:    bson.D{{"v", false}},
+		},
+		"All": {
+			filter:   bson.D{{"v.bar", bson.D{{"$all", bson.A{"a", "b"}}}}},
+			expected: []any{"both", "split"},
+			paths:    bson.D{{"v.bar", true}},
+		},
+		"Mixed": {
+			filter:   bson.D{{"v.foo", int32(2)}, {"v.bar", bson.D{{"$exists", true}}}},
+			expected: []any{"both", "split"},
+			paths:    bson.D{{"v.foo", true}, {"v.bar", false}},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			cursor, err := collection.Find(ctx, tc.filter, options.Find().SetSort(bson.D{{"_id", 1}}))
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, CollectIDs(t, FetchAll(t, ctx, cursor)))
+
+			if !setup.IsPostgreSQL(t) || setup.PushdownDisabled() {
+				return
+			}
+
+			var res bson.D
+			err = collection.Database().RunCommand(ctx, bson.D{{"explain", bson.D{
+				{"find", collection.Name()},
+				{"filter", tc.filter},
+			}}}).Decode(&res)
+			require.NoError(t, err)
+
+			doc := ConvertDocument(t, res)
+
+			var pushdown bool
+			for _, e := range tc.paths {
+				pushdown = pushdown || e.Value.(bool)
+			}
+
+			assert.Equal(t, pushdown, must.NotFail(doc.Get("filterPushdown")))
+			assert.Equal(t, ConvertDocument(t, tc.paths), must.NotFail(doc.Get("filterPushdownPaths")))
+		})
+	}
+}
+
 // TestQueryIDDoc checks that the order of fields in the _id document matters.
 func TestQueryIDDoc(t *testing.T) {
 	t.Parallel()
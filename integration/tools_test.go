@@ -0,0 +1,173 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"cmp"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/FerretDB/FerretDB/integration/setup"
+)
+
+// runTool runs the given MongoDB Database Tools binary with arguments.
+// The test is skipped if it is not installed, unless it runs on CI where tools are always installed.
+func runTool(t *testing.T, name string, args ...string) {
+	t.Helper()
+
+	path, err := exec.LookPath(name)
+	if err != nil {
+		if ci, _ := strconv.ParseBool(os.Getenv("CI")); ci {
+			require.NoError(t, err, "%s should be installed on CI", name)
+		}
+
+		t.Skipf("%s is not installed: %s", name, err)
+	}
+
+	out, err := exec.Command(path, args...).CombinedOutput()
+	require.NoError(t, err, "%s", out)
+
+	t.Logf("%s:\n%s", name, out)
+}
+
+func TestToolsDumpRestore(t *testing.T) {
+	t.Parallel()
+
+	s := setup.SetupWithOpts(t, nil)
+	ctx, db := s.Ctx, s.Collection.Database()
+
+	restoredDB := db.Client().Database(db.Name() + "_restored")
+	t.Cleanup(func() {
+		require.NoError(t, restoredDB.Drop(ctx))
+	})
+
+	opts := options.CreateCollection().SetCapped(true).SetSizeInBytes(1024 * 1024).SetMaxDocuments(100)
+	require.NoError(t, db.CreateCollection(ctx, "capped", opts))
+
+	opts = options.CreateCollection().SetValidator(bson.D{{"v", bson.D{{"$type", "int"}}}})
+	opts.SetValidationLevel("moderate").SetValidationAction("error")
+	require.NoError(t, db.CreateCollection(ctx, "validated", opts))
+
+	_, err := db.Collection("indexed").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{"v", 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{"foo", -1}, {"bar", 1}}, Options: options.Index().SetName("custom")},
+	})
+	require.NoError(t, err)
+
+	for _, name := range []string{"capped", "validated", "indexed"} {
+		var docs []any
+		for i := int32(0); i < 50; i++ {
+			docs = append(docs, bson.D{{"_id", i}, {"v", i}, {"foo", "bar"}, {"bar", bson.A{i, "baz"}}})
+		}
+
+		_, err = db.Collection(name).InsertMany(ctx, docs)
+		require.NoError(t, err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "dump.archive")
+
+	runTool(t, "mongodump", "--uri="+s.MongoDBURI, "--db="+db.Name(), "--archive="+archive)
+	runTool(t, "mongorestore", "--uri="+s.MongoDBURI, "--archive="+archive,
+		"--nsFrom="+db.Name()+".*", "--nsTo="+restoredDB.Name()+".*",
+	)
+
+	expected, actual := listCollectionSpecs(t, ctx, db), listCollectionSpecs(t, ctx, restoredDB)
+	assert.Equal(t, expected, actual)
+
+	for _, name := range []string{"capped", "validated", "indexed"} {
+		cursor, err := db.Collection(name).Indexes().List(ctx)
+		require.NoError(t, err)
+
+		var expectedIndexes []bson.D
+		require.NoError(t, cursor.All(ctx, &expectedIndexes))
+
+		cursor, err = restoredDB.Collection(name).Indexes().List(ctx)
+		require.NoError(t, err)
+
+		var actualIndexes []bson.D
+		require.NoError(t, cursor.All(ctx, &actualIndexes))
+
+		assert.Equal(t, expectedIndexes, actualIndexes, name)
+
+		findOpts := options.Find().SetSort(bson.D{{"_id", 1}})
+
+		cursor, err = db.Collection(name).Find(ctx, bson.D{}, findOpts)
+		require.NoError(t, err)
+
+		var expectedDocs []bson.D
+		require.NoError(t, cursor.All(ctx, &expectedDocs))
+
+		cursor, err = restoredDB.Collection(name).Find(ctx, bson.D{}, findOpts)
+		require.NoError(t, err)
+
+		var actualDocs []bson.D
+		require.NoError(t, cursor.All(ctx, &actualDocs))
+
+		assert.Equal(t, expectedDocs, actualDocs, name)
+	}
+}
+
+// listCollectionSpecs returns `listCollections` results sorted by name
+// and without fields that differ between databases.
+func listCollectionSpecs(t *testing.T, ctx context.Context, db *mongo.Database) []bson.D {
+	t.Helper()
+
+	cursor, err := db.ListCollections(ctx, bson.D{})
+	require.NoError(t, err)
+
+	var res []bson.D
+	require.NoError(t, cursor.All(ctx, &res))
+
+	name := func(spec bson.D) string {
+		for _, e := range spec {
+			if e.Key == "name" {
+				return e.Value.(string)
+			}
+		}
+
+		return ""
+	}
+
+	slices.SortFunc(res, func(a, b bson.D) int {
+		return cmp.Compare(name(a), name(b))
+	})
+
+	for i, spec := range res {
+		filtered := make(bson.D, 0, len(spec))
+
+		for _, e := range spec {
+			if e.Key == "info" {
+				continue
+			}
+
+			filtered = append(filtered, e)
+		}
+
+		res[i] = filtered
+	}
+
+	return res
+}
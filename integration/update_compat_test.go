@@ -587,6 +587,62 @@ func TestUpdateCommandCompat(t *testing.T) {
 	}
 }
 
+func TestUpdateCompatPipeline(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		filter   bson.D
+		pipeline bson.A
+		upsert   bool
+	}{
+		"Set": {
+			filter:   bson.D{{"_id", "int32"}},
+			pipeline: bson.A{bson.D{{"$set", bson.D{{"v", "foo"}, {"copy", "$v"}}}}},
+		},
+		"AddFieldsUnset": {
+			filter: bson.D{{"_id", "int64"}},
+			pipeline: bson.A{
+				bson.D{{"$addFields", bson.D{{"old", "$v"}}}},
+				bson.D{{"$unset", bson.A{"v"}}},
+			},
+		},
+		"Project": {
+			filter:   bson.D{{"_id", "string"}},
+			pipeline: bson.A{bson.D{{"$project", bson.D{{"v", 0}}}}},
+		},
+		"Upsert": {
+			filter:   bson.D{{"_id", "no-such-id"}},
+			pipeline: bson.A{bson.D{{"$set", bson.D{{"v", "foo"}}}}},
+			upsert:   true,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Helper()
+
+			t.Parallel()
+
+			s := setup.SetupCompatWithOpts(t, &setup.SetupCompatOpts{
+				Providers: []shareddata.Provider{shareddata.Scalars},
+			})
+			ctx, targetCollection, compatCollection := s.Ctx, s.TargetCollections[0], s.CompatCollections[0]
+
+			opts := options.Update().SetUpsert(tc.upsert)
+
+			targetUpdateRes, targetErr := targetCollection.UpdateOne(ctx, tc.filter, tc.pipeline, opts)
+			compatUpdateRes, compatErr := compatCollection.UpdateOne(ctx, tc.filter, tc.pipeline, opts)
+			require.NoError(t, compatErr)
+			require.NoError(t, targetErr)
+			assert.Equal(t, compatUpdateRes, targetUpdateRes)
+
+			var targetRes, compatRes bson.D
+			require.NoError(t, targetCollection.FindOne(ctx, tc.filter).Decode(&targetRes))
+			require.NoError(t, compatCollection.FindOne(ctx, tc.filter).Decode(&compatRes))
+			AssertEqualDocuments(t, compatRes, targetRes)
+		})
+	}
+}
+
 func TestUpdateCompat(t *testing.T) {
 	t.Parallel()
 
@@ -45,6 +45,8 @@ type Backend interface {
 	ListDatabases(context.Context, *ListDatabasesParams) (*ListDatabasesResult, error)
 	DropDatabase(context.Context, *DropDatabaseParams) error
 
+	Checkpoint(context.Context, *CheckpointParams) error
+
 	prometheus.Collector
 
 	// There is no interface method to create a database; see package documentation.
@@ -181,6 +183,23 @@ func (bc *backendContract) DropDatabase(ctx context.Context, params *DropDatabas
 	return err
 }
 
+// CheckpointParams represents the parameters of Backend.Checkpoint method.
+type CheckpointParams struct{}
+
+// Checkpoint flushes all committed writes to the durable storage files,
+// so they could be copied by a file system-level backup.
+//
+// Backends that can't do that (for example, because of insufficient privileges)
+// should still return nil if committed writes are already durable.
+func (bc *backendContract) Checkpoint(ctx context.Context, params *CheckpointParams) error {
+	defer observability.FuncCall(ctx)()
+
+	err := bc.b.Checkpoint(ctx, params)
+	checkError(err)
+
+	return err
+}
+
 // Describe implements prometheus.Collector.
 func (bc *backendContract) Describe(ch chan<- *prometheus.Desc) {
 	bc.b.Describe(ch)
@@ -52,6 +52,13 @@ type Collection interface {
 	ListIndexes(context.Context, *ListIndexesParams) (*ListIndexesResult, error)
 	CreateIndexes(context.Context, *CreateIndexesParams) (*CreateIndexesResult, error)
 	DropIndexes(context.Context, *DropIndexesParams) (*DropIndexesResult, error)
+	ReIndex(context.Context, *ReIndexParams) (*ReIndexResult, error)
+
+	Search(context.Context, *SearchParams) (*SearchResult, error)
+	VectorSearch(context.Context, *VectorSearchParams) (*VectorSearchResult, error)
+	ListSearchIndexes(context.Context, *ListSearchIndexesParams) (*ListSearchIndexesResult, error)
+	CreateSearchIndexes(context.Context, *CreateSearchIndexesParams) (*CreateSearchIndexesResult, error)
+	DropSearchIndexes(context.Context, *DropSearchIndexesParams) (*DropSearchIndexesResult, error)
 }
 
 // collectionContract implements Collection interface.
@@ -100,6 +107,8 @@ type QueryResult struct {
 // Sort should have one of the following forms: nil, {}, {"$natural": int64(1)} or {"$natural": int64(-1)}.
 // Other field names are not supported.
 // If non-empty, it should be applied.
+// The natural order is the insertion order for capped collections, the order of _id values for clustered collections,
+// and the storage order for other collections.
 //
 // Limit, if non-zero, should be applied.
 func (cc *collectionContract) Query(ctx context.Context, params *QueryParams) (*QueryResult, error) {
@@ -137,6 +146,9 @@ type ExplainResult struct {
 	FilterPushdown bool
 	SortPushdown   bool
 	LimitPushdown  bool
+
+	// top-level filter keys and whether they were pushed down; nil if the backend does not report them
+	FilterPushdownPaths *types.Document
 }
 
 // Explain return a backend-specific execution plan for the given query.
@@ -367,6 +379,19 @@ func (cc *collectionContract) ListIndexes(ctx context.Context, params *ListIndex
 // CreateIndexesParams represents the parameters of Collection.CreateIndexes method.
 type CreateIndexesParams struct {
 	Indexes []IndexInfo
+
+	// Progress, if not nil, is called periodically while indexes are being built.
+	Progress func(*IndexBuildProgress)
+}
+
+// IndexBuildProgress represents the progress of a single index build.
+//
+// Done and Total are in backend-specific units (rows, pages, etc); Total is zero if unknown.
+type IndexBuildProgress struct {
+	Index string
+	Phase string
+	Done  int64
+	Total int64
 }
 
 // CreateIndexesResult represents the results of Collection.CreateIndexes method.
@@ -378,12 +403,34 @@ type CreateIndexesResult struct{}
 // If some indexes cannot be created, the operation should be rolled back,
 // and the first encountered error should be returned.
 //
+// Backends should avoid blocking writes to the collection while indexes are built, if possible.
+// If the backend cannot index some of the given fields (for example, because of the collection's storage codec),
+// ErrorCodeNotSupported should be returned.
+//
 // Database or collection may not exist; that's not an error.
 func (cc *collectionContract) CreateIndexes(ctx context.Context, params *CreateIndexesParams) (*CreateIndexesResult, error) {
 	defer observability.FuncCall(ctx)()
 
 	res, err := cc.c.CreateIndexes(ctx, params)
-	checkError(err)
+	checkError(err, ErrorCodeNotSupported)
+
+	return res, err
+}
+
+// ReIndexParams represents the parameters of Collection.ReIndex method.
+type ReIndexParams struct{}
+
+// ReIndexResult represents the results of Collection.ReIndex method.
+type ReIndexResult struct{}
+
+// ReIndex rebuilds all indexes of the collection.
+//
+// Backends should avoid blocking writes to the collection while indexes are rebuilt, if possible.
+func (cc *collectionContract) ReIndex(ctx context.Context, params *ReIndexParams) (*ReIndexResult, error) {
+	defer observability.FuncCall(ctx)()
+
+	res, err := cc.c.ReIndex(ctx, params)
+	checkError(err, ErrorCodeDatabaseDoesNotExist, ErrorCodeCollectionDoesNotExist)
 
 	return res, err
 }
@@ -412,6 +459,217 @@ func (cc *collectionContract) DropIndexes(ctx context.Context, params *DropIndex
 	return res, err
 }
 
+// SearchOperator represents a full-text search operator.
+type SearchOperator string
+
+// Search operators.
+const (
+	// SearchText matches documents containing any of the analyzed query terms.
+	SearchText SearchOperator = "text"
+
+	// SearchPhrase matches documents containing query terms in the given order.
+	SearchPhrase SearchOperator = "phrase"
+
+	// SearchAutocomplete matches documents with words starting with the query.
+	SearchAutocomplete SearchOperator = "autocomplete"
+)
+
+// SearchParams represents the parameters of Collection.Search method.
+type SearchParams struct {
+	Index    string
+	Operator SearchOperator
+	Query    string
+	Paths    []string
+	Limit    int64
+}
+
+// SearchResult represents the results of Collection.Search method.
+type SearchResult struct {
+	Iter types.DocumentsIterator
+}
+
+// Search executes a full-text search query against the collection.
+//
+// Documents matching the query in at least one of the given paths should be returned,
+// ordered by relevance (the most relevant first) when the operator provides it.
+// Index names the search index that may be used to speed up the query.
+//
+// If database, collection or index does not exist it returns empty iterator.
+//
+// Limit, if non-zero, should be applied.
+//
+// Backends without full-text search support should return ErrorCodeNotSupported.
+func (cc *collectionContract) Search(ctx context.Context, params *SearchParams) (*SearchResult, error) {
+	defer observability.FuncCall(ctx)()
+
+	must.BeTrue(params.Operator != "")
+	must.BeTrue(len(params.Paths) > 0)
+
+	res, err := cc.c.Search(ctx, params)
+	checkError(err, ErrorCodeNotSupported)
+
+	return res, err
+}
+
+// VectorSearchParams represents the parameters of Collection.VectorSearch method.
+type VectorSearchParams struct {
+	Index       string
+	Path        string
+	QueryVector []float64
+	Filter      *types.Document
+	Limit       int64
+}
+
+// VectorSearchResult represents the results of Collection.VectorSearch method.
+type VectorSearchResult struct {
+	Iter types.DocumentsIterator
+}
+
+// VectorSearch executes an approximate nearest neighbor search against the collection
+// using the vector search index.
+//
+// Documents should be returned ordered by the similarity of the vector at the given path to the query vector
+// (the most similar first), using the similarity function of the index.
+// Documents without a valid vector at the given path should not be returned.
+//
+// Filter may be ignored, or safely applied partially or entirely.
+// Extra documents will be filtered out by the handler.
+//
+// Limit is the number of nearest neighbor candidates to return; it is always set.
+//
+// If database, collection or index does not exist it returns empty iterator.
+//
+// Backends that can't use the index for the search should return ErrorCodeNotSupported;
+// the handler will perform exact search instead.
+func (cc *collectionContract) VectorSearch(ctx context.Context, params *VectorSearchParams) (*VectorSearchResult, error) {
+	defer observability.FuncCall(ctx)()
+
+	must.BeTrue(params.Path != "")
+	must.BeTrue(len(params.QueryVector) > 0)
+	must.BeTrue(params.Limit > 0)
+
+	res, err := cc.c.VectorSearch(ctx, params)
+	checkError(err, ErrorCodeNotSupported)
+
+	return res, err
+}
+
+// ListSearchIndexesParams represents the parameters of Collection.ListSearchIndexes method.
+type ListSearchIndexesParams struct{}
+
+// ListSearchIndexesResult represents the results of Collection.ListSearchIndexes method.
+type ListSearchIndexesResult struct {
+	Indexes []SearchIndexInfo
+}
+
+// SearchIndexInfo represents information about a single search index.
+//
+// Full-text search indexes have Dynamic or Fields set,
+// vector search indexes have Vectors (and, optionally, Filters) set.
+type SearchIndexInfo struct {
+	Name    string
+	Dynamic bool
+	Fields  []SearchIndexField
+	Vectors []VectorSearchIndexField
+	Filters []string
+}
+
+// Vector returns true if that's a vector search index.
+func (i *SearchIndexInfo) Vector() bool {
+	return len(i.Vectors) > 0
+}
+
+// SearchIndexField represents a single field of the search index.
+type SearchIndexField struct {
+	Path         string
+	Autocomplete bool
+}
+
+// VectorSimilarity represents a function used to compare vectors.
+type VectorSimilarity string
+
+// Vector similarity functions.
+const (
+	VectorSimilarityCosine     VectorSimilarity = "cosine"
+	VectorSimilarityEuclidean  VectorSimilarity = "euclidean"
+	VectorSimilarityDotProduct VectorSimilarity = "dotProduct"
+)
+
+// VectorSearchIndexField represents a single vector field of the vector search index.
+type VectorSearchIndexField struct {
+	Path       string
+	Dimensions int
+	Similarity VectorSimilarity
+}
+
+// ListSearchIndexes returns a list of collection search indexes.
+//
+// The errors for non-existing database and non-existing collection are the same.
+func (cc *collectionContract) ListSearchIndexes(ctx context.Context, params *ListSearchIndexesParams) (*ListSearchIndexesResult, error) { //nolint:lll // for readability
+	defer observability.FuncCall(ctx)()
+
+	res, err := cc.c.ListSearchIndexes(ctx, params)
+	checkError(err, ErrorCodeCollectionDoesNotExist, ErrorCodeNotSupported)
+
+	if res != nil && len(res.Indexes) > 0 {
+		must.BeTrue(slices.IsSortedFunc(res.Indexes, func(a, b SearchIndexInfo) int {
+			return cmp.Compare(a.Name, b.Name)
+		}))
+	}
+
+	return res, err
+}
+
+// CreateSearchIndexesParams represents the parameters of Collection.CreateSearchIndexes method.
+type CreateSearchIndexesParams struct {
+	Indexes []SearchIndexInfo
+}
+
+// CreateSearchIndexesResult represents the results of Collection.CreateSearchIndexes method.
+type CreateSearchIndexesResult struct{}
+
+// CreateSearchIndexes creates search indexes for the collection.
+//
+// Existing indexes with given names are ignored.
+// If some indexes cannot be created, the operation should be rolled back,
+// and the first encountered error should be returned.
+//
+// Database or collection may not exist; that's not an error.
+func (cc *collectionContract) CreateSearchIndexes(ctx context.Context, params *CreateSearchIndexesParams) (*CreateSearchIndexesResult, error) { //nolint:lll // for readability
+	defer observability.FuncCall(ctx)()
+
+	for _, index := range params.Indexes {
+		must.BeTrue(index.Dynamic || len(index.Fields) > 0 || index.Vector())
+	}
+
+	res, err := cc.c.CreateSearchIndexes(ctx, params)
+	checkError(err, ErrorCodeNotSupported)
+
+	return res, err
+}
+
+// DropSearchIndexesParams represents the parameters of Collection.DropSearchIndexes method.
+type DropSearchIndexesParams struct {
+	Indexes []string
+}
+
+// DropSearchIndexesResult represents the results of Collection.DropSearchIndexes method.
+type DropSearchIndexesResult struct{}
+
+// DropSearchIndexes drops search indexes for the collection.
+//
+// Non-existing indexes are ignored.
+//
+// Database or collection may not exist; that's not an error.
+func (cc *collectionContract) DropSearchIndexes(ctx context.Context, params *DropSearchIndexesParams) (*DropSearchIndexesResult, error) { //nolint:lll // for readability
+	defer observability.FuncCall(ctx)()
+
+	res, err := cc.c.DropSearchIndexes(ctx, params)
+	checkError(err, ErrorCodeNotSupported)
+
+	return res, err
+}
+
 // check interfaces
 var (
 	_ Collection = (*collectionContract)(nil)
@@ -19,6 +19,7 @@ import (
 	"context"
 	"slices"
 
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/observability"
 )
@@ -92,7 +93,22 @@ type CollectionInfo struct {
 	UUID            string
 	CappedSize      int64
 	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+
+	// Validator is stored as is and is not interpreted by backends.
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+
+	TimeSeries *TimeSeriesOptions
+
+	// Clustered collections are ordered by _id values.
+	Clustered bool
+
+	// Codec is the backend-specific storage codec; empty for the backend's default.
+	Codec       string
+	CodecFields []string
+
+	_ struct{} // prevent unkeyed literals
 }
 
 // Capped returns true if collection is capped.
@@ -130,7 +146,34 @@ type CreateCollectionParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+
+	// Validator is stored as is and is not interpreted by backends.
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+
+	TimeSeries *TimeSeriesOptions
+
+	// Clustered collections are ordered by _id values.
+	Clustered bool
+
+	// Codec is the backend-specific storage codec, such as "bson" for PostgreSQL;
+	// empty value means the backend's default.
+	// CodecFields are top-level fields used by some codecs.
+	// Backends that do not support codecs ignore them.
+	Codec       string
+	CodecFields []string
+
+	_ struct{} // prevent unkeyed literals
+}
+
+// TimeSeriesOptions represents options of the time-series collection.
+//
+// Only TimeField is used by backends for the storage layout; other fields are stored as is.
+type TimeSeriesOptions struct {
+	TimeField   string
+	MetaField   string
+	Granularity string
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -146,6 +189,8 @@ func (dbc *databaseContract) CreateCollection(ctx context.Context, params *Creat
 
 	must.BeTrue(params.CappedSize >= 0)
 	must.BeTrue(params.CappedDocuments >= 0)
+	must.BeTrue(params.TimeSeries == nil || (params.TimeSeries.TimeField != "" && !params.Capped()))
+	must.BeTrue(!params.Clustered || (params.TimeSeries == nil && !params.Capped()))
 
 	err := validateCollectionName(params.Name)
 	if err == nil {
@@ -67,6 +67,11 @@ func (b *backend) DropDatabase(ctx context.Context, params *backends.DropDatabas
 	return b.b.DropDatabase(ctx, params)
 }
 
+// Checkpoint implements backends.Backend interface.
+func (b *backend) Checkpoint(ctx context.Context, params *backends.CheckpointParams) error {
+	return b.b.Checkpoint(ctx, params)
+}
+
 // Describe implements prometheus.Collector.
 func (b *backend) Describe(ch chan<- *prometheus.Desc) {
 	b.b.Describe(ch)
@@ -80,6 +80,36 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 	return c.c.DropIndexes(ctx, params)
 }
 
+// ReIndex implements backends.Collection interface.
+func (c *collection) ReIndex(ctx context.Context, params *backends.ReIndexParams) (*backends.ReIndexResult, error) {
+	return c.c.ReIndex(ctx, params)
+}
+
+// Search implements backends.Collection interface.
+func (c *collection) Search(ctx context.Context, params *backends.SearchParams) (*backends.SearchResult, error) {
+	return c.c.Search(ctx, params)
+}
+
+// VectorSearch implements backends.Collection interface.
+func (c *collection) VectorSearch(ctx context.Context, params *backends.VectorSearchParams) (*backends.VectorSearchResult, error) { //nolint:lll // for readability
+	return c.c.VectorSearch(ctx, params)
+}
+
+// ListSearchIndexes implements backends.Collection interface.
+func (c *collection) ListSearchIndexes(ctx context.Context, params *backends.ListSearchIndexesParams) (*backends.ListSearchIndexesResult, error) { //nolint:lll // for readability
+	return c.c.ListSearchIndexes(ctx, params)
+}
+
+// CreateSearchIndexes implements backends.Collection interface.
+func (c *collection) CreateSearchIndexes(ctx context.Context, params *backends.CreateSearchIndexesParams) (*backends.CreateSearchIndexesResult, error) { //nolint:lll // for readability
+	return c.c.CreateSearchIndexes(ctx, params)
+}
+
+// DropSearchIndexes implements backends.Collection interface.
+func (c *collection) DropSearchIndexes(ctx context.Context, params *backends.DropSearchIndexesParams) (*backends.DropSearchIndexesResult, error) { //nolint:lll // for readability
+	return c.c.DropSearchIndexes(ctx, params)
+}
+
 // check interfaces
 var (
 	_ backends.Collection = (*collection)(nil)
@@ -0,0 +1,311 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeseries
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// bucketsCollection implements backends.Collection interface for `system.buckets.<collection>` collection.
+//
+// If the time-series collection does not exist, all methods are delegated to the wrapped collection.
+type bucketsCollection struct {
+	db           *database
+	cName        string
+	c            backends.Collection
+	measurements backends.Collection
+}
+
+// newBucketsCollection creates a new bucket collection for the given time-series collection.
+func newBucketsCollection(db *database, cName string, c, measurements backends.Collection) backends.Collection {
+	return &bucketsCollection{
+		db:           db,
+		cName:        cName,
+		c:            c,
+		measurements: measurements,
+	}
+}
+
+// Query implements backends.Collection interface.
+//
+// Buckets are packed from measurements in memory.
+// Only measurements in time windows of buckets that could match the filter's conditions
+// on `control.min.<timeField>` and `control.max.<timeField>` are queried;
+// the filter is not applied otherwise.
+func (c *bucketsCollection) Query(ctx context.Context, params *backends.QueryParams) (*backends.QueryResult, error) {
+	ts, err := c.db.timeSeries(ctx, c.cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if ts == nil {
+		return c.c.Query(ctx, params)
+	}
+
+	if params == nil {
+		params = new(backends.QueryParams)
+	}
+
+	from, to := bucketsTimeRange(params.Filter, ts)
+
+	mp := &backends.QueryParams{Comment: params.Comment}
+
+	if !from.IsZero() || !to.IsZero() {
+		cond := types.MakeDocument(2)
+
+		if !from.IsZero() {
+			cond.Set("$gte", from)
+		}
+
+		if !to.IsZero() {
+			cond.Set("$lt", to)
+		}
+
+		mp.Filter = must.NotFail(types.NewDocument(ts.TimeField, cond))
+	}
+
+	qr, err := c.measurements.Query(ctx, mp)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	docs, err := iterator.ConsumeValues(qr.Iter)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// backend filtering is not exact; other windows would produce incomplete buckets
+	docs = slices.DeleteFunc(docs, func(doc *types.Document) bool {
+		v, _ := doc.Get(ts.TimeField)
+
+		// measurements without dates are skipped by packBuckets anyway
+		t, ok := v.(time.Time)
+		if !ok {
+			return false
+		}
+
+		return (!from.IsZero() && t.Before(from)) || (!to.IsZero() && !t.Before(to))
+	})
+
+	buckets := packBuckets(docs, ts)
+
+	if v, _ := params.Sort.Get("$natural"); v == int64(-1) {
+		slices.Reverse(buckets)
+	}
+
+	if params.Limit > 0 && int64(len(buckets)) > params.Limit {
+		buckets = buckets[:params.Limit]
+	}
+
+	return &backends.QueryResult{
+		Iter: iterator.Values(iterator.ForSlice(buckets)),
+	}, nil
+}
+
+// InsertAll implements backends.Collection interface.
+//
+// Inserted buckets are unpacked into measurements of the time-series collection.
+func (c *bucketsCollection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
+	ts, err := c.db.timeSeries(ctx, c.cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if ts == nil {
+		return c.c.InsertAll(ctx, params)
+	}
+
+	var docs []*types.Document
+
+	for _, bucket := range params.Docs {
+		var measurements []*types.Document
+		if measurements, err = unpackBucket(bucket, ts); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		docs = append(docs, measurements...)
+	}
+
+	if len(docs) == 0 {
+		return new(backends.InsertAllResult), nil
+	}
+
+	return c.measurements.InsertAll(ctx, &backends.InsertAllParams{Docs: docs})
+}
+
+// UpdateAll implements backends.Collection interface.
+func (c *bucketsCollection) UpdateAll(ctx context.Context, params *backends.UpdateAllParams) (*backends.UpdateAllResult, error) {
+	if err := c.checkWritable(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.c.UpdateAll(ctx, params)
+}
+
+// DeleteAll implements backends.Collection interface.
+func (c *bucketsCollection) DeleteAll(ctx context.Context, params *backends.DeleteAllParams) (*backends.DeleteAllResult, error) {
+	if err := c.checkWritable(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.c.DeleteAll(ctx, params)
+}
+
+// Explain implements backends.Collection interface.
+func (c *bucketsCollection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
+	ts, err := c.db.timeSeries(ctx, c.cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if ts == nil {
+		return c.c.Explain(ctx, params)
+	}
+
+	return &backends.ExplainResult{
+		QueryPlanner: must.NotFail(types.NewDocument()),
+	}, nil
+}
+
+// Stats implements backends.Collection interface.
+//
+// Statistics of the time-series collection are returned.
+func (c *bucketsCollection) Stats(ctx context.Context, params *backends.CollectionStatsParams) (*backends.CollectionStatsResult, error) { //nolint:lll // for readability
+	ts, err := c.db.timeSeries(ctx, c.cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if ts == nil {
+		return c.c.Stats(ctx, params)
+	}
+
+	return c.measurements.Stats(ctx, params)
+}
+
+// Compact implements backends.Collection interface.
+func (c *bucketsCollection) Compact(ctx context.Context, params *backends.CompactParams) (*backends.CompactResult, error) {
+	ts, err := c.db.timeSeries(ctx, c.cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if ts == nil {
+		return c.c.Compact(ctx, params)
+	}
+
+	return c.measurements.Compact(ctx, params)
+}
+
+// ListIndexes implements backends.Collection interface.
+//
+// Like in MongoDB, bucket collections don't have indexes.
+func (c *bucketsCollection) ListIndexes(ctx context.Context, params *backends.ListIndexesParams) (*backends.ListIndexesResult, error) { //nolint:lll // for readability
+	ts, err := c.db.timeSeries(ctx, c.cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if ts == nil {
+		return c.c.ListIndexes(ctx, params)
+	}
+
+	return new(backends.ListIndexesResult), nil
+}
+
+// CreateIndexes implements backends.Collection interface.
+func (c *bucketsCollection) CreateIndexes(ctx context.Context, params *backends.CreateIndexesParams) (*backends.CreateIndexesResult, error) { //nolint:lll // for readability
+	if err := c.checkWritable(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.c.CreateIndexes(ctx, params)
+}
+
+// DropIndexes implements backends.Collection interface.
+func (c *bucketsCollection) DropIndexes(ctx context.Context, params *backends.DropIndexesParams) (*backends.DropIndexesResult, error) { //nolint:lll // for readability
+	if err := c.checkWritable(ctx); err != nil {
+		return nil, err
+	}
+
+	return c.c.DropIndexes(ctx, params)
+}
+
+// ReIndex implements backends.Collection interface.
+func (c *bucketsCollection) ReIndex(ctx context.Context, params *backends.ReIndexParams) (*backends.ReIndexResult, error) {
+	ts, err := c.db.timeSeries(ctx, c.cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if ts == nil {
+		return c.c.ReIndex(ctx, params)
+	}
+
+	return c.measurements.ReIndex(ctx, params)
+}
+
+// Search implements backends.Collection interface.
+func (c *bucketsCollection) Search(ctx context.Context, params *backends.SearchParams) (*backends.SearchResult, error) {
+	return c.c.Search(ctx, params)
+}
+
+// VectorSearch implements backends.Collection interface.
+func (c *bucketsCollection) VectorSearch(ctx context.Context, params *backends.VectorSearchParams) (*backends.VectorSearchResult, error) { //nolint:lll // for readability
+	return c.c.VectorSearch(ctx, params)
+}
+
+// ListSearchIndexes implements backends.Collection interface.
+func (c *bucketsCollection) ListSearchIndexes(ctx context.Context, params *backends.ListSearchIndexesParams) (*backends.ListSearchIndexesResult, error) { //nolint:lll // for readability
+	return c.c.ListSearchIndexes(ctx, params)
+}
+
+// CreateSearchIndexes implements backends.Collection interface.
+func (c *bucketsCollection) CreateSearchIndexes(ctx context.Context, params *backends.CreateSearchIndexesParams) (*backends.CreateSearchIndexesResult, error) { //nolint:lll // for readability
+	return c.c.CreateSearchIndexes(ctx, params)
+}
+
+// DropSearchIndexes implements backends.Collection interface.
+func (c *bucketsCollection) DropSearchIndexes(ctx context.Context, params *backends.DropSearchIndexesParams) (*backends.DropSearchIndexesResult, error) { //nolint:lll // for readability
+	return c.c.DropSearchIndexes(ctx, params)
+}
+
+// checkWritable returns an error if the bucket collection of existing time-series collection
+// is being modified other than by inserting buckets.
+func (c *bucketsCollection) checkWritable(ctx context.Context) error {
+	ts, err := c.db.timeSeries(ctx, c.cName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if ts != nil {
+		return lazyerrors.New("bucket collections of time-series collections can only be queried or inserted into")
+	}
+
+	return nil
+}
+
+// check interfaces
+var (
+	_ backends.Collection = (*bucketsCollection)(nil)
+)
@@ -0,0 +1,154 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeseries
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// database implements backends.Database interface by adding bucket collections of time-series collections.
+type database struct {
+	db backends.Database
+}
+
+// newDatabase creates a new Database that wraps the given database.
+func newDatabase(db backends.Database) backends.Database {
+	return &database{db: db}
+}
+
+// timeSeries returns options of the given time-series collection, or nil if that's not a time-series collection.
+func (db *database) timeSeries(ctx context.Context, cName string) (*backends.TimeSeriesOptions, error) {
+	res, err := db.db.ListCollections(ctx, &backends.ListCollectionsParams{Name: cName})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(res.Collections) == 0 {
+		return nil, nil
+	}
+
+	return res.Collections[0].TimeSeries, nil
+}
+
+// Collection implements backends.Database interface.
+func (db *database) Collection(name string) (backends.Collection, error) {
+	c, err := db.db.Collection(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cName, ok := bucketsCollectionName(name)
+	if !ok {
+		return c, nil
+	}
+
+	measurements, err := db.db.Collection(cName)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBucketsCollection(db, cName, c, measurements), nil
+}
+
+// ListCollections implements backends.Database interface.
+//
+// Bucket collections of time-series collections are added to the list.
+//
+//nolint:lll // for readability
+func (db *database) ListCollections(ctx context.Context, params *backends.ListCollectionsParams) (*backends.ListCollectionsResult, error) {
+	if params != nil && params.Name != "" {
+		cName, ok := bucketsCollectionName(params.Name)
+		if !ok {
+			return db.db.ListCollections(ctx, params)
+		}
+
+		ts, err := db.timeSeries(ctx, cName)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if ts == nil {
+			return db.db.ListCollections(ctx, params)
+		}
+
+		return &backends.ListCollectionsResult{
+			Collections: []backends.CollectionInfo{{Name: params.Name}},
+		}, nil
+	}
+
+	res, err := db.db.ListCollections(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var added bool
+
+	for _, c := range res.Collections {
+		if c.TimeSeries != nil {
+			res.Collections = append(res.Collections, backends.CollectionInfo{Name: BucketsPrefix + c.Name})
+			added = true
+		}
+	}
+
+	if added {
+		slices.SortFunc(res.Collections, func(a, b backends.CollectionInfo) int { return cmp.Compare(a.Name, b.Name) })
+	}
+
+	return res, nil
+}
+
+// CreateCollection implements backends.Database interface.
+func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
+	if cName, ok := bucketsCollectionName(params.Name); ok {
+		ts, err := db.timeSeries(ctx, cName)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if ts != nil {
+			return backends.NewError(
+				backends.ErrorCodeCollectionAlreadyExists,
+				lazyerrors.Errorf("bucket collection %q already exists", params.Name),
+			)
+		}
+	}
+
+	return db.db.CreateCollection(ctx, params)
+}
+
+// DropCollection implements backends.Database interface.
+func (db *database) DropCollection(ctx context.Context, params *backends.DropCollectionParams) error {
+	return db.db.DropCollection(ctx, params)
+}
+
+// RenameCollection implements backends.Database interface.
+func (db *database) RenameCollection(ctx context.Context, params *backends.RenameCollectionParams) error {
+	return db.db.RenameCollection(ctx, params)
+}
+
+// Stats implements backends.Database interface.
+func (db *database) Stats(ctx context.Context, params *backends.DatabaseStatsParams) (*backends.DatabaseStatsResult, error) {
+	return db.db.Stats(ctx, params)
+}
+
+// check interfaces
+var (
+	_ backends.Database = (*database)(nil)
+)
@@ -0,0 +1,377 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package timeseries provides decorators that expose measurements of time-series collections
+// as `system.buckets.<collection>` collections of bucket documents.
+//
+// Measurements are stored by backends as regular documents
+// (PostgreSQL adds a BRIN index on the time field; there is no bucketed storage layout).
+// Buckets are built on the fly when `system.buckets` collection is queried,
+// and inserted buckets are unpacked into measurements.
+// Buckets never cross fixed time windows, so queries with conditions on bucket time bounds
+// read only measurements of matching windows.
+// That allows tools like mongodump and mongorestore to handle time-series collections.
+//
+// Only uncompressed (version 1) buckets are supported.
+package timeseries
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// BucketsPrefix is the prefix of bucket collection names.
+const BucketsPrefix = "system.buckets."
+
+// Time-series collection granularities.
+const (
+	GranularitySeconds = "seconds"
+	GranularityMinutes = "minutes"
+	GranularityHours   = "hours"
+)
+
+// maxBucketMeasurements is the maximal number of measurements in a single bucket, the same as in MongoDB.
+const maxBucketMeasurements = 1000
+
+// BucketSpan returns the rounding of bucket start time and the maximal time span of the bucket
+// for the given granularity, the same as in MongoDB.
+func BucketSpan(granularity string) (rounding, maxSpan time.Duration) {
+	switch granularity {
+	case GranularityMinutes:
+		return time.Hour, 24 * time.Hour
+	case GranularityHours:
+		return 24 * time.Hour, 30 * 24 * time.Hour
+	default:
+		return time.Minute, time.Hour
+	}
+}
+
+// bucketsCollectionName returns the name of time-series collection for the given bucket collection name,
+// or false if it is not a bucket collection name.
+func bucketsCollectionName(name string) (string, bool) {
+	cName, ok := strings.CutPrefix(name, BucketsPrefix)
+	return cName, ok && cName != ""
+}
+
+// packBuckets groups measurements of the time-series collection into bucket documents.
+//
+// Measurements are grouped by the meta field value and then by time windows of the maximal bucket span.
+// Measurements without a date in the time field are skipped.
+func packBuckets(docs []*types.Document, ts *backends.TimeSeriesOptions) []*types.Document {
+	type measurement struct {
+		doc     *types.Document
+		t       time.Time
+		meta    any
+		hasMeta bool
+	}
+
+	ms := make([]measurement, 0, len(docs))
+
+	for _, doc := range docs {
+		v, _ := doc.Get(ts.TimeField)
+
+		t, ok := v.(time.Time)
+		if !ok {
+			continue
+		}
+
+		m := measurement{doc: doc, t: t}
+
+		if ts.MetaField != "" {
+			var err error
+			m.meta, err = doc.Get(ts.MetaField)
+			m.hasMeta = err == nil
+		}
+
+		ms = append(ms, m)
+	}
+
+	sort.SliceStable(ms, func(i, j int) bool {
+		a, b := ms[i], ms[j]
+
+		if a.hasMeta != b.hasMeta {
+			return !a.hasMeta
+		}
+
+		if a.hasMeta {
+			switch types.CompareOrder(a.meta, b.meta, types.Ascending) {
+			case types.Less:
+				return true
+			case types.Greater:
+				return false
+			}
+		}
+
+		return a.t.Before(b.t)
+	})
+
+	rounding, maxSpan := BucketSpan(ts.Granularity)
+
+	var res []*types.Document
+	var bucket []measurement
+	var start, windowEnd time.Time
+	var seq int
+
+	flush := func() {
+		if len(bucket) == 0 {
+			return
+		}
+
+		docs := make([]*types.Document, len(bucket))
+		for i, m := range bucket {
+			docs[i] = m.doc
+		}
+
+		var meta any
+		if bucket[0].hasMeta {
+			meta = bucket[0].meta
+		}
+
+		res = append(res, packBucket(docs, ts, start, meta, seq))
+		bucket = bucket[:0]
+	}
+
+	for _, m := range ms {
+		if len(bucket) > 0 {
+			prev := bucket[0]
+
+			sameMeta := prev.hasMeta == m.hasMeta &&
+				(!m.hasMeta || types.CompareOrder(prev.meta, m.meta, types.Ascending) == types.Equal)
+
+			switch {
+			case !sameMeta || !m.t.Before(windowEnd):
+				flush()
+				seq = 0
+			case len(bucket) == maxBucketMeasurements:
+				flush()
+				seq++
+			}
+		}
+
+		if len(bucket) == 0 {
+			start = m.t.Truncate(rounding)
+			windowEnd = bucketWindow(m.t, maxSpan).Add(maxSpan)
+		}
+
+		bucket = append(bucket, m)
+	}
+
+	flush()
+
+	return res
+}
+
+// bucketWindow returns the start of the time window that contains the given time.
+//
+// Buckets never cross window boundaries, so buckets packed from measurements of some windows
+// are the same as buckets packed from all measurements.
+func bucketWindow(t time.Time, maxSpan time.Duration) time.Time {
+	return t.Truncate(maxSpan)
+}
+
+// bucketsTimeRange returns the time range [from, to) of measurements that are enough to pack all buckets
+// that match conditions of the given filter on `control.min.<timeField>` and `control.max.<timeField>`.
+// Zero values mean no bound.
+func bucketsTimeRange(filter *types.Document, ts *backends.TimeSeriesOptions) (from, to time.Time) {
+	_, maxSpan := BucketSpan(ts.Granularity)
+
+	for _, k := range []string{"control.min." + ts.TimeField, "control.max." + ts.TimeField} {
+		v, _ := filter.Get(k)
+		if v == nil {
+			continue
+		}
+
+		ops, ok := v.(*types.Document)
+		if !ok {
+			ops = must.NotFail(types.NewDocument("$eq", v))
+		}
+
+		for _, op := range ops.Keys() {
+			t, ok := must.NotFail(ops.Get(op)).(time.Time)
+			if !ok {
+				continue
+			}
+
+			// all measurements of the bucket are in the same window
+			window := bucketWindow(t, maxSpan)
+
+			switch op {
+			case "$eq", "$gt", "$gte":
+				if from.IsZero() || window.After(from) {
+					from = window
+				}
+			}
+
+			switch op {
+			case "$eq", "$lt", "$lte":
+				if end := window.Add(maxSpan); to.IsZero() || end.Before(to) {
+					to = end
+				}
+			}
+		}
+	}
+
+	return from, to
+}
+
+// packBucket returns a bucket document for the given measurements.
+//
+// Meta is the meta field value shared by all measurements, or nil if they don't have it.
+// Seq is the number of the bucket with the same meta value in the same time window;
+// together with start time and meta value, it is used to generate a stable bucket _id.
+func packBucket(docs []*types.Document, ts *backends.TimeSeriesOptions, start time.Time, meta any, seq int) *types.Document {
+	data := types.MakeDocument(0)
+	minDoc := types.MakeDocument(0)
+	maxDoc := types.MakeDocument(0)
+
+	for i, doc := range docs {
+		key := strconv.Itoa(i)
+
+		for _, k := range doc.Keys() {
+			if ts.MetaField != "" && k == ts.MetaField {
+				continue
+			}
+
+			v := must.NotFail(doc.Get(k))
+
+			column, _ := data.Get(k)
+			if column == nil {
+				column = types.MakeDocument(0)
+				data.Set(k, column)
+			}
+
+			column.(*types.Document).Set(key, v)
+
+			if cur, _ := minDoc.Get(k); cur == nil || types.CompareOrder(v, cur, types.Ascending) == types.Less {
+				minDoc.Set(k, v)
+			}
+
+			if cur, _ := maxDoc.Get(k); cur == nil || types.CompareOrder(v, cur, types.Ascending) == types.Greater {
+				maxDoc.Set(k, v)
+			}
+		}
+	}
+
+	// like in MongoDB, the minimal time is the bucket start time
+	minDoc.Set(ts.TimeField, start)
+
+	h := fnv.New32a()
+	if meta != nil {
+		must.NotFail(h.Write([]byte(types.FormatAnyValue(meta))))
+	}
+
+	var id types.ObjectID
+	binary.BigEndian.PutUint32(id[:4], uint32(start.Unix()))
+	binary.BigEndian.PutUint32(id[4:8], h.Sum32())
+	binary.BigEndian.PutUint32(id[8:], uint32(seq))
+
+	res := must.NotFail(types.NewDocument(
+		"_id", id,
+		"control", must.NotFail(types.NewDocument(
+			"version", int32(1),
+			"min", minDoc,
+			"max", maxDoc,
+		)),
+	))
+
+	if meta != nil {
+		res.Set("meta", meta)
+	}
+
+	res.Set("data", data)
+
+	return res
+}
+
+// unpackBucket returns measurements stored in the given bucket document.
+func unpackBucket(bucket *types.Document, ts *backends.TimeSeriesOptions) ([]*types.Document, error) {
+	v, _ := bucket.GetByPath(types.NewStaticPath("control", "version"))
+	if v != int32(1) {
+		return nil, lazyerrors.Errorf("unsupported time-series bucket version %s", types.FormatAnyValue(v))
+	}
+
+	v, _ = bucket.Get("data")
+
+	data, ok := v.(*types.Document)
+	if !ok {
+		return nil, lazyerrors.New("time-series bucket 'data' field must be an object")
+	}
+
+	rows := map[int]*types.Document{}
+
+	for _, k := range data.Keys() {
+		column, ok := must.NotFail(data.Get(k)).(*types.Document)
+		if !ok {
+			return nil, lazyerrors.Errorf("time-series bucket 'data.%s' field must be an object", k)
+		}
+
+		for _, key := range column.Keys() {
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 {
+				return nil, lazyerrors.Errorf("invalid time-series bucket measurement index %q", key)
+			}
+
+			row := rows[i]
+			if row == nil {
+				row = types.MakeDocument(0)
+				rows[i] = row
+			}
+
+			row.Set(k, must.NotFail(column.Get(key)))
+		}
+	}
+
+	indexes := make([]int, 0, len(rows))
+	for i := range rows {
+		indexes = append(indexes, i)
+	}
+
+	sort.Ints(indexes)
+
+	meta, metaErr := bucket.Get("meta")
+
+	res := make([]*types.Document, len(indexes))
+
+	for i, index := range indexes {
+		doc := rows[index]
+
+		if metaErr == nil && ts.MetaField != "" {
+			doc.Set(ts.MetaField, meta)
+		}
+
+		if !doc.Has("_id") {
+			doc.Set("_id", types.NewObjectID())
+		}
+
+		// _id is always the first field
+		res[i] = must.NotFail(types.NewDocument("_id", must.NotFail(doc.Get("_id"))))
+
+		for _, k := range doc.Keys() {
+			if k != "_id" {
+				res[i].Set(k, must.NotFail(doc.Get(k)))
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package timeseries
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestBuckets(t *testing.T) {
+	t.Parallel()
+
+	ts := &backends.TimeSeriesOptions{
+		TimeField:   "t",
+		MetaField:   "m",
+		Granularity: GranularitySeconds,
+	}
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	docs := []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "t", start.Add(30*time.Second), "v", 1.5, "m", "a")),
+		must.NotFail(types.NewDocument("_id", int32(2), "t", start.Add(90*time.Minute), "m", "a", "v", 2.5)),
+		must.NotFail(types.NewDocument("_id", int32(3), "t", start.Add(10*time.Second), "m", "b")),
+		must.NotFail(types.NewDocument("_id", int32(4), "t", start.Add(20*time.Second), "v", 0.5, "m", "a")),
+		must.NotFail(types.NewDocument("_id", int32(5), "t", "not a date", "m", "a")),
+	}
+
+	buckets := packBuckets(docs, ts)
+	require.Len(t, buckets, 3)
+
+	expected := must.NotFail(types.NewDocument(
+		"_id", must.NotFail(buckets[0].Get("_id")),
+		"control", must.NotFail(types.NewDocument(
+			"version", int32(1),
+			"min", must.NotFail(types.NewDocument("_id", int32(1), "t", start, "v", 0.5)),
+			"max", must.NotFail(types.NewDocument("_id", int32(4), "t", start.Add(30*time.Second), "v", 1.5)),
+		)),
+		"meta", "a",
+		"data", must.NotFail(types.NewDocument(
+			"_id", must.NotFail(types.NewDocument("0", int32(4), "1", int32(1))),
+			"t", must.NotFail(types.NewDocument("0", start.Add(20*time.Second), "1", start.Add(30*time.Second))),
+			"v", must.NotFail(types.NewDocument("0", 0.5, "1", 1.5)),
+		)),
+	))
+	testutil.AssertEqual(t, expected, buckets[0])
+
+	assert.Equal(t, "b", must.NotFail(buckets[2].Get("meta")))
+
+	measurements, err := unpackBucket(buckets[0], ts)
+	require.NoError(t, err)
+	require.Len(t, measurements, 2)
+	testutil.AssertEqual(t, docs[3], measurements[0])
+	testutil.AssertEqual(t, docs[0], measurements[1])
+
+	buckets[1].Set("control", must.NotFail(types.NewDocument("version", int32(2))))
+	_, err = unpackBucket(buckets[1], ts)
+	require.Error(t, err)
+}
+
+func TestBucketsTimeRange(t *testing.T) {
+	t.Parallel()
+
+	ts := &backends.TimeSeriesOptions{
+		TimeField:   "t",
+		MetaField:   "m",
+		Granularity: GranularitySeconds,
+	}
+
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	var docs []*types.Document
+	for i := range 15000 {
+		docs = append(docs, must.NotFail(types.NewDocument(
+			"_id", int32(i),
+			"t", start.Add(time.Duration(i)*time.Second),
+			"m", int32(i%2),
+		)))
+	}
+
+	all := packBuckets(docs, ts)
+
+	for name, tc := range map[string]struct {
+		filter   *types.Document
+		from, to time.Time
+	}{
+		"None": {
+			filter: new(types.Document),
+		},
+		"MinGte": {
+			filter: must.NotFail(types.NewDocument(
+				"control.min.t", must.NotFail(types.NewDocument("$gte", start.Add(90*time.Minute))),
+			)),
+			from: start.Add(time.Hour),
+		},
+		"MaxLt": {
+			filter: must.NotFail(types.NewDocument(
+				"control.max.t", must.NotFail(types.NewDocument("$lt", start.Add(150*time.Minute))),
+			)),
+			to: start.Add(3 * time.Hour),
+		},
+		"Both": {
+			filter: must.NotFail(types.NewDocument(
+				"control.max.t", must.NotFail(types.NewDocument("$gt", start.Add(70*time.Minute))),
+				"control.min.t", must.NotFail(types.NewDocument("$lte", start.Add(100*time.Minute))),
+			)),
+			from: start.Add(time.Hour),
+			to:   start.Add(2 * time.Hour),
+		},
+		"Eq": {
+			filter: must.NotFail(types.NewDocument("control.min.t", start.Add(3*time.Hour))),
+			from:   start.Add(3 * time.Hour),
+			to:     start.Add(4 * time.Hour),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			from, to := bucketsTimeRange(tc.filter, ts)
+			assert.Equal(t, tc.from, from)
+			assert.Equal(t, tc.to, to)
+
+			var subset []*types.Document
+
+			for _, doc := range docs {
+				dt := must.NotFail(doc.Get("t")).(time.Time)
+				if (from.IsZero() || !dt.Before(from)) && (to.IsZero() || dt.Before(to)) {
+					subset = append(subset, doc)
+				}
+			}
+
+			// buckets packed from the subset are the same as buckets packed from all measurements
+			var expected []*types.Document
+
+			for _, b := range all {
+				bt := must.NotFail(b.GetByPath(types.NewStaticPath("control", "min", "t"))).(time.Time)
+				if (from.IsZero() || !bt.Before(from)) && (to.IsZero() || bt.Before(to)) {
+					expected = append(expected, b)
+				}
+			}
+
+			actual := packBuckets(subset, ts)
+			require.Len(t, actual, len(expected))
+
+			for i := range expected {
+				testutil.AssertEqual(t, expected[i], actual[i])
+			}
+		})
+	}
+}
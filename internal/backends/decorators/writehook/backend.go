@@ -0,0 +1,97 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package writehook provides decorators that call the given function after writes.
+//
+// It could be used to invalidate caches of data stored in the backend.
+package writehook
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+)
+
+// Hook is called after every write to the given collection, successful or not.
+// Collection is empty if the whole database was dropped.
+//
+// It should be fast and should not call the backend.
+type Hook func(db, collection string)
+
+// backend implements backends.Backend interface by delegating all methods to the wrapped backend.
+type backend struct {
+	b    backends.Backend
+	hook Hook
+}
+
+// NewBackend creates a new Backend that wraps the given backend and calls the hook after writes.
+func NewBackend(b backends.Backend, hook Hook) backends.Backend {
+	return &backend{b: b, hook: hook}
+}
+
+// Close implements backends.Backend interface.
+func (b *backend) Close() {
+	b.b.Close()
+}
+
+// Status implements backends.Backend interface.
+func (b *backend) Status(ctx context.Context, params *backends.StatusParams) (*backends.StatusResult, error) {
+	return b.b.Status(ctx, params)
+}
+
+// Database implements backends.Backend interface.
+func (b *backend) Database(name string) (backends.Database, error) {
+	db, err := b.b.Database(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDatabase(db, name, b.hook), nil
+}
+
+// ListDatabases implements backends.Backend interface.
+//
+//nolint:lll // for readability
+func (b *backend) ListDatabases(ctx context.Context, params *backends.ListDatabasesParams) (*backends.ListDatabasesResult, error) {
+	return b.b.ListDatabases(ctx, params)
+}
+
+// DropDatabase implements backends.Backend interface.
+func (b *backend) DropDatabase(ctx context.Context, params *backends.DropDatabaseParams) error {
+	defer b.hook(params.Name, "")
+
+	return b.b.DropDatabase(ctx, params)
+}
+
+// Checkpoint implements backends.Backend interface.
+func (b *backend) Checkpoint(ctx context.Context, params *backends.CheckpointParams) error {
+	return b.b.Checkpoint(ctx, params)
+}
+
+// Describe implements prometheus.Collector.
+func (b *backend) Describe(ch chan<- *prometheus.Desc) {
+	b.b.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (b *backend) Collect(ch chan<- prometheus.Metric) {
+	b.b.Collect(ch)
+}
+
+// check interfaces
+var (
+	_ backends.Backend = (*backend)(nil)
+)
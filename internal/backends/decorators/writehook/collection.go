@@ -0,0 +1,125 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writehook
+
+import (
+	"context"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+)
+
+// collection implements backends.Collection interface by delegating all methods to the wrapped collection.
+type collection struct {
+	c    backends.Collection
+	db   string
+	name string
+	hook Hook
+}
+
+// newCollection creates a new Collection that wraps the given collection.
+func newCollection(c backends.Collection, db, name string, hook Hook) backends.Collection {
+	return &collection{c: c, db: db, name: name, hook: hook}
+}
+
+// Query implements backends.Collection interface.
+func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*backends.QueryResult, error) {
+	return c.c.Query(ctx, params)
+}
+
+// InsertAll implements backends.Collection interface.
+func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
+	defer c.hook(c.db, c.name)
+
+	return c.c.InsertAll(ctx, params)
+}
+
+// UpdateAll implements backends.Collection interface.
+func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllParams) (*backends.UpdateAllResult, error) {
+	defer c.hook(c.db, c.name)
+
+	return c.c.UpdateAll(ctx, params)
+}
+
+// DeleteAll implements backends.Collection interface.
+func (c *collection) DeleteAll(ctx context.Context, params *backends.DeleteAllParams) (*backends.DeleteAllResult, error) {
+	defer c.hook(c.db, c.name)
+
+	return c.c.DeleteAll(ctx, params)
+}
+
+// Explain implements backends.Collection interface.
+func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams) (*backends.ExplainResult, error) {
+	return c.c.Explain(ctx, params)
+}
+
+// Stats implements backends.Collection interface.
+func (c *collection) Stats(ctx context.Context, params *backends.CollectionStatsParams) (*backends.CollectionStatsResult, error) {
+	return c.c.Stats(ctx, params)
+}
+
+// Compact implements backends.Collection interface.
+func (c *collection) Compact(ctx context.Context, params *backends.CompactParams) (*backends.CompactResult, error) {
+	return c.c.Compact(ctx, params)
+}
+
+// ListIndexes implements backends.Collection interface.
+func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndexesParams) (*backends.ListIndexesResult, error) {
+	return c.c.ListIndexes(ctx, params)
+}
+
+// CreateIndexes implements backends.Collection interface.
+func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateIndexesParams) (*backends.CreateIndexesResult, error) { //nolint:lll // for readability
+	return c.c.CreateIndexes(ctx, params)
+}
+
+// DropIndexes implements backends.Collection interface.
+func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndexesParams) (*backends.DropIndexesResult, error) {
+	return c.c.DropIndexes(ctx, params)
+}
+
+// ReIndex implements backends.Collection interface.
+func (c *collection) ReIndex(ctx context.Context, params *backends.ReIndexParams) (*backends.ReIndexResult, error) {
+	return c.c.ReIndex(ctx, params)
+}
+
+// Search implements backends.Collection interface.
+func (c *collection) Search(ctx context.Context, params *backends.SearchParams) (*backends.SearchResult, error) {
+	return c.c.Search(ctx, params)
+}
+
+// VectorSearch implements backends.Collection interface.
+func (c *collection) VectorSearch(ctx context.Context, params *backends.VectorSearchParams) (*backends.VectorSearchResult, error) { //nolint:lll // for readability
+	return c.c.VectorSearch(ctx, params)
+}
+
+// ListSearchIndexes implements backends.Collection interface.
+func (c *collection) ListSearchIndexes(ctx context.Context, params *backends.ListSearchIndexesParams) (*backends.ListSearchIndexesResult, error) { //nolint:lll // for readability
+	return c.c.ListSearchIndexes(ctx, params)
+}
+
+// CreateSearchIndexes implements backends.Collection interface.
+func (c *collection) CreateSearchIndexes(ctx context.Context, params *backends.CreateSearchIndexesParams) (*backends.CreateSearchIndexesResult, error) { //nolint:lll // for readability
+	return c.c.CreateSearchIndexes(ctx, params)
+}
+
+// DropSearchIndexes implements backends.Collection interface.
+func (c *collection) DropSearchIndexes(ctx context.Context, params *backends.DropSearchIndexesParams) (*backends.DropSearchIndexesResult, error) { //nolint:lll // for readability
+	return c.c.DropSearchIndexes(ctx, params)
+}
+
+// check interfaces
+var (
+	_ backends.Collection = (*collection)(nil)
+)
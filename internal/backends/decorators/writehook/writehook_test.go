@@ -0,0 +1,115 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writehook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+// fakeBackend is a backend where all writes fail; unimplemented methods panic.
+type fakeBackend struct {
+	backends.Backend
+}
+
+var errWrite = errors.New("write failed")
+
+func (fb *fakeBackend) Database(string) (backends.Database, error) {
+	return new(fakeDatabase), nil
+}
+
+func (fb *fakeBackend) DropDatabase(context.Context, *backends.DropDatabaseParams) error {
+	return errWrite
+}
+
+// fakeDatabase is a database where all writes fail; unimplemented methods panic.
+type fakeDatabase struct {
+	backends.Database
+}
+
+func (fdb *fakeDatabase) Collection(string) (backends.Collection, error) {
+	return new(fakeCollection), nil
+}
+
+func (fdb *fakeDatabase) DropCollection(context.Context, *backends.DropCollectionParams) error {
+	return errWrite
+}
+
+func (fdb *fakeDatabase) RenameCollection(context.Context, *backends.RenameCollectionParams) error {
+	return errWrite
+}
+
+// fakeCollection is a collection where all writes fail; unimplemented methods panic.
+type fakeCollection struct {
+	backends.Collection
+}
+
+func (fc *fakeCollection) InsertAll(context.Context, *backends.InsertAllParams) (*backends.InsertAllResult, error) {
+	return nil, errWrite
+}
+
+func (fc *fakeCollection) UpdateAll(context.Context, *backends.UpdateAllParams) (*backends.UpdateAllResult, error) {
+	return nil, errWrite
+}
+
+func (fc *fakeCollection) DeleteAll(context.Context, *backends.DeleteAllParams) (*backends.DeleteAllResult, error) {
+	return nil, errWrite
+}
+
+func TestWriteHook(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	var calls []string
+
+	b := NewBackend(new(fakeBackend), func(db, collection string) {
+		calls = append(calls, db+"."+collection)
+	})
+
+	db, err := b.Database("db")
+	require.NoError(t, err)
+
+	c, err := db.Collection("c")
+	require.NoError(t, err)
+
+	_, err = c.InsertAll(ctx, new(backends.InsertAllParams))
+	assert.ErrorIs(t, err, errWrite)
+
+	_, err = c.UpdateAll(ctx, new(backends.UpdateAllParams))
+	assert.ErrorIs(t, err, errWrite)
+
+	_, err = c.DeleteAll(ctx, new(backends.DeleteAllParams))
+	assert.ErrorIs(t, err, errWrite)
+
+	err = db.RenameCollection(ctx, &backends.RenameCollectionParams{OldName: "c", NewName: "d"})
+	assert.ErrorIs(t, err, errWrite)
+
+	err = db.DropCollection(ctx, &backends.DropCollectionParams{Name: "d"})
+	assert.ErrorIs(t, err, errWrite)
+
+	err = b.DropDatabase(ctx, &backends.DropDatabaseParams{Name: "db"})
+	assert.ErrorIs(t, err, errWrite)
+
+	expected := []string{"db.c", "db.c", "db.c", "db.c", "db.d", "db.d", "db."}
+	assert.Equal(t, expected, calls)
+}
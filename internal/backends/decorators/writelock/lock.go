@@ -0,0 +1,156 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package writelock provides decorators that block writes while the lock is held.
+//
+// It is used to implement `fsync` command with `lock: true`.
+package writelock
+
+import (
+	"context"
+	"sync"
+)
+
+// Lock blocks writes made via wrapped backends while it is held.
+//
+// It is reentrant: it should be unlocked as many times as it was locked.
+// Unlike sync.RWMutex, waiting writes could be canceled.
+//
+//nolint:vet // for readability
+type Lock struct {
+	mu sync.Mutex
+
+	// count is the number of times the lock is held
+	count int
+
+	// writes is the number of in-progress writes
+	writes int
+
+	// unlocked is closed when count drops to zero
+	unlocked chan struct{}
+
+	// drained is closed when writes drop to zero while the lock is held;
+	// nil if nobody waits for that
+	drained chan struct{}
+}
+
+// NewLock creates a new unlocked Lock.
+func NewLock() *Lock {
+	return new(Lock)
+}
+
+// Lock acquires the lock and returns the new lock count.
+//
+// New writes are blocked immediately;
+// Lock waits for in-progress writes to finish or for ctx cancellation.
+// In the latter case, the lock is released.
+func (l *Lock) Lock(ctx context.Context) (int, error) {
+	l.mu.Lock()
+
+	l.count++
+	count := l.count
+
+	if count == 1 {
+		l.unlocked = make(chan struct{})
+	}
+
+	if l.writes == 0 {
+		l.mu.Unlock()
+		return count, nil
+	}
+
+	if l.drained == nil {
+		l.drained = make(chan struct{})
+	}
+
+	drained := l.drained
+
+	l.mu.Unlock()
+
+	select {
+	case <-drained:
+		return count, nil
+	case <-ctx.Done():
+		l.Unlock()
+		return 0, context.Cause(ctx)
+	}
+}
+
+// Unlock releases the lock once and returns the remaining lock count.
+//
+// It returns false if the lock was not held.
+func (l *Lock) Unlock() (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.count == 0 {
+		return 0, false
+	}
+
+	l.count--
+
+	if l.count == 0 {
+		close(l.unlocked)
+		l.unlocked = nil
+	}
+
+	return l.count, true
+}
+
+// Count returns the number of times the lock is held.
+func (l *Lock) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.count
+}
+
+// startWrite waits until the lock is released or ctx is canceled, then registers a new write.
+//
+// If it returns nil, endWrite should be called after the write.
+func (l *Lock) startWrite(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+
+		if l.count == 0 {
+			l.writes++
+			l.mu.Unlock()
+
+			return nil
+		}
+
+		unlocked := l.unlocked
+
+		l.mu.Unlock()
+
+		select {
+		case <-unlocked:
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+}
+
+// endWrite unregisters finished write.
+func (l *Lock) endWrite() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.writes--
+
+	if l.writes == 0 && l.drained != nil {
+		close(l.drained)
+		l.drained = nil
+	}
+}
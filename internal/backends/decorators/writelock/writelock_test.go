@@ -0,0 +1,169 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writelock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+// fakeBackend is a backend that returns fakeDatabase; unimplemented methods panic.
+type fakeBackend struct {
+	backends.Backend
+}
+
+func (fb *fakeBackend) Database(string) (backends.Database, error) {
+	return new(fakeDatabase), nil
+}
+
+// fakeDatabase is a database that returns fakeCollection; unimplemented methods panic.
+type fakeDatabase struct {
+	backends.Database
+}
+
+func (fdb *fakeDatabase) Collection(name string) (backends.Collection, error) {
+	return &fakeCollection{wait: name == "wait"}, nil
+}
+
+// fakeCollection is a collection where inserts succeed; unimplemented methods panic.
+//
+// If wait is true, inserts wait for the context cancellation first.
+type fakeCollection struct {
+	backends.Collection
+	wait bool
+}
+
+func (fc *fakeCollection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
+	if fc.wait {
+		<-ctx.Done()
+	}
+
+	return new(backends.InsertAllResult), nil
+}
+
+func TestLock(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	l := NewLock()
+
+	db, err := NewBackend(new(fakeBackend), l).Database("db")
+	require.NoError(t, err)
+
+	c, err := db.Collection("c")
+	require.NoError(t, err)
+
+	_, err = c.InsertAll(ctx, new(backends.InsertAllParams))
+	require.NoError(t, err)
+
+	t.Run("Blocked", func(t *testing.T) {
+		count, err := l.Lock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		count, err = l.Lock(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		_, err = c.InsertAll(timeoutCtx, new(backends.InsertAllParams))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			_, err := c.InsertAll(ctx, new(backends.InsertAllParams))
+			assert.NoError(t, err)
+		}()
+
+		count, ok := l.Unlock()
+		assert.True(t, ok)
+		assert.Equal(t, 1, count)
+
+		select {
+		case <-done:
+			t.Fatal("write was not blocked")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		count, ok = l.Unlock()
+		assert.True(t, ok)
+		assert.Equal(t, 0, count)
+
+		<-done
+
+		_, ok = l.Unlock()
+		assert.False(t, ok)
+	})
+
+	t.Run("InProgress", func(t *testing.T) {
+		wc, err := db.Collection("wait")
+		require.NoError(t, err)
+
+		writeCtx, cancelWrite := context.WithCancel(ctx)
+
+		done := make(chan struct{})
+
+		go func() {
+			defer close(done)
+
+			_, err := wc.InsertAll(writeCtx, new(backends.InsertAllParams))
+			assert.NoError(t, err)
+		}()
+
+		require.Eventually(t, func() bool {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			return l.writes == 1
+		}, time.Second, time.Millisecond)
+
+		lockCtx, cancelLock := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancelLock()
+
+		_, err = l.Lock(lockCtx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 0, l.Count())
+
+		locked := make(chan struct{})
+
+		go func() {
+			defer close(locked)
+
+			count, err := l.Lock(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, count)
+		}()
+
+		cancelWrite()
+		<-done
+		<-locked
+
+		_, ok := l.Unlock()
+		assert.True(t, ok)
+	})
+}
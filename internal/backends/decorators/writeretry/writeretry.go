@@ -0,0 +1,194 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package writeretry provides decorators that hold writes while the backend is unavailable
+// and retry them when it becomes available again.
+//
+// It is intended to smooth over short backend outages, like PostgreSQL primary switchovers,
+// for clients that tolerate increased write latency.
+// The backend is considered unavailable when a write fails and a subsequent status check also fails.
+//
+// The number of held writes and the time they are held for are bounded.
+// Writes that could not be held, or that were not completed in time, fail with the last error.
+//
+// Backends execute each write in a transaction, so a failed write is not partially applied.
+// In the rare case of the connection loss during commit, a retried insert may fail with a duplicate key error.
+package writeretry
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
+)
+
+// Parts of Prometheus metric names.
+const (
+	namespace = "ferretdb"
+	subsystem = "write_retry"
+)
+
+// maxRetryDelay is the maximum delay between retries.
+const maxRetryDelay = time.Second
+
+// Opts represents decorator options.
+type Opts struct {
+	L *zap.Logger
+
+	// Writes are held for up to that duration before failing. Must be positive.
+	Timeout time.Duration
+
+	// The maximum number of writes held at the same time.
+	// Writes above that limit fail immediately. Must be positive.
+	MaxWrites int
+}
+
+// retrier holds and retries writes for all decorated objects.
+type retrier struct {
+	b       backends.Backend // wrapped backend, used for status checks
+	l       *zap.Logger
+	timeout time.Duration
+	slots   chan struct{}
+
+	held    prometheus.Gauge
+	results *prometheus.CounterVec
+}
+
+// newRetrier creates a new retrier.
+func newRetrier(b backends.Backend, opts *Opts) *retrier {
+	if opts.Timeout <= 0 {
+		panic("writeretry: timeout must be positive")
+	}
+
+	if opts.MaxWrites <= 0 {
+		panic("writeretry: max writes must be positive")
+	}
+
+	return &retrier{
+		b:       b,
+		l:       opts.L,
+		timeout: opts.Timeout,
+		slots:   make(chan struct{}, opts.MaxWrites),
+		held: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "held",
+				Help:      "Current number of writes held while the backend is unavailable.",
+			},
+		),
+		results: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "results_total",
+				Help:      "Total number of writes that failed because the backend was unavailable, by final result.",
+			},
+			[]string{"result"},
+		),
+	}
+}
+
+// unavailable returns true if the given write error is caused by the backend being unavailable.
+func (r *retrier) unavailable(ctx context.Context, err error) bool {
+	// backend errors are returned for problems with the request itself, not for outages
+	if _, ok := err.(*backends.Error); ok { //nolint:errorlint // do not inspect error chain
+		return false
+	}
+
+	// the write was canceled by the client or by maxTimeMS
+	if ctx.Err() != nil {
+		return false
+	}
+
+	_, statusErr := r.b.Status(ctx, nil)
+
+	return statusErr != nil
+}
+
+// do calls f, holding and retrying it while the backend is unavailable.
+func do[T any](ctx context.Context, r *retrier, f func(context.Context) (T, error)) (T, error) {
+	res, err := f(ctx)
+	if err == nil || !r.unavailable(ctx, err) {
+		return res, err
+	}
+
+	select {
+	case r.slots <- struct{}{}:
+	default:
+		r.l.Warn("Backend is unavailable and write retry buffer is full, failing write.", zap.Error(err))
+		r.results.WithLabelValues("full").Inc()
+
+		return res, err
+	}
+
+	defer func() { <-r.slots }()
+
+	r.held.Inc()
+	defer r.held.Dec()
+
+	r.l.Warn("Backend is unavailable, holding write.", zap.Error(err))
+
+	holdCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	for attempt := int64(1); ; attempt++ {
+		ctxutil.SleepWithJitter(holdCtx, maxRetryDelay, attempt)
+
+		if holdCtx.Err() != nil {
+			r.l.Warn("Backend is still unavailable, failing held write.", zap.Int64("attempts", attempt), zap.Error(err))
+			r.results.WithLabelValues("timeout").Inc()
+
+			return res, err
+		}
+
+		if _, statusErr := r.b.Status(holdCtx, nil); statusErr != nil {
+			continue
+		}
+
+		res, err = f(holdCtx)
+		if err == nil {
+			r.l.Info("Held write completed.", zap.Int64("attempts", attempt))
+			r.results.WithLabelValues("ok").Inc()
+
+			return res, nil
+		}
+
+		if !r.unavailable(holdCtx, err) {
+			r.results.WithLabelValues("error").Inc()
+			return res, err
+		}
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (r *retrier) Describe(ch chan<- *prometheus.Desc) {
+	r.held.Describe(ch)
+	r.results.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (r *retrier) Collect(ch chan<- prometheus.Metric) {
+	r.held.Collect(ch)
+	r.results.Collect(ch)
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*retrier)(nil)
+)
@@ -0,0 +1,170 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writeretry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+// fake stores the state of a fake backend that could be made unavailable.
+type fake struct {
+	down    atomic.Int32 // number of remaining failing calls
+	inserts atomic.Int32
+}
+
+var errDown = errors.New("backend is down")
+
+// fail returns true if the current call should fail.
+func (f *fake) fail() bool {
+	for {
+		n := f.down.Load()
+		if n <= 0 {
+			return false
+		}
+
+		if f.down.CompareAndSwap(n, n-1) {
+			return true
+		}
+	}
+}
+
+// fakeBackend is a backend with a single database; unimplemented methods panic.
+type fakeBackend struct {
+	backends.Backend
+	f *fake
+}
+
+func (fb *fakeBackend) Status(context.Context, *backends.StatusParams) (*backends.StatusResult, error) {
+	if fb.f.fail() {
+		return nil, errDown
+	}
+
+	return new(backends.StatusResult), nil
+}
+
+func (fb *fakeBackend) Database(string) (backends.Database, error) {
+	return &fakeDatabase{f: fb.f}, nil
+}
+
+// fakeDatabase is a database with a single collection; unimplemented methods panic.
+type fakeDatabase struct {
+	backends.Database
+	f *fake
+}
+
+func (fdb *fakeDatabase) Collection(string) (backends.Collection, error) {
+	return &fakeCollection{f: fdb.f}, nil
+}
+
+// fakeCollection is a collection that supports only inserts; unimplemented methods panic.
+type fakeCollection struct {
+	backends.Collection
+	f *fake
+}
+
+func (fc *fakeCollection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) {
+	if fc.f.fail() {
+		return nil, errDown
+	}
+
+	if len(params.Docs) == 0 {
+		return nil, backends.NewError(backends.ErrorCodeInsertDuplicateID, nil)
+	}
+
+	fc.f.inserts.Add(1)
+
+	return new(backends.InsertAllResult), nil
+}
+
+func TestWriteRetry(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, timeout time.Duration, down int32) (*fake, backends.Collection) {
+		t.Helper()
+
+		f := new(fake)
+		f.down.Store(down)
+
+		b := NewBackend(&fakeBackend{f: f}, &Opts{
+			L:         testutil.Logger(t),
+			Timeout:   timeout,
+			MaxWrites: 1,
+		})
+
+		db, err := b.Database("db")
+		require.NoError(t, err)
+
+		c, err := db.Collection("c")
+		require.NoError(t, err)
+
+		return f, c
+	}
+
+	t.Run("Recovered", func(t *testing.T) {
+		t.Parallel()
+
+		// insert, status, and a few more status checks fail
+		f, c := setup(t, 10*time.Second, 4)
+
+		params := &backends.InsertAllParams{Docs: make([]*types.Document, 1)}
+		_, err := c.InsertAll(testutil.Ctx(t), params)
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), f.inserts.Load())
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		t.Parallel()
+
+		f, c := setup(t, 200*time.Millisecond, 1_000_000)
+
+		params := &backends.InsertAllParams{Docs: make([]*types.Document, 1)}
+		_, err := c.InsertAll(testutil.Ctx(t), params)
+		require.ErrorIs(t, err, errDown)
+		assert.Equal(t, int32(0), f.inserts.Load())
+	})
+
+	t.Run("BackendError", func(t *testing.T) {
+		t.Parallel()
+
+		f, c := setup(t, 10*time.Second, 0)
+
+		_, err := c.InsertAll(testutil.Ctx(t), new(backends.InsertAllParams))
+		assert.True(t, backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID))
+		assert.Equal(t, int32(0), f.inserts.Load())
+	})
+
+	t.Run("Available", func(t *testing.T) {
+		t.Parallel()
+
+		// insert fails, but status check succeeds, so the error is returned as is
+		f, c := setup(t, 10*time.Second, 1)
+
+		params := &backends.InsertAllParams{Docs: make([]*types.Document, 1)}
+		_, err := c.InsertAll(testutil.Ctx(t), params)
+		require.ErrorIs(t, err, errDown)
+		assert.Equal(t, int32(0), f.inserts.Load())
+	})
+}
@@ -147,6 +147,13 @@ func (b *backend) DropDatabase(ctx context.Context, params *backends.DropDatabas
 	return nil
 }
 
+// Checkpoint implements backends.Backend interface.
+//
+// Committed transactions are already persisted by SAP HANA's redo log, so there is nothing to do.
+func (b *backend) Checkpoint(ctx context.Context, params *backends.CheckpointParams) error {
+	return nil
+}
+
 // Describe implements prometheus.Collector.
 func (b *backend) Describe(ch chan<- *prometheus.Desc) {
 }
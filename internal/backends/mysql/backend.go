@@ -73,6 +73,11 @@ func (b *backend) DropDatabase(ctx context.Context, params *backends.DropDatabas
 	return lazyerrors.New("not yet implemented.")
 }
 
+// Checkpoint implements backends.Backend interface.
+func (b *backend) Checkpoint(ctx context.Context, params *backends.CheckpointParams) error {
+	return lazyerrors.New("not yet implemented.")
+}
+
 // Describe implements prometheus.Collector.
 func (b *backend) Describe(ch chan<- *prometheus.Desc) {
 	// b.r.Describe(ch)
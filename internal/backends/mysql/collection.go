@@ -88,6 +88,51 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 	return nil, lazyerrors.New("not yet implemented")
 }
 
+// ReIndex implements backends.Collection interface.
+func (c *collection) ReIndex(ctx context.Context, params *backends.ReIndexParams) (*backends.ReIndexResult, error) {
+	return nil, lazyerrors.New("not yet implemented")
+}
+
+// Search implements backends.Collection interface.
+func (c *collection) Search(ctx context.Context, params *backends.SearchParams) (*backends.SearchResult, error) {
+	return nil, backends.NewError(
+		backends.ErrorCodeNotSupported,
+		lazyerrors.New("full-text search is not supported by MySQL backend"),
+	)
+}
+
+// VectorSearch implements backends.Collection interface.
+func (c *collection) VectorSearch(ctx context.Context, params *backends.VectorSearchParams) (*backends.VectorSearchResult, error) { //nolint:lll // for readability
+	return nil, backends.NewError(
+		backends.ErrorCodeNotSupported,
+		lazyerrors.New("vector search is not supported by MySQL backend"),
+	)
+}
+
+// ListSearchIndexes implements backends.Collection interface.
+func (c *collection) ListSearchIndexes(ctx context.Context, params *backends.ListSearchIndexesParams) (*backends.ListSearchIndexesResult, error) { //nolint:lll // for readability
+	return nil, backends.NewError(
+		backends.ErrorCodeNotSupported,
+		lazyerrors.New("full-text search is not supported by MySQL backend"),
+	)
+}
+
+// CreateSearchIndexes implements backends.Collection interface.
+func (c *collection) CreateSearchIndexes(ctx context.Context, params *backends.CreateSearchIndexesParams) (*backends.CreateSearchIndexesResult, error) { //nolint:lll // for readability
+	return nil, backends.NewError(
+		backends.ErrorCodeNotSupported,
+		lazyerrors.New("full-text search is not supported by MySQL backend"),
+	)
+}
+
+// DropSearchIndexes implements backends.Collection interface.
+func (c *collection) DropSearchIndexes(ctx context.Context, params *backends.DropSearchIndexesParams) (*backends.DropSearchIndexesResult, error) { //nolint:lll // for readability
+	return nil, backends.NewError(
+		backends.ErrorCodeNotSupported,
+		lazyerrors.New("full-text search is not supported by MySQL backend"),
+	)
+}
+
 // check interfaces
 var (
 	_ backends.Collection = (*collection)(nil)
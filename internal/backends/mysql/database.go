@@ -76,10 +76,22 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 
 	for i, c := range list {
 		res[i] = backends.CollectionInfo{
-			Name:            c.Name,
-			UUID:            c.UUID,
-			CappedSize:      c.CappedSize,
-			CappedDocuments: c.CappedDocuments,
+			Name:             c.Name,
+			UUID:             c.UUID,
+			CappedSize:       c.CappedSize,
+			CappedDocuments:  c.CappedDocuments,
+			Validator:        c.Validator,
+			ValidationLevel:  c.ValidationLevel,
+			ValidationAction: c.ValidationAction,
+			Clustered:        c.Clustered,
+		}
+
+		if ts := c.TimeSeries; ts != nil {
+			res[i].TimeSeries = &backends.TimeSeriesOptions{
+				TimeField:   ts.TimeField,
+				MetaField:   ts.MetaField,
+				Granularity: ts.Granularity,
+			}
 		}
 	}
 
@@ -90,12 +102,26 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 
 // CreateCollection implements backends.Database interface.
 func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
-	created, err := db.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{
-		DBName:          db.name,
-		Name:            params.Name,
-		CappedSize:      params.CappedSize,
-		CappedDocuments: params.CappedDocuments,
-	})
+	p := &metadata.CollectionCreateParams{
+		DBName:           db.name,
+		Name:             params.Name,
+		CappedSize:       params.CappedSize,
+		CappedDocuments:  params.CappedDocuments,
+		Validator:        params.Validator,
+		ValidationLevel:  params.ValidationLevel,
+		ValidationAction: params.ValidationAction,
+		Clustered:        params.Clustered,
+	}
+
+	if ts := params.TimeSeries; ts != nil {
+		p.TimeSeries = &metadata.TimeSeries{
+			TimeField:   ts.TimeField,
+			MetaField:   ts.MetaField,
+			Granularity: ts.Granularity,
+		}
+	}
+
+	created, err := db.r.CollectionCreate(ctx, p)
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
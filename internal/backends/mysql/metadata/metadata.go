@@ -54,6 +54,20 @@ type Collection struct {
 	Indexes         Indexes
 	CappedSize      int64
 	CappedDocuments int64
+
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+
+	TimeSeries *TimeSeries
+	Clustered  bool
+}
+
+// TimeSeries represents time-series collection options.
+type TimeSeries struct {
+	TimeField   string
+	MetaField   string
+	Granularity string
 }
 
 // deepCopy returns a deep copy.
@@ -62,14 +76,28 @@ func (c *Collection) deepCopy() *Collection {
 		return nil
 	}
 
-	return &Collection{
-		Name:            c.Name,
-		UUID:            c.UUID,
-		TableName:       c.TableName,
-		Indexes:         c.Indexes.deepCopy(),
-		CappedSize:      c.CappedSize,
-		CappedDocuments: c.CappedDocuments,
+	res := &Collection{
+		Name:             c.Name,
+		UUID:             c.UUID,
+		TableName:        c.TableName,
+		Indexes:          c.Indexes.deepCopy(),
+		CappedSize:       c.CappedSize,
+		CappedDocuments:  c.CappedDocuments,
+		ValidationLevel:  c.ValidationLevel,
+		ValidationAction: c.ValidationAction,
+		Clustered:        c.Clustered,
+	}
+
+	if c.Validator != nil {
+		res.Validator = c.Validator.DeepCopy()
 	}
+
+	if c.TimeSeries != nil {
+		ts := *c.TimeSeries
+		res.TimeSeries = &ts
+	}
+
+	return res
 }
 
 // Capped returns true if collection is capped.
@@ -117,7 +145,7 @@ func (c *Collection) Scan(src any) error {
 
 // marshal returns the [*types.Document] for that collection.
 func (c *Collection) marshal() *types.Document {
-	return must.NotFail(types.NewDocument(
+	doc := must.NotFail(types.NewDocument(
 		"_id", c.Name,
 		"uuid", c.UUID,
 		"table", c.TableName,
@@ -125,6 +153,26 @@ func (c *Collection) marshal() *types.Document {
 		"cappedSize", c.CappedSize,
 		"cappedDocuments", c.CappedDocuments,
 	))
+
+	if c.Validator != nil {
+		doc.Set("validator", c.Validator)
+		doc.Set("validationLevel", c.ValidationLevel)
+		doc.Set("validationAction", c.ValidationAction)
+	}
+
+	if ts := c.TimeSeries; ts != nil {
+		doc.Set("timeseries", must.NotFail(types.NewDocument(
+			"timeField", ts.TimeField,
+			"metaField", ts.MetaField,
+			"granularity", ts.Granularity,
+		)))
+	}
+
+	if c.Clustered {
+		doc.Set("clustered", true)
+	}
+
+	return doc
 }
 
 // unmarshal sets collection metadata from [*types.Document].
@@ -166,6 +214,26 @@ func (c *Collection) unmarshal(doc *types.Document) error {
 		c.CappedSize = v.(int64)
 	}
 
+	if v, _ := doc.Get("validator"); v != nil {
+		c.Validator = v.(*types.Document)
+		c.ValidationLevel, _ = must.NotFail(doc.Get("validationLevel")).(string)
+		c.ValidationAction, _ = must.NotFail(doc.Get("validationAction")).(string)
+	}
+
+	if v, _ := doc.Get("timeseries"); v != nil {
+		ts := v.(*types.Document)
+
+		c.TimeSeries = &TimeSeries{
+			TimeField:   must.NotFail(ts.Get("timeField")).(string),
+			MetaField:   must.NotFail(ts.Get("metaField")).(string),
+			Granularity: must.NotFail(ts.Get("granularity")).(string),
+		}
+	}
+
+	if v, _ := doc.Get("clustered"); v != nil {
+		c.Clustered = v.(bool)
+	}
+
 	return nil
 }
 
@@ -23,6 +23,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
@@ -33,6 +34,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/backends/mysql/metadata/pool"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/fsql"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -75,13 +77,21 @@ type Registry struct {
 	p *pool.Pool
 	l *zap.Logger
 
-	// rw protects colls but also acts like a global lock for the whole registry.
-	// The latter effectively replaces transactions (see the mysql backend package description for more info).
+	// mu serializes metadata modifications and acts like a global lock for all of them.
+	// That effectively replaces transactions (see the mysql backend package description for more info).
 	// One global lock should be replaced by more granular locks – one per database or even one per collection.
 	// But that requires some redesign.
 	// TODO https://github.com/FerretDB/FerretDB/issues/2755
-	rw    sync.RWMutex
-	colls map[string]map[string]*Collection // database name -> collection name -> collection
+	mu sync.Mutex
+
+	// colls is an immutable snapshot of database name -> collection name -> collection mapping.
+	// Readers load it without locking; writers hold mu, copy the affected maps, and store the new snapshot.
+	// It is nil until metadata is loaded.
+	colls atomic.Pointer[map[string]map[string]*Collection]
+
+	// cacheHits and cacheMisses count collection lookups in the snapshot.
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
 }
 
 // NewRegistry creates a registry for the MySQL databases with a given base URI.
@@ -110,8 +120,8 @@ func (r *Registry) Close() {
 //
 // It loads metadata if it hasn't been loaded from the database yet.
 //
-// It acquires read lock to check metadata, if metadata is empty it acquires write lock
-// to load metadata, so it is safe for concurrent use.
+// It checks metadata without locking, if metadata is not loaded it acquires the lock
+// to load it, so it is safe for concurrent use.
 //
 // All methods use this method to check authentication and load metadata.
 func (r *Registry) getPool(ctx context.Context) (*fsql.DB, error) {
@@ -132,28 +142,32 @@ func (r *Registry) getPool(ctx context.Context) (*fsql.DB, error) {
 		}
 	}
 
-	r.rw.RLock()
-	if r.colls != nil {
-		r.rw.RUnlock()
+	if r.colls.Load() != nil {
 		return p, nil
 	}
-	r.rw.RUnlock()
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// metadata could be loaded while we were waiting for the lock
+	if r.colls.Load() != nil {
+		return p, nil
+	}
 
 	dbNames, err := r.initDBs(ctx, p)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.colls = make(map[string]map[string]*Collection, len(dbNames))
+	colls := make(map[string]map[string]*Collection, len(dbNames))
 	for _, dbName := range dbNames {
-		if err = r.initCollections(ctx, dbName, p); err != nil {
+		if colls[dbName], err = r.initCollections(ctx, dbName, p); err != nil {
 			return nil, lazyerrors.Error(err)
 		}
 	}
 
+	r.colls.Store(&colls)
+
 	return p, nil
 }
 
@@ -208,7 +222,7 @@ func (r *Registry) initDBs(ctx context.Context, p *fsql.DB) ([]string, error) {
 }
 
 // initCollection loads collection metadata from the database during initialization.
-func (r *Registry) initCollections(ctx context.Context, dbName string, p *fsql.DB) error {
+func (r *Registry) initCollections(ctx context.Context, dbName string, p *fsql.DB) (map[string]*Collection, error) {
 	defer observability.FuncCall(ctx)()
 
 	q := fmt.Sprintf(
@@ -219,7 +233,7 @@ func (r *Registry) initCollections(ctx context.Context, dbName string, p *fsql.D
 
 	rows, err := p.QueryContext(ctx, q)
 	if err != nil {
-		return lazyerrors.Error(err)
+		return nil, lazyerrors.Error(err)
 	}
 	defer rows.Close()
 
@@ -229,18 +243,16 @@ func (r *Registry) initCollections(ctx context.Context, dbName string, p *fsql.D
 		var c Collection
 
 		if err = rows.Scan(&c); err != nil {
-			return lazyerrors.Error(err)
+			return nil, lazyerrors.Error(err)
 		}
 		colls[c.Name] = &c
 	}
 
 	if err = rows.Err(); err != nil {
-		return lazyerrors.Error(err)
+		return nil, lazyerrors.Error(err)
 	}
 
-	r.colls[dbName] = colls
-
-	return nil
+	return colls, nil
 }
 
 // DatabaseList returns a sorted list of existing databases.
@@ -254,10 +266,7 @@ func (r *Registry) DatabaseList(ctx context.Context) ([]string, error) {
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
-
-	res := maps.Keys(r.colls)
+	res := maps.Keys(r.snapshot())
 	sort.Strings(res)
 
 	return res, nil
@@ -274,10 +283,7 @@ func (r *Registry) DatabaseGetExisting(ctx context.Context, dbName string) (*fsq
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
-
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db == nil {
 		return nil, nil
 	}
@@ -298,8 +304,8 @@ func (r *Registry) DatabaseGetOrCreate(ctx context.Context, dbName string) (*fsq
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.databaseGetOrCreate(ctx, p, dbName)
 }
@@ -312,7 +318,7 @@ func (r *Registry) DatabaseGetOrCreate(ctx context.Context, dbName string) (*fsq
 func (r *Registry) databaseGetOrCreate(ctx context.Context, p *fsql.DB, dbName string) (*fsql.DB, error) {
 	defer observability.FuncCall(ctx)()
 
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db != nil {
 		return p, nil
 	}
@@ -381,7 +387,7 @@ func (r *Registry) databaseGetOrCreate(ctx context.Context, p *fsql.DB, dbName s
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.colls[dbName] = map[string]*Collection{}
+	r.storeDatabase(dbName, map[string]*Collection{})
 
 	return p, nil
 }
@@ -400,8 +406,8 @@ func (r *Registry) DatabaseDrop(ctx context.Context, dbName string) (bool, error
 		return false, lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.databaseDrop(ctx, p, dbName)
 }
@@ -415,7 +421,7 @@ func (r *Registry) DatabaseDrop(ctx context.Context, dbName string) (bool, error
 func (r *Registry) databaseDrop(ctx context.Context, p *fsql.DB, dbName string) (bool, error) {
 	defer observability.FuncCall(ctx)()
 
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db == nil {
 		return false, nil
 	}
@@ -430,7 +436,7 @@ func (r *Registry) databaseDrop(ctx context.Context, p *fsql.DB, dbName string)
 		return false, lazyerrors.Error(err)
 	}
 
-	delete(r.colls, dbName)
+	r.storeDatabase(dbName, nil)
 
 	return true, nil
 }
@@ -447,16 +453,13 @@ func (r *Registry) CollectionList(ctx context.Context, dbName string) ([]*Collec
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
-
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db == nil {
 		return nil, nil
 	}
 
-	res := make([]*Collection, 0, len(r.colls[dbName]))
-	for _, c := range r.colls[dbName] {
+	res := make([]*Collection, 0, len(db))
+	for _, c := range db {
 		res = append(res, c.deepCopy())
 	}
 
@@ -471,6 +474,13 @@ type CollectionCreateParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
+
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+
+	TimeSeries *TimeSeries
+	Clustered  bool
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -493,8 +503,13 @@ func (r *Registry) CollectionCreate(ctx context.Context, params *CollectionCreat
 		return false, lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	// fast path for the common case of inserting into an existing collection
+	if r.lookup(params.DBName, params.Name) != nil {
+		return false, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.collectionCreate(ctx, p, params)
 }
@@ -516,7 +531,7 @@ func (r *Registry) collectionCreate(ctx context.Context, p *fsql.DB, params *Col
 		return false, lazyerrors.Error(err)
 	}
 
-	colls := r.colls[dbName]
+	colls := r.snapshot()[dbName]
 	if colls != nil && colls[collectionName] != nil {
 		return false, nil
 	}
@@ -547,11 +562,16 @@ func (r *Registry) collectionCreate(ctx context.Context, p *fsql.DB, params *Col
 	}
 
 	c := &Collection{
-		Name:            collectionName,
-		UUID:            uuid.NewString(),
-		TableName:       tableName,
-		CappedSize:      params.CappedSize,
-		CappedDocuments: params.CappedDocuments,
+		Name:             collectionName,
+		UUID:             uuid.NewString(),
+		TableName:        tableName,
+		CappedSize:       params.CappedSize,
+		CappedDocuments:  params.CappedDocuments,
+		Validator:        params.Validator,
+		ValidationLevel:  params.ValidationLevel,
+		ValidationAction: params.ValidationAction,
+		TimeSeries:       params.TimeSeries,
+		Clustered:        params.Clustered,
 	}
 
 	q := fmt.Sprintf(`CREATE TABLE %s.%s (`, dbName, tableName)
@@ -579,10 +599,7 @@ func (r *Registry) collectionCreate(ctx context.Context, p *fsql.DB, params *Col
 		return false, lazyerrors.Error(err)
 	}
 
-	if r.colls[dbName] == nil {
-		r.colls[dbName] = map[string]*Collection{}
-	}
-	r.colls[dbName][collectionName] = c
+	r.storeCollection(dbName, collectionName, c)
 
 	err = r.indexesCreate(ctx, p, dbName, collectionName, []IndexInfo{{
 		Name:   "_id_",
@@ -610,10 +627,7 @@ func (r *Registry) CollectionGet(ctx context.Context, dbName, collectionName str
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
-
-	return r.collectionGet(dbName, collectionName), nil
+	return r.lookup(dbName, collectionName).deepCopy(), nil
 }
 
 // collectionGet returns a copy of collection metadata.
@@ -623,7 +637,7 @@ func (r *Registry) CollectionGet(ctx context.Context, dbName, collectionName str
 //
 // It does not hold the lock.
 func (r *Registry) collectionGet(dbName, collectionName string) *Collection {
-	colls := r.colls[dbName]
+	colls := r.snapshot()[dbName]
 	if colls == nil {
 		return nil
 	}
@@ -631,6 +645,72 @@ func (r *Registry) collectionGet(dbName, collectionName string) *Collection {
 	return colls[collectionName].deepCopy()
 }
 
+// snapshot returns the current snapshot of collections metadata.
+//
+// It does not hold the lock. Returned maps must not be modified.
+func (r *Registry) snapshot() map[string]map[string]*Collection {
+	if colls := r.colls.Load(); colls != nil {
+		return *colls
+	}
+
+	return nil
+}
+
+// lookup returns collection metadata from the snapshot and counts cache hit or miss.
+// If database or collection does not exist, nil is returned.
+//
+// It does not hold the lock. Returned collection must not be modified.
+func (r *Registry) lookup(dbName, collectionName string) *Collection {
+	c := r.snapshot()[dbName][collectionName]
+	if c == nil {
+		r.cacheMisses.Add(1)
+		return nil
+	}
+
+	r.cacheHits.Add(1)
+
+	return c
+}
+
+// storeDatabase stores a new snapshot with the given database collections.
+// If colls is nil, the database is removed from the snapshot.
+//
+// It should be called with the lock held.
+func (r *Registry) storeDatabase(dbName string, colls map[string]*Collection) {
+	old := r.snapshot()
+
+	res := make(map[string]map[string]*Collection, len(old)+1)
+	maps.Copy(res, old)
+
+	if colls == nil {
+		delete(res, dbName)
+	} else {
+		res[dbName] = colls
+	}
+
+	r.colls.Store(&res)
+}
+
+// storeCollection stores a new snapshot with the given collection.
+// If c is nil, the collection is removed from the snapshot.
+// The database is added to the snapshot if needed.
+//
+// It should be called with the lock held.
+func (r *Registry) storeCollection(dbName, collectionName string, c *Collection) {
+	old := r.snapshot()[dbName]
+
+	colls := make(map[string]*Collection, len(old)+1)
+	maps.Copy(colls, old)
+
+	if c == nil {
+		delete(colls, collectionName)
+	} else {
+		colls[collectionName] = c
+	}
+
+	r.storeDatabase(dbName, colls)
+}
+
 // CollectionDrop drops a collection in the database.
 //
 // Returned boolean value indicates whether the collection was dropped.
@@ -645,8 +725,8 @@ func (r *Registry) CollectionDrop(ctx context.Context, dbName, collectionName st
 		return false, lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.collectionDrop(ctx, p, dbName, collectionName)
 }
@@ -660,7 +740,7 @@ func (r *Registry) CollectionDrop(ctx context.Context, dbName, collectionName st
 func (r *Registry) collectionDrop(ctx context.Context, p *fsql.DB, dbName, collectionName string) (bool, error) {
 	defer observability.FuncCall(ctx)()
 
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db == nil {
 		return false, nil
 	}
@@ -694,7 +774,7 @@ func (r *Registry) collectionDrop(ctx context.Context, p *fsql.DB, dbName, colle
 		return false, lazyerrors.Error(err)
 	}
 
-	delete(r.colls[dbName], collectionName)
+	r.storeCollection(dbName, collectionName, nil)
 
 	return true, nil
 }
@@ -715,10 +795,10 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 		return false, lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db == nil {
 		return false, nil
 	}
@@ -751,8 +831,8 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 		return false, lazyerrors.Error(err)
 	}
 
-	r.colls[dbName][newCollectionName] = c
-	delete(r.colls[dbName], oldCollectionName)
+	r.storeCollection(dbName, newCollectionName, c)
+	r.storeCollection(dbName, oldCollectionName, nil)
 
 	return true, nil
 }
@@ -770,8 +850,8 @@ func (r *Registry) IndexesCreate(ctx context.Context, dbName, collectionName str
 		return lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.indexesCreate(ctx, p, dbName, collectionName, indexes)
 }
@@ -788,7 +868,7 @@ func (r *Registry) indexesCreate(ctx context.Context, p *fsql.DB, dbName, collec
 		return lazyerrors.Error(err)
 	}
 
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db == nil {
 		panic("database does not exist")
 	}
@@ -961,7 +1041,7 @@ func (r *Registry) indexesCreate(ctx context.Context, p *fsql.DB, dbName, collec
 		return lazyerrors.Error(err)
 	}
 
-	r.colls[dbName][collectionName] = c
+	r.storeCollection(dbName, collectionName, c)
 
 	return nil
 }
@@ -981,8 +1061,8 @@ func (r *Registry) IndexesDrop(ctx context.Context, dbName, collectionName strin
 		return lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.indexesDrop(ctx, p, dbName, collectionName, indexNames)
 }
@@ -1039,7 +1119,7 @@ func (r *Registry) indexesDrop(ctx context.Context, p *fsql.DB, dbName, collecti
 		return lazyerrors.Error(err)
 	}
 
-	r.colls[dbName][collectionName] = c
+	r.storeCollection(dbName, collectionName, c)
 
 	return nil
 }
@@ -1053,8 +1133,7 @@ func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
 func (r *Registry) Collect(ch chan<- prometheus.Metric) {
 	r.p.Collect(ch)
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
+	snapshot := r.snapshot()
 
 	ch <- prometheus.MustNewConstMetric(
 		prometheus.NewDesc(
@@ -1063,10 +1142,18 @@ func (r *Registry) Collect(ch chan<- prometheus.Metric) {
 			nil, nil,
 		),
 		prometheus.GaugeValue,
-		float64(len(r.colls)),
+		float64(len(snapshot)),
+	)
+
+	cacheDesc := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "cache_lookups_total"),
+		"The total number of collection lookups in the registry cache.",
+		[]string{"result"}, nil,
 	)
+	ch <- prometheus.MustNewConstMetric(cacheDesc, prometheus.CounterValue, float64(r.cacheHits.Load()), "hit")
+	ch <- prometheus.MustNewConstMetric(cacheDesc, prometheus.CounterValue, float64(r.cacheMisses.Load()), "miss")
 
-	for db, colls := range r.colls {
+	for db, colls := range snapshot {
 		ch <- prometheus.MustNewConstMetric(
 			prometheus.NewDesc(
 				prometheus.BuildFQName(namespace, subsystem, "collections"),
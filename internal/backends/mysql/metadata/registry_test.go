@@ -297,8 +297,10 @@ func TestCheckDatabaseUpdated(t *testing.T) {
 	var err error
 
 	t.Run("CheckDatabaseCreate", func(t *testing.T) {
-		err = r.initCollections(ctx, dbName, db)
+		var colls map[string]*Collection
+		colls, err = r.initCollections(ctx, dbName, db)
 		require.NoError(t, err)
+		r.storeDatabase(dbName, colls)
 
 		var p *fsql.DB
 		p, err = r.DatabaseGetExisting(ctx, dbName)
@@ -317,8 +319,10 @@ func TestCheckDatabaseUpdated(t *testing.T) {
 		require.NotNil(t, metadataCollection)
 		require.Equal(t, collectionName, metadataCollection.Name)
 
-		err = r.initCollections(ctx, dbName, db)
+		var colls map[string]*Collection
+		colls, err = r.initCollections(ctx, dbName, db)
 		require.NoError(t, err)
+		r.storeDatabase(dbName, colls)
 
 		dbCollection, err := r.CollectionGet(ctx, dbName, collectionName)
 		require.NoError(t, err)
@@ -330,8 +334,10 @@ func TestCheckDatabaseUpdated(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, dropped)
 
-		err = r.initCollections(ctx, dbName, db)
+		var colls map[string]*Collection
+		colls, err = r.initCollections(ctx, dbName, db)
 		require.NoError(t, err)
+		r.storeDatabase(dbName, colls)
 
 		dbCollection, err := r.CollectionGet(ctx, dbName, collectionName)
 		require.NoError(t, err)
@@ -343,7 +349,7 @@ func TestCheckDatabaseUpdated(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, dropped)
 
-		err = r.initCollections(ctx, dbName, db)
+		_, err = r.initCollections(ctx, dbName, db)
 		require.Error(t, err)
 		require.ErrorContains(t, err, "Unknown database 'TestCheckDatabaseUpdated'")
 	})
@@ -373,8 +379,10 @@ func TestRenameCollection(t *testing.T) {
 	})
 
 	t.Run("CheckCollectionRenamed", func(t *testing.T) {
-		err = r.initCollections(ctx, dbName, db)
+		var colls map[string]*Collection
+		colls, err = r.initCollections(ctx, dbName, db)
 		require.NoError(t, err)
+		r.storeDatabase(dbName, colls)
 
 		expected := &Collection{
 			Name:      newCollectionName,
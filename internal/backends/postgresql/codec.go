@@ -0,0 +1,92 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
+	"github.com/FerretDB/FerretDB/internal/bson"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// marshalDocument returns values of DefaultColumn and BSONColumn for the given document
+// according to the collection's codec.
+//
+// BSONColumn value is nil if the codec does not use it.
+func marshalDocument(meta *metadata.Collection, doc *types.Document) (string, []byte, error) {
+	b, err := sjson.Marshal(meta.JSONBDocument(doc))
+	if err != nil {
+		return "", nil, lazyerrors.Error(err)
+	}
+
+	if !meta.HasBSONColumn() {
+		return string(b), nil, nil
+	}
+
+	d, err := bson.ConvertDocument(doc)
+	if err != nil {
+		return "", nil, lazyerrors.Error(err)
+	}
+
+	raw, err := d.MarshalBinary()
+	if err != nil {
+		return "", nil, lazyerrors.Error(err)
+	}
+
+	return string(b), raw, nil
+}
+
+// unmarshalBSON decodes the document stored in BSONColumn.
+func unmarshalBSON(b []byte) (*types.Document, error) {
+	var doc bson.Document
+	if err := doc.ReadFrom(bufio.NewReader(bytes.NewReader(b))); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	res, err := types.ConvertDocument(&doc)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res, nil
+}
+
+// pushdownFilter returns the filter without conditions for fields that are not stored in DefaultColumn
+// by the collection's codec, so they are applied only by the handler.
+func pushdownFilter(meta *metadata.Collection, filter *types.Document) *types.Document {
+	if filter == nil || !meta.HasBSONColumn() {
+		return filter
+	}
+
+	res := types.MakeDocument(filter.Len())
+
+	for _, k := range filter.Keys() {
+		// top-level operators are not pushed down anyway
+		field, _, _ := strings.Cut(k, ".")
+		if !strings.HasPrefix(k, "$") && !meta.JSONBField(field) {
+			continue
+		}
+
+		res.Set(k, must.NotFail(filter.Get(k)))
+	}
+
+	return res
+}
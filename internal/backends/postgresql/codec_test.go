@@ -0,0 +1,125 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgresql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestMarshalDocument(t *testing.T) {
+	t.Parallel()
+
+	doc := must.NotFail(types.NewDocument(
+		"_id", types.ObjectID{0x01},
+		"v", "foo",
+		"w", must.NotFail(types.NewDocument("x", int64(42))),
+		"t", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"d", 42.0,
+	))
+
+	for name, tc := range map[string]struct { //nolint:vet // used for test only
+		meta *metadata.Collection
+
+		expectedJSONB string
+		expectedBSON  bool
+	}{
+		"Default": {
+			meta:          new(metadata.Collection),
+			expectedJSONB: `{"$s":{"p":{"_id":{"t":"objectId"},"v":{"t":"string"},"w":{"t":"object","$s":{"p":{"x":{"t":"long"}},"$k":["x"]}},"t":{"t":"date"},"d":{"t":"double"}},"$k":["_id","v","w","t","d"]},"_id":"010000000000000000000000","v":"foo","w":{"x":42},"t":1704164645000,"d":42}`, //nolint:lll // for readability
+		},
+		"BSON": {
+			meta:          &metadata.Collection{Codec: metadata.CodecBSON},
+			expectedJSONB: `{"$s":{"p":{"_id":{"t":"objectId"}},"$k":["_id"]},"_id":"010000000000000000000000"}`,
+			expectedBSON:  true,
+		},
+		"Hybrid": {
+			meta:          &metadata.Collection{Codec: metadata.CodecHybrid, CodecFields: []string{"v", "missing"}},
+			expectedJSONB: `{"$s":{"p":{"_id":{"t":"objectId"},"v":{"t":"string"}},"$k":["_id","v"]},"_id":"010000000000000000000000","v":"foo"}`, //nolint:lll // for readability
+			expectedBSON:  true,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			b, raw, err := marshalDocument(tc.meta, doc)
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expectedJSONB, b)
+
+			if !tc.expectedBSON {
+				assert.Nil(t, raw)
+				return
+			}
+
+			actual, err := unmarshalBSON(raw)
+			require.NoError(t, err)
+			testutil.AssertEqual(t, doc, actual)
+		})
+	}
+}
+
+func TestPushdownFilter(t *testing.T) {
+	t.Parallel()
+
+	filter := must.NotFail(types.NewDocument(
+		"_id", int32(1),
+		"v", "foo",
+		"v.foo", "bar",
+		"w", int32(42),
+		"$comment", "I'm comment",
+	))
+
+	assert.Same(t, filter, pushdownFilter(new(metadata.Collection), filter))
+
+	meta := &metadata.Collection{Codec: metadata.CodecHybrid, CodecFields: []string{"v"}}
+	expected := must.NotFail(types.NewDocument(
+		"_id", int32(1),
+		"v", "foo",
+		"v.foo", "bar",
+		"$comment", "I'm comment",
+	))
+	assert.Equal(t, expected, pushdownFilter(meta, filter))
+
+	meta = &metadata.Collection{Codec: metadata.CodecBSON}
+	expected = must.NotFail(types.NewDocument(
+		"_id", int32(1),
+		"$comment", "I'm comment",
+	))
+	assert.Equal(t, expected, pushdownFilter(meta, filter))
+}
+
+func TestPrepareInsertStatement(t *testing.T) {
+	t.Parallel()
+
+	doc := must.NotFail(types.NewDocument("_id", int32(1), "v", "foo"))
+	meta := &metadata.Collection{TableName: "table", Codec: metadata.CodecBSON}
+
+	q, args, err := prepareInsertStatement("schema", meta, []*types.Document{doc})
+	require.NoError(t, err)
+
+	assert.Equal(t, `INSERT INTO "schema"."table" (_jsonb, _ferretdb_bson) VALUES ($1, $2)`, q)
+	require.Len(t, args, 2)
+	assert.JSONEq(t, `{"$s":{"p":{"_id":{"t":"int"}},"$k":["_id"]},"_id":1}`, args[0].(string))
+	assert.IsType(t, []byte(nil), args[1])
+}
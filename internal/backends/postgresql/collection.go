@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata/pool"
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
@@ -80,6 +82,7 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 		Comment:       params.Comment,
 		Capped:        meta.Capped(),
 		OnlyRecordIDs: params.OnlyRecordIDs,
+		BSON:          meta.HasBSONColumn(),
 	})
 
 	var placeholder metadata.Placeholder
@@ -87,14 +90,28 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 	var where string
 	var args []any
 
-	where, args, err = prepareWhereClause(&placeholder, params.Filter)
+	filter := pushdownFilter(meta, params.Filter)
+
+	where, args, err = prepareWhereClause(&placeholder, filter)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
+	if meta.TimeSeries != nil {
+		var tsArgs []any
+		where, tsArgs = prepareTimeSeriesClause(&placeholder, where, meta.TimeSeries.TimeField, filter)
+		args = append(args, tsArgs...)
+	}
+
+	if meta.Clustered {
+		var idArgs []any
+		where, idArgs = prepareClusteredClause(&placeholder, where, filter)
+		args = append(args, idArgs...)
+	}
+
 	q += where
 
-	sort, sortArgs := prepareOrderByClause(params.Sort)
+	sort, sortArgs := prepareOrderByClause(params.Sort, meta.Clustered, meta.Capped())
 
 	q += sort
 	args = append(args, sortArgs...)
@@ -147,7 +164,7 @@ func (c *collection) InsertAll(ctx context.Context, params *backends.InsertAllPa
 			var q string
 			var args []any
 
-			q, args, err = prepareInsertStatement(c.dbName, meta.TableName, meta.Capped(), batch)
+			q, args, err = prepareInsertStatement(c.dbName, meta, batch)
 			if err != nil {
 				return lazyerrors.Error(err)
 			}
@@ -199,20 +216,33 @@ func (c *collection) UpdateAll(ctx context.Context, params *backends.UpdateAllPa
 		metadata.IDColumn,
 	)
 
+	if meta.HasBSONColumn() {
+		q = fmt.Sprintf(
+			`UPDATE %s SET %s = $1, %s = $3 WHERE %s = $2`,
+			pgx.Identifier{c.dbName, meta.TableName}.Sanitize(),
+			metadata.DefaultColumn,
+			metadata.BSONColumn,
+			metadata.IDColumn,
+		)
+	}
+
 	err = pool.InTransaction(ctx, p, func(tx pgx.Tx) error {
 		for _, doc := range params.Docs {
-			var b []byte
-			if b, err = sjson.Marshal(doc); err != nil {
+			b, raw, err := marshalDocument(meta, doc)
+			if err != nil {
 				return lazyerrors.Error(err)
 			}
 
 			id, _ := doc.Get("_id")
 			must.NotBeZero(id)
 
-			arg := must.NotFail(sjson.MarshalSingleValue(id))
+			args := []any{b, must.NotFail(sjson.MarshalSingleValue(id))}
+			if raw != nil {
+				args = append(args, raw)
+			}
 
 			var tag pgconn.CommandTag
-			if tag, err = tx.Exec(ctx, q, b, arg); err != nil {
+			if tag, err = tx.Exec(ctx, q, args...); err != nil {
 				return lazyerrors.Error(err)
 			}
 
@@ -326,22 +356,41 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		Schema: c.dbName,
 		Table:  meta.TableName,
 		Capped: meta.Capped(),
+		BSON:   meta.HasBSONColumn(),
 	}
 
 	q := `EXPLAIN (VERBOSE true, FORMAT JSON) ` + prepareSelectClause(opts)
 
 	var placeholder metadata.Placeholder
 
-	where, args, err := prepareWhereClause(&placeholder, params.Filter)
+	filter := pushdownFilter(meta, params.Filter)
+
+	where, args, err := prepareWhereClause(&placeholder, filter)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
+	if meta.TimeSeries != nil {
+		var tsArgs []any
+		where, tsArgs = prepareTimeSeriesClause(&placeholder, where, meta.TimeSeries.TimeField, filter)
+		args = append(args, tsArgs...)
+	}
+
+	if meta.Clustered {
+		var idArgs []any
+		where, idArgs = prepareClusteredClause(&placeholder, where, filter)
+		args = append(args, idArgs...)
+	}
+
 	res.FilterPushdown = where != ""
 
+	if res.FilterPushdownPaths, err = prepareFilterPushdownPaths(meta, params.Filter); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	q += where
 
-	sort, sortArgs := prepareOrderByClause(params.Sort)
+	sort, sortArgs := prepareOrderByClause(params.Sort, meta.Clustered, meta.Capped())
 	res.SortPushdown = sort != ""
 
 	q += sort
@@ -493,6 +542,15 @@ func (c *collection) Compact(ctx context.Context, params *backends.CompactParams
 		return nil, lazyerrors.Error(err)
 	}
 
+	// full compaction of clustered collection also restores the order of _id values
+	if coll.Clustered && params != nil && params.Full {
+		q = "CLUSTER " + pgx.Identifier{c.dbName, coll.TableName}.Sanitize()
+
+		if _, err = db.Exec(ctx, q); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
 	return new(backends.CompactResult), nil
 }
 
@@ -550,6 +608,11 @@ func (c *collection) ListIndexes(ctx context.Context, params *backends.ListIndex
 
 // CreateIndexes implements backends.Collection interface.
 func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateIndexesParams) (*backends.CreateIndexesResult, error) { //nolint:lll // for readability
+	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	indexes := make([]metadata.IndexInfo, len(params.Indexes))
 	for i, index := range params.Indexes {
 		indexes[i] = metadata.IndexInfo{
@@ -559,6 +622,14 @@ func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateI
 		}
 
 		for j, key := range index.Key {
+			// only fields stored in DefaultColumn could be indexed
+			if field, _, _ := strings.Cut(key.Field, "."); meta != nil && !meta.JSONBField(field) {
+				return nil, backends.NewError(
+					backends.ErrorCodeNotSupported,
+					lazyerrors.Errorf("field %q is not stored in %q column by %q codec", key.Field, metadata.DefaultColumn, meta.Codec),
+				)
+			}
+
 			indexes[i].Key[j] = metadata.IndexKeyPair{
 				Field:      key.Field,
 				Descending: key.Descending,
@@ -566,7 +637,7 @@ func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateI
 		}
 	}
 
-	err := c.r.IndexesCreate(ctx, c.dbName, c.name, indexes)
+	err = c.r.IndexesCreate(ctx, c.dbName, c.name, indexes, params.Progress)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
@@ -574,6 +645,42 @@ func (c *collection) CreateIndexes(ctx context.Context, params *backends.CreateI
 	return new(backends.CreateIndexesResult), nil
 }
 
+// ReIndex implements backends.Collection interface.
+func (c *collection) ReIndex(ctx context.Context, params *backends.ReIndexParams) (*backends.ReIndexResult, error) {
+	db, err := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if db == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeDatabaseDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	coll, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if coll == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	// rebuild indexes without blocking writes
+	q := "REINDEX TABLE CONCURRENTLY " + pgx.Identifier{c.dbName, coll.TableName}.Sanitize()
+
+	if _, err = db.Exec(ctx, q); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return new(backends.ReIndexResult), nil
+}
+
 // DropIndexes implements backends.Collection interface.
 func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndexesParams) (*backends.DropIndexesResult, error) {
 	err := c.r.IndexesDrop(ctx, c.dbName, c.name, params.Indexes)
@@ -584,6 +691,304 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 	return new(backends.DropIndexesResult), nil
 }
 
+// Search implements backends.Collection interface.
+func (c *collection) Search(ctx context.Context, params *backends.SearchParams) (*backends.SearchResult, error) {
+	p, err := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if p == nil {
+		return &backends.SearchResult{
+			Iter: newQueryIterator(ctx, nil, false),
+		}, nil
+	}
+
+	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var index *metadata.SearchIndexInfo
+
+	if meta != nil {
+		if i := slices.IndexFunc(meta.SearchIndexes, func(i metadata.SearchIndexInfo) bool {
+			return i.Name == params.Index
+		}); i >= 0 {
+			index = &meta.SearchIndexes[i]
+		}
+	}
+
+	if index == nil {
+		return &backends.SearchResult{
+			Iter: newQueryIterator(ctx, nil, false),
+		}, nil
+	}
+
+	q := prepareSelectClause(&selectParams{
+		Schema: c.dbName,
+		Table:  meta.TableName,
+		Capped: meta.Capped(),
+		BSON:   meta.HasBSONColumn(),
+	})
+
+	var placeholder metadata.Placeholder
+
+	where, orderBy, args := prepareSearchClause(&placeholder, index, params)
+
+	q += where + orderBy
+
+	if params.Limit != 0 {
+		q += fmt.Sprintf(` LIMIT %s`, placeholder.Next())
+		args = append(args, params.Limit)
+	}
+
+	rows, err := p.Query(ctx, q, args...)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &backends.SearchResult{
+		Iter: newQueryIterator(ctx, rows, false),
+	}, nil
+}
+
+// VectorSearch implements backends.Collection interface.
+func (c *collection) VectorSearch(ctx context.Context, params *backends.VectorSearchParams) (*backends.VectorSearchResult, error) { //nolint:lll // for readability
+	p, err := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if p == nil {
+		return &backends.VectorSearchResult{
+			Iter: newQueryIterator(ctx, nil, false),
+		}, nil
+	}
+
+	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var field *metadata.VectorSearchIndexField
+
+	if meta != nil {
+		for _, index := range meta.SearchIndexes {
+			if index.Name != params.Index {
+				continue
+			}
+
+			if i := slices.IndexFunc(index.Vectors, func(v metadata.VectorSearchIndexField) bool {
+				return v.Path == params.Path
+			}); i >= 0 {
+				field = &index.Vectors[i]
+			}
+		}
+	}
+
+	if field == nil {
+		return &backends.VectorSearchResult{
+			Iter: newQueryIterator(ctx, nil, false),
+		}, nil
+	}
+
+	if field.PgIndex == "" {
+		return nil, backends.NewError(
+			backends.ErrorCodeNotSupported,
+			lazyerrors.Errorf("vector field %q is not indexed", params.Path),
+		)
+	}
+
+	q := prepareSelectClause(&selectParams{
+		Schema: c.dbName,
+		Table:  meta.TableName,
+		Capped: meta.Capped(),
+		BSON:   meta.HasBSONColumn(),
+	})
+
+	var placeholder metadata.Placeholder
+
+	where, args, err := prepareWhereClause(&placeholder, pushdownFilter(meta, params.Filter))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// documents without a valid vector are not indexed, but they could be returned by sequential scans
+	valid := metadata.VectorExpression(c.dbName, field.Path, field.Dimensions) + " IS NOT NULL"
+	if where == "" {
+		where = " WHERE " + valid
+	} else {
+		where += " AND " + valid
+	}
+
+	orderBy, orderByArgs := prepareVectorOrderByClause(&placeholder, c.dbName, field, params.QueryVector)
+
+	q += where + orderBy
+	args = append(args, orderByArgs...)
+
+	q += fmt.Sprintf(` LIMIT %s`, placeholder.Next())
+	args = append(args, params.Limit)
+
+	// index scans return at most ef_search candidates, so it should not be lower than limit
+	efSearch := min(max(params.Limit, 40), 1000)
+
+	tx, err := p.Begin(ctx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	rollback := func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}
+
+	if _, err = tx.Exec(ctx, fmt.Sprintf("SET LOCAL hnsw.ef_search = %d", efSearch)); err != nil {
+		rollback()
+		return nil, lazyerrors.Error(err)
+	}
+
+	if _, err = tx.Exec(ctx, "SET LOCAL ivfflat.probes = 10"); err != nil {
+		rollback()
+		return nil, lazyerrors.Error(err)
+	}
+
+	rows, err := tx.Query(ctx, q, args...)
+	if err != nil {
+		rollback()
+		return nil, lazyerrors.Error(err)
+	}
+
+	// the transaction is used only for settings above, so it is rolled back after rows are read
+	iter := newQueryIterator(ctx, rows, false)
+
+	return &backends.VectorSearchResult{
+		Iter: iterator.WithClose(iter, func() {
+			iter.Close()
+			rollback()
+		}),
+	}, nil
+}
+
+// ListSearchIndexes implements backends.Collection interface.
+func (c *collection) ListSearchIndexes(ctx context.Context, params *backends.ListSearchIndexesParams) (*backends.ListSearchIndexesResult, error) { //nolint:lll // for readability
+	db, err := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if db == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	coll, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if coll == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	res := backends.ListSearchIndexesResult{
+		Indexes: make([]backends.SearchIndexInfo, len(coll.SearchIndexes)),
+	}
+
+	for i, index := range coll.SearchIndexes {
+		res.Indexes[i] = backends.SearchIndexInfo{
+			Name:    index.Name,
+			Dynamic: index.Dynamic,
+			Fields:  make([]backends.SearchIndexField, len(index.Fields)),
+		}
+
+		for j, f := range index.Fields {
+			res.Indexes[i].Fields[j] = backends.SearchIndexField{
+				Path:         f.Path,
+				Autocomplete: f.Autocomplete,
+			}
+		}
+
+		for _, v := range index.Vectors {
+			res.Indexes[i].Vectors = append(res.Indexes[i].Vectors, backends.VectorSearchIndexField{
+				Path:       v.Path,
+				Dimensions: int(v.Dimensions),
+				Similarity: backends.VectorSimilarity(v.Similarity),
+			})
+		}
+
+		res.Indexes[i].Filters = slices.Clone(index.Filters)
+	}
+
+	sort.Slice(res.Indexes, func(i, j int) bool {
+		return res.Indexes[i].Name < res.Indexes[j].Name
+	})
+
+	return &res, nil
+}
+
+// CreateSearchIndexes implements backends.Collection interface.
+func (c *collection) CreateSearchIndexes(ctx context.Context, params *backends.CreateSearchIndexesParams) (*backends.CreateSearchIndexesResult, error) { //nolint:lll // for readability
+	meta, err := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if meta != nil && meta.HasBSONColumn() {
+		return nil, backends.NewError(
+			backends.ErrorCodeNotSupported,
+			lazyerrors.Errorf("search indexes are not supported by %q codec", meta.Codec),
+		)
+	}
+
+	indexes := make([]metadata.SearchIndexInfo, len(params.Indexes))
+	for i, index := range params.Indexes {
+		indexes[i] = metadata.SearchIndexInfo{
+			Name:    index.Name,
+			Dynamic: index.Dynamic,
+			Fields:  make([]metadata.SearchIndexField, len(index.Fields)),
+		}
+
+		for j, f := range index.Fields {
+			indexes[i].Fields[j] = metadata.SearchIndexField{
+				Path:         f.Path,
+				Autocomplete: f.Autocomplete,
+			}
+		}
+
+		for _, v := range index.Vectors {
+			indexes[i].Vectors = append(indexes[i].Vectors, metadata.VectorSearchIndexField{
+				Path:       v.Path,
+				Dimensions: int64(v.Dimensions),
+				Similarity: string(v.Similarity),
+			})
+		}
+
+		indexes[i].Filters = slices.Clone(index.Filters)
+	}
+
+	err = c.r.SearchIndexesCreate(ctx, c.dbName, c.name, indexes)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return new(backends.CreateSearchIndexesResult), nil
+}
+
+// DropSearchIndexes implements backends.Collection interface.
+func (c *collection) DropSearchIndexes(ctx context.Context, params *backends.DropSearchIndexesParams) (*backends.DropSearchIndexesResult, error) { //nolint:lll // for readability
+	err := c.r.SearchIndexesDrop(ctx, c.dbName, c.name, params.Indexes)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return new(backends.DropSearchIndexesResult), nil
+}
+
 // check interfaces
 var (
 	_ backends.Collection = (*collection)(nil)
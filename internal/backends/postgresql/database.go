@@ -75,10 +75,24 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 
 	for i, c := range list {
 		res[i] = backends.CollectionInfo{
-			Name:            c.Name,
-			UUID:            c.UUID,
-			CappedSize:      c.CappedSize,
-			CappedDocuments: c.CappedDocuments,
+			Name:             c.Name,
+			UUID:             c.UUID,
+			CappedSize:       c.CappedSize,
+			CappedDocuments:  c.CappedDocuments,
+			Validator:        c.Validator,
+			ValidationLevel:  c.ValidationLevel,
+			ValidationAction: c.ValidationAction,
+			Clustered:        c.Clustered,
+			Codec:            string(c.Codec),
+			CodecFields:      slices.Clone(c.CodecFields),
+		}
+
+		if ts := c.TimeSeries; ts != nil {
+			res[i].TimeSeries = &backends.TimeSeriesOptions{
+				TimeField:   ts.TimeField,
+				MetaField:   ts.MetaField,
+				Granularity: ts.Granularity,
+			}
 		}
 	}
 
@@ -89,12 +103,28 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 
 // CreateCollection implements backends.Database interface.
 func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
-	created, err := db.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{
-		DBName:          db.name,
-		Name:            params.Name,
-		CappedSize:      params.CappedSize,
-		CappedDocuments: params.CappedDocuments,
-	})
+	p := &metadata.CollectionCreateParams{
+		DBName:           db.name,
+		Name:             params.Name,
+		CappedSize:       params.CappedSize,
+		CappedDocuments:  params.CappedDocuments,
+		Validator:        params.Validator,
+		ValidationLevel:  params.ValidationLevel,
+		ValidationAction: params.ValidationAction,
+		Clustered:        params.Clustered,
+		Codec:            metadata.Codec(params.Codec),
+		CodecFields:      params.CodecFields,
+	}
+
+	if ts := params.TimeSeries; ts != nil {
+		p.TimeSeries = &metadata.TimeSeries{
+			TimeField:   ts.TimeField,
+			MetaField:   ts.MetaField,
+			Granularity: ts.Granularity,
+		}
+	}
+
+	created, err := db.r.CollectionCreate(ctx, p)
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
@@ -21,45 +21,61 @@ import (
 	"github.com/jackc/pgx/v5"
 
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
-	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
 // prepareInsertStatement returns a statement and arguments for inserting the given documents.
 //
-// If capped is true, it returns a statement and arguments for inserting record IDs and documents.
-func prepareInsertStatement(schema, tableName string, capped bool, docs []*types.Document) (string, []any, error) {
+// For capped collection, it returns a statement and arguments for inserting record IDs and documents.
+// For collections with BSONColumn, documents are inserted into both columns.
+func prepareInsertStatement(schema string, meta *metadata.Collection, docs []*types.Document) (string, []any, error) {
 	var placeholder metadata.Placeholder
 	var args []any
 	rows := make([]string, len(docs))
 
+	capped := meta.Capped()
+	withBSON := meta.HasBSONColumn()
+
 	for i, doc := range docs {
-		b, err := sjson.Marshal(doc)
+		b, raw, err := marshalDocument(meta, doc)
 		if err != nil {
 			return "", nil, lazyerrors.Error(err)
 		}
 
+		var row []string
+
 		if capped {
-			rows[i] = "(" + placeholder.Next() + ", " + placeholder.Next() + ")"
-			args = append(args, doc.RecordID(), string(b))
+			row = append(row, placeholder.Next())
+			args = append(args, doc.RecordID())
+		}
+
+		row = append(row, placeholder.Next())
+		args = append(args, b)
 
-			continue
+		if withBSON {
+			row = append(row, placeholder.Next())
+			args = append(args, raw)
 		}
 
-		rows[i] = "(" + placeholder.Next() + ")"
-		args = append(args, string(b))
+		rows[i] = "(" + strings.Join(row, ", ") + ")"
 	}
 
-	columns := metadata.DefaultColumn
+	var columns []string
 	if capped {
-		columns = strings.Join([]string{metadata.RecordIDColumn, metadata.DefaultColumn}, ", ")
+		columns = append(columns, metadata.RecordIDColumn)
+	}
+
+	columns = append(columns, metadata.DefaultColumn)
+
+	if withBSON {
+		columns = append(columns, metadata.BSONColumn)
 	}
 
 	return fmt.Sprintf(
 		`INSERT INTO %s (%s) VALUES %s`,
-		pgx.Identifier{schema, tableName}.Sanitize(),
-		columns,
+		pgx.Identifier{schema, meta.TableName}.Sanitize(),
+		strings.Join(columns, ", "),
 		strings.Join(rows, ", "),
 	), args, nil
 }
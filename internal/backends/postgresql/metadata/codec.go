@@ -0,0 +1,95 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// BSONColumn is a column name for whole documents stored as BSON by some codecs.
+const BSONColumn = backends.ReservedPrefix + "bson"
+
+// Codec represents the way documents are stored in the collection's table.
+type Codec string
+
+const (
+	// CodecJSONB stores whole documents as jsonb in DefaultColumn.
+	// All fields could be indexed and used for query pushdown.
+	CodecJSONB Codec = "jsonb"
+
+	// CodecBSON stores whole documents as BSON in BSONColumn without any conversion.
+	// Only _id is stored in DefaultColumn, so only it could be indexed and used for query pushdown.
+	CodecBSON Codec = "bson"
+
+	// CodecHybrid stores whole documents as BSON in BSONColumn,
+	// and _id with configured top-level fields as jsonb in DefaultColumn.
+	// Only those fields could be indexed and used for query pushdown.
+	CodecHybrid Codec = "hybrid"
+)
+
+// Codecs contains all supported codecs.
+var Codecs = []Codec{CodecJSONB, CodecBSON, CodecHybrid}
+
+// StorageCodec returns the collection's codec.
+//
+// Collections created by older versions of FerretDB use CodecJSONB.
+func (c *Collection) StorageCodec() Codec {
+	if c.Codec == "" {
+		return CodecJSONB
+	}
+
+	return c.Codec
+}
+
+// HasBSONColumn returns true if whole documents are stored in BSONColumn.
+func (c *Collection) HasBSONColumn() bool {
+	return c.StorageCodec() != CodecJSONB
+}
+
+// JSONBField returns true if the given top-level field is stored in DefaultColumn.
+func (c *Collection) JSONBField(field string) bool {
+	switch codec := c.StorageCodec(); codec {
+	case CodecJSONB:
+		return true
+	case CodecBSON:
+		return field == "_id"
+	case CodecHybrid:
+		return field == "_id" || slices.Contains(c.CodecFields, field)
+	default:
+		panic(fmt.Sprintf("unexpected codec %q", codec))
+	}
+}
+
+// JSONBDocument returns the part of the given document that is stored in DefaultColumn.
+func (c *Collection) JSONBDocument(doc *types.Document) *types.Document {
+	if c.StorageCodec() == CodecJSONB {
+		return doc
+	}
+
+	res := types.MakeDocument(1 + len(c.CodecFields))
+
+	for _, k := range doc.Keys() {
+		if c.JSONBField(k) && !res.Has(k) {
+			res.Set(k, must.NotFail(doc.Get(k)))
+		}
+	}
+
+	return res
+}
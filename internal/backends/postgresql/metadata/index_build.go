@@ -0,0 +1,374 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/observability"
+)
+
+// indexBuildProgressInterval is the interval between index build progress reports.
+const indexBuildProgressInterval = time.Second
+
+// indexBuild represents indexes of a single collection that are being built concurrently
+// without holding the registry lock.
+type indexBuild struct {
+	dbName         string
+	collectionName string
+	tableName      string
+	indexes        []IndexInfo   // with reserved PostgreSQL index names
+	done           chan struct{} // closed when the build is finished
+}
+
+// indexesCreateConcurrently creates indexes in the existing collection without blocking writes to the table.
+//
+// Indexes are reserved with the lock held, built without it, and then stored in the collection metadata
+// with the lock held again, so other metadata operations are not blocked by long builds.
+// Existing indexes with given names are ignored.
+// If an index with the same name is being built by another call, this call waits for that build first.
+//
+// It acquires the lock.
+func (r *Registry) indexesCreateConcurrently(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string, indexes []IndexInfo, progress func(*backends.IndexBuildProgress)) error { //nolint:lll // for readability
+	defer observability.FuncCall(ctx)()
+
+	var b *indexBuild
+
+	for {
+		r.mu.Lock()
+
+		c := r.collectionGet(dbName, collectionName)
+		if c == nil {
+			// the collection was dropped after the caller checked it, so the new table is empty
+			defer r.mu.Unlock()
+
+			return r.indexesCreate(ctx, p, dbName, collectionName, indexes, progress)
+		}
+
+		var wait chan struct{}
+		b, wait = r.reserveIndexBuild(dbName, c, indexes)
+
+		r.mu.Unlock()
+
+		if wait == nil {
+			break
+		}
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return lazyerrors.Error(context.Cause(ctx))
+		}
+	}
+
+	if b == nil {
+		return nil
+	}
+
+	built := make([]string, 0, len(b.indexes))
+
+	var err error
+
+	for _, index := range b.indexes {
+		q := createIndexQuery(dbName, b.tableName, &index, true)
+
+		if err = execIndexBuild(ctx, p, q, index.Name, progress); err != nil {
+			// failed concurrent build leaves an invalid index
+			dropPgIndex(ctx, p, dbName, index.PgIndex)
+			break
+		}
+
+		built = append(built, index.PgIndex)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.indexBuilds, b)
+	close(b.done)
+
+	if err == nil {
+		err = r.indexBuildCommit(ctx, p, b)
+	}
+
+	if err != nil {
+		for _, pgIndex := range built {
+			dropPgIndex(ctx, p, dbName, pgIndex)
+		}
+
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// reserveIndexBuild reserves PostgreSQL index names for indexes of the given collection that do not exist yet.
+//
+// It returns nil build if there is nothing to build.
+// If some of the indexes are being built already, it returns a channel to wait on before retrying.
+//
+// It should be called with the lock held.
+func (r *Registry) reserveIndexBuild(dbName string, c *Collection, indexes []IndexInfo) (*indexBuild, chan struct{}) {
+	for other := range r.indexBuilds {
+		if other.dbName != dbName || other.tableName != c.TableName {
+			continue
+		}
+
+		for _, index := range indexes {
+			if slices.ContainsFunc(other.indexes, func(i IndexInfo) bool { return i.Name == index.Name }) {
+				return nil, other.done
+			}
+		}
+	}
+
+	allPgIndexes := r.pgIndexes(dbName, r.snapshot()[dbName])
+
+	b := &indexBuild{
+		dbName:         dbName,
+		collectionName: c.Name,
+		tableName:      c.TableName,
+		done:           make(chan struct{}),
+	}
+
+	for _, index := range indexes {
+		sameName := func(i IndexInfo) bool { return i.Name == index.Name }
+		if slices.ContainsFunc(c.Indexes, sameName) || slices.ContainsFunc(b.indexes, sameName) {
+			continue
+		}
+
+		index.PgIndex = pgIndexName(c.TableName, index.Name, allPgIndexes)
+		allPgIndexes[index.PgIndex] = c.Name
+
+		b.indexes = append(b.indexes, index)
+	}
+
+	if len(b.indexes) == 0 {
+		return nil, nil
+	}
+
+	if r.indexBuilds == nil {
+		r.indexBuilds = map[*indexBuild]struct{}{}
+	}
+
+	r.indexBuilds[b] = struct{}{}
+
+	return b, nil
+}
+
+// indexBuildCommit stores built indexes in the collection metadata.
+//
+// The collection is found by its table name, so renames during the build are handled.
+//
+// It should be called with the lock held.
+func (r *Registry) indexBuildCommit(ctx context.Context, p *pgxpool.Pool, b *indexBuild) error {
+	var c *Collection
+
+	for _, coll := range r.snapshot()[b.dbName] {
+		if coll.TableName == b.tableName {
+			c = coll.deepCopy()
+			break
+		}
+	}
+
+	if c == nil {
+		return lazyerrors.Errorf("collection %s.%s was dropped during index build", b.dbName, b.collectionName)
+	}
+
+	c.Indexes = append(c.Indexes, b.indexes...)
+
+	data, err := sjson.Marshal(c.marshal())
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	arg, err := sjson.MarshalSingleValue(c.Name)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	q := fmt.Sprintf(
+		`UPDATE %s SET %s = $1 WHERE %s = $2`,
+		pgx.Identifier{b.dbName, metadataTableName}.Sanitize(),
+		DefaultColumn,
+		IDColumn,
+	)
+
+	if _, err = p.Exec(ctx, q, string(data), arg); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	r.storeCollection(b.dbName, c.Name, c)
+
+	return nil
+}
+
+// createIndexQuery returns a CREATE INDEX query for the given index of the table.
+//
+// If concurrently is true, the index is built without blocking writes to the table.
+func createIndexQuery(dbName, tableName string, index *IndexInfo, concurrently bool) string {
+	q := "CREATE "
+
+	if index.Unique {
+		q += "UNIQUE "
+	}
+
+	q += "INDEX "
+
+	if concurrently {
+		q += "CONCURRENTLY "
+	}
+
+	q += "%s ON %s (%s)"
+
+	columns := make([]string, len(index.Key))
+
+	for i, key := range index.Key {
+		// if the field is nested (e.g. foo.bar), it needs to be translated to the correct json path (foo -> bar)
+		fs := strings.Split(key.Field, ".")
+		transformedParts := make([]string, len(fs))
+
+		for j, f := range fs {
+			// It's important to sanitize field.Field data here, as it's a user-provided value.
+			transformedParts[j] = quoteString(f)
+		}
+
+		columns[i] = fmt.Sprintf("((%s->%s))", DefaultColumn, strings.Join(transformedParts, " -> "))
+		if key.Descending {
+			columns[i] += " DESC"
+		}
+	}
+
+	return fmt.Sprintf(
+		q,
+		pgx.Identifier{index.PgIndex}.Sanitize(),
+		pgx.Identifier{dbName, tableName}.Sanitize(),
+		strings.Join(columns, ", "),
+	)
+}
+
+// dropPgIndex drops the given PostgreSQL index if it exists, ignoring errors.
+//
+// It is used for cleanup after failed builds, so it is not canceled with the context.
+func dropPgIndex(ctx context.Context, p *pgxpool.Pool, dbName, pgIndex string) {
+	q := fmt.Sprintf(`DROP INDEX IF EXISTS %s`, pgx.Identifier{dbName, pgIndex}.Sanitize())
+	_, _ = p.Exec(context.WithoutCancel(ctx), q)
+}
+
+// execIndexBuild executes the given CREATE INDEX query on a dedicated connection.
+//
+// If progress is not nil, the progress of the build is periodically read from pg_stat_progress_create_index
+// and reported while the query is running.
+func execIndexBuild(ctx context.Context, p *pgxpool.Pool, q, index string, progress func(*backends.IndexBuildProgress)) error {
+	if progress == nil {
+		if _, err := p.Exec(ctx, q); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		return nil
+	}
+
+	conn, err := p.Acquire(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer conn.Release()
+
+	var pid int32
+	if err = conn.QueryRow(ctx, `SELECT pg_backend_pid()`).Scan(&pid); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	progress(&backends.IndexBuildProgress{Index: index, Phase: "initializing"})
+
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(indexBuildProgressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			if res := readIndexBuildProgress(ctx, p, pid, index); res != nil {
+				progress(res)
+			}
+		}
+	}()
+
+	_, err = conn.Exec(ctx, q)
+
+	close(done)
+	wg.Wait()
+
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// readIndexBuildProgress returns the progress of the index build running in the given PostgreSQL backend,
+// or nil if it is not available.
+//
+// Tuples are reported when their number is known; otherwise, table blocks are reported.
+func readIndexBuildProgress(ctx context.Context, p *pgxpool.Pool, pid int32, index string) *backends.IndexBuildProgress {
+	q := `SELECT phase, blocks_done, blocks_total, tuples_done, tuples_total ` +
+		`FROM pg_stat_progress_create_index WHERE pid = $1`
+
+	var phase string
+	var blocksDone, blocksTotal, tuplesDone, tuplesTotal int64
+
+	err := p.QueryRow(ctx, q, pid).Scan(&phase, &blocksDone, &blocksTotal, &tuplesDone, &tuplesTotal)
+	if err != nil {
+		// there are no rows if the build is not started yet or already finished;
+		// other errors do not affect the build itself
+		return nil
+	}
+
+	res := &backends.IndexBuildProgress{
+		Index: index,
+		Phase: phase,
+		Done:  blocksDone,
+		Total: blocksTotal,
+	}
+
+	if tuplesTotal > 0 {
+		res.Done, res.Total = tuplesDone, tuplesTotal
+	}
+
+	return res
+}
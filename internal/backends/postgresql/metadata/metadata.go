@@ -18,6 +18,7 @@ package metadata
 import (
 	"database/sql"
 	"database/sql/driver"
+	"slices"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
@@ -46,8 +47,20 @@ type Collection struct {
 	UUID            string
 	TableName       string
 	Indexes         Indexes
+	SearchIndexes   SearchIndexes
 	CappedSize      int64
 	CappedDocuments int64
+
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+
+	TimeSeries *TimeSeries
+	Clustered  bool
+
+	// Codec is empty for collections created by older versions; use StorageCodec method.
+	Codec       Codec
+	CodecFields []string
 }
 
 // deepCopy returns a deep copy.
@@ -56,14 +69,31 @@ func (c *Collection) deepCopy() *Collection {
 		return nil
 	}
 
-	return &Collection{
-		Name:            c.Name,
-		UUID:            c.UUID,
-		TableName:       c.TableName,
-		Indexes:         c.Indexes.deepCopy(),
-		CappedSize:      c.CappedSize,
-		CappedDocuments: c.CappedDocuments,
+	res := &Collection{
+		Name:             c.Name,
+		UUID:             c.UUID,
+		TableName:        c.TableName,
+		Indexes:          c.Indexes.deepCopy(),
+		SearchIndexes:    c.SearchIndexes.deepCopy(),
+		CappedSize:       c.CappedSize,
+		CappedDocuments:  c.CappedDocuments,
+		ValidationLevel:  c.ValidationLevel,
+		ValidationAction: c.ValidationAction,
+		Clustered:        c.Clustered,
+		Codec:            c.Codec,
+		CodecFields:      slices.Clone(c.CodecFields),
+	}
+
+	if c.Validator != nil {
+		res.Validator = c.Validator.DeepCopy()
 	}
+
+	if c.TimeSeries != nil {
+		ts := *c.TimeSeries
+		res.TimeSeries = &ts
+	}
+
+	return res
 }
 
 // Capped returns true if collection is capped.
@@ -111,7 +141,7 @@ func (c *Collection) Scan(src any) error {
 
 // marshal returns [*types.Document] for that collection.
 func (c *Collection) marshal() *types.Document {
-	return must.NotFail(types.NewDocument(
+	doc := must.NotFail(types.NewDocument(
 		"_id", c.Name,
 		"uuid", c.UUID,
 		"table", c.TableName,
@@ -119,6 +149,42 @@ func (c *Collection) marshal() *types.Document {
 		"cappedSize", c.CappedSize,
 		"cappedDocs", c.CappedDocuments,
 	))
+
+	if len(c.SearchIndexes) > 0 {
+		doc.Set("searchIndexes", c.SearchIndexes.marshal())
+	}
+
+	if c.Validator != nil {
+		doc.Set("validator", c.Validator)
+		doc.Set("validationLevel", c.ValidationLevel)
+		doc.Set("validationAction", c.ValidationAction)
+	}
+
+	if ts := c.TimeSeries; ts != nil {
+		doc.Set("timeseries", must.NotFail(types.NewDocument(
+			"timeField", ts.TimeField,
+			"metaField", ts.MetaField,
+			"granularity", ts.Granularity,
+			"pgindex", ts.PgIndex,
+		)))
+	}
+
+	if c.Clustered {
+		doc.Set("clustered", true)
+	}
+
+	if c.Codec != "" {
+		doc.Set("codec", string(c.Codec))
+
+		fields := types.MakeArray(len(c.CodecFields))
+		for _, f := range c.CodecFields {
+			fields.Append(f)
+		}
+
+		doc.Set("codecFields", fields)
+	}
+
+	return doc
 }
 
 // unmarshal sets collection metadata from [*types.Document].
@@ -162,6 +228,42 @@ func (c *Collection) unmarshal(doc *types.Document) error {
 		c.CappedDocuments = v.(int64)
 	}
 
+	if v, _ := doc.Get("searchIndexes"); v != nil {
+		if err := c.SearchIndexes.unmarshal(v.(*types.Array)); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	if v, _ := doc.Get("validator"); v != nil {
+		c.Validator = v.(*types.Document)
+		c.ValidationLevel, _ = must.NotFail(doc.Get("validationLevel")).(string)
+		c.ValidationAction, _ = must.NotFail(doc.Get("validationAction")).(string)
+	}
+
+	if v, _ := doc.Get("timeseries"); v != nil {
+		ts := v.(*types.Document)
+
+		c.TimeSeries = &TimeSeries{
+			TimeField:   must.NotFail(ts.Get("timeField")).(string),
+			MetaField:   must.NotFail(ts.Get("metaField")).(string),
+			Granularity: must.NotFail(ts.Get("granularity")).(string),
+			PgIndex:     must.NotFail(ts.Get("pgindex")).(string),
+		}
+	}
+
+	if v, _ := doc.Get("clustered"); v != nil {
+		c.Clustered = v.(bool)
+	}
+
+	if v, _ := doc.Get("codec"); v != nil {
+		c.Codec = Codec(v.(string))
+
+		fields := must.NotFail(doc.Get("codecFields")).(*types.Array)
+		for i := 0; i < fields.Len(); i++ {
+			c.CodecFields = append(c.CodecFields, must.NotFail(fields.Get(i)).(string))
+		}
+	}
+
 	return nil
 }
 
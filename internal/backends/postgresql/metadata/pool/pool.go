@@ -88,6 +88,11 @@ func (p *Pool) Close() {
 	resource.Untrack(p, p.token)
 }
 
+// BaseUsername returns the username of the base URI, or empty string if it is not set.
+func (p *Pool) BaseUsername() string {
+	return p.baseURI.User.Username()
+}
+
 // Get returns a pool of connections to PostgreSQL database for that username/password combination.
 func (p *Pool) Get(username, password string) (*pgxpool.Pool, error) {
 	// do not log password or full URL
@@ -170,9 +175,33 @@ func (p *Pool) Collect(ch chan<- prometheus.Metric) {
 		float64(len(p.pools)),
 	)
 
+	// pools are labeled by username, so resources used by different users (tenants) can be tracked
+	connsDesc := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "connections"),
+		"The current number of connections in the pool.",
+		[]string{"username", "state"}, nil,
+	)
+	acquiresDesc := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "acquires_total"),
+		"The total number of successful connection acquires from the pool.",
+		[]string{"username"}, nil,
+	)
+	acquireDurationDesc := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "acquire_duration_seconds_total"),
+		"The total time spent on successful connection acquires from the pool.",
+		[]string{"username"}, nil,
+	)
+
 	for _, pool := range p.pools {
-		// TODO https://github.com/FerretDB/FerretDB/issues/3332
-		_ = pool.Stat()
+		username := pool.Config().ConnConfig.User
+		stat := pool.Stat()
+
+		ch <- prometheus.MustNewConstMetric(connsDesc, prometheus.GaugeValue, float64(stat.AcquiredConns()), username, "acquired")
+		ch <- prometheus.MustNewConstMetric(connsDesc, prometheus.GaugeValue, float64(stat.IdleConns()), username, "idle")
+		ch <- prometheus.MustNewConstMetric(acquiresDesc, prometheus.CounterValue, float64(stat.AcquireCount()), username)
+		ch <- prometheus.MustNewConstMetric(
+			acquireDurationDesc, prometheus.CounterValue, stat.AcquireDuration().Seconds(), username,
+		)
 	}
 }
 
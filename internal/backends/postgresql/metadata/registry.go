@@ -16,6 +16,7 @@ package metadata
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"regexp"
@@ -23,9 +24,12 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -35,6 +39,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata/pool"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/observability"
@@ -58,6 +63,9 @@ const (
 	subsystem = "postgresql_metadata"
 )
 
+// maxVectorIndexDimensions is the maximal number of dimensions supported by pgvector indexes.
+const maxVectorIndexDimensions = 2000
+
 // specialCharacters are unsupported characters of PostgreSQL table name that are replaced with `_`.
 var specialCharacters = regexp.MustCompile("[^a-z][^a-z0-9_]*")
 
@@ -67,7 +75,8 @@ var specialCharacters = regexp.MustCompile("[^a-z][^a-z0-9_]*")
 //
 // All methods should call [getPool] to check authentication.
 // There is no authorization yet – if username/password combination is correct,
-// all databases and collections are visible as far as Registry is concerned.
+// all databases and collections are visible as far as Registry is concerned,
+// unless tenant isolation mode is enabled (see tenant.go).
 //
 // Registry metadata is loaded upon first call by client, using [conninfo] in the context of the client.
 //
@@ -76,25 +85,48 @@ type Registry struct {
 	p *pool.Pool
 	l *zap.Logger
 
-	// rw protects colls but also acts like a global lock for the whole registry.
-	// The latter effectively replaces transactions (see the postgresql backend package description for more info).
+	// mu serializes metadata modifications and acts like a global lock for all of them.
+	// That effectively replaces transactions (see the postgresql backend package description for more info).
 	// One global lock should be replaced by more granular locks – one per database or even one per collection.
 	// But that requires some redesign.
 	// TODO https://github.com/FerretDB/FerretDB/issues/2755
-	rw    sync.RWMutex
-	colls map[string]map[string]*Collection // database name -> collection name -> collection
+	mu sync.Mutex
+
+	// colls is an immutable snapshot of database name -> collection name -> collection mapping.
+	// Readers load it without locking; writers hold mu, copy the affected maps, and store the new snapshot.
+	// It is nil until metadata is loaded.
+	colls atomic.Pointer[map[string]map[string]*Collection]
+
+	// cacheHits and cacheMisses count collection lookups in the snapshot.
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
+
+	tenantIsolation bool
+	adminRole       string // the role of the base URI
+
+	// owners is an immutable snapshot of database name -> owner role mapping, like colls.
+	// It is maintained only in tenant isolation mode.
+	owners atomic.Pointer[map[string]string]
+
+	// indexBuilds contains indexes that are being built concurrently without holding mu.
+	// It is protected by mu.
+	indexBuilds map[*indexBuild]struct{}
 }
 
 // NewRegistry creates a registry for PostgreSQL databases with a given base URI.
-func NewRegistry(u string, l *zap.Logger, sp *state.Provider) (*Registry, error) {
+//
+// If tenantIsolation is true, databases of different PostgreSQL roles are isolated from each other.
+func NewRegistry(u string, tenantIsolation bool, l *zap.Logger, sp *state.Provider) (*Registry, error) {
 	p, err := pool.New(u, l, sp)
 	if err != nil {
 		return nil, err
 	}
 
 	r := &Registry{
-		p: p,
-		l: l,
+		p:               p,
+		l:               l,
+		tenantIsolation: tenantIsolation,
+		adminRole:       p.BaseUsername(),
 	}
 
 	return r, nil
@@ -111,20 +143,33 @@ func (r *Registry) Close() {
 //
 // It loads metadata if it hasn't been loaded from the database yet.
 //
-// It acquires read lock to check metadata, if metadata is empty it acquires write lock
-// to load metadata, so it is safe for concurrent use.
+// It checks metadata without locking, if metadata is not loaded it acquires the lock
+// to load it, so it is safe for concurrent use.
 //
 // All methods should use this method to check authentication and load metadata.
 func (r *Registry) getPool(ctx context.Context) (*pgxpool.Pool, error) {
 	connInfo := conninfo.Get(ctx)
 
+	if err := r.checkTenant(ctx); err != nil {
+		return nil, err
+	}
+
 	var p *pgxpool.Pool
 
-	if connInfo.BypassBackendAuth() {
+	switch {
+	case connInfo.BypassBackendAuth() && r.tenantIsolation:
+		// any pool could belong to some tenant, so the administrator's one is used
+		var err error
+		if p, err = r.p.Get("", ""); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+	case connInfo.BypassBackendAuth():
 		if p = r.p.GetAny(); p == nil {
 			return nil, lazyerrors.New("no connection pool")
 		}
-	} else {
+
+	default:
 		username, password := connInfo.Auth()
 
 		var err error
@@ -133,28 +178,50 @@ func (r *Registry) getPool(ctx context.Context) (*pgxpool.Pool, error) {
 		}
 	}
 
-	r.rw.RLock()
-	if r.colls != nil {
-		r.rw.RUnlock()
+	if r.colls.Load() != nil {
 		return p, nil
 	}
-	r.rw.RUnlock()
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	dbNames, err := r.initDBs(ctx, p)
+	// metadata could be loaded while we were waiting for the lock
+	if r.colls.Load() != nil {
+		return p, nil
+	}
+
+	// in tenant isolation mode, metadata of all tenants is loaded by the administrator
+	initPool := p
+	if r.tenantIsolation {
+		var err error
+		if initPool, err = r.p.Get("", ""); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	dbNames, err := r.initDBs(ctx, initPool)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.colls = make(map[string]map[string]*Collection, len(dbNames))
+	colls := make(map[string]map[string]*Collection, len(dbNames))
 	for _, dbName := range dbNames {
-		if err = r.initCollections(ctx, dbName, p); err != nil {
+		if colls[dbName], err = r.initCollections(ctx, dbName, initPool); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	if r.tenantIsolation {
+		var owners map[string]string
+		if owners, err = r.initOwners(ctx, initPool, dbNames); err != nil {
 			return nil, lazyerrors.Error(err)
 		}
+
+		r.owners.Store(&owners)
 	}
 
+	r.colls.Store(&colls)
+
 	return p, nil
 }
 
@@ -213,7 +280,7 @@ func (r *Registry) initDBs(ctx context.Context, p *pgxpool.Pool) ([]string, erro
 }
 
 // initCollections loads collections metadata from the database during initialization.
-func (r *Registry) initCollections(ctx context.Context, dbName string, p *pgxpool.Pool) error {
+func (r *Registry) initCollections(ctx context.Context, dbName string, p *pgxpool.Pool) (map[string]*Collection, error) {
 	defer observability.FuncCall(ctx)()
 
 	q := fmt.Sprintf(
@@ -224,7 +291,7 @@ func (r *Registry) initCollections(ctx context.Context, dbName string, p *pgxpoo
 
 	rows, err := p.Query(ctx, q)
 	if err != nil {
-		return lazyerrors.Error(err)
+		return nil, lazyerrors.Error(err)
 	}
 	defer rows.Close()
 
@@ -233,19 +300,17 @@ func (r *Registry) initCollections(ctx context.Context, dbName string, p *pgxpoo
 	for rows.Next() {
 		var c Collection
 		if err = rows.Scan(&c); err != nil {
-			return lazyerrors.Error(err)
+			return nil, lazyerrors.Error(err)
 		}
 
 		colls[c.Name] = &c
 	}
 
 	if err = rows.Err(); err != nil {
-		return lazyerrors.Error(err)
+		return nil, lazyerrors.Error(err)
 	}
 
-	r.colls[dbName] = colls
-
-	return nil
+	return colls, nil
 }
 
 // DatabaseList returns a sorted list of existing databases.
@@ -259,10 +324,8 @@ func (r *Registry) DatabaseList(ctx context.Context) ([]string, error) {
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
-
-	res := maps.Keys(r.colls)
+	res := maps.Keys(r.snapshot())
+	res = slices.DeleteFunc(res, func(dbName string) bool { return !r.owned(ctx, dbName) })
 	sort.Strings(res)
 
 	return res, nil
@@ -279,11 +342,8 @@ func (r *Registry) DatabaseGetExisting(ctx context.Context, dbName string) (*pgx
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
-
-	db := r.colls[dbName]
-	if db == nil {
+	db := r.snapshot()[dbName]
+	if db == nil || !r.owned(ctx, dbName) {
 		return nil, nil
 	}
 
@@ -303,8 +363,8 @@ func (r *Registry) DatabaseGetOrCreate(ctx context.Context, dbName string) (*pgx
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.databaseGetOrCreate(ctx, p, dbName)
 }
@@ -317,8 +377,12 @@ func (r *Registry) DatabaseGetOrCreate(ctx context.Context, dbName string) (*pgx
 func (r *Registry) databaseGetOrCreate(ctx context.Context, p *pgxpool.Pool, dbName string) (*pgxpool.Pool, error) {
 	defer observability.FuncCall(ctx)()
 
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db != nil {
+		if !r.owned(ctx, dbName) {
+			return nil, errNotOwned(dbName)
+		}
+
 		return p, nil
 	}
 
@@ -367,7 +431,14 @@ func (r *Registry) databaseGetOrCreate(ctx context.Context, p *pgxpool.Pool, dbN
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.colls[dbName] = map[string]*Collection{}
+	if r.tenantIsolation {
+		if err = r.initTenantDatabase(ctx, p, dbName); err != nil {
+			_, _ = r.databaseDrop(ctx, p, dbName)
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	r.storeDatabase(dbName, map[string]*Collection{})
 
 	return p, nil
 }
@@ -386,8 +457,12 @@ func (r *Registry) DatabaseDrop(ctx context.Context, dbName string) (bool, error
 		return false, lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	if !r.owned(ctx, dbName) {
+		return false, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.databaseDrop(ctx, p, dbName)
 }
@@ -401,7 +476,7 @@ func (r *Registry) DatabaseDrop(ctx context.Context, dbName string) (bool, error
 func (r *Registry) databaseDrop(ctx context.Context, p *pgxpool.Pool, dbName string) (bool, error) {
 	defer observability.FuncCall(ctx)()
 
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db == nil {
 		return false, nil
 	}
@@ -415,7 +490,11 @@ func (r *Registry) databaseDrop(ctx context.Context, p *pgxpool.Pool, dbName str
 		return false, lazyerrors.Error(err)
 	}
 
-	delete(r.colls, dbName)
+	r.storeDatabase(dbName, nil)
+
+	if r.tenantIsolation {
+		r.storeOwner(dbName, "")
+	}
 
 	return true, nil
 }
@@ -432,16 +511,17 @@ func (r *Registry) CollectionList(ctx context.Context, dbName string) ([]*Collec
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
+	if !r.owned(ctx, dbName) {
+		return nil, nil
+	}
 
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db == nil {
 		return nil, nil
 	}
 
-	res := make([]*Collection, 0, len(r.colls[dbName]))
-	for _, c := range r.colls[dbName] {
+	res := make([]*Collection, 0, len(db))
+	for _, c := range db {
 		res = append(res, c.deepCopy())
 	}
 
@@ -456,7 +536,19 @@ type CollectionCreateParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+
+	TimeSeries *TimeSeries
+	Clustered  bool
+
+	Codec       Codec // empty value means CodecJSONB
+	CodecFields []string
+
+	_ struct {
+	} // prevent unkeyed literals
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -479,8 +571,17 @@ func (r *Registry) CollectionCreate(ctx context.Context, params *CollectionCreat
 		return false, lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	if !r.owned(ctx, params.DBName) {
+		return false, errNotOwned(params.DBName)
+	}
+
+	// fast path for the common case of inserting into an existing collection
+	if r.lookup(params.DBName, params.Name) != nil {
+		return false, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.collectionCreate(ctx, p, params)
 }
@@ -502,7 +603,7 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, params
 		return false, lazyerrors.Error(err)
 	}
 
-	colls := r.colls[dbName]
+	colls := r.snapshot()[dbName]
 	if colls != nil && colls[collectionName] != nil {
 		return false, nil
 	}
@@ -533,11 +634,28 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, params
 	}
 
 	c := &Collection{
-		Name:            collectionName,
-		UUID:            uuid.NewString(),
-		TableName:       tableName,
-		CappedSize:      params.CappedSize,
-		CappedDocuments: params.CappedDocuments,
+		Name:             collectionName,
+		UUID:             uuid.NewString(),
+		TableName:        tableName,
+		CappedSize:       params.CappedSize,
+		CappedDocuments:  params.CappedDocuments,
+		Validator:        params.Validator,
+		ValidationLevel:  params.ValidationLevel,
+		ValidationAction: params.ValidationAction,
+		Clustered:        params.Clustered,
+	}
+
+	// keep metadata of jsonb collections the same as in older versions
+	if params.Codec != "" && params.Codec != CodecJSONB {
+		if !slices.Contains(Codecs, params.Codec) {
+			return false, lazyerrors.Errorf("unexpected codec %q", params.Codec)
+		}
+
+		c.Codec = params.Codec
+
+		if params.Codec == CodecHybrid {
+			c.CodecFields = slices.Clone(params.CodecFields)
+		}
 	}
 
 	q := fmt.Sprintf(`CREATE TABLE %s (`, pgx.Identifier{dbName, tableName}.Sanitize())
@@ -546,12 +664,41 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, params
 		q += fmt.Sprintf(`%s bigint PRIMARY KEY, `, RecordIDColumn)
 	}
 
-	q += fmt.Sprintf(`%s jsonb)`, DefaultColumn)
+	q += fmt.Sprintf(`%s jsonb`, DefaultColumn)
+
+	if c.HasBSONColumn() {
+		q += fmt.Sprintf(`, %s bytea`, BSONColumn)
+	}
+
+	q += `)`
 
 	if _, err = p.Exec(ctx, q); err != nil {
 		return false, lazyerrors.Error(err)
 	}
 
+	// BRIN index keeps the table efficiently scannable by time ranges
+	// as time-series data is usually inserted in the time order
+	if params.TimeSeries != nil {
+		ts := *params.TimeSeries
+		ts.PgIndex = pgIndexName(tableName, "timeseries_"+ts.TimeField, r.pgIndexes(dbName, colls))
+
+		q = fmt.Sprintf(
+			`CREATE INDEX %s ON %s USING brin ((%s))`,
+			pgx.Identifier{ts.PgIndex}.Sanitize(),
+			pgx.Identifier{dbName, tableName}.Sanitize(),
+			TimeSeriesExpression(ts.TimeField),
+		)
+
+		if _, err = p.Exec(ctx, q); err != nil {
+			q = fmt.Sprintf(`DROP TABLE %s`, pgx.Identifier{dbName, tableName}.Sanitize())
+			_, _ = p.Exec(ctx, q)
+
+			return false, lazyerrors.Error(err)
+		}
+
+		c.TimeSeries = &ts
+	}
+
 	q = fmt.Sprintf(
 		`INSERT INTO %s (%s) VALUES ($1)`,
 		pgx.Identifier{dbName, metadataTableName}.Sanitize(),
@@ -564,21 +711,32 @@ func (r *Registry) collectionCreate(ctx context.Context, p *pgxpool.Pool, params
 		return false, lazyerrors.Error(err)
 	}
 
-	if r.colls[dbName] == nil {
-		r.colls[dbName] = map[string]*Collection{}
-	}
-	r.colls[dbName][collectionName] = c
+	r.storeCollection(dbName, collectionName, c)
 
 	err = r.indexesCreate(ctx, p, dbName, collectionName, []IndexInfo{{
 		Name:   "_id_",
 		Key:    []IndexKeyPair{{Field: "_id"}},
 		Unique: true,
-	}})
+	}}, nil)
 	if err != nil {
 		_, _ = r.collectionDrop(ctx, p, dbName, collectionName)
 		return false, lazyerrors.Error(err)
 	}
 
+	// the table of clustered collection is ordered by _id index on CLUSTER
+	if params.Clustered {
+		q = fmt.Sprintf(
+			`ALTER TABLE %s CLUSTER ON %s`,
+			pgx.Identifier{dbName, tableName}.Sanitize(),
+			pgx.Identifier{r.collectionGet(dbName, collectionName).Indexes[0].PgIndex}.Sanitize(),
+		)
+
+		if _, err = p.Exec(ctx, q); err != nil {
+			_, _ = r.collectionDrop(ctx, p, dbName, collectionName)
+			return false, lazyerrors.Error(err)
+		}
+	}
+
 	return true, nil
 }
 
@@ -595,10 +753,11 @@ func (r *Registry) CollectionGet(ctx context.Context, dbName, collectionName str
 		return nil, lazyerrors.Error(err)
 	}
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
+	if !r.owned(ctx, dbName) {
+		return nil, nil
+	}
 
-	return r.collectionGet(dbName, collectionName), nil
+	return r.lookup(dbName, collectionName).deepCopy(), nil
 }
 
 // collectionGet returns a copy of collection metadata.
@@ -608,7 +767,7 @@ func (r *Registry) CollectionGet(ctx context.Context, dbName, collectionName str
 //
 // It does not hold the lock.
 func (r *Registry) collectionGet(dbName, collectionName string) *Collection {
-	colls := r.colls[dbName]
+	colls := r.snapshot()[dbName]
 	if colls == nil {
 		return nil
 	}
@@ -616,6 +775,72 @@ func (r *Registry) collectionGet(dbName, collectionName string) *Collection {
 	return colls[collectionName].deepCopy()
 }
 
+// snapshot returns the current snapshot of collections metadata.
+//
+// It does not hold the lock. Returned maps must not be modified.
+func (r *Registry) snapshot() map[string]map[string]*Collection {
+	if colls := r.colls.Load(); colls != nil {
+		return *colls
+	}
+
+	return nil
+}
+
+// lookup returns collection metadata from the snapshot and counts cache hit or miss.
+// If database or collection does not exist, nil is returned.
+//
+// It does not hold the lock. Returned collection must not be modified.
+func (r *Registry) lookup(dbName, collectionName string) *Collection {
+	c := r.snapshot()[dbName][collectionName]
+	if c == nil {
+		r.cacheMisses.Add(1)
+		return nil
+	}
+
+	r.cacheHits.Add(1)
+
+	return c
+}
+
+// storeDatabase stores a new snapshot with the given database collections.
+// If colls is nil, the database is removed from the snapshot.
+//
+// It should be called with the lock held.
+func (r *Registry) storeDatabase(dbName string, colls map[string]*Collection) {
+	old := r.snapshot()
+
+	res := make(map[string]map[string]*Collection, len(old)+1)
+	maps.Copy(res, old)
+
+	if colls == nil {
+		delete(res, dbName)
+	} else {
+		res[dbName] = colls
+	}
+
+	r.colls.Store(&res)
+}
+
+// storeCollection stores a new snapshot with the given collection.
+// If c is nil, the collection is removed from the snapshot.
+// The database is added to the snapshot if needed.
+//
+// It should be called with the lock held.
+func (r *Registry) storeCollection(dbName, collectionName string, c *Collection) {
+	old := r.snapshot()[dbName]
+
+	colls := make(map[string]*Collection, len(old)+1)
+	maps.Copy(colls, old)
+
+	if c == nil {
+		delete(colls, collectionName)
+	} else {
+		colls[collectionName] = c
+	}
+
+	r.storeDatabase(dbName, colls)
+}
+
 // CollectionDrop drops a collection in the database.
 //
 // Returned boolean value indicates whether the collection was dropped.
@@ -630,8 +855,12 @@ func (r *Registry) CollectionDrop(ctx context.Context, dbName, collectionName st
 		return false, lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	if !r.owned(ctx, dbName) {
+		return false, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.collectionDrop(ctx, p, dbName, collectionName)
 }
@@ -645,7 +874,7 @@ func (r *Registry) CollectionDrop(ctx context.Context, dbName, collectionName st
 func (r *Registry) collectionDrop(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string) (bool, error) {
 	defer observability.FuncCall(ctx)()
 
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db == nil {
 		return false, nil
 	}
@@ -681,7 +910,7 @@ func (r *Registry) collectionDrop(ctx context.Context, p *pgxpool.Pool, dbName,
 		return false, lazyerrors.Error(err)
 	}
 
-	delete(r.colls[dbName], collectionName)
+	r.storeCollection(dbName, collectionName, nil)
 
 	return true, nil
 }
@@ -702,10 +931,14 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 		return false, lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	if !r.owned(ctx, dbName) {
+		return false, nil
+	}
 
-	db := r.colls[dbName]
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	db := r.snapshot()[dbName]
 	if db == nil {
 		return false, nil
 	}
@@ -738,8 +971,8 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 		return false, lazyerrors.Error(err)
 	}
 
-	r.colls[dbName][newCollectionName] = c
-	delete(r.colls[dbName], oldCollectionName)
+	r.storeCollection(dbName, newCollectionName, c)
+	r.storeCollection(dbName, oldCollectionName, nil)
 
 	return true, nil
 }
@@ -749,7 +982,7 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 // Existing indexes with given names are ignored.
 //
 // If the user is not authenticated, it returns error.
-func (r *Registry) IndexesCreate(ctx context.Context, dbName, collectionName string, indexes []IndexInfo) error {
+func (r *Registry) IndexesCreate(ctx context.Context, dbName, collectionName string, indexes []IndexInfo, progress func(*backends.IndexBuildProgress)) error { //nolint:lll // for readability
 	defer observability.FuncCall(ctx)()
 
 	p, err := r.getPool(ctx)
@@ -757,18 +990,34 @@ func (r *Registry) IndexesCreate(ctx context.Context, dbName, collectionName str
 		return lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	if !r.owned(ctx, dbName) {
+		return errNotOwned(dbName)
+	}
+
+	r.mu.Lock()
+
+	// indexes of a new collection are built on an empty table, so concurrent builds are not needed
+	if r.snapshot()[dbName][collectionName] == nil {
+		defer r.mu.Unlock()
+
+		return r.indexesCreate(ctx, p, dbName, collectionName, indexes, progress)
+	}
+
+	r.mu.Unlock()
 
-	return r.indexesCreate(ctx, p, dbName, collectionName, indexes)
+	return r.indexesCreateConcurrently(ctx, p, dbName, collectionName, indexes, progress)
 }
 
 // indexesCreate creates indexes in the collection.
 //
 // Existing indexes with given names are ignored.
 //
+// Indexes are built with the lock held and writes to the table blocked,
+// so it should be used only for new tables; see indexesCreateConcurrently for existing ones.
+// Progress, if not nil, is called periodically while indexes are built.
+//
 // It does not hold the lock.
-func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string, indexes []IndexInfo) error {
+func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string, indexes []IndexInfo, progress func(*backends.IndexBuildProgress)) error { //nolint:lll // for readability
 	defer observability.FuncCall(ctx)()
 
 	_, err := r.collectionCreate(ctx, p, &CollectionCreateParams{DBName: dbName, Name: collectionName})
@@ -776,7 +1025,7 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 		return lazyerrors.Error(err)
 	}
 
-	db := r.colls[dbName]
+	db := r.snapshot()[dbName]
 	if db == nil {
 		panic("database does not exist")
 	}
@@ -786,16 +1035,17 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 		panic("collection does not exist")
 	}
 
-	allIndexes := make(map[string]string, len(db))   // to check if the index already exists
-	allPgIndexes := make(map[string]string, len(db)) // to ensure there are no indexes with the same name in the pg schema
+	allIndexes := make(map[string]string, len(db)) // to check if the index already exists
 
 	for _, coll := range db {
 		for _, index := range coll.Indexes {
 			allIndexes[index.Name] = coll.Name
-			allPgIndexes[index.PgIndex] = coll.Name
 		}
 	}
 
+	// to ensure there are no indexes with the same name in the pg schema
+	allPgIndexes := r.pgIndexes(dbName, db)
+
 	created := make([]string, 0, len(indexes))
 
 	for _, index := range indexes {
@@ -803,83 +1053,245 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 			continue
 		}
 
-		tableNamePart := c.TableName
-		tableNamePartMax := maxIndexNameLength/2 - 1 // 1 for the separator between table name and index name
+		index.PgIndex = pgIndexName(c.TableName, index.Name, allPgIndexes)
+
+		q := createIndexQuery(dbName, c.TableName, &index, false)
+
+		if err = execIndexBuild(ctx, p, q, index.Name, progress); err != nil {
+
+			_ = r.indexesDrop(ctx, p, dbName, collectionName, created)
 
-		if len(tableNamePart) > tableNamePartMax {
-			tableNamePart = tableNamePart[:tableNamePartMax]
+			return lazyerrors.Error(err)
 		}
 
-		indexNamePart := specialCharacters.ReplaceAllString(strings.ToLower(index.Name), "_")
+		created = append(created, index.Name)
+		c.Indexes = append(c.Indexes, index)
+		allIndexes[index.Name] = collectionName
+		allPgIndexes[index.PgIndex] = collectionName
+	}
 
-		h := fnv.New32a()
-		must.NotFail(h.Write([]byte(index.Name)))
-		s := h.Sum32()
+	b, err := sjson.Marshal(c.marshal())
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
 
-		var pgIndexName string
+	arg, err := sjson.MarshalSingleValue(collectionName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
 
-		for {
-			suffixHash := fmt.Sprintf("_%08x_idx", s)
-			if l := maxIndexNameLength/2 - len(suffixHash); len(indexNamePart) > l {
-				indexNamePart = indexNamePart[:l]
-			}
+	q := fmt.Sprintf(
+		`UPDATE %s SET %s = $1 WHERE %s = $2`,
+		pgx.Identifier{dbName, metadataTableName}.Sanitize(),
+		DefaultColumn,
+		IDColumn,
+	)
 
-			pgIndexName = fmt.Sprintf("%s_%s%s", tableNamePart, indexNamePart, suffixHash)
+	if _, err := p.Exec(ctx, q, string(b), arg); err != nil {
+		return lazyerrors.Error(err)
+	}
 
-			// indexes must be unique across the whole database, so we check for duplicates for all collections
-			_, duplicate := allPgIndexes[pgIndexName]
+	r.storeCollection(dbName, collectionName, c)
 
-			if !duplicate {
-				break
-			}
+	return nil
+}
+
+// IndexesDrop removes given connection's indexes.
+//
+// Non-existing indexes are ignored.
+//
+// If database or collection does not exist, nil is returned.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) IndexesDrop(ctx context.Context, dbName, collectionName string, indexNames []string) error {
+	defer observability.FuncCall(ctx)()
+
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !r.owned(ctx, dbName) {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-			s++
+	return r.indexesDrop(ctx, p, dbName, collectionName, indexNames)
+}
+
+// indexesDrop removes given connection's indexes.
+//
+// Non-existing indexes are ignored.
+//
+// If database or collection does not exist, nil is returned.
+//
+// It does not hold the lock.
+func (r *Registry) indexesDrop(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string, indexNames []string) error {
+	defer observability.FuncCall(ctx)()
+
+	c := r.collectionGet(dbName, collectionName)
+	if c == nil {
+		return nil
+	}
+
+	for _, name := range indexNames {
+		i := slices.IndexFunc(c.Indexes, func(i IndexInfo) bool { return name == i.Name })
+		if i < 0 {
+			continue
 		}
 
-		index.PgIndex = pgIndexName
+		q := fmt.Sprintf("DROP INDEX %s", pgx.Identifier{dbName, c.Indexes[i].PgIndex}.Sanitize())
+		if _, err := p.Exec(ctx, q); err != nil {
+			return lazyerrors.Error(err)
+		}
 
-		q := "CREATE "
+		c.Indexes = slices.Delete(c.Indexes, i, i+1)
+	}
+
+	b, err := sjson.Marshal(c.marshal())
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	arg, err := sjson.MarshalSingleValue(collectionName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	q := fmt.Sprintf(
+		`UPDATE %s SET %s = $1 WHERE %s = $2`,
+		pgx.Identifier{dbName, metadataTableName}.Sanitize(),
+		DefaultColumn,
+		IDColumn,
+	)
+
+	if _, err := p.Exec(ctx, q, string(b), arg); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	r.storeCollection(dbName, collectionName, c)
+
+	return nil
+}
 
-		if index.Unique {
-			q += "UNIQUE "
+// SearchIndexesCreate creates search indexes in the collection.
+//
+// Existing search indexes with given names are ignored.
+//
+// If the user is not authenticated, it returns error.
+func (r *Registry) SearchIndexesCreate(ctx context.Context, dbName, collectionName string, indexes []SearchIndexInfo) error {
+	defer observability.FuncCall(ctx)()
+
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !r.owned(ctx, dbName) {
+		return errNotOwned(dbName)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.searchIndexesCreate(ctx, p, dbName, collectionName, indexes)
+}
+
+// searchIndexesCreate creates search indexes in the collection.
+//
+// Existing search indexes with given names are ignored.
+//
+// It does not hold the lock.
+func (r *Registry) searchIndexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string, indexes []SearchIndexInfo) error { //nolint:lll // for readability
+	defer observability.FuncCall(ctx)()
+
+	_, err := r.collectionCreate(ctx, p, &CollectionCreateParams{DBName: dbName, Name: collectionName})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	db := r.snapshot()[dbName]
+	if db == nil {
+		panic("database does not exist")
+	}
+
+	c := r.collectionGet(dbName, collectionName)
+	if c == nil {
+		panic("collection does not exist")
+	}
+
+	// to ensure there are no indexes with the same name in the pg schema
+	allPgIndexes := r.pgIndexes(dbName, db)
+
+	var created []string
+
+	for _, index := range indexes {
+		if slices.ContainsFunc(c.SearchIndexes, func(i SearchIndexInfo) bool { return i.Name == index.Name }) {
+			continue
 		}
 
-		q += "INDEX %s ON %s (%s)"
+		// columns are PostgreSQL index definitions, each of them is created as a separate index
+		var columns []string
 
-		columns := make([]string, len(index.Key))
+		if index.Dynamic {
+			columns = append(columns, fmt.Sprintf("GIN ((%s))", SearchDynamicExpression()))
+		}
+
+		for _, f := range index.Fields {
+			if f.Autocomplete {
+				if _, err = p.Exec(ctx, "CREATE EXTENSION IF NOT EXISTS pg_trgm"); err != nil {
+					_ = r.searchIndexesDrop(ctx, p, dbName, collectionName, created)
+					return lazyerrors.Error(err)
+				}
 
-		for i, key := range index.Key {
-			// if the field is nested (e.g. foo.bar), it needs to be translated to the correct json path (foo -> bar)
-			fs := strings.Split(key.Field, ".")
-			transformedParts := make([]string, len(fs))
+				columns = append(columns, fmt.Sprintf("GIN (%s gin_trgm_ops)", SearchAutocompleteExpression(f.Path)))
 
-			for j, f := range fs {
-				// It's important to sanitize field.Field data here, as it's a user-provided value.
-				transformedParts[j] = quoteString(f)
+				continue
 			}
 
-			columns[i] = fmt.Sprintf("((%s->%s))", DefaultColumn, strings.Join(transformedParts, " -> "))
-			if key.Descending {
-				columns[i] += " DESC"
+			columns = append(columns, fmt.Sprintf("GIN ((%s))", SearchTextExpression(f.Path)))
+		}
+
+		index.PgIndexes = make([]string, len(columns), len(columns)+len(index.Vectors))
+
+		for i, column := range columns {
+			pgIndex := pgIndexName(c.TableName, fmt.Sprintf("%s_search_%d", index.Name, i), allPgIndexes)
+
+			q := fmt.Sprintf(
+				"CREATE INDEX %s ON %s USING %s",
+				pgx.Identifier{pgIndex}.Sanitize(),
+				pgx.Identifier{dbName, c.TableName}.Sanitize(),
+				column,
+			)
+
+			if _, err = p.Exec(ctx, q); err != nil {
+				for _, pgIndex := range index.PgIndexes[:i] {
+					_, _ = p.Exec(ctx, fmt.Sprintf("DROP INDEX %s", pgx.Identifier{dbName, pgIndex}.Sanitize()))
+				}
+
+				_ = r.searchIndexesDrop(ctx, p, dbName, collectionName, created)
+
+				return lazyerrors.Error(err)
 			}
+
+			index.PgIndexes[i] = pgIndex
+			allPgIndexes[pgIndex] = collectionName
 		}
 
-		q = fmt.Sprintf(
-			q,
-			pgx.Identifier{index.PgIndex}.Sanitize(),
-			pgx.Identifier{dbName, c.TableName}.Sanitize(),
-			strings.Join(columns, ", "),
-		)
+		if err = r.vectorIndexesCreate(ctx, p, dbName, c, &index, allPgIndexes); err != nil {
+			for _, pgIndex := range index.PgIndexes {
+				_, _ = p.Exec(ctx, fmt.Sprintf("DROP INDEX %s", pgx.Identifier{dbName, pgIndex}.Sanitize()))
+			}
+
+			_ = r.searchIndexesDrop(ctx, p, dbName, collectionName, created)
 
-		if _, err = p.Exec(ctx, q); err != nil {
-			_ = r.indexesDrop(ctx, p, dbName, collectionName, created)
 			return lazyerrors.Error(err)
 		}
 
 		created = append(created, index.Name)
-		c.Indexes = append(c.Indexes, index)
-		allIndexes[index.Name] = collectionName
-		allPgIndexes[index.PgIndex] = collectionName
+		c.SearchIndexes = append(c.SearchIndexes, index)
 	}
 
 	b, err := sjson.Marshal(c.marshal())
@@ -903,19 +1315,95 @@ func (r *Registry) indexesCreate(ctx context.Context, p *pgxpool.Pool, dbName, c
 		return lazyerrors.Error(err)
 	}
 
-	r.colls[dbName][collectionName] = c
+	r.storeCollection(dbName, collectionName, c)
 
 	return nil
 }
 
-// IndexesDrop removes given connection's indexes.
+// vectorIndexesCreate creates pgvector indexes for vector fields of the given search index,
+// and adds their names to index.PgIndexes and allPgIndexes.
 //
-// Non-existing indexes are ignored.
+// If pgvector is not available, or the number of dimensions is too large, vector fields are not indexed;
+// that's not an error.
+//
+// It does not hold the lock.
+func (r *Registry) vectorIndexesCreate(ctx context.Context, p *pgxpool.Pool, dbName string, c *Collection, index *SearchIndexInfo, allPgIndexes map[string]string) error { //nolint:lll // for readability
+	if len(index.Vectors) == 0 {
+		return nil
+	}
+
+	index.Vectors = slices.Clone(index.Vectors)
+
+	if _, err := p.Exec(ctx, "CREATE EXTENSION IF NOT EXISTS vector"); err != nil {
+		r.l.Warn("pgvector is not available, vector search will not use indexes", zap.Error(err))
+		return nil
+	}
+
+	// the function should not fail for invalid values (like MongoDB Atlas, that just does not index them),
+	// otherwise documents with them could not be inserted
+	q := fmt.Sprintf(
+		`CREATE OR REPLACE FUNCTION %s(v jsonb, dims integer) RETURNS vector `+
+			`LANGUAGE plpgsql IMMUTABLE PARALLEL SAFE AS $$ `+
+			`BEGIN `+
+			`IF jsonb_typeof(v) IS DISTINCT FROM 'array' OR jsonb_array_length(v) <> dims THEN RETURN NULL; END IF; `+
+			`RETURN v::text::vector; `+
+			`EXCEPTION WHEN OTHERS THEN RETURN NULL; `+
+			`END $$`,
+		pgx.Identifier{dbName, VectorFunction}.Sanitize(),
+	)
+
+	if _, err := p.Exec(ctx, q); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for i, v := range index.Vectors {
+		if v.Dimensions > maxVectorIndexDimensions {
+			continue
+		}
+
+		pgIndex := pgIndexName(c.TableName, fmt.Sprintf("%s_vector_%d", index.Name, i), allPgIndexes)
+		_, opClass := VectorOperator(v.Similarity)
+
+		// HNSW is available since pgvector 0.5.0; use IVFFlat for older versions
+		for _, method := range []string{"hnsw", "ivfflat"} {
+			q = fmt.Sprintf(
+				"CREATE INDEX %s ON %s USING %s (%s %s)",
+				pgx.Identifier{pgIndex}.Sanitize(),
+				pgx.Identifier{dbName, c.TableName}.Sanitize(),
+				method,
+				VectorExpression(dbName, v.Path, v.Dimensions),
+				opClass,
+			)
+
+			_, err := p.Exec(ctx, q)
+			if err == nil {
+				break
+			}
+
+			var pgErr *pgconn.PgError
+			if method == "hnsw" && errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UndefinedObject {
+				continue
+			}
+
+			return lazyerrors.Error(err)
+		}
+
+		index.Vectors[i].PgIndex = pgIndex
+		index.PgIndexes = append(index.PgIndexes, pgIndex)
+		allPgIndexes[pgIndex] = c.Name
+	}
+
+	return nil
+}
+
+// SearchIndexesDrop removes given collection's search indexes.
+//
+// Non-existing search indexes are ignored.
 //
 // If database or collection does not exist, nil is returned.
 //
 // If the user is not authenticated, it returns error.
-func (r *Registry) IndexesDrop(ctx context.Context, dbName, collectionName string, indexNames []string) error {
+func (r *Registry) SearchIndexesDrop(ctx context.Context, dbName, collectionName string, indexNames []string) error {
 	defer observability.FuncCall(ctx)()
 
 	p, err := r.getPool(ctx)
@@ -923,20 +1411,24 @@ func (r *Registry) IndexesDrop(ctx context.Context, dbName, collectionName strin
 		return lazyerrors.Error(err)
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	if !r.owned(ctx, dbName) {
+		return nil
+	}
 
-	return r.indexesDrop(ctx, p, dbName, collectionName, indexNames)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.searchIndexesDrop(ctx, p, dbName, collectionName, indexNames)
 }
 
-// indexesDrop removes given connection's indexes.
+// searchIndexesDrop removes given collection's search indexes.
 //
-// Non-existing indexes are ignored.
+// Non-existing search indexes are ignored.
 //
 // If database or collection does not exist, nil is returned.
 //
 // It does not hold the lock.
-func (r *Registry) indexesDrop(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string, indexNames []string) error {
+func (r *Registry) searchIndexesDrop(ctx context.Context, p *pgxpool.Pool, dbName, collectionName string, indexNames []string) error { //nolint:lll // for readability
 	defer observability.FuncCall(ctx)()
 
 	c := r.collectionGet(dbName, collectionName)
@@ -945,17 +1437,19 @@ func (r *Registry) indexesDrop(ctx context.Context, p *pgxpool.Pool, dbName, col
 	}
 
 	for _, name := range indexNames {
-		i := slices.IndexFunc(c.Indexes, func(i IndexInfo) bool { return name == i.Name })
+		i := slices.IndexFunc(c.SearchIndexes, func(i SearchIndexInfo) bool { return name == i.Name })
 		if i < 0 {
 			continue
 		}
 
-		q := fmt.Sprintf("DROP INDEX %s", pgx.Identifier{dbName, c.Indexes[i].PgIndex}.Sanitize())
-		if _, err := p.Exec(ctx, q); err != nil {
-			return lazyerrors.Error(err)
+		for _, pgIndex := range c.SearchIndexes[i].PgIndexes {
+			q := fmt.Sprintf("DROP INDEX %s", pgx.Identifier{dbName, pgIndex}.Sanitize())
+			if _, err := p.Exec(ctx, q); err != nil {
+				return lazyerrors.Error(err)
+			}
 		}
 
-		c.Indexes = slices.Delete(c.Indexes, i, i+1)
+		c.SearchIndexes = slices.Delete(c.SearchIndexes, i, i+1)
 	}
 
 	b, err := sjson.Marshal(c.marshal())
@@ -979,11 +1473,88 @@ func (r *Registry) indexesDrop(ctx context.Context, p *pgxpool.Pool, dbName, col
 		return lazyerrors.Error(err)
 	}
 
-	r.colls[dbName][collectionName] = c
+	r.storeCollection(dbName, collectionName, c)
 
 	return nil
 }
 
+// pgIndexes returns a map of all PostgreSQL index names of the given collections
+// to the names of collections they belong to.
+func pgIndexes(colls map[string]*Collection) map[string]string {
+	res := make(map[string]string, len(colls))
+
+	for _, coll := range colls {
+		for _, index := range coll.Indexes {
+			res[index.PgIndex] = coll.Name
+		}
+
+		for _, index := range coll.SearchIndexes {
+			for _, pgIndex := range index.PgIndexes {
+				res[pgIndex] = coll.Name
+			}
+		}
+
+		if coll.TimeSeries != nil {
+			res[coll.TimeSeries.PgIndex] = coll.Name
+		}
+	}
+
+	return res
+}
+
+// pgIndexes returns PostgreSQL index names of the given database collections
+// and of indexes that are being built concurrently in that database, like pgIndexes function.
+//
+// It should be called with the lock held.
+func (r *Registry) pgIndexes(dbName string, colls map[string]*Collection) map[string]string {
+	res := pgIndexes(colls)
+
+	for b := range r.indexBuilds {
+		if b.dbName != dbName {
+			continue
+		}
+
+		for _, index := range b.indexes {
+			res[index.PgIndex] = b.collectionName
+		}
+	}
+
+	return res
+}
+
+// pgIndexName returns a PostgreSQL index name for the given table and index names
+// that is not present in allPgIndexes.
+func pgIndexName(tableName, indexName string, allPgIndexes map[string]string) string {
+	tableNamePart := tableName
+	tableNamePartMax := maxIndexNameLength/2 - 1 // 1 for the separator between table name and index name
+
+	if len(tableNamePart) > tableNamePartMax {
+		tableNamePart = tableNamePart[:tableNamePartMax]
+	}
+
+	indexNamePart := specialCharacters.ReplaceAllString(strings.ToLower(indexName), "_")
+
+	h := fnv.New32a()
+	must.NotFail(h.Write([]byte(indexName)))
+	s := h.Sum32()
+
+	for {
+		suffixHash := fmt.Sprintf("_%08x_idx", s)
+		if l := maxIndexNameLength/2 - len(suffixHash); len(indexNamePart) > l {
+			indexNamePart = indexNamePart[:l]
+		}
+
+		res := fmt.Sprintf("%s_%s%s", tableNamePart, indexNamePart, suffixHash)
+
+		// indexes must be unique across the whole database, so we check for duplicates for all collections
+		if _, duplicate := allPgIndexes[res]; !duplicate {
+			return res
+		}
+
+		s++
+	}
+}
+
 // quoteString returns a string that is safe to use in SQL queries.
 //
 // Deprecated: Warning! Avoid using this function unless there is no other way.
@@ -996,6 +1567,33 @@ func quoteString(str string) string {
 	return "'" + strings.ReplaceAll(str, "'", "''") + "'"
 }
 
+// Checkpoint forces a PostgreSQL checkpoint, flushing all dirty buffers to data files.
+//
+// CHECKPOINT requires superuser privileges or pg_checkpoint role;
+// without them, the checkpoint is skipped, as committed transactions are already durable in the WAL.
+func (r *Registry) Checkpoint(ctx context.Context) error {
+	defer observability.FuncCall(ctx)()
+
+	p, err := r.getPool(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	_, err = p.Exec(ctx, "CHECKPOINT")
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.InsufficientPrivilege {
+		r.l.Warn("Not enough privileges to run CHECKPOINT, skipping", zap.Error(err))
+		return nil
+	}
+
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
 // Describe implements prometheus.Collector.
 func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
 	prometheus.DescribeByCollect(r, ch)
@@ -1005,8 +1603,7 @@ func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
 func (r *Registry) Collect(ch chan<- prometheus.Metric) {
 	r.p.Collect(ch)
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
+	snapshot := r.snapshot()
 
 	ch <- prometheus.MustNewConstMetric(
 		prometheus.NewDesc(
@@ -1015,10 +1612,18 @@ func (r *Registry) Collect(ch chan<- prometheus.Metric) {
 			nil, nil,
 		),
 		prometheus.GaugeValue,
-		float64(len(r.colls)),
+		float64(len(snapshot)),
 	)
 
-	for db, colls := range r.colls {
+	cacheDesc := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "cache_lookups_total"),
+		"The total number of collection lookups in the registry cache.",
+		[]string{"result"}, nil,
+	)
+	ch <- prometheus.MustNewConstMetric(cacheDesc, prometheus.CounterValue, float64(r.cacheHits.Load()), "hit")
+	ch <- prometheus.MustNewConstMetric(cacheDesc, prometheus.CounterValue, float64(r.cacheMisses.Load()), "miss")
+
+	for db, colls := range snapshot {
 		ch <- prometheus.MustNewConstMetric(
 			prometheus.NewDesc(
 				prometheus.BuildFQName(namespace, subsystem, "collections"),
@@ -1030,6 +1635,42 @@ func (r *Registry) Collect(ch chan<- prometheus.Metric) {
 			db,
 		)
 	}
+	if !r.tenantIsolation {
+		return
+	}
+
+	tenantDBs := map[string]int{}
+	tenantColls := map[string]int{}
+
+	owners := r.ownersSnapshot()
+	for db, colls := range snapshot {
+		tenantDBs[owners[db]]++
+		tenantColls[owners[db]] += len(colls)
+	}
+
+	for tenant, dbs := range tenantDBs {
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName(namespace, subsystem, "tenant_databases"),
+				"The current number of databases owned by the tenant.",
+				[]string{"tenant"}, nil,
+			),
+			prometheus.GaugeValue,
+			float64(dbs),
+			tenant,
+		)
+
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName(namespace, subsystem, "tenant_collections"),
+				"The current number of collections owned by the tenant.",
+				[]string{"tenant"}, nil,
+			),
+			prometheus.GaugeValue,
+			float64(tenantColls[tenant]),
+			tenant,
+		)
+	}
 }
 
 // check interfaces
@@ -91,7 +91,7 @@ func createDatabase(t *testing.T, ctx context.Context) (*Registry, *pgxpool.Pool
 	sp, err := state.NewProvider("")
 	require.NoError(t, err)
 
-	r, err := NewRegistry(u, testutil.Logger(t), sp)
+	r, err := NewRegistry(u, false, testutil.Logger(t), sp)
 	require.NoError(t, err)
 	t.Cleanup(r.Close)
 
@@ -140,7 +140,7 @@ func TestAuth(t *testing.T) {
 			sp, err := state.NewProvider("")
 			require.NoError(t, err)
 
-			r, err := NewRegistry(tc.uri, testutil.Logger(t), sp)
+			r, err := NewRegistry(tc.uri, false, testutil.Logger(t), sp)
 			require.NoError(t, err)
 			t.Cleanup(r.Close)
 
@@ -211,6 +211,47 @@ func TestCreateSameStress(t *testing.T) {
 	require.Equal(t, int32(1), createdTotal.Load())
 }
 
+func TestIndexesCreateSameStress(t *testing.T) {
+	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
+	r, _, dbName := createDatabase(t, ctx)
+	collectionName := testutil.CollectionName(t)
+
+	// indexes of the existing collection are built concurrently without holding the lock
+	created, err := r.CollectionCreate(ctx, &CollectionCreateParams{DBName: dbName, Name: collectionName})
+	require.NoError(t, err)
+	require.True(t, created)
+
+	var i atomic.Int32
+
+	teststress.Stress(t, func(ready chan<- struct{}, start <-chan struct{}) {
+		id := i.Add(1)
+
+		ready <- struct{}{}
+		<-start
+
+		indexes := []IndexInfo{
+			{Name: "same", Key: []IndexKeyPair{{Field: "same"}}},
+			{Name: fmt.Sprintf("index_%03d", id), Key: []IndexKeyPair{{Field: fmt.Sprintf("f%d", id)}}},
+		}
+
+		err := r.IndexesCreate(ctx, dbName, collectionName, indexes, nil)
+		require.NoError(t, err)
+	})
+
+	c, err := r.CollectionGet(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.NotNil(t, c)
+
+	names := make([]string, len(c.Indexes))
+	for j, index := range c.Indexes {
+		names[j] = index.Name
+	}
+
+	// _id_, same, and one unique index per goroutine
+	require.Len(t, names, 2+int(i.Load()), "%v", names)
+	require.Len(t, r.indexBuilds, 0)
+}
+
 func TestDropSameStress(t *testing.T) {
 	ctx := conninfo.Ctx(testutil.Ctx(t), conninfo.New())
 	r, _, dbName := createDatabase(t, ctx)
@@ -296,8 +337,10 @@ func TestCheckDatabaseUpdated(t *testing.T) {
 	var err error
 
 	t.Run("CheckDatabaseCreate", func(t *testing.T) {
-		err = r.initCollections(ctx, dbName, db)
+		var colls map[string]*Collection
+		colls, err = r.initCollections(ctx, dbName, db)
 		require.NoError(t, err)
+		r.storeDatabase(dbName, colls)
 
 		var p *pgxpool.Pool
 		p, err = r.DatabaseGetExisting(ctx, dbName)
@@ -316,8 +359,10 @@ func TestCheckDatabaseUpdated(t *testing.T) {
 		require.NotNil(t, metadataCollection)
 		require.Equal(t, collectionName, metadataCollection.Name)
 
-		err = r.initCollections(ctx, dbName, db)
+		var colls map[string]*Collection
+		colls, err = r.initCollections(ctx, dbName, db)
 		require.NoError(t, err)
+		r.storeDatabase(dbName, colls)
 
 		dbCollection, err := r.CollectionGet(ctx, dbName, collectionName)
 		require.NoError(t, err)
@@ -329,8 +374,10 @@ func TestCheckDatabaseUpdated(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, dropped)
 
-		err = r.initCollections(ctx, dbName, db)
+		var colls map[string]*Collection
+		colls, err = r.initCollections(ctx, dbName, db)
 		require.NoError(t, err)
+		r.storeDatabase(dbName, colls)
 
 		dbCollection, err := r.CollectionGet(ctx, dbName, collectionName)
 		require.NoError(t, err)
@@ -342,7 +389,7 @@ func TestCheckDatabaseUpdated(t *testing.T) {
 		require.NoError(t, err)
 		require.True(t, dropped)
 
-		err = r.initCollections(ctx, dbName, db)
+		_, err = r.initCollections(ctx, dbName, db)
 		require.Error(t, err)
 		require.ErrorContains(t, err, "relation \"TestCheckDatabaseUpdated._ferretdb_database_metadata\" does not exist")
 	})
@@ -372,8 +419,10 @@ func TestRenameCollection(t *testing.T) {
 	})
 
 	t.Run("CheckCollectionRenamed", func(t *testing.T) {
-		err = r.initCollections(ctx, dbName, db)
+		var colls map[string]*Collection
+		colls, err = r.initCollections(ctx, dbName, db)
 		require.NoError(t, err)
+		r.storeDatabase(dbName, colls)
 
 		expected := &Collection{
 			Name:      newCollectionName,
@@ -455,7 +504,7 @@ func TestIndexesCreateDrop(t *testing.T) {
 		}},
 	}}
 
-	err := r.IndexesCreate(ctx, dbName, collectionName, toCreate)
+	err := r.IndexesCreate(ctx, dbName, collectionName, toCreate, nil)
 	require.NoError(t, err)
 
 	collection, err := r.CollectionGet(ctx, dbName, collectionName)
@@ -669,7 +718,7 @@ func TestLongIndexNames(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			err := r.IndexesCreate(ctx, dbName, tc.collectionName, batch1)
+			err := r.IndexesCreate(ctx, dbName, tc.collectionName, batch1, nil)
 			require.NoError(t, err)
 
 			collection, err := r.CollectionGet(ctx, dbName, tc.collectionName)
@@ -689,7 +738,7 @@ func TestLongIndexNames(t *testing.T) {
 				}
 			}
 
-			err = r.IndexesCreate(ctx, dbName, tc.collectionName, batch2)
+			err = r.IndexesCreate(ctx, dbName, tc.collectionName, batch2, nil)
 			require.NoError(t, err)
 
 			// Force DBs and collection initialization to check that indexes metadata is stored correctly in the database.
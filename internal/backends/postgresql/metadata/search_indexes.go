@@ -0,0 +1,285 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// SearchConfig is a PostgreSQL text search configuration used for `string` fields and dynamic mappings.
+const SearchConfig = "english"
+
+// SearchIndexes represents information about all search indexes in a collection.
+type SearchIndexes []SearchIndexInfo
+
+// SearchIndexInfo represents information about a single search index.
+//
+// Dynamic index is backed by a single PostgreSQL index over all string values of the document.
+// Each field of the static index is backed by a separate PostgreSQL index.
+//
+// Each vector field of the vector search index is backed by a separate pgvector index, if pgvector is available.
+// Filter fields are not indexed.
+type SearchIndexInfo struct {
+	Name      string
+	PgIndexes []string
+	Dynamic   bool
+	Fields    []SearchIndexField
+	Vectors   []VectorSearchIndexField
+	Filters   []string
+}
+
+// VectorSearchIndexField represents a single vector field of the vector search index.
+//
+// PgIndex is empty if the field is not backed by pgvector index.
+type VectorSearchIndexField struct {
+	Path       string
+	PgIndex    string
+	Dimensions int64
+	Similarity string
+}
+
+// SearchIndexField represents a single field of the search index.
+//
+// Autocomplete fields use pg_trgm trigram index, other fields use tsvector index.
+type SearchIndexField struct {
+	Path         string
+	Autocomplete bool
+}
+
+// deepCopy returns a deep copy.
+func (indexes SearchIndexes) deepCopy() SearchIndexes {
+	res := make(SearchIndexes, len(indexes))
+
+	for i, index := range indexes {
+		res[i] = SearchIndexInfo{
+			Name:      index.Name,
+			PgIndexes: slices.Clone(index.PgIndexes),
+			Dynamic:   index.Dynamic,
+			Fields:    slices.Clone(index.Fields),
+			Vectors:   slices.Clone(index.Vectors),
+			Filters:   slices.Clone(index.Filters),
+		}
+	}
+
+	return res
+}
+
+// marshal returns [*types.Array] for search indexes.
+func (indexes SearchIndexes) marshal() *types.Array {
+	res := types.MakeArray(len(indexes))
+
+	for _, index := range indexes {
+		pgIndexes := types.MakeArray(len(index.PgIndexes))
+		for _, pgIndex := range index.PgIndexes {
+			pgIndexes.Append(pgIndex)
+		}
+
+		fields := types.MakeArray(len(index.Fields))
+		for _, f := range index.Fields {
+			fields.Append(must.NotFail(types.NewDocument(
+				"path", f.Path,
+				"autocomplete", f.Autocomplete,
+			)))
+		}
+
+		doc := must.NotFail(types.NewDocument(
+			"pgindexes", pgIndexes,
+			"name", index.Name,
+			"dynamic", index.Dynamic,
+			"fields", fields,
+		))
+
+		if len(index.Vectors) > 0 {
+			vectors := types.MakeArray(len(index.Vectors))
+			for _, v := range index.Vectors {
+				vectors.Append(must.NotFail(types.NewDocument(
+					"path", v.Path,
+					"pgindex", v.PgIndex,
+					"dimensions", v.Dimensions,
+					"similarity", v.Similarity,
+				)))
+			}
+
+			filters := types.MakeArray(len(index.Filters))
+			for _, f := range index.Filters {
+				filters.Append(f)
+			}
+
+			doc.Set("vectors", vectors)
+			doc.Set("filters", filters)
+		}
+
+		res.Append(doc)
+	}
+
+	return res
+}
+
+// unmarshal sets search indexes from [*types.Array].
+func (indexes *SearchIndexes) unmarshal(a *types.Array) error {
+	res := make(SearchIndexes, a.Len())
+
+	iter := a.Iterator()
+	defer iter.Close()
+
+	for {
+		i, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		index := v.(*types.Document)
+
+		pgIndexesArr := must.NotFail(index.Get("pgindexes")).(*types.Array)
+		pgIndexes := make([]string, pgIndexesArr.Len())
+
+		for j := range pgIndexes {
+			pgIndexes[j] = must.NotFail(pgIndexesArr.Get(j)).(string)
+		}
+
+		fieldsArr := must.NotFail(index.Get("fields")).(*types.Array)
+		fields := make([]SearchIndexField, fieldsArr.Len())
+
+		for j := range fields {
+			f := must.NotFail(fieldsArr.Get(j)).(*types.Document)
+
+			fields[j] = SearchIndexField{
+				Path:         must.NotFail(f.Get("path")).(string),
+				Autocomplete: must.NotFail(f.Get("autocomplete")).(bool),
+			}
+		}
+
+		res[i] = SearchIndexInfo{
+			Name:      must.NotFail(index.Get("name")).(string),
+			PgIndexes: pgIndexes,
+			Dynamic:   must.NotFail(index.Get("dynamic")).(bool),
+			Fields:    fields,
+		}
+
+		// vector search indexes only
+		if v, _ := index.Get("vectors"); v != nil {
+			vectorsArr := v.(*types.Array)
+			res[i].Vectors = make([]VectorSearchIndexField, vectorsArr.Len())
+
+			for j := range res[i].Vectors {
+				f := must.NotFail(vectorsArr.Get(j)).(*types.Document)
+
+				res[i].Vectors[j] = VectorSearchIndexField{
+					Path:       must.NotFail(f.Get("path")).(string),
+					PgIndex:    must.NotFail(f.Get("pgindex")).(string),
+					Dimensions: must.NotFail(f.Get("dimensions")).(int64),
+					Similarity: must.NotFail(f.Get("similarity")).(string),
+				}
+			}
+
+			filtersArr := must.NotFail(index.Get("filters")).(*types.Array)
+			res[i].Filters = make([]string, filtersArr.Len())
+
+			for j := range res[i].Filters {
+				res[i].Filters[j] = must.NotFail(filtersArr.Get(j)).(string)
+			}
+		}
+	}
+
+	*indexes = res
+
+	return nil
+}
+
+// SearchTextExpression returns tsvector SQL expression for the given dot-separated field path.
+//
+// The same expression is used for index creation and querying, so PostgreSQL could use the index.
+func SearchTextExpression(path string) string {
+	return fmt.Sprintf(
+		"to_tsvector(%s::regconfig, COALESCE(%s, ''))",
+		quoteString(SearchConfig), searchValueExpression(path),
+	)
+}
+
+// SearchDynamicExpression returns tsvector SQL expression for all string values of the document.
+func SearchDynamicExpression() string {
+	return fmt.Sprintf(
+		`jsonb_to_tsvector(%s::regconfig, %s - '$s', '["string"]')`,
+		quoteString(SearchConfig), DefaultColumn,
+	)
+}
+
+// SearchAutocompleteExpression returns text SQL expression for the given dot-separated field path
+// that is indexed with pg_trgm trigram index.
+func SearchAutocompleteExpression(path string) string {
+	return fmt.Sprintf("(%s)", searchValueExpression(path))
+}
+
+// searchValueExpression returns SQL expression that extracts the given dot-separated field path as text.
+func searchValueExpression(path string) string {
+	return fmt.Sprintf("%s #>> ARRAY[%s]", DefaultColumn, searchPathElements(path))
+}
+
+// searchPathElements returns SQL array elements for the given dot-separated field path.
+func searchPathElements(path string) string {
+	parts := strings.Split(path, ".")
+	for i, p := range parts {
+		// It's important to sanitize path here, as it's a user-provided value.
+		parts[i] = quoteString(p)
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// VectorFunction is a name of the function that converts a document field value to pgvector's vector,
+// or returns NULL if the value is not a numeric array of the given length.
+//
+// It is created in each database schema with pgvector indexes.
+const VectorFunction = backends.ReservedPrefix + "vector"
+
+// VectorExpression returns pgvector SQL expression for the given dot-separated field path.
+//
+// The same expression is used for index creation and querying, so PostgreSQL could use the index.
+func VectorExpression(dbName, path string, dimensions int64) string {
+	return fmt.Sprintf(
+		"(%s(%s #> ARRAY[%s], %d)::vector(%d))",
+		pgx.Identifier{dbName, VectorFunction}.Sanitize(), DefaultColumn, searchPathElements(path), dimensions, dimensions,
+	)
+}
+
+// VectorOperator returns pgvector distance operator and index operator class for the given similarity function.
+func VectorOperator(similarity string) (operator, opClass string) {
+	switch backends.VectorSimilarity(similarity) {
+	case backends.VectorSimilarityCosine:
+		return "<=>", "vector_cosine_ops"
+	case backends.VectorSimilarityEuclidean:
+		return "<->", "vector_l2_ops"
+	case backends.VectorSimilarityDotProduct:
+		// negative inner product
+		return "<#>", "vector_ip_ops"
+	default:
+		panic(fmt.Sprintf("unexpected similarity %q", similarity))
+	}
+}
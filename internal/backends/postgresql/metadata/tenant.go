@@ -0,0 +1,205 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/exp/maps"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// Tenant isolation mode.
+//
+// In that mode, each database (PostgreSQL schema) belongs to the PostgreSQL role that created it – a tenant.
+// Clients must authenticate; their credentials are used to connect to PostgreSQL as usual,
+// so PostgreSQL privileges isolate data of different tenants.
+// Registry additionally hides databases of other tenants from the metadata it returns,
+// and refuses to create collections in them.
+//
+// The role from the base URI is an administrator: it loads metadata of all databases
+// and can access all of them. Tenants grant it read access to their metadata tables.
+// Background operations (such as capped collections cleanup) bypass backend authentication
+// without username and use the administrator role too.
+//
+// Clients authenticated by FerretDB itself (with SCRAM) also bypass backend authentication,
+// but their PostgreSQL role can't be used, so they are rejected (see checkTenant).
+
+// tenant returns the name of the PostgreSQL role of the client in the context,
+// or empty string if the client can access all databases.
+func (r *Registry) tenant(ctx context.Context) string {
+	if !r.tenantIsolation {
+		return ""
+	}
+
+	username, _ := conninfo.Get(ctx).Auth()
+	if username == r.adminRole {
+		return ""
+	}
+
+	return username
+}
+
+// checkTenant returns an error if the client in the context can't be isolated in tenant isolation mode.
+func (r *Registry) checkTenant(ctx context.Context) error {
+	if !r.tenantIsolation {
+		return nil
+	}
+
+	connInfo := conninfo.Get(ctx)
+	username, _ := connInfo.Auth()
+
+	switch {
+	case connInfo.BypassBackendAuth():
+		if username != "" && username != r.adminRole {
+			return lazyerrors.New("backend authentication bypass is not supported in tenant isolation mode")
+		}
+
+	case username == "":
+		return lazyerrors.New("authentication is required in tenant isolation mode")
+	}
+
+	return nil
+}
+
+// owned returns true if the database is accessible by the client in the context.
+// Non-existent databases are accessible.
+//
+// It does not hold the lock.
+func (r *Registry) owned(ctx context.Context, dbName string) bool {
+	tenant := r.tenant(ctx)
+	if tenant == "" {
+		return true
+	}
+
+	owner, ok := r.ownersSnapshot()[dbName]
+
+	return !ok || owner == tenant
+}
+
+// errNotOwned returns an error for the attempt to modify the database of another tenant.
+func errNotOwned(dbName string) error {
+	return lazyerrors.Errorf("database %q is owned by another tenant", dbName)
+}
+
+// ownersSnapshot returns the current snapshot of database owners.
+//
+// It does not hold the lock. Returned map must not be modified.
+func (r *Registry) ownersSnapshot() map[string]string {
+	if owners := r.owners.Load(); owners != nil {
+		return *owners
+	}
+
+	return nil
+}
+
+// storeOwner stores a new snapshot with the given database owner.
+// If owner is empty, the database is removed from the snapshot.
+//
+// It should be called with the lock held.
+func (r *Registry) storeOwner(dbName, owner string) {
+	old := r.ownersSnapshot()
+
+	res := make(map[string]string, len(old)+1)
+	maps.Copy(res, old)
+
+	if owner == "" {
+		delete(res, dbName)
+	} else {
+		res[dbName] = owner
+	}
+
+	r.owners.Store(&res)
+}
+
+// initOwners loads owners of the given databases.
+func (r *Registry) initOwners(ctx context.Context, p *pgxpool.Pool, dbNames []string) (map[string]string, error) {
+	q := strings.TrimSpace(`
+		SELECT schema_name, schema_owner
+		FROM information_schema.schemata
+		WHERE schema_name = ANY($1)
+	`)
+
+	rows, err := p.Query(ctx, q, dbNames)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+	defer rows.Close()
+
+	owners := make(map[string]string, len(dbNames))
+
+	for rows.Next() {
+		var dbName, owner string
+		if err = rows.Scan(&dbName, &owner); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		owners[dbName] = owner
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return owners, nil
+}
+
+// initTenantDatabase records the owner of the newly created database
+// and grants the administrator access to its metadata table.
+//
+// It should be called with the lock held.
+func (r *Registry) initTenantDatabase(ctx context.Context, p *pgxpool.Pool, dbName string) error {
+	owners, err := r.initOwners(ctx, p, []string{dbName})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	owner := owners[dbName]
+	if owner == "" {
+		return lazyerrors.Errorf("no owner of database %q", dbName)
+	}
+
+	if r.adminRole != "" && owner != r.adminRole {
+		q := fmt.Sprintf(
+			`GRANT USAGE ON SCHEMA %s TO %s`,
+			pgx.Identifier{dbName}.Sanitize(),
+			pgx.Identifier{r.adminRole}.Sanitize(),
+		)
+
+		if _, err = p.Exec(ctx, q); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		q = fmt.Sprintf(
+			`GRANT SELECT ON %s TO %s`,
+			pgx.Identifier{dbName, metadataTableName}.Sanitize(),
+			pgx.Identifier{r.adminRole}.Sanitize(),
+		)
+
+		if _, err = p.Exec(ctx, q); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	r.storeOwner(dbName, owner)
+
+	return nil
+}
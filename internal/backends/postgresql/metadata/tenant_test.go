@@ -0,0 +1,136 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+)
+
+func TestTenantOwned(t *testing.T) {
+	t.Parallel()
+
+	r := &Registry{
+		tenantIsolation: true,
+		adminRole:       "admin",
+	}
+
+	r.storeOwner("db1", "tenant1")
+	r.storeOwner("db2", "tenant2")
+	r.storeOwner("db3", "tenant1")
+	r.storeOwner("db3", "")
+
+	ctx := func(username string, bypass bool) context.Context {
+		connInfo := conninfo.New()
+		connInfo.SetAuth(username, "password")
+
+		if bypass {
+			connInfo.SetBypassBackendAuth()
+		}
+
+		return conninfo.Ctx(context.Background(), connInfo)
+	}
+
+	tenant1 := ctx("tenant1", false)
+	assert.Equal(t, "tenant1", r.tenant(tenant1))
+	assert.True(t, r.owned(tenant1, "db1"))
+	assert.False(t, r.owned(tenant1, "db2"))
+	assert.True(t, r.owned(tenant1, "db3"), "removed database")
+	assert.True(t, r.owned(tenant1, "new"), "non-existent database")
+
+	// clients authenticated by FerretDB are still limited to their tenant
+	bypass := ctx("tenant1", true)
+	assert.Equal(t, "tenant1", r.tenant(bypass))
+	assert.False(t, r.owned(bypass, "db2"))
+
+	for name, ctx := range map[string]context.Context{
+		"Admin":      ctx("admin", false),
+		"Background": ctx("", true),
+	} {
+		assert.Empty(t, r.tenant(ctx), name)
+		assert.True(t, r.owned(ctx, "db1"), name)
+		assert.True(t, r.owned(ctx, "db2"), name)
+	}
+
+	r.tenantIsolation = false
+	assert.Empty(t, r.tenant(tenant1))
+	assert.True(t, r.owned(tenant1, "db2"))
+}
+
+func TestTenantBypass(t *testing.T) {
+	t.Parallel()
+
+	r := &Registry{
+		tenantIsolation: true,
+		adminRole:       "admin",
+	}
+
+	r.storeOwner("db1", "tenant1")
+	r.storeOwner("db2", "tenant2")
+
+	for name, tc := range map[string]struct {
+		username string
+		bypass   bool
+		err      string // empty if no error is expected
+	}{
+		"Tenant": {
+			username: "tenant1",
+		},
+		"TenantBypass": {
+			username: "tenant1",
+			bypass:   true,
+			err:      "backend authentication bypass is not supported in tenant isolation mode",
+		},
+		"AdminBypass": {
+			username: "admin",
+			bypass:   true,
+		},
+		"Background": {
+			bypass: true,
+		},
+		"Anonymous": {
+			err: "authentication is required in tenant isolation mode",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			connInfo := conninfo.New()
+			connInfo.SetAuth(tc.username, "password")
+
+			if tc.bypass {
+				connInfo.SetBypassBackendAuth()
+			}
+
+			ctx := conninfo.Ctx(context.Background(), connInfo)
+
+			err := r.checkTenant(ctx)
+			if tc.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorContains(t, err, tc.err)
+
+			// other tenants' databases can't be listed
+			_, err = r.DatabaseList(ctx)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import "fmt"
+
+// TimeSeries represents time-series collection options.
+type TimeSeries struct {
+	TimeField   string
+	MetaField   string
+	Granularity string
+
+	// PgIndex is the name of the BRIN index on the time field expression.
+	PgIndex string
+}
+
+// TimeSeriesExpression returns numeric SQL expression for the time field of the time-series collection
+// that is indexed with BRIN index.
+//
+// Dates are stored as a number of milliseconds since epoch.
+// The expression is NULL for values of other JSON types, such as arrays.
+func TimeSeriesExpression(timeField string) string {
+	// It's important to sanitize field name here, as it's a user-provided value.
+	return fmt.Sprintf(
+		"(CASE WHEN jsonb_typeof(%[1]s -> %[2]s) = 'number' THEN (%[1]s ->> %[2]s)::numeric END)",
+		DefaultColumn, quoteString(timeField),
+	)
+}
@@ -15,13 +15,18 @@
 package postgresql
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
 	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -39,6 +44,7 @@ type selectParams struct {
 
 	Capped        bool
 	OnlyRecordIDs bool
+	BSON          bool
 }
 
 // prepareSelectClause returns SELECT clause for default column of provided schema and table name.
@@ -46,11 +52,18 @@ type selectParams struct {
 // For capped collection with onlyRecordIDs, it returns select clause for recordID column.
 //
 // For capped collection, it returns select clause for recordID column and default column.
+//
+// If BSON is true, BSON column is selected instead of default column.
 func prepareSelectClause(params *selectParams) string {
 	if params == nil {
 		params = new(selectParams)
 	}
 
+	column := metadata.DefaultColumn
+	if params.BSON {
+		column = metadata.BSONColumn
+	}
+
 	if params.Comment != "" {
 		params.Comment = strings.ReplaceAll(params.Comment, "/*", "/ *")
 		params.Comment = strings.ReplaceAll(params.Comment, "*/", "* /")
@@ -71,7 +84,7 @@ func prepareSelectClause(params *selectParams) string {
 			`SELECT %s %s, %s FROM %s`,
 			params.Comment,
 			metadata.RecordIDColumn,
-			metadata.DefaultColumn,
+			column,
 			pgx.Identifier{params.Schema, params.Table}.Sanitize(),
 		)
 	}
@@ -79,7 +92,7 @@ func prepareSelectClause(params *selectParams) string {
 	return fmt.Sprintf(
 		`SELECT %s %s FROM %s`,
 		params.Comment,
-		metadata.DefaultColumn,
+		column,
 		pgx.Identifier{params.Schema, params.Table}.Sanitize(),
 	)
 }
@@ -103,13 +116,6 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 			return "", nil, lazyerrors.Error(err)
 		}
 
-		keyOperator := "->" // keyOperator is the operator that is used to access the field. (->/#>)
-
-		// key can be either a string '"v"' or PostgreSQL path '{v,foo}'.
-		// We use path type only for dot notation due to simplicity of SQL queries, and the fact
-		// that path doesn't handle empty keys.
-		var key any = rootKey
-
 		// don't pushdown $comment, as it's attached to query with select clause
 		//
 		// all of the other top-level operators such as `$or` do not support pushdown yet
@@ -123,9 +129,14 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 
 		switch {
 		case err == nil:
+			// dot notation paths may go through arrays of documents, use jsonpath for them
 			if path.Len() > 1 {
-				keyOperator = "#>"
-				key = path.Slice() // '{v,foo}'
+				if f, a := filterJSONPath(p, rootKey, rootVal); f != "" {
+					filters = append(filters, f)
+					args = append(args, a...)
+				}
+
+				continue
 			}
 
 		case errors.As(err, &pe):
@@ -155,7 +166,7 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 
 				switch k {
 				case "$eq":
-					if f, a := filterEqual(p, key, v, keyOperator); f != "" {
+					if f, a := filterEqual(p, rootKey, v, "->"); f != "" {
 						filters = append(filters, f)
 						args = append(args, a...)
 					}
@@ -205,6 +216,12 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 						panic(fmt.Sprintf("Unexpected type of value: %v", v))
 					}
 
+				case "$all", "$elemMatch":
+					if f, a := filterJSONPath(p, rootKey, must.NotFail(types.NewDocument(k, v))); f != "" {
+						filters = append(filters, f)
+						args = append(args, a...)
+					}
+
 				default:
 					// $gt and $lt
 					// TODO https://github.com/FerretDB/FerretDB/issues/1875
@@ -216,7 +233,7 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 			// type not supported for pushdown
 
 		case float64, string, types.ObjectID, bool, time.Time, int32, int64:
-			if f, a := filterEqual(p, key, v, keyOperator); f != "" {
+			if f, a := filterEqual(p, rootKey, v, "->"); f != "" {
 				filters = append(filters, f)
 				args = append(args, a...)
 			}
@@ -234,11 +251,156 @@ func prepareWhereClause(p *metadata.Placeholder, sqlFilters *types.Document) (st
 	return filter, args, nil
 }
 
+// prepareFilterPushdownPaths returns a document with top-level filter keys (except $comment)
+// and booleans indicating whether at least one of their conditions is pushed down by prepareWhereClause.
+//
+// Keys for fields that are not stored in DefaultColumn by the collection's codec are never pushed down.
+func prepareFilterPushdownPaths(meta *metadata.Collection, filter *types.Document) (*types.Document, error) {
+	res := types.MakeDocument(filter.Len())
+
+	for _, k := range filter.Keys() {
+		if k == "$comment" {
+			continue
+		}
+
+		f, err := types.NewDocument(k, must.NotFail(filter.Get(k)))
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		where, _, err := prepareWhereClause(new(metadata.Placeholder), pushdownFilter(meta, f))
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		res.Set(k, where != "")
+	}
+
+	return res, nil
+}
+
+// prepareTimeSeriesClause adds conditions for time ranges of the time-series collection to the given WHERE clause,
+// so the BRIN index on the time field could be used.
+//
+// Only $gt, $gte, $lt and $lte operators with date values for the time field are supported.
+// Documents with non-number time values are not filtered out, as they may be arrays containing matching dates.
+func prepareTimeSeriesClause(p *metadata.Placeholder, where, timeField string, filter *types.Document) (string, []any) {
+	v, _ := filter.Get(timeField)
+
+	ops, ok := v.(*types.Document)
+	if !ok {
+		return where, nil
+	}
+
+	var filters []string
+	var args []any
+
+	for _, op := range ops.Keys() {
+		var sqlOp string
+
+		switch op {
+		case "$gt":
+			sqlOp = ">"
+		case "$gte":
+			sqlOp = ">="
+		case "$lt":
+			sqlOp = "<"
+		case "$lte":
+			sqlOp = "<="
+		default:
+			continue
+		}
+
+		t, ok := must.NotFail(ops.Get(op)).(time.Time)
+		if !ok {
+			continue
+		}
+
+		expr := metadata.TimeSeriesExpression(timeField)
+		filters = append(filters, fmt.Sprintf(`(%[1]s IS NULL OR %[1]s %[2]s %[3]s)`, expr, sqlOp, p.Next()))
+		args = append(args, t.UnixMilli())
+	}
+
+	if len(filters) == 0 {
+		return where, nil
+	}
+
+	if where == "" {
+		return ` WHERE ` + strings.Join(filters, " AND "), args
+	}
+
+	return where + ` AND ` + strings.Join(filters, " AND "), args
+}
+
+// prepareClusteredClause adds conditions for _id ranges of the clustered collection to the given WHERE clause,
+// so the _id index could be used for range scans.
+//
+// Only $gt, $gte, $lt and $lte operators with ObjectID and date values are supported.
+// As values of different types are never matched by those operators, documents with _id of other types are filtered out.
+func prepareClusteredClause(p *metadata.Placeholder, where string, filter *types.Document) (string, []any) {
+	v, _ := filter.Get("_id")
+
+	ops, ok := v.(*types.Document)
+	if !ok {
+		return where, nil
+	}
+
+	var filters []string
+	var args []any
+
+	for _, op := range ops.Keys() {
+		var sqlOp string
+
+		switch op {
+		case "$gt":
+			sqlOp = ">"
+		case "$gte":
+			sqlOp = ">="
+		case "$lt":
+			sqlOp = "<"
+		case "$lte":
+			sqlOp = "<="
+		default:
+			continue
+		}
+
+		v := must.NotFail(ops.Get(op))
+
+		switch v.(type) {
+		case types.ObjectID, time.Time:
+		default:
+			continue
+		}
+
+		filters = append(filters, fmt.Sprintf(
+			`%[1]s->'$s'->'p'->'_id'->'t' = '"%[2]s"' AND %[1]s->'_id' %[3]s %[4]s`,
+			metadata.DefaultColumn,
+			sjson.GetTypeOfValue(v),
+			sqlOp,
+			p.Next(),
+		))
+		args = append(args, string(must.NotFail(sjson.MarshalSingleValue(v))))
+	}
+
+	if len(filters) == 0 {
+		return where, nil
+	}
+
+	if where == "" {
+		return ` WHERE ` + strings.Join(filters, " AND "), args
+	}
+
+	return where + ` AND ` + strings.Join(filters, " AND "), args
+}
+
 // prepareOrderByClause returns ORDER BY clause with arguments for given sort document.
 //
 // The provided sort document should be already validated.
 // Provided document should only contain a single value.
-func prepareOrderByClause(sort *types.Document) (string, []any) {
+//
+// Clustered collections are sorted by _id index instead of recordID column.
+// Other collections without recordID column are sorted by the physical location of rows.
+func prepareOrderByClause(sort *types.Document, clustered, capped bool) (string, []any) {
 	if sort.Len() != 1 {
 		return "", nil
 	}
@@ -255,9 +417,94 @@ func prepareOrderByClause(sort *types.Document) (string, []any) {
 		panic("not reachable")
 	}
 
+	if clustered {
+		return fmt.Sprintf(" ORDER BY %s->'_id'%s", metadata.DefaultColumn, order), nil
+	}
+
+	if !capped {
+		return fmt.Sprintf(" ORDER BY ctid%s", order), nil
+	}
+
 	return fmt.Sprintf(" ORDER BY %s%s", metadata.RecordIDColumn, order), nil
 }
 
+// prepareSearchClause returns WHERE and ORDER BY clauses with arguments for given full-text search parameters.
+//
+// If index is dynamic, its expression is added to the WHERE clause, so PostgreSQL could use it
+// to find candidate documents before checking given paths.
+func prepareSearchClause(p *metadata.Placeholder, index *metadata.SearchIndexInfo, params *backends.SearchParams) (string, string, []any) { //nolint:lll // for readability
+	var query, cond string
+	var arg any
+
+	switch params.Operator {
+	case backends.SearchText:
+		// plainto_tsquery matches all terms, but any of them should match
+		query = fmt.Sprintf(
+			"replace(plainto_tsquery('%s'::regconfig, %s)::text, '&', '|')::tsquery",
+			metadata.SearchConfig, p.Next(),
+		)
+		arg = params.Query
+
+	case backends.SearchPhrase:
+		query = fmt.Sprintf("phraseto_tsquery('%s'::regconfig, %s)", metadata.SearchConfig, p.Next())
+		arg = params.Query
+
+	case backends.SearchAutocomplete:
+		// \m matches at the beginning of a word
+		query = p.Next()
+		arg = `\m` + regexp.QuoteMeta(params.Query)
+
+	default:
+		panic(fmt.Sprintf("unexpected search operator %q", params.Operator))
+	}
+
+	conds := make([]string, len(params.Paths))
+	vectors := make([]string, len(params.Paths))
+
+	for i, path := range params.Paths {
+		if params.Operator == backends.SearchAutocomplete {
+			conds[i] = fmt.Sprintf("%s ~* %s", metadata.SearchAutocompleteExpression(path), query)
+			continue
+		}
+
+		vectors[i] = metadata.SearchTextExpression(path)
+		conds[i] = fmt.Sprintf("%s @@ %s", vectors[i], query)
+	}
+
+	cond = "(" + strings.Join(conds, " OR ") + ")"
+
+	if index.Dynamic && params.Operator != backends.SearchAutocomplete {
+		cond = fmt.Sprintf("%s @@ %s AND %s", metadata.SearchDynamicExpression(), query, cond)
+	}
+
+	var orderBy string
+	if params.Operator != backends.SearchAutocomplete {
+		orderBy = fmt.Sprintf(" ORDER BY ts_rank(%s, %s) DESC", strings.Join(vectors, " || "), query)
+	}
+
+	return " WHERE " + cond, orderBy, []any{arg}
+}
+
+// prepareVectorOrderByClause returns ORDER BY clause with arguments that orders documents
+// by the distance between the given vector field and the query vector (the nearest first).
+//
+// The expression used for ordering matches the index, so PostgreSQL could use it.
+func prepareVectorOrderByClause(p *metadata.Placeholder, dbName string, field *metadata.VectorSearchIndexField, queryVector []float64) (string, []any) { //nolint:lll // for readability
+	operator, _ := metadata.VectorOperator(field.Similarity)
+
+	elements := make([]string, len(queryVector))
+	for i, f := range queryVector {
+		elements[i] = strconv.FormatFloat(f, 'g', -1, 64)
+	}
+
+	orderBy := fmt.Sprintf(
+		" ORDER BY %s %s %s::vector(%d)",
+		metadata.VectorExpression(dbName, field.Path, field.Dimensions), operator, p.Next(), field.Dimensions,
+	)
+
+	return orderBy, []any{"[" + strings.Join(elements, ",") + "]"}
+}
+
 // filterEqual returns the proper SQL filter with arguments that filters documents
 // where the value under k is equal to v.
 func filterEqual(p *metadata.Placeholder, k any, v any, operator string) (filter string, args []any) {
@@ -329,3 +576,184 @@ func filterEqual(p *metadata.Placeholder, k any, v any, operator string) (filter
 
 	return
 }
+
+// filterJSONPath returns the proper SQL filter with arguments that filters documents
+// where the value under the given path (possibly in dot notation) matches the given condition.
+//
+// The condition is converted to jsonpath predicates that are evaluated in lax mode,
+// so arrays on the path (including arrays of documents) are unwrapped automatically.
+// Only parts of the condition that could be converted safely are included;
+// the rest is applied by the handler. Empty filter is returned if nothing could be converted.
+func filterJSONPath(p *metadata.Placeholder, path string, cond any) (filter string, args []any) {
+	accessor := jsonPathAccessor(path)
+	if accessor == "" {
+		return
+	}
+
+	var filters []string
+
+	// each predicate is checked separately, as $all elements may be matched by different array elements
+	for _, pred := range jsonPathPredicates("@", cond) {
+		filters = append(filters, fmt.Sprintf(`%s @? %s::text::jsonpath`, metadata.DefaultColumn, p.Next()))
+		args = append(args, "$"+accessor+" ? ("+pred+")")
+	}
+
+	filter = strings.Join(filters, " AND ")
+
+	return
+}
+
+// jsonPathAccessor returns jsonpath member accessors for the given dot notation path, such as `."v"."foo"`.
+//
+// Empty string is returned for paths that can't be converted safely.
+// That includes numeric path elements, as they could be both array indexes and field names.
+func jsonPathAccessor(path string) string {
+	p, err := types.NewPathFromString(path)
+	if err != nil {
+		return ""
+	}
+
+	var res string
+
+	for _, e := range p.Slice() {
+		if _, err = strconv.Atoi(e); err == nil {
+			return ""
+		}
+
+		if strings.HasPrefix(e, "$") || strings.ContainsRune(e, 0) {
+			return ""
+		}
+
+		res += "." + string(must.NotFail(json.Marshal(e)))
+	}
+
+	return res
+}
+
+// jsonPathPredicates returns jsonpath predicates that check that the value of the given expression,
+// such as `@` or `@."foo"`, matches the given condition.
+//
+// Implicit equality, $eq, $all, and $elemMatch (with the same conditions inside) are supported.
+// Returned predicates should be combined with AND.
+func jsonPathPredicates(expr string, cond any) []string {
+	doc, ok := cond.(*types.Document)
+	if !ok {
+		if lit := jsonPathLiteral(cond); lit != "" {
+			return []string{expr + " == " + lit}
+		}
+
+		return nil
+	}
+
+	// document equality is not supported
+	if doc.Len() == 0 || !strings.HasPrefix(doc.Keys()[0], "$") {
+		return nil
+	}
+
+	var res []string
+
+	for _, op := range doc.Keys() {
+		v := must.NotFail(doc.Get(op))
+
+		switch op {
+		case "$eq":
+			if _, ok = v.(*types.Document); !ok {
+				res = append(res, jsonPathPredicates(expr, v)...)
+			}
+
+		case "$all":
+			arr, ok := v.(*types.Array)
+			if !ok {
+				continue
+			}
+
+			for i := 0; i < arr.Len(); i++ {
+				elem := must.NotFail(arr.Get(i))
+
+				// only literal values and $elemMatch conditions are allowed
+				if d, ok := elem.(*types.Document); ok && (d.Len() != 1 || !d.Has("$elemMatch")) {
+					continue
+				}
+
+				res = append(res, jsonPathPredicates(expr, elem)...)
+			}
+
+		case "$elemMatch":
+			em, ok := v.(*types.Document)
+			if !ok {
+				continue
+			}
+
+			if preds := jsonPathElemMatch(em); len(preds) > 0 {
+				res = append(res, fmt.Sprintf("exists(%s[*] ? (%s))", expr, strings.Join(preds, " && ")))
+			}
+		}
+	}
+
+	return res
+}
+
+// jsonPathElemMatch returns jsonpath predicates for the current array element (`@`)
+// that match the given $elemMatch condition.
+func jsonPathElemMatch(cond *types.Document) []string {
+	if cond.Len() == 0 {
+		return nil
+	}
+
+	// conditions for the element itself, such as {$eq: 42}
+	if strings.HasPrefix(cond.Keys()[0], "$") {
+		return jsonPathPredicates("@", cond)
+	}
+
+	var res []string
+
+	for _, k := range cond.Keys() {
+		accessor := jsonPathAccessor(k)
+		if accessor == "" {
+			continue
+		}
+
+		res = append(res, jsonPathPredicates("@"+accessor, must.NotFail(cond.Get(k)))...)
+	}
+
+	return res
+}
+
+// jsonPathLiteral returns jsonpath literal for the given value as it is stored in the database.
+//
+// Empty string is returned for values that can't be compared safely.
+func jsonPathLiteral(v any) string {
+	switch v := v.(type) {
+	case string:
+		// PostgreSQL does not support \u0000 in jsonb and jsonpath
+		if strings.ContainsRune(v, 0) {
+			return ""
+		}
+
+		return string(must.NotFail(sjson.MarshalSingleValue(v)))
+
+	case types.ObjectID, time.Time, bool, int32:
+		return string(must.NotFail(sjson.MarshalSingleValue(v)))
+
+	case int64:
+		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+		maxSafeDouble := int64(types.MaxSafeDouble)
+		if v > maxSafeDouble || v < -maxSafeDouble {
+			return ""
+		}
+
+		return strconv.FormatInt(v, 10)
+
+	case float64:
+		// TODO https://github.com/FerretDB/FerretDB/issues/3626
+		if math.IsNaN(v) || v > types.MaxSafeDouble || v < -types.MaxSafeDouble {
+			return ""
+		}
+
+		// jsonpath does not support all exponent forms, so use plain notation
+		return strconv.FormatFloat(v, 'f', -1, 64)
+
+	default:
+		return ""
+	}
+}
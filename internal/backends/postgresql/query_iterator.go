@@ -103,14 +103,21 @@ func (iter *queryIterator) Next() (struct{}, *types.Document, error) {
 	var recordID int64
 	var b []byte
 	var dest []any
+	var isBSON bool
 
 	switch {
 	case slices.Equal(columns, []string{metadata.RecordIDColumn, metadata.DefaultColumn}):
 		dest = []any{&recordID, &b}
+	case slices.Equal(columns, []string{metadata.RecordIDColumn, metadata.BSONColumn}):
+		dest = []any{&recordID, &b}
+		isBSON = true
 	case slices.Equal(columns, []string{metadata.RecordIDColumn}):
 		dest = []any{&recordID}
 	case slices.Equal(columns, []string{metadata.DefaultColumn}):
 		dest = []any{&b}
+	case slices.Equal(columns, []string{metadata.BSONColumn}):
+		dest = []any{&b}
+		isBSON = true
 	default:
 		panic(fmt.Sprintf("cannot scan unknown columns: %v", columns))
 	}
@@ -124,7 +131,13 @@ func (iter *queryIterator) Next() (struct{}, *types.Document, error) {
 	doc := must.NotFail(types.NewDocument())
 
 	if !iter.onlyRecordIDs {
-		if doc, err = sjson.Unmarshal(b); err != nil {
+		if isBSON {
+			doc, err = unmarshalBSON(b)
+		} else {
+			doc, err = sjson.Unmarshal(b)
+		}
+
+		if err != nil {
 			iter.close()
 			return unused, nil, lazyerrors.Error(err)
 		}
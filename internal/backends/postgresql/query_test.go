@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/postgresql/metadata"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -95,7 +96,8 @@ func TestPrepareWhereClause(t *testing.T) {
 
 	// WHERE clauses occurring frequently in tests
 	whereContain := " WHERE _jsonb->$1 @> $2"
-	whereContainDotNotation := " WHERE _jsonb#>$1 @> $2"
+	whereJSONPath := " WHERE _jsonb @? $1::text::jsonpath"
+	whereJSONPath2 := " WHERE _jsonb @? $1::text::jsonpath AND _jsonb @? $2::text::jsonpath"
 
 	whereGt := " WHERE _jsonb->$1 > $2"
 	whereNotEq := ` WHERE NOT ( _jsonb ? $1 AND _jsonb->$1 @> $2 AND _jsonb->'$s'->'p'->$1->'t' = `
@@ -120,16 +122,101 @@ func TestPrepareWhereClause(t *testing.T) {
 		},
 		"IDDotNotation": {
 			filter:   must.NotFail(types.NewDocument("_id.doc", "foo")),
-			expected: whereContainDotNotation,
+			args:     []any{`$."_id"."doc" ? (@ == "foo")`},
+			expected: whereJSONPath,
 		},
 
 		"DotNotation": {
 			filter:   must.NotFail(types.NewDocument("v.doc", "foo")),
-			expected: whereContainDotNotation,
+			args:     []any{`$."v"."doc" ? (@ == "foo")`},
+			expected: whereJSONPath,
+		},
+		"DotNotationEq": {
+			filter: must.NotFail(types.NewDocument(
+				"v.doc", must.NotFail(types.NewDocument("$eq", int64(42))),
+			)),
+			args:     []any{`$."v"."doc" ? (@ == 42)`},
+			expected: whereJSONPath,
+		},
+		"DotNotationSpecialCharacters": {
+			filter:   must.NotFail(types.NewDocument("v.f\"o[*]", 42.13)),
+			args:     []any{`$."v"."f\"o[*]" ? (@ == 42.13)`},
+			expected: whereJSONPath,
 		},
 		"DotNotationArrayIndex": {
-			filter:   must.NotFail(types.NewDocument("v.arr.0", "foo")),
-			expected: whereContainDotNotation,
+			filter: must.NotFail(types.NewDocument("v.arr.0", "foo")),
+		},
+		"DotNotationNe": {
+			filter: must.NotFail(types.NewDocument(
+				"v.doc", must.NotFail(types.NewDocument("$ne", "foo")),
+			)),
+		},
+		"DotNotationMaxFloat64": {
+			filter: must.NotFail(types.NewDocument("v.doc", math.MaxFloat64)),
+		},
+
+		"ElemMatchFields": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$elemMatch", must.NotFail(types.NewDocument(
+					"foo", "bar",
+					"baz.qux", must.NotFail(types.NewDocument("$eq", true)),
+					"quux", must.NotFail(types.NewDocument("$gt", int32(1))),
+				)))),
+			)),
+			args:     []any{`$."v" ? (exists(@[*] ? (@."foo" == "bar" && @."baz"."qux" == true)))`},
+			expected: whereJSONPath,
+		},
+		"ElemMatchOperators": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$elemMatch", must.NotFail(types.NewDocument(
+					"$eq", int32(42),
+				)))),
+			)),
+			args:     []any{`$."v" ? (exists(@[*] ? (@ == 42)))`},
+			expected: whereJSONPath,
+		},
+		"ElemMatchNested": {
+			filter: must.NotFail(types.NewDocument(
+				"v.foo", must.NotFail(types.NewDocument("$elemMatch", must.NotFail(types.NewDocument(
+					"bar", must.NotFail(types.NewDocument("$elemMatch", must.NotFail(types.NewDocument(
+						"baz", objectID,
+					)))),
+				)))),
+			)),
+			args:     []any{`$."v"."foo" ? (exists(@[*] ? (exists(@."bar"[*] ? (@."baz" == "6256c5ba0badc0ffeeffffff")))))`},
+			expected: whereJSONPath,
+		},
+		"ElemMatchUnsupported": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$elemMatch", must.NotFail(types.NewDocument(
+					"$gt", int32(0),
+					"$lt", int32(42),
+				)))),
+			)),
+		},
+
+		"All": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$all", must.NotFail(types.NewArray(
+					"foo", int32(42), types.Null, must.NotFail(types.NewDocument("foo", "bar")),
+				)))),
+			)),
+			args:     []any{`$."v" ? (@ == "foo")`, `$."v" ? (@ == 42)`},
+			expected: whereJSONPath2,
+		},
+		"AllElemMatch": {
+			filter: must.NotFail(types.NewDocument(
+				"v.foo", must.NotFail(types.NewDocument("$all", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("$elemMatch", must.NotFail(types.NewDocument("bar", int32(1))))),
+				)))),
+			)),
+			args:     []any{`$."v"."foo" ? (exists(@[*] ? (@."bar" == 1)))`},
+			expected: whereJSONPath,
+		},
+		"AllEmpty": {
+			filter: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$all", must.NotFail(types.NewArray()))),
+			)),
 		},
 
 		"ImplicitString": {
@@ -322,12 +409,48 @@ func TestPrepareWhereClause(t *testing.T) {
 	}
 }
 
+func TestPrepareFilterPushdownPaths(t *testing.T) {
+	t.Parallel()
+
+	filter := must.NotFail(types.NewDocument(
+		"v", "foo",
+		"v.foo", must.NotFail(types.NewDocument("$gt", int32(42))),
+		"v.bar", must.NotFail(types.NewDocument("$elemMatch", must.NotFail(types.NewDocument("baz", int32(42))))),
+		"$comment", "I'm comment",
+		"$or", must.NotFail(types.NewArray()),
+	))
+
+	actual, err := prepareFilterPushdownPaths(new(metadata.Collection), filter)
+	require.NoError(t, err)
+
+	expected := must.NotFail(types.NewDocument(
+		"v", true,
+		"v.foo", false,
+		"v.bar", true,
+		"$or", false,
+	))
+	assert.Equal(t, expected, actual)
+
+	meta := &metadata.Collection{Codec: metadata.CodecHybrid, CodecFields: []string{"w"}}
+
+	actual, err = prepareFilterPushdownPaths(meta, must.NotFail(types.NewDocument("v", "foo", "w", "bar")))
+	require.NoError(t, err)
+
+	expected = must.NotFail(types.NewDocument(
+		"v", false,
+		"w", true,
+	))
+	assert.Equal(t, expected, actual)
+}
+
 func TestPrepareOrderByClause(t *testing.T) {
 	t.Parallel()
 
 	for name, tc := range map[string]struct { //nolint:vet // used for test only
-		sort *types.Document
-		skip string
+		sort      *types.Document
+		clustered bool
+		capped    bool
+		skip      string
 
 		orderBy string
 		args    []any
@@ -356,12 +479,23 @@ func TestPrepareOrderByClause(t *testing.T) {
 		},
 		"NaturalAscending": {
 			sort:    must.NotFail(types.NewDocument("$natural", int64(1))),
+			capped:  true,
 			orderBy: ` ORDER BY _ferretdb_record_id`,
 		},
 		"NaturalDescending": {
 			sort:    must.NotFail(types.NewDocument("$natural", int64(-1))),
+			capped:  true,
 			orderBy: ` ORDER BY _ferretdb_record_id DESC`,
 		},
+		"NaturalNonCapped": {
+			sort:    must.NotFail(types.NewDocument("$natural", int64(1))),
+			orderBy: ` ORDER BY ctid`,
+		},
+		"NaturalClustered": {
+			sort:      must.NotFail(types.NewDocument("$natural", int64(-1))),
+			clustered: true,
+			orderBy:   ` ORDER BY _jsonb->'_id' DESC`,
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
@@ -371,10 +505,146 @@ func TestPrepareOrderByClause(t *testing.T) {
 				t.Skip(tc.skip)
 			}
 
-			orderBy, args := prepareOrderByClause(tc.sort)
+			orderBy, args := prepareOrderByClause(tc.sort, tc.clustered, tc.capped)
+
+			assert.Equal(t, tc.orderBy, orderBy)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
+
+func TestPrepareSearchClause(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		index  *metadata.SearchIndexInfo
+		params *backends.SearchParams
 
+		where   string
+		orderBy string
+		args    []any
+	}{
+		"Text": {
+			index: &metadata.SearchIndexInfo{Name: "default"},
+			params: &backends.SearchParams{
+				Operator: backends.SearchText,
+				Query:    "quick fox",
+				Paths:    []string{"title", "v.plot"},
+			},
+			where: ` WHERE (to_tsvector('english'::regconfig, COALESCE(_jsonb #>> ARRAY['title'], '')) @@ ` +
+				`replace(plainto_tsquery('english'::regconfig, $1)::text, '&', '|')::tsquery OR ` +
+				`to_tsvector('english'::regconfig, COALESCE(_jsonb #>> ARRAY['v', 'plot'], '')) @@ ` +
+				`replace(plainto_tsquery('english'::regconfig, $1)::text, '&', '|')::tsquery)`,
+			orderBy: ` ORDER BY ts_rank(to_tsvector('english'::regconfig, COALESCE(_jsonb #>> ARRAY['title'], '')) || ` +
+				`to_tsvector('english'::regconfig, COALESCE(_jsonb #>> ARRAY['v', 'plot'], '')), ` +
+				`replace(plainto_tsquery('english'::regconfig, $1)::text, '&', '|')::tsquery) DESC`,
+			args: []any{"quick fox"},
+		},
+		"PhraseDynamic": {
+			index: &metadata.SearchIndexInfo{Name: "default", Dynamic: true},
+			params: &backends.SearchParams{
+				Operator: backends.SearchPhrase,
+				Query:    "quick fox",
+				Paths:    []string{"it's"},
+			},
+			where: ` WHERE jsonb_to_tsvector('english'::regconfig, _jsonb - '$s', '["string"]') @@ ` +
+				`phraseto_tsquery('english'::regconfig, $1) AND ` +
+				`(to_tsvector('english'::regconfig, COALESCE(_jsonb #>> ARRAY['it''s'], '')) @@ ` +
+				`phraseto_tsquery('english'::regconfig, $1))`,
+			orderBy: ` ORDER BY ts_rank(to_tsvector('english'::regconfig, COALESCE(_jsonb #>> ARRAY['it''s'], '')), ` +
+				`phraseto_tsquery('english'::regconfig, $1)) DESC`,
+			args: []any{"quick fox"},
+		},
+		"Autocomplete": {
+			index: &metadata.SearchIndexInfo{Name: "default", Dynamic: true},
+			params: &backends.SearchParams{
+				Operator: backends.SearchAutocomplete,
+				Query:    "qu.",
+				Paths:    []string{"title"},
+			},
+			where: ` WHERE ((_jsonb #>> ARRAY['title']) ~* $1)`,
+			args:  []any{`\mqu\.`},
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var placeholder metadata.Placeholder
+
+			where, orderBy, args := prepareSearchClause(&placeholder, tc.index, tc.params)
+
+			assert.Equal(t, tc.where, where)
 			assert.Equal(t, tc.orderBy, orderBy)
 			assert.Equal(t, tc.args, args)
 		})
 	}
 }
+
+func TestPrepareVectorOrderByClause(t *testing.T) {
+	t.Parallel()
+
+	field := &metadata.VectorSearchIndexField{
+		Path:       "v.embedding",
+		Dimensions: 3,
+		Similarity: "cosine",
+	}
+
+	var placeholder metadata.Placeholder
+	placeholder.Next()
+
+	orderBy, args := prepareVectorOrderByClause(&placeholder, "db", field, []float64{1, -0.5, 1e-7})
+
+	expected := ` ORDER BY ("db"."_ferretdb_vector"(_jsonb #> ARRAY['v', 'embedding'], 3)::vector(3)) <=> $2::vector(3)`
+	assert.Equal(t, expected, orderBy)
+	assert.Equal(t, []any{"[1,-0.5,1e-07]"}, args)
+}
+
+func TestPrepareTimeSeriesClause(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	filter := must.NotFail(types.NewDocument(
+		"t", must.NotFail(types.NewDocument("$gte", from, "$lt", to, "$ne", to)),
+		"meta", "a",
+	))
+
+	var placeholder metadata.Placeholder
+	placeholder.Next()
+
+	where, args := prepareTimeSeriesClause(&placeholder, ` WHERE x`, "t", filter)
+
+	expr := `(CASE WHEN jsonb_typeof(_jsonb -> 't') = 'number' THEN (_jsonb ->> 't')::numeric END)`
+	expected := ` WHERE x AND (` + expr + ` IS NULL OR ` + expr + ` >= $2) AND (` + expr + ` IS NULL OR ` + expr + ` < $3)`
+	assert.Equal(t, expected, where)
+	assert.Equal(t, []any{from.UnixMilli(), to.UnixMilli()}, args)
+
+	where, args = prepareTimeSeriesClause(&placeholder, "", "t", must.NotFail(types.NewDocument("t", from)))
+	assert.Empty(t, where)
+	assert.Nil(t, args)
+}
+
+func TestPrepareClusteredClause(t *testing.T) {
+	t.Parallel()
+
+	id := types.ObjectID{0x65, 0x92, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}
+
+	filter := must.NotFail(types.NewDocument(
+		"_id", must.NotFail(types.NewDocument("$gt", id, "$lt", "x", "$ne", id)),
+	))
+
+	var placeholder metadata.Placeholder
+	placeholder.Next()
+
+	where, args := prepareClusteredClause(&placeholder, ` WHERE x`, filter)
+
+	expected := ` WHERE x AND _jsonb->'$s'->'p'->'_id'->'t' = '"objectId"' AND _jsonb->'_id' > $2`
+	assert.Equal(t, expected, where)
+	assert.Equal(t, []any{`"659200800000000000000001"`}, args)
+
+	where, args = prepareClusteredClause(&placeholder, "", must.NotFail(types.NewDocument("_id", id)))
+	assert.Empty(t, where)
+	assert.Nil(t, args)
+}
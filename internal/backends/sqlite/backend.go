@@ -141,6 +141,30 @@ func (b *backend) DropDatabase(ctx context.Context, params *backends.DropDatabas
 	return nil
 }
 
+// Checkpoint implements backends.Backend interface.
+//
+// It checkpoints and truncates write-ahead logs of all databases.
+func (b *backend) Checkpoint(ctx context.Context, params *backends.CheckpointParams) error {
+	for _, dbName := range b.r.DatabaseList(ctx) {
+		db := b.r.DatabaseGetExisting(ctx, dbName)
+		if db == nil {
+			continue
+		}
+
+		var busy, logFrames, checkpointedFrames int
+		row := db.QueryRowContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)")
+		if err := row.Scan(&busy, &logFrames, &checkpointedFrames); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if busy != 0 {
+			return lazyerrors.Errorf("checkpoint of database %q was blocked", dbName)
+		}
+	}
+
+	return nil
+}
+
 // Describe implements prometheus.Collector.
 func (b *backend) Describe(ch chan<- *prometheus.Desc) {
 	b.r.Describe(ch)
@@ -82,7 +82,7 @@ func (c *collection) Query(ctx context.Context, params *backends.QueryParams) (*
 	}
 
 	q += whereClause
-	q += prepareOrderByClause(params.Sort)
+	q += prepareOrderByClause(params.Sort, meta.Settings.Clustered, meta.Capped())
 
 	if params.Limit != 0 {
 		q += ` LIMIT ?`
@@ -283,7 +283,7 @@ func (c *collection) Explain(ctx context.Context, params *backends.ExplainParams
 		}
 	}
 
-	orderByClause := prepareOrderByClause(params.Sort)
+	orderByClause := prepareOrderByClause(params.Sort, meta.Settings.Clustered, meta.Capped())
 	sortPushdown := orderByClause != ""
 
 	q := `EXPLAIN QUERY PLAN ` + selectClause + whereClause + orderByClause
@@ -529,6 +529,71 @@ func (c *collection) DropIndexes(ctx context.Context, params *backends.DropIndex
 	return new(backends.DropIndexesResult), nil
 }
 
+// ReIndex implements backends.Collection interface.
+func (c *collection) ReIndex(ctx context.Context, params *backends.ReIndexParams) (*backends.ReIndexResult, error) {
+	db := c.r.DatabaseGetExisting(ctx, c.dbName)
+	if db == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeDatabaseDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	coll := c.r.CollectionGet(ctx, c.dbName, c.name)
+	if coll == nil {
+		return nil, backends.NewError(
+			backends.ErrorCodeCollectionDoesNotExist,
+			lazyerrors.Errorf("no ns %s.%s", c.dbName, c.name),
+		)
+	}
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("REINDEX %q", coll.TableName)); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return new(backends.ReIndexResult), nil
+}
+
+// Search implements backends.Collection interface.
+func (c *collection) Search(ctx context.Context, params *backends.SearchParams) (*backends.SearchResult, error) {
+	return nil, backends.NewError(
+		backends.ErrorCodeNotSupported,
+		lazyerrors.New("full-text search is not supported by SQLite backend"),
+	)
+}
+
+// VectorSearch implements backends.Collection interface.
+func (c *collection) VectorSearch(ctx context.Context, params *backends.VectorSearchParams) (*backends.VectorSearchResult, error) { //nolint:lll // for readability
+	return nil, backends.NewError(
+		backends.ErrorCodeNotSupported,
+		lazyerrors.New("vector search is not supported by SQLite backend"),
+	)
+}
+
+// ListSearchIndexes implements backends.Collection interface.
+func (c *collection) ListSearchIndexes(ctx context.Context, params *backends.ListSearchIndexesParams) (*backends.ListSearchIndexesResult, error) { //nolint:lll // for readability
+	return nil, backends.NewError(
+		backends.ErrorCodeNotSupported,
+		lazyerrors.New("full-text search is not supported by SQLite backend"),
+	)
+}
+
+// CreateSearchIndexes implements backends.Collection interface.
+func (c *collection) CreateSearchIndexes(ctx context.Context, params *backends.CreateSearchIndexesParams) (*backends.CreateSearchIndexesResult, error) { //nolint:lll // for readability
+	return nil, backends.NewError(
+		backends.ErrorCodeNotSupported,
+		lazyerrors.New("full-text search is not supported by SQLite backend"),
+	)
+}
+
+// DropSearchIndexes implements backends.Collection interface.
+func (c *collection) DropSearchIndexes(ctx context.Context, params *backends.DropSearchIndexesParams) (*backends.DropSearchIndexesResult, error) { //nolint:lll // for readability
+	return nil, backends.NewError(
+		backends.ErrorCodeNotSupported,
+		lazyerrors.New("full-text search is not supported by SQLite backend"),
+	)
+}
+
 // check interfaces
 var (
 	_ backends.Collection = (*collection)(nil)
@@ -21,6 +21,7 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
@@ -74,10 +75,27 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 	res = make([]backends.CollectionInfo, len(list))
 	for i, c := range list {
 		res[i] = backends.CollectionInfo{
-			Name:            c.Name,
-			UUID:            c.Settings.UUID,
-			CappedSize:      c.Settings.CappedSize,
-			CappedDocuments: c.Settings.CappedDocuments,
+			Name:             c.Name,
+			UUID:             c.Settings.UUID,
+			CappedSize:       c.Settings.CappedSize,
+			CappedDocuments:  c.Settings.CappedDocuments,
+			ValidationLevel:  c.Settings.ValidationLevel,
+			ValidationAction: c.Settings.ValidationAction,
+			Clustered:        c.Settings.Clustered,
+		}
+
+		if c.Settings.Validator != nil {
+			if res[i].Validator, err = sjson.Unmarshal(c.Settings.Validator); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		if ts := c.Settings.TimeSeries; ts != nil {
+			res[i].TimeSeries = &backends.TimeSeriesOptions{
+				TimeField:   ts.TimeField,
+				MetaField:   ts.MetaField,
+				Granularity: ts.Granularity,
+			}
 		}
 	}
 
@@ -88,12 +106,26 @@ func (db *database) ListCollections(ctx context.Context, params *backends.ListCo
 
 // CreateCollection implements backends.Database interface.
 func (db *database) CreateCollection(ctx context.Context, params *backends.CreateCollectionParams) error {
-	created, err := db.r.CollectionCreate(ctx, &metadata.CollectionCreateParams{
-		DBName:          db.name,
-		Name:            params.Name,
-		CappedSize:      params.CappedSize,
-		CappedDocuments: params.CappedDocuments,
-	})
+	p := &metadata.CollectionCreateParams{
+		DBName:           db.name,
+		Name:             params.Name,
+		CappedSize:       params.CappedSize,
+		CappedDocuments:  params.CappedDocuments,
+		Validator:        params.Validator,
+		ValidationLevel:  params.ValidationLevel,
+		ValidationAction: params.ValidationAction,
+		Clustered:        params.Clustered,
+	}
+
+	if ts := params.TimeSeries; ts != nil {
+		p.TimeSeries = &metadata.TimeSeries{
+			TimeField:   ts.TimeField,
+			MetaField:   ts.MetaField,
+			Granularity: ts.Granularity,
+		}
+	}
+
+	created, err := db.r.CollectionCreate(ctx, p)
 	if err != nil {
 		return lazyerrors.Error(err)
 	}
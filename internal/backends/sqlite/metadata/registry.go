@@ -22,6 +22,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
@@ -30,6 +31,8 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/sqlite/metadata/pool"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/fsql"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -59,13 +62,20 @@ type Registry struct {
 	p *pool.Pool
 	l *zap.Logger
 
-	// rw protects colls but also acts like a global lock for the whole registry.
-	// The latter effectively replaces transactions (see the sqlite backend package description for more info).
+	// mu serializes metadata modifications and acts like a global lock for all of them.
+	// That effectively replaces transactions (see the sqlite backend package description for more info).
 	// One global lock should be replaced by more granular locks – one per database or even one per collection.
 	// But that requires some redesign.
 	// TODO https://github.com/FerretDB/FerretDB/issues/2755
-	rw    sync.RWMutex
-	colls map[string]map[string]*Collection // database name -> collection name -> collection
+	mu sync.Mutex
+
+	// colls is an immutable snapshot of database name -> collection name -> collection mapping.
+	// Readers load it without locking; writers hold mu, copy the affected maps, and store the new snapshot.
+	colls atomic.Pointer[map[string]map[string]*Collection]
+
+	// cacheHits and cacheMisses count collection lookups in the snapshot.
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
 }
 
 // NewRegistry creates a registry for SQLite databases in the directory specified by SQLite URI.
@@ -76,18 +86,21 @@ func NewRegistry(u string, l *zap.Logger, sp *state.Provider) (*Registry, error)
 	}
 
 	r := &Registry{
-		p:     p,
-		l:     l,
-		colls: map[string]map[string]*Collection{},
+		p: p,
+		l: l,
 	}
 
+	colls := make(map[string]map[string]*Collection, len(initDBs))
+
 	for name, db := range initDBs {
-		if err = r.initCollections(context.Background(), name, db); err != nil {
+		if colls[name], err = r.initCollections(context.Background(), name, db); err != nil {
 			r.Close()
 			return nil, lazyerrors.Error(err)
 		}
 	}
 
+	r.colls.Store(&colls)
+
 	return r, nil
 }
 
@@ -97,12 +110,12 @@ func (r *Registry) Close() {
 }
 
 // initCollections loads collections metadata from the database during initialization.
-func (r *Registry) initCollections(ctx context.Context, dbName string, db *fsql.DB) error {
+func (r *Registry) initCollections(ctx context.Context, dbName string, db *fsql.DB) (map[string]*Collection, error) {
 	defer observability.FuncCall(ctx)()
 
 	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT name, table_name, settings FROM %q", metadataTableName))
 	if err != nil {
-		return lazyerrors.Error(err)
+		return nil, lazyerrors.Error(err)
 	}
 	defer rows.Close()
 
@@ -111,19 +124,17 @@ func (r *Registry) initCollections(ctx context.Context, dbName string, db *fsql.
 	for rows.Next() {
 		var c Collection
 		if err = rows.Scan(&c.Name, &c.TableName, &c.Settings); err != nil {
-			return lazyerrors.Error(err)
+			return nil, lazyerrors.Error(err)
 		}
 
 		colls[c.Name] = &c
 	}
 
 	if err = rows.Err(); err != nil {
-		return lazyerrors.Error(err)
+		return nil, lazyerrors.Error(err)
 	}
 
-	r.colls[dbName] = colls
-
-	return nil
+	return colls, nil
 }
 
 // DatabaseList returns a sorted list of existing databases.
@@ -144,8 +155,8 @@ func (r *Registry) DatabaseGetExisting(ctx context.Context, dbName string) *fsql
 func (r *Registry) DatabaseGetOrCreate(ctx context.Context, dbName string) (*fsql.DB, error) {
 	defer observability.FuncCall(ctx)()
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.databaseGetOrCreate(ctx, dbName)
 }
@@ -187,8 +198,8 @@ func (r *Registry) databaseGetOrCreate(ctx context.Context, dbName string) (*fsq
 func (r *Registry) DatabaseDrop(ctx context.Context, dbName string) bool {
 	defer observability.FuncCall(ctx)()
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.databaseDrop(ctx, dbName)
 }
@@ -201,7 +212,9 @@ func (r *Registry) DatabaseDrop(ctx context.Context, dbName string) bool {
 func (r *Registry) databaseDrop(ctx context.Context, dbName string) bool {
 	defer observability.FuncCall(ctx)()
 
-	delete(r.colls, dbName)
+	if r.snapshot()[dbName] != nil {
+		r.storeDatabase(dbName, nil)
+	}
 
 	return r.p.Drop(ctx, dbName)
 }
@@ -217,15 +230,13 @@ func (r *Registry) CollectionList(ctx context.Context, dbName string) ([]*Collec
 		return nil, nil
 	}
 
-	r.rw.RLock()
+	colls := r.snapshot()[dbName]
 
-	res := make([]*Collection, 0, len(r.colls[dbName]))
-	for _, c := range r.colls[dbName] {
+	res := make([]*Collection, 0, len(colls))
+	for _, c := range colls {
 		res = append(res, c.deepCopy())
 	}
 
-	r.rw.RUnlock()
-
 	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
 	return res, nil
 }
@@ -236,7 +247,15 @@ type CollectionCreateParams struct {
 	Name            string
 	CappedSize      int64
 	CappedDocuments int64
-	_               struct{} // prevent unkeyed literals
+
+	Validator        *types.Document
+	ValidationLevel  string
+	ValidationAction string
+
+	TimeSeries *TimeSeries
+	Clustered  bool
+
+	_ struct{} // prevent unkeyed literals
 }
 
 // Capped returns true if capped collection creation is requested.
@@ -252,8 +271,13 @@ func (ccp *CollectionCreateParams) Capped() bool {
 func (r *Registry) CollectionCreate(ctx context.Context, params *CollectionCreateParams) (bool, error) {
 	defer observability.FuncCall(ctx)()
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	// fast path for the common case of inserting into an existing collection
+	if r.lookup(params.DBName, params.Name) != nil {
+		return false, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.collectionCreate(ctx, params)
 }
@@ -275,7 +299,7 @@ func (r *Registry) collectionCreate(ctx context.Context, params *CollectionCreat
 		return false, lazyerrors.Error(err)
 	}
 
-	colls := r.colls[dbName]
+	colls := r.snapshot()[dbName]
 	if colls != nil && colls[collectionName] != nil {
 		return false, nil
 	}
@@ -301,6 +325,23 @@ func (r *Registry) collectionCreate(ctx context.Context, params *CollectionCreat
 		s++
 	}
 
+	settings := Settings{
+		UUID:            uuid.NewString(),
+		CappedSize:      params.CappedSize,
+		CappedDocuments: params.CappedDocuments,
+		TimeSeries:      params.TimeSeries,
+		Clustered:       params.Clustered,
+	}
+
+	if params.Validator != nil {
+		if settings.Validator, err = sjson.Marshal(params.Validator); err != nil {
+			return false, lazyerrors.Error(err)
+		}
+
+		settings.ValidationLevel = params.ValidationLevel
+		settings.ValidationAction = params.ValidationAction
+	}
+
 	q := fmt.Sprintf("CREATE TABLE %q (", tableName)
 
 	if params.Capped() {
@@ -319,18 +360,11 @@ func (r *Registry) collectionCreate(ctx context.Context, params *CollectionCreat
 		return false, lazyerrors.Error(err)
 	}
 
-	if r.colls[dbName] == nil {
-		r.colls[dbName] = map[string]*Collection{}
-	}
-	r.colls[dbName][collectionName] = &Collection{
+	r.storeCollection(dbName, collectionName, &Collection{
 		Name:      collectionName,
 		TableName: tableName,
-		Settings: Settings{
-			UUID:            uuid.NewString(),
-			CappedSize:      params.CappedSize,
-			CappedDocuments: params.CappedDocuments,
-		},
-	}
+		Settings:  settings,
+	})
 
 	err = r.indexesCreate(ctx, dbName, collectionName, []IndexInfo{{
 		Name:   backends.DefaultIndexName,
@@ -352,10 +386,7 @@ func (r *Registry) collectionCreate(ctx context.Context, params *CollectionCreat
 func (r *Registry) CollectionGet(ctx context.Context, dbName, collectionName string) *Collection {
 	defer observability.FuncCall(ctx)()
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
-
-	return r.collectionGet(dbName, collectionName)
+	return r.lookup(dbName, collectionName).deepCopy()
 }
 
 // collectionGet returns a copy of collection metadata.
@@ -365,7 +396,7 @@ func (r *Registry) CollectionGet(ctx context.Context, dbName, collectionName str
 //
 // It does not hold the lock.
 func (r *Registry) collectionGet(dbName, collectionName string) *Collection {
-	colls := r.colls[dbName]
+	colls := r.snapshot()[dbName]
 	if colls == nil {
 		return nil
 	}
@@ -373,6 +404,72 @@ func (r *Registry) collectionGet(dbName, collectionName string) *Collection {
 	return colls[collectionName].deepCopy()
 }
 
+// snapshot returns the current snapshot of collections metadata.
+//
+// It does not hold the lock. Returned maps must not be modified.
+func (r *Registry) snapshot() map[string]map[string]*Collection {
+	if colls := r.colls.Load(); colls != nil {
+		return *colls
+	}
+
+	return nil
+}
+
+// lookup returns collection metadata from the snapshot and counts cache hit or miss.
+// If database or collection does not exist, nil is returned.
+//
+// It does not hold the lock. Returned collection must not be modified.
+func (r *Registry) lookup(dbName, collectionName string) *Collection {
+	c := r.snapshot()[dbName][collectionName]
+	if c == nil {
+		r.cacheMisses.Add(1)
+		return nil
+	}
+
+	r.cacheHits.Add(1)
+
+	return c
+}
+
+// storeDatabase stores a new snapshot with the given database collections.
+// If colls is nil, the database is removed from the snapshot.
+//
+// It should be called with the lock held.
+func (r *Registry) storeDatabase(dbName string, colls map[string]*Collection) {
+	old := r.snapshot()
+
+	res := make(map[string]map[string]*Collection, len(old)+1)
+	maps.Copy(res, old)
+
+	if colls == nil {
+		delete(res, dbName)
+	} else {
+		res[dbName] = colls
+	}
+
+	r.colls.Store(&res)
+}
+
+// storeCollection stores a new snapshot with the given collection.
+// If c is nil, the collection is removed from the snapshot.
+// The database is added to the snapshot if needed.
+//
+// It should be called with the lock held.
+func (r *Registry) storeCollection(dbName, collectionName string, c *Collection) {
+	old := r.snapshot()[dbName]
+
+	colls := make(map[string]*Collection, len(old)+1)
+	maps.Copy(colls, old)
+
+	if c == nil {
+		delete(colls, collectionName)
+	} else {
+		colls[collectionName] = c
+	}
+
+	r.storeDatabase(dbName, colls)
+}
+
 // CollectionDrop drops a collection in the database.
 //
 // Returned boolean value indicates whether the collection was dropped.
@@ -380,8 +477,8 @@ func (r *Registry) collectionGet(dbName, collectionName string) *Collection {
 func (r *Registry) CollectionDrop(ctx context.Context, dbName, collectionName string) (bool, error) {
 	defer observability.FuncCall(ctx)()
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.collectionDrop(ctx, dbName, collectionName)
 }
@@ -415,7 +512,7 @@ func (r *Registry) collectionDrop(ctx context.Context, dbName, collectionName st
 		return false, lazyerrors.Error(err)
 	}
 
-	delete(r.colls[dbName], collectionName)
+	r.storeCollection(dbName, collectionName, nil)
 
 	return true, nil
 }
@@ -434,8 +531,8 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 		return false, nil
 	}
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	c := r.collectionGet(dbName, oldCollectionName)
 	if c == nil {
@@ -448,8 +545,8 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 	}
 
 	c.Name = newCollectionName
-	r.colls[dbName][newCollectionName] = c
-	delete(r.colls[dbName], oldCollectionName)
+	r.storeCollection(dbName, newCollectionName, c)
+	r.storeCollection(dbName, oldCollectionName, nil)
 
 	return true, nil
 }
@@ -460,8 +557,8 @@ func (r *Registry) CollectionRename(ctx context.Context, dbName, oldCollectionNa
 func (r *Registry) IndexesCreate(ctx context.Context, dbName, collectionName string, indexes []IndexInfo) error {
 	defer observability.FuncCall(ctx)()
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.indexesCreate(ctx, dbName, collectionName, indexes)
 }
@@ -541,7 +638,7 @@ func (r *Registry) indexesCreate(ctx context.Context, dbName, collectionName str
 		return lazyerrors.Error(err)
 	}
 
-	r.colls[dbName][collectionName] = c
+	r.storeCollection(dbName, collectionName, c)
 
 	return nil
 }
@@ -554,8 +651,8 @@ func (r *Registry) indexesCreate(ctx context.Context, dbName, collectionName str
 func (r *Registry) IndexesDrop(ctx context.Context, dbName, collectionName string, indexNames []string) error {
 	defer observability.FuncCall(ctx)()
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
 	return r.indexesDrop(ctx, dbName, collectionName, indexNames)
 }
@@ -599,7 +696,7 @@ func (r *Registry) indexesDrop(ctx context.Context, dbName, collectionName strin
 		return lazyerrors.Error(err)
 	}
 
-	r.colls[dbName][collectionName] = c
+	r.storeCollection(dbName, collectionName, c)
 
 	return nil
 }
@@ -613,8 +710,7 @@ func (r *Registry) Describe(ch chan<- *prometheus.Desc) {
 func (r *Registry) Collect(ch chan<- prometheus.Metric) {
 	r.p.Collect(ch)
 
-	r.rw.RLock()
-	defer r.rw.RUnlock()
+	snapshot := r.snapshot()
 
 	ch <- prometheus.MustNewConstMetric(
 		prometheus.NewDesc(
@@ -623,10 +719,18 @@ func (r *Registry) Collect(ch chan<- prometheus.Metric) {
 			nil, nil,
 		),
 		prometheus.GaugeValue,
-		float64(len(r.colls)),
+		float64(len(snapshot)),
+	)
+
+	cacheDesc := prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, subsystem, "cache_lookups_total"),
+		"The total number of collection lookups in the registry cache.",
+		[]string{"result"}, nil,
 	)
+	ch <- prometheus.MustNewConstMetric(cacheDesc, prometheus.CounterValue, float64(r.cacheHits.Load()), "hit")
+	ch <- prometheus.MustNewConstMetric(cacheDesc, prometheus.CounterValue, float64(r.cacheMisses.Load()), "miss")
 
-	for db, colls := range r.colls {
+	for db, colls := range snapshot {
 		ch <- prometheus.MustNewConstMetric(
 			prometheus.NewDesc(
 				prometheus.BuildFQName(namespace, subsystem, "collections"),
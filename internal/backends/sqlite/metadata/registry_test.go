@@ -20,6 +20,7 @@ import (
 	"sync/atomic"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/FerretDB/FerretDB/internal/util/fsql"
@@ -94,6 +95,53 @@ func TestCreateDrop(t *testing.T) {
 	testCollection(t, ctx, r, db, dbName, collectionName)
 }
 
+func TestCache(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	r, err := NewRegistry(testutil.TestSQLiteURI(t, ""), testutil.Logger(t), sp)
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+
+	dbName := testutil.DatabaseName(t)
+	collectionName := testutil.CollectionName(t)
+
+	require.Nil(t, r.CollectionGet(ctx, dbName, collectionName))
+	assert.Equal(t, int64(0), r.cacheHits.Load())
+	assert.Equal(t, int64(1), r.cacheMisses.Load())
+
+	created, err := r.CollectionCreate(ctx, &CollectionCreateParams{DBName: dbName, Name: collectionName})
+	require.NoError(t, err)
+	require.True(t, created)
+	assert.Equal(t, int64(0), r.cacheHits.Load())
+	assert.Equal(t, int64(2), r.cacheMisses.Load())
+
+	created, err = r.CollectionCreate(ctx, &CollectionCreateParams{DBName: dbName, Name: collectionName})
+	require.NoError(t, err)
+	require.False(t, created)
+	assert.Equal(t, int64(1), r.cacheHits.Load())
+	assert.Equal(t, int64(2), r.cacheMisses.Load())
+
+	c := r.CollectionGet(ctx, dbName, collectionName)
+	require.NotNil(t, c)
+	assert.Equal(t, int64(2), r.cacheHits.Load())
+
+	// returned collection is a copy
+	c.TableName = "modified"
+	assert.NotEqual(t, "modified", r.CollectionGet(ctx, dbName, collectionName).TableName)
+
+	dropped, err := r.CollectionDrop(ctx, dbName, collectionName)
+	require.NoError(t, err)
+	require.True(t, dropped)
+
+	require.Nil(t, r.CollectionGet(ctx, dbName, collectionName))
+	assert.Equal(t, int64(3), r.cacheHits.Load())
+	assert.Equal(t, int64(3), r.cacheMisses.Load())
+}
+
 func TestCreateDropStress(t *testing.T) {
 	// Otherwise, the test might fail with "database schema has changed".
 	// That error code is SQLITE_SCHEMA (17).
@@ -431,8 +479,10 @@ func TestIndexesCreateDrop(t *testing.T) {
 	})
 
 	t.Run("CheckSettingsAfterCreation", func(t *testing.T) {
-		err = r.initCollections(ctx, dbName, db)
+		var colls map[string]*Collection
+		colls, err = r.initCollections(ctx, dbName, db)
 		require.NoError(t, err)
+		r.storeDatabase(dbName, colls)
 
 		collection = r.CollectionGet(ctx, dbName, collectionName)
 		require.Equal(t, 4, len(collection.Settings.Indexes))
@@ -452,10 +502,52 @@ func TestIndexesCreateDrop(t *testing.T) {
 	})
 
 	t.Run("CheckSettingsAfterDrop", func(t *testing.T) {
-		err = r.initCollections(ctx, dbName, db)
+		var colls map[string]*Collection
+		colls, err = r.initCollections(ctx, dbName, db)
 		require.NoError(t, err)
+		r.storeDatabase(dbName, colls)
 
 		collection = r.CollectionGet(ctx, dbName, collectionName)
 		require.Equal(t, 1, len(collection.Settings.Indexes))
 	})
 }
+
+func BenchmarkCollectionGet(b *testing.B) {
+	ctx := testutil.Ctx(b)
+
+	sp, err := state.NewProvider("")
+	require.NoError(b, err)
+
+	r, err := NewRegistry(testutil.TestSQLiteURI(b, ""), testutil.Logger(b), sp)
+	require.NoError(b, err)
+	b.Cleanup(r.Close)
+
+	dbName := testutil.DatabaseName(b)
+
+	b.Cleanup(func() {
+		r.DatabaseDrop(ctx, dbName)
+	})
+
+	collectionNames := make([]string, 100)
+	for i := range collectionNames {
+		collectionNames[i] = fmt.Sprintf("collection_%d", i)
+
+		created, err := r.CollectionCreate(ctx, &CollectionCreateParams{DBName: dbName, Name: collectionNames[i]})
+		require.NoError(b, err)
+		require.True(b, created)
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var i int
+
+		for pb.Next() {
+			if c := r.CollectionGet(ctx, dbName, collectionNames[i%len(collectionNames)]); c == nil {
+				b.Fatal("collection not found")
+			}
+
+			i++
+		}
+	})
+}
@@ -29,6 +29,21 @@ type Settings struct {
 	Indexes         []IndexInfo `json:"indexes"`
 	CappedSize      int64       `json:"cappedSize"`
 	CappedDocuments int64       `json:"cappedDocuments"`
+
+	// Validator is a validator document encoded with sjson.
+	Validator        json.RawMessage `json:"validator,omitempty"`
+	ValidationLevel  string          `json:"validationLevel,omitempty"`
+	ValidationAction string          `json:"validationAction,omitempty"`
+
+	TimeSeries *TimeSeries `json:"timeseries,omitempty"`
+	Clustered  bool        `json:"clustered,omitempty"`
+}
+
+// TimeSeries represents time-series collection options.
+type TimeSeries struct {
+	TimeField   string `json:"timeField"`
+	MetaField   string `json:"metaField,omitempty"`
+	Granularity string `json:"granularity,omitempty"`
 }
 
 // IndexInfo represents information about a single index.
@@ -56,12 +71,23 @@ func (s Settings) deepCopy() Settings {
 		}
 	}
 
-	return Settings{
-		UUID:            s.UUID,
-		Indexes:         indexes,
-		CappedSize:      s.CappedSize,
-		CappedDocuments: s.CappedDocuments,
+	res := Settings{
+		UUID:             s.UUID,
+		Indexes:          indexes,
+		CappedSize:       s.CappedSize,
+		CappedDocuments:  s.CappedDocuments,
+		Validator:        slices.Clone(s.Validator),
+		ValidationLevel:  s.ValidationLevel,
+		ValidationAction: s.ValidationAction,
+		Clustered:        s.Clustered,
 	}
+
+	if s.TimeSeries != nil {
+		ts := *s.TimeSeries
+		res.TimeSeries = &ts
+	}
+
+	return res
 }
 
 // Value implements driver.Valuer interface.
@@ -50,7 +50,10 @@ func prepareSelectClause(table, comment string, capped, onlyRecordIDs bool) stri
 //
 // The provided sort document should be already validated.
 // Provided document should only contain a single value.
-func prepareOrderByClause(sort *types.Document) string {
+//
+// Clustered collections are sorted by _id index instead of recordID column.
+// Other collections without recordID column are sorted by rowid.
+func prepareOrderByClause(sort *types.Document, clustered, capped bool) string {
 	if sort.Len() != 1 {
 		return ""
 	}
@@ -67,5 +70,15 @@ func prepareOrderByClause(sort *types.Document) string {
 		panic("not reachable")
 	}
 
+	// the same expression as in the _id index, so it could be used
+	if clustered {
+		return fmt.Sprintf(` ORDER BY %s->"_id"%s`, metadata.DefaultColumn, order)
+	}
+
+	// recordID column is an alias of rowid, so other tables are sorted by rowid too
+	if !capped {
+		return fmt.Sprintf(" ORDER BY rowid%s", order)
+	}
+
 	return fmt.Sprintf(" ORDER BY %s%s", metadata.RecordIDColumn, order)
 }
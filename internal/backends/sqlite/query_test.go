@@ -79,9 +79,11 @@ func TestPrepareOrderByClause(t *testing.T) {
 	t.Parallel()
 
 	for name, tc := range map[string]struct { //nolint:vet // used for test only
-		sort    *types.Document
-		skip    string
-		orderBy string
+		sort      *types.Document
+		clustered bool
+		capped    bool
+		skip      string
+		orderBy   string
 	}{
 		"Ascending": {
 			sort:    must.NotFail(types.NewDocument("field", int64(1))),
@@ -98,12 +100,23 @@ func TestPrepareOrderByClause(t *testing.T) {
 		},
 		"NaturalAscending": {
 			sort:    must.NotFail(types.NewDocument("$natural", int64(1))),
+			capped:  true,
 			orderBy: ` ORDER BY _ferretdb_record_id`,
 		},
 		"NaturalDescending": {
 			sort:    must.NotFail(types.NewDocument("$natural", int64(-1))),
+			capped:  true,
 			orderBy: ` ORDER BY _ferretdb_record_id DESC`,
 		},
+		"NaturalNonCapped": {
+			sort:    must.NotFail(types.NewDocument("$natural", int64(-1))),
+			orderBy: ` ORDER BY rowid DESC`,
+		},
+		"NaturalClustered": {
+			sort:      must.NotFail(types.NewDocument("$natural", int64(1))),
+			clustered: true,
+			orderBy:   ` ORDER BY _ferretdb_sjson->"_id"`,
+		},
 	} {
 		name, tc := name, tc
 		t.Run(name, func(t *testing.T) {
@@ -113,7 +126,7 @@ func TestPrepareOrderByClause(t *testing.T) {
 				t.Skip(tc.skip)
 			}
 
-			orderBy := prepareOrderByClause(tc.sort)
+			orderBy := prepareOrderByClause(tc.sort, tc.clustered, tc.capped)
 
 			assert.Equal(t, tc.orderBy, orderBy)
 		})
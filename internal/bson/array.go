@@ -30,20 +30,20 @@ func (a *arrayType) bsontype() {}
 
 // ReadFrom implements bsontype interface.
 func (a *arrayType) ReadFrom(r *bufio.Reader) error {
-	return a.readNested(r, 0)
+	return a.readNested(r, 0, types.MaxDocumentLenLimit)
 }
 
 // readNested, similarly to ReadFrom, takes raw bytes from reader
 // and unmarshal them to the arrayType.
 // It also takes the nesting value, and checks if the
-// document doesn't exceed the max nesting allowed.
-func (a *arrayType) readNested(r *bufio.Reader, nesting int) error {
+// document doesn't exceed the max nesting allowed and the max length.
+func (a *arrayType) readNested(r *bufio.Reader, nesting int, maxLen int32) error {
 	if nesting > maxNesting {
 		return lazyerrors.Errorf("bson.Array.readNested: document has exceeded the max supported nesting: %d", maxNesting)
 	}
 
 	var doc Document
-	if err := doc.readNested(r, nesting); err != nil {
+	if err := doc.readNested(r, nesting, maxLen); err != nil {
 		return lazyerrors.Error(err)
 	}
 
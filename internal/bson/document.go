@@ -130,14 +130,21 @@ func (doc *Document) Values() []any {
 
 // ReadFrom implements bsontype interface.
 func (doc *Document) ReadFrom(r *bufio.Reader) error {
-	return doc.readNested(r, 0)
+	return doc.readNested(r, 0, types.MaxDocumentLenLimit)
+}
+
+// ReadFromLimit is a variant of ReadFrom that rejects documents longer than maxLen,
+// for example, longer than the configured maximum BSON object size.
+func (doc *Document) ReadFromLimit(r *bufio.Reader, maxLen int32) error {
+	return doc.readNested(r, 0, min(maxLen, types.MaxDocumentLenLimit))
 }
 
 // readNested, similarly to ReadFrom, takes raw bytes from reader
 // and unmarshal them to the Document.
 // It also takes the nesting value, and checks if the
-// document doesn't exceed the max nesting allowed.
-func (doc *Document) readNested(r *bufio.Reader, nesting int) error {
+// document doesn't exceed the max nesting allowed
+// and the max length (for embedded documents, the remaining length of the parent document).
+func (doc *Document) readNested(r *bufio.Reader, nesting int, maxLen int32) error {
 	if nesting > maxNesting {
 		return fmt.Errorf("bson.Document.readNested: document has exceeded the max supported nesting: %d", maxNesting)
 	}
@@ -146,22 +153,36 @@ func (doc *Document) readNested(r *bufio.Reader, nesting int) error {
 	if err := binary.Read(r, binary.LittleEndian, &l); err != nil {
 		return lazyerrors.Errorf("bson.Document.ReadFrom (binary.Read): %w", err)
 	}
-	if l < minDocumentLen || l > types.MaxDocumentLen {
+	if l < minDocumentLen || l > maxLen {
 		return lazyerrors.Errorf("bson.Document.ReadFrom: invalid length %d", l)
 	}
 
-	// make buffer
-	b := make([]byte, l)
+	// decoded values do not reference the buffer, so it could be reused
+	buf := getBuffer()
+	defer putBuffer(buf)
 
-	binary.LittleEndian.PutUint32(b, uint32(l))
+	// the length is not trusted yet, so the buffer grows as the data is actually read
+	// instead of being allocated upfront
+	must.NotFail(buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(l))))
 
 	// read e_list and terminating zero
-	n, err := io.ReadFull(r, b[4:])
+	n, err := buf.ReadFrom(io.LimitReader(r, int64(l)-4))
+	if err == nil && n != int64(l)-4 {
+		err = io.ErrUnexpectedEOF
+	}
+
 	if err != nil {
-		return lazyerrors.Errorf("bson.Document.ReadFrom (io.ReadFull, expected %d, read %d): %w", len(b), n, err)
+		return lazyerrors.Errorf("bson.Document.ReadFrom (ReadFrom, expected %d, read %d): %w", l, n+4, err)
 	}
 
-	bufr := bufio.NewReader(bytes.NewReader(b[4:]))
+	b := buf.Bytes()
+
+	br := bytes.NewReader(b[4:])
+	bufr := getBufioReader(br)
+	defer putBufioReader(bufr)
+
+	// embedded documents can't be longer than the rest of this document
+	remaining := func() int32 { return int32(br.Len() + bufr.Buffered()) }
 
 	fields := make([]field, 0, 8)
 	for {
@@ -188,7 +209,7 @@ func (doc *Document) readNested(r *bufio.Reader, nesting int) error {
 		switch tag(t) {
 		case tagDocument:
 			var v Document
-			if err := v.readNested(bufr, nesting+1); err != nil {
+			if err := v.readNested(bufr, nesting+1, remaining()); err != nil {
 				return lazyerrors.Errorf("bson.Document.ReadFrom (embedded document): %w", err)
 			}
 
@@ -201,7 +222,7 @@ func (doc *Document) readNested(r *bufio.Reader, nesting int) error {
 
 		case tagArray:
 			var v arrayType
-			if err := v.readNested(bufr, nesting+1); err != nil {
+			if err := v.readNested(bufr, nesting+1, remaining()); err != nil {
 				return lazyerrors.Errorf("bson.Document.ReadFrom (Array): %w", err)
 			}
 			a := types.Array(v)
@@ -322,8 +343,11 @@ func (doc Document) WriteTo(w *bufio.Writer) error {
 
 // MarshalBinary implements bsontype interface.
 func (doc Document) MarshalBinary() ([]byte, error) {
-	var elist bytes.Buffer
-	bufw := bufio.NewWriter(&elist)
+	elist := getBuffer()
+	defer putBuffer(elist)
+
+	bufw := getBufioWriter(elist)
+	defer putBufioWriter(bufw)
 
 	keys := doc.Keys()
 	values := doc.Values()
@@ -467,15 +491,15 @@ func (doc Document) MarshalBinary() ([]byte, error) {
 		return nil, lazyerrors.Error(err)
 	}
 
-	var res bytes.Buffer
 	l := int32(elist.Len() + 5)
-	binary.Write(&res, binary.LittleEndian, l)
-	must.NotFail(elist.WriteTo(&res))
-	res.WriteByte(0)
-	if int32(res.Len()) != l {
-		panic(fmt.Sprintf("got %d, expected %d", res.Len(), l))
+	res := make([]byte, 4, l)
+	binary.LittleEndian.PutUint32(res, uint32(l))
+	res = append(res, elist.Bytes()...)
+	res = append(res, 0)
+	if int32(len(res)) != l {
+		panic(fmt.Sprintf("got %d, expected %d", len(res), l))
 	}
-	return res.Bytes(), nil
+	return res, nil
 }
 
 // check interfaces
@@ -190,7 +190,32 @@ var (
 		},
 	}
 
-	documentTestCases = []testCase{handshake1, handshake2, handshake3, handshake4, all, eof, duplicateKeys}
+	// the length is larger than the input, so it should not be allocated upfront
+	truncated = testCase{
+		name: "truncated",
+		b: []byte{
+			0x00, 0x00, 0x00, 0x10, // document length (256 MiB)
+			0x00, // end of document
+		},
+		bErr: `unexpected EOF`,
+	}
+
+	embeddedTooLong = testCase{
+		name: "embeddedTooLong",
+		b: []byte{
+			0x0e, 0x00, 0x00, 0x00, // document length
+			0x03, 0x00, // "": embedded document
+			0x40, 0x00, 0x00, 0x00, // embedded document length, longer than the rest of the document
+			0x00,       // end of embedded document
+			0x00,       // end of document
+			0x00, 0x00, // padding
+		},
+		bErr: `bson.Document.ReadFrom: invalid length 64`,
+	}
+
+	documentTestCases = []testCase{
+		handshake1, handshake2, handshake3, handshake4, all, eof, duplicateKeys, truncated, embeddedTooLong,
+	}
 )
 
 func TestDocument(t *testing.T) {
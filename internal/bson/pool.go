@@ -0,0 +1,92 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bson
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxPooledBufferCap is the maximum capacity of buffers returned to the pool;
+// larger buffers are garbage-collected as usual.
+const maxPooledBufferCap = 1024 * 1024 // 1 MiB
+
+// Pools of reusable readers, writers, and buffers used for decoding and encoding.
+// Every nested document or array uses its own instance.
+var (
+	bufioReaderPool = sync.Pool{
+		New: func() any {
+			return bufio.NewReader(nil)
+		},
+	}
+
+	bufioWriterPool = sync.Pool{
+		New: func() any {
+			return bufio.NewWriter(nil)
+		},
+	}
+
+	bufferPool = sync.Pool{
+		New: func() any {
+			return new(bytes.Buffer)
+		},
+	}
+)
+
+// getBufioReader returns a pooled *bufio.Reader that reads from r.
+func getBufioReader(r io.Reader) *bufio.Reader {
+	bufr := bufioReaderPool.Get().(*bufio.Reader)
+	bufr.Reset(r)
+
+	return bufr
+}
+
+// putBufioReader returns *bufio.Reader obtained from getBufioReader to the pool.
+func putBufioReader(bufr *bufio.Reader) {
+	bufr.Reset(nil)
+	bufioReaderPool.Put(bufr)
+}
+
+// getBufioWriter returns a pooled *bufio.Writer that writes to w.
+func getBufioWriter(w io.Writer) *bufio.Writer {
+	bufw := bufioWriterPool.Get().(*bufio.Writer)
+	bufw.Reset(w)
+
+	return bufw
+}
+
+// putBufioWriter returns *bufio.Writer obtained from getBufioWriter to the pool.
+func putBufioWriter(bufw *bufio.Writer) {
+	bufw.Reset(nil)
+	bufioWriterPool.Put(bufw)
+}
+
+// getBuffer returns an empty pooled *bytes.Buffer.
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+// putBuffer returns *bytes.Buffer obtained from getBuffer to the pool.
+// Its content should not be used after that.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferCap {
+		return
+	}
+
+	buf.Reset()
+	bufferPool.Put(buf)
+}
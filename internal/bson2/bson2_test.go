@@ -553,6 +553,7 @@ func TestNormal(t *testing.T) {
 					raw, err := doc.Encode()
 					require.NoError(t, err)
 					assert.Equal(t, tc.raw, raw)
+					assert.Equal(t, len(tc.raw), doc.Size())
 				})
 			})
 		})
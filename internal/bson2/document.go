@@ -20,6 +20,7 @@ import (
 	"errors"
 	"log/slog"
 	"math"
+	"time"
 
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
@@ -154,12 +155,32 @@ func (doc *Document) add(name string, value any) error {
 	return nil
 }
 
+// Size returns the size of BSON document encoding in bytes without encoding it.
+func (doc *Document) Size() int {
+	return sizeDocument(doc)
+}
+
 // Encode encodes BSON document.
 //
 // TODO https://github.com/FerretDB/FerretDB/issues/3759
 // This method should accept a slice of bytes, not return it.
 // That would allow to avoid unnecessary allocations.
 func (doc *Document) Encode() (RawDocument, error) {
+	start := time.Now()
+
+	raw, err := doc.encode()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	metrics.observe(opEncode, len(raw), start)
+
+	return raw, nil
+}
+
+// encode encodes BSON document without recording metrics;
+// it is used for nested documents.
+func (doc *Document) encode() (RawDocument, error) {
 	size := sizeAny(doc)
 	buf := bytes.NewBuffer(make([]byte, 0, size))
 
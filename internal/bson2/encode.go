@@ -42,7 +42,7 @@ func encodeField(buf *bytes.Buffer, name string, v any) error {
 			return lazyerrors.Error(err)
 		}
 
-		b, err := v.Encode()
+		b, err := v.encode()
 		if err != nil {
 			return lazyerrors.Error(err)
 		}
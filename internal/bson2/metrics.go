@@ -0,0 +1,178 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bson2
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Parts of Prometheus metric names.
+const (
+	namespace = "ferretdb"
+	subsystem = "bson"
+)
+
+// conversionOp represents an instrumented conversion operation.
+type conversionOp int
+
+const (
+	opConvert conversionOp = iota
+	opEncode
+	opDecodeDeep
+	opCount
+)
+
+// conversionOpNames contains values of the `operation` label.
+var conversionOpNames = [opCount]string{
+	opConvert:    "convert",
+	opEncode:     "encode",
+	opDecodeDeep: "decode_deep",
+}
+
+// sizeClasses contains upper bounds (exclusive) and values of the `size` label.
+var sizeClasses = []struct {
+	max   int
+	label string
+}{
+	{1 << 10, "0-1KiB"},
+	{16 << 10, "1KiB-16KiB"},
+	{256 << 10, "16KiB-256KiB"},
+	{1 << 20, "256KiB-1MiB"},
+	{0, "1MiB+"}, // the rest
+}
+
+// sizeClass returns the index of the size class for the given document size in bytes.
+func sizeClass(size int) int {
+	for i, c := range sizeClasses[:len(sizeClasses)-1] {
+		if size < c.max {
+			return i
+		}
+	}
+
+	return len(sizeClasses) - 1
+}
+
+// conversionMetrics tracks costs of conversions of top-level documents.
+//
+// Nested documents and arrays are accounted as a part of their top-level document.
+type conversionMetrics struct {
+	total    *prometheus.CounterVec
+	bytes    *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+
+	// resolved children of vectors above to avoid label lookups on the hot path
+	totalC    [opCount][]prometheus.Counter
+	bytesC    [opCount][]prometheus.Counter
+	durationO [opCount][]prometheus.Observer
+}
+
+// metrics is used by all conversions.
+var metrics = newConversionMetrics()
+
+// newConversionMetrics creates new conversion metrics.
+func newConversionMetrics() *conversionMetrics {
+	labels := []string{"operation", "size"}
+
+	m := &conversionMetrics{
+		total: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "conversions_total",
+				Help:      "Total number of BSON document conversions.",
+			},
+			labels,
+		),
+		bytes: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "conversion_bytes_total",
+				Help:      "Total size of converted BSON documents in bytes.",
+			},
+			labels,
+		),
+		duration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "conversion_duration_seconds",
+				Help:      "BSON document conversion duration in seconds.",
+				Buckets: []float64{
+					(1 * time.Microsecond).Seconds(),
+					(5 * time.Microsecond).Seconds(),
+					(10 * time.Microsecond).Seconds(),
+					(50 * time.Microsecond).Seconds(),
+					(100 * time.Microsecond).Seconds(),
+					(500 * time.Microsecond).Seconds(),
+					(1 * time.Millisecond).Seconds(),
+					(5 * time.Millisecond).Seconds(),
+					(10 * time.Millisecond).Seconds(),
+					(50 * time.Millisecond).Seconds(),
+				},
+			},
+			labels,
+		),
+	}
+
+	for op, opName := range conversionOpNames {
+		for _, c := range sizeClasses {
+			m.totalC[op] = append(m.totalC[op], m.total.WithLabelValues(opName, c.label))
+			m.bytesC[op] = append(m.bytesC[op], m.bytes.WithLabelValues(opName, c.label))
+			m.durationO[op] = append(m.durationO[op], m.duration.WithLabelValues(opName, c.label))
+		}
+	}
+
+	return m
+}
+
+// observe records the conversion of the document with the given size in bytes that started at the given time.
+func (m *conversionMetrics) observe(op conversionOp, size int, start time.Time) {
+	d := time.Since(start)
+	class := sizeClass(size)
+
+	m.totalC[op][class].Inc()
+	m.bytesC[op][class].Add(float64(size))
+	m.durationO[op][class].Observe(d.Seconds())
+}
+
+// Describe implements prometheus.Collector.
+func (m *conversionMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.total.Describe(ch)
+	m.bytes.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *conversionMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.total.Collect(ch)
+	m.bytes.Collect(ch)
+	m.duration.Collect(ch)
+}
+
+// Collector returns Prometheus collector for metrics of BSON conversions
+// done by [RawDocument.Convert], [RawDocument.DecodeDeep], and [Document.Encode].
+//
+// It should be registered once.
+func Collector() prometheus.Collector {
+	return metrics
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*conversionMetrics)(nil)
+)
@@ -0,0 +1,46 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bson2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversionMetrics(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "0-1KiB", sizeClasses[sizeClass(0)].label)
+	assert.Equal(t, "1KiB-16KiB", sizeClasses[sizeClass(1<<10)].label)
+	assert.Equal(t, "256KiB-1MiB", sizeClasses[sizeClass(1<<20-1)].label)
+	assert.Equal(t, "1MiB+", sizeClasses[sizeClass(16<<20)].label)
+
+	m := newConversionMetrics()
+
+	m.observe(opEncode, 100, time.Now())
+	m.observe(opEncode, 200, time.Now())
+	m.observe(opConvert, 2<<20, time.Now())
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.total.WithLabelValues("encode", "0-1KiB")))
+	assert.Equal(t, float64(300), testutil.ToFloat64(m.bytes.WithLabelValues("encode", "0-1KiB")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.total.WithLabelValues("convert", "1MiB+")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(m.total.WithLabelValues("decode_deep", "0-1KiB")))
+
+	// all series are exported from the start
+	assert.Equal(t, 15, testutil.CollectAndCount(m, "ferretdb_bson_conversion_duration_seconds"))
+}
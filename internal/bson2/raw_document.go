@@ -16,6 +16,7 @@ package bson2
 
 import (
 	"log/slog"
+	"time"
 
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -50,6 +51,8 @@ func (raw RawDocument) Decode() (*Document, error) {
 //
 // All nested documents and arrays are decoded recursively.
 func (raw RawDocument) DecodeDeep() (*Document, error) {
+	defer metrics.observe(opDecodeDeep, len(raw), time.Now())
+
 	res, err := raw.decode(decodeDeep)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -60,6 +63,8 @@ func (raw RawDocument) DecodeDeep() (*Document, error) {
 
 // Convert converts a single valid BSON document that takes the whole byte slice into [*types.Document].
 func (raw RawDocument) Convert() (*types.Document, error) {
+	defer metrics.observe(opConvert, len(raw), time.Now())
+
 	doc, err := raw.decode(decodeShallow)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
@@ -27,6 +27,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime/pprof"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -36,10 +37,12 @@ import (
 
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/clientconn/ratelimit"
 	"github.com/FerretDB/FerretDB/internal/handler"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/proxy"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/observability"
@@ -70,25 +73,71 @@ var AllModes = []string{
 	string(DiffProxyMode),
 }
 
+// errDrained is returned by conn.run when connection is closed after draining.
+var errDrained = errors.New("connection drained")
+
+// errIdleTimeout is returned by conn.run when connection is closed after being idle for too long.
+var errIdleTimeout = errors.New("connection idle timeout")
+
 // conn represents client connection.
 type conn struct {
 	netConn        net.Conn
+	drain          <-chan struct{}
+	idleTimeout    time.Duration      // zero if idle connections are kept
+	limiter        *ratelimit.Limiter // global; nil if there is no limit
+	connLimiter    *ratelimit.Limiter // of that connection; nil if there is no limit
 	mode           Mode
 	l              *zap.SugaredLogger
 	h              *handler.Handler
 	m              *connmetrics.ConnMetrics
 	proxy          *proxy.Router
 	lastRequestID  atomic.Int32
+	requireAuth    bool
 	testRecordsDir string // if empty, no records are created
+
+	// unacknowledged writes are executed in order by a separate goroutine,
+	// so the connection could read the next requests in the meantime
+	unackQueue chan *unacknowledgedRequest // nil until the first unacknowledged write
+	unackWG    sync.WaitGroup              // queued and in-progress unacknowledged writes
+	unackErrsM sync.Mutex
+	unackErrs  []*types.Document // errors not returned yet in the unacknowledgedWriteErrors field
+
+	// read deadlines are set by both run and the goroutine it starts,
+	// so the decision to set one and setting it are done under that mutex
+	deadlineM sync.Mutex
+	idle      bool // true while waiting for the next request
+	draining  bool // true after drain channel is closed
+	stopped   bool // true after ctx is canceled
+}
+
+// noAuthCommands contains commands that could be run on connections that require authentication
+// before the client authenticates.
+var noAuthCommands = map[string]bool{
+	"buildInfo":        true,
+	"buildinfo":        true,
+	"connectionStatus": true,
+	"hello":            true,
+	"isMaster":         true,
+	"ismaster":         true,
+	"logout":           true,
+	"ping":             true,
+	"saslContinue":     true,
+	"saslStart":        true,
+	"whatsmyuri":       true,
 }
 
 // newConnOpts represents newConn options.
 type newConnOpts struct {
 	netConn     net.Conn
+	drain       <-chan struct{} // closed when connection should be closed after the current command
+	idleTimeout time.Duration   // connection is closed after that duration without requests; zero disables that
+	limiter     *ratelimit.Limiter
+	connLimiter *ratelimit.Limiter
 	mode        Mode
 	l           *zap.Logger
 	handler     *handler.Handler
 	connMetrics *connmetrics.ConnMetrics
+	requireAuth bool // commands other than noAuthCommands fail until the client authenticates
 
 	proxyAddr        string
 	proxyTLSCertFile string
@@ -117,16 +166,22 @@ func newConn(opts *newConnOpts) (*conn, error) {
 
 	return &conn{
 		netConn:        opts.netConn,
+		drain:          opts.drain,
+		idleTimeout:    opts.idleTimeout,
+		limiter:        opts.limiter,
+		connLimiter:    opts.connLimiter,
 		mode:           opts.mode,
 		l:              opts.l.Sugar(),
 		h:              opts.handler,
 		m:              opts.connMetrics,
 		proxy:          p,
+		requireAuth:    opts.requireAuth,
 		testRecordsDir: opts.testRecordsDir,
 	}, nil
 }
 
 // run runs the client connection until ctx is canceled, client disconnects,
+// drain channel is closed and the current command (if any) completes,
 // or fatal error or panic is encountered.
 //
 // Returned error is always non-nil.
@@ -145,17 +200,57 @@ func (c *conn) run(ctx context.Context) (err error) {
 
 	ctx = conninfo.Ctx(ctx, connInfo)
 
+	c.h.Conns().Add(connInfo)
+	defer c.h.Conns().Remove(connInfo)
+
+	c.m.Connections.Inc()
+	c.m.ConnectionsCreated.Inc()
+
+	defer c.m.Connections.Dec()
+
 	done := make(chan struct{})
 
-	// handle ctx cancellation
+	// handle draining and ctx cancellation
 	go func() {
-		select {
-		case <-done:
-			// nothing, let goroutine exit
-		case <-ctx.Done():
-			// unblocks ReadMessage below; any non-zero past value will do
-			if e := c.netConn.SetDeadline(time.Unix(0, 0)); e != nil {
-				c.l.Warnf("Failed to set deadline: %s", e)
+		drain := c.drain
+
+		for {
+			select {
+			case <-done:
+				// nothing, let goroutine exit
+				return
+
+			case <-drain:
+				c.deadlineM.Lock()
+
+				c.draining = true
+
+				// unblocks waiting for the next request below;
+				// the current request, if any, is handled first
+				if c.idle {
+					if e := c.netConn.SetReadDeadline(time.Unix(0, 0)); e != nil {
+						c.l.Warnf("Failed to set read deadline: %s", e)
+					}
+				}
+
+				c.deadlineM.Unlock()
+
+				// do not select closed channel again
+				drain = nil
+
+			case <-ctx.Done():
+				c.deadlineM.Lock()
+
+				c.stopped = true
+
+				// unblocks ReadMessage below; any non-zero past value will do
+				if e := c.netConn.SetDeadline(time.Unix(0, 0)); e != nil {
+					c.l.Warnf("Failed to set deadline: %s", e)
+				}
+
+				c.deadlineM.Unlock()
+
+				return
 			}
 		}
 	}()
@@ -238,15 +333,48 @@ func (c *conn) run(ctx context.Context) (err error) {
 		// c.netConn is closed by the caller
 	}()
 
+	// finish unacknowledged writes before the context is canceled
+	defer c.stopUnacknowledged()
+
 	for {
 		var reqHeader *wire.MsgHeader
 		var reqBody wire.MsgBody
 		var resHeader *wire.MsgHeader
 		var resBody wire.MsgBody
 		var validationErr *wire.ValidationError
+		var tooLargeErr *wire.DocumentTooLargeError
+
+		// either the check below or the goroutine above unblocks waiting
+		if err = c.startIdle(); err != nil {
+			return
+		}
+
+		// wait for the first byte of the next message,
+		// so the time spent on reading and decoding it is subtracted from the request's time budget
+		_, err = bufr.Peek(1)
 
-		reqHeader, reqBody, err = wire.ReadMessage(bufr)
-		if err != nil && errors.As(err, &validationErr) {
+		draining, e := c.stopIdle(err == nil)
+
+		if err != nil {
+			if errors.Is(err, os.ErrDeadlineExceeded) && ctx.Err() == nil {
+				err = errIdleTimeout
+
+				if draining {
+					err = errDrained
+				}
+			}
+
+			return
+		}
+
+		if err = e; err != nil {
+			return
+		}
+
+		reqCtx := ctxutil.WithRequestStart(ctx, time.Now())
+
+		reqHeader, reqBody, err = wire.ReadMessageLimits(bufr, c.h.MaxMessageSize, c.h.MaxBSONObjectSize)
+		if err != nil && (errors.As(err, &validationErr) || errors.As(err, &tooLargeErr)) {
 			// Currently, we respond with OP_MSG containing an error and don't close the connection.
 			// That's probably not right. First, we always respond with OP_MSG, even to OP_QUERY.
 			// Second, we don't know what command it was, if any,
@@ -255,7 +383,12 @@ func (c *conn) run(ctx context.Context) (err error) {
 			// TODO https://github.com/FerretDB/FerretDB/issues/2412
 
 			// get protocol error to return correct error document
-			protoErr := handlererrors.ProtocolError(validationErr)
+			var protoErr handlererrors.ProtoErr
+			if tooLargeErr != nil {
+				protoErr = handlererrors.ProtocolError(tooLargeErr)
+			} else {
+				protoErr = handlererrors.ProtocolError(validationErr)
+			}
 
 			var res wire.OpMsg
 			must.NoError(res.SetSections(wire.MakeOpMsgSection(
@@ -283,12 +416,51 @@ func (c *conn) run(ctx context.Context) (err error) {
 		}
 
 		if err != nil {
+			// the connection may start draining while the rest of the request is read
+			if errors.Is(err, os.ErrDeadlineExceeded) && ctx.Err() == nil {
+				err = errDrained
+			}
+
 			return
 		}
 
+		c.m.ReceivedBytes.Add(float64(reqHeader.MessageLength))
+
 		c.l.Debugf("Request header: %s", reqHeader)
 		c.l.Debugf("Request message:\n%s\n\n\n", reqBody)
 
+		if c.mode == NormalMode {
+			unack, reply := unacknowledged(reqHeader, reqBody)
+			if unack {
+				c.enqueueUnacknowledged(&unacknowledgedRequest{
+					ctx:    reqCtx,
+					header: reqHeader,
+					body:   reqBody,
+				})
+
+				if !reply {
+					continue
+				}
+
+				resHeader, resBody = c.unacknowledgedReply(reqHeader)
+
+				if err = wire.WriteMessage(bufw, resHeader, resBody); err != nil {
+					return
+				}
+
+				if err = bufw.Flush(); err != nil {
+					return
+				}
+
+				c.m.SentBytes.Add(float64(resHeader.MessageLength))
+
+				continue
+			}
+
+			// acknowledged requests should see the results of all previous writes
+			c.waitUnacknowledged()
+		}
+
 		// diffLogLevel provides the level of logging for the diff between the "normal" and "proxy" responses.
 		// It is set to the highest level of logging used to log response.
 		var diffLogLevel zapcore.Level
@@ -303,13 +475,13 @@ func (c *conn) run(ctx context.Context) (err error) {
 				panic("proxy addr was nil")
 			}
 
-			proxyHeader, proxyBody = c.proxy.Route(ctx, reqHeader, reqBody)
+			proxyHeader, proxyBody = c.proxy.Route(reqCtx, reqHeader, reqBody)
 		}
 
 		// handle request unless we are in proxy mode
 		var resCloseConn bool
 		if c.mode != ProxyMode {
-			resHeader, resBody, resCloseConn = c.route(ctx, reqHeader, reqBody)
+			resHeader, resBody, resCloseConn = c.route(reqCtx, reqHeader, reqBody, false)
 			if level := c.logResponse("Response", resHeader, resBody, resCloseConn); level > diffLogLevel {
 				diffLogLevel = level
 			}
@@ -380,6 +552,8 @@ func (c *conn) run(ctx context.Context) (err error) {
 			return
 		}
 
+		c.m.SentBytes.Add(float64(resHeader.MessageLength))
+
 		if resCloseConn {
 			err = errors.New("fatal error")
 			return
@@ -387,6 +561,47 @@ func (c *conn) run(ctx context.Context) (err error) {
 	}
 }
 
+// startIdle marks the connection as waiting for the next request and sets the idle timeout deadline.
+// It returns errDrained if the connection is draining.
+func (c *conn) startIdle() error {
+	c.deadlineM.Lock()
+	defer c.deadlineM.Unlock()
+
+	if c.draining {
+		return errDrained
+	}
+
+	// do not override the past deadline set on ctx cancellation
+	if c.idleTimeout > 0 && !c.stopped {
+		if err := c.netConn.SetReadDeadline(time.Now().Add(c.idleTimeout)); err != nil {
+			return err
+		}
+	}
+
+	c.idle = true
+
+	return nil
+}
+
+// stopIdle marks the connection as not waiting for the next request and returns true if it is draining.
+//
+// If the first byte of the request was received, the deadline set by idle timeout or draining is reset,
+// so the rest of the request is read and handled.
+func (c *conn) stopIdle(received bool) (bool, error) {
+	c.deadlineM.Lock()
+	defer c.deadlineM.Unlock()
+
+	c.idle = false
+
+	if received && (c.idleTimeout > 0 || c.draining) && !c.stopped {
+		if err := c.netConn.SetReadDeadline(time.Time{}); err != nil {
+			return c.draining, err
+		}
+	}
+
+	return c.draining, nil
+}
+
 // route sends request to a handler's command based on the op code provided in the request header.
 //
 // The passed context is canceled when the client disconnects.
@@ -394,8 +609,11 @@ func (c *conn) run(ctx context.Context) (err error) {
 // Handlers to which it routes, should not panic on bad input, but may do so in "impossible" cases.
 // They also should not use recover(). That allows us to use fuzzing.
 //
+// If unack is true, the response is not going to be sent to the client, so it is not fully constructed;
+// otherwise, errors of previous unacknowledged writes are added to OP_MSG response.
+//
 // Returned resBody can be nil.
-func (c *conn) route(ctx context.Context, reqHeader *wire.MsgHeader, reqBody wire.MsgBody) (resHeader *wire.MsgHeader, resBody wire.MsgBody, closeConn bool) { //nolint:lll // argument list is too long
+func (c *conn) route(ctx context.Context, reqHeader *wire.MsgHeader, reqBody wire.MsgBody, unack bool) (resHeader *wire.MsgHeader, resBody wire.MsgBody, closeConn bool) { //nolint:lll // argument list is too long
 	var command, result, argument string
 	defer func() {
 		if result == "" {
@@ -537,6 +755,23 @@ func (c *conn) route(ctx context.Context, reqHeader *wire.MsgHeader, reqBody wir
 		}
 	}
 
+	if unack {
+		if result == "" {
+			result = "ok"
+		}
+
+		return
+	}
+
+	if resHeader.OpCode == wire.OpCodeMsg {
+		if errs := c.unacknowledgedErrors(); errs != nil {
+			resMsg := resBody.(*wire.OpMsg)
+			doc := must.NotFail(resMsg.Document())
+			doc.Set("unacknowledgedWriteErrors", errs)
+			must.NoError(resMsg.SetSections(wire.MakeOpMsgSection(doc)))
+		}
+	}
+
 	// Don't call MarshalBinary there. Fix header in the caller?
 	// TODO https://github.com/FerretDB/FerretDB/issues/273
 	b, err := resBody.MarshalBinary()
@@ -560,6 +795,19 @@ func (c *conn) route(ctx context.Context, reqHeader *wire.MsgHeader, reqBody wir
 //
 // The passed context is canceled when the client disconnects.
 func (c *conn) handleOpMsg(ctx context.Context, msg *wire.OpMsg, command string) (*wire.OpMsg, error) {
+	if c.requireAuth && !noAuthCommands[command] && !conninfo.Get(ctx).Authenticated() {
+		return nil, handlererrors.NewCommandErrorMsg(
+			handlererrors.ErrUnauthorized,
+			fmt.Sprintf("Command %s requires authentication", command),
+		)
+	}
+
+	if !noAuthCommands[command] {
+		if err := c.throttle(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	if cmd, ok := c.h.Commands()[command]; ok {
 		if cmd.Handler != nil {
 			defer observability.FuncCall(ctx)()
@@ -613,3 +861,44 @@ func (c *conn) logResponse(who string, resHeader *wire.MsgHeader, resBody wire.M
 
 	return level
 }
+
+// throttle waits until the operation is allowed by the connection and global rate limits.
+// It returns an error if the operation is rejected or if ctx is canceled while waiting.
+func (c *conn) throttle(ctx context.Context) error {
+	limits := []struct {
+		name    string
+		limiter *ratelimit.Limiter
+	}{
+		{"connection", c.connLimiter},
+		{"global", c.limiter},
+	}
+
+	for i, limit := range limits {
+		d, err := limit.limiter.Wait(ctx)
+		if err == nil {
+			if d > 0 {
+				c.m.Throttled.WithLabelValues(limit.name, "delayed").Inc()
+			}
+
+			continue
+		}
+
+		// the operation is not done, so return tokens taken by previous limiters
+		for _, prev := range limits[:i] {
+			prev.limiter.Cancel()
+		}
+
+		if errors.Is(err, ratelimit.ErrLimited) {
+			c.m.Throttled.WithLabelValues(limit.name, "rejected").Inc()
+
+			return handlererrors.NewCommandErrorMsg(
+				handlererrors.ErrTemporarilyUnavailable,
+				fmt.Sprintf("Operation rejected: %s rate limit exceeded, retry later", limit.name),
+			)
+		}
+
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
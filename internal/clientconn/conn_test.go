@@ -0,0 +1,258 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite"
+	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/clientconn/ratelimit"
+	"github.com/FerretDB/FerretDB/internal/handler"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// setupHandler returns a handler with SQLite backend for connection tests.
+func setupHandler(t *testing.T) *handler.Handler {
+	t.Helper()
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := sqlite.NewBackend(&sqlite.NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	h, err := handler.New(&handler.NewOpts{
+		Backend:                 b,
+		BackendName:             "sqlite",
+		L:                       testutil.Logger(t),
+		ConnMetrics:             connmetrics.NewListenerMetrics().ConnMetrics,
+		StateProvider:           sp,
+		CappedCleanupPercentage: 10,
+	})
+	require.NoError(t, err)
+	t.Cleanup(h.Close)
+
+	return h
+}
+
+// startConn runs a new connection with the given drain channel over TCP loopback.
+// It returns the client side of the connection and the channel that receives the error returned by run.
+func startConn(t *testing.T, ctx context.Context, h *handler.Handler, drain <-chan struct{}) (net.Conn, <-chan error) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer lis.Close()
+
+	client, err := net.Dial("tcp", lis.Addr().String())
+	require.NoError(t, err)
+
+	netConn, err := lis.Accept()
+	require.NoError(t, err)
+
+	c, err := newConn(&newConnOpts{
+		netConn:     netConn,
+		drain:       drain,
+		mode:        NormalMode,
+		l:           testutil.Logger(t),
+		handler:     h,
+		connMetrics: connmetrics.NewListenerMetrics().ConnMetrics,
+	})
+	require.NoError(t, err)
+
+	errCh := make(chan error, 1)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		errCh <- c.run(ctx)
+		netConn.Close()
+	}()
+
+	// connection logs until run returns
+	t.Cleanup(func() {
+		client.Close()
+		<-done
+	})
+
+	return client, errCh
+}
+
+// sendCommand writes the command to the client side of the connection.
+func sendCommand(t *testing.T, client net.Conn, doc *types.Document) {
+	t.Helper()
+
+	var msg wire.OpMsg
+	require.NoError(t, msg.SetSections(wire.MakeOpMsgSection(doc)))
+
+	b, err := msg.MarshalBinary()
+	require.NoError(t, err)
+
+	header := &wire.MsgHeader{
+		MessageLength: int32(wire.MsgHeaderLen + len(b)),
+		RequestID:     1,
+		OpCode:        wire.OpCodeMsg,
+	}
+
+	bufw := bufio.NewWriter(client)
+	require.NoError(t, wire.WriteMessage(bufw, header, &msg))
+	require.NoError(t, bufw.Flush())
+}
+
+// readReply reads the command reply from the client side of the connection.
+func readReply(t *testing.T, bufr *bufio.Reader) *types.Document {
+	t.Helper()
+
+	_, body, err := wire.ReadMessage(bufr)
+	require.NoError(t, err)
+
+	doc, err := body.(*wire.OpMsg).Document()
+	require.NoError(t, err)
+
+	return doc
+}
+
+func TestConnDrain(t *testing.T) {
+	t.Parallel()
+
+	h := setupHandler(t)
+
+	t.Run("Idle", func(t *testing.T) {
+		t.Parallel()
+
+		drain := make(chan struct{})
+		client, errCh := startConn(t, testutil.Ctx(t), h, drain)
+
+		sendCommand(t, client, must.NotFail(types.NewDocument("ping", int32(1), "$db", "admin")))
+		assert.Equal(t, float64(1), must.NotFail(readReply(t, bufio.NewReader(client)).Get("ok")))
+
+		close(drain)
+
+		select {
+		case err := <-errCh:
+			assert.ErrorIs(t, err, errDrained)
+		case <-time.After(5 * time.Second):
+			t.Fatal("idle connection was not drained")
+		}
+	})
+
+	t.Run("InFlight", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := testutil.Ctx(t)
+		dbName := testutil.DatabaseName(t)
+
+		// the other connection locks writes, so createIndexes below is in-flight until it unlocks them
+		other, _ := startConn(t, ctx, h, nil)
+		otherR := bufio.NewReader(other)
+
+		sendCommand(t, other, must.NotFail(types.NewDocument("create", "test", "$db", dbName)))
+		require.Equal(t, float64(1), must.NotFail(readReply(t, otherR).Get("ok")))
+
+		sendCommand(t, other, must.NotFail(types.NewDocument("fsync", int32(1), "lock", true, "$db", "admin")))
+		require.Equal(t, float64(1), must.NotFail(readReply(t, otherR).Get("ok")))
+
+		drain := make(chan struct{})
+		client, errCh := startConn(t, ctx, h, drain)
+
+		sendCommand(t, client, must.NotFail(types.NewDocument(
+			"createIndexes", "test",
+			"indexes", must.NotFail(types.NewArray(must.NotFail(types.NewDocument(
+				"key", must.NotFail(types.NewDocument("v", int32(1))),
+				"name", "v_1",
+			)))),
+			"$db", dbName,
+		)))
+
+		require.Eventually(t, func() bool {
+			sendCommand(t, other, must.NotFail(types.NewDocument("currentOp", int32(1), "$db", "admin")))
+			inprog := must.NotFail(readReply(t, otherR).Get("inprog")).(*types.Array)
+
+			return inprog.Len() > 0
+		}, 5*time.Second, 10*time.Millisecond)
+
+		close(drain)
+
+		sendCommand(t, other, must.NotFail(types.NewDocument("fsyncUnlock", int32(1), "$db", "admin")))
+		require.Equal(t, float64(1), must.NotFail(readReply(t, otherR).Get("ok")))
+
+		res := readReply(t, bufio.NewReader(client))
+		assert.Equal(t, float64(1), must.NotFail(res.Get("ok")))
+		assert.Equal(t, int32(2), must.NotFail(res.Get("numIndexesAfter")))
+
+		select {
+		case err := <-errCh:
+			assert.ErrorIs(t, err, errDrained)
+		case <-time.After(5 * time.Second):
+			t.Fatal("connection was not drained after in-flight command")
+		}
+	})
+
+	t.Run("Race", func(t *testing.T) {
+		t.Parallel()
+
+		// the request sent right before draining is either handled or not read at all
+		for range 50 {
+			drain := make(chan struct{})
+			client, errCh := startConn(t, testutil.Ctx(t), h, drain)
+
+			sendCommand(t, client, must.NotFail(types.NewDocument("ping", int32(1), "$db", "admin")))
+			close(drain)
+
+			err := <-errCh
+			require.ErrorIs(t, err, errDrained)
+		}
+	})
+}
+
+func TestConnThrottle(t *testing.T) {
+	t.Parallel()
+
+	c := &conn{
+		limiter:     ratelimit.New(1, 1, 0),
+		connLimiter: ratelimit.New(1, 2, 0),
+		m:           connmetrics.NewListenerMetrics().ConnMetrics,
+	}
+
+	ctx := testutil.Ctx(t)
+
+	require.NoError(t, c.throttle(ctx))
+
+	// rejected operations do not take tokens from the connection limiter
+	for range 3 {
+		err := c.throttle(ctx)
+
+		var ce *handlererrors.CommandError
+		require.ErrorAs(t, err, &ce)
+		assert.Equal(t, handlererrors.ErrTemporarilyUnavailable, ce.Code())
+		assert.Contains(t, ce.Err().Error(), "global rate limit exceeded")
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conninfo
+
+// ClientMetadata represents parts of the client metadata document sent by drivers
+// in the first `hello` or `isMaster` command of the connection.
+//
+// Missing values are empty.
+type ClientMetadata struct {
+	DriverName     string
+	DriverVersion  string
+	AppName        string
+	OSType         string
+	OSName         string
+	OSArchitecture string
+	OSVersion      string
+	Platform       string
+}
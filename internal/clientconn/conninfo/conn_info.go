@@ -36,8 +36,10 @@ type ConnInfo struct {
 	username     string // protected by rw
 	password     string // protected by rw
 	metadataRecv bool   // protected by rw
+	authVerified bool   // protected by rw
 
-	sc *scram.ServerConversation // protected by rw
+	sc             *scram.ServerConversation // protected by rw
+	clientMetadata *ClientMetadata           // protected by rw
 
 	// If true, backend implementations should not perform authentication
 	// by adding username and password to the connection string.
@@ -77,6 +79,34 @@ func (connInfo *ConnInfo) SetAuth(username, password string) {
 
 	connInfo.username = username
 	connInfo.password = password
+	connInfo.authVerified = false
+}
+
+// SetAuthVerified marks credentials stored by SetAuth as verified by the backend.
+func (connInfo *ConnInfo) SetAuthVerified() {
+	connInfo.rw.Lock()
+	defer connInfo.rw.Unlock()
+
+	connInfo.authVerified = true
+}
+
+// Authenticated returns true if the client provided credentials
+// that were verified either by the completed SCRAM conversation or by the backend.
+//
+// Credentials provided with PLAIN mechanism count only after SetAuthVerified is called.
+func (connInfo *ConnInfo) Authenticated() bool {
+	connInfo.rw.RLock()
+	defer connInfo.rw.RUnlock()
+
+	if connInfo.username == "" {
+		return false
+	}
+
+	if connInfo.sc != nil {
+		return connInfo.sc.Valid()
+	}
+
+	return connInfo.authVerified
 }
 
 // Conv returns stored SCRAM server conversation.
@@ -89,8 +119,8 @@ func (connInfo *ConnInfo) Conv() *scram.ServerConversation {
 
 // SetConv stores the SCRAM server conversation.
 func (connInfo *ConnInfo) SetConv(sc *scram.ServerConversation) {
-	connInfo.rw.RLock()
-	defer connInfo.rw.RUnlock()
+	connInfo.rw.Lock()
+	defer connInfo.rw.Unlock()
 
 	connInfo.username = sc.Username()
 	connInfo.sc = sc
@@ -112,6 +142,28 @@ func (connInfo *ConnInfo) SetMetadataRecv() {
 	connInfo.metadataRecv = true
 }
 
+// ClientMetadata returns a copy of client metadata sent on handshake, or nil if it was not received.
+func (connInfo *ConnInfo) ClientMetadata() *ClientMetadata {
+	connInfo.rw.RLock()
+	defer connInfo.rw.RUnlock()
+
+	if connInfo.clientMetadata == nil {
+		return nil
+	}
+
+	md := *connInfo.clientMetadata
+
+	return &md
+}
+
+// SetClientMetadata stores client metadata sent on handshake.
+func (connInfo *ConnInfo) SetClientMetadata(md *ClientMetadata) {
+	connInfo.rw.Lock()
+	defer connInfo.rw.Unlock()
+
+	connInfo.clientMetadata = md
+}
+
 // SetBypassBackendAuth marks the connection as not requiring backend authentication.
 func (connInfo *ConnInfo) SetBypassBackendAuth() {
 	connInfo.rw.Lock()
@@ -72,3 +72,19 @@ func TestGet(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthenticated(t *testing.T) {
+	t.Parallel()
+
+	connInfo := New()
+	assert.False(t, connInfo.Authenticated())
+
+	connInfo.SetAuth("user", "password")
+	assert.False(t, connInfo.Authenticated(), "PLAIN credentials are not verified yet")
+
+	connInfo.SetAuthVerified()
+	assert.True(t, connInfo.Authenticated())
+
+	connInfo.SetAuth("other", "password")
+	assert.False(t, connInfo.Authenticated(), "new credentials should be verified again")
+}
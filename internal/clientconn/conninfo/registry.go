@@ -0,0 +1,84 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conninfo
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Registry stores ConnInfo values of open client connections.
+//
+// It is safe for concurrent use.
+type Registry struct {
+	rw     sync.RWMutex
+	lastID int64
+	conns  map[*ConnInfo]*Conn
+}
+
+// Conn represents a registered client connection.
+type Conn struct {
+	ID       int64
+	Started  time.Time
+	ConnInfo *ConnInfo
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		conns: map[*ConnInfo]*Conn{},
+	}
+}
+
+// Add registers the connection.
+//
+// The caller must call [Registry.Remove] when the connection is closed.
+func (r *Registry) Add(connInfo *ConnInfo) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.lastID++
+
+	r.conns[connInfo] = &Conn{
+		ID:       r.lastID,
+		Started:  time.Now(),
+		ConnInfo: connInfo,
+	}
+}
+
+// Remove unregisters the connection.
+func (r *Registry) Remove(connInfo *ConnInfo) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	delete(r.conns, connInfo)
+}
+
+// Conns returns all registered connections sorted by ID.
+func (r *Registry) Conns() []*Conn {
+	r.rw.RLock()
+
+	res := make([]*Conn, 0, len(r.conns))
+	for _, c := range r.conns {
+		res = append(res, c)
+	}
+
+	r.rw.RUnlock()
+
+	slices.SortFunc(res, func(a, b *Conn) int { return int(a.ID - b.ID) })
+
+	return res
+}
@@ -0,0 +1,49 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conninfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	assert.Empty(t, r.Conns())
+
+	c1, c2 := New(), New()
+	r.Add(c1)
+	r.Add(c2)
+
+	c1.SetClientMetadata(&ClientMetadata{DriverName: "nodejs", DriverVersion: "6.3.0"})
+
+	conns := r.Conns()
+	require.Len(t, conns, 2)
+
+	assert.Equal(t, int64(1), conns[0].ID)
+	assert.Same(t, c1, conns[0].ConnInfo)
+	assert.Equal(t, "nodejs", conns[0].ConnInfo.ClientMetadata().DriverName)
+	assert.Nil(t, conns[1].ConnInfo.ClientMetadata())
+
+	r.Remove(c1)
+
+	conns = r.Conns()
+	require.Len(t, conns, 1)
+	assert.Equal(t, int64(2), conns[0].ID)
+}
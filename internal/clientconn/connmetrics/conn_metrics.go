@@ -28,6 +28,12 @@ import (
 type ConnMetrics struct {
 	Requests  *prometheus.CounterVec
 	Responses *prometheus.CounterVec
+	Throttled *prometheus.CounterVec
+
+	Connections        prometheus.Gauge
+	ConnectionsCreated prometheus.Counter
+	ReceivedBytes      prometheus.Counter
+	SentBytes          prometheus.Counter
 }
 
 // commandMetrics represents command results metrics.
@@ -57,6 +63,47 @@ func newConnMetrics() *ConnMetrics {
 			},
 			[]string{"opcode", "command", "argument", "result"},
 		),
+		Throttled: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "throttled_total",
+				Help:      "Total number of operations delayed or rejected by rate limits.",
+			},
+			[]string{"limit", "result"},
+		),
+		Connections: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "connections",
+				Help:      "Current number of client connections.",
+			},
+		),
+		ConnectionsCreated: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "connections_created_total",
+				Help:      "Total number of client connections created.",
+			},
+		),
+		ReceivedBytes: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "received_bytes_total",
+				Help:      "Total number of bytes received from clients.",
+			},
+		),
+		SentBytes: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "sent_bytes_total",
+				Help:      "Total number of bytes sent to clients.",
+			},
+		),
 	}
 }
 
@@ -64,12 +111,92 @@ func newConnMetrics() *ConnMetrics {
 func (cm *ConnMetrics) Describe(ch chan<- *prometheus.Desc) {
 	cm.Requests.Describe(ch)
 	cm.Responses.Describe(ch)
+	cm.Throttled.Describe(ch)
+	cm.Connections.Describe(ch)
+	cm.ConnectionsCreated.Describe(ch)
+	cm.ReceivedBytes.Describe(ch)
+	cm.SentBytes.Describe(ch)
 }
 
 // Collect implements prometheus.Collector.
 func (cm *ConnMetrics) Collect(ch chan<- prometheus.Metric) {
 	cm.Requests.Collect(ch)
 	cm.Responses.Collect(ch)
+	cm.Throttled.Collect(ch)
+	cm.Connections.Collect(ch)
+	cm.ConnectionsCreated.Collect(ch)
+	cm.ReceivedBytes.Collect(ch)
+	cm.SentBytes.Collect(ch)
+}
+
+// Totals represents current values of connection and network metrics.
+type Totals struct {
+	Connections        int64
+	ConnectionsCreated int64
+	ReceivedBytes      int64
+	SentBytes          int64
+}
+
+// GetTotals returns current values of connection and network metrics.
+func (cm *ConnMetrics) GetTotals() *Totals {
+	return &Totals{
+		Connections:        int64(metricValue(cm.Connections)),
+		ConnectionsCreated: int64(metricValue(cm.ConnectionsCreated)),
+		ReceivedBytes:      int64(metricValue(cm.ReceivedBytes)),
+		SentBytes:          int64(metricValue(cm.SentBytes)),
+	}
+}
+
+// GetRequests returns a map with all request metrics:
+//
+// opcode (e.g. "OP_MSG", "OP_QUERY") ->
+// command (e.g. "find", "aggregate") ->
+// count.
+func (cm *ConnMetrics) GetRequests() map[string]map[string]int {
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		cm.Requests.Collect(metrics)
+		close(metrics)
+	}()
+
+	res := map[string]map[string]int{}
+
+	for m := range metrics {
+		var content dto.Metric
+		must.NoError(m.Write(&content))
+
+		var opcode, command string
+		for _, label := range content.GetLabel() {
+			switch label.GetName() {
+			case "opcode":
+				opcode = label.GetValue()
+			case "command":
+				command = label.GetValue()
+			default:
+				panic(fmt.Sprintf("%s is not a valid label. Allowed: [opcode, command]", label.GetName()))
+			}
+		}
+
+		if _, ok := res[opcode]; !ok {
+			res[opcode] = map[string]int{}
+		}
+
+		res[opcode][command] += int(content.GetCounter().GetValue())
+	}
+
+	return res
+}
+
+// metricValue returns the current value of a counter or gauge.
+func metricValue(m prometheus.Metric) float64 {
+	var content dto.Metric
+	must.NoError(m.Write(&content))
+
+	if g := content.GetGauge(); g != nil {
+		return g.GetValue()
+	}
+
+	return content.GetCounter().GetValue()
 }
 
 // GetResponses returns a map with all response metrics:
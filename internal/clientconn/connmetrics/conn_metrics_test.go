@@ -38,3 +38,36 @@ func TestGetResponses(t *testing.T) {
 	}
 	assert.Equal(t, expected, m.GetResponses())
 }
+
+func TestGetRequests(t *testing.T) {
+	m := newConnMetrics()
+	m.Requests.WithLabelValues("OP_MSG", "find").Inc()
+	m.Requests.WithLabelValues("OP_MSG", "find").Inc()
+	m.Requests.WithLabelValues("OP_QUERY", "unknown").Inc()
+	expected := map[string]map[string]int{
+		"OP_MSG": {
+			"find": 2,
+		},
+		"OP_QUERY": {
+			"unknown": 1,
+		},
+	}
+	assert.Equal(t, expected, m.GetRequests())
+}
+
+func TestGetTotals(t *testing.T) {
+	m := newConnMetrics()
+	m.Connections.Inc()
+	m.Connections.Inc()
+	m.Connections.Dec()
+	m.ConnectionsCreated.Add(2)
+	m.ReceivedBytes.Add(100)
+	m.SentBytes.Add(200)
+	expected := &Totals{
+		Connections:        1,
+		ConnectionsCreated: 2,
+		ReceivedBytes:      100,
+		SentBytes:          200,
+	}
+	assert.Equal(t, expected, m.GetTotals())
+}
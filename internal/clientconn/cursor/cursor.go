@@ -24,7 +24,9 @@
 package cursor
 
 import (
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -70,9 +72,13 @@ type Cursor struct {
 	r            *Registry
 	l            *zap.Logger
 	token        *resource.Token
-	removed      chan struct{} // protected by m
+	removed      chan struct{}     // protected by m
+	unread       []*types.Document // protected by m
+	prefetch     *prefetch         // protected by m
 	ID           int64
-	lastRecordID int64 // protected by m
+	lastUsed     atomic.Int64 // UnixNano
+	lastRecordID int64        // protected by m
+	prevRecordID int64        // protected by m
 	m            sync.Mutex
 }
 
@@ -93,11 +99,18 @@ func newCursor(id int64, iter types.DocumentsIterator, params *NewParams, r *Reg
 		token:     resource.NewToken(),
 	}
 
+	c.lastUsed.Store(c.created.UnixNano())
+
 	resource.Track(c, c.token)
 
 	return c
 }
 
+// idle returns the duration since the cursor was used last time.
+func (c *Cursor) idle() time.Duration {
+	return time.Since(time.Unix(0, c.lastUsed.Load()))
+}
+
 // Reset replaces the underlying iterator with a given one
 // and advanced it until the last known record ID is reached.
 //
@@ -111,6 +124,7 @@ func (c *Cursor) Reset(iter types.DocumentsIterator) error {
 
 	c.l.Debug("Resetting cursor")
 	c.iter = iter
+	c.unread = nil
 	recordID := c.lastRecordID
 
 	c.m.Unlock()
@@ -132,13 +146,24 @@ func (c *Cursor) Next() (struct{}, *types.Document, error) {
 	c.m.Lock()
 	defer c.m.Unlock()
 
+	c.lastUsed.Store(time.Now().UnixNano())
+
 	if c.iter == nil {
 		return struct{}{}, nil, iterator.ErrIteratorDone
 	}
 
+	if len(c.unread) > 0 {
+		doc := c.unread[0]
+		c.unread = c.unread[1:]
+		c.lastRecordID = doc.RecordID()
+
+		return struct{}{}, doc, nil
+	}
+
 	zero, doc, err := c.iter.Next()
 	if doc != nil {
 		recordID := doc.RecordID()
+		c.prevRecordID = c.lastRecordID
 		c.lastRecordID = recordID
 
 		if c.ShowRecordID {
@@ -149,6 +174,75 @@ func (c *Cursor) Next() (struct{}, *types.Document, error) {
 	return zero, doc, err
 }
 
+// Unread makes documents returned by the last Next calls to be returned again by the next ones,
+// in the same order.
+//
+// It is used to keep documents that do not fit into the current batch for the next batch.
+// For tailable cursors, only the document returned by the last Next call could be unread.
+func (c *Cursor) Unread(docs ...*types.Document) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if len(docs) == 0 || c.iter == nil {
+		return
+	}
+
+	if c.Type != Normal && (len(docs) > 1 || len(c.unread) > 0) {
+		panic("Unread called with more than one document on tailable cursor")
+	}
+
+	c.unread = append(slices.Clone(docs), c.unread...)
+	c.lastRecordID = c.prevRecordID
+}
+
+// Prefetch calls fetch in a separate goroutine to fetch the next batch of documents,
+// so the next [Cursor.Prefetched] call returns it without waiting for the backend.
+// Fetch should use Next and should not close the cursor.
+//
+// The release function is called when the prefetched batch is returned or discarded.
+// It is called immediately if the cursor is closed or some batch is already being prefetched.
+func (c *Cursor) Prefetch(fetch func() ([]*types.Document, error), release func()) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.iter == nil || c.prefetch != nil {
+		release()
+		return
+	}
+
+	c.l.Debug("Prefetching next batch")
+
+	p := &prefetch{
+		done:    make(chan struct{}),
+		release: release,
+	}
+	c.prefetch = p
+
+	go func() {
+		defer close(p.done)
+
+		p.docs, p.err = fetch()
+	}()
+}
+
+// Prefetched waits for the batch started by the last [Cursor.Prefetch] call and returns it.
+// The returned ok is false if there is no such batch.
+func (c *Cursor) Prefetched() (docs []*types.Document, ok bool, err error) {
+	c.m.Lock()
+	p := c.prefetch
+	c.prefetch = nil
+	c.m.Unlock()
+
+	if p == nil {
+		return nil, false, nil
+	}
+
+	<-p.done
+	p.release()
+
+	return p.docs, true, p.err
+}
+
 // Close implements types.DocumentsIterator interface.
 //
 // It closes the underlying iterator.
@@ -164,6 +258,17 @@ func (c *Cursor) Close() {
 	c.l.Debug("Closing cursor's iterator")
 	c.iter.Close()
 	c.iter = nil
+	c.unread = nil
+
+	if p := c.prefetch; p != nil {
+		c.prefetch = nil
+
+		// fetch may be waiting for the lock, and may even call Close itself
+		go func() {
+			<-p.done
+			p.release()
+		}()
+	}
 
 	c.m.Unlock()
 
@@ -175,6 +280,14 @@ func (c *Cursor) Close() {
 	resource.Untrack(c, c.token)
 }
 
+// prefetch represents a batch being fetched in the background.
+type prefetch struct {
+	done    chan struct{}
+	release func()
+	docs    []*types.Document // set before done is closed
+	err     error             // set before done is closed
+}
+
 // check interfaces
 var (
 	_ types.DocumentsIterator = (*Cursor)(nil)
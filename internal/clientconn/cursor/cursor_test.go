@@ -16,6 +16,7 @@ package cursor
 
 import (
 	"context"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -91,6 +92,69 @@ func TestCursor(t *testing.T) {
 			assert.Nil(t, r.Get(c.ID), "cursor should be removed")
 		})
 
+		t.Run("Unread", func(t *testing.T) {
+			t.Parallel()
+
+			c := r.NewCursor(ctx, iterator.Values(iterator.ForSlice(all)), params)
+
+			first, err := iterator.ConsumeValuesN(c, 2)
+			require.NoError(t, err)
+			assert.Equal(t, two, first)
+
+			c.Unread(doc2)
+
+			actual, err := iterator.ConsumeValues(c)
+			require.NoError(t, err)
+			assert.Equal(t, []*types.Document{doc2, doc3}, actual)
+
+			assert.Nil(t, r.Get(c.ID), "cursor should be removed")
+		})
+
+		t.Run("Prefetch", func(t *testing.T) {
+			t.Parallel()
+
+			c := r.NewCursor(ctx, iterator.Values(iterator.ForSlice(all)), params)
+
+			fetch := func() ([]*types.Document, error) {
+				var res []*types.Document
+
+				for range 2 {
+					_, doc, err := c.Next()
+					if err != nil {
+						return nil, err
+					}
+
+					res = append(res, doc)
+				}
+
+				return res, nil
+			}
+
+			var released atomic.Int32
+			release := func() { released.Add(1) }
+
+			c.Prefetch(fetch, release)
+			c.Prefetch(fetch, release) // already prefetching
+			assert.Equal(t, int32(1), released.Load())
+
+			docs, ok, err := c.Prefetched()
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, two, docs)
+			assert.Equal(t, int32(2), released.Load())
+
+			_, ok, _ = c.Prefetched()
+			assert.False(t, ok)
+
+			c.Prefetch(fetch, release)
+			c.Close()
+
+			assert.Eventually(t, func() bool { return released.Load() == 3 }, time.Second, 10*time.Millisecond)
+
+			_, ok, _ = c.Prefetched()
+			assert.False(t, ok)
+		})
+
 		t.Run("Reset", func(t *testing.T) {
 			t.Parallel()
 
@@ -168,3 +232,35 @@ func TestCursor(t *testing.T) {
 		})
 	})
 }
+
+func TestCursorTimeout(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(testutil.Logger(t))
+	t.Cleanup(r.Close)
+
+	assert.Equal(t, DefaultTimeout, r.Timeout())
+
+	r.SetTimeout(100 * time.Millisecond)
+
+	ctx := testutil.Ctx(t)
+	docs := []*types.Document{must.NotFail(types.NewDocument("v", int32(1)))}
+
+	c := r.NewCursor(ctx, iterator.Values(iterator.ForSlice(docs)), &NewParams{Type: Tailable})
+	require.Same(t, c, r.Get(c.ID))
+
+	require.Eventually(t, func() bool {
+		return r.Get(c.ID) == nil
+	}, 10*time.Second, 50*time.Millisecond, "idle cursor should be removed")
+
+	_, _, err := c.Next()
+	assert.ErrorIs(t, err, iterator.ErrIteratorDone)
+
+	r.SetTimeout(0)
+
+	c = r.NewCursor(ctx, iterator.Values(iterator.ForSlice(docs)), &NewParams{Type: Tailable})
+	t.Cleanup(func() { r.CloseAndRemove(c) })
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Same(t, c, r.Get(c.ID), "cursor should not be removed when timeouts are disabled")
+}
@@ -45,15 +45,38 @@ func init() {
 	}
 }
 
+// DefaultTimeout is the default duration after which idle cursors are closed.
+const DefaultTimeout = 10 * time.Minute
+
+// timeoutCheckInterval is the maximum interval between checks for idle cursors.
+const timeoutCheckInterval = 4 * time.Second
+
+// numShards is the number of registry shards.
+// It must be a power of two.
+const numShards = 64
+
+// shard stores a subset of cursors.
+type shard struct {
+	rw sync.RWMutex
+	m  map[int64]*Cursor
+
+	_ [64]byte // prevent false sharing of locks of adjacent shards
+}
+
 // Registry stores cursors.
 //
+// Cursors are partitioned into shards by ID, so operations on different cursors
+// (for example, concurrent getMore commands) do not contend on a single lock.
+//
 //nolint:vet // for readability
 type Registry struct {
-	rw sync.RWMutex
-	m  map[int64]*Cursor
+	shards  [numShards]shard
+	count   atomic.Int64
+	timeout atomic.Int64 // time.Duration
 
-	l  *zap.Logger
-	wg sync.WaitGroup
+	l    *zap.Logger
+	wg   sync.WaitGroup
+	done chan struct{}
 
 	created  *prometheus.CounterVec
 	duration *prometheus.HistogramVec
@@ -61,9 +84,9 @@ type Registry struct {
 
 // NewRegistry creates a new Registry.
 func NewRegistry(l *zap.Logger) *Registry {
-	return &Registry{
-		m: map[int64]*Cursor{},
-		l: l,
+	r := &Registry{
+		l:    l,
+		done: make(chan struct{}),
 		created: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Namespace: namespace,
@@ -97,12 +120,84 @@ func NewRegistry(l *zap.Logger) *Registry {
 			[]string{"type", "db", "collection", "username"},
 		),
 	}
+
+	for i := range r.shards {
+		r.shards[i].m = map[int64]*Cursor{}
+	}
+
+	r.SetTimeout(DefaultTimeout)
+
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		r.runTimeouts()
+	}()
+
+	return r
+}
+
+// Timeout returns the duration after which idle cursors are closed.
+// Zero means that idle cursors are never closed.
+func (r *Registry) Timeout() time.Duration {
+	return time.Duration(r.timeout.Load())
+}
+
+// SetTimeout sets the duration after which idle cursors are closed.
+// Zero disables timeouts; negative values are treated as zero.
+//
+// It could be called at any time; the new value is applied to all existing cursors.
+func (r *Registry) SetTimeout(d time.Duration) {
+	r.timeout.Store(int64(max(d, 0)))
+}
+
+// runTimeouts periodically closes and removes idle cursors until the registry is closed.
+func (r *Registry) runTimeouts() {
+	for {
+		interval := timeoutCheckInterval
+		if timeout := r.Timeout(); timeout > 0 {
+			interval = min(interval, timeout)
+		}
+
+		t := time.NewTimer(interval)
+
+		select {
+		case <-t.C:
+			r.closeIdle()
+
+		case <-r.done:
+			t.Stop()
+			return
+		}
+	}
+}
+
+// closeIdle closes and removes cursors that were not used for longer than the timeout.
+func (r *Registry) closeIdle() {
+	timeout := r.Timeout()
+	if timeout <= 0 {
+		return
+	}
+
+	for _, c := range r.All() {
+		if idle := c.idle(); idle > timeout {
+			r.l.Debug("Closing idle cursor", zap.Int64("id", c.ID), zap.Duration("idle", idle))
+			r.CloseAndRemove(c)
+		}
+	}
+}
+
+// shard returns the shard for the given cursor ID.
+func (r *Registry) shard(id int64) *shard {
+	return &r.shards[uint64(id)&(numShards-1)]
 }
 
 // Close waits for all cursors to be closed and removed from the registry.
 func (r *Registry) Close() {
 	// we mainly do that for tests; see https://github.com/uber-go/zap/issues/687
 
+	close(r.done)
 	r.wg.Wait()
 }
 
@@ -130,15 +225,27 @@ type NewParams struct {
 // The cursor of any type will be closed automatically when a given context is canceled,
 // even if the cursor is not being used at that time.
 func (r *Registry) NewCursor(ctx context.Context, iter types.DocumentsIterator, params *NewParams) *Cursor {
-	r.rw.Lock()
-	defer r.rw.Unlock()
-
 	// use global, sequential, positive, short cursor IDs to make debugging easier
 	var id int64
-	for id == 0 || r.m[id] != nil {
-		id = int64(lastCursorID.Add(1))
+	var s *shard
+
+	for {
+		if id = int64(lastCursorID.Add(1)); id == 0 {
+			continue
+		}
+
+		s = r.shard(id)
+		s.rw.Lock()
+
+		if s.m[id] == nil {
+			break
+		}
+
+		s.rw.Unlock()
 	}
 
+	defer s.rw.Unlock()
+
 	r.l.Debug(
 		"Creating cursor",
 		zap.Int64("id", id), zap.Stringer("type", params.Type),
@@ -148,7 +255,8 @@ func (r *Registry) NewCursor(ctx context.Context, iter types.DocumentsIterator,
 	r.created.WithLabelValues(params.Type.String(), params.DB, params.Collection, params.Username).Inc()
 
 	c := newCursor(id, iter, params, r)
-	r.m[id] = c
+	s.m[id] = c
+	r.count.Add(1)
 
 	r.wg.Add(1)
 	go func() {
@@ -168,28 +276,41 @@ func (r *Registry) NewCursor(ctx context.Context, iter types.DocumentsIterator,
 
 // Get returns stored cursor by ID, or nil.
 func (r *Registry) Get(id int64) *Cursor {
-	r.rw.RLock()
-	defer r.rw.RUnlock()
+	s := r.shard(id)
+
+	s.rw.RLock()
+	defer s.rw.RUnlock()
 
-	return r.m[id]
+	return s.m[id]
 }
 
 // All returns a shallow copy of all stored cursors.
+//
+// Shards are locked one by one, so the result is not an atomic snapshot of the whole registry.
 func (r *Registry) All() []*Cursor {
-	r.rw.RLock()
-	defer r.rw.RUnlock()
+	res := make([]*Cursor, 0, r.count.Load())
+
+	for i := range r.shards {
+		s := &r.shards[i]
 
-	return maps.Values(r.m)
+		s.rw.RLock()
+		res = append(res, maps.Values(s.m)...)
+		s.rw.RUnlock()
+	}
+
+	return res
 }
 
 // CloseAndRemove closes the given cursors, then removes it from the registry.
 func (r *Registry) CloseAndRemove(c *Cursor) {
 	c.Close()
 
-	r.rw.Lock()
-	defer r.rw.Unlock()
+	s := r.shard(c.ID)
+
+	s.rw.Lock()
+	defer s.rw.Unlock()
 
-	if r.m[c.ID] == nil {
+	if s.m[c.ID] == nil {
 		return
 	}
 
@@ -198,13 +319,14 @@ func (r *Registry) CloseAndRemove(c *Cursor) {
 		"Removing cursor",
 		zap.Int64("id", c.ID),
 		zap.Stringer("type", c.Type),
-		zap.Int("total", len(r.m)),
+		zap.Int64("total", r.count.Load()),
 		zap.Duration("duration", d),
 	)
 
 	r.duration.WithLabelValues(c.Type.String(), c.DB, c.Collection, c.Username).Observe(d.Seconds())
 
-	delete(r.m, c.ID)
+	delete(s.m, c.ID)
+	r.count.Add(-1)
 	close(c.removed)
 }
 
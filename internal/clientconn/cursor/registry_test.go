@@ -0,0 +1,85 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cursor
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+)
+
+func BenchmarkRegistry(b *testing.B) {
+	ctx := context.Background()
+	params := &NewParams{Type: Normal}
+
+	newIter := func() types.DocumentsIterator {
+		return iterator.Values(iterator.ForSlice([]*types.Document{}))
+	}
+
+	b.Run("NewGetRemove", func(b *testing.B) {
+		r := NewRegistry(zap.NewNop())
+		b.Cleanup(r.Close)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				c := r.NewCursor(ctx, newIter(), params)
+
+				if r.Get(c.ID) != c {
+					b.Error("cursor not found")
+				}
+
+				r.CloseAndRemove(c)
+			}
+		})
+	})
+
+	b.Run("Get", func(b *testing.B) {
+		r := NewRegistry(zap.NewNop())
+		b.Cleanup(r.Close)
+
+		cursors := make([]*Cursor, 1000)
+		for i := range cursors {
+			cursors[i] = r.NewCursor(ctx, newIter(), params)
+		}
+
+		b.Cleanup(func() {
+			for _, c := range cursors {
+				r.CloseAndRemove(c)
+			}
+		})
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		b.RunParallel(func(pb *testing.PB) {
+			var i int
+
+			for pb.Next() {
+				if r.Get(cursors[i%len(cursors)].ID) == nil {
+					b.Error("cursor not found")
+				}
+
+				i++
+			}
+		})
+	})
+}
@@ -0,0 +1,186 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/tlsutil"
+)
+
+// Endpoint represents an additional listener with its own settings.
+type Endpoint struct {
+	Network string // "tcp", "unix", or "tls"
+	Addr    string // host:port or Unix domain socket path
+
+	// for "tls" network; CA file may be empty to skip client's certificate validation
+	TLSCertFile string
+	TLSKeyFile  string
+	TLSCAFile   string
+
+	// if true, commands other than handshake and authentication ones
+	// fail until the client authenticates
+	RequireAuth bool
+}
+
+// ParseEndpoint parses an endpoint URL like `tcp://127.0.0.1:27018`, `unix:///tmp/ferretdb.sock`,
+// or `tls://0.0.0.0:27019?cert-file=cert.pem&key-file=key.pem&ca-file=ca.pem`.
+//
+// The `auth=required` query parameter sets RequireAuth for any network.
+func ParseEndpoint(s string) (*Endpoint, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Endpoint{
+		Network: u.Scheme,
+	}
+
+	switch e.Network {
+	case "tcp", "tls":
+		e.Addr = u.Host
+	case "unix":
+		e.Addr = u.Path
+	default:
+		return nil, fmt.Errorf("%q: unexpected scheme %q, expected tcp, unix, or tls", s, u.Scheme)
+	}
+
+	if e.Addr == "" {
+		return nil, fmt.Errorf("%q: address is empty", s)
+	}
+
+	for k, vs := range u.Query() {
+		if len(vs) != 1 {
+			return nil, fmt.Errorf("%q: parameter %q is set %d times", s, k, len(vs))
+		}
+
+		v := vs[0]
+
+		switch k {
+		case "cert-file":
+			e.TLSCertFile = v
+		case "key-file":
+			e.TLSKeyFile = v
+		case "ca-file":
+			e.TLSCAFile = v
+		case "auth":
+			if v != "required" && v != "none" {
+				return nil, fmt.Errorf("%q: unexpected auth %q, expected required or none", s, v)
+			}
+
+			e.RequireAuth = v == "required"
+		default:
+			return nil, fmt.Errorf("%q: unexpected parameter %q", s, k)
+		}
+	}
+
+	tlsFiles := e.TLSCertFile != "" || e.TLSKeyFile != "" || e.TLSCAFile != ""
+
+	switch {
+	case e.Network == "tls" && (e.TLSCertFile == "" || e.TLSKeyFile == ""):
+		return nil, fmt.Errorf("%q: cert-file and key-file are required for tls", s)
+	case e.Network != "tls" && tlsFiles:
+		return nil, fmt.Errorf("%q: TLS files could be set only for tls", s)
+	}
+
+	return e, nil
+}
+
+// String returns endpoint's URL.
+func (e *Endpoint) String() string {
+	u := &url.URL{
+		Scheme: e.Network,
+		Host:   e.Addr,
+	}
+
+	if e.Network == "unix" {
+		u.Host = ""
+		u.Path = e.Addr
+	}
+
+	q := url.Values{}
+
+	if e.TLSCertFile != "" {
+		q.Set("cert-file", e.TLSCertFile)
+	}
+
+	if e.TLSKeyFile != "" {
+		q.Set("key-file", e.TLSKeyFile)
+	}
+
+	if e.TLSCAFile != "" {
+		q.Set("ca-file", e.TLSCAFile)
+	}
+
+	if e.RequireAuth {
+		q.Set("auth", "required")
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+// endpointListener represents a running endpoint.
+type endpointListener struct {
+	*Endpoint
+
+	listener  net.Listener
+	tlsConfig atomic.Pointer[tls.Config] // replaced by reloadTLS
+}
+
+// listen starts listening on the endpoint.
+func (el *endpointListener) listen() error {
+	var err error
+
+	switch el.Network {
+	case "tcp", "unix":
+		el.listener, err = net.Listen(el.Network, el.Addr)
+
+	case "tls":
+		if err = el.reloadTLS(); err != nil {
+			return err
+		}
+
+		el.listener, err = setupTLSListener(&setupTLSListenerOpts{
+			addr:   el.Addr,
+			config: &el.tlsConfig,
+		})
+	}
+
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// reloadTLS loads TLS files for new connections.
+func (el *endpointListener) reloadTLS() error {
+	config, err := tlsutil.Config(el.TLSCertFile, el.TLSKeyFile, el.TLSCAFile)
+	if err != nil {
+		return err
+	}
+
+	el.tlsConfig.Store(config)
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		s        string
+		expected *Endpoint
+		err      string
+	}{
+		"TCP": {
+			s:        "tcp://127.0.0.1:27018",
+			expected: &Endpoint{Network: "tcp", Addr: "127.0.0.1:27018"},
+		},
+		"Unix": {
+			s:        "unix:///tmp/ferretdb.sock?auth=required",
+			expected: &Endpoint{Network: "unix", Addr: "/tmp/ferretdb.sock", RequireAuth: true},
+		},
+		"TLS": {
+			s: "tls://0.0.0.0:27019?ca-file=ca.pem&cert-file=cert.pem&key-file=key.pem",
+			expected: &Endpoint{
+				Network:     "tls",
+				Addr:        "0.0.0.0:27019",
+				TLSCertFile: "cert.pem",
+				TLSKeyFile:  "key.pem",
+				TLSCAFile:   "ca.pem",
+			},
+		},
+		"Scheme": {
+			s:   "http://127.0.0.1:27018",
+			err: `"http://127.0.0.1:27018": unexpected scheme "http", expected tcp, unix, or tls`,
+		},
+		"NoAddr": {
+			s:   "unix://",
+			err: `"unix://": address is empty`,
+		},
+		"Param": {
+			s:   "tcp://127.0.0.1:27018?foo=bar",
+			err: `"tcp://127.0.0.1:27018?foo=bar": unexpected parameter "foo"`,
+		},
+		"Auth": {
+			s:   "tcp://127.0.0.1:27018?auth=yes",
+			err: `"tcp://127.0.0.1:27018?auth=yes": unexpected auth "yes", expected required or none`,
+		},
+		"TLSNoKey": {
+			s:   "tls://127.0.0.1:27018?cert-file=cert.pem",
+			err: `"tls://127.0.0.1:27018?cert-file=cert.pem": cert-file and key-file are required for tls`,
+		},
+		"TCPWithCert": {
+			s:   "tcp://127.0.0.1:27018?cert-file=cert.pem",
+			err: `"tcp://127.0.0.1:27018?cert-file=cert.pem": TLS files could be set only for tls`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := ParseEndpoint(tc.s)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+
+			roundtrip, err := ParseEndpoint(actual.String())
+			require.NoError(t, err)
+			assert.Equal(t, actual, roundtrip)
+		})
+	}
+}
@@ -23,19 +23,25 @@ import (
 	"net"
 	"runtime/pprof"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
+	"github.com/FerretDB/FerretDB/internal/clientconn/ratelimit"
 	"github.com/FerretDB/FerretDB/internal/handler"
 	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
 	"github.com/FerretDB/FerretDB/internal/util/tlsutil"
 	"github.com/FerretDB/FerretDB/internal/wire"
 )
 
+// DefaultDrainTimeout is the default duration for in-flight commands to complete on shutdown.
+const DefaultDrainTimeout = 10 * time.Second
+
 // Listener listens on one or multiple interfaces (TCP, Unix, TLS sockets)
 // and accepts incoming client connections.
 type Listener struct {
@@ -48,6 +54,15 @@ type Listener struct {
 	tcpListenerReady  chan struct{}
 	unixListenerReady chan struct{}
 	tlsListenerReady  chan struct{}
+
+	tlsConfig atomic.Pointer[tls.Config] // replaced by ReloadTLS
+
+	endpointsM sync.Mutex
+	endpoints  []*endpointListener // protected by endpointsM
+
+	accepting atomic.Bool // true when all listeners accept connections
+
+	limiter *ratelimit.Limiter // shared by all connections; nil if there is no global rate limit
 }
 
 // NewListenerOpts represents listener configuration.
@@ -60,11 +75,31 @@ type NewListenerOpts struct {
 	TLSKeyFile  string
 	TLSCAFile   string
 
+	// additional listeners with their own settings
+	Endpoints []*Endpoint
+
+	// already listening sockets, for example, passed by systemd socket activation;
+	// they are closed by Run
+	Activated []net.Listener
+
 	ProxyAddr        string
 	ProxyTLSCertFile string
 	ProxyTLSKeyFile  string
 	ProxyTLSCAFile   string
 
+	// In-flight commands are allowed to complete for up to that duration after ctx passed to Run is canceled;
+	// idle connections are closed immediately. If zero, DefaultDrainTimeout is used.
+	DrainTimeout time.Duration
+
+	// Connections without requests for that duration are closed. If zero, idle connections are kept.
+	IdleTimeout time.Duration
+
+	// Maximum number of operations per second for all connections and for a single connection;
+	// zero disables that. Operations over the limit are queued for up to RateLimitMaxWait and then rejected.
+	RateLimit        float64
+	ConnRateLimit    float64
+	RateLimitMaxWait time.Duration
+
 	Mode           Mode
 	Metrics        *connmetrics.ListenerMetrics
 	Handler        *handler.Handler
@@ -76,6 +111,7 @@ type NewListenerOpts struct {
 func NewListener(opts *NewListenerOpts) *Listener {
 	return &Listener{
 		NewListenerOpts:   opts,
+		limiter:           ratelimit.New(opts.RateLimit, 0, opts.RateLimitMaxWait),
 		tcpListenerReady:  make(chan struct{}),
 		unixListenerReady: make(chan struct{}),
 		tlsListenerReady:  make(chan struct{}),
@@ -117,13 +153,13 @@ func (l *Listener) Run(ctx context.Context) error {
 	}
 
 	if l.TLS != "" {
-		var err error
-		l.tlsListener, err = setupTLSListener(&setupTLSListenerOpts{
-			addr:     l.TLS,
-			certFile: l.TLSCertFile,
-			keyFile:  l.TLSKeyFile,
-			caFile:   l.TLSCAFile,
-		})
+		err := l.ReloadTLS(l.TLSCertFile, l.TLSKeyFile, l.TLSCAFile)
+		if err == nil {
+			l.tlsListener, err = setupTLSListener(&setupTLSListenerOpts{
+				addr:   l.TLS,
+				config: &l.tlsConfig,
+			})
+		}
 
 		close(l.tlsListenerReady)
 
@@ -134,6 +170,35 @@ func (l *Listener) Run(ctx context.Context) error {
 		logger.Sugar().Infof("Listening on TLS %s ...", l.TLSAddr())
 	}
 
+	endpoints := make([]*endpointListener, 0, len(l.Endpoints))
+
+	for _, e := range l.Endpoints {
+		el := &endpointListener{Endpoint: e}
+		if err := el.listen(); err != nil {
+			return err
+		}
+
+		endpoints = append(endpoints, el)
+
+		logger.Sugar().Infof(
+			"Listening on %s %s (authentication required: %t) ...",
+			e.Network, el.listener.Addr(), e.RequireAuth,
+		)
+	}
+
+	l.endpointsM.Lock()
+	l.endpoints = endpoints
+	l.endpointsM.Unlock()
+
+	for _, al := range l.Activated {
+		logger.Sugar().Infof("Listening on activated socket %s %s ...", al.Addr().Network(), al.Addr())
+	}
+
+	// warnings logged after that point are not startup warnings
+	logging.StartupFinished()
+
+	l.accepting.Store(true)
+
 	var wg sync.WaitGroup
 
 	wg.Add(1)
@@ -142,6 +207,8 @@ func (l *Listener) Run(ctx context.Context) error {
 
 		<-ctx.Done()
 
+		l.accepting.Store(false)
+
 		if l.tcpListener != nil {
 			l.tcpListener.Close()
 		}
@@ -153,6 +220,14 @@ func (l *Listener) Run(ctx context.Context) error {
 		if l.tlsListener != nil {
 			l.tlsListener.Close()
 		}
+
+		for _, el := range endpoints {
+			el.listener.Close()
+		}
+
+		for _, al := range l.Activated {
+			al.Close()
+		}
 	}()
 
 	if l.TCP != "" {
@@ -164,7 +239,7 @@ func (l *Listener) Run(ctx context.Context) error {
 				wg.Done()
 			}()
 
-			acceptLoop(ctx, l.tcpListener, &wg, l, logger)
+			acceptLoop(ctx, l.tcpListener, false, &wg, l, logger)
 		}()
 	}
 
@@ -177,7 +252,7 @@ func (l *Listener) Run(ctx context.Context) error {
 				wg.Done()
 			}()
 
-			acceptLoop(ctx, l.unixListener, &wg, l, logger)
+			acceptLoop(ctx, l.unixListener, false, &wg, l, logger)
 		}()
 	}
 
@@ -190,30 +265,108 @@ func (l *Listener) Run(ctx context.Context) error {
 				wg.Done()
 			}()
 
-			acceptLoop(ctx, l.tlsListener, &wg, l, logger)
+			acceptLoop(ctx, l.tlsListener, false, &wg, l, logger)
+		}()
+	}
+
+	for _, el := range endpoints {
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				logger.Sugar().Infof("%s stopped.", el.listener.Addr())
+				wg.Done()
+			}()
+
+			acceptLoop(ctx, el.listener, el.RequireAuth, &wg, l, logger)
+		}()
+	}
+
+	for _, al := range l.Activated {
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				logger.Sugar().Infof("%s stopped.", al.Addr())
+				wg.Done()
+			}()
+
+			acceptLoop(ctx, al, false, &wg, l, logger)
 		}()
 	}
 
 	<-ctx.Done()
-	logger.Info("Waiting for all connections to stop...")
+	logger.Sugar().Infof("Waiting for in-flight commands to complete for up to %s ...", l.drainTimeout())
 	wg.Wait()
 
 	return context.Cause(ctx)
 }
 
+// Check returns an error if listener does not accept new connections:
+// it is not started yet, failed to start, or stopping.
+//
+// It is used by health and readiness probes.
+func (l *Listener) Check(context.Context) error {
+	if !l.accepting.Load() {
+		return errors.New("listener does not accept connections")
+	}
+
+	return nil
+}
+
+// ReloadTLS loads TLS certificate, key, and CA files (the last one may be empty
+// to skip client's certificate validation) for new TLS connections.
+// Established connections are not affected.
+//
+// If files can't be loaded, an error is returned, and the previous configuration is kept.
+func (l *Listener) ReloadTLS(certFile, keyFile, caFile string) error {
+	config, err := tlsutil.Config(certFile, keyFile, caFile)
+	if err != nil {
+		return err
+	}
+
+	l.tlsConfig.Store(config)
+
+	return nil
+}
+
+// ReloadEndpointsTLS loads TLS files of additional TLS listeners for new connections.
+// Established connections are not affected.
+//
+// Listeners with files that can't be loaded keep the previous configuration;
+// errors for all of them are returned.
+func (l *Listener) ReloadEndpointsTLS() error {
+	l.endpointsM.Lock()
+	endpoints := l.endpoints
+	l.endpointsM.Unlock()
+
+	var errs []error
+
+	for _, el := range endpoints {
+		if el.Network != "tls" {
+			continue
+		}
+
+		if err := el.reloadTLS(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", el.Addr, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
 // setupTLSListenerOpts represents TLS listener setup options.
 type setupTLSListenerOpts struct {
-	addr     string
-	certFile string
-	keyFile  string
-	caFile   string // may be empty to skip client's certificate validation
+	addr   string
+	config *atomic.Pointer[tls.Config] // loaded for each new connection
 }
 
 // setupTLSListener returns a new TLS listener or and error.
 func setupTLSListener(opts *setupTLSListenerOpts) (net.Listener, error) {
-	config, err := tlsutil.Config(opts.certFile, opts.keyFile, opts.caFile)
-	if err != nil {
-		return nil, err
+	config := &tls.Config{
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return opts.config.Load(), nil
+		},
 	}
 
 	listener, err := tls.Listen("tcp", opts.addr, config)
@@ -225,7 +378,9 @@ func setupTLSListener(opts *setupTLSListenerOpts) (net.Listener, error) {
 }
 
 // acceptLoop runs listener's connection accepting loop until context is canceled.
-func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup, l *Listener, logger *zap.Logger) {
+//
+// If requireAuth is true, accepted connections require authentication.
+func acceptLoop(ctx context.Context, listener net.Listener, requireAuth bool, wg *sync.WaitGroup, l *Listener, logger *zap.Logger) { //nolint:lll // for readability
 	var retry int64
 	for {
 		netConn, err := listener.Accept()
@@ -271,8 +426,8 @@ func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup,
 
 			connID := fmt.Sprintf("%s -> %s", remoteAddr, netConn.LocalAddr())
 
-			// give clients a few seconds to disconnect after ctx is canceled
-			runCtx, runCancel := ctxutil.WithDelay(ctx.Done(), 3*time.Second)
+			// give in-flight commands some time to complete after ctx is canceled
+			runCtx, runCancel := ctxutil.WithDelay(ctx.Done(), l.drainTimeout())
 			defer runCancel()
 
 			defer pprof.SetGoroutineLabels(runCtx)
@@ -281,10 +436,15 @@ func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup,
 
 			opts := &newConnOpts{
 				netConn:     netConn,
+				drain:       ctx.Done(),
+				idleTimeout: l.IdleTimeout,
+				limiter:     l.limiter,
+				connLimiter: ratelimit.New(l.ConnRateLimit, 0, l.RateLimitMaxWait),
 				mode:        l.Mode,
 				l:           l.Logger.Named("// " + connID + " "), // derive from the original unnamed logger
 				handler:     l.Handler,
 				connMetrics: l.Metrics.ConnMetrics, // share between all conns
+				requireAuth: requireAuth,
 
 				proxyAddr:        l.ProxyAddr,
 				proxyTLSCertFile: l.ProxyTLSCertFile,
@@ -303,7 +463,7 @@ func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup,
 			logger.Info("Connection started", zap.String("conn", connID))
 
 			connErr = conn.run(runCtx)
-			if errors.Is(connErr, wire.ErrZeroRead) {
+			if errors.Is(connErr, wire.ErrZeroRead) || errors.Is(connErr, errDrained) || errors.Is(connErr, errIdleTimeout) {
 				connErr = nil
 				logger.Info("Connection stopped", zap.String("conn", connID))
 			} else {
@@ -313,6 +473,15 @@ func acceptLoop(ctx context.Context, listener net.Listener, wg *sync.WaitGroup,
 	}
 }
 
+// drainTimeout returns the duration for in-flight commands to complete on shutdown.
+func (l *Listener) drainTimeout() time.Duration {
+	if l.DrainTimeout == 0 {
+		return DefaultDrainTimeout
+	}
+
+	return l.DrainTimeout
+}
+
 // TCPAddr returns TCP listener's address.
 // It can be used to determine an actually used port, if it was zero.
 func (l *Listener) TCPAddr() net.Addr {
@@ -0,0 +1,137 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit provides a token bucket rate limiter for client operations.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrLimited is returned when the operation would wait for longer than allowed.
+var ErrLimited = errors.New("rate limit exceeded")
+
+// Limiter allows operations at the given rate with bursts.
+//
+// Operations that exceed the rate are queued for up to the maximum wait duration
+// and allowed in the order of arrival; if the wait would be longer, they are rejected immediately.
+// That bounds the queue length to about rate × maximum wait operations.
+//
+// Nil limiter allows all operations.
+type Limiter struct {
+	rate    float64 // tokens per second
+	burst   float64
+	maxWait time.Duration
+
+	m      sync.Mutex
+	tokens float64 // negative if there are waiting operations
+	last   time.Time
+}
+
+// New creates a new limiter for the given number of operations per second.
+//
+// Burst is the number of operations that could be done at once after some idle time;
+// if zero, the rate rounded up is used.
+// If rate is zero, nil limiter is returned.
+func New(rate float64, burst int, maxWait time.Duration) *Limiter {
+	if rate <= 0 {
+		return nil
+	}
+
+	b := float64(burst)
+	if b <= 0 {
+		b = math.Ceil(rate)
+	}
+
+	return &Limiter{
+		rate:    rate,
+		burst:   b,
+		maxWait: maxWait,
+		tokens:  b,
+	}
+}
+
+// Wait waits until the operation is allowed and returns the waited duration.
+//
+// It returns ErrLimited without waiting if the operation would wait longer than the maximum wait duration,
+// or context error if ctx is canceled while waiting.
+func (l *Limiter) Wait(ctx context.Context) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+
+	d, ok := l.reserve(time.Now())
+	if !ok {
+		return 0, ErrLimited
+	}
+
+	if d == 0 {
+		return 0, nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return d, nil
+	case <-ctx.Done():
+		l.Cancel()
+		return 0, context.Cause(ctx)
+	}
+}
+
+// reserve takes a token and returns the duration after which the operation is allowed.
+// It returns false and does not take a token if that duration exceeds the maximum wait duration.
+func (l *Limiter) reserve(now time.Time) (time.Duration, bool) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+
+	l.last = now
+
+	tokens := l.tokens - 1
+	if tokens >= 0 {
+		l.tokens = tokens
+		return 0, true
+	}
+
+	d := time.Duration(-tokens / l.rate * float64(time.Second))
+	if d > l.maxWait {
+		return 0, false
+	}
+
+	l.tokens = tokens
+
+	return d, true
+}
+
+// Cancel returns a token taken by Wait for the operation that was not done after all.
+func (l *Limiter) Cancel() {
+	if l == nil {
+		return
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	l.tokens = min(l.burst, l.tokens+1)
+}
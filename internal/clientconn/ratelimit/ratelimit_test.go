@@ -0,0 +1,102 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterReserve(t *testing.T) {
+	t.Parallel()
+
+	l := New(10, 2, 150*time.Millisecond)
+	now := time.Now()
+
+	// burst
+	for range 2 {
+		d, ok := l.reserve(now)
+		require.True(t, ok)
+		assert.Zero(t, d)
+	}
+
+	// queued
+	d, ok := l.reserve(now)
+	require.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, d)
+
+	// would wait too long
+	_, ok = l.reserve(now)
+	assert.False(t, ok)
+
+	// rejected operation does not take a token
+	d, ok = l.reserve(now.Add(100 * time.Millisecond))
+	require.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, d)
+
+	// tokens are refilled up to burst
+	for range 2 {
+		d, ok = l.reserve(now.Add(time.Hour))
+		require.True(t, ok)
+		assert.Zero(t, d)
+	}
+
+	_, ok = l.reserve(now.Add(time.Hour))
+	require.True(t, ok)
+}
+
+func TestLimiterWait(t *testing.T) {
+	t.Parallel()
+
+	var l *Limiter
+	d, err := l.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, d)
+
+	assert.Nil(t, New(0, 0, time.Second))
+
+	l = New(1, 1, time.Hour)
+
+	d, err = l.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, d)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = l.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	l = New(1, 1, 0)
+
+	_, err = l.Wait(context.Background())
+	require.NoError(t, err)
+
+	_, err = l.Wait(context.Background())
+	assert.ErrorIs(t, err, ErrLimited)
+
+	// canceled operation returns its token
+	l.Cancel()
+
+	_, err = l.Wait(context.Background())
+	require.NoError(t, err)
+
+	l = nil
+	l.Cancel()
+}
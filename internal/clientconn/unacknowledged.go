@@ -0,0 +1,211 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"context"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+const (
+	// unacknowledgedQueueSize is the number of queued unacknowledged writes
+	// after which the connection stops reading new requests.
+	unacknowledgedQueueSize = 100
+
+	// maxUnacknowledgedErrors is the maximum number of errors of unacknowledged writes
+	// kept until the next response.
+	maxUnacknowledgedErrors = 100
+)
+
+// unacknowledgedCommands contains commands that are not acknowledged with `w: 0` write concern.
+var unacknowledgedCommands = map[string]bool{
+	"delete": true,
+	"insert": true,
+	"update": true,
+}
+
+// unacknowledgedRequest represents a queued request that is executed without sending a response.
+type unacknowledgedRequest struct {
+	ctx    context.Context
+	header *wire.MsgHeader
+	body   wire.MsgBody
+}
+
+// unacknowledged returns true if the client does not wait for the result of the given request.
+//
+// That's the case for OP_MSG requests with moreToCome flag (drivers use it for `w: 0` writes),
+// and for write commands with `w: 0` write concern.
+// For the latter, reply is true: the client still waits for the `{ok: 1}` response,
+// but it could be sent before the command is executed.
+func unacknowledged(reqHeader *wire.MsgHeader, reqBody wire.MsgBody) (unack, reply bool) {
+	if reqHeader.OpCode != wire.OpCodeMsg {
+		return false, false
+	}
+
+	msg := reqBody.(*wire.OpMsg)
+	if msg.FlagBits.FlagSet(wire.OpMsgMoreToCome) {
+		return true, false
+	}
+
+	doc, err := msg.Document()
+	if err != nil || !unacknowledgedCommands[doc.Command()] {
+		return false, false
+	}
+
+	v, _ := doc.Get("writeConcern")
+
+	wc, ok := v.(*types.Document)
+	if !ok {
+		return false, false
+	}
+
+	var zero bool
+
+	w, _ := wc.Get("w")
+	switch w := w.(type) {
+	case int32:
+		zero = w == 0
+	case int64:
+		zero = w == 0
+	case float64:
+		zero = w == 0
+	}
+
+	return zero, zero
+}
+
+// enqueueUnacknowledged queues the unacknowledged request for the execution,
+// starting the goroutine that executes them in order if needed.
+//
+// It blocks if the queue is full.
+// It should be called only by the goroutine that reads requests.
+func (c *conn) enqueueUnacknowledged(req *unacknowledgedRequest) {
+	if c.unackQueue == nil {
+		c.unackQueue = make(chan *unacknowledgedRequest, unacknowledgedQueueSize)
+
+		go func() {
+			for req := range c.unackQueue {
+				c.runUnacknowledged(req)
+				c.unackWG.Done()
+			}
+		}()
+	}
+
+	c.unackWG.Add(1)
+	c.unackQueue <- req
+}
+
+// runUnacknowledged executes a single unacknowledged request,
+// keeping the error, if any, for the next response.
+func (c *conn) runUnacknowledged(req *unacknowledgedRequest) {
+	defer func() {
+		if p := recover(); p != nil {
+			c.l.DPanicf("%v", p)
+		}
+	}()
+
+	resHeader, resBody, _ := c.route(req.ctx, req.header, req.body, true)
+	c.logResponse("Unacknowledged response", resHeader, resBody, false)
+
+	if resHeader.OpCode != wire.OpCodeMsg {
+		return
+	}
+
+	doc := must.NotFail(resBody.(*wire.OpMsg).Document())
+
+	ok, _ := doc.Get("ok")
+	if ok == float64(1) && !doc.Has("writeErrors") && !doc.Has("writeConcernError") {
+		return
+	}
+
+	c.unackErrsM.Lock()
+	defer c.unackErrsM.Unlock()
+
+	if len(c.unackErrs) >= maxUnacknowledgedErrors {
+		c.unackErrs = c.unackErrs[1:]
+	}
+
+	errDoc := must.NotFail(types.NewDocument("requestID", req.header.RequestID))
+
+	for _, k := range doc.Keys() {
+		if k != "ok" {
+			errDoc.Set(k, must.NotFail(doc.Get(k)))
+		}
+	}
+
+	c.unackErrs = append(c.unackErrs, errDoc)
+}
+
+// waitUnacknowledged waits for all queued unacknowledged requests to be executed.
+//
+// It should be called only by the goroutine that reads requests.
+func (c *conn) waitUnacknowledged() {
+	c.unackWG.Wait()
+}
+
+// stopUnacknowledged waits for all queued unacknowledged requests to be executed
+// and stops the goroutine that executes them.
+//
+// It should be called only by the goroutine that reads requests.
+func (c *conn) stopUnacknowledged() {
+	if c.unackQueue == nil {
+		return
+	}
+
+	close(c.unackQueue)
+	c.unackWG.Wait()
+}
+
+// unacknowledgedErrors returns errors of executed unacknowledged requests
+// not returned before, or nil if there were no errors.
+func (c *conn) unacknowledgedErrors() *types.Array {
+	c.unackErrsM.Lock()
+	defer c.unackErrsM.Unlock()
+
+	if len(c.unackErrs) == 0 {
+		return nil
+	}
+
+	res := types.MakeArray(len(c.unackErrs))
+	for _, doc := range c.unackErrs {
+		res.Append(doc)
+	}
+
+	c.unackErrs = nil
+
+	return res
+}
+
+// unacknowledgedReply returns `{ok: 1}` response for the unacknowledged request that expects a reply.
+func (c *conn) unacknowledgedReply(reqHeader *wire.MsgHeader) (*wire.MsgHeader, wire.MsgBody) {
+	var res wire.OpMsg
+	must.NoError(res.SetSections(wire.MakeOpMsgSection(
+		must.NotFail(types.NewDocument("ok", float64(1))),
+	)))
+
+	b := must.NotFail(res.MarshalBinary())
+
+	resHeader := &wire.MsgHeader{
+		OpCode:        wire.OpCodeMsg,
+		RequestID:     c.lastRequestID.Add(1),
+		ResponseTo:    reqHeader.RequestID,
+		MessageLength: int32(wire.MsgHeaderLen + len(b)),
+	}
+
+	return resHeader, &res
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+func TestUnacknowledged(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		flags wire.OpMsgFlags
+		doc   *types.Document
+		unack bool
+		reply bool
+	}{
+		"MoreToCome": {
+			flags: wire.OpMsgFlags(wire.OpMsgMoreToCome),
+			doc:   must.NotFail(types.NewDocument("insert", "test")),
+			unack: true,
+		},
+		"WriteConcernZero": {
+			doc: must.NotFail(types.NewDocument(
+				"insert", "test",
+				"writeConcern", must.NotFail(types.NewDocument("w", int32(0))),
+			)),
+			unack: true,
+			reply: true,
+		},
+		"WriteConcernOne": {
+			doc: must.NotFail(types.NewDocument(
+				"update", "test",
+				"writeConcern", must.NotFail(types.NewDocument("w", float64(1))),
+			)),
+		},
+		"WriteConcernMajority": {
+			doc: must.NotFail(types.NewDocument(
+				"delete", "test",
+				"writeConcern", must.NotFail(types.NewDocument("w", "majority")),
+			)),
+		},
+		"NoWriteConcern": {
+			doc: must.NotFail(types.NewDocument("insert", "test")),
+		},
+		"NotWrite": {
+			doc: must.NotFail(types.NewDocument(
+				"find", "test",
+				"writeConcern", must.NotFail(types.NewDocument("w", int32(0))),
+			)),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			msg := &wire.OpMsg{FlagBits: tc.flags}
+			require.NoError(t, msg.SetSections(wire.MakeOpMsgSection(tc.doc)))
+
+			unack, reply := unacknowledged(&wire.MsgHeader{OpCode: wire.OpCodeMsg}, msg)
+			assert.Equal(t, tc.unack, unack)
+			assert.Equal(t, tc.reply, reply)
+		})
+	}
+}
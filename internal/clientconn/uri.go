@@ -0,0 +1,101 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientconn
+
+import (
+	"net"
+	"net/url"
+)
+
+// URIOpts represents options for MongoDB URIs generated by [Endpoint.MongoDBURI].
+type URIOpts struct {
+	// If Username is set, URI contains credentials and authMechanism and authSource parameters.
+	Username string
+	Password string
+
+	// PLAIN (default), SCRAM-SHA-1, or SCRAM-SHA-256.
+	AuthMechanism string
+
+	// Database in the URI path; may be empty.
+	Database string
+
+	// Client's certificate and key file for TLS listeners that verify client certificates.
+	TLSCertificateKeyFile string
+}
+
+// MongoDBURI returns MongoDB URI that clients could use to connect to the endpoint.
+// Empty and unspecified (like 0.0.0.0) TCP hosts are replaced with localhost.
+//
+// For TLS endpoints with CA file, the same file is set as tlsCAFile,
+// assuming that the server certificate is signed by the same CA as client certificates.
+func (e *Endpoint) MongoDBURI(opts *URIOpts) string {
+	if opts == nil {
+		opts = new(URIOpts)
+	}
+
+	host := e.Addr
+
+	if e.Network != "unix" {
+		if h, port, err := net.SplitHostPort(host); err == nil {
+			if ip := net.ParseIP(h); h == "" || (ip != nil && ip.IsUnspecified()) {
+				host = net.JoinHostPort("localhost", port)
+			}
+		}
+	}
+
+	// MongoDB really wants Unix socket path in the host part of the URI
+	u := &url.URL{
+		Scheme: "mongodb",
+		Host:   host,
+		Path:   "/" + opts.Database,
+	}
+
+	q := url.Values{}
+
+	if e.Network == "tls" {
+		q.Set("tls", "true")
+
+		if e.TLSCAFile != "" {
+			q.Set("tlsCAFile", e.TLSCAFile)
+		}
+
+		if opts.TLSCertificateKeyFile != "" {
+			q.Set("tlsCertificateKeyFile", opts.TLSCertificateKeyFile)
+		}
+	}
+
+	if opts.Username != "" {
+		u.User = url.UserPassword(opts.Username, opts.Password)
+
+		mechanism := opts.AuthMechanism
+		if mechanism == "" {
+			mechanism = "PLAIN"
+		}
+
+		q.Set("authMechanism", mechanism)
+
+		// PLAIN credentials are checked by the backend, others are stored in the admin database
+		authSource := "$external"
+		if mechanism != "PLAIN" {
+			authSource = "admin"
+		}
+
+		q.Set("authSource", authSource)
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
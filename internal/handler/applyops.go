@@ -0,0 +1,459 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson2"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// applyOpsReservedDatabases contains databases that can't be written by `applyOps` command.
+var applyOpsReservedDatabases = []string{"admin", "config", "local"}
+
+// checkApplyOpNamespace checks that the oplog entry's namespace is a user collection.
+func checkApplyOpNamespace(command string, op *types.Document) error {
+	ns, err := common.GetRequiredParam[string](op, "ns")
+	if err != nil {
+		return err
+	}
+
+	dbName, cName, err := handlerparams.SplitNamespace(ns, command)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(applyOpsReservedDatabases, dbName) || strings.HasPrefix(cName, "system.") {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			fmt.Sprintf("%s is supported only for user collections, got namespace %s", command, ns),
+			command,
+		)
+	}
+
+	return nil
+}
+
+// applyOp applies a single insert (`i`), update (`u`), or delete (`d`) oplog entry
+// to a user collection.
+//
+// Inserts of existing documents replace them, so already applied entries could be applied again.
+// If alwaysUpsert is true, updates of missing documents insert them.
+func (h *Handler) applyOp(ctx context.Context, command, opType string, op *types.Document, alwaysUpsert bool) error {
+	if err := checkApplyOpNamespace(command, op); err != nil {
+		return err
+	}
+
+	ns := must.NotFail(op.Get("ns")).(string)
+
+	dbName, cName, err := handlerparams.SplitNamespace(ns, command)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	o, err := common.GetRequiredParam[*types.Document](op, "o")
+	if err != nil {
+		return err
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s'", ns)
+			return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(cName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s'", ns)
+			return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return lazyerrors.Error(err)
+	}
+
+	switch opType {
+	case "i":
+		if !o.Has("_id") {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("%s insert operation for %s must contain _id", command, ns),
+				command,
+			)
+		}
+
+		if err = h.checkImplicitCollection(ctx, db, dbName, cName, command); err != nil {
+			return err
+		}
+
+		if c, err = h.withQuota(ctx, db, dbName, cName, c, command); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{o}})
+		if backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
+			_, err = c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{o}})
+		}
+
+	case "u":
+		err = h.applyUpdateOp(ctx, db, c, command, dbName, cName, op, o, alwaysUpsert)
+
+	case "d":
+		id, _ := o.Get("_id")
+		if id == nil {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("%s delete operation for %s must contain _id", command, ns),
+				command,
+			)
+		}
+
+		_, err = c.DeleteAll(ctx, &backends.DeleteAllParams{IDs: []any{id}})
+
+	default:
+		panic(fmt.Sprintf("unexpected operation type %q", opType))
+	}
+
+	if err != nil {
+		var ce *handlererrors.CommandError
+		if errors.As(err, &ce) {
+			return err
+		}
+
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// applyUpdateOp applies the update oplog entry with the given `o` field.
+//
+// The update could be a replacement document, update operators (`$set` and `$unset` in oplogs),
+// or a diff (`$v: 2` format used by MongoDB 5.0+).
+func (h *Handler) applyUpdateOp(ctx context.Context, db backends.Database, c backends.Collection, command, dbName, cName string, op, o *types.Document, alwaysUpsert bool) error { //nolint:lll // for readability
+	o2, err := common.GetRequiredParam[*types.Document](op, "o2")
+	if err != nil {
+		return err
+	}
+
+	id, _ := o2.Get("_id")
+	if id == nil {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("%s update operation for %s.%s must contain o2._id", command, dbName, cName),
+			command,
+		)
+	}
+
+	filter := must.NotFail(types.NewDocument("_id", id))
+
+	var qp backends.QueryParams
+	if !h.disablePushdown.Load() {
+		qp.Filter = filter
+	}
+
+	qr, err := c.Query(ctx, &qp)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	closer.Add(qr.Iter)
+
+	iter := common.LimitIterator(common.FilterIterator(qr.Iter, closer, filter), closer, 1)
+
+	var doc *types.Document
+	var upsert bool
+
+	_, doc, err = iter.Next()
+
+	switch {
+	case err == nil:
+	case errors.Is(err, iterator.ErrIteratorDone):
+		if !alwaysUpsert {
+			return nil
+		}
+
+		doc = must.NotFail(types.NewDocument("_id", id))
+		upsert = true
+	default:
+		return lazyerrors.Error(err)
+	}
+
+	version, _ := o.Get("$v")
+
+	switch {
+	case version != nil && types.Compare(version, int32(2)) == types.Equal:
+		diff, err := common.GetRequiredParam[*types.Document](o, "diff")
+		if err != nil {
+			return err
+		}
+
+		if err = applyOplogDiff(doc, diff, int64(h.MaxBSONObjectSize)); err != nil {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("%s update operation for %s.%s has invalid diff: %s", command, dbName, cName, err),
+				command,
+			)
+		}
+
+		// unlike update operators, diffs are not validated by common.ApplyUpdate
+		if err = h.checkOplogDiffResult(command, dbName, cName, doc, id); err != nil {
+			return err
+		}
+
+	default:
+		update := o.DeepCopy()
+		update.Remove("$v")
+
+		hasOperators, err := common.HasSupportedUpdateModifiers(command, update)
+		if err != nil {
+			return err
+		}
+
+		if hasOperators {
+			if err = common.ValidateUpdateOperators(command, update); err != nil {
+				return err
+			}
+		}
+
+		_, err = common.ApplyUpdate(command, doc, &common.Update{Update: update, HasUpdateOperators: hasOperators})
+		if err != nil {
+			return err
+		}
+
+		if !doc.Has("_id") {
+			doc.Set("_id", id)
+		}
+	}
+
+	if c, err = h.withQuota(ctx, db, dbName, cName, c, command); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if !upsert {
+		_, err = c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{doc}})
+		return err
+	}
+
+	if err = h.checkImplicitCollection(ctx, db, dbName, cName, command); err != nil {
+		return err
+	}
+
+	_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{doc}})
+
+	return err
+}
+
+// checkOplogDiffResult checks that the document produced by the oplog diff
+// has the same _id, is a valid data document, and is not too large.
+func (h *Handler) checkOplogDiffResult(command, dbName, cName string, doc *types.Document, id any) error {
+	if newID, _ := doc.Get("_id"); newID == nil || types.Compare(newID, id) != types.Equal {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrImmutableField,
+			fmt.Sprintf("%s update operation for %s.%s would modify the immutable field '_id'", command, dbName, cName),
+			command,
+		)
+	}
+
+	if err := doc.ValidateData(); err != nil {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("%s update operation for %s.%s produced invalid document: %s", command, dbName, cName, err),
+			command,
+		)
+	}
+
+	d, err := bson2.ConvertDocument(doc)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if size := d.Size(); size > int(h.MaxBSONObjectSize) {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBSONObjectTooLarge,
+			fmt.Sprintf("%s update operation for %s.%s produced document of %d bytes, maximum is %d",
+				command, dbName, cName, size, h.MaxBSONObjectSize),
+			command,
+		)
+	}
+
+	return nil
+}
+
+// applyOplogDiff applies the update diff of `$v: 2` oplog entry to the document.
+//
+// The diff contains deleted (`d`), updated (`u`), and inserted (`i`) fields,
+// and diffs of nested documents and arrays (`s<field>`).
+//
+// Array elements added by the diff are limited by maxLen (the maximum document size),
+// because each of them takes at least 3 bytes (type, key, and its terminating zero),
+// and larger documents could not be stored anyway.
+func applyOplogDiff(doc, diff *types.Document, maxLen int64) error {
+	budget := maxLen / 3
+
+	return applyOplogDocumentDiff(doc, diff, &budget)
+}
+
+// applyOplogDocumentDiff applies the diff of the document.
+// Budget is the remaining number of array elements that could be added.
+func applyOplogDocumentDiff(doc, diff *types.Document, budget *int64) error {
+	for _, k := range diff.Keys() {
+		v := must.NotFail(diff.Get(k))
+
+		switch {
+		case k == "d":
+			fields, ok := v.(*types.Document)
+			if !ok {
+				return fmt.Errorf("field %q must be a document", k)
+			}
+
+			for _, f := range fields.Keys() {
+				doc.Remove(f)
+			}
+
+		case k == "u" || k == "i":
+			fields, ok := v.(*types.Document)
+			if !ok {
+				return fmt.Errorf("field %q must be a document", k)
+			}
+
+			for _, f := range fields.Keys() {
+				doc.Set(f, must.NotFail(fields.Get(f)))
+			}
+
+		case strings.HasPrefix(k, "s") && len(k) > 1:
+			if err := applyOplogSubDiff(doc, k[1:], v, budget); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("unexpected field %q", k)
+		}
+	}
+
+	return nil
+}
+
+// applyOplogSubDiff applies the diff of the nested document or array to the document's field.
+func applyOplogSubDiff(doc *types.Document, field string, v any, budget *int64) error {
+	subDiff, ok := v.(*types.Document)
+	if !ok {
+		return fmt.Errorf("diff of field %q must be a document", field)
+	}
+
+	current, _ := doc.Get(field)
+
+	switch current := current.(type) {
+	case *types.Document:
+		return applyOplogDocumentDiff(current, subDiff, budget)
+
+	case *types.Array:
+		return applyOplogArrayDiff(current, subDiff, budget)
+
+	default:
+		return fmt.Errorf("field %q is not a document or an array", field)
+	}
+}
+
+// applyOplogArrayDiff applies the array diff: new length (`l`),
+// updated elements (`u<index>`), and diffs of nested documents and arrays (`s<index>`).
+func applyOplogArrayDiff(arr *types.Array, diff *types.Document, budget *int64) error {
+	if a, _ := diff.Get("a"); a != true {
+		return fmt.Errorf("array diff must contain 'a: true'")
+	}
+
+	if v, _ := diff.Get("l"); v != nil {
+		l, err := handlerparams.GetWholeNumberParam(v)
+		if err != nil || l < 0 || l-int64(arr.Len()) > *budget {
+			return fmt.Errorf("invalid array length %v", v)
+		}
+
+		for int64(arr.Len()) > l {
+			arr.Remove(arr.Len() - 1)
+		}
+
+		*budget -= max(l-int64(arr.Len()), 0)
+
+		for int64(arr.Len()) < l {
+			arr.Append(types.Null)
+		}
+	}
+
+	for _, k := range diff.Keys() {
+		if k == "a" || k == "l" {
+			continue
+		}
+
+		if len(k) < 2 || (k[0] != 'u' && k[0] != 's') {
+			return fmt.Errorf("unexpected field %q", k)
+		}
+
+		i, err := strconv.Atoi(k[1:])
+		if err != nil || i < 0 || int64(i-arr.Len()) >= *budget {
+			return fmt.Errorf("invalid array index in field %q", k)
+		}
+
+		v := must.NotFail(diff.Get(k))
+
+		if k[0] == 's' {
+			elem, err := arr.Get(i)
+			if err != nil {
+				return fmt.Errorf("invalid array index in field %q", k)
+			}
+
+			d := must.NotFail(types.NewDocument(k[1:], elem))
+			if err = applyOplogSubDiff(d, k[1:], v, budget); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		*budget -= max(int64(i-arr.Len()+1), 0)
+
+		for arr.Len() < i {
+			arr.Append(types.Null)
+		}
+
+		if arr.Len() == i {
+			arr.Append(v)
+			continue
+		}
+
+		must.NoError(arr.Set(i, v))
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestApplyOplogDiff(t *testing.T) {
+	t.Parallel()
+
+	doc := must.NotFail(types.NewDocument(
+		"_id", int32(1),
+		"a", "old",
+		"b", int32(1),
+		"nested", must.NotFail(types.NewDocument("x", int32(1), "y", int32(2))),
+		"arr", must.NotFail(types.NewArray(int32(1), int32(2), int32(3))),
+		"docs", must.NotFail(types.NewArray(must.NotFail(types.NewDocument("v", int32(1))))),
+	))
+
+	diff := must.NotFail(types.NewDocument(
+		"d", must.NotFail(types.NewDocument("b", false)),
+		"u", must.NotFail(types.NewDocument("a", "new")),
+		"i", must.NotFail(types.NewDocument("c", true)),
+		"snested", must.NotFail(types.NewDocument(
+			"d", must.NotFail(types.NewDocument("y", false)),
+		)),
+		"sarr", must.NotFail(types.NewDocument(
+			"a", true,
+			"l", int32(2),
+			"u1", int32(20),
+			"u3", int32(40),
+		)),
+		"sdocs", must.NotFail(types.NewDocument(
+			"a", true,
+			"s0", must.NotFail(types.NewDocument(
+				"u", must.NotFail(types.NewDocument("v", int32(2))),
+			)),
+		)),
+	))
+
+	require.NoError(t, applyOplogDiff(doc, diff, types.MaxDocumentLen))
+
+	expected := must.NotFail(types.NewDocument(
+		"_id", int32(1),
+		"a", "new",
+		"nested", must.NotFail(types.NewDocument("x", int32(1))),
+		"arr", must.NotFail(types.NewArray(int32(1), int32(20), types.Null, int32(40))),
+		"docs", must.NotFail(types.NewArray(must.NotFail(types.NewDocument("v", int32(2))))),
+		"c", true,
+	))
+	assert.Equal(t, types.Equal, types.Compare(expected, doc), "%s", types.FormatAnyValue(doc))
+
+	err := applyOplogDiff(doc, must.NotFail(types.NewDocument("sa", must.NotFail(types.NewDocument()))), types.MaxDocumentLen)
+	assert.EqualError(t, err, `field "a" is not a document or an array`)
+
+	err = applyOplogDiff(doc, must.NotFail(types.NewDocument("x", int32(1))), types.MaxDocumentLen)
+	assert.EqualError(t, err, `unexpected field "x"`)
+}
+
+func TestApplyOplogDiffLimits(t *testing.T) {
+	t.Parallel()
+
+	doc := must.NotFail(types.NewDocument("_id", int32(1), "arr", must.NotFail(types.NewArray(int32(1)))))
+
+	err := applyOplogDiff(doc, must.NotFail(types.NewDocument(
+		"sarr", must.NotFail(types.NewDocument("a", true, "l", int64(1<<40))),
+	)), types.MaxDocumentLen)
+	assert.EqualError(t, err, `invalid array length 1099511627776`)
+
+	err = applyOplogDiff(doc, must.NotFail(types.NewDocument(
+		"sarr", must.NotFail(types.NewDocument("a", true, "u100000000", int32(1))),
+	)), types.MaxDocumentLen)
+	assert.EqualError(t, err, `invalid array index in field "u100000000"`)
+
+	// the limit applies to all arrays of the diff together
+	err = applyOplogDiff(doc, must.NotFail(types.NewDocument(
+		"i", must.NotFail(types.NewDocument("other", must.NotFail(types.NewArray()))),
+		"sarr", must.NotFail(types.NewDocument("a", true, "l", int32(20))),
+		"sother", must.NotFail(types.NewDocument("a", true, "l", int32(20))),
+	)), 90)
+	assert.EqualError(t, err, `invalid array length 20`)
+}
+
+func TestCheckOplogDiffResult(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{NewOpts: &NewOpts{MaxBSONObjectSize: 64}}
+
+	for name, tc := range map[string]struct {
+		doc  *types.Document
+		code handlererrors.ErrorCode // zero if no error is expected
+	}{
+		"Valid": {
+			doc: must.NotFail(types.NewDocument("_id", int32(1), "v", "foo")),
+		},
+		"ChangedID": {
+			doc:  must.NotFail(types.NewDocument("_id", int32(2), "v", "foo")),
+			code: handlererrors.ErrImmutableField,
+		},
+		"DollarKey": {
+			doc:  must.NotFail(types.NewDocument("_id", int32(1), "$v", "foo")),
+			code: handlererrors.ErrBadValue,
+		},
+		"DottedKey": {
+			doc:  must.NotFail(types.NewDocument("_id", int32(1), "v", must.NotFail(types.NewDocument("a.b", "foo")))),
+			code: handlererrors.ErrBadValue,
+		},
+		"TooLarge": {
+			doc:  must.NotFail(types.NewDocument("_id", int32(1), "v", strings.Repeat("x", 64))),
+			code: handlererrors.ErrBSONObjectTooLarge,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := h.checkOplogDiffResult("applyOps", "db", "coll", tc.doc, int32(1))
+			if tc.code == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var cmdErr *handlererrors.CommandError
+			require.ErrorAs(t, err, &cmdErr)
+			assert.Equal(t, tc.code, cmdErr.Code())
+		})
+	}
+}
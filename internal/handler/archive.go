@@ -0,0 +1,587 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// archivePoliciesCollection is the name of the collection in the admin database
+// that stores archive policies.
+const archivePoliciesCollection = "system.archive_policies"
+
+// archivePoliciesReloadInterval is the interval after which cached archive policies are reloaded,
+// so policies set by other FerretDB instances are eventually applied too.
+const archivePoliciesReloadInterval = 10 * time.Second
+
+// archiveCollectionPrefix is the prefix of archive collection names.
+const archiveCollectionPrefix = "system.archive."
+
+// archiveBatchSize is the maximum number of documents moved to the archive collection at once.
+const archiveBatchSize = 1000
+
+// archiveCollectionName returns the name of the archive collection for the given collection.
+func archiveCollectionName(cName string) string {
+	return archiveCollectionPrefix + cName
+}
+
+// archivePolicy represents a policy that moves documents matching the filter
+// and older than the given age to the archive collection.
+type archivePolicy struct {
+	db             string
+	collection     string
+	filter         *types.Document
+	field          types.Path
+	olderThan      time.Duration
+	includeInReads bool
+}
+
+// matches returns true if the document should be moved to the archive collection.
+//
+// Documents without a date in the field are never archived.
+func (p *archivePolicy) matches(doc *types.Document, now time.Time) (bool, error) {
+	v, err := doc.GetByPath(p.field)
+	if err != nil {
+		return false, nil
+	}
+
+	t, ok := v.(time.Time)
+	if !ok || !t.Before(now.Add(-p.olderThan)) {
+		return false, nil
+	}
+
+	return common.FilterDocument(doc, p.filter)
+}
+
+// document returns the policy as stored in the admin database.
+func (p *archivePolicy) document() *types.Document {
+	return must.NotFail(types.NewDocument(
+		"_id", p.db+"."+p.collection,
+		"db", p.db,
+		"collection", p.collection,
+		"filter", p.filter,
+		"field", p.field.String(),
+		"olderThanSeconds", int64(p.olderThan/time.Second),
+		"includeInReads", p.includeInReads,
+	))
+}
+
+// parseArchivePolicy parses and validates the policy document
+// (`setArchivePolicy` command or stored document).
+func parseArchivePolicy(command string, doc *types.Document, dbName, cName string) (*archivePolicy, error) {
+	p := &archivePolicy{
+		db:         dbName,
+		collection: cName,
+		filter:     must.NotFail(types.NewDocument()),
+	}
+
+	if v, _ := doc.Get("filter"); v != nil {
+		filter, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '%s.filter' is the wrong type '%s', expected type 'object'",
+					command, handlerparams.AliasFromType(v),
+				),
+				command,
+			)
+		}
+
+		// check operators before storing
+		if _, err := common.FilterDocument(must.NotFail(types.NewDocument()), filter); err != nil {
+			return nil, err
+		}
+
+		p.filter = filter
+	}
+
+	field, err := common.GetRequiredParam[string](doc, "field")
+	if err != nil {
+		return nil, err
+	}
+
+	if p.field, err = types.NewPathFromString(field); err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Invalid field %q: %s", field, err),
+			command,
+		)
+	}
+
+	v, _ := doc.Get("olderThanSeconds")
+	if v == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			fmt.Sprintf("BSON field '%s.olderThanSeconds' is missing but a required field", command),
+			command,
+		)
+	}
+
+	olderThan, err := handlerparams.GetValidatedNumberParamWithMinValue(command, "olderThanSeconds", v, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	p.olderThan = time.Duration(olderThan) * time.Second
+
+	if v, _ = doc.Get("includeInReads"); v != nil {
+		if p.includeInReads, err = handlerparams.GetBoolOptionalParam("includeInReads", v); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// archivePolicies caches archive policies stored in the admin database.
+type archivePolicies struct {
+	rw       sync.RWMutex
+	loaded   time.Time
+	policies map[string]*archivePolicy // keyed by `db.collection`
+}
+
+// invalidate makes policies to be reloaded on the next use.
+func (ap *archivePolicies) invalidate() {
+	ap.rw.Lock()
+	defer ap.rw.Unlock()
+
+	ap.loaded = time.Time{}
+}
+
+// archivePoliciesCollection returns the backend collection that stores archive policies.
+func (h *Handler) archivePoliciesCollection() (backends.Collection, error) {
+	adminDB, err := h.b.Database("admin")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, err := adminDB.Collection(archivePoliciesCollection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return c, nil
+}
+
+// loadArchivePolicies returns all archive policies keyed by `db.collection`.
+func (h *Handler) loadArchivePolicies(ctx context.Context) (map[string]*archivePolicy, error) {
+	c, err := h.archivePoliciesCollection()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	qr, err := c.Query(ctx, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	res := map[string]*archivePolicy{}
+
+	for {
+		_, doc, err := qr.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		id, _ := doc.Get("_id")
+		dbName, _ := doc.Get("db")
+		cName, _ := doc.Get("collection")
+
+		p, err := parseArchivePolicy("setArchivePolicy", doc, fmt.Sprint(dbName), fmt.Sprint(cName))
+		if err != nil {
+			return nil, lazyerrors.Errorf("invalid archive policy %v: %w", id, err)
+		}
+
+		res[p.db+"."+p.collection] = p
+	}
+
+	return res, nil
+}
+
+// archivePolicy returns the archive policy of the given collection, or nil if there is none.
+func (h *Handler) archivePolicy(ctx context.Context, dbName, cName string) (*archivePolicy, error) {
+	h.archivePolicies.rw.RLock()
+	policies, loaded := h.archivePolicies.policies, h.archivePolicies.loaded
+	h.archivePolicies.rw.RUnlock()
+
+	if time.Since(loaded) > archivePoliciesReloadInterval {
+		var err error
+		if policies, err = h.loadArchivePolicies(ctx); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		h.archivePolicies.rw.Lock()
+		h.archivePolicies.policies, h.archivePolicies.loaded = policies, time.Now()
+		h.archivePolicies.rw.Unlock()
+	}
+
+	return policies[dbName+"."+cName], nil
+}
+
+// removeArchivePolicy removes the archive policy of the given collection.
+// It returns false if there was none.
+//
+// Archived documents are kept.
+func (h *Handler) removeArchivePolicy(ctx context.Context, dbName, cName string) (bool, error) {
+	c, err := h.archivePoliciesCollection()
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	res, err := c.DeleteAll(ctx, &backends.DeleteAllParams{IDs: []any{dbName + "." + cName}})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	h.archivePolicies.invalidate()
+
+	// archived documents are excluded from reads now
+	h.queryCache.Invalidate(dbName, cName)
+
+	return res.Deleted > 0, nil
+}
+
+// dropArchive removes the archive policy of the given collection and drops its archive collection.
+func (h *Handler) dropArchive(ctx context.Context, db backends.Database, dbName, cName string) error {
+	if _, err := h.removeArchivePolicy(ctx, dbName, cName); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	err := db.DropCollection(ctx, &backends.DropCollectionParams{Name: archiveCollectionName(cName)})
+	if err != nil && !backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// removeDatabaseArchivePolicies removes archive policies of all collections in the given database.
+func (h *Handler) removeDatabaseArchivePolicies(ctx context.Context, dbName string) error {
+	policies, err := h.loadArchivePolicies(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	var ids []any
+
+	for id, p := range policies {
+		if p.db == dbName {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	c, err := h.archivePoliciesCollection()
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if _, err = c.DeleteAll(ctx, &backends.DeleteAllParams{IDs: ids}); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	h.archivePolicies.invalidate()
+
+	return nil
+}
+
+// archivedCollection wraps a collection to return documents of its archive collection
+// after its own documents in query results.
+type archivedCollection struct {
+	backends.Collection
+	archive backends.Collection
+}
+
+// Query implements backends.Collection interface.
+func (c *archivedCollection) Query(ctx context.Context, params *backends.QueryParams) (*backends.QueryResult, error) {
+	res, err := c.Collection.Query(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	archiveRes, err := c.archive.Query(ctx, params)
+	if err != nil {
+		res.Iter.Close()
+		return nil, err
+	}
+
+	return &backends.QueryResult{
+		Iter: iterator.Concat(res.Iter, archiveRes.Iter),
+	}, nil
+}
+
+// withArchive returns the collection that also includes archived documents in query results
+// if the collection's archive policy says so; otherwise, it returns the given collection.
+func (h *Handler) withArchive(ctx context.Context, db backends.Database, dbName, cName string, c backends.Collection) (backends.Collection, error) { //nolint:lll // for readability
+	p, err := h.archivePolicy(ctx, dbName, cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if p == nil || !p.includeInReads {
+		return c, nil
+	}
+
+	archive, err := db.Collection(archiveCollectionName(cName))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return &archivedCollection{
+		Collection: c,
+		archive:    archive,
+	}, nil
+}
+
+// runArchiver applies all archive policies according to the given interval.
+func (h *Handler) runArchiver() {
+	if h.ArchiveInterval <= 0 {
+		h.L.Info("Background archiving disabled.")
+		return
+	}
+
+	h.L.Info("Background archiving enabled.", zap.Duration("interval", h.ArchiveInterval))
+
+	ticker := time.NewTicker(h.ArchiveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := h.archiveAll(context.Background()); err != nil {
+				h.L.Error("Failed to archive documents.", zap.Error(err))
+			}
+
+		case <-h.archiverStop:
+			h.L.Info("Background archiving stopped.")
+			return
+		}
+	}
+}
+
+// archiveAll applies all archive policies.
+func (h *Handler) archiveAll(ctx context.Context) error {
+	connInfo := conninfo.New()
+	connInfo.SetBypassBackendAuth()
+	ctx = conninfo.Ctx(ctx, connInfo)
+
+	policies, err := h.loadArchivePolicies(ctx)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	ids := make([]string, 0, len(policies))
+	for id := range policies {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		p := policies[id]
+
+		archived, err := h.archive(ctx, p)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if archived > 0 {
+			h.L.Info("Documents archived.",
+				zap.String("db", p.db), zap.String("collection", p.collection), zap.Int("archived", archived),
+			)
+		}
+	}
+
+	return nil
+}
+
+// archive moves documents matching the policy to the archive collection.
+// It returns the number of moved documents.
+//
+// If the previous run was interrupted, some documents could be present in both collections;
+// they are removed from the main collection.
+func (h *Handler) archive(ctx context.Context, p *archivePolicy) (int, error) {
+	db, err := h.b.Database(p.db)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	cList, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: p.collection})
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+			return 0, nil
+		}
+
+		return 0, lazyerrors.Error(err)
+	}
+
+	// capped collections are rejected by `setArchivePolicy`, but could be created after that
+	if len(cList.Collections) == 0 || cList.Collections[0].Capped() {
+		return 0, nil
+	}
+
+	c, err := db.Collection(p.collection)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	var total int
+
+	for {
+		docs, err := findArchivable(ctx, c, p, time.Now())
+		if err != nil {
+			return total, lazyerrors.Error(err)
+		}
+
+		if len(docs) == 0 {
+			break
+		}
+
+		if err = moveToArchive(ctx, db, c, p, docs); err != nil {
+			return total, lazyerrors.Error(err)
+		}
+
+		total += len(docs)
+		h.archivedDocs.WithLabelValues(p.db, p.collection).Add(float64(len(docs)))
+
+		if len(docs) < archiveBatchSize {
+			break
+		}
+	}
+
+	return total, nil
+}
+
+// findArchivable returns up to archiveBatchSize documents of the collection matching the policy.
+func findArchivable(ctx context.Context, c backends.Collection, p *archivePolicy, now time.Time) ([]*types.Document, error) {
+	qr, err := c.Query(ctx, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	var res []*types.Document
+
+	for len(res) < archiveBatchSize {
+		_, doc, err := qr.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		matches, err := p.matches(doc, now)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if matches {
+			res = append(res, doc)
+		}
+	}
+
+	return res, nil
+}
+
+// moveToArchive inserts documents into the archive collection and deletes them from the main collection.
+func moveToArchive(ctx context.Context, db backends.Database, c backends.Collection, p *archivePolicy, docs []*types.Document) error { //nolint:lll // for readability
+	aName := archiveCollectionName(p.collection)
+
+	err := db.CreateCollection(ctx, &backends.CreateCollectionParams{Name: aName})
+	if err != nil && !backends.ErrorCodeIs(err, backends.ErrorCodeCollectionAlreadyExists) {
+		return lazyerrors.Error(err)
+	}
+
+	archive, err := db.Collection(aName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if _, err = archive.InsertAll(ctx, &backends.InsertAllParams{Docs: docs}); err != nil {
+		if !backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
+			return lazyerrors.Error(err)
+		}
+
+		// some documents were archived by the interrupted run; insert others one by one
+		for _, doc := range docs {
+			_, err = archive.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{doc}})
+			if err != nil && !backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
+				return lazyerrors.Error(err)
+			}
+		}
+	}
+
+	ids := make([]any, len(docs))
+	for i, doc := range docs {
+		ids[i] = must.NotFail(doc.Get("_id"))
+	}
+
+	if _, err = c.DeleteAll(ctx, &backends.DeleteAllParams{IDs: ids}); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// validateArchivedCollectionName returns an error if the archive policy can't be set for the collection.
+func validateArchivedCollectionName(command, cName string) error {
+	switch {
+	case cName == "":
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidNamespace,
+			"Collection name cannot be empty",
+			command,
+		)
+	case strings.HasPrefix(cName, archiveCollectionPrefix):
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidNamespace,
+			fmt.Sprintf("Collection %s is an archive collection", cName),
+			command,
+		)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestParseArchivePolicy(t *testing.T) {
+	t.Parallel()
+
+	doc := must.NotFail(types.NewDocument(
+		"setArchivePolicy", "events",
+		"filter", must.NotFail(types.NewDocument("status", "done")),
+		"field", "meta.createdAt",
+		"olderThanSeconds", int32(60),
+		"includeInReads", true,
+	))
+
+	p, err := parseArchivePolicy("setArchivePolicy", doc, "app", "events")
+	require.NoError(t, err)
+
+	assert.Equal(t, "app", p.db)
+	assert.Equal(t, "events", p.collection)
+	assert.Equal(t, "meta.createdAt", p.field.String())
+	assert.Equal(t, time.Minute, p.olderThan)
+	assert.True(t, p.includeInReads)
+
+	// stored document is parsed back to the same policy
+	stored, err := parseArchivePolicy("setArchivePolicy", p.document(), "app", "events")
+	require.NoError(t, err)
+	assert.Equal(t, p, stored)
+
+	now := time.Now()
+
+	for name, tc := range map[string]struct {
+		doc      *types.Document
+		expected bool
+	}{
+		"Old": {
+			doc: must.NotFail(types.NewDocument(
+				"status", "done", "meta", must.NotFail(types.NewDocument("createdAt", now.Add(-time.Hour))),
+			)),
+			expected: true,
+		},
+		"Recent": {
+			doc: must.NotFail(types.NewDocument(
+				"status", "done", "meta", must.NotFail(types.NewDocument("createdAt", now)),
+			)),
+		},
+		"FilterMismatch": {
+			doc: must.NotFail(types.NewDocument(
+				"status", "new", "meta", must.NotFail(types.NewDocument("createdAt", now.Add(-time.Hour))),
+			)),
+		},
+		"NotDate": {
+			doc: must.NotFail(types.NewDocument(
+				"status", "done", "meta", must.NotFail(types.NewDocument("createdAt", int64(0))),
+			)),
+		},
+		"NoField": {
+			doc: must.NotFail(types.NewDocument("status", "done")),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := p.matches(tc.doc, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+
+	for name, doc := range map[string]*types.Document{
+		"NoField":     must.NotFail(types.NewDocument("olderThanSeconds", int32(1))),
+		"EmptyField":  must.NotFail(types.NewDocument("field", "", "olderThanSeconds", int32(1))),
+		"NoAge":       must.NotFail(types.NewDocument("field", "createdAt")),
+		"NegativeAge": must.NotFail(types.NewDocument("field", "createdAt", "olderThanSeconds", int32(-1))),
+		"BadFilter":   must.NotFail(types.NewDocument("filter", "x", "field", "createdAt", "olderThanSeconds", int32(1))),
+		"BadOperator": must.NotFail(types.NewDocument(
+			"filter", must.NotFail(types.NewDocument("$foo", int32(1))), "field", "createdAt", "olderThanSeconds", int32(1),
+		)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseArchivePolicy("setArchivePolicy", doc, "app", "events")
+			assert.Error(t, err)
+		})
+	}
+}
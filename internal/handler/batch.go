@@ -0,0 +1,116 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/FerretDB/FerretDB/internal/bson2"
+	"github.com/FerretDB/FerretDB/internal/clientconn/cursor"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// consumeBatch returns up to batchSize next documents from the cursor.
+// The returned done is true if the cursor is exhausted; it is closed in that case.
+// The cursor is also closed on any error.
+//
+// Documents prefetched by [Handler.prefetchBatch] are returned first.
+func (h *Handler) consumeBatch(c *cursor.Cursor, batchSize int64) ([]*types.Document, bool, error) {
+	prefetched, _, err := c.Prefetched()
+	if err != nil {
+		c.Close()
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	c.Unread(prefetched...)
+
+	docs, done, err := h.fetchBatch(c, batchSize)
+	if err != nil || done {
+		c.Close()
+	}
+
+	if err != nil {
+		return nil, false, lazyerrors.Error(err)
+	}
+
+	return docs, done, nil
+}
+
+// fetchBatch fetches up to batchSize documents from the cursor one by one without closing it.
+// The returned done is true if the cursor is exhausted.
+//
+// Like in MongoDB, the total size of the batch is limited by the maximum BSON object size,
+// so only the current batch is kept in memory regardless of batchSize and the result set size.
+// The first document that does not fit is left in the cursor for the next batch.
+func (h *Handler) fetchBatch(c *cursor.Cursor, batchSize int64) ([]*types.Document, bool, error) {
+	var res []*types.Document
+	var size int
+
+	for int64(len(res)) < batchSize {
+		_, doc, err := c.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				return res, true, nil
+			}
+
+			return nil, false, lazyerrors.Error(err)
+		}
+
+		d, err := bson2.ConvertDocument(doc)
+		if err != nil {
+			return nil, false, lazyerrors.Error(err)
+		}
+
+		// type byte, array index, and NUL
+		size += 1 + len(strconv.Itoa(len(res))) + 1 + d.Size()
+
+		if len(res) > 0 && size > int(h.MaxBSONObjectSize) {
+			c.Unread(doc)
+			break
+		}
+
+		res = append(res, doc)
+	}
+
+	return res, false, nil
+}
+
+// prefetchBatch starts fetching the next batch of the normal cursor in the background,
+// so the next getMore does not wait for the backend while the client processes the current batch.
+//
+// Every prefetched batch takes up to the maximum BSON object size;
+// nothing is prefetched if the total size would exceed CursorPrefetchMemory.
+func (h *Handler) prefetchBatch(c *cursor.Cursor, batchSize int64) {
+	if c.Type != cursor.Normal || h.CursorPrefetchMemory <= 0 {
+		return
+	}
+
+	size := int64(h.MaxBSONObjectSize)
+	if h.prefetchMemory.Add(size) > h.CursorPrefetchMemory {
+		h.prefetchMemory.Add(-size)
+		return
+	}
+
+	c.Prefetch(
+		func() ([]*types.Document, error) {
+			docs, _, err := h.fetchBatch(c, batchSize)
+			return docs, err
+		},
+		func() { h.prefetchMemory.Add(-size) },
+	)
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"sync"
+)
+
+// bulkWrite calls f for each of n write statements or sub-batches concurrently,
+// using up to BulkWriteConcurrency goroutines.
+// It is used for unordered writes only.
+//
+// The first error returned by f cancels the context passed to other calls,
+// prevents new calls, and is returned.
+func (h *Handler) bulkWrite(ctx context.Context, n int, f func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	sem := make(chan struct{}, h.BulkWriteConcurrency)
+
+	var wg sync.WaitGroup
+
+loop:
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break loop
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			if err := f(ctx, i); err != nil {
+				cancel(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return context.Cause(ctx)
+}
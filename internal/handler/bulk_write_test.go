@@ -0,0 +1,222 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+func TestBulkWrite(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{NewOpts: &NewOpts{BulkWriteConcurrency: 4}}
+
+	t.Run("All", func(t *testing.T) {
+		t.Parallel()
+
+		var running, maxRunning, calls atomic.Int32
+
+		err := h.bulkWrite(testutil.Ctx(t), 100, func(context.Context, int) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+
+			calls.Add(1)
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(100), calls.Load())
+		assert.LessOrEqual(t, maxRunning.Load(), int32(4))
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		expected := errors.New("write failed")
+
+		var calls atomic.Int32
+
+		err := h.bulkWrite(testutil.Ctx(t), 100, func(ctx context.Context, i int) error {
+			calls.Add(1)
+
+			if i == 0 {
+				return expected
+			}
+
+			<-ctx.Done()
+
+			return context.Cause(ctx)
+		})
+		require.ErrorIs(t, err, expected)
+
+		// no new calls after the error
+		assert.Less(t, calls.Load(), int32(100))
+	})
+}
+
+func TestConcurrentUpdates(t *testing.T) {
+	t.Parallel()
+
+	byID := func(id any) common.Update {
+		return common.Update{Filter: must.NotFail(types.NewDocument("_id", id))}
+	}
+
+	for name, tc := range map[string]struct {
+		updates  []common.Update
+		expected bool
+	}{
+		"Distinct": {
+			updates:  []common.Update{byID("a"), byID(int32(1)), byID(int64(2)), byID(types.NewObjectID())},
+			expected: true,
+		},
+		"Single": {
+			updates: []common.Update{byID("a")},
+		},
+		"Same": {
+			updates: []common.Update{byID("a"), byID("b"), byID("a")},
+		},
+		"SameNumber": {
+			updates: []common.Update{byID(int32(1)), byID(int64(1))},
+		},
+		"UnsupportedID": {
+			updates: []common.Update{byID("a"), byID(42.0)},
+		},
+		"OtherFilter": {
+			updates: []common.Update{byID("a"), {Filter: must.NotFail(types.NewDocument("v", "b"))}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, concurrentUpdates(tc.updates))
+		})
+	}
+}
+
+func TestUpdateWriteErrors(t *testing.T) {
+	t.Parallel()
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := sqlite.NewBackend(&sqlite.NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	// statements are executed one by one
+	h := &Handler{NewOpts: &NewOpts{L: testutil.Logger(t), BulkWriteConcurrency: 1}, b: b}
+
+	for name, tc := range map[string]struct {
+		ordered  bool
+		expected []any // values of v after the update
+	}{
+		"Ordered": {
+			ordered:  true,
+			expected: []any{int32(2), "x", int32(3)},
+		},
+		"Unordered": {
+			expected: []any{int32(2), "x", int32(4)},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := testutil.Ctx(t)
+			dbName, cName := testutil.DatabaseName(t), testutil.CollectionName(t)
+
+			c, err := must.NotFail(b.Database(dbName)).Collection(cName)
+			require.NoError(t, err)
+
+			_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+				must.NotFail(types.NewDocument("_id", int32(1), "v", int32(1))),
+				must.NotFail(types.NewDocument("_id", int32(2), "v", "x")),
+				must.NotFail(types.NewDocument("_id", int32(3), "v", int32(3))),
+			}})
+			require.NoError(t, err)
+
+			updates := types.MakeArray(3)
+			for id := range int32(3) {
+				updates.Append(must.NotFail(types.NewDocument(
+					"q", must.NotFail(types.NewDocument("_id", id+1)),
+					"u", must.NotFail(types.NewDocument("$inc", must.NotFail(types.NewDocument("v", int32(1))))),
+				)))
+			}
+
+			var msg wire.OpMsg
+			require.NoError(t, msg.SetSections(wire.MakeOpMsgSection(must.NotFail(types.NewDocument(
+				"update", cName,
+				"updates", updates,
+				"ordered", tc.ordered,
+				"$db", dbName,
+			)))))
+
+			reply, err := h.MsgUpdate(ctx, &msg)
+
+			if tc.ordered {
+				var we *handlererrors.WriteErrors
+				require.ErrorAs(t, err, &we)
+			} else {
+				require.NoError(t, err)
+
+				res := must.NotFail(reply.Document())
+				assert.Equal(t, int32(2), must.NotFail(res.Get("n")))
+				assert.Equal(t, int32(2), must.NotFail(res.Get("nModified")))
+
+				writeErrors := must.NotFail(res.Get("writeErrors")).(*types.Array)
+				require.Equal(t, 1, writeErrors.Len())
+
+				we := must.NotFail(writeErrors.Get(0)).(*types.Document)
+				assert.Equal(t, int32(1), must.NotFail(we.Get("index")))
+				assert.Equal(t, int32(handlererrors.ErrTypeMismatch), must.NotFail(we.Get("code")))
+			}
+
+			qr, err := c.Query(ctx, nil)
+			require.NoError(t, err)
+
+			docs, err := iterator.ConsumeValues(qr.Iter)
+			require.NoError(t, err)
+			require.Len(t, docs, len(tc.expected))
+
+			for i, doc := range docs {
+				assert.Equal(t, tc.expected[i], must.NotFail(doc.Get("v")), "%d", i)
+			}
+		})
+	}
+}
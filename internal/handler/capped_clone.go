@@ -0,0 +1,202 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson2"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// cappedCloneBatchSize is the maximum number of documents inserted into the capped collection at once.
+const cappedCloneBatchSize = 1000
+
+// getCappedCloneSize returns the validated `size` parameter of
+// `convertToCapped` and `cloneCollectionAsCapped` commands.
+func getCappedCloneSize(document *types.Document) (int64, error) {
+	command := document.Command()
+
+	v, _ := document.Get("size")
+	if _, ok := v.(types.NullType); v == nil || ok {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			fmt.Sprintf("BSON field '%s.size' is missing but a required field", command),
+			command,
+		)
+	}
+
+	return handlerparams.GetValidatedNumberParamWithMinValue(command, "size", v, 1)
+}
+
+// getCappedCloneSource returns information about the source collection of
+// `convertToCapped` and `cloneCollectionAsCapped` commands.
+func getCappedCloneSource(ctx context.Context, db backends.Database, command, dbName, cName string) (*backends.CollectionInfo, error) { //nolint:lll // for readability
+	cList, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: cName})
+	if err != nil && !backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if cList == nil || len(cList.Collections) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNamespaceNotFound,
+			fmt.Sprintf("source collection %s.%s does not exist", dbName, cName),
+			command,
+		)
+	}
+
+	src := cList.Collections[0]
+
+	if src.TimeSeries != nil || src.Clustered {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"Time-series and clustered collections cannot be capped",
+			command,
+		)
+	}
+
+	return &src, nil
+}
+
+// cloneAsCapped creates a new capped collection of the given size and copies documents of the source collection to it.
+//
+// Documents are copied in natural order.
+// If they do not fit, the oldest documents are evicted, as if they were inserted into the capped collection one by one.
+// The validator and the storage codec of the source collection are kept; indexes other than the default one are not copied.
+// Writes to the source collection made during cloning may be missed.
+func cloneAsCapped(ctx context.Context, db backends.Database, src *backends.CollectionInfo, dst string, size int64) error {
+	err := db.CreateCollection(ctx, &backends.CreateCollectionParams{
+		Name:             dst,
+		CappedSize:       size,
+		Validator:        src.Validator,
+		ValidationLevel:  src.ValidationLevel,
+		ValidationAction: src.ValidationAction,
+		Codec:            src.Codec,
+		CodecFields:      src.CodecFields,
+	})
+	if err != nil {
+		return err
+	}
+
+	sc, err := db.Collection(src.Name)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	dc, err := db.Collection(dst)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	qr, err := sc.Query(ctx, &backends.QueryParams{
+		Sort: must.NotFail(types.NewDocument("$natural", int64(1))),
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	// sizes of copied documents in insertion order
+	var sizes []int64
+	var total int64
+
+	batch := make([]*types.Document, 0, cappedCloneBatchSize)
+
+	for {
+		_, doc, err := qr.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		d, err := bson2.ConvertDocument(doc)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		sizes = append(sizes, int64(d.Size()))
+		total += int64(d.Size())
+
+		if batch = append(batch, doc); len(batch) < cappedCloneBatchSize {
+			continue
+		}
+
+		if _, err = dc.InsertAll(ctx, &backends.InsertAllParams{Docs: batch}); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		batch = make([]*types.Document, 0, cappedCloneBatchSize)
+	}
+
+	if len(batch) > 0 {
+		if _, err = dc.InsertAll(ctx, &backends.InsertAllParams{Docs: batch}); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	var evict int64
+	for total > size {
+		total -= sizes[evict]
+		evict++
+	}
+
+	if evict == 0 {
+		return nil
+	}
+
+	res, err := dc.Query(ctx, &backends.QueryParams{
+		Sort:          must.NotFail(types.NewDocument("$natural", int64(1))),
+		Limit:         evict,
+		OnlyRecordIDs: true,
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	defer res.Iter.Close()
+
+	recordIDs := make([]int64, 0, evict)
+
+	for {
+		_, doc, err := res.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		recordIDs = append(recordIDs, doc.RecordID())
+	}
+
+	if _, err = dc.DeleteAll(ctx, &backends.DeleteAllParams{RecordIDs: recordIDs}); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
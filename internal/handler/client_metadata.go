@@ -0,0 +1,130 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// clientMetadataDocument returns the client metadata document in the same shape as drivers send it.
+// Empty values are omitted.
+func clientMetadataDocument(md *conninfo.ClientMetadata) *types.Document {
+	res := new(types.Document)
+
+	for _, sub := range []struct {
+		key    string
+		fields [][2]string
+	}{
+		{"driver", [][2]string{{"name", md.DriverName}, {"version", md.DriverVersion}}},
+		{"application", [][2]string{{"name", md.AppName}}},
+		{"os", [][2]string{
+			{"type", md.OSType},
+			{"name", md.OSName},
+			{"architecture", md.OSArchitecture},
+			{"version", md.OSVersion},
+		}},
+	} {
+		doc := new(types.Document)
+
+		for _, f := range sub.fields {
+			if f[1] != "" {
+				doc.Set(f[0], f[1])
+			}
+		}
+
+		if doc.Len() > 0 {
+			res.Set(sub.key, doc)
+		}
+	}
+
+	if md.Platform != "" {
+		res.Set("platform", md.Platform)
+	}
+
+	return res
+}
+
+// clientVersion represents connections made by a single driver version.
+type clientVersion struct {
+	driverName    string
+	driverVersion string
+	connections   int32
+	appNames      []string
+}
+
+// clientVersions returns an array of documents describing distinct driver names and versions of given connections,
+// sorted by them, and the number of connections that did not send client metadata.
+func clientVersions(conns []*conninfo.Conn) (*types.Array, int32) {
+	var versions []*clientVersion
+	var unknown int32
+
+	for _, c := range conns {
+		md := c.ConnInfo.ClientMetadata()
+		if md == nil {
+			unknown++
+			continue
+		}
+
+		i := slices.IndexFunc(versions, func(v *clientVersion) bool {
+			return v.driverName == md.DriverName && v.driverVersion == md.DriverVersion
+		})
+
+		if i < 0 {
+			i = len(versions)
+			versions = append(versions, &clientVersion{
+				driverName:    md.DriverName,
+				driverVersion: md.DriverVersion,
+			})
+		}
+
+		v := versions[i]
+		v.connections++
+
+		if md.AppName != "" && !slices.Contains(v.appNames, md.AppName) {
+			v.appNames = append(v.appNames, md.AppName)
+		}
+	}
+
+	slices.SortFunc(versions, func(a, b *clientVersion) int {
+		return cmp.Or(cmp.Compare(a.driverName, b.driverName), cmp.Compare(a.driverVersion, b.driverVersion))
+	})
+
+	res := types.MakeArray(len(versions))
+
+	for _, v := range versions {
+		slices.Sort(v.appNames)
+
+		appNames := types.MakeArray(len(v.appNames))
+		for _, name := range v.appNames {
+			appNames.Append(name)
+		}
+
+		res.Append(must.NotFail(types.NewDocument(
+			"driver", must.NotFail(types.NewDocument(
+				"name", v.driverName,
+				"version", v.driverVersion,
+			)),
+			"connections", v.connections,
+			"appNames", appNames,
+		)))
+	}
+
+	return res, unknown
+}
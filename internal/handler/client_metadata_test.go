@@ -0,0 +1,88 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestClientVersions(t *testing.T) {
+	t.Parallel()
+
+	r := conninfo.NewRegistry()
+
+	for _, md := range []*conninfo.ClientMetadata{
+		{DriverName: "nodejs|mongosh", DriverVersion: "6.3.0|2.1.1", AppName: "mongosh 2.1.1"},
+		{DriverName: "mongo-go-driver", DriverVersion: "v1.12.1", AppName: "worker"},
+		{DriverName: "mongo-go-driver", DriverVersion: "v1.12.1", AppName: "api"},
+		{DriverName: "mongo-go-driver", DriverVersion: "v1.12.1"},
+		{DriverName: "mongo-go-driver", DriverVersion: "v1.11.0", AppName: "api"},
+		nil,
+	} {
+		connInfo := conninfo.New()
+		if md != nil {
+			connInfo.SetClientMetadata(md)
+		}
+
+		r.Add(connInfo)
+	}
+
+	actual, unknown := clientVersions(r.Conns())
+	assert.Equal(t, int32(1), unknown)
+
+	expected := must.NotFail(types.NewArray(
+		must.NotFail(types.NewDocument(
+			"driver", must.NotFail(types.NewDocument("name", "mongo-go-driver", "version", "v1.11.0")),
+			"connections", int32(1),
+			"appNames", must.NotFail(types.NewArray("api")),
+		)),
+		must.NotFail(types.NewDocument(
+			"driver", must.NotFail(types.NewDocument("name", "mongo-go-driver", "version", "v1.12.1")),
+			"connections", int32(3),
+			"appNames", must.NotFail(types.NewArray("api", "worker")),
+		)),
+		must.NotFail(types.NewDocument(
+			"driver", must.NotFail(types.NewDocument("name", "nodejs|mongosh", "version", "6.3.0|2.1.1")),
+			"connections", int32(1),
+			"appNames", must.NotFail(types.NewArray("mongosh 2.1.1")),
+		)),
+	))
+	testutil.AssertEqual(t, expected, actual)
+}
+
+func TestClientMetadataDocument(t *testing.T) {
+	t.Parallel()
+
+	actual := clientMetadataDocument(&conninfo.ClientMetadata{
+		DriverName:    "mongo-go-driver",
+		DriverVersion: "v1.12.1",
+		OSType:        "linux",
+		Platform:      "go1.21.5",
+	})
+
+	expected := must.NotFail(types.NewDocument(
+		"driver", must.NotFail(types.NewDocument("name", "mongo-go-driver", "version", "v1.12.1")),
+		"os", must.NotFail(types.NewDocument("type", "linux")),
+		"platform", "go1.21.5",
+	))
+	testutil.AssertEqual(t, expected, actual)
+}
@@ -32,7 +32,7 @@ func (h *Handler) CmdQuery(ctx context.Context, query *wire.OpQuery) (*wire.OpRe
 	collection := query.FullCollectionName
 
 	if (cmd == "ismaster" || cmd == "isMaster") && strings.HasSuffix(collection, ".$cmd") {
-		return common.IsMaster(ctx, query.Query(), h.TCPHost, h.ReplSetName)
+		return common.IsMaster(ctx, query.Query(), h.TCPHost, h.ReplSetName, h.MaxBSONObjectSize, h.MaxMessageSize)
 	}
 
 	// TODO https://github.com/FerretDB/FerretDB/issues/3008
@@ -0,0 +1,293 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// collectionTemplatesCollection is the name of the collection in the admin database
+// that stores collection templates.
+const collectionTemplatesCollection = "system.collection_templates"
+
+// collectionTemplatesReloadInterval is the interval after which cached collection templates are reloaded,
+// so templates created by other FerretDB instances are eventually applied too.
+const collectionTemplatesReloadInterval = 10 * time.Second
+
+// collectionTemplate represents a named template that new collections
+// with namespaces matching the pattern inherit options and indexes from.
+type collectionTemplate struct {
+	name            string
+	pattern         string // path.Match pattern for `db.collection` namespace
+	indexes         []backends.IndexInfo
+	cappedSize      int64
+	cappedDocuments int64
+}
+
+// matches returns true if the template should be applied to the given collection.
+func (t *collectionTemplate) matches(dbName, cName string) bool {
+	ok, _ := path.Match(t.pattern, dbName+"."+cName)
+	return ok
+}
+
+// parseCollectionTemplate parses and validates the template document
+// (`createCollectionTemplate` command or stored document).
+func parseCollectionTemplate(command string, doc *types.Document, name string) (*collectionTemplate, error) {
+	// validators are not supported by the `create` command either
+	if err := common.Unimplemented(doc, "validator", "validationLevel", "validationAction"); err != nil {
+		return nil, err
+	}
+
+	t := &collectionTemplate{
+		name: name,
+	}
+
+	var err error
+
+	if t.pattern, err = common.GetRequiredParam[string](doc, "pattern"); err != nil {
+		return nil, err
+	}
+
+	if _, err = path.Match(t.pattern, ""); err != nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Invalid pattern %q: %s", t.pattern, err),
+			command,
+		)
+	}
+
+	if v, _ := doc.Get("indexes"); v != nil {
+		arr, ok := v.(*types.Array)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '%s.indexes' is the wrong type '%s', expected type 'array'",
+					command, handlerparams.AliasFromType(v),
+				),
+				command,
+			)
+		}
+
+		if t.indexes, err = processIndexesArray(command, arr); err != nil {
+			return nil, err
+		}
+
+		if t.indexes, err = validateIndexesForCreation(command, nil, t.indexes); err != nil {
+			return nil, err
+		}
+
+		// _id index is always created with the collection
+		t.indexes = slices.DeleteFunc(t.indexes, func(idx backends.IndexInfo) bool {
+			return idx.Name == backends.DefaultIndexName
+		})
+	}
+
+	var capped bool
+	if v, _ := doc.Get("capped"); v != nil {
+		if capped, err = handlerparams.GetBoolOptionalParam("capped", v); err != nil {
+			return nil, err
+		}
+	}
+
+	if capped {
+		size, _ := doc.Get("size")
+		if _, ok := size.(types.NullType); size == nil || ok {
+			msg := "the 'size' field is required when 'capped' is true"
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, command)
+		}
+
+		if t.cappedSize, err = handlerparams.GetValidatedNumberParamWithMinValue(command, "size", size, 1); err != nil {
+			return nil, err
+		}
+
+		if max, _ := doc.Get("max"); max != nil {
+			if t.cappedDocuments, err = handlerparams.GetValidatedNumberParamWithMinValue(command, "max", max, 0); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// collectionTemplates caches collection templates stored in the admin database.
+type collectionTemplates struct {
+	rw        sync.RWMutex
+	loaded    time.Time
+	templates []*collectionTemplate // sorted by name
+}
+
+// invalidate makes templates to be reloaded on the next use.
+func (ct *collectionTemplates) invalidate() {
+	ct.rw.Lock()
+	defer ct.rw.Unlock()
+
+	ct.loaded = time.Time{}
+}
+
+// collectionTemplatesCollection returns the backend collection that stores collection templates.
+func (h *Handler) collectionTemplatesCollection() (backends.Collection, error) {
+	adminDB, err := h.b.Database("admin")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, err := adminDB.Collection(collectionTemplatesCollection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return c, nil
+}
+
+// loadCollectionTemplates returns all collection templates sorted by name.
+func (h *Handler) loadCollectionTemplates(ctx context.Context) ([]*collectionTemplate, error) {
+	c, err := h.collectionTemplatesCollection()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	qr, err := c.Query(ctx, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	var res []*collectionTemplate
+
+	for {
+		_, doc, err := qr.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		name, _ := doc.Get("_id")
+
+		t, err := parseCollectionTemplate("createCollectionTemplate", doc, fmt.Sprint(name))
+		if err != nil {
+			return nil, lazyerrors.Errorf("invalid collection template %v: %w", name, err)
+		}
+
+		res = append(res, t)
+	}
+
+	slices.SortFunc(res, func(a, b *collectionTemplate) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	return res, nil
+}
+
+// collectionTemplate returns the first (by name) collection template that matches the given collection,
+// or nil if there is none.
+func (h *Handler) collectionTemplate(ctx context.Context, dbName, cName string) (*collectionTemplate, error) {
+	h.templates.rw.RLock()
+	templates, loaded := h.templates.templates, h.templates.loaded
+	h.templates.rw.RUnlock()
+
+	if time.Since(loaded) > collectionTemplatesReloadInterval {
+		var err error
+		if templates, err = h.loadCollectionTemplates(ctx); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		h.templates.rw.Lock()
+		h.templates.templates, h.templates.loaded = templates, time.Now()
+		h.templates.rw.Unlock()
+	}
+
+	for _, t := range templates {
+		if t.matches(dbName, cName) {
+			return t, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// createFromCollectionTemplate creates a collection with options and indexes of the named template.
+func createFromCollectionTemplate(ctx context.Context, db backends.Database, t *collectionTemplate, cName string) error {
+	err := db.CreateCollection(ctx, &backends.CreateCollectionParams{
+		Name:            cName,
+		CappedSize:      t.cappedSize,
+		CappedDocuments: t.cappedDocuments,
+	})
+
+	switch {
+	case err == nil:
+		// nothing
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionAlreadyExists):
+		// created concurrently; do not create indexes again
+		return nil
+	default:
+		return lazyerrors.Error(err)
+	}
+
+	return createTemplateIndexes(ctx, db, t, cName)
+}
+
+// createTemplateIndexes creates indexes of the named template for the just created collection.
+func createTemplateIndexes(ctx context.Context, db backends.Database, t *collectionTemplate, cName string) error {
+	if len(t.indexes) == 0 {
+		return nil
+	}
+
+	c, err := db.Collection(cName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if _, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: t.indexes}); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// collectionTemplateDocument returns a document representation of the stored template
+// for `listCollectionTemplates` command.
+func collectionTemplateDocument(doc *types.Document) *types.Document {
+	res := must.NotFail(types.NewDocument("name", must.NotFail(doc.Get("_id"))))
+
+	for _, k := range doc.Keys() {
+		if k != "_id" {
+			res.Set(k, must.NotFail(doc.Get(k)))
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,84 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestParseCollectionTemplate(t *testing.T) {
+	t.Parallel()
+
+	doc := must.NotFail(types.NewDocument(
+		"pattern", "*.events_*",
+		"indexes", must.NotFail(types.NewArray(
+			must.NotFail(types.NewDocument(
+				"key", must.NotFail(types.NewDocument("_id", int32(1))),
+				"name", "_id_",
+			)),
+			must.NotFail(types.NewDocument(
+				"key", must.NotFail(types.NewDocument("createdAt", int32(-1))),
+				"name", "createdAt_-1",
+				"unique", true,
+			)),
+		)),
+		"capped", true,
+		"size", int32(1024),
+		"max", int64(10),
+	))
+
+	tmpl, err := parseCollectionTemplate("createCollectionTemplate", doc, "events")
+	require.NoError(t, err)
+
+	expected := &collectionTemplate{
+		name:    "events",
+		pattern: "*.events_*",
+		indexes: []backends.IndexInfo{{
+			Name:   "createdAt_-1",
+			Key:    []backends.IndexKeyPair{{Field: "createdAt", Descending: true}},
+			Unique: true,
+		}},
+		cappedSize:      1024,
+		cappedDocuments: 10,
+	}
+	assert.Equal(t, expected, tmpl)
+
+	assert.True(t, tmpl.matches("app", "events_2024"))
+	assert.False(t, tmpl.matches("app", "users"))
+	assert.False(t, tmpl.matches("app", "old_events_2024"))
+
+	for name, doc := range map[string]*types.Document{
+		"NoPattern":  must.NotFail(types.NewDocument()),
+		"BadPattern": must.NotFail(types.NewDocument("pattern", "[")),
+		"Validator": must.NotFail(types.NewDocument(
+			"pattern", "*", "validator", must.NotFail(types.NewDocument()),
+		)),
+		"NoSize": must.NotFail(types.NewDocument("pattern", "*", "capped", true)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseCollectionTemplate("createCollectionTemplate", doc, name)
+			require.Error(t, err)
+		})
+	}
+}
@@ -40,6 +40,14 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgAggregate,
 			Help:    "Returns aggregated data.",
 		},
+		"applyOps": {
+			Handler: h.MsgApplyOps,
+			Help:    "Applies oplog entries; only no-op entries are supported.",
+		},
+		"archive": {
+			Handler: h.MsgArchive,
+			Help:    "Moves documents matching the archive policy of the collection to its archive collection.",
+		},
 		"buildInfo": {
 			Handler: h.MsgBuildInfo,
 			Help:    "Returns a summary of the build information.",
@@ -48,6 +56,10 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgBuildInfo,
 			Help:    "", // hidden
 		},
+		"cloneCollectionAsCapped": {
+			Handler: h.MsgCloneCollectionAsCapped,
+			Help:    "Creates a new capped collection from an existing collection.",
+		},
 		"collMod": {
 			Handler: h.MsgCollMod,
 			Help:    "Adds options to a collection or modify view definitions.",
@@ -65,6 +77,10 @@ func (h *Handler) initCommands() {
 			Help: "Returns information about the current connection, " +
 				"specifically the state of authenticated users and their available permissions.",
 		},
+		"convertToCapped": {
+			Handler: h.MsgConvertToCapped,
+			Help:    "Converts an existing collection to a capped collection.",
+		},
 		"count": {
 			Handler: h.MsgCount,
 			Help:    "Returns the count of documents that's matched by the query.",
@@ -73,10 +89,18 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgCreate,
 			Help:    "Creates the collection.",
 		},
+		"createCollectionTemplate": {
+			Handler: h.MsgCreateCollectionTemplate,
+			Help:    "Creates a named template for new collections.",
+		},
 		"createIndexes": {
 			Handler: h.MsgCreateIndexes,
 			Help:    "Creates indexes on a collection.",
 		},
+		"createSearchIndexes": {
+			Handler: h.MsgCreateSearchIndexes,
+			Help:    "Creates full-text search indexes on a collection.",
+		},
 		"currentOp": {
 			Handler: h.MsgCurrentOp,
 			Help:    "Returns information about operations currently in progress.",
@@ -85,6 +109,10 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgDataSize,
 			Help:    "Returns the size of the collection in bytes.",
 		},
+		"dbCheck": {
+			Handler: h.MsgDBCheck,
+			Help:    "Checks consistency of collections in the background; results are stored in local.system.healthlog.",
+		},
 		"dbStats": {
 			Handler: h.MsgDBStats,
 			Help:    "Returns the statistics of the database.",
@@ -101,6 +129,10 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgDelete,
 			Help:    "Deletes documents matched by the query.",
 		},
+		"discoverSchema": {
+			Handler: h.MsgDiscoverSchema,
+			Help:    "Returns field paths and their types inferred from sampled documents of the collection.",
+		},
 		"distinct": {
 			Handler: h.MsgDistinct,
 			Help:    "Returns an array of distinct values for the given field.",
@@ -109,6 +141,10 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgDrop,
 			Help:    "Drops the collection.",
 		},
+		"dropCollectionTemplate": {
+			Handler: h.MsgDropCollectionTemplate,
+			Help:    "Drops a named template for new collections.",
+		},
 		"dropDatabase": {
 			Handler: h.MsgDropDatabase,
 			Help:    "Drops production database.",
@@ -117,6 +153,10 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgDropIndexes,
 			Help:    "Drops indexes on a collection.",
 		},
+		"dropSearchIndex": {
+			Handler: h.MsgDropSearchIndex,
+			Help:    "Drops a full-text search index on a collection.",
+		},
 		"explain": {
 			Handler: h.MsgExplain,
 			Help:    "Returns the execution plan.",
@@ -133,6 +173,14 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgFindAndModify,
 			Help:    "", // hidden
 		},
+		"fsync": {
+			Handler: h.MsgFsync,
+			Help:    "Flushes all pending writes to the storage and optionally locks against writes.",
+		},
+		"fsyncUnlock": {
+			Handler: h.MsgFsyncUnlock,
+			Help:    "Unlocks writes locked by fsync.",
+		},
 		"getCmdLineOpts": {
 			Handler: h.MsgGetCmdLineOpts,
 			Help:    "Returns a summary of all runtime and configuration options.",
@@ -177,6 +225,18 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgKillCursors,
 			Help:    "Closes server cursors.",
 		},
+		"listArchivePolicies": {
+			Handler: h.MsgListArchivePolicies,
+			Help:    "Returns archive policies of the database.",
+		},
+		"listClientVersions": {
+			Handler: h.MsgListClientVersions,
+			Help:    "Returns distinct driver names and versions of connected clients.",
+		},
+		"listCollectionTemplates": {
+			Handler: h.MsgListCollectionTemplates,
+			Help:    "Returns a list of named templates for new collections.",
+		},
 		"listCollections": {
 			Handler: h.MsgListCollections,
 			Help:    "Returns the information of the collections and views in the database.",
@@ -193,6 +253,10 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgListIndexes,
 			Help:    "Returns a summary of indexes of the specified collection.",
 		},
+		"listQuotas": {
+			Handler: h.MsgListQuotas,
+			Help:    "Returns storage quotas of the database and its collections.",
+		},
 		"logout": {
 			Handler: h.MsgLogout,
 			Help:    "Logs out from the current session.",
@@ -201,6 +265,18 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgPing,
 			Help:    "Returns a pong response.",
 		},
+		"reIndex": {
+			Handler: h.MsgReIndex,
+			Help:    "Rebuilds all indexes of the collection.",
+		},
+		"removeArchivePolicy": {
+			Handler: h.MsgRemoveArchivePolicy,
+			Help:    "Removes the archive policy of the collection.",
+		},
+		"removeQuota": {
+			Handler: h.MsgRemoveQuota,
+			Help:    "Removes the storage quota of the database or collection.",
+		},
 		"renameCollection": {
 			Handler: h.MsgRenameCollection,
 			Help:    "Changes the name of an existing collection.",
@@ -217,14 +293,39 @@ func (h *Handler) initCommands() {
 			Handler: h.MsgServerStatus,
 			Help:    "Returns an overview of the databases state.",
 		},
+		"setArchivePolicy": {
+			Handler: h.MsgSetArchivePolicy,
+			Help:    "Sets the archive policy of the collection.",
+		},
 		"setFreeMonitoring": {
 			Handler: h.MsgSetFreeMonitoring,
 			Help:    "Toggles free monitoring.",
 		},
+		"setParameter": {
+			Handler: h.MsgSetParameter,
+			Help:    "Changes the value of the parameter at runtime.",
+		},
+		"setQuota": {
+			Handler: h.MsgSetQuota,
+			Help:    "Sets the storage quota of the database or collection.",
+		},
+		"supportedFeatures": {
+			Handler: h.MsgSupportedFeatures,
+			Help: "Returns commands, aggregation stages, operators, and index types " +
+				"supported with the current backend.",
+		},
+		"top": {
+			Handler: h.MsgTop,
+			Help:    "Returns usage statistics for each collection.",
+		},
 		"update": {
 			Handler: h.MsgUpdate,
 			Help:    "Updates documents that are matched by the query.",
 		},
+		"updateSearchIndex": {
+			Handler: h.MsgUpdateSearchIndex,
+			Help:    "Updates the definition of a full-text search index on a collection.",
+		},
 		"validate": {
 			Handler: h.MsgValidate,
 			Help:    "Validates collection.",
@@ -260,6 +361,21 @@ func (h *Handler) initCommands() {
 		}
 		// please keep sorted alphabetically
 	}
+
+	for name, tc := range topCommands {
+		cmd := h.commands[name]
+		cmd.Handler = h.withTop(tc, cmd.Handler)
+		h.commands[name] = cmd
+	}
+
+	for name, cmd := range h.commands {
+		if cmd.Handler == nil || ownMaxTimeMSCommands[name] {
+			continue
+		}
+
+		cmd.Handler = h.withMaxTimeMS(cmd.Handler)
+		h.commands[name] = cmd
+	}
 }
 
 // Commands returns a map of enabled commands.
@@ -16,7 +16,9 @@ package common
 
 import (
 	"errors"
+	"strings"
 
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -74,6 +76,21 @@ func (iter *addFieldsIterator) Next() (struct{}, *types.Document, error) {
 			if err = processAddFieldsError(err); err != nil {
 				return unused, nil, err
 			}
+
+		case string:
+			if !strings.HasPrefix(v, "$") {
+				break
+			}
+
+			expr, err := aggregations.NewExpression(v, nil)
+			if err != nil {
+				return unused, nil, processAddFieldsExpressionError(err)
+			}
+
+			// fields with non-existent paths are not added
+			if val, err = expr.Evaluate(doc); err != nil {
+				continue
+			}
 		}
 
 		doc.Set(key, val)
@@ -132,6 +149,44 @@ func processAddFieldsError(err error) error {
 			"Invalid $addFields :: caused by :: "+opErr.Error(),
 			"$addFields (stage)",
 		)
+	case operators.ErrJavaScriptDisabled:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrJavaScriptDisabled,
+			opErr.Error(),
+			"$addFields (stage)",
+		)
+	default:
+		return lazyerrors.Error(err)
+	}
+}
+
+// processAddFieldsExpressionError takes internal error related to field path expression and
+// returns proper CommandError that can be returned by $addFields aggregation stage.
+func processAddFieldsExpressionError(err error) error {
+	var exprErr *aggregations.ExpressionError
+	if !errors.As(err, &exprErr) {
+		return lazyerrors.Error(err)
+	}
+
+	switch exprErr.Code() {
+	case aggregations.ErrInvalidExpression:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"'$' starts with an invalid character for a user variable name",
+			"$addFields (stage)",
+		)
+	case aggregations.ErrEmptyFieldPath:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrGroupInvalidFieldPath,
+			"'$' by itself is not a valid FieldPath",
+			"$addFields (stage)",
+		)
+	case aggregations.ErrUndefinedVariable:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"Aggregation expression variables are not implemented yet",
+			"$addFields (stage)",
+		)
 	default:
 		return lazyerrors.Error(err)
 	}
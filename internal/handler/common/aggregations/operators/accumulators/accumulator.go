@@ -0,0 +1,177 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accumulators
+
+import (
+	"errors"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/javascript"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// accumulator represents `$accumulator` accumulator.
+//
+// It is used only if JavaScript evaluation is enabled.
+type accumulator struct {
+	init           *javascript.Function
+	accumulate     *javascript.Function
+	finalize       *javascript.Function // may be nil
+	initArgs       operators.Operator   // may be nil
+	accumulateArgs operators.Operator
+}
+
+// newJavaScriptAccumulator creates a new `$accumulator` accumulator.
+func newJavaScriptAccumulator(args ...any) (Accumulator, error) {
+	var spec *types.Document
+	if len(args) == 1 {
+		spec, _ = args[0].(*types.Document)
+	}
+
+	if spec == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$accumulator requires an object as an argument",
+			"$accumulator (accumulator)",
+		)
+	}
+
+	if err := javascript.CheckLang(spec, "$accumulator"); err != nil {
+		return nil, err
+	}
+
+	acc := new(accumulator)
+
+	var err error
+
+	for _, f := range []struct {
+		field    string
+		dst      **javascript.Function
+		optional bool
+	}{
+		{field: "init", dst: &acc.init},
+		{field: "accumulate", dst: &acc.accumulate},
+		{field: "merge", dst: new(*javascript.Function)}, // validated, but not used, as groups are never merged
+		{field: "finalize", dst: &acc.finalize, optional: true},
+	} {
+		if f.optional && !spec.Has(f.field) {
+			continue
+		}
+
+		var code string
+		if code, err = javascript.GetCode(spec, f.field, "$accumulator"); err != nil {
+			return nil, err
+		}
+
+		if *f.dst, err = javascript.Compile("$accumulator", code); err != nil {
+			return nil, err
+		}
+	}
+
+	if spec.Has("initArgs") {
+		if acc.initArgs, err = newArgs(spec, "initArgs"); err != nil {
+			return nil, err
+		}
+	}
+
+	if acc.accumulateArgs, err = newArgs(spec, "accumulateArgs"); err != nil {
+		return nil, err
+	}
+
+	return acc, nil
+}
+
+// newArgs returns an operator that evaluates arguments array expression in the given field.
+func newArgs(spec *types.Document, field string) (operators.Operator, error) {
+	v, _ := spec.Get(field)
+
+	if _, ok := v.(*types.Array); !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"The "+field+" field must be specified and be an array",
+			"$accumulator (accumulator)",
+		)
+	}
+
+	return operators.NewExpr(must.NotFail(types.NewDocument("$expr", v)), "$accumulator (accumulator)")
+}
+
+// Accumulate implements Accumulator interface.
+func (a *accumulator) Accumulate(iter types.DocumentsIterator) (any, error) {
+	defer iter.Close()
+
+	var initArgs []any
+
+	if a.initArgs != nil {
+		// initArgs can't reference fields of documents
+		v, err := a.initArgs.Process(new(types.Document))
+		if err != nil {
+			return nil, err
+		}
+
+		initArgs = arrayValues(v.(*types.Array))
+	}
+
+	state, err := a.init.Call(initArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		_, doc, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		v, err := a.accumulateArgs.Process(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if state, err = a.accumulate.Call(append([]any{state}, arrayValues(v.(*types.Array))...)...); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.finalize == nil {
+		return state, nil
+	}
+
+	return a.finalize.Call(state)
+}
+
+// arrayValues returns all values of the given array.
+func arrayValues(arr *types.Array) []any {
+	res := make([]any, arr.Len())
+
+	for i := range res {
+		res[i] = must.NotFail(arr.Get(i))
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ Accumulator = (*accumulator)(nil)
+)
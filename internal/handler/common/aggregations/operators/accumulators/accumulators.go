@@ -26,6 +26,7 @@ import (
 	"fmt"
 
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/javascript"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -88,6 +89,19 @@ func NewAccumulator(stage, key string, value any) (Accumulator, error) {
 		args = append(args, expr)
 	}
 
+	// see javaScriptOperators in the operators package
+	if operator == "$accumulator" {
+		if javascript.Enabled() {
+			return newJavaScriptAccumulator(args...)
+		}
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrJavaScriptDisabled,
+			"Cannot run server-side javascript without the javascript engine enabled",
+			operator+" (accumulator)",
+		)
+	}
+
 	newAccumulator, ok := Accumulators[operator]
 	if !ok {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
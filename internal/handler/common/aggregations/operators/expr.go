@@ -268,6 +268,12 @@ func processExprOperatorErrors(err error, argument string) error {
 				opErr.Error(),
 				argument,
 			)
+		case ErrJavaScriptDisabled:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrJavaScriptDisabled,
+				opErr.Error(),
+				argument,
+			)
 		}
 
 	case errors.As(err, &exErr):
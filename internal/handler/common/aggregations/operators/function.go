@@ -0,0 +1,110 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operators
+
+import (
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/javascript"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// function represents `$function` operator.
+//
+// It is used only if JavaScript evaluation is enabled.
+type function struct {
+	f    *javascript.Function
+	args *types.Array
+	expr *expr // used to evaluate arguments
+}
+
+// newFunction returns `$function` operator.
+func newFunction(args ...any) (Operator, error) {
+	var spec *types.Document
+	if len(args) == 1 {
+		spec, _ = args[0].(*types.Document)
+	}
+
+	if spec == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$function requires an object as an argument",
+			"$function",
+		)
+	}
+
+	body, err := javascript.GetCode(spec, "body", "$function")
+	if err != nil {
+		return nil, err
+	}
+
+	v, _ := spec.Get("args")
+
+	fArgs, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"The args field must be specified and be an array",
+			"$function",
+		)
+	}
+
+	if err = javascript.CheckLang(spec, "$function"); err != nil {
+		return nil, err
+	}
+
+	e := &expr{errArgument: "$function"}
+	if err = e.validateExpr(fArgs); err != nil {
+		return nil, err
+	}
+
+	f, err := javascript.Compile("$function", body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &function{
+		f:    f,
+		args: fArgs,
+		expr: e,
+	}, nil
+}
+
+// Process implements Operator interface.
+func (f *function) Process(doc *types.Document) (any, error) {
+	// nil document is passed for validation only, do not evaluate code
+	if doc == nil {
+		return types.Null, nil
+	}
+
+	v, err := f.expr.processExpr(f.args, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	arr := v.(*types.Array)
+	args := make([]any, arr.Len())
+
+	for i := range args {
+		args[i] = must.NotFail(arr.Get(i))
+	}
+
+	return f.f.Call(args...)
+}
+
+// check interfaces
+var (
+	_ Operator = (*function)(nil)
+)
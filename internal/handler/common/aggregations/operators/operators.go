@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/FerretDB/FerretDB/internal/handler/javascript"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -96,6 +97,7 @@ func NewOperator(doc *types.Document) (Operator, error) {
 
 	newOperator, supported := Operators[operator]
 	_, unsupported := unsupportedOperators[operator]
+	_, javaScript := javaScriptOperators[operator]
 
 	expr := must.NotFail(doc.Get(operator))
 
@@ -121,6 +123,15 @@ func NewOperator(doc *types.Document) (Operator, error) {
 	switch {
 	case supported:
 		return newOperator(args...)
+	case javaScript && javascript.Enabled():
+		// $function is the only JavaScript operator for now
+		return newFunction(args...)
+	case javaScript:
+		return nil, newOperatorError(
+			ErrJavaScriptDisabled,
+			operator,
+			"Cannot run server-side javascript without the javascript engine enabled",
+		)
 	case unsupported:
 		return nil, newOperatorError(
 			ErrNotImplemented,
@@ -144,6 +155,14 @@ var Operators = map[string]newOperatorFunc{
 	// please keep sorted alphabetically
 }
 
+// javaScriptOperators maps all operators that require server-side JavaScript execution.
+//
+// They are used only if JavaScript evaluation is enabled (see javascript.Enable).
+// Otherwise, they return the same error as MongoDB with JavaScript disabled (`security.javascriptEnabled: false`).
+var javaScriptOperators = map[string]struct{}{
+	"$function": {},
+}
+
 // unsupportedOperators maps all unsupported yet operators.
 var unsupportedOperators = map[string]struct{}{
 	// sorted alphabetically
@@ -195,7 +214,6 @@ var unsupportedOperators = map[string]struct{}{
 	"$expMovingAvg":     {},
 	"$filter":           {},
 	"$floor":            {},
-	"$function":         {},
 	"$getField":         {},
 	"$gt":               {},
 	"$gte":              {},
@@ -34,6 +34,9 @@ const (
 
 	// ErrInvalidNestedExpression indicates that operator inside the target operator does not exist.
 	ErrInvalidNestedExpression
+
+	// ErrJavaScriptDisabled indicates that given operator requires server-side JavaScript execution.
+	ErrJavaScriptDisabled
 )
 
 // newOperatorError returns new OperatorError.
@@ -32,6 +32,7 @@ import (
 // collStats represents $collStats stage.
 type collStats struct {
 	storageStats   *storageStats
+	schema         *schemaStats
 	count          bool
 	latencyStats   bool
 	queryExecStats bool
@@ -42,6 +43,11 @@ type storageStats struct {
 	scale int32
 }
 
+// schemaStats represents $collStats.schema field.
+type schemaStats struct {
+	sampleSize int64 // zero means the default
+}
+
 // newCollStats creates a new $collStats stage.
 func newCollStats(stage *types.Document) (aggregations.Stage, error) {
 	fields, err := common.GetRequiredParam[*types.Document](stage, "$collStats")
@@ -85,6 +91,30 @@ func newCollStats(stage *types.Document) (aggregations.Stage, error) {
 		}
 	}
 
+	if v, _ := fields.Get("schema"); v != nil {
+		schemaFields, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					"BSON field '$collStats.schema' is the wrong type '%s', expected type 'object'",
+					handlerparams.AliasFromType(v),
+				),
+				"$collStats (stage)",
+			)
+		}
+
+		cs.schema = new(schemaStats)
+
+		if s, _ := schemaFields.Get("sampleSize"); s != nil {
+			if cs.schema.sampleSize, err = handlerparams.GetValidatedNumberParamWithMinValue(
+				"$collStats.schema", "sampleSize", s, 1,
+			); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return &cs, nil
 }
 
@@ -427,6 +427,12 @@ func processGroupStageError(err error) error {
 				opErr.Error(),
 				"$group (stage)",
 			)
+		case operators.ErrJavaScriptDisabled:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrJavaScriptDisabled,
+				opErr.Error(),
+				"$group (stage)",
+			)
 		}
 
 	case errors.As(err, &exErr):
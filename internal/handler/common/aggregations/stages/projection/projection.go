@@ -676,6 +676,12 @@ func processOperatorError(err error) error {
 				"Invalid $project :: caused by :: "+opErr.Error(),
 				"$project (stage)",
 			)
+		case operators.ErrJavaScriptDisabled:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrJavaScriptDisabled,
+				opErr.Error(),
+				"$project (stage)",
+			)
 		}
 
 	case errors.As(err, &exErr):
@@ -68,7 +68,6 @@ var unsupportedStages = map[string]struct{}{
 	"$replaceRoot":            {},
 	"$replaceWith":            {},
 	"$sample":                 {},
-	"$search":                 {},
 	"$searchMeta":             {},
 	"$setWindowFields":        {},
 	"$sharedDataDistribution": {},
@@ -26,6 +26,7 @@ const (
 	StatisticLatency
 	StatisticQueryExec
 	StatisticStorage
+	StatisticSchema
 )
 
 // GetStatistics has the same idea as GetPushdownQuery: it returns a list of statistics that need
@@ -51,8 +52,26 @@ func GetStatistics(stages []aggregations.Stage) map[Statistic]struct{} {
 			if st.storageStats != nil {
 				stats[StatisticStorage] = struct{}{}
 			}
+
+			if st.schema != nil {
+				stats[StatisticSchema] = struct{}{}
+			}
 		}
 	}
 
 	return stats
 }
+
+// SchemaSampleSize returns the number of documents to sample for StatisticSchema.
+// Zero means that the stages do not specify it.
+func SchemaSampleSize(stages []aggregations.Stage) int64 {
+	var res int64
+
+	for _, stage := range stages {
+		if st, ok := stage.(*collStats); ok && st.schema != nil {
+			res = max(res, st.schema.sampleSize)
+		}
+	}
+
+	return res
+}
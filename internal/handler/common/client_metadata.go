@@ -39,5 +39,32 @@ func CheckClientMetadata(ctx context.Context, doc *types.Document) error {
 
 	connInfo.SetMetadataRecv()
 
+	if md, ok := c.(*types.Document); ok {
+		connInfo.SetClientMetadata(parseClientMetadata(md))
+	}
+
 	return nil
 }
+
+// parseClientMetadata returns known values of the given client metadata document.
+//
+// Missing fields and fields with unexpected types are ignored, so they never fail the handshake.
+func parseClientMetadata(doc *types.Document) *conninfo.ClientMetadata {
+	str := func(path ...string) string {
+		v, _ := doc.GetByPath(types.NewStaticPath(path...))
+		s, _ := v.(string)
+
+		return s
+	}
+
+	return &conninfo.ClientMetadata{
+		DriverName:     str("driver", "name"),
+		DriverVersion:  str("driver", "version"),
+		AppName:        str("application", "name"),
+		OSType:         str("os", "type"),
+		OSName:         str("os", "name"),
+		OSArchitecture: str("os", "architecture"),
+		OSVersion:      str("os", "version"),
+		Platform:       str("platform"),
+	}
+}
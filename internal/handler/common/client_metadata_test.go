@@ -100,3 +100,35 @@ func TestCheckClientMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckClientMetadataParse(t *testing.T) {
+	t.Parallel()
+
+	connInfo := conninfo.New()
+	ctx := conninfo.Ctx(context.Background(), connInfo)
+
+	doc := must.NotFail(types.NewDocument(
+		"client", must.NotFail(types.NewDocument(
+			"driver", must.NotFail(types.NewDocument(
+				"name", "mongo-go-driver",
+				"version", "v1.12.1",
+			)),
+			"os", must.NotFail(types.NewDocument(
+				"type", "linux",
+				"architecture", int32(64),
+			)),
+			"platform", "go1.21.5",
+			"application", "not a document",
+		)),
+	))
+
+	assert.NoError(t, CheckClientMetadata(ctx, doc))
+
+	expected := &conninfo.ClientMetadata{
+		DriverName:    "mongo-go-driver",
+		DriverVersion: "v1.12.1",
+		OSType:        "linux",
+		Platform:      "go1.21.5",
+	}
+	assert.Equal(t, expected, connInfo.ClientMetadata())
+}
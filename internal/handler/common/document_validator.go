@@ -0,0 +1,179 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Validation levels and actions supported by `create` command's document validation.
+const (
+	ValidationLevelOff      = "off"
+	ValidationLevelStrict   = "strict"
+	ValidationLevelModerate = "moderate"
+
+	ValidationActionError = "error"
+	ValidationActionWarn  = "warn"
+)
+
+// DocumentValidator checks inserted and updated documents against collection's validator
+// and time field of time-series collection.
+//
+// Nil DocumentValidator accepts all documents.
+type DocumentValidator struct {
+	validator *types.Document
+	level     string
+	action    string
+	timeField string
+	l         *zap.Logger
+}
+
+// NewDocumentValidator returns validator for the given collection.
+//
+// It returns nil if the collection is not a time-series collection
+// and does not have a validator or validation is off.
+func NewDocumentValidator(info *backends.CollectionInfo, l *zap.Logger) *DocumentValidator {
+	if info == nil {
+		return nil
+	}
+
+	v := &DocumentValidator{
+		validator: info.Validator,
+		level:     info.ValidationLevel,
+		action:    info.ValidationAction,
+		l:         l,
+	}
+
+	if info.ValidationLevel == ValidationLevelOff {
+		v.validator = nil
+	}
+
+	if info.TimeSeries != nil {
+		v.timeField = info.TimeSeries.TimeField
+	}
+
+	if v.validator == nil && v.timeField == "" {
+		return nil
+	}
+
+	return v
+}
+
+// ValidateTimeField returns an error message if the document of time-series collection
+// does not contain a date in the time field, and an empty string otherwise.
+func (v *DocumentValidator) ValidateTimeField(doc *types.Document) string {
+	if v == nil || v.timeField == "" {
+		return ""
+	}
+
+	if t, _ := doc.Get(v.timeField); t != nil {
+		if _, ok := t.(time.Time); ok {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("'%s' must be present and contain a valid BSON UTC datetime value", v.timeField)
+}
+
+// Validate returns true if the given document could be stored.
+//
+// For updates, old is the document before the update; for inserts, it should be nil.
+// With moderate validation level, updates of documents that did not pass validation before are not checked.
+// With warn validation action, documents that do not pass validation are logged and stored.
+func (v *DocumentValidator) Validate(old, doc *types.Document) (bool, error) {
+	if v == nil || v.validator == nil {
+		return true, nil
+	}
+
+	if old != nil && v.level == ValidationLevelModerate {
+		matches, err := FilterDocument(old, v.validator)
+		if err != nil {
+			return false, lazyerrors.Error(err)
+		}
+
+		if !matches {
+			return true, nil
+		}
+	}
+
+	matches, err := FilterDocument(doc, v.validator)
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	if matches {
+		return true, nil
+	}
+
+	if v.action == ValidationActionWarn {
+		id, _ := doc.Get("_id")
+		v.l.Warn("Document would fail validation", zap.String("id", types.FormatAnyValue(id)))
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// ValidateValidator checks that the given document could be used as a collection validator.
+func ValidateValidator(command string, validator *types.Document) error {
+	iter := validator.Iterator()
+	defer iter.Close()
+
+	for {
+		key, _, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return lazyerrors.Error(err)
+		}
+
+		switch key {
+		case "$jsonSchema":
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				"$jsonSchema validator is not implemented yet",
+				command,
+			)
+
+		case "$near", "$nearSphere", "$text", "$where":
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("%s is not allowed in collection validators", key),
+				command,
+			)
+		}
+	}
+
+	// check that validator is a valid filter
+	if _, err := FilterDocument(must.NotFail(types.NewDocument()), validator); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -26,12 +26,46 @@ import (
 	"github.com/FerretDB/FerretDB/internal/handler/commonpath"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/handler/javascript"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
+// FilterOperators lists all supported query operators, both top-level and field ones.
+//
+// Please keep it in sync with filterOperator and filterFieldExpr.
+var FilterOperators = []string{
+	// sorted alphabetically
+	"$all",
+	"$and",
+	"$bitsAllClear",
+	"$bitsAllSet",
+	"$bitsAnyClear",
+	"$bitsAnySet",
+	"$comment",
+	"$elemMatch",
+	"$eq",
+	"$exists",
+	"$expr",
+	"$gt",
+	"$gte",
+	"$in",
+	"$lt",
+	"$lte",
+	"$mod",
+	"$ne",
+	"$nin",
+	"$nor",
+	"$not",
+	"$or",
+	"$regex",
+	"$size",
+	"$type",
+	// please keep sorted alphabetically
+}
+
 // FilterDocument returns true if given document satisfies given filter expression.
 //
 // Passed arguments must not be modified.
@@ -289,6 +323,20 @@ func filterOperator(doc *types.Document, operator string, filterValue any) (bool
 
 	case "$expr":
 		return filterExprOperator(doc, must.NotFail(types.NewDocument(operator, filterValue)))
+
+	case "$where":
+		// see javaScriptOperators in the operators package
+		if javascript.Enabled() {
+			return filterWhereOperator(doc, filterValue)
+		}
+
+		// return the same error as MongoDB with JavaScript disabled
+		return false, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"no globalScriptEngine in $where parsing",
+			operator,
+		)
+
 	default:
 		msg := fmt.Sprintf(
 			`unknown top level operator: %s. `+
@@ -332,6 +380,28 @@ func filterExprOperator(doc, filter *types.Document) (bool, error) {
 	}
 }
 
+// filterWhereOperator uses $where operator to evaluate JavaScript function or expression.
+// It returns boolean indicating filter has matched.
+//
+// It is used only if JavaScript evaluation is enabled.
+func filterWhereOperator(doc *types.Document, filterValue any) (bool, error) {
+	code, ok := filterValue.(string)
+	if !ok {
+		return false, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"$where got bad type",
+			"$where",
+		)
+	}
+
+	f, err := javascript.Compile("$where", code)
+	if err != nil {
+		return false, err
+	}
+
+	return f.Match(doc)
+}
+
 // filterFieldExpr handles {field: {expr}} or {field: {document}} filter.
 func filterFieldExpr(doc *types.Document, filterKey, filterSuffix string, expr *types.Document) (bool, error) {
 	// check if both documents are empty
@@ -19,6 +19,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -42,6 +43,10 @@ type FindAndModifyParams struct {
 	Update      *types.Document `ferretdb:"-"`
 	Aggregation *types.Array    `ferretdb:"-"`
 
+	// Stages of the update pipeline are set by the handler
+	// because stages package can't be used there.
+	Stages []aggregations.Stage `ferretdb:"-"`
+
 	HasUpdateOperators bool `ferretdb:"-"`
 
 	Let       *types.Document `ferretdb:"let,unimplemented"`
@@ -94,11 +99,7 @@ func GetFindAndModifyParams(doc *types.Document, l *zap.Logger) (*FindAndModifyP
 		case *types.Document:
 			params.Update = updateParam
 		case *types.Array:
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrNotImplemented,
-				"Aggregation pipelines are not supported yet",
-				"update",
-			)
+			params.Aggregation = updateParam
 		default:
 			return nil, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrFailedToParse,
@@ -108,7 +109,7 @@ func GetFindAndModifyParams(doc *types.Document, l *zap.Logger) (*FindAndModifyP
 		}
 	}
 
-	if params.Update != nil && params.Remove {
+	if params.UpdateValue != nil && params.Remove {
 		return nil, handlererrors.NewCommandErrorMsg(
 			handlererrors.ErrFailedToParse,
 			"Cannot specify both an update and remove=true",
@@ -26,24 +26,26 @@ import (
 )
 
 // IsMaster is a common implementation of the isMaster command used by deprecated OP_QUERY message.
-func IsMaster(ctx context.Context, query *types.Document, tcpHost, name string) (*wire.OpReply, error) {
+func IsMaster(
+	ctx context.Context, query *types.Document, tcpHost, name string, maxBSONObjectSize, maxMessageSize int32,
+) (*wire.OpReply, error) {
 	if err := CheckClientMetadata(ctx, query); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
 	var reply wire.OpReply
-	reply.SetDocument(IsMasterDocument(tcpHost, name))
+	reply.SetDocument(IsMasterDocument(tcpHost, name, maxBSONObjectSize, maxMessageSize))
 
 	return &reply, nil
 }
 
 // IsMasterDocument returns isMaster's Documents field (identical for both OP_MSG and OP_QUERY).
-func IsMasterDocument(tcpHost, name string) *types.Document {
+func IsMasterDocument(tcpHost, name string, maxBSONObjectSize, maxMessageSize int32) *types.Document {
 	doc := must.NotFail(types.NewDocument(
 		"ismaster", true, // only lowercase
 		// topologyVersion
-		"maxBsonObjectSize", int32(types.MaxDocumentLen),
-		"maxMessageSizeBytes", int32(wire.MaxMsgLen),
+		"maxBsonObjectSize", maxBSONObjectSize,
+		"maxMessageSizeBytes", maxMessageSize,
 		"maxWriteBatchSize", int32(100000),
 		"localTime", time.Now(),
 		"logicalSessionTimeoutMinutes", int32(30),
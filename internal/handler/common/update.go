@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -33,13 +34,38 @@ import (
 	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
+// UpdateOperators lists all supported update operators.
+//
+// Please keep it in sync with processUpdateOperator and HasSupportedUpdateModifiers.
+var UpdateOperators = []string{
+	// sorted alphabetically
+	"$addToSet",
+	"$bit",
+	"$currentDate",
+	"$inc",
+	"$max",
+	"$min",
+	"$mul",
+	"$pop",
+	"$pull",
+	"$pullAll",
+	"$push",
+	"$rename",
+	"$set",
+	"$setOnInsert",
+	"$unset",
+	// please keep sorted alphabetically
+}
+
 // UpdateDocument iterates through documents from iter and processes them sequentially based on param.
 // Returns UpdateResult if all operations (update/upsert) are successful.
 //
 // In case of updating multiple documents, UpdateDocument returns an error immediately after one of the
 // operation fails. The rest of the documents are not processed.
 // TODO https://github.com/FerretDB/FerretDB/issues/2612
-func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter types.DocumentsIterator, param *Update) (*UpdateResult, error) { //nolint:lll // for readability
+//
+// Upserted and modified documents are checked by the given validator that may be nil.
+func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter types.DocumentsIterator, param *Update, v *DocumentValidator) (*UpdateResult, error) { //nolint:lll // for readability
 	result := new(UpdateResult)
 
 	isFindAndModify := (strings.ToLower(cmd) == "findandmodify")
@@ -74,9 +100,17 @@ func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter
 			}
 		}
 
-		if !param.HasUpdateOperators {
+		var old *types.Document
+		if v != nil && !upsert {
+			old = doc.DeepCopy()
+		}
+
+		switch {
+		case param.Pipeline != nil:
+			modified, err = processPipeline(ctx, cmd, doc, param.Stages)
+		case !param.HasUpdateOperators:
 			modified, err = processReplacementDoc(cmd, doc, param.Update)
-		} else {
+		default:
 			modified, err = processUpdateOperator(cmd, doc, param.Update, upsert)
 		}
 
@@ -93,6 +127,21 @@ func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter
 			return nil, lazyerrors.Error(err)
 		}
 
+		if upsert || modified {
+			if msg := v.ValidateTimeField(doc); msg != "" {
+				return nil, NewUpdateError(handlererrors.ErrBadValue, msg, cmd)
+			}
+
+			var valid bool
+			if valid, err = v.Validate(old, doc); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			if !valid {
+				return nil, NewUpdateError(handlererrors.ErrDocumentValidationFailure, "Document failed validation", cmd)
+			}
+		}
+
 		if upsert {
 			_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{doc}})
 			if err != nil {
@@ -116,6 +165,31 @@ func UpdateDocument(ctx context.Context, c backends.Collection, cmd string, iter
 	}
 }
 
+// ApplyUpdate changes the matched document according to the update parameters
+// the same way UpdateDocument does, but does not store it.
+// It returns true if the document was changed.
+func ApplyUpdate(cmd string, doc *types.Document, param *Update) (bool, error) {
+	var modified bool
+	var err error
+
+	if !param.HasUpdateOperators {
+		modified, err = processReplacementDoc(cmd, doc, param.Update)
+	} else {
+		modified, err = processUpdateOperator(cmd, doc, param.Update, false)
+	}
+
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	// TODO https://github.com/FerretDB/FerretDB/issues/3454
+	if err = doc.ValidateData(); err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return modified, nil
+}
+
 // processFilterEqualityCondition copies the fields with equality condition from filter to doc.
 func processFilterEqualityCondition(doc, filter *types.Document) error {
 	iter := filter.Iterator()
@@ -203,6 +277,31 @@ func processReplacementDoc(command string, doc, update *types.Document) (bool, e
 	return changed, nil
 }
 
+// processPipeline updates the given document with update pipeline stages.
+// The pipeline result replaces the document the same way as the replacement document does.
+// Returns true if the document is changed. Returns error when _id is attempted to be changed.
+func processPipeline(ctx context.Context, command string, doc *types.Document, stages []aggregations.Stage) (bool, error) { //nolint:lll // for readability
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	iter := iterator.Values(iterator.ForSlice([]*types.Document{doc.DeepCopy()}))
+	closer.Add(iter)
+
+	for _, s := range stages {
+		var err error
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return false, lazyerrors.Error(err)
+		}
+	}
+
+	_, res, err := iter.Next()
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	return processReplacementDoc(command, doc, res)
+}
+
 // processUpdateOperator updates the given document with a series of update operators.
 // Returns true if the document is changed.
 // Returns CommandError if the command is findAndModify, otherwise returns WriteError.
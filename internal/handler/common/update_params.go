@@ -17,6 +17,7 @@ package common
 import (
 	"go.uber.org/zap"
 
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -36,7 +37,7 @@ type UpdateParams struct {
 
 	Let *types.Document `ferretdb:"let,unimplemented"`
 
-	Ordered                  bool            `ferretdb:"ordered,ignored"`
+	Ordered                  bool            `ferretdb:"ordered,opt"`
 	BypassDocumentValidation bool            `ferretdb:"bypassDocumentValidation,ignored"`
 	WriteConcern             *types.Document `ferretdb:"writeConcern,ignored"`
 	LSID                     any             `ferretdb:"lsid,ignored"`
@@ -50,10 +51,17 @@ type UpdateParams struct {
 //
 //nolint:vet // for readability
 type Update struct {
-	Filter *types.Document `ferretdb:"q,opt"`
-	Update *types.Document `ferretdb:"u,opt"` // TODO https://github.com/FerretDB/FerretDB/issues/2742
-	Multi  bool            `ferretdb:"multi,opt"`
-	Upsert bool            `ferretdb:"upsert,opt,numericBool"`
+	Filter      *types.Document `ferretdb:"q,opt"`
+	UpdateValue any             `ferretdb:"u,opt"`
+	Multi       bool            `ferretdb:"multi,opt"`
+	Upsert      bool            `ferretdb:"upsert,opt,numericBool"`
+
+	Update   *types.Document `ferretdb:"-"` // TODO https://github.com/FerretDB/FerretDB/issues/2742
+	Pipeline *types.Array    `ferretdb:"-"`
+
+	// Stages of the update pipeline are set by the handler
+	// because stages package can't be used there.
+	Stages []aggregations.Stage `ferretdb:"-"`
 
 	HasUpdateOperators bool `ferretdb:"-"`
 
@@ -85,7 +93,9 @@ type UpdateResult struct {
 
 // GetUpdateParams returns parameters for update command.
 func GetUpdateParams(document *types.Document, l *zap.Logger) (*UpdateParams, error) {
-	var params UpdateParams
+	params := UpdateParams{
+		Ordered: true,
+	}
 
 	err := handlerparams.ExtractParams(document, "update", &params, l)
 	if err != nil {
@@ -96,8 +106,20 @@ func GetUpdateParams(document *types.Document, l *zap.Logger) (*UpdateParams, er
 		for i := range params.Updates {
 			update := &params.Updates[i]
 
-			if update.Update == nil {
+			switch u := update.UpdateValue.(type) {
+			case nil:
+				continue
+			case *types.Document:
+				update.Update = u
+			case *types.Array:
+				update.Pipeline = u
 				continue
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrFailedToParse,
+					"Update argument must be either an object or an array",
+					"update",
+				)
 			}
 
 			hasUpdateOperators, err := HasSupportedUpdateModifiers("update", update.Update)
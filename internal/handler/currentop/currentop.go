@@ -0,0 +1,150 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package currentop tracks long-running operations, such as index builds, for the `currentOp` command.
+package currentop
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Registry stores in-progress operations.
+//
+// It is safe for concurrent use.
+type Registry struct {
+	lastID atomic.Int64
+
+	rw  sync.RWMutex
+	ops map[int64]*Op
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		ops: map[int64]*Op{},
+	}
+}
+
+// Start registers a new operation on the given namespace.
+//
+// The caller must call [Op.Finish] when the operation is done.
+func (r *Registry) Start(ns string, command *types.Document) *Op {
+	op := &Op{
+		r:       r,
+		id:      r.lastID.Add(1),
+		ns:      ns,
+		command: command,
+		started: time.Now(),
+	}
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.ops[op.id] = op
+
+	return op
+}
+
+// Documents returns `currentOp` command's `inprog` documents for all operations sorted by opid.
+func (r *Registry) Documents() []*types.Document {
+	r.rw.RLock()
+
+	ops := make([]*Op, 0, len(r.ops))
+	for _, op := range r.ops {
+		ops = append(ops, op)
+	}
+
+	r.rw.RUnlock()
+
+	slices.SortFunc(ops, func(a, b *Op) int { return int(a.id - b.id) })
+
+	res := make([]*types.Document, len(ops))
+	for i, op := range ops {
+		res[i] = op.document()
+	}
+
+	return res
+}
+
+// Op represents a single in-progress operation.
+type Op struct {
+	r       *Registry
+	id      int64
+	ns      string
+	command *types.Document
+	started time.Time
+
+	m        sync.Mutex
+	progress *backends.IndexBuildProgress
+}
+
+// SetProgress updates index build progress of the operation.
+//
+// It has a signature of [backends.CreateIndexesParams] Progress field.
+func (op *Op) SetProgress(p *backends.IndexBuildProgress) {
+	op.m.Lock()
+	defer op.m.Unlock()
+
+	op.progress = p
+}
+
+// Finish removes the operation from the registry.
+func (op *Op) Finish() {
+	op.r.rw.Lock()
+	defer op.r.rw.Unlock()
+
+	delete(op.r.ops, op.id)
+}
+
+// document returns a `currentOp` command's `inprog` document of the operation.
+func (op *Op) document() *types.Document {
+	running := time.Since(op.started)
+
+	doc := must.NotFail(types.NewDocument(
+		"type", "op",
+		"active", true,
+		"opid", op.id,
+		"secs_running", int64(running.Seconds()),
+		"microsecs_running", running.Microseconds(),
+		"op", "command",
+		"ns", op.ns,
+		"command", op.command,
+	))
+
+	op.m.Lock()
+	p := op.progress
+	op.m.Unlock()
+
+	if p == nil {
+		return doc
+	}
+
+	doc.Set("msg", "Index Build: "+p.Phase)
+
+	if p.Total > 0 {
+		doc.Set("progress", must.NotFail(types.NewDocument(
+			"done", p.Done,
+			"total", p.Total,
+		)))
+	}
+
+	return doc
+}
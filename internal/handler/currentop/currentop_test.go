@@ -0,0 +1,63 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package currentop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	assert.Empty(t, r.Documents())
+
+	op1 := r.Start("db.a", must.NotFail(types.NewDocument("createIndexes", "a")))
+	op2 := r.Start("db.b", must.NotFail(types.NewDocument("reIndex", "b")))
+
+	op1.SetProgress(&backends.IndexBuildProgress{Index: "v_1", Phase: "building index", Done: 5, Total: 10})
+	op2.SetProgress(&backends.IndexBuildProgress{Index: "v_1", Phase: "initializing"})
+
+	docs := r.Documents()
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, int64(1), must.NotFail(docs[0].Get("opid")))
+	assert.Equal(t, "db.a", must.NotFail(docs[0].Get("ns")))
+	assert.Equal(t, "Index Build: building index", must.NotFail(docs[0].Get("msg")))
+
+	progress := must.NotFail(docs[0].Get("progress")).(*types.Document)
+	assert.Equal(t, int64(5), must.NotFail(progress.Get("done")))
+	assert.Equal(t, int64(10), must.NotFail(progress.Get("total")))
+
+	assert.Equal(t, int64(2), must.NotFail(docs[1].Get("opid")))
+	assert.Equal(t, "Index Build: initializing", must.NotFail(docs[1].Get("msg")))
+	assert.False(t, docs[1].Has("progress"))
+
+	op1.Finish()
+
+	docs = r.Documents()
+	require.Len(t, docs, 1)
+	assert.Equal(t, "db.b", must.NotFail(docs[0].Get("ns")))
+
+	op2.Finish()
+	assert.Empty(t, r.Documents())
+}
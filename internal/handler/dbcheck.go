@@ -0,0 +1,728 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/sjson"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Database and collection that store dbCheck results, as in MongoDB.
+const (
+	healthLogDatabase   = "local"
+	healthLogCollection = "system.healthlog"
+)
+
+// healthLogSize is the maximum size of the capped health log collection, as in MongoDB.
+const healthLogSize = 100 * 1024 * 1024
+
+// dbCheckChecksumsCollection is the collection in the local database that stores
+// batch checksums of the last check of each collection, so changes between checks could be found.
+const dbCheckChecksumsCollection = "system.dbcheck"
+
+// dbCheckBatchSize is the average number of documents checksummed together in a single health log entry.
+// It is also the number of health log entries written at once.
+const dbCheckBatchSize = 1000
+
+// dbCheckMaxKeys is the maximum number of `_id` and unique index keys kept in memory to find duplicates.
+// Collections with more keys are scanned multiple times, each time checking a different part of keys.
+const dbCheckMaxKeys = 1 << 20
+
+// dbCheckBatch accumulates a checksum of documents.
+//
+// The checksum does not depend on the order of documents,
+// so checksums of different checks of the same documents could be compared.
+type dbCheckBatch struct {
+	md5   [md5.Size]byte
+	count int64
+	bytes int64
+}
+
+// add adds the marshaled document to the batch.
+func (b *dbCheckBatch) add(data []byte) {
+	sum := md5.Sum(data)
+	for i := range b.md5 {
+		b.md5[i] ^= sum[i]
+	}
+
+	b.count++
+	b.bytes += int64(len(data))
+}
+
+// document returns the batch checksum as stored in the health log and checksums collection.
+func (b *dbCheckBatch) document() *types.Document {
+	return must.NotFail(types.NewDocument(
+		"count", b.count,
+		"bytes", b.bytes,
+		"md5", hex.EncodeToString(b.md5[:]),
+	))
+}
+
+// entry returns the health log entry for the i-th batch.
+func (b *dbCheckBatch) entry(ns string, i int) *types.Document {
+	data := b.document()
+	data.Set("success", true)
+	data.Set("batch", int64(i))
+
+	return healthLogEntry(ns, "dbCheckBatch", "info", "dbCheck batch consistent", data)
+}
+
+// parseDBCheckBatch parses the batch checksum stored by the previous check.
+func parseDBCheckBatch(doc *types.Document) (dbCheckBatch, bool) {
+	var b dbCheckBatch
+
+	count, _ := doc.Get("count")
+	bytes, _ := doc.Get("bytes")
+	sum, _ := doc.Get("md5")
+
+	var ok bool
+	if b.count, ok = count.(int64); !ok {
+		return b, false
+	}
+
+	if b.bytes, ok = bytes.(int64); !ok {
+		return b, false
+	}
+
+	s, ok := sum.(string)
+	if !ok {
+		return b, false
+	}
+
+	if n, err := hex.Decode(b.md5[:], []byte(s)); err != nil || n != md5.Size {
+		return b, false
+	}
+
+	return b, true
+}
+
+// dbCheckBatches returns the number of batches for the given number of documents.
+// It is a power of two, so it does not change with small changes of the collection.
+func dbCheckBatches(count int64) int {
+	n := 1
+	for int64(n)*dbCheckBatchSize < count {
+		n *= 2
+	}
+
+	return n
+}
+
+// dbCheckPartitions returns the number of collection scans needed to find duplicates
+// among the given number of keys without keeping more than dbCheckMaxKeys of them in memory.
+func dbCheckPartitions(keys int64) uint64 {
+	return uint64(max(1, (keys+dbCheckMaxKeys-1)/dbCheckMaxKeys))
+}
+
+// dbCheckKeyHash returns the hash of the `_id` or unique index key.
+func dbCheckKeyHash(v any) [md5.Size]byte {
+	return md5.Sum(must.NotFail(sjson.MarshalSingleValue(v)))
+}
+
+// dbCheckKeys finds duplicate `_id` and unique index keys.
+//
+// To bound memory usage, only keys of a single partition (selected by a key hash) are tracked.
+type dbCheckKeys struct {
+	sets       []map[[md5.Size]byte]struct{} // `_id` first, then unique indexes
+	partitions uint64
+	partition  uint64
+}
+
+// newDBCheckKeys returns keys for the given number of sets and the given partition.
+func newDBCheckKeys(sets int, partitions, partition uint64) *dbCheckKeys {
+	k := &dbCheckKeys{
+		sets:       make([]map[[md5.Size]byte]struct{}, sets),
+		partitions: partitions,
+		partition:  partition,
+	}
+
+	for i := range k.sets {
+		k.sets[i] = map[[md5.Size]byte]struct{}{}
+	}
+
+	return k
+}
+
+// add adds the key hash to the i-th set.
+// It returns false if the set already contains it.
+// Keys of other partitions are not added.
+func (k *dbCheckKeys) add(i int, hash [md5.Size]byte) bool {
+	if binary.BigEndian.Uint64(hash[8:])%k.partitions != k.partition {
+		return true
+	}
+
+	if _, ok := k.sets[i][hash]; ok {
+		return false
+	}
+
+	k.sets[i][hash] = struct{}{}
+
+	return true
+}
+
+// healthLogEntry returns a new health log document.
+func healthLogEntry(ns, operation, severity, msg string, data *types.Document) *types.Document {
+	return must.NotFail(types.NewDocument(
+		"_id", types.NewObjectID(),
+		"timestamp", time.Now(),
+		"severity", severity,
+		"msg", msg,
+		"namespace", ns,
+		"operation", operation,
+		"data", data,
+	))
+}
+
+// dbCheckInconsistency returns the health log entry for the document that is inconsistent
+// with the collection metadata.
+func dbCheckInconsistency(ns, msg string, id any) *types.Document {
+	data := must.NotFail(types.NewDocument("success", false))
+	if id != nil {
+		data.Set("_id", id)
+	}
+
+	return healthLogEntry(ns, "dbCheckBatch", "error", msg, data)
+}
+
+// writeHealthLog inserts entries into the health log collection, creating it if needed.
+func (h *Handler) writeHealthLog(ctx context.Context, entries []*types.Document) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	db, err := h.b.Database(healthLogDatabase)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	err = db.CreateCollection(ctx, &backends.CreateCollectionParams{
+		Name:       healthLogCollection,
+		CappedSize: healthLogSize,
+	})
+	if err != nil && !backends.ErrorCodeIs(err, backends.ErrorCodeCollectionAlreadyExists) {
+		return lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(healthLogCollection)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if _, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: entries}); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// dbCheckChecksums returns batch checksums of the last check of the given namespace, or nil if there are none.
+func (h *Handler) dbCheckChecksums(ctx context.Context, ns string) ([]dbCheckBatch, error) {
+	db, err := h.b.Database(healthLogDatabase)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(dbCheckChecksumsCollection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	qr, err := c.Query(ctx, &backends.QueryParams{Filter: must.NotFail(types.NewDocument("_id", ns))})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	for {
+		_, doc, err := qr.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return nil, nil
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		// backend filtering is not exact
+		if id, _ := doc.Get("_id"); id != ns {
+			continue
+		}
+
+		arr, _ := doc.Get("batches")
+
+		batches, ok := arr.(*types.Array)
+		if !ok {
+			return nil, nil
+		}
+
+		res := make([]dbCheckBatch, batches.Len())
+
+		for i := range res {
+			d, _ := must.NotFail(batches.Get(i)).(*types.Document)
+			if d == nil {
+				return nil, nil
+			}
+
+			if res[i], ok = parseDBCheckBatch(d); !ok {
+				return nil, nil
+			}
+		}
+
+		return res, nil
+	}
+}
+
+// saveDBCheckChecksums stores batch checksums of the given namespace for the next check.
+func (h *Handler) saveDBCheckChecksums(ctx context.Context, ns string, batches []dbCheckBatch) error {
+	db, err := h.b.Database(healthLogDatabase)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(dbCheckChecksumsCollection)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	arr := types.MakeArray(len(batches))
+	for _, b := range batches {
+		arr.Append(b.document())
+	}
+
+	doc := must.NotFail(types.NewDocument("_id", ns, "batches", arr))
+
+	_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{doc}})
+	if backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
+		_, err = c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{doc}})
+	}
+
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// dbCheck checks consistency of the given collections of the database, or all its collections if none are given,
+// and records results in the health log.
+// It returns the number of found inconsistencies.
+func (h *Handler) dbCheck(ctx context.Context, dbName string, cNames []string) (int, error) {
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	if len(cNames) == 0 {
+		cList, err := db.ListCollections(ctx, nil)
+		if err != nil {
+			if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+				return 0, nil
+			}
+
+			return 0, lazyerrors.Error(err)
+		}
+
+		for _, cInfo := range cList.Collections {
+			if dbName == healthLogDatabase && (cInfo.Name == healthLogCollection || cInfo.Name == dbCheckChecksumsCollection) {
+				continue
+			}
+
+			cNames = append(cNames, cInfo.Name)
+		}
+	}
+
+	var total int
+
+	for _, cName := range cNames {
+		n, err := h.dbCheckCollection(ctx, db, dbName, cName)
+		if err != nil {
+			return total, lazyerrors.Error(err)
+		}
+
+		total += n
+	}
+
+	return total, nil
+}
+
+// dbCheckRun records results of a single collection check in the health log.
+type dbCheckRun struct {
+	h               *Handler
+	ns              string
+	entries         []*types.Document
+	inconsistencies int
+}
+
+// log adds the entry to the health log.
+// Entries are written in batches.
+func (r *dbCheckRun) log(ctx context.Context, entry *types.Document) error {
+	r.entries = append(r.entries, entry)
+
+	if len(r.entries) < dbCheckBatchSize {
+		return nil
+	}
+
+	return r.flush(ctx)
+}
+
+// inconsistency records the inconsistency of the document with the given _id (that may be nil).
+func (r *dbCheckRun) inconsistency(ctx context.Context, msg string, id any) error {
+	r.inconsistencies++
+
+	return r.log(ctx, dbCheckInconsistency(r.ns, msg, id))
+}
+
+// flush writes buffered entries to the health log.
+func (r *dbCheckRun) flush(ctx context.Context) error {
+	entries := r.entries
+	r.entries = nil
+
+	return r.h.writeHealthLog(ctx, entries)
+}
+
+// dbCheckCollection checks that all documents of the collection can be read
+// and are consistent with the collection's indexes: `_id` is present and unique,
+// and values of unique indexes are unique.
+//
+// Documents are split into batches by `_id` hash.
+// Each batch is recorded in the health log with its checksum;
+// batches with checksums that differ from the previous check are recorded as warnings.
+// Each inconsistency is recorded as an error.
+// It returns the number of found inconsistencies.
+func (h *Handler) dbCheckCollection(ctx context.Context, db backends.Database, dbName, cName string) (int, error) {
+	ns := dbName + "." + cName
+
+	c, err := db.Collection(cName)
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	indexes, err := c.ListIndexes(ctx, nil)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+			return 0, nil
+		}
+
+		return 0, lazyerrors.Error(err)
+	}
+
+	// `_id` is checked separately for all backends
+	var unique []backends.IndexInfo
+
+	for _, index := range indexes.Indexes {
+		if index.Unique && index.Name != backends.DefaultIndexName {
+			unique = append(unique, index)
+		}
+	}
+
+	// the number of documents is used only to select the number of batches and scans
+	stats, err := c.Stats(ctx, &backends.CollectionStatsParams{Refresh: true})
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+			return 0, nil
+		}
+
+		return 0, lazyerrors.Error(err)
+	}
+
+	count := max(stats.CountDocuments, 0)
+
+	batches := make([]dbCheckBatch, dbCheckBatches(count))
+	partitions := dbCheckPartitions(count * int64(1+len(unique)))
+
+	r := &dbCheckRun{h: h, ns: ns}
+
+	if err = r.log(ctx, healthLogEntry(ns, "dbCheckStart", "info", "dbCheck start", new(types.Document))); err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	complete := true
+
+	for partition := range partitions {
+		var readErr error
+
+		if readErr, err = h.dbCheckScan(ctx, c, r, unique, batches, partitions, partition); err != nil {
+			return r.inconsistencies, lazyerrors.Error(err)
+		}
+
+		if readErr != nil {
+			// the rest of the collection can't be read
+			if err = r.inconsistency(ctx, fmt.Sprintf("failed to read document: %s", readErr), nil); err != nil {
+				return r.inconsistencies, lazyerrors.Error(err)
+			}
+
+			complete = false
+
+			break
+		}
+	}
+
+	var changed int
+
+	// checksums of partially read collections can't be compared
+	if complete {
+		prev, err := h.dbCheckChecksums(ctx, ns)
+		if err != nil {
+			return r.inconsistencies, lazyerrors.Error(err)
+		}
+
+		for i, b := range batches {
+			if b.count > 0 {
+				if err = r.log(ctx, b.entry(ns, i)); err != nil {
+					return r.inconsistencies, lazyerrors.Error(err)
+				}
+			}
+
+			// the number of batches changes when the collection grows or shrinks significantly
+			if len(prev) != len(batches) || prev[i] == b {
+				continue
+			}
+
+			changed++
+
+			data := must.NotFail(types.NewDocument("batch", int64(i), "previous", prev[i].document(), "current", b.document()))
+			entry := healthLogEntry(ns, "dbCheckBatch", "warning", "dbCheck batch changed since previous check", data)
+
+			if err = r.log(ctx, entry); err != nil {
+				return r.inconsistencies, lazyerrors.Error(err)
+			}
+		}
+
+		if err = h.saveDBCheckChecksums(ctx, ns, batches); err != nil {
+			return r.inconsistencies, lazyerrors.Error(err)
+		}
+	}
+
+	err = r.log(ctx, healthLogEntry(ns, "dbCheckStop", "info", "dbCheck stop", must.NotFail(types.NewDocument(
+		"success", r.inconsistencies == 0,
+		"inconsistencies", int64(r.inconsistencies),
+		"changedBatches", int64(changed),
+	))))
+	if err != nil {
+		return r.inconsistencies, lazyerrors.Error(err)
+	}
+
+	if err = r.flush(ctx); err != nil {
+		return r.inconsistencies, lazyerrors.Error(err)
+	}
+
+	return r.inconsistencies, nil
+}
+
+// dbCheckScan reads all documents of the collection once, checking keys of the given partition for duplicates.
+// The first scan also adds documents to batches.
+//
+// It returns the first read error separately; the rest of the collection is not scanned in that case.
+func (h *Handler) dbCheckScan(ctx context.Context, c backends.Collection, r *dbCheckRun, unique []backends.IndexInfo, batches []dbCheckBatch, partitions, partition uint64) (readErr, err error) { //nolint:lll // for readability
+	first := partition == 0
+	keys := newDBCheckKeys(1+len(unique), partitions, partition)
+
+	qr, err := c.Query(ctx, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	for {
+		_, doc, err := qr.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return nil, nil
+		}
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			return err, nil
+		}
+
+		id, _ := doc.Get("_id")
+
+		var idHash [md5.Size]byte
+		if id != nil {
+			idHash = dbCheckKeyHash(id)
+		}
+
+		if first {
+			data, err := sjson.Marshal(doc)
+			if err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			// documents without _id are added to the first batch
+			batches[binary.BigEndian.Uint64(idHash[:8])%uint64(len(batches))].add(data)
+		}
+
+		switch {
+		case id == nil:
+			if first {
+				err = r.inconsistency(ctx, "document has no _id", nil)
+			}
+
+		case !keys.add(0, idHash):
+			err = r.inconsistency(ctx, "found duplicate _id", id)
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		for i, index := range unique {
+			key := uniqueIndexKey(doc, &index)
+			if key == nil || keys.add(i+1, dbCheckKeyHash(key)) {
+				continue
+			}
+
+			msg := fmt.Sprintf("found duplicate key in unique index %s", index.Name)
+			if err = r.inconsistency(ctx, msg, id); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+	}
+}
+
+// uniqueIndexKey returns values of the unique index fields of the document,
+// or nil if some of them are missing (backends do not enforce uniqueness in that case).
+func uniqueIndexKey(doc *types.Document, index *backends.IndexInfo) *types.Array {
+	key := types.MakeArray(len(index.Key))
+
+	for _, pair := range index.Key {
+		path, err := types.NewPathFromString(pair.Field)
+		if err != nil {
+			return nil
+		}
+
+		v, err := doc.GetByPath(path)
+		if err != nil {
+			return nil
+		}
+
+		key.Append(v)
+	}
+
+	return key
+}
+
+// startDBCheck runs dbCheck of the given collections of the database in the background.
+// It is visible in `currentOp` command's output while running.
+func (h *Handler) startDBCheck(dbName string, cNames []string, command *types.Document) {
+	op := h.currentOps.Start(dbName+".$cmd", command)
+
+	h.wg.Add(1)
+
+	go func() {
+		defer h.wg.Done()
+		defer op.Finish()
+
+		ctx, cancel := h.dbCheckContext()
+		defer cancel()
+
+		h.runDBCheckJob(ctx, dbName, cNames)
+	}()
+}
+
+// dbCheckContext returns a context that is canceled when the handler is closed.
+func (h *Handler) dbCheckContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		select {
+		case <-h.dbCheckStop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, cancel
+}
+
+// runDBCheckJob runs dbCheck of the given collections of the database and logs results.
+func (h *Handler) runDBCheckJob(ctx context.Context, dbName string, cNames []string) {
+	connInfo := conninfo.New()
+	connInfo.SetBypassBackendAuth()
+	ctx = conninfo.Ctx(ctx, connInfo)
+
+	n, err := h.dbCheck(ctx, dbName, cNames)
+	if err != nil {
+		h.L.Error("Failed to check database consistency.", zap.String("db", dbName), zap.Error(err))
+		return
+	}
+
+	if n > 0 {
+		h.L.Warn(
+			"Database inconsistencies found, see local.system.healthlog for details.",
+			zap.String("db", dbName), zap.Int("inconsistencies", n),
+		)
+	}
+}
+
+// runDBCheck checks consistency of all databases according to the given interval.
+func (h *Handler) runDBCheck() {
+	if h.DBCheckInterval <= 0 {
+		h.L.Info("Scheduled consistency checks disabled.")
+		return
+	}
+
+	h.L.Info("Scheduled consistency checks enabled.", zap.Duration("interval", h.DBCheckInterval))
+
+	ticker := time.NewTicker(h.DBCheckInterval)
+	defer ticker.Stop()
+
+	ctx, cancel := h.dbCheckContext()
+	defer cancel()
+
+	for {
+		select {
+		case <-ticker.C:
+			connInfo := conninfo.New()
+			connInfo.SetBypassBackendAuth()
+
+			dbList, err := h.b.ListDatabases(conninfo.Ctx(ctx, connInfo), nil)
+			if err != nil {
+				h.L.Error("Failed to list databases for consistency checks.", zap.Error(err))
+				continue
+			}
+
+			for _, dbInfo := range dbList.Databases {
+				if ctx.Err() != nil {
+					break
+				}
+
+				h.runDBCheckJob(ctx, dbInfo.Name, nil)
+			}
+
+		case <-h.dbCheckStop:
+			h.L.Info("Scheduled consistency checks stopped.")
+			return
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestDBCheckUniqueKeys(t *testing.T) {
+	t.Parallel()
+
+	index := &backends.IndexInfo{
+		Name:   "a_1_b.c_-1",
+		Key:    []backends.IndexKeyPair{{Field: "a"}, {Field: "b.c", Descending: true}},
+		Unique: true,
+	}
+
+	doc1 := must.NotFail(types.NewDocument("_id", int32(1), "a", "x", "b", must.NotFail(types.NewDocument("c", int32(1)))))
+	doc2 := must.NotFail(types.NewDocument("_id", int32(2), "a", "x", "b", must.NotFail(types.NewDocument("c", int32(2)))))
+	doc3 := must.NotFail(types.NewDocument("_id", int32(3), "a", "x", "b", must.NotFail(types.NewDocument("c", int32(1)))))
+	doc4 := must.NotFail(types.NewDocument("_id", int32(4), "a", "x"))
+
+	keys := newDBCheckKeys(2, 1, 0)
+
+	assert.True(t, keys.add(1, dbCheckKeyHash(uniqueIndexKey(doc1, index))))
+	assert.True(t, keys.add(1, dbCheckKeyHash(uniqueIndexKey(doc2, index))))
+	assert.False(t, keys.add(1, dbCheckKeyHash(uniqueIndexKey(doc3, index))))
+
+	// backends do not enforce uniqueness if some fields are missing
+	assert.Nil(t, uniqueIndexKey(doc4, index))
+
+	assert.True(t, keys.add(0, dbCheckKeyHash(int32(1))))
+	assert.True(t, keys.add(0, dbCheckKeyHash("1")))
+	assert.False(t, keys.add(0, dbCheckKeyHash(int32(1))))
+}
+
+func TestDBCheckKeysPartitions(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, uint64(1), dbCheckPartitions(0))
+	assert.Equal(t, uint64(1), dbCheckPartitions(dbCheckMaxKeys))
+	assert.Equal(t, uint64(2), dbCheckPartitions(dbCheckMaxKeys+1))
+
+	// each key is tracked in exactly one partition
+	const partitions = 4
+
+	var tracked int
+
+	for partition := range uint64(partitions) {
+		keys := newDBCheckKeys(1, partitions, partition)
+
+		for i := range 100 {
+			keys.add(0, dbCheckKeyHash(int32(i)))
+		}
+
+		tracked += len(keys.sets[0])
+
+		// duplicates are still found in their partition
+		for i := range 100 {
+			h := dbCheckKeyHash(int32(i))
+			if _, ok := keys.sets[0][h]; ok {
+				assert.False(t, keys.add(0, h))
+			}
+		}
+	}
+
+	assert.Equal(t, 100, tracked)
+}
+
+func TestDBCheckBatch(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 1, dbCheckBatches(0))
+	assert.Equal(t, 1, dbCheckBatches(dbCheckBatchSize))
+	assert.Equal(t, 2, dbCheckBatches(dbCheckBatchSize+1))
+	assert.Equal(t, 4, dbCheckBatches(3*dbCheckBatchSize))
+
+	var b1, b2 dbCheckBatch
+
+	b1.add([]byte("a"))
+	b1.add([]byte("b"))
+
+	// checksum does not depend on the order of documents
+	b2.add([]byte("b"))
+	b2.add([]byte("a"))
+	assert.Equal(t, b1, b2)
+
+	parsed, ok := parseDBCheckBatch(b1.document())
+	require.True(t, ok)
+	assert.Equal(t, b1, parsed)
+
+	b2.add([]byte("c"))
+	assert.NotEqual(t, b1, b2)
+}
+
+func TestDBCheckCollection(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := sqlite.NewBackend(&sqlite.NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	h := &Handler{NewOpts: &NewOpts{L: testutil.Logger(t)}, b: b}
+
+	dbName, cName := testutil.DatabaseName(t), testutil.CollectionName(t)
+	ns := dbName + "." + cName
+
+	db, err := b.Database(dbName)
+	require.NoError(t, err)
+
+	c, err := db.Collection(cName)
+	require.NoError(t, err)
+
+	_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "v", "a")),
+		must.NotFail(types.NewDocument("_id", int32(2), "v", "b")),
+	}})
+	require.NoError(t, err)
+
+	// changedBatches of the last dbCheckStop entry
+	changed := func() int64 {
+		t.Helper()
+
+		n, err := h.dbCheckCollection(ctx, db, dbName, cName)
+		require.NoError(t, err)
+		require.Zero(t, n)
+
+		hc, err := must.NotFail(b.Database(healthLogDatabase)).Collection(healthLogCollection)
+		require.NoError(t, err)
+
+		qr, err := hc.Query(ctx, nil)
+		require.NoError(t, err)
+
+		defer qr.Iter.Close()
+
+		var res int64
+
+		for {
+			_, doc, err := qr.Iter.Next()
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			require.NoError(t, err)
+
+			if must.NotFail(doc.Get("namespace")) == ns && must.NotFail(doc.Get("operation")) == "dbCheckStop" {
+				data := must.NotFail(doc.Get("data")).(*types.Document)
+				res = must.NotFail(data.Get("changedBatches")).(int64)
+			}
+		}
+
+		return res
+	}
+
+	assert.Zero(t, changed())
+	assert.Zero(t, changed())
+
+	_, err = c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(2), "v", "c")),
+	}})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), changed())
+	assert.Zero(t, changed())
+
+	list, err := must.NotFail(b.Database(healthLogDatabase)).ListCollections(ctx, &backends.ListCollectionsParams{
+		Name: healthLogCollection,
+	})
+	require.NoError(t, err)
+	require.Len(t, list.Collections, 1)
+	assert.Equal(t, int64(healthLogSize), list.Collections[0].CappedSize)
+}
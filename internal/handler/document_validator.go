@@ -0,0 +1,39 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// documentValidator returns document validator for the given collection.
+//
+// It returns nil if the collection does not exist, does not have a validator, or validation is off.
+func (h *Handler) documentValidator(ctx context.Context, db backends.Database, collection string) (*common.DocumentValidator, error) { //nolint:lll // for readability
+	res, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: collection})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if len(res.Collections) == 0 {
+		return nil, nil
+	}
+
+	return common.NewDocumentValidator(&res.Collections[0], h.L), nil
+}
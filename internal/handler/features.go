@@ -0,0 +1,150 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"slices"
+	"sort"
+
+	"go.uber.org/zap"
+	"golang.org/x/exp/maps"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/operators/accumulators"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/stages"
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// features describes commands, aggregation stages, operators, and index types
+// supported by the handler with the current backend.
+//
+// Each field is a sorted list of names.
+type features struct {
+	commands             []string
+	aggregationStages    []string
+	aggregationOperators []string
+	accumulators         []string
+	queryOperators       []string
+	updateOperators      []string
+	projectionOperators  []string
+	indexTypes           []string
+}
+
+// searchBackends contains backends that support search commands, stages, and indexes.
+var searchBackends = []string{"postgresql"}
+
+// searchCommands contains commands that are available only with searchBackends.
+var searchCommands = []string{"createSearchIndexes", "dropSearchIndex", "updateSearchIndex"}
+
+// features returns features supported by the handler.
+func (h *Handler) features() *features {
+	search := slices.Contains(searchBackends, h.BackendName)
+
+	var commands []string
+
+	for name, cmd := range h.Commands() {
+		// hidden commands are old variants of other commands
+		if cmd.Help == "" {
+			continue
+		}
+
+		if !search && slices.Contains(searchCommands, name) {
+			continue
+		}
+
+		commands = append(commands, name)
+	}
+
+	// stages handled by MsgAggregate itself
+	aggregationStages := append(maps.Keys(stages.Stages), "$queryStats")
+
+	indexTypes := []string{"ascending", "compound", "descending", "unique"}
+
+	if search {
+		aggregationStages = append(aggregationStages, "$listSearchIndexes", "$search", "$vectorSearch")
+		indexTypes = append(indexTypes, "search", "vectorSearch")
+	}
+
+	res := &features{
+		commands:             commands,
+		aggregationStages:    aggregationStages,
+		aggregationOperators: maps.Keys(operators.Operators),
+		accumulators:         maps.Keys(accumulators.Accumulators),
+		queryOperators:       slices.Clone(common.FilterOperators),
+		updateOperators:      slices.Clone(common.UpdateOperators),
+
+		// only positional projection is supported; see common.ValidateProjection
+		projectionOperators: []string{"$"},
+
+		indexTypes: indexTypes,
+	}
+
+	for _, l := range res.lists() {
+		sort.Strings(l.names)
+	}
+
+	return res
+}
+
+// featureList is a named list of features.
+type featureList struct {
+	name  string
+	names []string
+}
+
+// lists returns all feature lists in the order of the `supportedFeatures` command output.
+func (f *features) lists() []featureList {
+	return []featureList{
+		{"commands", f.commands},
+		{"aggregationStages", f.aggregationStages},
+		{"aggregationOperators", f.aggregationOperators},
+		{"accumulators", f.accumulators},
+		{"queryOperators", f.queryOperators},
+		{"updateOperators", f.updateOperators},
+		{"projectionOperators", f.projectionOperators},
+		{"indexTypes", f.indexTypes},
+	}
+}
+
+// document returns features as a document with an array of names for each list.
+func (f *features) document() *types.Document {
+	doc := new(types.Document)
+
+	for _, l := range f.lists() {
+		arr := types.MakeArray(len(l.names))
+		for _, name := range l.names {
+			arr.Append(name)
+		}
+
+		doc.Set(l.name, arr)
+	}
+
+	return doc
+}
+
+// logFeatures logs the summary of supported features.
+func (h *Handler) logFeatures() {
+	lists := h.features().lists()
+
+	fields := make([]zap.Field, 0, len(lists)+1)
+	fields = append(fields, zap.String("backend", h.BackendName))
+
+	for _, l := range lists {
+		fields = append(fields, zap.Int(l.name, len(l.names)))
+	}
+
+	h.L.Info("Supported features; use supportedFeatures command for details.", fields...)
+}
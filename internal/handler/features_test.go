@@ -0,0 +1,135 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestFeatures(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		search bool
+	}{
+		"sqlite":     {search: false},
+		"postgresql": {search: true},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			h := &Handler{NewOpts: &NewOpts{BackendName: name}}
+			h.initCommands()
+
+			f := h.features()
+
+			for _, l := range f.lists() {
+				assert.NotEmpty(t, l.names, l.name)
+				assert.True(t, sort.StringsAreSorted(l.names), l.name)
+			}
+
+			assert.Contains(t, f.commands, "find")
+			assert.Contains(t, f.commands, "supportedFeatures")
+			assert.NotContains(t, f.commands, "buildinfo", "hidden commands should not be listed")
+			assert.Contains(t, f.aggregationStages, "$match")
+			assert.Contains(t, f.aggregationStages, "$queryStats")
+
+			if tc.search {
+				assert.Contains(t, f.aggregationStages, "$search")
+				assert.Contains(t, f.indexTypes, "vectorSearch")
+				assert.Contains(t, f.commands, "createSearchIndexes")
+			} else {
+				assert.NotContains(t, f.aggregationStages, "$search")
+				assert.NotContains(t, f.indexTypes, "vectorSearch")
+				assert.NotContains(t, f.commands, "createSearchIndexes")
+			}
+
+			doc := f.document()
+			assert.Equal(t, len(f.lists()), doc.Len())
+		})
+	}
+}
+
+// TestFeaturesOperators checks that all listed query and update operators are actually supported.
+func TestFeaturesOperators(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Query", func(t *testing.T) {
+		t.Parallel()
+
+		doc := must.NotFail(types.NewDocument("a", must.NotFail(types.NewArray(int32(1)))))
+		sub := func(pairs ...any) *types.Document { return must.NotFail(types.NewDocument(pairs...)) }
+		arr := func(values ...any) *types.Array { return must.NotFail(types.NewArray(values...)) }
+
+		filters := map[string]*types.Document{
+			"$all":          sub("a", sub("$all", arr(int32(1)))),
+			"$and":          sub("$and", arr(sub("a", int32(1)))),
+			"$bitsAllClear": sub("a", sub("$bitsAllClear", int32(2))),
+			"$bitsAllSet":   sub("a", sub("$bitsAllSet", int32(1))),
+			"$bitsAnyClear": sub("a", sub("$bitsAnyClear", int32(2))),
+			"$bitsAnySet":   sub("a", sub("$bitsAnySet", int32(1))),
+			"$comment":      sub("$comment", "test"),
+			"$elemMatch":    sub("a", sub("$elemMatch", sub("$gt", int32(0)))),
+			"$eq":           sub("a", sub("$eq", int32(1))),
+			"$exists":       sub("a", sub("$exists", true)),
+			"$expr":         sub("$expr", sub("$type", "$a")),
+			"$gt":           sub("a", sub("$gt", int32(0))),
+			"$gte":          sub("a", sub("$gte", int32(1))),
+			"$in":           sub("a", sub("$in", arr(int32(1)))),
+			"$lt":           sub("a", sub("$lt", int32(2))),
+			"$lte":          sub("a", sub("$lte", int32(1))),
+			"$mod":          sub("a", sub("$mod", arr(int32(2), int32(1)))),
+			"$ne":           sub("a", sub("$ne", int32(2))),
+			"$nin":          sub("a", sub("$nin", arr(int32(2)))),
+			"$nor":          sub("$nor", arr(sub("a", int32(2)))),
+			"$not":          sub("a", sub("$not", sub("$eq", int32(2)))),
+			"$or":           sub("$or", arr(sub("a", int32(1)))),
+			"$regex":        sub("a", sub("$regex", "^b")),
+			"$size":         sub("a", sub("$size", int32(1))),
+			"$type":         sub("a", sub("$type", "int")),
+		}
+
+		expected := maps.Keys(filters)
+		sort.Strings(expected)
+		require.Equal(t, expected, common.FilterOperators)
+
+		for _, op := range common.FilterOperators {
+			_, err := common.FilterDocument(doc, filters[op])
+			assert.NoError(t, err, op)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		t.Parallel()
+
+		for _, op := range common.UpdateOperators {
+			update := must.NotFail(types.NewDocument(op, must.NotFail(types.NewDocument("a", int32(1)))))
+
+			ok, err := common.HasSupportedUpdateModifiers("update", update)
+			require.NoError(t, err, op)
+			assert.True(t, ok, op)
+		}
+	})
+}
@@ -19,22 +19,36 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/backends/decorators/oplog"
+	"github.com/FerretDB/FerretDB/internal/backends/decorators/timeseries"
+	"github.com/FerretDB/FerretDB/internal/backends/decorators/writehook"
+	"github.com/FerretDB/FerretDB/internal/backends/decorators/writelock"
+	"github.com/FerretDB/FerretDB/internal/backends/decorators/writeretry"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/connmetrics"
 	"github.com/FerretDB/FerretDB/internal/clientconn/cursor"
+	"github.com/FerretDB/FerretDB/internal/handler/currentop"
+	"github.com/FerretDB/FerretDB/internal/handler/querycache"
+	"github.com/FerretDB/FerretDB/internal/handler/querystats"
+	"github.com/FerretDB/FerretDB/internal/handler/top"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/coalesce"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/wire"
 )
 
 // Parts of Prometheus metric names.
@@ -54,13 +68,42 @@ type Handler struct {
 
 	b backends.Backend
 
-	cursors  *cursor.Registry
-	commands map[string]command
-	wg       sync.WaitGroup
+	cursors         *cursor.Registry
+	queryStats      *querystats.Registry
+	queryCache      *querycache.Cache // nil if disabled
+	top             *top.Registry
+	currentOps      *currentop.Registry
+	conns           *conninfo.Registry
+	commands        map[string]command
+	parameters      map[string]*parameter
+	templates       collectionTemplates
+	archivePolicies archivePolicies
+	quotas          quotas
+	fsyncLock       *writelock.Lock
+	wg              sync.WaitGroup
+
+	slowQueryL         *zap.Logger
+	slowQueryThreshold atomic.Int64 // time.Duration
+
+	prefetchMemory atomic.Int64 // bytes reserved for prefetched batches
+
+	// runtime values of options that could be changed with `setParameter`
+	disablePushdown      atomic.Bool
+	enableNestedPushdown atomic.Bool
+	startupLogLevel      zapcore.Level
 
 	cappedCleanupStop             chan struct{}
 	cleanupCappedCollectionsDocs  *prometheus.CounterVec
 	cleanupCappedCollectionsBytes *prometheus.CounterVec
+
+	incCoalescer        *coalesce.Coalescer[incKey, *incOp] // nil if disabled
+	incCoalescedUpdates prometheus.Counter
+	incCoalescedWrites  prometheus.Counter
+
+	archiverStop chan struct{}
+	archivedDocs *prometheus.CounterVec
+
+	dbCheckStop chan struct{}
 }
 
 // NewOpts represents handler configuration.
@@ -68,6 +111,7 @@ type Handler struct {
 //nolint:vet // for readability
 type NewOpts struct {
 	Backend     backends.Backend
+	BackendName string // registered handler name, such as "postgresql"
 	TCPHost     string
 	ReplSetName string
 
@@ -75,6 +119,50 @@ type NewOpts struct {
 	ConnMetrics   *connmetrics.ConnMetrics
 	StateProvider *state.Provider
 
+	// queries slower than that are logged; zero disables the slow query log
+	SlowQueryThreshold time.Duration
+
+	// writes are held for up to that duration while the backend is unavailable; zero disables that
+	WriteRetryTimeout   time.Duration
+	WriteRetryMaxWrites int
+
+	// policy of implicit collection creation on the first write, with per-database overrides;
+	// see ImplicitCollectionPolicies
+	ImplicitCollectionPolicy           string
+	ImplicitCollectionDatabasePolicies map[string]string
+	ImplicitCollectionTemplate         string
+
+	// commands without maxTimeMS are limited to that duration; zero disables that
+	DefaultMaxTime time.Duration
+
+	// next cursor batches are prefetched in the background using up to that many bytes in total;
+	// zero disables prefetching
+	CursorPrefetchMemory int64
+
+	// results of `find` commands are cached using up to that many bytes in total, for up to the given duration;
+	// zero size disables the cache, zero duration keeps results until they are invalidated by writes
+	QueryCacheSize int64
+	QueryCacheTTL  time.Duration
+
+	// unordered insert, update, and delete batches are executed with up to that many concurrent writes;
+	// zero is replaced with the number of CPUs, one disables that
+	BulkWriteConcurrency int
+
+	// `$inc` updates of the same document within that window are written together; zero disables that
+	IncCoalescingWindow time.Duration
+
+	// archive policies are applied with that interval; zero disables background archiving
+	ArchiveInterval time.Duration
+
+	// consistency of all databases is checked with that interval; zero disables scheduled checks
+	DBCheckInterval time.Duration
+
+	// maximum BSON object size and maximum message size reported to clients and enforced for requests;
+	// zero values are replaced with defaults: types.MaxDocumentLen and
+	// the larger of wire.MaxMsgLen and three maximum BSON object sizes
+	MaxBSONObjectSize int32
+	MaxMessageSize    int32
+
 	// test options
 	DisablePushdown         bool
 	EnableNestedPushdown    bool
@@ -85,7 +173,74 @@ type NewOpts struct {
 
 // New returns a new handler.
 func New(opts *NewOpts) (*Handler, error) {
-	b := oplog.NewBackend(opts.Backend, opts.L.Named("oplog"))
+	b := opts.Backend
+
+	if opts.WriteRetryTimeout > 0 {
+		if opts.WriteRetryMaxWrites <= 0 {
+			return nil, fmt.Errorf(
+				"maximum number of held writes must be positive, but %d given",
+				opts.WriteRetryMaxWrites,
+			)
+		}
+
+		b = writeretry.NewBackend(b, &writeretry.Opts{
+			L:         opts.L.Named("writeretry"),
+			Timeout:   opts.WriteRetryTimeout,
+			MaxWrites: opts.WriteRetryMaxWrites,
+		})
+	}
+
+	b = oplog.NewBackend(b, opts.L.Named("oplog"))
+	b = timeseries.NewBackend(b)
+
+	// the hook sees collection names as used by the handler (for example, time series collections, not their buckets),
+	// but not writes to the oplog made by the oplog decorator; see cacheableFind
+	queryCache := querycache.New(opts.QueryCacheSize, opts.QueryCacheTTL)
+	if queryCache != nil {
+		b = writehook.NewBackend(b, queryCache.Invalidate)
+	}
+
+	// all writes, including ones made by the handler itself, are blocked by `fsync` with `lock: true`
+	fsyncLock := writelock.NewLock()
+	b = writelock.NewBackend(b, fsyncLock)
+
+	if opts.ImplicitCollectionPolicy != "" {
+		if err := validateImplicitCollectionPolicy(opts.ImplicitCollectionPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	for db, policy := range opts.ImplicitCollectionDatabasePolicies {
+		if err := validateImplicitCollectionPolicy(policy); err != nil {
+			return nil, fmt.Errorf("database %q: %w", db, err)
+		}
+	}
+
+	if opts.BulkWriteConcurrency <= 0 {
+		opts.BulkWriteConcurrency = runtime.GOMAXPROCS(-1)
+	}
+
+	if opts.MaxBSONObjectSize == 0 {
+		opts.MaxBSONObjectSize = types.MaxDocumentLen
+	}
+
+	if opts.MaxBSONObjectSize < 0 || opts.MaxBSONObjectSize > types.MaxDocumentLenLimit {
+		return nil, fmt.Errorf(
+			"maximum BSON object size must be in range (0, %d], but %d given",
+			types.MaxDocumentLenLimit, opts.MaxBSONObjectSize,
+		)
+	}
+
+	if opts.MaxMessageSize == 0 {
+		opts.MaxMessageSize = max(wire.MaxMsgLen, 3*opts.MaxBSONObjectSize)
+	}
+
+	if opts.MaxMessageSize < opts.MaxBSONObjectSize || opts.MaxMessageSize > wire.MaxMsgLenLimit {
+		return nil, fmt.Errorf(
+			"maximum message size must be in range [%d, %d], but %d given",
+			opts.MaxBSONObjectSize, wire.MaxMsgLenLimit, opts.MaxMessageSize,
+		)
+	}
 
 	if opts.CappedCleanupPercentage >= 100 || opts.CappedCleanupPercentage <= 0 {
 		return nil, fmt.Errorf(
@@ -95,9 +250,16 @@ func New(opts *NewOpts) (*Handler, error) {
 	}
 
 	h := &Handler{
-		b:       b,
-		NewOpts: opts,
-		cursors: cursor.NewRegistry(opts.L.Named("cursors")),
+		b:          b,
+		NewOpts:    opts,
+		cursors:    cursor.NewRegistry(opts.L.Named("cursors")),
+		queryStats: querystats.NewRegistry(0),
+		queryCache: queryCache,
+		fsyncLock:  fsyncLock,
+		top:        top.NewRegistry(),
+		currentOps: currentop.NewRegistry(),
+		conns:      conninfo.NewRegistry(),
+		slowQueryL: opts.L.Named("slow"),
 
 		cappedCleanupStop: make(chan struct{}),
 		cleanupCappedCollectionsDocs: prometheus.NewCounterVec(
@@ -118,11 +280,51 @@ func New(opts *NewOpts) (*Handler, error) {
 			},
 			[]string{"db", "collection"},
 		),
+		incCoalescedUpdates: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "inc_coalesced_updates_total",
+				Help:      "Total number of coalesced $inc updates.",
+			},
+		),
+		incCoalescedWrites: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "inc_coalesced_writes_total",
+				Help:      "Total number of document writes for coalesced $inc updates.",
+			},
+		),
+
+		archiverStop: make(chan struct{}),
+		archivedDocs: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "archived_docs",
+				Help:      "Total number of documents moved to archive collections.",
+			},
+			[]string{"db", "collection"},
+		),
+
+		dbCheckStop: make(chan struct{}),
+	}
+
+	if opts.IncCoalescingWindow > 0 {
+		h.incCoalescer = coalesce.New(opts.IncCoalescingWindow, incCoalescingMaxOps, h.flushInc)
 	}
 
+	h.SetSlowQueryThreshold(opts.SlowQueryThreshold)
+	h.disablePushdown.Store(opts.DisablePushdown)
+	h.enableNestedPushdown.Store(opts.EnableNestedPushdown)
+	h.startupLogLevel = logging.Level()
+
 	h.initCommands()
+	h.initParameters()
+	h.logFeatures()
 
-	h.wg.Add(1)
+	h.wg.Add(3)
 
 	go func() {
 		defer h.wg.Done()
@@ -130,6 +332,18 @@ func New(opts *NewOpts) (*Handler, error) {
 		h.runCappedCleanup()
 	}()
 
+	go func() {
+		defer h.wg.Done()
+
+		h.runArchiver()
+	}()
+
+	go func() {
+		defer h.wg.Done()
+
+		h.runDBCheck()
+	}()
+
 	return h, nil
 }
 
@@ -164,23 +378,63 @@ func (h *Handler) runCappedCleanup() {
 func (h *Handler) Close() {
 	h.cursors.Close()
 	close(h.cappedCleanupStop)
+	close(h.archiverStop)
+	close(h.dbCheckStop)
+
+	// unblock background writes so they could be stopped
+	for {
+		if _, ok := h.fsyncLock.Unlock(); !ok {
+			break
+		}
+	}
+
 	h.wg.Wait()
 }
 
+// Conns returns the registry of client connections.
+//
+// Connections should be added to it when they are opened and removed when they are closed.
+func (h *Handler) Conns() *conninfo.Registry {
+	return h.conns
+}
+
+// CheckBackend returns an error if the backend is not available.
+//
+// It is used by health and readiness probes.
+func (h *Handler) CheckBackend(ctx context.Context) error {
+	connInfo := conninfo.New()
+	connInfo.SetBypassBackendAuth()
+	ctx = conninfo.Ctx(ctx, connInfo)
+
+	if _, err := h.b.Status(ctx, nil); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
 // Describe implements prometheus.Collector interface.
 func (h *Handler) Describe(ch chan<- *prometheus.Desc) {
 	h.b.Describe(ch)
 	h.cursors.Describe(ch)
+	h.queryCache.Describe(ch)
 	h.cleanupCappedCollectionsDocs.Describe(ch)
 	h.cleanupCappedCollectionsBytes.Describe(ch)
+	h.incCoalescedUpdates.Describe(ch)
+	h.incCoalescedWrites.Describe(ch)
+	h.archivedDocs.Describe(ch)
 }
 
 // Collect implements prometheus.Collector interface.
 func (h *Handler) Collect(ch chan<- prometheus.Metric) {
 	h.b.Collect(ch)
 	h.cursors.Collect(ch)
+	h.queryCache.Collect(ch)
 	h.cleanupCappedCollectionsDocs.Collect(ch)
 	h.cleanupCappedCollectionsBytes.Collect(ch)
+	h.incCoalescedUpdates.Collect(ch)
+	h.incCoalescedWrites.Collect(ch)
+	h.archivedDocs.Collect(ch)
 }
 
 // cleanupAllCappedCollections drops the given percent of documents from all capped collections.
@@ -17,6 +17,7 @@ package handlererrors
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/wire"
@@ -36,6 +37,9 @@ const (
 	// ErrBadValue indicates wrong input.
 	ErrBadValue = ErrorCode(2) // BadValue
 
+	// ErrHostUnreachable indicates that the backend is unavailable.
+	ErrHostUnreachable = ErrorCode(6) // HostUnreachable
+
 	// ErrFailedToParse indicates user input parsing failure.
 	ErrFailedToParse = ErrorCode(9) // FailedToParse
 
@@ -114,6 +118,9 @@ const (
 	// ErrDocumentValidationFailure indicates that document validation failed.
 	ErrDocumentValidationFailure = ErrorCode(121) // DocumentValidationFailure
 
+	// ErrJSInterpreterFailure indicates that server-side JavaScript code failed or exceeded limits.
+	ErrJSInterpreterFailure = ErrorCode(139) // JSInterpreterFailure
+
 	// ErrInvalidIndexSpecificationOption indicates that the index option is invalid.
 	ErrInvalidIndexSpecificationOption = ErrorCode(197) // InvalidIndexSpecificationOption
 
@@ -129,12 +136,21 @@ const (
 	// ErrMechanismUnavailable indicates that the authentication mechanism is unavailable.
 	ErrMechanismUnavailable = ErrorCode(334)
 
+	// ErrTemporarilyUnavailable indicates that the operation was rejected because the server is overloaded.
+	ErrTemporarilyUnavailable = ErrorCode(365) // TemporarilyUnavailable
+
+	// ErrBSONObjectTooLarge indicates that BSON object exceeds the maximum size.
+	ErrBSONObjectTooLarge = ErrorCode(10334) // BSONObjectTooLarge
+
 	// ErrIndexesWrongType indicates that indexes parameter has wrong type.
 	ErrIndexesWrongType = ErrorCode(10065) // Location10065
 
 	// ErrDuplicateKeyInsert indicates duplicate key violation on inserting document.
 	ErrDuplicateKeyInsert = ErrorCode(11000) // DuplicateKey
 
+	// ErrQuotaExceeded indicates that a write would exceed the database or collection quota.
+	ErrQuotaExceeded = ErrorCode(12501) // QuotaExceeded
+
 	// ErrSetBadExpression indicates set expression is not object.
 	ErrSetBadExpression = ErrorCode(40272) // Location40272
 
@@ -228,6 +244,9 @@ const (
 	// while projection document already marked as inclusion.
 	ErrProjectionExIn = ErrorCode(31254) // Location31254
 
+	// ErrJavaScriptDisabled indicates that server-side JavaScript execution is not available.
+	ErrJavaScriptDisabled = ErrorCode(31264) // Location31264
+
 	// ErrAggregatePositionalProject indicates that positional projection cannot be used in aggregation.
 	ErrAggregatePositionalProject = ErrorCode(31324) // Location31324
 
@@ -375,6 +394,18 @@ func ProtocolError(err error) ProtoErr {
 		return writeErr
 	}
 
+	var tooLargeErr *wire.DocumentTooLargeError
+	if errors.As(err, &tooLargeErr) {
+		//nolint:errorlint // only *CommandError could be returned
+		return NewCommandErrorMsg(
+			ErrBSONObjectTooLarge,
+			fmt.Sprintf(
+				"BSONObj size: %d (0x%X) is invalid. Size must be between 0 and %d(%dMB)",
+				tooLargeErr.Len, tooLargeErr.Len, tooLargeErr.MaxLen, tooLargeErr.MaxLen/(1024*1024),
+			),
+		).(*CommandError)
+	}
+
 	var validationErr *wire.ValidationError
 	if errors.As(err, &validationErr) {
 		//nolint:errorlint // only *CommandError could be returned
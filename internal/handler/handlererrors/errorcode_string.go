@@ -11,6 +11,7 @@ func _() {
 	_ = x[errUnset-0]
 	_ = x[errInternalError-1]
 	_ = x[ErrBadValue-2]
+	_ = x[ErrHostUnreachable-6]
 	_ = x[ErrFailedToParse-9]
 	_ = x[ErrUserNotFound-11]
 	_ = x[ErrUnauthorized-13]
@@ -37,13 +38,17 @@ func _() {
 	_ = x[ErrIndexKeySpecsConflict-86]
 	_ = x[ErrOperationFailed-96]
 	_ = x[ErrDocumentValidationFailure-121]
+	_ = x[ErrJSInterpreterFailure-139]
 	_ = x[ErrInvalidIndexSpecificationOption-197]
 	_ = x[ErrInvalidPipelineOperator-168]
 	_ = x[ErrClientMetadataCannotBeMutated-186]
 	_ = x[ErrNotImplemented-238]
 	_ = x[ErrMechanismUnavailable-334]
+	_ = x[ErrTemporarilyUnavailable-365]
+	_ = x[ErrBSONObjectTooLarge-10334]
 	_ = x[ErrIndexesWrongType-10065]
 	_ = x[ErrDuplicateKeyInsert-11000]
+	_ = x[ErrQuotaExceeded-12501]
 	_ = x[ErrSetBadExpression-40272]
 	_ = x[ErrStageGroupInvalidFields-15947]
 	_ = x[ErrStageGroupID-15948]
@@ -74,6 +79,7 @@ func _() {
 	_ = x[ErrUnsetPathOverwrite-31250]
 	_ = x[ErrProjectionInEx-31253]
 	_ = x[ErrProjectionExIn-31254]
+	_ = x[ErrJavaScriptDisabled-31264]
 	_ = x[ErrAggregatePositionalProject-31324]
 	_ = x[ErrAggregateInvalidExpression-31325]
 	_ = x[ErrWrongPositionalOperatorLocation-31394]
@@ -111,110 +117,116 @@ func _() {
 	_ = x[ErrStageIndexedStringVectorDuplicate-7582300]
 }
 
-const _ErrorCode_name = "UnsetInternalErrorBadValueFailedToParseUserNotFoundUnauthorizedTypeMismatchAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsMaxTimeMSExpiredDollarPrefixedFieldNameInvalidIdFieldEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedDocumentValidationFailureInvalidPipelineOperatorClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionNotImplementedErrMechanismUnavailableLocation10065DuplicateKeyLocation15947Location15948Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16020Location16406Location16410Location16872Location17276Location28667Location28724Location28812Location28818Location31002Location31119Location31120Location31249Location31250Location31253Location31254Location31324Location31325Location31394Location31395Location40156Location40157Location40158Location40160Location40181Location40234Location40237Location40238Location40272Location40323Location40352Location40353Location40414Location40415Location40602Location50687Location50692Location50840Location51003Location51024Location51075Location51091Location51108Location51246Location51247Location51270Location51272Location4822819Location5107200Location5107201Location5447000Location7582300"
+const _ErrorCode_name = "UnsetInternalErrorBadValueHostUnreachableFailedToParseUserNotFoundUnauthorizedTypeMismatchAuthenticationFailedIllegalOperationNamespaceNotFoundIndexNotFoundPathNotViableConflictingUpdateOperatorsCursorNotFoundNamespaceExistsMaxTimeMSExpiredDollarPrefixedFieldNameInvalidIdFieldEmptyFieldNameCommandNotFoundImmutableFieldCannotCreateIndexIndexAlreadyExistsInvalidOptionsInvalidNamespaceIndexOptionsConflictIndexKeySpecsConflictOperationFailedDocumentValidationFailureJSInterpreterFailureInvalidPipelineOperatorClientMetadataCannotBeMutatedInvalidIndexSpecificationOptionNotImplementedErrMechanismUnavailableTemporarilyUnavailableLocation10065BSONObjectTooLargeDuplicateKeyQuotaExceededLocation15947Location15948Location15955Location15958Location15959Location15969Location15973Location15974Location15975Location15976Location15981Location15983Location15998Location16020Location16406Location16410Location16872Location17276Location28667Location28724Location28812Location28818Location31002Location31119Location31120Location31249Location31250Location31253Location31254Location31264Location31324Location31325Location31394Location31395Location40156Location40157Location40158Location40160Location40181Location40234Location40237Location40238Location40272Location40323Location40352Location40353Location40414Location40415Location40602Location50687Location50692Location50840Location51003Location51024Location51075Location51091Location51108Location51246Location51247Location51270Location51272Location4822819Location5107200Location5107201Location5447000Location7582300"
 
 var _ErrorCode_map = map[ErrorCode]string{
 	0:       _ErrorCode_name[0:5],
 	1:       _ErrorCode_name[5:18],
 	2:       _ErrorCode_name[18:26],
-	9:       _ErrorCode_name[26:39],
-	11:      _ErrorCode_name[39:51],
-	13:      _ErrorCode_name[51:63],
-	14:      _ErrorCode_name[63:75],
-	18:      _ErrorCode_name[75:95],
-	20:      _ErrorCode_name[95:111],
-	26:      _ErrorCode_name[111:128],
-	27:      _ErrorCode_name[128:141],
-	28:      _ErrorCode_name[141:154],
-	40:      _ErrorCode_name[154:180],
-	43:      _ErrorCode_name[180:194],
-	48:      _ErrorCode_name[194:209],
-	50:      _ErrorCode_name[209:225],
-	52:      _ErrorCode_name[225:248],
-	53:      _ErrorCode_name[248:262],
-	56:      _ErrorCode_name[262:276],
-	59:      _ErrorCode_name[276:291],
-	66:      _ErrorCode_name[291:305],
-	67:      _ErrorCode_name[305:322],
-	68:      _ErrorCode_name[322:340],
-	72:      _ErrorCode_name[340:354],
-	73:      _ErrorCode_name[354:370],
-	85:      _ErrorCode_name[370:390],
-	86:      _ErrorCode_name[390:411],
-	96:      _ErrorCode_name[411:426],
-	121:     _ErrorCode_name[426:451],
-	168:     _ErrorCode_name[451:474],
-	186:     _ErrorCode_name[474:503],
-	197:     _ErrorCode_name[503:534],
-	238:     _ErrorCode_name[534:548],
-	334:     _ErrorCode_name[548:571],
-	10065:   _ErrorCode_name[571:584],
-	11000:   _ErrorCode_name[584:596],
-	15947:   _ErrorCode_name[596:609],
-	15948:   _ErrorCode_name[609:622],
-	15955:   _ErrorCode_name[622:635],
-	15958:   _ErrorCode_name[635:648],
-	15959:   _ErrorCode_name[648:661],
-	15969:   _ErrorCode_name[661:674],
-	15973:   _ErrorCode_name[674:687],
-	15974:   _ErrorCode_name[687:700],
-	15975:   _ErrorCode_name[700:713],
-	15976:   _ErrorCode_name[713:726],
-	15981:   _ErrorCode_name[726:739],
-	15983:   _ErrorCode_name[739:752],
-	15998:   _ErrorCode_name[752:765],
-	16020:   _ErrorCode_name[765:778],
-	16406:   _ErrorCode_name[778:791],
-	16410:   _ErrorCode_name[791:804],
-	16872:   _ErrorCode_name[804:817],
-	17276:   _ErrorCode_name[817:830],
-	28667:   _ErrorCode_name[830:843],
-	28724:   _ErrorCode_name[843:856],
-	28812:   _ErrorCode_name[856:869],
-	28818:   _ErrorCode_name[869:882],
-	31002:   _ErrorCode_name[882:895],
-	31119:   _ErrorCode_name[895:908],
-	31120:   _ErrorCode_name[908:921],
-	31249:   _ErrorCode_name[921:934],
-	31250:   _ErrorCode_name[934:947],
-	31253:   _ErrorCode_name[947:960],
-	31254:   _ErrorCode_name[960:973],
-	31324:   _ErrorCode_name[973:986],
-	31325:   _ErrorCode_name[986:999],
-	31394:   _ErrorCode_name[999:1012],
-	31395:   _ErrorCode_name[1012:1025],
-	40156:   _ErrorCode_name[1025:1038],
-	40157:   _ErrorCode_name[1038:1051],
-	40158:   _ErrorCode_name[1051:1064],
-	40160:   _ErrorCode_name[1064:1077],
-	40181:   _ErrorCode_name[1077:1090],
-	40234:   _ErrorCode_name[1090:1103],
-	40237:   _ErrorCode_name[1103:1116],
-	40238:   _ErrorCode_name[1116:1129],
-	40272:   _ErrorCode_name[1129:1142],
-	40323:   _ErrorCode_name[1142:1155],
-	40352:   _ErrorCode_name[1155:1168],
-	40353:   _ErrorCode_name[1168:1181],
-	40414:   _ErrorCode_name[1181:1194],
-	40415:   _ErrorCode_name[1194:1207],
-	40602:   _ErrorCode_name[1207:1220],
-	50687:   _ErrorCode_name[1220:1233],
-	50692:   _ErrorCode_name[1233:1246],
-	50840:   _ErrorCode_name[1246:1259],
-	51003:   _ErrorCode_name[1259:1272],
-	51024:   _ErrorCode_name[1272:1285],
-	51075:   _ErrorCode_name[1285:1298],
-	51091:   _ErrorCode_name[1298:1311],
-	51108:   _ErrorCode_name[1311:1324],
-	51246:   _ErrorCode_name[1324:1337],
-	51247:   _ErrorCode_name[1337:1350],
-	51270:   _ErrorCode_name[1350:1363],
-	51272:   _ErrorCode_name[1363:1376],
-	4822819: _ErrorCode_name[1376:1391],
-	5107200: _ErrorCode_name[1391:1406],
-	5107201: _ErrorCode_name[1406:1421],
-	5447000: _ErrorCode_name[1421:1436],
-	7582300: _ErrorCode_name[1436:1451],
+	6:       _ErrorCode_name[26:41],
+	9:       _ErrorCode_name[41:54],
+	11:      _ErrorCode_name[54:66],
+	13:      _ErrorCode_name[66:78],
+	14:      _ErrorCode_name[78:90],
+	18:      _ErrorCode_name[90:110],
+	20:      _ErrorCode_name[110:126],
+	26:      _ErrorCode_name[126:143],
+	27:      _ErrorCode_name[143:156],
+	28:      _ErrorCode_name[156:169],
+	40:      _ErrorCode_name[169:195],
+	43:      _ErrorCode_name[195:209],
+	48:      _ErrorCode_name[209:224],
+	50:      _ErrorCode_name[224:240],
+	52:      _ErrorCode_name[240:263],
+	53:      _ErrorCode_name[263:277],
+	56:      _ErrorCode_name[277:291],
+	59:      _ErrorCode_name[291:306],
+	66:      _ErrorCode_name[306:320],
+	67:      _ErrorCode_name[320:337],
+	68:      _ErrorCode_name[337:355],
+	72:      _ErrorCode_name[355:369],
+	73:      _ErrorCode_name[369:385],
+	85:      _ErrorCode_name[385:405],
+	86:      _ErrorCode_name[405:426],
+	96:      _ErrorCode_name[426:441],
+	121:     _ErrorCode_name[441:466],
+	139:     _ErrorCode_name[466:486],
+	168:     _ErrorCode_name[486:509],
+	186:     _ErrorCode_name[509:538],
+	197:     _ErrorCode_name[538:569],
+	238:     _ErrorCode_name[569:583],
+	334:     _ErrorCode_name[583:606],
+	365:     _ErrorCode_name[606:628],
+	10065:   _ErrorCode_name[628:641],
+	10334:   _ErrorCode_name[641:659],
+	11000:   _ErrorCode_name[659:671],
+	12501:   _ErrorCode_name[671:684],
+	15947:   _ErrorCode_name[684:697],
+	15948:   _ErrorCode_name[697:710],
+	15955:   _ErrorCode_name[710:723],
+	15958:   _ErrorCode_name[723:736],
+	15959:   _ErrorCode_name[736:749],
+	15969:   _ErrorCode_name[749:762],
+	15973:   _ErrorCode_name[762:775],
+	15974:   _ErrorCode_name[775:788],
+	15975:   _ErrorCode_name[788:801],
+	15976:   _ErrorCode_name[801:814],
+	15981:   _ErrorCode_name[814:827],
+	15983:   _ErrorCode_name[827:840],
+	15998:   _ErrorCode_name[840:853],
+	16020:   _ErrorCode_name[853:866],
+	16406:   _ErrorCode_name[866:879],
+	16410:   _ErrorCode_name[879:892],
+	16872:   _ErrorCode_name[892:905],
+	17276:   _ErrorCode_name[905:918],
+	28667:   _ErrorCode_name[918:931],
+	28724:   _ErrorCode_name[931:944],
+	28812:   _ErrorCode_name[944:957],
+	28818:   _ErrorCode_name[957:970],
+	31002:   _ErrorCode_name[970:983],
+	31119:   _ErrorCode_name[983:996],
+	31120:   _ErrorCode_name[996:1009],
+	31249:   _ErrorCode_name[1009:1022],
+	31250:   _ErrorCode_name[1022:1035],
+	31253:   _ErrorCode_name[1035:1048],
+	31254:   _ErrorCode_name[1048:1061],
+	31264:   _ErrorCode_name[1061:1074],
+	31324:   _ErrorCode_name[1074:1087],
+	31325:   _ErrorCode_name[1087:1100],
+	31394:   _ErrorCode_name[1100:1113],
+	31395:   _ErrorCode_name[1113:1126],
+	40156:   _ErrorCode_name[1126:1139],
+	40157:   _ErrorCode_name[1139:1152],
+	40158:   _ErrorCode_name[1152:1165],
+	40160:   _ErrorCode_name[1165:1178],
+	40181:   _ErrorCode_name[1178:1191],
+	40234:   _ErrorCode_name[1191:1204],
+	40237:   _ErrorCode_name[1204:1217],
+	40238:   _ErrorCode_name[1217:1230],
+	40272:   _ErrorCode_name[1230:1243],
+	40323:   _ErrorCode_name[1243:1256],
+	40352:   _ErrorCode_name[1256:1269],
+	40353:   _ErrorCode_name[1269:1282],
+	40414:   _ErrorCode_name[1282:1295],
+	40415:   _ErrorCode_name[1295:1308],
+	40602:   _ErrorCode_name[1308:1321],
+	50687:   _ErrorCode_name[1321:1334],
+	50692:   _ErrorCode_name[1334:1347],
+	50840:   _ErrorCode_name[1347:1360],
+	51003:   _ErrorCode_name[1360:1373],
+	51024:   _ErrorCode_name[1373:1386],
+	51075:   _ErrorCode_name[1386:1399],
+	51091:   _ErrorCode_name[1399:1412],
+	51108:   _ErrorCode_name[1412:1425],
+	51246:   _ErrorCode_name[1425:1438],
+	51247:   _ErrorCode_name[1438:1451],
+	51270:   _ErrorCode_name[1451:1464],
+	51272:   _ErrorCode_name[1464:1477],
+	4822819: _ErrorCode_name[1477:1492],
+	5107200: _ErrorCode_name[1492:1507],
+	5107201: _ErrorCode_name[1507:1522],
+	5447000: _ErrorCode_name[1522:1537],
+	7582300: _ErrorCode_name[1537:1552],
 }
 
 func (i ErrorCode) String() string {
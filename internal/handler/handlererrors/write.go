@@ -15,7 +15,9 @@
 package handlererrors
 
 import (
+	"cmp"
 	"errors"
+	"slices"
 
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -126,6 +128,13 @@ func (we *WriteErrors) Merge(we2 *WriteErrors, index int32) {
 	}
 }
 
+// Sort sorts errors by index, keeping the order of errors with the same index.
+func (we *WriteErrors) Sort() {
+	slices.SortStableFunc(we.errs, func(a, b writeError) int {
+		return cmp.Compare(a.index, b.index)
+	})
+}
+
 // check interfaces
 var (
 	_ ProtoErr = (*WriteErrors)(nil)
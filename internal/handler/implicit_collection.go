@@ -0,0 +1,203 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// Implicit collection creation policies.
+//
+// They control what happens on the first write (insert, upsert, index creation)
+// into a collection that does not exist.
+const (
+	// ImplicitCollectionAllow creates collections implicitly, as MongoDB does.
+	ImplicitCollectionAllow = "allow"
+
+	// ImplicitCollectionDeny rejects writes into non-existing collections;
+	// they should be created explicitly with the `create` command.
+	ImplicitCollectionDeny = "deny"
+
+	// ImplicitCollectionTemplate creates collections implicitly
+	// with options and indexes of the template collection in the same database.
+	ImplicitCollectionTemplate = "template"
+)
+
+// ImplicitCollectionPolicies contains all implicit collection creation policies.
+var ImplicitCollectionPolicies = []string{
+	ImplicitCollectionAllow,
+	ImplicitCollectionDeny,
+	ImplicitCollectionTemplate,
+}
+
+// validateImplicitCollectionPolicy returns an error if the given policy is unknown.
+func validateImplicitCollectionPolicy(policy string) error {
+	switch policy {
+	case ImplicitCollectionAllow, ImplicitCollectionDeny, ImplicitCollectionTemplate:
+		return nil
+	default:
+		return fmt.Errorf("unknown implicit collection creation policy %q", policy)
+	}
+}
+
+// implicitCollectionPolicy returns the implicit collection creation policy for the given database.
+func (h *Handler) implicitCollectionPolicy(dbName string) string {
+	if policy, ok := h.ImplicitCollectionDatabasePolicies[dbName]; ok {
+		return policy
+	}
+
+	if h.ImplicitCollectionPolicy == "" {
+		return ImplicitCollectionAllow
+	}
+
+	return h.ImplicitCollectionPolicy
+}
+
+// checkImplicitCollection should be called before a write that would implicitly create a collection.
+//
+// It returns an error if the collection does not exist and the database quota does not allow to create it.
+// Then, depending on the database's policy, it does nothing, returns an error if collection does not exist,
+// or creates it from the matching named template or the template collection.
+func (h *Handler) checkImplicitCollection(ctx context.Context, db backends.Database, dbName, cName, command string) error {
+	if err := h.checkCollectionQuota(ctx, db, dbName, cName, command); err != nil {
+		return err
+	}
+
+	policy := h.implicitCollectionPolicy(dbName)
+
+	// named templates are applied unless implicit creation is denied
+	var t *collectionTemplate
+
+	if policy != ImplicitCollectionDeny {
+		var err error
+		if t, err = h.collectionTemplate(ctx, dbName, cName); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	if policy == ImplicitCollectionAllow && t == nil {
+		return nil
+	}
+
+	list, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: cName})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if len(list.Collections) > 0 {
+		return nil
+	}
+
+	switch policy {
+	case ImplicitCollectionDeny:
+		msg := fmt.Sprintf(
+			"Collection [%s.%s] does not exist and implicit collection creation is denied; create it explicitly",
+			dbName, cName,
+		)
+
+		return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrNamespaceNotFound, msg, command)
+
+	case ImplicitCollectionAllow, ImplicitCollectionTemplate:
+		if t != nil {
+			return createFromCollectionTemplate(ctx, db, t, cName)
+		}
+
+		return h.createFromTemplate(ctx, db, dbName, cName)
+
+	default:
+		panic(fmt.Sprintf("unexpected implicit collection creation policy %q", policy))
+	}
+}
+
+// createFromTemplate creates a collection with options and indexes of the template collection.
+//
+// If the template collection does not exist, the collection is created with default options.
+func (h *Handler) createFromTemplate(ctx context.Context, db backends.Database, dbName, cName string) error {
+	params := &backends.CreateCollectionParams{
+		Name: cName,
+	}
+
+	var indexes []backends.IndexInfo
+
+	if tName := h.ImplicitCollectionTemplate; tName != "" && tName != cName {
+		list, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: tName})
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if len(list.Collections) > 0 {
+			params.CappedSize = list.Collections[0].CappedSize
+			params.CappedDocuments = list.Collections[0].CappedDocuments
+
+			tc, err := db.Collection(tName)
+			if err != nil {
+				return lazyerrors.Error(err)
+			}
+
+			res, err := tc.ListIndexes(ctx, new(backends.ListIndexesParams))
+			if err != nil && !backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+				return lazyerrors.Error(err)
+			}
+
+			if res != nil {
+				for _, idx := range res.Indexes {
+					// _id index is created with the collection
+					if idx.Name != backends.DefaultIndexName {
+						indexes = append(indexes, idx)
+					}
+				}
+			}
+		}
+	}
+
+	err := db.CreateCollection(ctx, params)
+
+	switch {
+	case err == nil:
+		// nothing
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionAlreadyExists):
+		// created concurrently; do not copy indexes again
+		return nil
+	default:
+		return lazyerrors.Error(err)
+	}
+
+	h.L.Debug(
+		"Collection created from template",
+		zap.String("db", dbName), zap.String("collection", cName), zap.Int("indexes", len(indexes)),
+	)
+
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	c, err := db.Collection(cName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if _, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: indexes}); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImplicitCollectionPolicy(t *testing.T) {
+	t.Parallel()
+
+	for _, policy := range ImplicitCollectionPolicies {
+		require.NoError(t, validateImplicitCollectionPolicy(policy))
+	}
+
+	require.Error(t, validateImplicitCollectionPolicy("forbid"))
+
+	h := &Handler{
+		NewOpts: &NewOpts{
+			ImplicitCollectionDatabasePolicies: map[string]string{
+				"app": ImplicitCollectionDeny,
+			},
+		},
+	}
+
+	assert.Equal(t, ImplicitCollectionAllow, h.implicitCollectionPolicy("logs"))
+	assert.Equal(t, ImplicitCollectionDeny, h.implicitCollectionPolicy("app"))
+
+	h.ImplicitCollectionPolicy = ImplicitCollectionTemplate
+	assert.Equal(t, ImplicitCollectionTemplate, h.implicitCollectionPolicy("logs"))
+	assert.Equal(t, ImplicitCollectionDeny, h.implicitCollectionPolicy("app"))
+}
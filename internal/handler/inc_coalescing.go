@@ -0,0 +1,216 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// incCoalescingMaxOps is the maximum number of coalesced `$inc` updates written together.
+const incCoalescingMaxOps = 1000
+
+// incKey identifies a document for coalescing of `$inc` updates.
+type incKey struct {
+	username   string
+	db         string
+	collection string
+	id         string
+}
+
+// incOp represents a single coalesced `$inc` update statement and its result.
+type incOp struct {
+	c      backends.Collection
+	update *common.Update
+
+	matched  int32
+	modified int32
+	err      error
+}
+
+// incCoalescingKey returns the key for coalescing of the given update statement.
+//
+// Only non-upsert updates that contain just `$inc` operator
+// and select a single document by `_id` of string, integer, or ObjectID type are coalesced.
+// If update can't be coalesced, false is returned.
+func incCoalescingKey(username, dbName, cName string, u *common.Update) (incKey, bool) {
+	if u.Upsert || !u.HasUpdateOperators || u.Update.Len() != 1 || !u.Update.Has("$inc") {
+		return incKey{}, false
+	}
+
+	if u.Filter.Len() != 1 || !u.Filter.Has("_id") {
+		return incKey{}, false
+	}
+
+	id, ok := documentIDKey(must.NotFail(u.Filter.Get("_id")))
+	if !ok {
+		return incKey{}, false
+	}
+
+	return incKey{
+		username:   username,
+		db:         dbName,
+		collection: cName,
+		id:         id,
+	}, true
+}
+
+// documentIDKey returns a string that identifies a document with the given `_id` value.
+//
+// Only string, integer, and ObjectID values are supported; false is returned for other values.
+func documentIDKey(v any) (string, bool) {
+	// int32 and int64 values match the same document
+	switch v := v.(type) {
+	case string:
+		return "s" + v, true
+	case int32:
+		return fmt.Sprintf("n%d", v), true
+	case int64:
+		return fmt.Sprintf("n%d", v), true
+	case types.ObjectID:
+		return fmt.Sprintf("o%x", v), true
+	default:
+		return "", false
+	}
+}
+
+// coalesceInc executes the update statement together with other `$inc` updates of the same document,
+// if coalescing is enabled and possible.
+//
+// It returns false if update was not executed.
+func (h *Handler) coalesceInc(ctx context.Context, c backends.Collection, dbName, cName string, u *common.Update) (*incOp, bool) {
+	if h.incCoalescer == nil {
+		return nil, false
+	}
+
+	key, ok := incCoalescingKey(conninfo.Get(ctx).Username(), dbName, cName, u)
+	if !ok {
+		return nil, false
+	}
+
+	op := &incOp{
+		c:      c,
+		update: u,
+	}
+
+	h.incCoalescer.Do(ctx, key, op)
+
+	return op, true
+}
+
+// flushInc reads the document once, applies coalesced updates to it in order, and writes it once.
+//
+// Results are stored in operations.
+// An operation that fails does not affect the document and other operations.
+func (h *Handler) flushInc(ctx context.Context, key incKey, ops []*incOp) {
+	h.incCoalescedUpdates.Add(float64(len(ops)))
+
+	doc, err := h.findIncDocument(ctx, ops[0])
+	if err != nil {
+		for _, op := range ops {
+			op.err = err
+		}
+
+		return
+	}
+
+	if doc == nil {
+		return
+	}
+
+	var modified bool
+
+	for _, op := range ops {
+		updated := doc.DeepCopy()
+
+		var changed bool
+		if changed, op.err = common.ApplyUpdate("update", updated, op.update); op.err != nil {
+			continue
+		}
+
+		doc = updated
+		op.matched = 1
+
+		if changed {
+			op.modified = 1
+			modified = true
+		}
+	}
+
+	if !modified {
+		return
+	}
+
+	h.incCoalescedWrites.Inc()
+
+	if _, err = ops[0].c.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{doc}}); err != nil {
+		err = lazyerrors.Error(err)
+
+		for _, op := range ops {
+			if op.err == nil {
+				op.matched, op.modified, op.err = 0, 0, err
+			}
+		}
+
+		return
+	}
+
+	h.L.Debug(
+		"Coalesced $inc updates",
+		zap.String("db", key.db), zap.String("collection", key.collection), zap.Int("updates", len(ops)),
+	)
+}
+
+// findIncDocument returns the document selected by the operation's filter, or nil if there is none.
+func (h *Handler) findIncDocument(ctx context.Context, op *incOp) (*types.Document, error) {
+	var qp backends.QueryParams
+	if !h.disablePushdown.Load() {
+		qp.Filter = op.update.Filter
+	}
+
+	res, err := op.c.Query(ctx, &qp)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	closer.Add(res.Iter)
+
+	iter := common.LimitIterator(common.FilterIterator(res.Iter, closer, op.update.Filter), closer, 1)
+
+	_, doc, err := iter.Next()
+	if err != nil {
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return nil, nil
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestIncCoalescingKey(t *testing.T) {
+	t.Parallel()
+
+	id := types.ObjectID{0x62, 0x56, 0xc5, 0xba, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0xff, 0xff, 0xff}
+
+	inc := must.NotFail(types.NewDocument("$inc", must.NotFail(types.NewDocument("v", int32(1)))))
+
+	for name, tc := range map[string]struct {
+		u        *common.Update
+		expected string // empty if update can't be coalesced
+	}{
+		"String": {
+			u: &common.Update{
+				Filter:             must.NotFail(types.NewDocument("_id", "counter")),
+				Update:             inc,
+				HasUpdateOperators: true,
+			},
+			expected: "scounter",
+		},
+		"Int32": {
+			u: &common.Update{
+				Filter:             must.NotFail(types.NewDocument("_id", int32(42))),
+				Update:             inc,
+				HasUpdateOperators: true,
+			},
+			expected: "n42",
+		},
+		"Int64": {
+			u: &common.Update{
+				Filter:             must.NotFail(types.NewDocument("_id", int64(42))),
+				Update:             inc,
+				HasUpdateOperators: true,
+				Multi:              true,
+			},
+			expected: "n42",
+		},
+		"ObjectID": {
+			u: &common.Update{
+				Filter:             must.NotFail(types.NewDocument("_id", id)),
+				Update:             inc,
+				HasUpdateOperators: true,
+			},
+			expected: "o6256c5ba0badc0ffeeffffff",
+		},
+		"Double": {
+			u: &common.Update{
+				Filter:             must.NotFail(types.NewDocument("_id", 42.0)),
+				Update:             inc,
+				HasUpdateOperators: true,
+			},
+		},
+		"Upsert": {
+			u: &common.Update{
+				Filter:             must.NotFail(types.NewDocument("_id", "counter")),
+				Update:             inc,
+				HasUpdateOperators: true,
+				Upsert:             true,
+			},
+		},
+		"OtherFilter": {
+			u: &common.Update{
+				Filter:             must.NotFail(types.NewDocument("_id", "counter", "v", int32(1))),
+				Update:             inc,
+				HasUpdateOperators: true,
+			},
+		},
+		"Set": {
+			u: &common.Update{
+				Filter: must.NotFail(types.NewDocument("_id", "counter")),
+				Update: must.NotFail(types.NewDocument(
+					"$inc", must.NotFail(types.NewDocument("v", int32(1))),
+					"$set", must.NotFail(types.NewDocument("t", int32(1))),
+				)),
+				HasUpdateOperators: true,
+			},
+		},
+		"Replacement": {
+			u: &common.Update{
+				Filter: must.NotFail(types.NewDocument("_id", "counter")),
+				Update: must.NotFail(types.NewDocument("v", int32(1))),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			key, ok := incCoalescingKey("user", "db", "coll", tc.u)
+
+			if tc.expected == "" {
+				assert.False(t, ok)
+				return
+			}
+
+			assert.True(t, ok)
+			assert.Equal(t, incKey{username: "user", db: "db", collection: "coll", id: tc.expected}, key)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package javascript
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dop251/goja"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// toValue converts the given value to JavaScript value.
+//
+// ObjectIDs are converted to hexadecimal strings.
+// Binary data, regular expressions, and timestamps are not supported.
+func toValue(vm *goja.Runtime, v any) (goja.Value, error) {
+	switch v := v.(type) {
+	case *types.Document:
+		obj := vm.NewObject()
+
+		for _, k := range v.Keys() {
+			fv, err := toValue(vm, must.NotFail(v.Get(k)))
+			if err != nil {
+				return nil, err
+			}
+
+			if err = obj.Set(k, fv); err != nil {
+				return nil, err
+			}
+		}
+
+		return obj, nil
+
+	case *types.Array:
+		values := make([]any, v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			ev, err := toValue(vm, must.NotFail(v.Get(i)))
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = ev
+		}
+
+		return vm.NewArray(values...), nil
+
+	case float64, string, bool, int32, int64:
+		return vm.ToValue(v), nil
+
+	case types.ObjectID:
+		return vm.ToValue(hex.EncodeToString(v[:])), nil
+
+	case time.Time:
+		return vm.New(vm.Get("Date"), vm.ToValue(v.UnixMilli()))
+
+	case types.NullType:
+		return goja.Null(), nil
+
+	default:
+		return nil, fmt.Errorf("value of type %s is not supported", handlerparams.AliasFromType(v))
+	}
+}
+
+// fromValue converts the given JavaScript value.
+//
+// Numbers are converted to doubles, as in MongoDB.
+// Undefined values are converted to null.
+func fromValue(v goja.Value) (any, error) {
+	if v == nil || goja.IsUndefined(v) || goja.IsNull(v) {
+		return types.Null, nil
+	}
+
+	obj, ok := v.(*goja.Object)
+	if !ok {
+		switch v := v.Export().(type) {
+		case bool, string, float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		default:
+			return nil, fmt.Errorf("value %s is not supported", v)
+		}
+	}
+
+	switch obj.ClassName() {
+	case "Array":
+		l := obj.Get("length").ToInteger()
+		if l < 0 || l > math.MaxInt32 {
+			return nil, fmt.Errorf("invalid array length %d", l)
+		}
+
+		arr := types.MakeArray(int(l))
+
+		for i := int64(0); i < l; i++ {
+			ev, err := fromValue(obj.Get(fmt.Sprint(i)))
+			if err != nil {
+				return nil, err
+			}
+
+			arr.Append(ev)
+		}
+
+		return arr, nil
+
+	case "Date":
+		t, ok := obj.Export().(time.Time)
+		if !ok {
+			return nil, fmt.Errorf("invalid date %s", obj)
+		}
+
+		return t.Truncate(time.Millisecond).UTC(), nil
+
+	case "Function":
+		return nil, fmt.Errorf("function %s is not supported", obj)
+
+	default:
+		keys := obj.Keys()
+		doc := types.MakeDocument(len(keys))
+
+		for _, k := range keys {
+			fv, err := fromValue(obj.Get(k))
+			if err != nil {
+				return nil, err
+			}
+
+			doc.Set(k, fv)
+		}
+
+		return doc, nil
+	}
+}
@@ -0,0 +1,333 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package javascript provides sandboxed evaluation of server-side JavaScript code
+// for `$function`, `$accumulator`, and `$where` operators.
+//
+// Evaluation is disabled by default, see Enable.
+// Each evaluation uses a new interpreter instance without access to the file system, network, or other evaluations.
+package javascript
+
+import (
+	"errors"
+	"fmt"
+	"runtime/metrics"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dop251/goja"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+)
+
+// maxCallStackSize limits the depth of function calls.
+const maxCallStackSize = 1024
+
+// memoryCheckInterval is the interval between heap size checks during evaluation.
+const memoryCheckInterval = 10 * time.Millisecond
+
+// heapMetric is the runtime metric used to check heap growth during evaluation.
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// Limits represents limits for a single evaluation.
+type Limits struct {
+	// Timeout limits the duration of evaluation; 0 means no limit.
+	Timeout time.Duration
+
+	// MaxMemory limits heap growth during evaluation, in bytes; 0 means no limit.
+	// The limit is approximate, as the heap is shared with the rest of the process.
+	MaxMemory uint64
+}
+
+// maxPrograms is the maximum number of compiled programs cached by Compile.
+const maxPrograms = 1000
+
+// limits stores evaluation limits; nil if evaluation is disabled.
+var limits atomic.Pointer[Limits]
+
+// programs caches compiled programs by operator and source code,
+// as the same code is compiled for each document by `$where` operator.
+var (
+	programsM sync.Mutex
+	programs  = make(map[string]*goja.Program)
+)
+
+// Enable enables evaluation with the given limits for the whole process.
+// Nil limits disable evaluation.
+//
+// It should be called on startup before handling any requests.
+func Enable(l *Limits) {
+	limits.Store(l)
+}
+
+// Enabled returns true if evaluation is enabled.
+func Enabled() bool {
+	return limits.Load() != nil
+}
+
+// GetCode returns JavaScript code from the given field of the operator specification.
+//
+// It returns *handlererrors.CommandError if the field is missing or is not a string.
+func GetCode(spec *types.Document, field, operator string) (string, error) {
+	v, _ := spec.Get(field)
+
+	code, ok := v.(string)
+	if !ok {
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("The %s function must be specified and be a string", field),
+			operator,
+		)
+	}
+
+	return code, nil
+}
+
+// CheckLang checks the `lang` field of the operator specification.
+//
+// It returns *handlererrors.CommandError if the language is not JavaScript.
+func CheckLang(spec *types.Document, operator string) error {
+	if lang, _ := spec.Get("lang"); lang != "js" {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"Currently the only supported language specifier is 'js'.",
+			operator,
+		)
+	}
+
+	return nil
+}
+
+// Function represents compiled JavaScript code of the given operator.
+type Function struct {
+	operator string
+	prog     *goja.Program
+}
+
+// Compile compiles JavaScript function (such as `function(a) { return a * 2 }`)
+// or expression (such as `this.a == 42`) source code of the given operator.
+//
+// It returns *handlererrors.CommandError for invalid code.
+func Compile(operator, src string) (*Function, error) {
+	// wrap code into a function, so `this` could be bound for expressions
+	programsM.Lock()
+	defer programsM.Unlock()
+
+	key := operator + "\x00" + src
+
+	prog := programs[key]
+	if prog == nil {
+		var err error
+		if prog, err = goja.Compile(operator, "(function() { return (\n"+src+"\n) })", false); err != nil {
+			return nil, newError(operator, err)
+		}
+
+		if len(programs) >= maxPrograms {
+			clear(programs)
+		}
+
+		programs[key] = prog
+	}
+
+	return &Function{
+		operator: operator,
+		prog:     prog,
+	}, nil
+}
+
+// Call calls compiled JavaScript function with the given arguments.
+//
+// It returns *handlererrors.CommandError if code is not a function, throws an exception, or exceeds limits.
+func (f *Function) Call(args ...any) (any, error) {
+	return f.run(nil, args, nil)
+}
+
+// Match evaluates compiled JavaScript function or expression with `this` and `obj` set to the given document,
+// and returns true if the result is truthy. Functions are called without arguments.
+//
+// It returns *handlererrors.CommandError if code throws an exception or exceeds limits.
+func (f *Function) Match(doc *types.Document) (bool, error) {
+	var res bool
+
+	_, err := f.run(doc, nil, func(v goja.Value) (any, error) {
+		res = v.ToBoolean()
+		return nil, nil
+	})
+
+	return res, err
+}
+
+// run evaluates compiled code in a new interpreter instance with the current limits.
+//
+// If convert is nil, code should evaluate to a function, and its result is converted with fromValue.
+// Otherwise, code may also be an expression, and convert is used for the result.
+func (f *Function) run(this *types.Document, args []any, convert func(goja.Value) (any, error)) (res any, err error) {
+	l := limits.Load()
+	if l == nil {
+		return nil, lazyerrors.New("JavaScript evaluation is disabled")
+	}
+
+	vm := goja.New()
+	vm.SetMaxCallStackSize(maxCallStackSize)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go watch(vm, l, done)
+
+	defer func() {
+		// exceptions and interrupts of JavaScript code invoked by Go code (such as getters) are panics
+		if p := recover(); p != nil {
+			e, ok := p.(error)
+			if !ok {
+				panic(p)
+			}
+
+			res, err = nil, newError(f.operator, e)
+		}
+	}()
+
+	thisV := goja.Undefined()
+
+	if this != nil {
+		if thisV, err = toValue(vm, this); err != nil {
+			return nil, newError(f.operator, err)
+		}
+
+		if err = vm.Set("obj", thisV); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	argsV := make([]goja.Value, len(args))
+
+	for i, arg := range args {
+		if argsV[i], err = toValue(vm, arg); err != nil {
+			return nil, newError(f.operator, err)
+		}
+	}
+
+	wrapper, err := vm.RunProgram(f.prog)
+	if err != nil {
+		return nil, newError(f.operator, err)
+	}
+
+	wrapperF, ok := goja.AssertFunction(wrapper)
+	if !ok {
+		return nil, lazyerrors.Errorf("unexpected wrapper value %v", wrapper)
+	}
+
+	v, err := wrapperF(thisV)
+	if err != nil {
+		return nil, newError(f.operator, err)
+	}
+
+	if fn, ok := goja.AssertFunction(v); ok {
+		if v, err = fn(thisV, argsV...); err != nil {
+			return nil, newError(f.operator, err)
+		}
+	} else if convert == nil {
+		return nil, newError(f.operator, errors.New("code did not evaluate to a function"))
+	}
+
+	if convert == nil {
+		convert = fromValue
+	}
+
+	if res, err = convert(v); err != nil {
+		return nil, newError(f.operator, err)
+	}
+
+	return res, nil
+}
+
+// watch interrupts evaluation in the given interpreter instance when limits are exceeded,
+// until done is closed.
+func watch(vm *goja.Runtime, l *Limits, done <-chan struct{}) {
+	var timeout <-chan time.Time
+
+	if l.Timeout > 0 {
+		t := time.NewTimer(l.Timeout)
+		defer t.Stop()
+
+		timeout = t.C
+	}
+
+	var check <-chan time.Time
+	var start uint64
+
+	if l.MaxMemory > 0 {
+		t := time.NewTicker(memoryCheckInterval)
+		defer t.Stop()
+
+		check = t.C
+		start = heapSize()
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+
+		case <-timeout:
+			vm.Interrupt(fmt.Errorf("execution time limit of %s exceeded", l.Timeout))
+			return
+
+		case <-check:
+			if size := heapSize(); size > start && size-start > l.MaxMemory {
+				vm.Interrupt(fmt.Errorf("memory limit of %d bytes exceeded", l.MaxMemory))
+				return
+			}
+		}
+	}
+}
+
+// heapSize returns the current size of heap objects.
+func heapSize() uint64 {
+	s := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(s)
+
+	if s[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+
+	return s[0].Value.Uint64()
+}
+
+// newError returns *handlererrors.CommandError for the given evaluation error of the given operator.
+func newError(operator string, err error) error {
+	msg := err.Error()
+
+	var ie *goja.InterruptedError
+	var se *goja.StackOverflowError
+
+	switch {
+	case errors.As(err, &ie):
+		if e, ok := ie.Value().(error); ok {
+			msg = e.Error()
+		}
+
+	case errors.As(err, &se):
+		msg = fmt.Sprintf("maximum call stack size of %d exceeded", maxCallStackSize)
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrJSInterpreterFailure,
+		fmt.Sprintf("Invalid %s :: caused by :: %s", operator, msg),
+		operator,
+	)
+}
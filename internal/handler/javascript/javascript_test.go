@@ -0,0 +1,208 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package javascript
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+// enable enables evaluation with the given limits for the duration of the test.
+//
+// Tests that use it should not be parallel.
+func enable(t *testing.T, l *Limits) {
+	t.Helper()
+
+	prev := limits.Load()
+	Enable(l)
+
+	t.Cleanup(func() { Enable(prev) })
+}
+
+// requireEvalError checks that err is JSInterpreterFailure error that contains msg.
+func requireEvalError(t *testing.T, err error, msg string) {
+	t.Helper()
+
+	var ce *handlererrors.CommandError
+	require.True(t, errors.As(err, &ce), "%v", err)
+	assert.Equal(t, handlererrors.ErrJSInterpreterFailure, ce.Code())
+	assert.Contains(t, ce.Err().Error(), msg)
+}
+
+func TestCall(t *testing.T) {
+	enable(t, &Limits{Timeout: 10 * time.Second})
+
+	date := time.Date(2024, 1, 2, 3, 4, 5, 6_000_000, time.UTC)
+
+	for name, tc := range map[string]struct {
+		code     string
+		args     []any
+		expected any
+	}{
+		"Numbers": {
+			code:     "function(a, b, c) { return a + b + c }",
+			args:     []any{int32(1), int64(2), 0.5},
+			expected: 3.5,
+		},
+		"Integer": {
+			code:     "function(v) { return v * 2 }",
+			args:     []any{int32(21)},
+			expected: float64(42),
+		},
+		"Document": {
+			code: "function(doc) { return {z: doc.a, a: [doc.b, doc.c], n: null, u: undefined} }",
+			args: []any{must.NotFail(types.NewDocument("a", "foo", "b", true, "c", types.Null))},
+			expected: must.NotFail(types.NewDocument(
+				"z", "foo",
+				"a", must.NotFail(types.NewArray(true, types.Null)),
+				"n", types.Null,
+				"u", types.Null,
+			)),
+		},
+		"Date": {
+			code:     "function(d) { return new Date(d.getTime() + 1000) }",
+			args:     []any{date},
+			expected: date.Add(time.Second).Truncate(time.Millisecond),
+		},
+		"ObjectID": {
+			code:     "function(id) { return id }",
+			args:     []any{types.ObjectID{0x62, 0x56, 0xc5, 0xba, 0x0b, 0xad, 0xc0, 0xff, 0xee, 0xff, 0xff, 0xff}},
+			expected: "6256c5ba0badc0ffeeffffff",
+		},
+		"NoArgs": {
+			code:     "function() { return 'foo' }",
+			expected: "foo",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f, err := Compile("$function", tc.code)
+			require.NoError(t, err)
+
+			actual, err := f.Call(tc.args...)
+			require.NoError(t, err)
+			testutil.AssertEqual(t, must.NotFail(types.NewArray(tc.expected)), must.NotFail(types.NewArray(actual)))
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	enable(t, &Limits{Timeout: 10 * time.Second})
+
+	doc := must.NotFail(types.NewDocument("_id", "foo", "v", int32(42)))
+
+	for name, tc := range map[string]struct {
+		code     string
+		expected bool
+	}{
+		"Expression":      {code: "this.v == 42", expected: true},
+		"ExpressionFalse": {code: "this.v > 42", expected: false},
+		"Obj":             {code: "obj._id == 'foo'", expected: true},
+		"Function":        {code: "function() { return this.v === 42 }", expected: true},
+		"Truthy":          {code: "function() { return this._id }", expected: true},
+		"Falsy":           {code: "this.missing", expected: false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f, err := Compile("$where", tc.code)
+			require.NoError(t, err)
+
+			actual, err := f.Match(doc)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	enable(t, &Limits{Timeout: 100 * time.Millisecond, MaxMemory: 16 << 20})
+
+	t.Run("Syntax", func(t *testing.T) {
+		_, err := Compile("$function", "function( {")
+		requireEvalError(t, err, "Invalid $function :: caused by :: SyntaxError")
+	})
+
+	for name, tc := range map[string]struct {
+		code string
+		args []any
+		msg  string
+	}{
+		"Exception": {
+			code: "function() { throw new Error('boom') }",
+			msg:  "Error: boom",
+		},
+		"NotFunction": {
+			code: "42",
+			msg:  "code did not evaluate to a function",
+		},
+		"Timeout": {
+			code: "function() { while (true) {} }",
+			msg:  "execution time limit of 100ms exceeded",
+		},
+		"StackOverflow": {
+			code: "function f() { return f() }",
+			msg:  "maximum call stack size of 1024 exceeded",
+		},
+		"UnsupportedArgument": {
+			code: "function(v) { return v }",
+			args: []any{types.Binary{Subtype: types.BinaryGeneric, B: []byte{42}}},
+			msg:  "value of type binData is not supported",
+		},
+		"UnsupportedResult": {
+			code: "function() { return function() {} }",
+			msg:  "is not supported",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			f, err := Compile("$function", tc.code)
+			require.NoError(t, err)
+
+			_, err = f.Call(tc.args...)
+			requireEvalError(t, err, tc.msg)
+		})
+	}
+}
+
+func TestMemoryLimit(t *testing.T) {
+	enable(t, &Limits{Timeout: time.Minute, MaxMemory: 16 << 20})
+
+	f, err := Compile("$function", "function() { var a = []; while (true) { a.push(new Array(1000).fill('foo')) } }")
+	require.NoError(t, err)
+
+	_, err = f.Call()
+	requireEvalError(t, err, "memory limit of 16777216 bytes exceeded")
+}
+
+func TestDisabled(t *testing.T) {
+	enable(t, nil)
+
+	require.False(t, Enabled())
+
+	f, err := Compile("$function", "function() { return 42 }")
+	require.NoError(t, err)
+
+	_, err = f.Call()
+	require.Error(t, err)
+
+	var ce *handlererrors.CommandError
+	require.False(t, errors.As(err, &ce))
+}
@@ -0,0 +1,166 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// ownMaxTimeMSCommands contains commands that handle maxTimeMS themselves
+// because their contexts are used by cursors after the command returns,
+// or because maxTimeMS has a different meaning.
+var ownMaxTimeMSCommands = map[string]bool{
+	"aggregate": true,
+	"find":      true,
+	"getMore":   true,
+}
+
+// getMaxTimeMS returns the value of the optional maxTimeMS field of the command document,
+// or zero if it is not set.
+func getMaxTimeMS(document *types.Document) (int64, error) {
+	v, _ := document.Get("maxTimeMS")
+	if v == nil {
+		return 0, nil
+	}
+
+	// cannot use other existing handlerparams function, they return different error codes
+	maxTimeMS, err := handlerparams.GetWholeNumberParam(v)
+	if err != nil {
+		switch {
+		case errors.Is(err, handlerparams.ErrUnexpectedType):
+			if _, ok := v.(types.NullType); ok {
+				return 0, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"maxTimeMS must be a number",
+					document.Command(),
+				)
+			}
+
+			return 0, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf(
+					`BSON field '%s.maxTimeMS' is the wrong type '%s', expected types '[long, int, decimal, double]'`,
+					document.Command(), handlerparams.AliasFromType(v),
+				),
+				document.Command(),
+			)
+		case errors.Is(err, handlerparams.ErrNotWholeNumber):
+			return 0, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"maxTimeMS has non-integral value",
+				document.Command(),
+			)
+		case errors.Is(err, handlerparams.ErrLongExceededPositive):
+			return 0, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("%s value for maxTimeMS is out of range", types.FormatAnyValue(v)),
+				document.Command(),
+			)
+		case errors.Is(err, handlerparams.ErrLongExceededNegative):
+			return 0, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrValueNegative,
+				fmt.Sprintf("BSON field 'maxTimeMS' value must be >= 0, actual value '%s'", types.FormatAnyValue(v)),
+				document.Command(),
+			)
+		default:
+			return 0, lazyerrors.Error(err)
+		}
+	}
+
+	if maxTimeMS < int64(0) {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrValueNegative,
+			fmt.Sprintf("BSON field 'maxTimeMS' value must be >= 0, actual value '%s'", types.FormatAnyValue(v)),
+			document.Command(),
+		)
+	}
+
+	if maxTimeMS > math.MaxInt32 {
+		return 0, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("%v value for maxTimeMS is out of range", v),
+			document.Command(),
+		)
+	}
+
+	return maxTimeMS, nil
+}
+
+// effectiveMaxTimeMS returns maxTimeMS set by the client,
+// or the server's default maximum operation time if it is not set (zero).
+func (h *Handler) effectiveMaxTimeMS(maxTimeMS int64) int64 {
+	if maxTimeMS != 0 {
+		return maxTimeMS
+	}
+
+	return h.DefaultMaxTime.Milliseconds()
+}
+
+// withMaxTimeMS wraps the command handler to limit its execution time by maxTimeMS
+// or the server's default maximum operation time.
+//
+// The handler's context is canceled when the time limit is exceeded,
+// and the MaxTimeMSExpired error is returned if the handler fails after that.
+func (h *Handler) withMaxTimeMS(
+	handler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error),
+) func(context.Context, *wire.OpMsg) (*wire.OpMsg, error) {
+	return func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		document, err := msg.Document()
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		maxTimeMS, err := getMaxTimeMS(document)
+		if err != nil {
+			return nil, err
+		}
+
+		if maxTimeMS = h.effectiveMaxTimeMS(maxTimeMS); maxTimeMS == 0 {
+			return handler(ctx, msg)
+		}
+
+		budget := time.Duration(maxTimeMS) * time.Millisecond
+
+		budgetCtx, _, cancel := ctxutil.WithBudget(ctx, budget)
+		defer cancel()
+
+		res, err := handler(budgetCtx, msg)
+
+		// the handler could apply the same budget itself, so check the remaining time too
+		expired := budgetCtx.Err() != nil || ctxutil.Remaining(ctx, budget) == 0
+		if err != nil && expired && ctx.Err() == nil {
+			command := document.Command()
+
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMaxTimeMSExpired,
+				"Executor error during "+command+" command :: caused by :: operation exceeded time limit",
+				command,
+			)
+		}
+
+		return res, err
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+func TestWithMaxTimeMS(t *testing.T) {
+	t.Parallel()
+
+	// waits for the context cancellation, but no longer than a second
+	slow := func(ctx context.Context, _ *wire.OpMsg) (*wire.OpMsg, error) {
+		select {
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		case <-time.After(time.Second):
+			return new(wire.OpMsg), nil
+		}
+	}
+
+	makeMsg := func(pairs ...any) *wire.OpMsg {
+		var msg wire.OpMsg
+		must.NoError(msg.SetSections(wire.MakeOpMsgSection(must.NotFail(types.NewDocument(pairs...)))))
+
+		return &msg
+	}
+
+	for name, tc := range map[string]struct {
+		defaultMaxTime time.Duration
+		msg            *wire.OpMsg
+		code           handlererrors.ErrorCode // zero if no error is expected
+	}{
+		"NoLimit": {
+			msg: makeMsg("count", "values", "$db", "test"),
+		},
+		"MaxTimeMS": {
+			msg:  makeMsg("count", "values", "maxTimeMS", int32(10), "$db", "test"),
+			code: handlererrors.ErrMaxTimeMSExpired,
+		},
+		"Default": {
+			defaultMaxTime: 10 * time.Millisecond,
+			msg:            makeMsg("count", "values", "$db", "test"),
+			code:           handlererrors.ErrMaxTimeMSExpired,
+		},
+		"MaxTimeMSOverridesDefault": {
+			defaultMaxTime: 10 * time.Millisecond,
+			msg:            makeMsg("count", "values", "maxTimeMS", int64(5000), "$db", "test"),
+		},
+		"Invalid": {
+			msg:  makeMsg("count", "values", "maxTimeMS", "10", "$db", "test"),
+			code: handlererrors.ErrTypeMismatch,
+		},
+		"Negative": {
+			msg:  makeMsg("count", "values", "maxTimeMS", int32(-1), "$db", "test"),
+			code: handlererrors.ErrValueNegative,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			h := &Handler{NewOpts: &NewOpts{DefaultMaxTime: tc.defaultMaxTime}}
+
+			_, err := h.withMaxTimeMS(slow)(context.Background(), tc.msg)
+			if tc.code == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var cmdErr *handlererrors.CommandError
+			require.ErrorAs(t, err, &cmdErr)
+			assert.Equal(t, tc.code, cmdErr.Code())
+		})
+	}
+}
@@ -18,7 +18,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math"
 	"os"
 	"strings"
 	"time"
@@ -33,7 +32,9 @@ import (
 	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/stages"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/handler/querystats"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -69,106 +70,64 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		return nil, err
 	}
 
-	// handle collection-agnostic pipelines ({aggregate: 1})
+	// handle collection-agnostic pipelines ({aggregate: 1});
+	// only $queryStats stage is supported for them.
 	// TODO https://github.com/FerretDB/FerretDB/issues/1890
 	var ok bool
 	var cName string
+	var collectionAgnostic bool
 
 	if cName, ok = collectionParam.(string); !ok {
-		return nil, handlererrors.NewCommandErrorMsgWithArgument(
-			handlererrors.ErrFailedToParse,
-			"Invalid command format: the 'aggregate' field must specify a collection name or 1",
-			document.Command(),
-		)
-	}
-
-	db, err := h.b.Database(dbName)
-	if err != nil {
-		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
-			msg := fmt.Sprintf("Invalid namespace specified '%s.%s'", dbName, cName)
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, document.Command())
+		if n, _ := handlerparams.GetWholeNumberParam(collectionParam); n != 1 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				"Invalid command format: the 'aggregate' field must specify a collection name or 1",
+				document.Command(),
+			)
 		}
 
-		return nil, lazyerrors.Error(err)
+		collectionAgnostic = true
+		cName = "$cmd.aggregate"
 	}
 
-	c, err := db.Collection(cName)
-	if err != nil {
-		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
-			msg := fmt.Sprintf("Invalid collection name: %s", cName)
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, document.Command())
-		}
-
-		return nil, lazyerrors.Error(err)
-	}
+	var db backends.Database
+	var c backends.Collection
 
-	username := conninfo.Get(ctx).Username()
+	if !collectionAgnostic {
+		db, err = h.b.Database(dbName)
+		if err != nil {
+			if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+				msg := fmt.Sprintf("Invalid namespace specified '%s.%s'", dbName, cName)
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, document.Command())
+			}
 
-	v, _ := document.Get("maxTimeMS")
-	if v == nil {
-		v = int64(0)
-	}
+			return nil, lazyerrors.Error(err)
+		}
 
-	// cannot use other existing handlerparams function, they return different error codes
-	maxTimeMS, err := handlerparams.GetWholeNumberParam(v)
-	if err != nil {
-		switch {
-		case errors.Is(err, handlerparams.ErrUnexpectedType):
-			if _, ok = v.(types.NullType); ok {
-				return nil, handlererrors.NewCommandErrorMsgWithArgument(
-					handlererrors.ErrBadValue,
-					"maxTimeMS must be a number",
-					document.Command(),
-				)
+		c, err = db.Collection(cName)
+		if err != nil {
+			if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+				msg := fmt.Sprintf("Invalid collection name: %s", cName)
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, document.Command())
 			}
 
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrTypeMismatch,
-				fmt.Sprintf(
-					`BSON field 'aggregate.maxTimeMS' is the wrong type '%s', expected types '[long, int, decimal, double]'`,
-					handlerparams.AliasFromType(v),
-				),
-				document.Command(),
-			)
-		case errors.Is(err, handlerparams.ErrNotWholeNumber):
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrBadValue,
-				"maxTimeMS has non-integral value",
-				document.Command(),
-			)
-		case errors.Is(err, handlerparams.ErrLongExceededPositive):
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrBadValue,
-				fmt.Sprintf("%s value for maxTimeMS is out of range", types.FormatAnyValue(v)),
-				document.Command(),
-			)
-		case errors.Is(err, handlerparams.ErrLongExceededNegative):
-			return nil, handlererrors.NewCommandErrorMsgWithArgument(
-				handlererrors.ErrValueNegative,
-				fmt.Sprintf("BSON field 'maxTimeMS' value must be >= 0, actual value '%s'", types.FormatAnyValue(v)),
-				document.Command(),
-			)
-		default:
 			return nil, lazyerrors.Error(err)
 		}
-	}
 
-	if maxTimeMS < int64(0) {
-		return nil, handlererrors.NewCommandErrorMsgWithArgument(
-			handlererrors.ErrValueNegative,
-			fmt.Sprintf("BSON field 'maxTimeMS' value must be >= 0, actual value '%s'", types.FormatAnyValue(v)),
-			document.Command(),
-		)
+		if c, err = h.withArchive(ctx, db, dbName, cName, c); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
 	}
 
-	if maxTimeMS > math.MaxInt32 {
-		return nil, handlererrors.NewCommandErrorMsgWithArgument(
-			handlererrors.ErrBadValue,
-			fmt.Sprintf("%v value for maxTimeMS is out of range", v),
-			document.Command(),
-		)
+	username := conninfo.Get(ctx).Username()
+
+	maxTimeMS, err := getMaxTimeMS(document)
+	if err != nil {
+		return nil, err
 	}
 
+	maxTimeMS = h.effectiveMaxTimeMS(maxTimeMS)
+
 	pipeline, err := common.GetRequiredParam[*types.Array](document, "pipeline")
 	if err != nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
@@ -182,6 +141,9 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 	stagesDocuments := make([]aggregations.Stage, 0, len(aggregationStages))
 	collStatsDocuments := make([]aggregations.Stage, 0, len(aggregationStages))
 
+	var queryStatsStage bool
+	var searchStage *types.Document
+
 	for i, v := range aggregationStages {
 		var d *types.Document
 
@@ -193,6 +155,40 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 			)
 		}
 
+		// $queryStats stage needs access to the handler's state, so it is handled there
+		if d.Command() == "$queryStats" {
+			if i > 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrCollStatsIsNotFirstStage,
+					"$queryStats is only valid as the first stage in a pipeline",
+					document.Command(),
+				)
+			}
+
+			if err = validateQueryStatsStage(d); err != nil {
+				return nil, err
+			}
+
+			queryStatsStage = true
+
+			continue
+		}
+
+		// search stages are executed by the backend, so they are handled there
+		if cmd := d.Command(); cmd == "$search" || cmd == "$vectorSearch" || cmd == "$listSearchIndexes" {
+			if i > 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrCollStatsIsNotFirstStage,
+					fmt.Sprintf("%s is only valid as the first stage in a pipeline", cmd),
+					document.Command(),
+				)
+			}
+
+			searchStage = d
+
+			continue
+		}
+
 		var s aggregations.Stage
 
 		if s, err = stages.NewStage(d); err != nil {
@@ -216,8 +212,23 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		}
 	}
 
+	switch {
+	case queryStatsStage && (!collectionAgnostic || dbName != "admin"):
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidNamespace,
+			"$queryStats must be run against the 'admin' database with {aggregate: 1}",
+			document.Command(),
+		)
+	case !queryStatsStage && collectionAgnostic:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"Collection-agnostic aggregations are supported only with $queryStats stage",
+			document.Command(),
+		)
+	}
+
 	// validate cursor after validating pipeline stages to keep compatibility
-	v, _ = document.Get("cursor")
+	v, _ := document.Get("cursor")
 	if v == nil {
 		return nil, handlererrors.NewCommandErrorMsgWithArgument(
 			handlererrors.ErrFailedToParse,
@@ -251,38 +262,35 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 	cancel := func() {}
 
 	if maxTimeMS != 0 {
-		findDone := make(chan struct{})
-		defer close(findDone)
-
-		ctx, cancel = context.WithCancel(ctx)
+		// the budget covers the whole request, but not the cursor's lifetime after that
+		var stop func()
+		ctx, stop, cancel = ctxutil.WithBudget(ctx, time.Duration(maxTimeMS)*time.Millisecond)
 
-		go func() {
-			t := time.NewTimer(time.Duration(maxTimeMS) * time.Millisecond)
-			defer t.Stop()
-
-			select {
-			case <-t.C:
-				cancel()
-			case <-findDone:
-			}
-		}()
+		defer stop()
 	}
 
 	closer := iterator.NewMultiCloser(iterator.CloserFunc(cancel))
 
 	var iter iterator.Interface[struct{}, *types.Document]
 
-	if len(collStatsDocuments) == len(stagesDocuments) {
+	switch {
+	case queryStatsStage:
+		iter, err = processStagesQueryStats(ctx, closer, h.queryStats, stagesDocuments)
+
+	case searchStage != nil:
+		iter, err = processStagesSearch(ctx, closer, c, searchStage, stagesDocuments)
+
+	case len(collStatsDocuments) == len(stagesDocuments):
 		filter, sort := aggregations.GetPushdownQuery(aggregationStages)
 
 		// only documents stages or no stages - fetch documents from the DB and apply stages to them
 		qp := new(backends.QueryParams)
 
-		if !h.DisablePushdown {
+		if !h.disablePushdown.Load() {
 			qp.Filter = filter
 		}
 
-		if !h.EnableNestedPushdown && filter != nil {
+		if !h.nestedPushdown() && filter != nil {
 			qp.Filter = filter.DeepCopy()
 
 			for _, k := range qp.Filter.Keys() {
@@ -324,21 +332,21 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		}
 
 		switch {
-		case h.DisablePushdown:
+		case h.disablePushdown.Load():
 			// Pushdown disabled
-		case sort.Len() == 0 && cInfo.Capped():
-			// Pushdown default recordID sorting for capped collections
+		case sort.Len() == 0 && (cInfo.Capped() || cInfo.Clustered):
+			// Pushdown default recordID sorting for capped collections and _id sorting for clustered collections
 			qp.Sort = must.NotFail(types.NewDocument("$natural", int64(1)))
 		case sort.Len() == 1:
 			if sort.Keys()[0] != "$natural" {
 				break
 			}
 
-			if !cInfo.Capped() {
+			if !cInfo.Capped() && !cInfo.Clustered {
 				closer.Close()
 				return nil, handlererrors.NewCommandErrorMsgWithArgument(
 					handlererrors.ErrNotImplemented,
-					"$natural sort for non-capped collection is not supported.",
+					"$natural sort for non-capped and non-clustered collection is not supported.",
 					"aggregate",
 				)
 			}
@@ -346,8 +354,19 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 			qp.Sort = sort
 		}
 
-		iter, err = processStagesDocuments(ctx, closer, &stagesDocumentsParams{c, qp, stagesDocuments})
-	} else {
+		tracker := h.newQueryTracker(&querystats.Key{
+			DB:         dbName,
+			Collection: cName,
+			Command:    "aggregate",
+			Shape:      must.NotFail(types.NewDocument("pipeline", querystats.Shape(pipeline))),
+		}, qp)
+
+		iter, err = processStagesDocuments(ctx, closer, &stagesDocumentsParams{c, qp, stagesDocuments, tracker})
+		if err == nil {
+			iter = tracker.Returned(iter)
+		}
+
+	default:
 		// TODO https://github.com/FerretDB/FerretDB/issues/2423
 		statistics := stages.GetStatistics(collStatsDocuments)
 
@@ -372,7 +391,7 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 
 	cursorID := cursor.ID
 
-	docs, err := iterator.ConsumeValuesN(cursor, int(batchSize))
+	docs, done, err := h.consumeBatch(cursor, batchSize)
 	if err != nil {
 		return nil, handleMaxTimeMSError(err, maxTimeMS, "aggregate")
 	}
@@ -387,11 +406,13 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 		firstBatch.Append(doc)
 	}
 
-	if firstBatch.Len() < int(batchSize) {
+	if done {
 		// let the client know that there are no more results
 		cursorID = 0
 
 		cursor.Close()
+	} else {
+		h.prefetchBatch(cursor, batchSize)
 	}
 
 	var reply wire.OpMsg
@@ -411,9 +432,10 @@ func (h *Handler) MsgAggregate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 
 // stagesDocumentsParams contains the parameters for processStagesDocuments.
 type stagesDocumentsParams struct {
-	c      backends.Collection
-	qp     *backends.QueryParams
-	stages []aggregations.Stage
+	c       backends.Collection
+	qp      *backends.QueryParams
+	stages  []aggregations.Stage
+	tracker *querystats.Tracker
 }
 
 // processStagesDocuments retrieves the documents from the database and then processes them through the stages.
@@ -426,7 +448,7 @@ func processStagesDocuments(ctx context.Context, closer *iterator.MultiCloser, p
 
 	closer.Add(queryRes.Iter)
 
-	iter := queryRes.Iter
+	iter := p.tracker.Examined(queryRes.Iter)
 
 	for _, s := range p.stages {
 		if iter, err = s.Process(ctx, iter, closer); err != nil {
@@ -437,6 +459,54 @@ func processStagesDocuments(ctx context.Context, closer *iterator.MultiCloser, p
 	return iter, nil
 }
 
+// validateQueryStatsStage validates $queryStats stage document.
+func validateQueryStatsStage(stage *types.Document) error {
+	fields, err := common.GetRequiredParam[*types.Document](stage, "$queryStats")
+	if err != nil {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf("$queryStats must take a nested object but found: %s", types.FormatAnyValue(stage)),
+			"$queryStats (stage)",
+		)
+	}
+
+	if fields.Has("transformIdentifiers") {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			"$queryStats.transformIdentifiers is not implemented yet",
+			"$queryStats (stage)",
+		)
+	}
+
+	for _, k := range fields.Keys() {
+		if k != "transformIdentifiers" {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '$queryStats.%s' is an unknown field.", k),
+				"$queryStats (stage)",
+			)
+		}
+	}
+
+	return nil
+}
+
+// processStagesQueryStats returns collected query statistics processed through the given stages.
+func processStagesQueryStats(ctx context.Context, closer *iterator.MultiCloser, r *querystats.Registry, stages []aggregations.Stage) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	iter := iterator.Values(iterator.ForSlice(r.Documents()))
+	closer.Add(iter)
+
+	var err error
+
+	for _, s := range stages {
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	return iter, nil
+}
+
 // stagesStatsParams contains the parameters for processStagesStats.
 type stagesStatsParams struct {
 	c          backends.Collection
@@ -455,6 +525,7 @@ func processStagesStats(ctx context.Context, closer *iterator.MultiCloser, p *st
 	// Clarify what needs to be retrieved from the database and retrieve it.
 	_, hasCount := p.statistics[stages.StatisticCount]
 	_, hasStorage := p.statistics[stages.StatisticStorage]
+	_, hasSchema := p.statistics[stages.StatisticSchema]
 
 	var host string
 	var err error
@@ -553,6 +624,21 @@ func processStagesStats(ctx context.Context, closer *iterator.MultiCloser, p *st
 		)
 	}
 
+	if hasSchema {
+		sampleSize := stages.SchemaSampleSize(p.stages)
+		if sampleSize == 0 {
+			sampleSize = schemaDefaultSampleSize
+		}
+
+		var schema *types.Document
+
+		if schema, err = discoverSchema(ctx, p.c, sampleSize); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		doc.Set("schema", schema)
+	}
+
 	// Process the retrieved statistics through the stages.
 	iter := iterator.Values(iterator.ForSlice([]*types.Document{doc}))
 	closer.Add(iter)
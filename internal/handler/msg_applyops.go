@@ -0,0 +1,135 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// MsgApplyOps implements `applyOps` command.
+//
+// Only no-op (`n`), insert (`i`), update (`u`), and delete (`d`) oplog entries for user collections are supported.
+// They are sent by mongorestore with `--oplogReplay` and by other tools that copy oplogs.
+// Entries are applied one by one, not atomically; the first error stops the command.
+func (h *Handler) MsgApplyOps(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	common.Ignored(document, h.L, "comment", "writeConcern", "bypassDocumentValidation")
+
+	if err = common.Unimplemented(document, "preCondition"); err != nil {
+		return nil, err
+	}
+
+	command := document.Command()
+
+	ops, err := common.GetRequiredParam[*types.Array](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	alwaysUpsert := true
+
+	if v, _ := document.Get("alwaysUpsert"); v != nil {
+		if alwaysUpsert, err = handlerparams.GetBoolOptionalParam("alwaysUpsert", v); err != nil {
+			return nil, err
+		}
+	}
+
+	// all entries are checked before any of them is applied
+	entries := make([]*types.Document, 0, ops.Len())
+
+	iter := ops.Iterator()
+	defer iter.Close()
+
+	for {
+		_, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		op, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("cannot apply a malformed operation in %s: %s", command, types.FormatAnyValue(v)),
+				command,
+			)
+		}
+
+		opType, err := common.GetRequiredParam[string](op, "op")
+		if err != nil {
+			return nil, err
+		}
+
+		switch opType {
+		case "n":
+			// nothing to check
+		case "i", "u", "d":
+			if err = checkApplyOpNamespace(command, op); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				fmt.Sprintf("%s operation type %q is not implemented yet", command, opType),
+				command,
+			)
+		}
+
+		entries = append(entries, op)
+	}
+
+	results := types.MakeArray(len(entries))
+
+	for _, op := range entries {
+		// no-op entries are applied by doing nothing
+		if opType := must.NotFail(op.Get("op")).(string); opType != "n" {
+			if err = h.applyOp(ctx, command, opType, op, alwaysUpsert); err != nil {
+				return nil, err
+			}
+		}
+
+		results.Append(true)
+	}
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
+		must.NotFail(types.NewDocument(
+			"applied", int32(results.Len()),
+			"results", results,
+			"ok", float64(1),
+		)),
+	)))
+
+	return &reply, nil
+}
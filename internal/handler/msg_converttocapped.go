@@ -0,0 +1,142 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// MsgConvertToCapped implements `convertToCapped` command.
+//
+// Documents are copied to a temporary capped collection that replaces the original one,
+// so indexes other than the default one are dropped, as in MongoDB.
+// If the replacement fails, the original collection is restored.
+func (h *Handler) MsgConvertToCapped(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	common.Ignored(document, h.L, "writeConcern", "comment")
+
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := getCappedCloneSize(document)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s.%s'", dbName, collection)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	src, err := getCappedCloneSource(ctx, db, command, dbName, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	n := rand.Intn(100000)
+	tmp := fmt.Sprintf("tmp%05d.convertToCapped.%s", n, collection)
+	backup := fmt.Sprintf("tmp%05d.convertToCapped.backup.%s", n, collection)
+
+	dropTmp := func(name string) {
+		dropErr := db.DropCollection(context.WithoutCancel(ctx), &backends.DropCollectionParams{Name: name})
+		if dropErr != nil && !backends.ErrorCodeIs(dropErr, backends.ErrorCodeCollectionDoesNotExist) {
+			h.L.Warn("Failed to drop temporary collection.", zap.String("collection", name), zap.Error(dropErr))
+		}
+	}
+
+	if err = cloneAsCapped(ctx, db, src, tmp, size); err != nil {
+		dropTmp(tmp)
+
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid collection name: %s", tmp)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	// backends do not support replacing renames, so the original collection is renamed first;
+	// it is restored if the capped copy could not take its place
+	err = db.RenameCollection(ctx, &backends.RenameCollectionParams{OldName: collection, NewName: backup})
+	if err != nil {
+		dropTmp(tmp)
+		return nil, lazyerrors.Error(err)
+	}
+
+	err = db.RenameCollection(ctx, &backends.RenameCollectionParams{OldName: tmp, NewName: collection})
+	if err != nil {
+		restoreErr := db.RenameCollection(
+			context.WithoutCancel(ctx),
+			&backends.RenameCollectionParams{OldName: backup, NewName: collection},
+		)
+		if restoreErr != nil {
+			h.L.Error(
+				"Failed to restore the original collection.",
+				zap.String("collection", collection), zap.String("original", backup), zap.String("capped", tmp),
+				zap.Error(restoreErr),
+			)
+
+			return nil, lazyerrors.Errorf(
+				"convertToCapped failed: original documents of %[1]s.%[2]s are in %[1]s.%[3]s, capped copy is in %[1]s.%[4]s: %[5]w",
+				dbName, collection, backup, tmp, err,
+			)
+		}
+
+		dropTmp(tmp)
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	dropTmp(backup)
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
+		must.NotFail(types.NewDocument(
+			"ok", float64(1),
+		)),
+	)))
+
+	return &reply, nil
+}
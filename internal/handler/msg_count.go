@@ -22,6 +22,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/querystats"
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
@@ -61,17 +62,33 @@ func (h *Handler) MsgCount(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, e
 		return nil, lazyerrors.Error(err)
 	}
 
+	if c, err = h.withArchive(ctx, db, params.DB, params.Collection, c); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	var qp backends.QueryParams
-	if !h.DisablePushdown {
+	if !h.disablePushdown.Load() {
 		qp.Filter = params.Filter
 	}
 
+	shape := must.NotFail(types.NewDocument())
+	if params.Filter != nil {
+		shape.Set("query", querystats.Shape(params.Filter))
+	}
+
+	tracker := h.newQueryTracker(&querystats.Key{
+		DB:         params.DB,
+		Collection: params.Collection,
+		Command:    "count",
+		Shape:      shape,
+	}, &qp)
+
 	queryRes, err := c.Query(ctx, &qp)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	iter := queryRes.Iter
+	iter := tracker.Examined(queryRes.Iter)
 
 	closer := iterator.NewMultiCloser(iter)
 	defer closer.Close()
@@ -84,6 +101,9 @@ func (h *Handler) MsgCount(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, e
 
 	iter = common.CountIterator(iter, closer, "count")
 
+	iter = tracker.Returned(iter)
+	closer.Add(iter)
+
 	_, res, err := iter.Next()
 	if errors.Is(err, iterator.ErrIteratorDone) {
 		err = nil
@@ -16,13 +16,18 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/decorators/timeseries"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/wire"
@@ -36,11 +41,7 @@ func (h *Handler) MsgCreate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 	}
 
 	unimplementedFields := []string{
-		"timeseries",
 		"expireAfterSeconds",
-		"validator",
-		"validationLevel",
-		"validationAction",
 		"viewOn",
 		"pipeline",
 		"collation",
@@ -51,7 +52,6 @@ func (h *Handler) MsgCreate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 
 	ignoredFields := []string{
 		"autoIndexId",
-		"storageEngine",
 		"indexOptionDefaults",
 		"writeConcern",
 		"comment",
@@ -102,6 +102,37 @@ func (h *Handler) MsgCreate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		}
 	}
 
+	if err = getValidationParams(document, &params); err != nil {
+		return nil, err
+	}
+
+	if err = getTimeSeriesParams(document, &params); err != nil {
+		return nil, err
+	}
+
+	if capped && params.TimeSeries != nil {
+		msg := "Time-series collections cannot be capped"
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, "create")
+	}
+
+	if err = getClusteredIndexParams(document, &params); err != nil {
+		return nil, err
+	}
+
+	if params.Clustered && (capped || params.TimeSeries != nil) {
+		msg := "The clusteredIndex option is not supported for capped and time-series collections"
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, "create")
+	}
+
+	if err = getStorageEngineParams(document, h.BackendName, &params); err != nil {
+		return nil, err
+	}
+
+	if params.Codec != "" && params.TimeSeries != nil {
+		msg := fmt.Sprintf("Time-series collections do not support the %q storage codec", params.Codec)
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidOptions, msg, "create")
+	}
+
 	db, err := h.b.Database(dbName)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
@@ -112,10 +143,35 @@ func (h *Handler) MsgCreate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	t, err := h.collectionTemplate(ctx, dbName, collectionName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// explicitly given options take precedence over template's options
+	if t != nil && !capped && params.TimeSeries == nil && !params.Clustered {
+		params.CappedSize = t.cappedSize
+		params.CappedDocuments = t.cappedDocuments
+	}
+
+	if err = h.checkCollectionQuota(ctx, db, dbName, collectionName, "create"); err != nil {
+		return nil, err
+	}
+
 	err = db.CreateCollection(ctx, &params)
 
 	switch {
 	case err == nil:
+		if t != nil {
+			if err = createTemplateIndexes(ctx, db, t, collectionName); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+		}
+
+		if err = createTimeSeriesIndex(ctx, db, collectionName, params.TimeSeries); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
 		var reply wire.OpMsg
 		must.NoError(reply.SetSections(wire.MakeOpMsgSection(
 			must.NotFail(types.NewDocument(
@@ -137,3 +193,468 @@ func (h *Handler) MsgCreate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 }
+
+// getValidationParams sets document validation parameters of `create` command.
+func getValidationParams(document *types.Document, params *backends.CreateCollectionParams) error {
+	command := document.Command()
+
+	validator, err := common.GetOptionalParam[*types.Document](document, "validator", nil)
+	if err != nil {
+		return err
+	}
+
+	level := common.ValidationLevelStrict
+	if level, err = common.GetOptionalParam(document, "validationLevel", level); err != nil {
+		return err
+	}
+
+	switch level {
+	case common.ValidationLevelOff, common.ValidationLevelStrict, common.ValidationLevelModerate:
+		// valid
+	default:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Enumeration value '%s' for field '%s.validationLevel' is not a valid value.", level, command),
+			command,
+		)
+	}
+
+	action := common.ValidationActionError
+	if action, err = common.GetOptionalParam(document, "validationAction", action); err != nil {
+		return err
+	}
+
+	switch action {
+	case common.ValidationActionError, common.ValidationActionWarn:
+		// valid
+	default:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Enumeration value '%s' for field '%s.validationAction' is not a valid value.", action, command),
+			command,
+		)
+	}
+
+	if validator == nil {
+		return nil
+	}
+
+	if err = common.ValidateValidator(command, validator); err != nil {
+		return err
+	}
+
+	params.Validator = validator
+	params.ValidationLevel = level
+	params.ValidationAction = action
+
+	return nil
+}
+
+// getTimeSeriesParams sets time-series parameters of `create` command.
+func getTimeSeriesParams(document *types.Document, params *backends.CreateCollectionParams) error {
+	command := document.Command()
+
+	ts, err := common.GetOptionalParam[*types.Document](document, "timeseries", nil)
+	if err != nil {
+		return err
+	}
+
+	if ts == nil {
+		return nil
+	}
+
+	if err = common.Unimplemented(ts, "bucketMaxSpanSeconds", "bucketRoundingSeconds"); err != nil {
+		return err
+	}
+
+	res := &backends.TimeSeriesOptions{
+		Granularity: timeseries.GranularitySeconds,
+	}
+
+	iter := ts.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		var s string
+
+		switch k {
+		case "timeField", "metaField", "granularity":
+			var ok bool
+			if s, ok = v.(string); !ok {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field '%s.timeseries.%s' is the wrong type '%s', expected type 'string'",
+						command, k, handlerparams.AliasFromType(v),
+					),
+					command,
+				)
+			}
+		default:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '%s.timeseries.%s' is an unknown field.", command, k),
+				command,
+			)
+		}
+
+		switch k {
+		case "timeField":
+			res.TimeField = s
+		case "metaField":
+			res.MetaField = s
+		case "granularity":
+			res.Granularity = s
+		}
+	}
+
+	if res.TimeField == "" {
+		if !ts.Has("timeField") {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMissingField,
+				fmt.Sprintf("BSON field '%s.timeseries.timeField' is missing but a required field", command),
+				command,
+			)
+		}
+
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"The 'timeField' must be a non-empty string",
+			command,
+		)
+	}
+
+	for _, f := range []struct {
+		name  string
+		value string
+	}{
+		{"timeField", res.TimeField},
+		{"metaField", res.MetaField},
+	} {
+		if strings.Contains(f.value, ".") || strings.HasPrefix(f.value, "$") {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("The '%s' field cannot contain dots or start with '$': %s", f.name, f.value),
+				command,
+			)
+		}
+	}
+
+	if ts.Has("metaField") {
+		switch res.MetaField {
+		case "":
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"The 'metaField' must be a non-empty string",
+				command,
+			)
+		case "_id":
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"The 'metaField' cannot be \"_id\"",
+				command,
+			)
+		case res.TimeField:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"The 'metaField' cannot be the same as the 'timeField'",
+				command,
+			)
+		}
+	}
+
+	switch res.Granularity {
+	case timeseries.GranularitySeconds, timeseries.GranularityMinutes, timeseries.GranularityHours:
+		// valid
+	default:
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf(
+				"Enumeration value '%s' for field '%s.timeseries.granularity' is not a valid value.",
+				res.Granularity, command,
+			),
+			command,
+		)
+	}
+
+	params.TimeSeries = res
+
+	return nil
+}
+
+// getClusteredIndexParams sets clustered index parameters of `create` command.
+//
+// Only the clustered index on _id with the default name is supported.
+func getClusteredIndexParams(document *types.Document, params *backends.CreateCollectionParams) error {
+	command := document.Command()
+
+	ci, err := common.GetOptionalParam[*types.Document](document, "clusteredIndex", nil)
+	if err != nil {
+		return err
+	}
+
+	if ci == nil {
+		return nil
+	}
+
+	for _, k := range []string{"key", "unique"} {
+		if !ci.Has(k) {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMissingField,
+				fmt.Sprintf("BSON field '%s.clusteredIndex.%s' is missing but a required field", command, k),
+				command,
+			)
+		}
+	}
+
+	iter := ci.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "key":
+			key, ok := v.(*types.Document)
+			if !ok || key.Len() != 1 || !key.Has("_id") {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrInvalidIndexSpecificationOption,
+					"The clusteredIndex option is only supported for key: {_id: 1}",
+					command,
+				)
+			}
+
+			if order, err := handlerparams.GetWholeNumberParam(must.NotFail(key.Get("_id"))); err != nil || order != 1 {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrInvalidIndexSpecificationOption,
+					"The clusteredIndex option is only supported for key: {_id: 1}",
+					command,
+				)
+			}
+
+		case "unique":
+			if unique, ok := v.(bool); !ok || !unique {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrInvalidIndexSpecificationOption,
+					"The clusteredIndex option requires unique: true",
+					command,
+				)
+			}
+
+		case "name":
+			if name, ok := v.(string); !ok || name != backends.DefaultIndexName {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNotImplemented,
+					fmt.Sprintf("The clusteredIndex option supports only the default name %q", backends.DefaultIndexName),
+					command,
+				)
+			}
+
+		case "v":
+			if version, err := handlerparams.GetWholeNumberParam(v); err != nil || version != 2 {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("Invalid clusteredIndex version: %s", types.FormatAnyValue(v)),
+					command,
+				)
+			}
+
+		default:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '%s.clusteredIndex.%s' is an unknown field.", command, k),
+				command,
+			)
+		}
+	}
+
+	params.Clustered = true
+
+	return nil
+}
+
+// storageCodecs contains document storage codecs supported by backends.
+// The first codec is the default one.
+var storageCodecs = map[string][]string{
+	"postgresql": {"jsonb", "bson", "hybrid"},
+}
+
+// storageCodecHybrid is the only codec that accepts a list of fields.
+const storageCodecHybrid = "hybrid"
+
+// getStorageEngineParams sets storage codec parameters of `create` command.
+//
+// Options for other storage engines and backends without codecs are ignored, like MongoDB does.
+// The default codec is not set explicitly.
+func getStorageEngineParams(document *types.Document, backendName string, params *backends.CreateCollectionParams) error {
+	command := document.Command()
+
+	se, err := common.GetOptionalParam[*types.Document](document, "storageEngine", nil)
+	if err != nil {
+		return err
+	}
+
+	if se == nil {
+		return nil
+	}
+
+	for _, k := range se.Keys() {
+		if _, ok := must.NotFail(se.Get(k)).(*types.Document); !ok {
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf("'storageEngine.%s' has to be an embedded document.", k),
+				command,
+			)
+		}
+	}
+
+	codecs := storageCodecs[backendName]
+	if len(codecs) == 0 || !se.Has(backendName) {
+		return nil
+	}
+
+	opts := must.NotFail(se.Get(backendName)).(*types.Document)
+
+	var codec string
+	var fields []string
+
+	iter := opts.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		switch k {
+		case "codec":
+			var ok bool
+			if codec, ok = v.(string); !ok {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field '%s.storageEngine.%s.codec' is the wrong type '%s', expected type 'string'",
+						command, backendName, handlerparams.AliasFromType(v),
+					),
+					command,
+				)
+			}
+
+			if !slices.Contains(codecs, codec) {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf(
+						"Enumeration value '%s' for field '%s.storageEngine.%s.codec' is not a valid value.",
+						codec, command, backendName,
+					),
+					command,
+				)
+			}
+
+		case "fields":
+			arr, ok := v.(*types.Array)
+			if !ok {
+				return handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field '%s.storageEngine.%s.fields' is the wrong type '%s', expected type 'array'",
+						command, backendName, handlerparams.AliasFromType(v),
+					),
+					command,
+				)
+			}
+
+			for i := 0; i < arr.Len(); i++ {
+				f, ok := must.NotFail(arr.Get(i)).(string)
+				if !ok || f == "" || strings.Contains(f, ".") || strings.HasPrefix(f, "$") {
+					return handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrBadValue,
+						fmt.Sprintf(
+							"'%s.storageEngine.%s.fields' must contain non-empty top-level field names: %s",
+							command, backendName, types.FormatAnyValue(arr),
+						),
+						command,
+					)
+				}
+
+				if !slices.Contains(fields, f) {
+					fields = append(fields, f)
+				}
+			}
+
+		default:
+			return handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '%s.storageEngine.%s.%s' is an unknown field.", command, backendName, k),
+				command,
+			)
+		}
+	}
+
+	if opts.Has("fields") && codec != storageCodecHybrid {
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			fmt.Sprintf("'%s.storageEngine.%s.fields' is only supported by the %q codec", command, backendName, storageCodecHybrid),
+			command,
+		)
+	}
+
+	if codec == "" || codec == codecs[0] {
+		return nil
+	}
+
+	params.Codec = codec
+	params.CodecFields = fields
+
+	return nil
+}
+
+// createTimeSeriesIndex creates a compound index on meta and time fields of a new time-series collection,
+// like MongoDB does.
+// It does nothing if the collection is not a time-series collection or if it does not have a meta field.
+func createTimeSeriesIndex(ctx context.Context, db backends.Database, cName string, ts *backends.TimeSeriesOptions) error {
+	if ts == nil || ts.MetaField == "" {
+		return nil
+	}
+
+	c, err := db.Collection(cName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	_, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{
+		Indexes: []backends.IndexInfo{{
+			Name: ts.MetaField + "_1_" + ts.TimeField + "_1",
+			Key: []backends.IndexKeyPair{
+				{Field: ts.MetaField},
+				{Field: ts.TimeField},
+			},
+		}},
+	})
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
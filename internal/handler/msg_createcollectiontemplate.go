@@ -0,0 +1,110 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// MsgCreateCollectionTemplate implements `createCollectionTemplate` command.
+func (h *Handler) MsgCreateCollectionTemplate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName != "admin" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrUnauthorized,
+			command+" may only be run against the admin database.",
+			command,
+		)
+	}
+
+	common.Ignored(document, h.L, "comment", "writeConcern")
+
+	name, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"Collection template name cannot be empty",
+			command,
+		)
+	}
+
+	// validate before storing
+	if _, err = parseCollectionTemplate(command, document, name); err != nil {
+		return nil, err
+	}
+
+	saved := must.NotFail(types.NewDocument("_id", name))
+
+	for _, k := range []string{"pattern", "indexes", "capped", "size", "max"} {
+		if v, _ := document.Get(k); v != nil {
+			saved.Set(k, v)
+		}
+	}
+
+	c, err := h.collectionTemplatesCollection()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	_, err = c.InsertAll(ctx, &backends.InsertAllParams{
+		Docs: []*types.Document{saved},
+	})
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNamespaceExists,
+				fmt.Sprintf("Collection template %s already exists", name),
+				command,
+			)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	h.templates.invalidate()
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
+		must.NotFail(types.NewDocument(
+			"ok", float64(1),
+		)),
+	)))
+
+	return &reply, nil
+}
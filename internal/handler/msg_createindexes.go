@@ -124,13 +124,25 @@ func (h *Handler) MsgCreateIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.
 	if err != nil {
 		switch {
 		case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
-			// If the namespace doesn't exist, we just don't need to compare new indexes with existing ones,
-			// the namespace will be created when indexes are created.
-			beforeCreate = &backends.ListIndexesResult{
-				Indexes: []backends.IndexInfo{},
+			if err = h.checkImplicitCollection(ctx, db, dbName, collection, command); err != nil {
+				return nil, err
 			}
+
 			createCollection = true
 
+			// collection might be created from the template with its indexes
+			if beforeCreate, err = c.ListIndexes(ctx, new(backends.ListIndexesParams)); err != nil {
+				if !backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+					return nil, lazyerrors.Error(err)
+				}
+
+				// If the namespace doesn't exist, we just don't need to compare new indexes with existing ones,
+				// the namespace will be created when indexes are created.
+				beforeCreate = &backends.ListIndexesResult{
+					Indexes: []backends.IndexInfo{},
+				}
+			}
+
 		default:
 			return nil, lazyerrors.Error(err)
 		}
@@ -147,8 +159,22 @@ func (h *Handler) MsgCreateIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.
 		return nil, err
 	}
 
-	_, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{Indexes: toCreate})
+	op := h.currentOps.Start(dbName+"."+collection, document)
+	defer op.Finish()
+
+	_, err = c.CreateIndexes(ctx, &backends.CreateIndexesParams{
+		Indexes:  toCreate,
+		Progress: op.SetProgress,
+	})
 	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeNotSupported) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrCannotCreateIndex,
+				"Index fields are not supported by the collection's storage codec",
+				command,
+			)
+		}
+
 		return nil, lazyerrors.Error(err)
 	}
 
@@ -351,7 +377,20 @@ func processIndex(command string, indexDoc *types.Document) (*backends.IndexInfo
 				index.Unique = true
 			}
 
-		case "background":
+		case "v":
+			// index version is set by mongodump/mongorestore and other tools that copy index specifications
+			v := must.NotFail(indexDoc.Get("v"))
+
+			version, err := handlerparams.GetWholeNumberParam(v)
+			if err != nil || version < 1 || version > 2 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrCannotCreateIndex,
+					fmt.Sprintf("Index version v=%s is not supported", types.FormatAnyValue(v)),
+					command,
+				)
+			}
+
+		case "background", "ns":
 			// ignore deprecated options
 
 		case "sparse", "partialFilterExpression", "expireAfterSeconds":
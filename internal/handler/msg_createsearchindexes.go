@@ -0,0 +1,202 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// MsgCreateSearchIndexes implements `createSearchIndexes` command.
+func (h *Handler) MsgCreateSearchIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := document.Command()
+
+	c, err := h.searchIndexesCollection(document)
+	if err != nil {
+		return nil, err
+	}
+
+	specs, err := common.GetRequiredParam[*types.Array](document, "indexes")
+	if err != nil {
+		return nil, err
+	}
+
+	if specs.Len() == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"Must specify at least one search index to create",
+			command,
+		)
+	}
+
+	indexes := make([]backends.SearchIndexInfo, 0, specs.Len())
+
+	for _, v := range must.NotFail(iterator.ConsumeValues(specs.Iterator())) {
+		spec, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"BSON field 'createSearchIndexes.indexes' must be an array of objects",
+				command,
+			)
+		}
+
+		var index *backends.SearchIndexInfo
+		if index, err = processSearchIndexSpec(command, spec); err != nil {
+			return nil, err
+		}
+
+		indexes = append(indexes, *index)
+	}
+
+	list, err := c.ListSearchIndexes(ctx, new(backends.ListSearchIndexesParams))
+
+	switch {
+	case err == nil:
+		// nothing
+	case backends.ErrorCodeIs(err, backends.ErrorCodeNotSupported):
+		return nil, searchNotSupportedError(command)
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		list = new(backends.ListSearchIndexesResult)
+	default:
+		return nil, lazyerrors.Error(err)
+	}
+
+	for i, index := range indexes {
+		duplicate := func(si backends.SearchIndexInfo) bool { return si.Name == index.Name }
+		if slices.ContainsFunc(list.Indexes, duplicate) || slices.ContainsFunc(indexes[:i], duplicate) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrIndexAlreadyExists,
+				fmt.Sprintf("Search index %q already exists", index.Name),
+				command,
+			)
+		}
+	}
+
+	if _, err = c.CreateSearchIndexes(ctx, &backends.CreateSearchIndexesParams{Indexes: indexes}); err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeNotSupported) {
+			return nil, searchNotSupportedError(command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	created := types.MakeArray(len(indexes))
+
+	for _, index := range indexes {
+		// index names are used as identifiers
+		created.Append(must.NotFail(types.NewDocument(
+			"id", index.Name,
+			"name", index.Name,
+		)))
+	}
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
+		must.NotFail(types.NewDocument(
+			"indexesCreated", created,
+			"ok", float64(1),
+		)),
+	)))
+
+	return &reply, nil
+}
+
+// processSearchIndexSpec parses and validates a single search index specification
+// of `createSearchIndexes` command.
+func processSearchIndexSpec(command string, spec *types.Document) (*backends.SearchIndexInfo, error) {
+	name, typ := defaultSearchIndexName, searchIndexTypeSearch
+
+	var definition *types.Document
+
+	for _, k := range spec.Keys() {
+		v := must.NotFail(spec.Get(k))
+
+		var ok bool
+
+		switch k {
+		case "name":
+			if name, ok = v.(string); !ok || name == "" {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"Search index name must be a non-empty string",
+					command,
+				)
+			}
+
+		case "definition":
+			if definition, ok = v.(*types.Document); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					"BSON field 'createSearchIndexes.indexes.definition' must be an object",
+					command,
+				)
+			}
+
+		case "type":
+			if typ, ok = v.(string); !ok || (typ != searchIndexTypeSearch && typ != searchIndexTypeVectorSearch) {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNotImplemented,
+					fmt.Sprintf("Search index type %s is not supported", types.FormatAnyValue(v)),
+					command,
+				)
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field 'createSearchIndexes.indexes.%s' is an unknown field.", k),
+				command,
+			)
+		}
+	}
+
+	if definition == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrMissingField,
+			"BSON field 'createSearchIndexes.indexes.definition' is missing but a required field",
+			command,
+		)
+	}
+
+	parse := parseSearchIndexDefinition
+	if typ == searchIndexTypeVectorSearch {
+		parse = parseVectorSearchIndexDefinition
+	}
+
+	index, err := parse(command, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	index.Name = name
+
+	return index, nil
+}
@@ -16,21 +16,109 @@ package handler
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/wire"
 )
 
 // MsgCurrentOp implements `currentOp` command.
+//
+// Only long-running operations, such as index builds, are reported.
+// With `$all: true`, idle client connections are reported too.
 func (h *Handler) MsgCurrentOp(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	common.Ignored(document, h.L, "$ownOps", "comment")
+
+	var all bool
+	if v, _ := document.Get("$all"); v != nil {
+		if all, err = handlerparams.GetBoolOptionalParam("$all", v); err != nil {
+			return nil, err
+		}
+	}
+
+	// all other top-level fields are filter conditions
+	filter := new(types.Document)
+
+	for _, k := range document.Keys() {
+		if k == document.Command() || k == "lsid" || k == "comment" || strings.HasPrefix(k, "$") {
+			continue
+		}
+
+		filter.Set(k, must.NotFail(document.Get(k)))
+	}
+
+	inprog := types.MakeArray(0)
+
+	docs := h.currentOps.Documents()
+	if all {
+		docs = append(docs, connDocuments(h.conns.Conns())...)
+	}
+
+	for _, doc := range docs {
+		matches, err := common.FilterDocument(doc, filter)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if matches {
+			inprog.Append(doc)
+		}
+	}
+
+	res := must.NotFail(types.NewDocument(
+		"inprog", inprog,
+	))
+
+	if h.fsyncLock.Count() > 0 {
+		res.Set("fsyncLock", true)
+	}
+
+	res.Set("ok", float64(1))
+
 	var reply wire.OpMsg
-	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
-		must.NotFail(types.NewDocument(
-			"inprog", must.NotFail(types.NewArray()),
-			"ok", float64(1),
-		)),
-	)))
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(res)))
 
 	return &reply, nil
 }
+
+// connDocuments returns `currentOp` command's `inprog` documents for idle client connections.
+func connDocuments(conns []*conninfo.Conn) []*types.Document {
+	res := make([]*types.Document, len(conns))
+
+	for i, c := range conns {
+		doc := must.NotFail(types.NewDocument(
+			"type", "idleConnection",
+			"active", false,
+			"connectionId", int32(c.ID),
+			"desc", fmt.Sprintf("conn%d", c.ID),
+			"connectedAt", c.Started,
+		))
+
+		if c.ConnInfo.PeerAddr != "" {
+			doc.Set("client", c.ConnInfo.PeerAddr)
+		}
+
+		if md := c.ConnInfo.ClientMetadata(); md != nil {
+			if md.AppName != "" {
+				doc.Set("appName", md.AppName)
+			}
+
+			doc.Set("clientMetadata", clientMetadataDocument(md))
+		}
+
+		res[i] = doc
+	}
+
+	return res
+}
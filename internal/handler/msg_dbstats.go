@@ -152,6 +152,15 @@ func (h *Handler) MsgDBStats(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		)
 	}
 
+	quotaDoc, err := h.quotaUsage(ctx, dbName, int64(len(list.Collections)), stats.CountDocuments, stats.SizeCollections, scale)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if quotaDoc != nil {
+		pairs = append(pairs, "quota", quotaDoc)
+	}
+
 	pairs = append(pairs,
 		"scaleFactor", scale,
 		"ok", float64(1),
@@ -15,9 +15,12 @@
 package handler
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 
 	"go.mongodb.org/mongo-driver/mongo"
 
@@ -63,42 +66,65 @@ func (h *Handler) MsgDelete(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	var mu sync.Mutex
 	var deleted int32
-	writeErrors := types.MakeArray(0)
+	var writeErrors []*mongo.WriteError
 
-	for i, p := range params.Deletes {
-		d, err := h.execDelete(ctx, c, &p)
+	exec := func(ctx context.Context, i int) error {
+		d, err := h.execDelete(ctx, c, &params.Deletes[i])
+
+		mu.Lock()
+		defer mu.Unlock()
 
 		deleted += d
 
-		if err != nil {
-			var ce *handlererrors.CommandError
-			if errors.As(err, &ce) {
-				we := &mongo.WriteError{
-					Index:   i,
-					Code:    int(ce.Code()),
-					Message: ce.Err().Error(),
-				}
+		if err == nil {
+			return nil
+		}
 
-				writeErrors.Append(WriteErrorDocument(we))
+		var ce *handlererrors.CommandError
+		if !errors.As(err, &ce) {
+			return lazyerrors.Error(err)
+		}
 
-				if params.Ordered {
-					break
-				}
+		writeErrors = append(writeErrors, &mongo.WriteError{
+			Index:   i,
+			Code:    int(ce.Code()),
+			Message: ce.Err().Error(),
+		})
 
-				continue
-			}
+		return nil
+	}
 
-			return nil, lazyerrors.Error(err)
+	if !params.Ordered && h.BulkWriteConcurrency > 1 {
+		err = h.bulkWrite(ctx, len(params.Deletes), exec)
+	} else {
+		for i := range params.Deletes {
+			if err = exec(ctx, i); err != nil || (params.Ordered && len(writeErrors) > 0) {
+				break
+			}
 		}
 	}
 
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	res := must.NotFail(types.NewDocument(
 		"n", deleted,
 	))
 
-	if writeErrors.Len() > 0 {
-		res.Set("writeErrors", writeErrors)
+	if len(writeErrors) > 0 {
+		slices.SortFunc(writeErrors, func(a, b *mongo.WriteError) int {
+			return cmp.Compare(a.Index, b.Index)
+		})
+
+		array := types.MakeArray(len(writeErrors))
+		for _, we := range writeErrors {
+			array.Append(WriteErrorDocument(we))
+		}
+
+		res.Set("writeErrors", array)
 	}
 
 	res.Set("ok", float64(1))
@@ -117,7 +143,7 @@ func (h *Handler) MsgDelete(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 // The error is either a (wrapped) *handlererrors.CommandError or something fatal.
 func (h *Handler) execDelete(ctx context.Context, c backends.Collection, p *common.Delete) (int32, error) {
 	var qp backends.QueryParams
-	if !h.DisablePushdown {
+	if !h.disablePushdown.Load() {
 		qp.Filter = p.Filter
 	}
 
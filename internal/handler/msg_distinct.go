@@ -60,11 +60,15 @@ func (h *Handler) MsgDistinct(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 		return nil, lazyerrors.Error(err)
 	}
 
+	if c, err = h.withArchive(ctx, db, params.DB, params.Collection, c); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	closer := iterator.NewMultiCloser()
 	defer closer.Close()
 
 	var qp backends.QueryParams
-	if !h.DisablePushdown {
+	if !h.disablePushdown.Load() {
 		qp.Filter = params.Filter
 	}
 
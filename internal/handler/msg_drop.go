@@ -75,6 +75,12 @@ func (h *Handler) MsgDrop(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 
 	switch {
 	case err == nil, backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		if err = h.dropArchive(ctx, db, dbName, collectionName); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		h.top.RemoveCollection(dbName, collectionName)
+
 		var reply wire.OpMsg
 		must.NoError(reply.SetSections(wire.MakeOpMsgSection(
 			must.NotFail(types.NewDocument(
@@ -58,6 +58,7 @@ func (h *Handler) MsgDropDatabase(ctx context.Context, msg *wire.OpMsg) (*wire.O
 
 	switch {
 	case err == nil:
+		h.top.RemoveDatabase(dbName)
 		res.Set("dropped", dbName)
 	case backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid):
 		// nothing?
@@ -67,6 +68,13 @@ func (h *Handler) MsgDropDatabase(ctx context.Context, msg *wire.OpMsg) (*wire.O
 		return nil, lazyerrors.Error(err)
 	}
 
+	// policies could be set for databases that do not exist yet
+	if err == nil || backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+		if err = h.removeDatabaseArchivePolicies(ctx, dbName); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
 	res.Set("ok", float64(1))
 
 	var reply wire.OpMsg
@@ -0,0 +1,132 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// MsgDropSearchIndex implements `dropSearchIndex` command.
+func (h *Handler) MsgDropSearchIndex(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := document.Command()
+
+	c, err := h.searchIndexesCollection(document)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := searchIndexNameParam(document)
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.ListSearchIndexes(ctx, new(backends.ListSearchIndexesParams))
+
+	switch {
+	case err == nil:
+		// nothing
+	case backends.ErrorCodeIs(err, backends.ErrorCodeNotSupported):
+		return nil, searchNotSupportedError(command)
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNamespaceNotFound,
+			"Collection does not exist",
+			command,
+		)
+	default:
+		return nil, lazyerrors.Error(err)
+	}
+
+	if !slices.ContainsFunc(list.Indexes, func(i backends.SearchIndexInfo) bool { return i.Name == name }) {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexNotFound,
+			fmt.Sprintf("Search index %q not found", name),
+			command,
+		)
+	}
+
+	if _, err = c.DropSearchIndexes(ctx, &backends.DropSearchIndexesParams{Indexes: []string{name}}); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
+		must.NotFail(types.NewDocument(
+			"ok", float64(1),
+		)),
+	)))
+
+	return &reply, nil
+}
+
+// searchIndexNameParam returns search index name given by `name` or `id` field
+// of `dropSearchIndex` or `updateSearchIndex` command.
+//
+// Index names are used as identifiers, so both fields are equivalent.
+func searchIndexNameParam(document *types.Document) (string, error) {
+	command := document.Command()
+
+	var name string
+
+	for _, k := range []string{"name", "id"} {
+		v, _ := document.Get(k)
+		if v == nil {
+			continue
+		}
+
+		s, ok := v.(string)
+		if !ok {
+			return "", handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf("BSON field '%s.%s' must be a string", command, k),
+				command,
+			)
+		}
+
+		if name != "" && name != s {
+			return "", handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				"Cannot specify both 'name' and 'id' with different values",
+				command,
+			)
+		}
+
+		name = s
+	}
+
+	if name == "" {
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"Either 'name' or 'id' must be specified",
+			command,
+		)
+	}
+
+	return name, nil
+}
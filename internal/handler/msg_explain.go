@@ -87,11 +87,11 @@ func (h *Handler) MsgExplain(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		params.Filter, params.Sort = aggregations.GetPushdownQuery(params.StagesDocs)
 	}
 
-	if !h.DisablePushdown {
+	if !h.disablePushdown.Load() {
 		qp.Filter = params.Filter
 	}
 
-	if !h.EnableNestedPushdown && params.Filter != nil {
+	if !h.nestedPushdown() && params.Filter != nil {
 		qp.Filter = params.Filter.DeepCopy()
 
 		for _, k := range qp.Filter.Keys() {
@@ -130,20 +130,20 @@ func (h *Handler) MsgExplain(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 	}
 
 	switch {
-	case h.DisablePushdown:
+	case h.disablePushdown.Load():
 		// Pushdown disabled
-	case params.Sort.Len() == 0 && cInfo.Capped():
-		// Pushdown default recordID sorting for capped collections
+	case params.Sort.Len() == 0 && (cInfo.Capped() || cInfo.Clustered):
+		// Pushdown default recordID sorting for capped collections and _id sorting for clustered collections
 		qp.Sort = must.NotFail(types.NewDocument("$natural", int64(1)))
 	case params.Sort.Len() == 1:
 		if params.Sort.Keys()[0] != "$natural" {
 			break
 		}
 
-		if !cInfo.Capped() {
+		if !cInfo.Capped() && !cInfo.Clustered {
 			return nil, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrNotImplemented,
-				"$natural sort for non-capped collection is not supported.",
+				"$natural sort for non-capped and non-clustered collection is not supported.",
 				"explain",
 			)
 		}
@@ -156,7 +156,7 @@ func (h *Handler) MsgExplain(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 	//  - `filter` is set, it must fetch all documents to filter them in memory;
 	//  - `sort` is set, it must fetch all documents and sort them in memory;
 	//  - `skip` is non-zero value, skip pushdown is not supported yet.
-	if !h.DisablePushdown && params.Filter.Len() == 0 && params.Sort.Len() == 0 && params.Skip == 0 {
+	if !h.disablePushdown.Load() && params.Filter.Len() == 0 && params.Sort.Len() == 0 && params.Skip == 0 {
 		qp.Limit = params.Limit
 	}
 
@@ -165,23 +165,27 @@ func (h *Handler) MsgExplain(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	resDoc := must.NotFail(types.NewDocument(
+		"queryPlanner", res.QueryPlanner,
+		"explainVersion", "1",
+		"command", cmd,
+		"serverInfo", serverInfo,
+
+		// our extensions
+		// TODO https://github.com/FerretDB/FerretDB/issues/3235
+		"filterPushdown", res.FilterPushdown,
+		"sortPushdown", res.SortPushdown,
+		"limitPushdown", res.LimitPushdown,
+	))
+
+	if res.FilterPushdownPaths != nil {
+		resDoc.Set("filterPushdownPaths", res.FilterPushdownPaths)
+	}
+
+	resDoc.Set("ok", float64(1))
+
 	var reply wire.OpMsg
-	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
-		must.NotFail(types.NewDocument(
-			"queryPlanner", res.QueryPlanner,
-			"explainVersion", "1",
-			"command", cmd,
-			"serverInfo", serverInfo,
-
-			// our extensions
-			// TODO https://github.com/FerretDB/FerretDB/issues/3235
-			"filterPushdown", res.FilterPushdown,
-			"sortPushdown", res.SortPushdown,
-			"limitPushdown", res.LimitPushdown,
-
-			"ok", float64(1),
-		)),
-	)))
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(resDoc)))
 
 	return &reply, nil
 }
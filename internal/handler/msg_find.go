@@ -24,11 +24,16 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson2"
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/clientconn/cursor"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/querycache"
+	"github.com/FerretDB/FerretDB/internal/handler/querystats"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/types/fjson"
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -69,6 +74,28 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		return nil, lazyerrors.Error(err)
 	}
 
+	var cacheKey querycache.Key
+	var cacheVersion querycache.Version
+
+	cacheable := h.cacheableFind(conninfo.Get(ctx), params)
+	if cacheable {
+		if cacheKey, err = findCacheKey(params, username); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		if docs, ok := h.queryCache.Get(cacheKey); ok {
+			h.L.Debug("Got cached result", zap.Int("count", docs.Len()))
+			return findReply(params, docs, 0), nil
+		}
+
+		// get version before the query to avoid caching results that could miss concurrent writes
+		cacheVersion = h.queryCache.Version(params.DB, params.Collection)
+	}
+
+	if coll, err = h.withArchive(ctx, db, params.DB, params.Collection, coll); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	var cList *backends.ListCollectionsResult
 	collectionParam := backends.ListCollectionsParams{Name: params.Collection}
 
@@ -100,37 +127,37 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 
 	cancel := func() {}
 
-	if params.MaxTimeMS != 0 {
-		findDone := make(chan struct{})
-		defer close(findDone)
-
-		ctx, cancel = context.WithCancel(ctx)
+	maxTimeMS := h.effectiveMaxTimeMS(params.MaxTimeMS)
+	if maxTimeMS != 0 {
+		// the budget covers the whole request, but not the cursor's lifetime after that
+		var stop func()
+		ctx, stop, cancel = ctxutil.WithBudget(ctx, time.Duration(maxTimeMS)*time.Millisecond)
 
-		go func() {
-			t := time.NewTimer(time.Duration(params.MaxTimeMS) * time.Millisecond)
-			defer t.Stop()
-
-			select {
-			case <-t.C:
-				cancel()
-			case <-findDone:
-			}
-		}()
+		defer stop()
 	}
 
+	tracker := h.newQueryTracker(&querystats.Key{
+		DB:         params.DB,
+		Collection: params.Collection,
+		Command:    "find",
+		Shape:      findQueryShape(params),
+	}, qp)
+
 	queryRes, err := coll.Query(ctx, qp)
 	if err != nil {
-		return nil, handleMaxTimeMSError(err, params.MaxTimeMS, "find")
+		return nil, handleMaxTimeMSError(err, maxTimeMS, "find")
 	}
 
 	// closer accumulates all things that should be closed / canceled.
 	closer := iterator.NewMultiCloser(iterator.CloserFunc(cancel))
 
-	iter, err := h.makeFindIter(queryRes.Iter, closer, params)
+	iter, err := h.makeFindIter(tracker.Examined(queryRes.Iter), closer, params)
 	if err != nil {
-		return nil, handleMaxTimeMSError(err, params.MaxTimeMS, "find")
+		return nil, handleMaxTimeMSError(err, maxTimeMS, "find")
 	}
 
+	iter = tracker.Returned(iter)
+
 	t := cursor.Normal
 
 	if params.Tailable {
@@ -156,9 +183,9 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 
 	cursorID := c.ID
 
-	docs, err := iterator.ConsumeValuesN(c, int(params.BatchSize))
+	docs, done, err := h.consumeBatch(c, params.BatchSize)
 	if err != nil {
-		return nil, handleMaxTimeMSError(err, params.MaxTimeMS, "find")
+		return nil, handleMaxTimeMSError(err, maxTimeMS, "find")
 	}
 
 	h.L.Debug(
@@ -167,7 +194,7 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		zap.Bool("single_batch", params.SingleBatch),
 	)
 
-	if params.SingleBatch || len(docs) < int(params.BatchSize) {
+	if params.SingleBatch || done {
 		c.Close()
 
 		// It is not entirely clear if we should do that; more tests are needed.
@@ -177,6 +204,8 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 
 		// let the client know that there are no more results
 		cursorID = 0
+	} else {
+		h.prefetchBatch(c, params.BatchSize)
 	}
 
 	firstBatch := types.MakeArray(len(docs))
@@ -184,6 +213,27 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		firstBatch.Append(doc)
 	}
 
+	// only complete results are cached
+	if cacheable && cursorID == 0 {
+		var size int64
+
+		for _, doc := range docs {
+			var d *bson2.Document
+			if d, err = bson2.ConvertDocument(doc); err != nil {
+				return nil, lazyerrors.Error(err)
+			}
+
+			size += int64(d.Size())
+		}
+
+		h.queryCache.Set(cacheKey, cacheVersion, firstBatch, size)
+	}
+
+	return findReply(params, firstBatch, cursorID), nil
+}
+
+// findReply returns the reply to the find command with the given first batch.
+func findReply(params *common.FindParams, firstBatch *types.Array, cursorID int64) *wire.OpMsg {
 	var reply wire.OpMsg
 	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
 		must.NotFail(types.NewDocument(
@@ -196,7 +246,61 @@ func (h *Handler) MsgFind(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 		)),
 	)))
 
-	return &reply, nil
+	return &reply
+}
+
+// cacheableFind returns true if the result of the find command could be cached.
+//
+// Tailable cursors are never cached.
+// The local database is not cached because the oplog is written bypassing the cache invalidation.
+// Results are not cached or served for connections with credentials that were not verified yet,
+// because cached results would be returned without the backend checking them.
+func (h *Handler) cacheableFind(connInfo *conninfo.ConnInfo, params *common.FindParams) bool {
+	if h.queryCache == nil || params.Tailable || params.DB == "local" {
+		return false
+	}
+
+	return connInfo.Username() == "" || connInfo.Authenticated()
+}
+
+// findCacheKey returns the query cache key for the find command of the given user.
+//
+// The username is a part of the key because the result could depend on it,
+// for example, with PostgreSQL tenant isolation.
+func findCacheKey(params *common.FindParams, username string) (querycache.Key, error) {
+	query := must.NotFail(types.NewDocument(
+		"username", username,
+	))
+
+	for _, f := range []struct {
+		name  string
+		value *types.Document
+	}{
+		{"filter", params.Filter},
+		{"sort", params.Sort},
+		{"projection", params.Projection},
+	} {
+		if f.value != nil {
+			query.Set(f.name, f.value)
+		}
+	}
+
+	query.Set("skip", params.Skip)
+	query.Set("limit", params.Limit)
+	query.Set("batchSize", params.BatchSize)
+	query.Set("singleBatch", params.SingleBatch)
+	query.Set("showRecordId", params.ShowRecordId)
+
+	b, err := fjson.Marshal(query)
+	if err != nil {
+		return querycache.Key{}, lazyerrors.Error(err)
+	}
+
+	return querycache.Key{
+		DB:         params.DB,
+		Collection: params.Collection,
+		Query:      string(b),
+	}, nil
 }
 
 type findCursorData struct {
@@ -205,6 +309,33 @@ type findCursorData struct {
 	findParams *common.FindParams
 }
 
+// findQueryShape returns the query shape of the find command for query statistics.
+func findQueryShape(params *common.FindParams) *types.Document {
+	shape := must.NotFail(types.NewDocument())
+
+	if params.Filter != nil {
+		shape.Set("filter", querystats.Shape(params.Filter))
+	}
+
+	if params.Sort.Len() > 0 {
+		shape.Set("sort", params.Sort.DeepCopy())
+	}
+
+	if params.Projection.Len() > 0 {
+		shape.Set("projection", params.Projection.DeepCopy())
+	}
+
+	if params.Skip != 0 {
+		shape.Set("skip", "?number")
+	}
+
+	if params.Limit != 0 {
+		shape.Set("limit", "?number")
+	}
+
+	return shape
+}
+
 // makeFindQueryParams creates the backend's query parameters for the find command.
 func (h *Handler) makeFindQueryParams(params *common.FindParams, cInfo *backends.CollectionInfo) (*backends.QueryParams, error) {
 	qp := &backends.QueryParams{
@@ -218,11 +349,11 @@ func (h *Handler) makeFindQueryParams(params *common.FindParams, cInfo *backends
 		}
 	}
 
-	if !h.DisablePushdown {
+	if !h.disablePushdown.Load() {
 		qp.Filter = params.Filter
 	}
 
-	if !h.EnableNestedPushdown && params.Filter != nil {
+	if !h.nestedPushdown() && params.Filter != nil {
 		qp.Filter = params.Filter.DeepCopy()
 
 		for _, k := range qp.Filter.Keys() {
@@ -248,20 +379,20 @@ func (h *Handler) makeFindQueryParams(params *common.FindParams, cInfo *backends
 	}
 
 	switch {
-	case h.DisablePushdown:
+	case h.disablePushdown.Load():
 		// Pushdown disabled
-	case params.Sort.Len() == 0 && cInfo.Capped():
-		// Pushdown default recordID sorting for capped collections
+	case params.Sort.Len() == 0 && (cInfo.Capped() || cInfo.Clustered):
+		// Pushdown default recordID sorting for capped collections and _id sorting for clustered collections
 		qp.Sort = must.NotFail(types.NewDocument("$natural", int64(1)))
 	case params.Sort.Len() == 1:
 		if params.Sort.Keys()[0] != "$natural" {
 			break
 		}
 
-		if !cInfo.Capped() {
+		if !cInfo.Capped() && !cInfo.Clustered {
 			return nil, handlererrors.NewCommandErrorMsgWithArgument(
 				handlererrors.ErrNotImplemented,
-				"$natural sort for non-capped collection is not supported.",
+				"$natural sort for non-capped and non-clustered collection is not supported.",
 				"find",
 			)
 		}
@@ -274,7 +405,7 @@ func (h *Handler) makeFindQueryParams(params *common.FindParams, cInfo *backends
 	//  - `filter` is set, it must fetch all documents to filter them in memory;
 	//  - `sort` is set, it must fetch all documents and sort them in memory;
 	//  - `skip` is non-zero value, skip pushdown is not supported yet.
-	if !h.DisablePushdown && params.Filter.Len() == 0 && params.Sort.Len() == 0 && params.Skip == 0 {
+	if !h.disablePushdown.Load() && params.Filter.Len() == 0 && params.Sort.Len() == 0 && params.Skip == 0 {
 		qp.Limit = params.Limit
 	}
 
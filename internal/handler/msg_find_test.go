@@ -0,0 +1,49 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/querycache"
+)
+
+func TestCacheableFind(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{queryCache: querycache.New(1024, time.Minute)}
+	params := &common.FindParams{DB: "test", Collection: "test"}
+
+	anonymous := conninfo.New()
+	assert.True(t, h.cacheableFind(anonymous, params))
+
+	unverified := conninfo.New()
+	unverified.SetAuth("user", "wrong")
+	assert.False(t, h.cacheableFind(unverified, params))
+
+	verified := conninfo.New()
+	verified.SetAuth("user", "pass")
+	verified.SetAuthVerified()
+	assert.True(t, h.cacheableFind(verified, params))
+
+	assert.False(t, h.cacheableFind(anonymous, &common.FindParams{DB: "local", Collection: "oplog.rs"}))
+	assert.False(t, h.cacheableFind(anonymous, &common.FindParams{DB: "test", Collection: "test", Tailable: true}))
+	assert.False(t, new(Handler).cacheableFind(anonymous, params))
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/ctxutil"
 	"github.com/FerretDB/FerretDB/internal/util/iterator"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 	"github.com/FerretDB/FerretDB/internal/util/must"
@@ -56,6 +57,12 @@ func (h *Handler) MsgFindAndModify(ctx context.Context, msg *wire.OpMsg) (*wire.
 		}
 	}
 
+	if params.Aggregation != nil {
+		if params.Stages, err = newUpdatePipeline(document.Command(), params.Aggregation); err != nil {
+			return nil, err
+		}
+	}
+
 	var resDoc *types.Document
 
 	res, err := h.findAndModifyDocument(ctx, params)
@@ -117,10 +124,16 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 		return nil, lazyerrors.Error(err)
 	}
 
+	if params.Upsert {
+		if err = h.checkImplicitCollection(ctx, db, params.DB, params.Collection, "findAndModify"); err != nil {
+			return nil, err
+		}
+	}
+
 	cancel := func() {}
 	if params.MaxTimeMS != 0 {
 		// TODO https://github.com/FerretDB/FerretDB/issues/2168
-		ctx, cancel = context.WithTimeout(ctx, time.Duration(params.MaxTimeMS)*time.Millisecond)
+		ctx, _, cancel = ctxutil.WithBudget(ctx, time.Duration(params.MaxTimeMS)*time.Millisecond)
 	}
 
 	// closer accumulates all things that should be closed / canceled.
@@ -128,7 +141,7 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 	defer closer.Close()
 
 	var qp backends.QueryParams
-	if !h.DisablePushdown {
+	if !h.disablePushdown.Load() {
 		qp.Filter = params.Query
 	}
 
@@ -186,12 +199,24 @@ func (h *Handler) findAndModifyDocument(ctx context.Context, params *common.Find
 	update := &common.Update{
 		Filter:             params.Query,
 		Update:             params.Update,
+		Pipeline:           params.Aggregation,
+		Stages:             params.Stages,
 		Upsert:             params.Upsert,
 		HasUpdateOperators: params.HasUpdateOperators,
 	}
 
+	v, err := h.documentValidator(ctx, db, params.Collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	// inserts and updates that grow documents are checked against quotas
+	if c, err = h.withQuota(ctx, db, params.DB, params.Collection, c, "findAndModify"); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	// TODO https://github.com/FerretDB/FerretDB/issues/2168
-	updateRes, err := common.UpdateDocument(ctx, c, "findAndModify", iter, update)
+	updateRes, err := common.UpdateDocument(ctx, c, "findAndModify", iter, update, v)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
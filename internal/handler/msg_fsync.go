@@ -0,0 +1,134 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// defaultFsyncLockTimeout is the default time to wait for in-progress writes when the fsync lock is acquired.
+const defaultFsyncLockTimeout = 90 * time.Second
+
+// MsgFsync implements `fsync` command.
+//
+// It checkpoints the backend, so all committed writes are in the data files.
+// With `lock: true`, it also blocks all writes until `fsyncUnlock` is called as many times as `fsync` was.
+func (h *Handler) MsgFsync(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName != "admin" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrUnauthorized,
+			"fsync may only be run against the admin database.",
+			command,
+		)
+	}
+
+	common.Ignored(document, h.L, "async", "comment")
+
+	var lock bool
+
+	if v, _ := document.Get("lock"); v != nil {
+		if lock, err = handlerparams.GetBoolOptionalParam("lock", v); err != nil {
+			return nil, err
+		}
+	}
+
+	timeout := defaultFsyncLockTimeout
+
+	if v, _ := document.Get("fsyncLockAcquisitionTimeoutMillis"); v != nil {
+		var ms int64
+		ms, err = handlerparams.GetValidatedNumberParamWithMinValue(command, "fsyncLockAcquisitionTimeoutMillis", v, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		timeout = time.Duration(ms) * time.Millisecond
+	}
+
+	if !lock {
+		if err = h.b.Checkpoint(ctx, new(backends.CheckpointParams)); err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		var reply wire.OpMsg
+		must.NoError(reply.SetSections(wire.MakeOpMsgSection(
+			must.NotFail(types.NewDocument(
+				"numFiles", int32(1),
+				"ok", float64(1),
+			)),
+		)))
+
+		return &reply, nil
+	}
+
+	lockCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	count, err := h.fsyncLock.Lock(lockCtx)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrMaxTimeMSExpired,
+				"Fsync lock timed out waiting for in-progress writes",
+				command,
+			)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	// checkpoint after in-progress writes are finished
+	if err = h.b.Checkpoint(ctx, new(backends.CheckpointParams)); err != nil {
+		h.fsyncLock.Unlock()
+		return nil, lazyerrors.Error(err)
+	}
+
+	h.L.Warn("Writes are locked by fsync", zap.Int("lockCount", count))
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
+		must.NotFail(types.NewDocument(
+			"info", "now locked against writes, use db.fsyncUnlock() to unlock",
+			"lockCount", int64(count),
+			"ok", float64(1),
+		)),
+	)))
+
+	return &reply, nil
+}
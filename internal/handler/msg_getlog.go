@@ -81,7 +81,7 @@ func (h *Handler) MsgGetLog(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		}
 		resDoc = must.NotFail(types.NewDocument(
 			"log", log,
-			"totalLinesWritten", int64(log.Len()),
+			"totalLinesWritten", logging.RecentEntries.Total(),
 			"ok", float64(1),
 		))
 
@@ -143,6 +143,17 @@ func (h *Handler) MsgGetLog(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 
 			log.Append(string(b))
 		}
+
+		// add real warnings logged during startup
+		warnings, err := logging.StartupWarnings.GetArray(zap.WarnLevel)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		for i := 0; i < warnings.Len(); i++ {
+			log.Append(must.NotFail(warnings.Get(i)))
+		}
+
 		resDoc = must.NotFail(types.NewDocument(
 			"log", &log,
 			"totalLinesWritten", int64(log.Len()),
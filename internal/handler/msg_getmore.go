@@ -171,11 +171,9 @@ func (h *Handler) MsgGetMore(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 
 	v, _ = document.Get("batchSize")
 	if v == nil || types.Compare(v, int32(0)) == types.Equal {
-		// Use 16MB batchSize limit.
-		// Unlimited default batchSize is used for missing batchSize and zero values,
-		// set 250 assuming it is small enough not to crash FerretDB.
-		// TODO https://github.com/FerretDB/FerretDB/issues/2824
-		v = int32(250)
+		// Unlimited default batchSize is used for missing batchSize and zero values;
+		// batches are limited by size anyway, see consumeBatch.
+		v = int32(math.MaxInt32)
 	}
 
 	batchSize, err := handlerparams.GetValidatedNumberParamWithMinValue(document.Command(), "batchSize", v, 0)
@@ -197,17 +195,19 @@ func (h *Handler) MsgGetMore(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		)
 	}
 
-	nextBatch, err := h.makeNextBatch(c, batchSize)
+	nextBatch, done, err := h.makeNextBatch(c, batchSize)
 	if err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
 	switch c.Type {
 	case cursor.Normal:
-		if nextBatch.Len() < int(batchSize) {
+		if done {
 			// The cursor is already closed and removed;
 			// let the client know that there are no more results.
 			cursorID = 0
+		} else {
+			h.prefetchBatch(c, batchSize)
 		}
 
 	case cursor.Tailable:
@@ -234,7 +234,7 @@ func (h *Handler) MsgGetMore(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 			}
 
 			if nextBatch.Len() == 0 {
-				nextBatch, err = h.makeNextBatch(c, batchSize)
+				nextBatch, _, err = h.makeNextBatch(c, batchSize)
 				if err != nil {
 					return nil, lazyerrors.Error(err)
 				}
@@ -273,10 +273,11 @@ func (h *Handler) MsgGetMore(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 }
 
 // makeNextBatch returns the next batch of documents from the cursor.
-func (h *Handler) makeNextBatch(c *cursor.Cursor, batchSize int64) (*types.Array, error) {
-	docs, err := iterator.ConsumeValuesN(c, int(batchSize))
+// The returned done is true if the cursor is exhausted.
+func (h *Handler) makeNextBatch(c *cursor.Cursor, batchSize int64) (*types.Array, bool, error) {
+	docs, done, err := h.consumeBatch(c, batchSize)
 	if err != nil {
-		return nil, lazyerrors.Error(err)
+		return nil, false, lazyerrors.Error(err)
 	}
 
 	h.L.Debug(
@@ -289,7 +290,7 @@ func (h *Handler) makeNextBatch(c *cursor.Cursor, batchSize int64) (*types.Array
 		nextBatch.Append(doc)
 	}
 
-	return nextBatch, nil
+	return nextBatch, done, nil
 }
 
 // awaitDataParams contains parameters that can be passed to awaitData function.
@@ -310,7 +311,8 @@ func (h *Handler) awaitData(ctx context.Context, params *awaitDataParams) (resBa
 	c := params.cursor
 	data := c.Data.(*findCursorData)
 
-	sleepDur := time.Duration(params.maxTimeMS) * time.Millisecond
+	// time spent before awaiting (decoding, getting the first batch) is subtracted
+	sleepDur := ctxutil.Remaining(ctx, time.Duration(params.maxTimeMS)*time.Millisecond)
 	ctx, cancel := context.WithTimeout(ctx, sleepDur)
 
 	defer func() {
@@ -354,7 +356,7 @@ func (h *Handler) awaitData(ctx context.Context, params *awaitDataParams) (resBa
 			return
 		}
 
-		resBatch, err = h.makeNextBatch(c, params.batchSize)
+		resBatch, _, err = h.makeNextBatch(c, params.batchSize)
 		if err != nil {
 			return
 		}
@@ -40,8 +40,8 @@ func (h *Handler) MsgHello(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, e
 	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
 		must.NotFail(types.NewDocument(
 			"isWritablePrimary", true,
-			"maxBsonObjectSize", int32(types.MaxDocumentLen),
-			"maxMessageSizeBytes", int32(wire.MaxMsgLen),
+			"maxBsonObjectSize", h.MaxBSONObjectSize,
+			"maxMessageSizeBytes", h.MaxMessageSize,
 			"maxWriteBatchSize", int32(100000),
 			"localTime", time.Now(),
 			"connectionId", int32(42),
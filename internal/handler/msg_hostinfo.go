@@ -54,6 +54,41 @@ func (h *Handler) MsgHostInfo(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 		}
 	}
 
+	// memory and CPU details are only available on Linux; do not fail if they are not present
+	var memSizeMB int64
+	extra := must.NotFail(types.NewDocument())
+
+	if runtime.GOOS == "linux" {
+		if file, err := os.Open("/proc/meminfo"); err == nil {
+			defer file.Close() //nolint:errcheck // we are only reading it
+			memSizeMB, _ = parseMemInfo(file)
+		}
+
+		if b, err := os.ReadFile("/proc/version"); err == nil {
+			extra.Set("versionString", strings.TrimSpace(string(b)))
+		}
+
+		if b, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+			extra.Set("kernelVersion", strings.TrimSpace(string(b)))
+		}
+
+		if file, err := os.Open("/proc/cpuinfo"); err == nil {
+			defer file.Close() //nolint:errcheck // we are only reading it
+
+			if cpuString, cpuFrequencyMHz, err := parseCPUInfo(file); err == nil {
+				extra.Set("cpuFrequencyMHz", cpuFrequencyMHz)
+				extra.Set("cpuString", cpuString)
+			}
+		}
+
+		pageSize := int64(os.Getpagesize())
+		extra.Set("pageSize", pageSize)
+
+		if memSizeMB > 0 {
+			extra.Set("numPages", memSizeMB*1024*1024/pageSize)
+		}
+	}
+
 	os := "unknown"
 
 	switch runtime.GOOS {
@@ -73,6 +108,9 @@ func (h *Handler) MsgHostInfo(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 				"hostname", hostname,
 				"cpuAddrSize", int32(strconv.IntSize),
 				"numCores", int32(runtime.GOMAXPROCS(-1)),
+				"memSizeMB", memSizeMB,
+				"memLimitMB", memSizeMB,
+				"numaEnabled", false,
 				"cpuArch", runtime.GOARCH,
 			)),
 			"os", must.NotFail(types.NewDocument(
@@ -80,7 +118,7 @@ func (h *Handler) MsgHostInfo(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg
 				"name", osName,
 				"version", osVersion,
 			)),
-			"extra", must.NotFail(types.NewDocument()),
+			"extra", extra,
 			"ok", float64(1),
 		)),
 	)))
@@ -114,3 +152,69 @@ func parseOSRelease(r io.Reader) (string, string, error) {
 
 	return configParams["NAME"], configParams["VERSION"], nil
 }
+
+// parseMemInfo parses the /proc/meminfo file content, returning the total memory size in megabytes.
+func parseMemInfo(r io.Reader) (int64, error) {
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || key != "MemTotal" {
+			continue
+		}
+
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "kB"))
+
+		kb, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+
+		return kb / 1024, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	return 0, lazyerrors.New("MemTotal not found")
+}
+
+// parseCPUInfo parses the /proc/cpuinfo file content,
+// returning the first CPU model name and frequency in megahertz.
+// Both values may be empty if they are not reported (for example, on some ARM systems).
+func parseCPUInfo(r io.Reader) (string, string, error) {
+	scanner := bufio.NewScanner(r)
+
+	var model, mhz string
+
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch key {
+		case "model name":
+			if model == "" {
+				model = value
+			}
+		case "cpu MHz":
+			if mhz == "" {
+				mhz = value
+			}
+		}
+
+		if model != "" && mhz != "" {
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", "", lazyerrors.Error(err)
+	}
+
+	return model, mhz, nil
+}
@@ -112,3 +112,63 @@ LOGO=archlinux-logo`,
 		assert.Equal(t, testCase["VERSION"], osVersion)
 	}
 }
+
+func TestParseMemInfo(t *testing.T) {
+	t.Parallel()
+
+	memInfo := `MemTotal:       16318164 kB
+MemFree:         8934388 kB
+MemAvailable:   12601628 kB
+Buffers:          258172 kB
+`
+
+	memSizeMB, err := parseMemInfo(bytes.NewReader([]byte(memInfo)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(15935), memSizeMB)
+
+	_, err = parseMemInfo(bytes.NewReader([]byte("MemFree:         8934388 kB\n")))
+	require.Error(t, err)
+}
+
+func TestParseCPUInfo(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		cpuInfo string
+		model   string
+		mhz     string
+	}{
+		"x86": {
+			cpuInfo: `processor	: 0
+vendor_id	: GenuineIntel
+cpu family	: 6
+model		: 142
+model name	: Intel(R) Core(TM) i7-8550U CPU @ 1.80GHz
+cpu MHz		: 2000.000
+
+processor	: 1
+vendor_id	: GenuineIntel
+model name	: Intel(R) Core(TM) i7-8550U CPU @ 1.80GHz
+cpu MHz		: 1800.000
+`,
+			model: "Intel(R) Core(TM) i7-8550U CPU @ 1.80GHz",
+			mhz:   "2000.000",
+		},
+		"ARM": {
+			cpuInfo: `processor	: 0
+BogoMIPS	: 48.00
+Features	: fp asimd evtstrm aes pmull sha1 sha2 crc32
+CPU implementer	: 0x41
+`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			model, mhz, err := parseCPUInfo(bytes.NewReader([]byte(tc.cpuInfo)))
+			require.NoError(t, err)
+			assert.Equal(t, tc.model, model)
+			assert.Equal(t, tc.mhz, mhz)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"sync"
 
 	"go.mongodb.org/mongo-driver/mongo"
 
@@ -77,19 +78,43 @@ func (h *Handler) MsgInsert(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
+	if err = h.checkImplicitCollection(ctx, db, params.DB, params.Collection, "insert"); err != nil {
+		return nil, err
+	}
+
+	if c, err = h.withQuota(ctx, db, params.DB, params.Collection, c, "insert"); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	v, err := h.documentValidator(ctx, db, params.Collection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
 	docsIter := params.Docs.Iterator()
 	defer docsIter.Close()
 
+	var mu sync.Mutex
 	var inserted int32
 	var writeErrors []*mongo.WriteError
 
+	// TODO https://github.com/FerretDB/FerretDB/issues/3708
+	batchSize := 1000
+
+	// unordered sub-batches are inserted concurrently, so make enough of them
+	concurrent := !params.Ordered && h.BulkWriteConcurrency > 1
+	if concurrent {
+		batchSize = min(batchSize, max(100, (params.Docs.Len()+h.BulkWriteConcurrency-1)/h.BulkWriteConcurrency))
+	}
+
+	var batches []*insertBatch
+
 	var done bool
 	for !done {
-		// TODO https://github.com/FerretDB/FerretDB/issues/3708
-		const batchSize = 1000
-
-		docs := make([]*types.Document, 0, batchSize)
-		docsIndexes := make([]int, 0, batchSize)
+		b := &insertBatch{
+			docs:    make([]*types.Document, 0, batchSize),
+			indexes: make([]int, 0, batchSize),
+		}
 
 		for j := 0; j < batchSize; j++ {
 			var i int
@@ -113,8 +138,41 @@ func (h *Handler) MsgInsert(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 
 			// TODO https://github.com/FerretDB/FerretDB/issues/3454
 			if err = doc.ValidateData(); err == nil {
-				docs = append(docs, doc)
-				docsIndexes = append(docsIndexes, i)
+				if msg := v.ValidateTimeField(doc); msg != "" {
+					writeErrors = append(writeErrors, &mongo.WriteError{
+						Index:   i,
+						Code:    int(handlererrors.ErrBadValue),
+						Message: msg,
+					})
+
+					if params.Ordered {
+						break
+					}
+
+					continue
+				}
+
+				var valid bool
+				if valid, err = v.Validate(nil, doc); err != nil {
+					return nil, lazyerrors.Error(err)
+				}
+
+				if valid {
+					b.docs = append(b.docs, doc)
+					b.indexes = append(b.indexes, i)
+
+					continue
+				}
+
+				writeErrors = append(writeErrors, &mongo.WriteError{
+					Index:   i,
+					Code:    int(handlererrors.ErrDocumentValidationFailure),
+					Message: "Document failed validation",
+				})
+
+				if params.Ordered {
+					break
+				}
 
 				continue
 			}
@@ -145,40 +203,41 @@ func (h *Handler) MsgInsert(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 			}
 		}
 
-		if _, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: docs}); err == nil {
-			inserted += int32(len(docs))
-
-			if params.Ordered && len(writeErrors) > 0 {
-				break
-			}
-
+		if concurrent {
+			batches = append(batches, b)
 			continue
 		}
 
-		// insert doc one by one upon failing on batch insertion
-		for j, doc := range docs {
-			if _, err = c.InsertAll(ctx, &backends.InsertAllParams{
-				Docs: []*types.Document{doc},
-			}); err == nil {
-				inserted++
+		n, errs, err := insertDocuments(ctx, c, params, b)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
 
-				continue
-			}
+		inserted += n
+		writeErrors = append(writeErrors, errs...)
 
-			if !backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID) {
-				return nil, lazyerrors.Error(err)
-			}
+		if params.Ordered && len(writeErrors) > 0 {
+			break
+		}
+	}
 
-			writeErrors = append(writeErrors, &mongo.WriteError{
-				Index:   docsIndexes[j],
-				Code:    int(handlererrors.ErrDuplicateKeyInsert),
-				Message: fmt.Sprintf(`E11000 duplicate key error collection: %s.%s`, params.DB, params.Collection),
-			})
+	err = h.bulkWrite(ctx, len(batches), func(ctx context.Context, i int) error {
+		n, errs, err := insertDocuments(ctx, c, params, batches[i])
 
-			if params.Ordered {
-				break
-			}
+		mu.Lock()
+		defer mu.Unlock()
+
+		inserted += n
+		writeErrors = append(writeErrors, errs...)
+
+		if err != nil {
+			return lazyerrors.Error(err)
 		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
 	}
 
 	res := must.NotFail(types.NewDocument(
@@ -207,3 +266,61 @@ func (h *Handler) MsgInsert(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 
 	return &reply, nil
 }
+
+// insertBatch represents documents inserted together and their indexes in the `insert` command.
+type insertBatch struct {
+	docs    []*types.Document
+	indexes []int
+}
+
+// insertDocuments inserts a batch of documents.
+// If that fails, documents are inserted one by one,
+// and duplicate key and command errors (such as quota errors) are returned as write errors.
+//
+// It returns the number of inserted documents, write errors, and something fatal.
+// In the latter case, documents inserted so far are still returned.
+func insertDocuments(ctx context.Context, c backends.Collection, params *common.InsertParams, b *insertBatch) (int32, []*mongo.WriteError, error) { //nolint:lll // for readability
+	if _, err := c.InsertAll(ctx, &backends.InsertAllParams{Docs: b.docs}); err == nil {
+		return int32(len(b.docs)), nil, nil
+	}
+
+	var inserted int32
+	var writeErrors []*mongo.WriteError
+
+	// insert doc one by one upon failing on batch insertion
+	for j, doc := range b.docs {
+		_, err := c.InsertAll(ctx, &backends.InsertAllParams{
+			Docs: []*types.Document{doc},
+		})
+		if err == nil {
+			inserted++
+
+			continue
+		}
+
+		var ce *handlererrors.CommandError
+
+		switch {
+		case backends.ErrorCodeIs(err, backends.ErrorCodeInsertDuplicateID):
+			writeErrors = append(writeErrors, &mongo.WriteError{
+				Index:   b.indexes[j],
+				Code:    int(handlererrors.ErrDuplicateKeyInsert),
+				Message: fmt.Sprintf(`E11000 duplicate key error collection: %s.%s`, params.DB, params.Collection),
+			})
+		case errors.As(err, &ce):
+			writeErrors = append(writeErrors, &mongo.WriteError{
+				Index:   b.indexes[j],
+				Code:    int(ce.Code()),
+				Message: ce.Err().Error(),
+			})
+		default:
+			return inserted, writeErrors, lazyerrors.Error(err)
+		}
+
+		if params.Ordered {
+			break
+		}
+	}
+
+	return inserted, writeErrors, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// errInsertFailed is returned by failingCollection.
+var errInsertFailed = errors.New("connection lost")
+
+// failingBackend wraps backend so that all inserts fail with errInsertFailed.
+type failingBackend struct {
+	backends.Backend
+}
+
+// Database implements backends.Backend interface.
+func (b *failingBackend) Database(name string) (backends.Database, error) {
+	db, err := b.Backend.Database(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &failingDatabase{Database: db}, nil
+}
+
+// failingDatabase wraps database so that all inserts fail with errInsertFailed.
+type failingDatabase struct {
+	backends.Database
+}
+
+// Collection implements backends.Database interface.
+func (db *failingDatabase) Collection(name string) (backends.Collection, error) {
+	c, err := db.Database.Collection(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &failingCollection{Collection: c}, nil
+}
+
+// failingCollection fails all inserts with errInsertFailed.
+type failingCollection struct {
+	backends.Collection
+}
+
+// InsertAll implements backends.Collection interface.
+func (c *failingCollection) InsertAll(context.Context, *backends.InsertAllParams) (*backends.InsertAllResult, error) {
+	return nil, errInsertFailed
+}
+
+func TestInsertBackendError(t *testing.T) {
+	t.Parallel()
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := sqlite.NewBackend(&sqlite.NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	for name, tc := range map[string]struct {
+		ordered     bool
+		concurrency int
+	}{
+		"Ordered": {
+			ordered:     true,
+			concurrency: 1,
+		},
+		"Unordered": {
+			concurrency: 1,
+		},
+		"UnorderedConcurrent": {
+			concurrency: 4,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			h := &Handler{
+				NewOpts: &NewOpts{L: testutil.Logger(t), BulkWriteConcurrency: tc.concurrency},
+				b:       &failingBackend{Backend: b},
+			}
+
+			docs := types.MakeArray(3)
+			for i := range int32(3) {
+				docs.Append(must.NotFail(types.NewDocument("_id", i)))
+			}
+
+			var msg wire.OpMsg
+			require.NoError(t, msg.SetSections(wire.MakeOpMsgSection(must.NotFail(types.NewDocument(
+				"insert", testutil.CollectionName(t),
+				"documents", docs,
+				"ordered", tc.ordered,
+				"$db", testutil.DatabaseName(t),
+			)))))
+
+			// the command fails instead of returning write errors
+			_, err := h.MsgInsert(testutil.Ctx(t), &msg)
+			require.ErrorIs(t, err, errInsertFailed)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// MsgListArchivePolicies implements `listArchivePolicies` command.
+//
+// It returns archive policies of the current database.
+func (h *Handler) MsgListArchivePolicies(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	common.Ignored(document, h.L, "comment")
+
+	all, err := h.loadArchivePolicies(ctx)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var policies []*archivePolicy
+
+	for _, p := range all {
+		if p.db == dbName {
+			policies = append(policies, p)
+		}
+	}
+
+	slices.SortFunc(policies, func(a, b *archivePolicy) int {
+		return strings.Compare(a.collection, b.collection)
+	})
+
+	res := types.MakeArray(len(policies))
+
+	for _, p := range policies {
+		doc := p.document()
+		doc.Remove("_id")
+		doc.Remove("db")
+
+		res.Append(doc)
+	}
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
+		must.NotFail(types.NewDocument(
+			"policies", res,
+			"ok", float64(1),
+		)),
+	)))
+
+	return &reply, nil
+}
@@ -17,10 +17,13 @@ package handler
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/decorators/timeseries"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
 	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
@@ -75,22 +78,52 @@ func (h *Handler) MsgListCollections(ctx context.Context, msg *wire.OpMsg) (*wir
 		return nil, lazyerrors.Error(err)
 	}
 
+	timeSeries := make(map[string]*backends.TimeSeriesOptions)
+
+	for _, collection := range res.Collections {
+		if collection.TimeSeries != nil {
+			timeSeries[collection.Name] = collection.TimeSeries
+		}
+	}
+
 	collections := types.MakeArray(len(res.Collections))
 
 	for _, collection := range res.Collections {
 		d := must.NotFail(types.NewDocument(
 			"name", collection.Name,
 			"type", "collection",
-			"idIndex", must.NotFail(types.NewDocument(
-				"v", int32(2),
-				"key", must.NotFail(types.NewDocument("_id", int32(1))),
-				"name", "_id_",
-			)),
 		))
 
 		options := must.NotFail(types.NewDocument())
 		info := must.NotFail(types.NewDocument("readOnly", false))
 
+		bucketsOf, isBuckets := strings.CutPrefix(collection.Name, timeseries.BucketsPrefix)
+
+		switch {
+		case collection.TimeSeries != nil:
+			d.Set("type", "timeseries")
+			options.Set("timeseries", timeSeriesOptionsDocument(collection.TimeSeries))
+
+		case isBuckets && timeSeries[bucketsOf] != nil:
+			options.Set("clusteredIndex", true)
+			options.Set("timeseries", timeSeriesOptionsDocument(timeSeries[bucketsOf]))
+
+		case collection.Clustered:
+			options.Set("clusteredIndex", must.NotFail(types.NewDocument(
+				"v", int32(2),
+				"key", must.NotFail(types.NewDocument("_id", int32(1))),
+				"name", backends.DefaultIndexName,
+				"unique", true,
+			)))
+
+		default:
+			d.Set("idIndex", must.NotFail(types.NewDocument(
+				"v", int32(2),
+				"key", must.NotFail(types.NewDocument("_id", int32(1))),
+				"name", "_id_",
+			)))
+		}
+
 		if collection.Capped() {
 			options.Set("capped", true)
 		}
@@ -103,6 +136,16 @@ func (h *Handler) MsgListCollections(ctx context.Context, msg *wire.OpMsg) (*wir
 			options.Set("max", collection.CappedDocuments)
 		}
 
+		if collection.Validator != nil {
+			options.Set("validator", collection.Validator)
+			options.Set("validationLevel", collection.ValidationLevel)
+			options.Set("validationAction", collection.ValidationAction)
+		}
+
+		if collection.Codec != "" {
+			options.Set("storageEngine", storageEngineOptionsDocument(h.BackendName, &collection))
+		}
+
 		d.Set("options", options)
 
 		if collection.UUID != "" {
@@ -153,3 +196,36 @@ func (h *Handler) MsgListCollections(ctx context.Context, msg *wire.OpMsg) (*wir
 
 	return &reply, nil
 }
+
+// timeSeriesOptionsDocument returns `timeseries` options document for the given time-series collection.
+func timeSeriesOptionsDocument(ts *backends.TimeSeriesOptions) *types.Document {
+	res := must.NotFail(types.NewDocument("timeField", ts.TimeField))
+
+	if ts.MetaField != "" {
+		res.Set("metaField", ts.MetaField)
+	}
+
+	_, maxSpan := timeseries.BucketSpan(ts.Granularity)
+
+	res.Set("granularity", ts.Granularity)
+	res.Set("bucketMaxSpanSeconds", int32(maxSpan/time.Second))
+
+	return res
+}
+
+// storageEngineOptionsDocument returns `storageEngine` options document for the given collection
+// with non-default storage codec.
+func storageEngineOptionsDocument(backendName string, collection *backends.CollectionInfo) *types.Document {
+	opts := must.NotFail(types.NewDocument("codec", collection.Codec))
+
+	if collection.Codec == storageCodecHybrid {
+		fields := types.MakeArray(len(collection.CodecFields))
+		for _, f := range collection.CodecFields {
+			fields.Append(f)
+		}
+
+		opts.Set("fields", fields)
+	}
+
+	return must.NotFail(types.NewDocument(backendName, opts))
+}
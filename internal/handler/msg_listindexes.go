@@ -76,32 +76,20 @@ func (h *Handler) MsgListIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.Op
 		return nil, lazyerrors.Error(err)
 	}
 
-	firstBatch := types.MakeArray(len(res.Indexes))
-
-	for _, index := range res.Indexes {
-		indexKey := must.NotFail(types.NewDocument())
-
-		for _, key := range index.Key {
-			order := int32(1)
-			if key.Descending {
-				order = -1
-			}
-
-			indexKey.Set(key.Field, order)
-		}
+	cList, err := db.ListCollections(ctx, &backends.ListCollectionsParams{Name: collection})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
 
-		indexDoc := must.NotFail(types.NewDocument(
-			"v", int32(2), // for compatibility, the meaning of this field is not documented
-			"key", indexKey,
-			"name", index.Name,
-		))
+	var clustered bool
+	if len(cList.Collections) > 0 {
+		clustered = cList.Collections[0].Clustered
+	}
 
-		// only non-default unique indexes should have unique field in the response
-		if index.Unique && index.Name != backends.DefaultIndexName {
-			indexDoc.Set("unique", index.Unique)
-		}
+	firstBatch := types.MakeArray(len(res.Indexes))
 
-		firstBatch.Append(indexDoc)
+	for _, index := range res.Indexes {
+		firstBatch.Append(indexDocument(&index, clustered))
 	}
 
 	var reply wire.OpMsg
@@ -118,3 +106,36 @@ func (h *Handler) MsgListIndexes(ctx context.Context, msg *wire.OpMsg) (*wire.Op
 
 	return &reply, nil
 }
+
+// indexDocument returns an index specification document as returned by `listIndexes` command.
+func indexDocument(index *backends.IndexInfo, clustered bool) *types.Document {
+	indexKey := must.NotFail(types.NewDocument())
+
+	for _, key := range index.Key {
+		order := int32(1)
+		if key.Descending {
+			order = -1
+		}
+
+		indexKey.Set(key.Field, order)
+	}
+
+	indexDoc := must.NotFail(types.NewDocument(
+		"v", int32(2), // for compatibility, the meaning of this field is not documented
+		"key", indexKey,
+		"name", index.Name,
+	))
+
+	// only non-default unique indexes should have unique field in the response
+	if index.Unique && index.Name != backends.DefaultIndexName {
+		indexDoc.Set("unique", index.Unique)
+	}
+
+	// the default index of clustered collection is the clustered index
+	if clustered && index.Name == backends.DefaultIndexName {
+		indexDoc.Set("unique", true)
+		indexDoc.Set("clustered", true)
+	}
+
+	return indexDoc
+}
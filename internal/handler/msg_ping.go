@@ -18,6 +18,8 @@ import (
 	"context"
 	"fmt"
 
+	"go.uber.org/zap"
+
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
 	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
@@ -49,7 +51,18 @@ func (h *Handler) MsgPing(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, er
 	}
 
 	if _, err = h.b.Status(ctx, nil); err != nil {
-		return nil, lazyerrors.Error(err)
+		if ctx.Err() != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		// MongoDB drivers treat that code as a transient network error
+		h.L.Warn("Backend is unavailable.", zap.Error(err))
+
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrHostUnreachable,
+			"Backend is unavailable",
+			"ping",
+		)
 	}
 
 	var reply wire.OpMsg
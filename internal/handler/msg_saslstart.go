@@ -20,8 +20,10 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/xdg-go/scram"
+	"go.uber.org/zap"
 
 	"github.com/FerretDB/FerretDB/internal/clientconn/conninfo"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
@@ -65,7 +67,9 @@ func (h *Handler) MsgSASLStart(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 			return nil, err
 		}
 
-		conninfo.Get(ctx).SetAuth(username, password)
+		if err = h.verifyPlainAuth(ctx, username, password); err != nil {
+			return nil, err
+		}
 
 		var emptyPayload types.Binary
 		must.NoError(reply.SetSections(wire.MakeOpMsgSection(
@@ -107,6 +111,38 @@ func (h *Handler) MsgSASLStart(ctx context.Context, msg *wire.OpMsg) (*wire.OpMs
 	return &reply, nil
 }
 
+// plainAuthBackends contains backends that verify PLAIN credentials when they connect to the database.
+var plainAuthBackends = []string{"postgresql", "mysql"}
+
+// verifyPlainAuth stores PLAIN credentials for the connection.
+//
+// For backends that verify credentials, the connection is checked with them,
+// and the connection is marked as authenticated only if that succeeds.
+// For other backends, credentials can't be verified, so the connection is not marked as authenticated.
+func (h *Handler) verifyPlainAuth(ctx context.Context, username, password string) error {
+	connInfo := conninfo.Get(ctx)
+	connInfo.SetAuth(username, password)
+
+	if !slices.Contains(plainAuthBackends, h.BackendName) {
+		return nil
+	}
+
+	if _, err := h.b.Status(ctx, nil); err != nil {
+		h.L.Warn("PLAIN authentication failed.", zap.String("username", username), zap.Error(err))
+		connInfo.SetAuth("", "")
+
+		return handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrAuthenticationFailed,
+			"Authentication failed.",
+			"payload",
+		)
+	}
+
+	connInfo.SetAuthVerified()
+
+	return nil
+}
+
 // saslStartPlain extracts username and password from PLAIN `saslStart` payload.
 func saslStartPlain(doc *types.Document) (string, string, error) {
 	var payload []byte
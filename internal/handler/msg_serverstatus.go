@@ -18,8 +18,14 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"golang.org/x/exp/maps"
+
 	"github.com/FerretDB/FerretDB/build/version"
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -42,22 +48,42 @@ func (h *Handler) MsgServerStatus(ctx context.Context, msg *wire.OpMsg) (*wire.O
 
 	uptime := time.Since(h.StateProvider.Get().Start)
 
-	metricsDoc := types.MakeDocument(0)
+	commands := map[string]*[2]int64{} // total and failed
+	for _, opcodeCommands := range h.ConnMetrics.GetResponses() {
+		for command, arguments := range opcodeCommands {
+			c := commands[command]
+			if c == nil {
+				c = new([2]int64)
+				commands[command] = c
+			}
 
-	metrics := h.ConnMetrics.GetResponses()
-	for _, commands := range metrics {
-		for command, arguments := range commands {
-			var total, failed int
 			for _, m := range arguments {
-				total += m.Total
+				c[0] += int64(m.Total)
 
 				for _, v := range m.Failures {
-					failed += v
+					c[1] += int64(v)
 				}
 			}
+		}
+	}
+
+	metricsDoc := types.MakeDocument(len(commands))
+
+	names := maps.Keys(commands)
+	slices.Sort(names)
+
+	for _, command := range names {
+		c := commands[command]
+		metricsDoc.Set(command, must.NotFail(types.NewDocument("total", c[0], "failed", c[1])))
+	}
 
-			d := must.NotFail(types.NewDocument("total", int64(total), "failed", int64(failed)))
-			metricsDoc.Set(command, d)
+	totals := h.ConnMetrics.GetTotals()
+	clients, _ := clientVersions(h.conns.Conns())
+
+	var numRequests int64
+	for _, opcodeCommands := range h.ConnMetrics.GetRequests() {
+		for _, v := range opcodeCommands {
+			numRequests += int64(v)
 		}
 	}
 
@@ -73,6 +99,23 @@ func (h *Handler) MsgServerStatus(ctx context.Context, msg *wire.OpMsg) (*wire.O
 		"freeMonitoring", must.NotFail(types.NewDocument(
 			"state", h.StateProvider.Get().TelemetryString(),
 		)),
+		"connections", must.NotFail(types.NewDocument(
+			"current", int32(totals.Connections),
+			"available", int32(max(maxConnections-totals.Connections, 0)),
+			"totalCreated", int32(totals.ConnectionsCreated),
+
+			// our extension
+			"clients", clients,
+		)),
+		"network", must.NotFail(types.NewDocument(
+			"bytesIn", totals.ReceivedBytes,
+			"bytesOut", totals.SentBytes,
+			"physicalBytesIn", totals.ReceivedBytes,
+			"physicalBytesOut", totals.SentBytes,
+			"numRequests", numRequests,
+		)),
+		"opcounters", opcounters(h.ConnMetrics.GetRequests()),
+		"mem", memStatus(),
 		"metrics", must.NotFail(types.NewDocument(
 			"commands", metricsDoc,
 		)),
@@ -105,3 +148,76 @@ func (h *Handler) MsgServerStatus(ctx context.Context, msg *wire.OpMsg) (*wire.O
 
 	return &reply, nil
 }
+
+// maxConnections is the number of connections reported as the maximum by serverStatus.
+// FerretDB does not limit it, so MongoDB's default is used.
+const maxConnections = 1_000_000
+
+// opcounters returns serverStatus' opcounters section for given request metrics.
+//
+// Unlike MongoDB, insert, update, and delete operations are counted per command, not per document or statement.
+func opcounters(requests map[string]map[string]int) *types.Document {
+	var insert, query, update, del, getmore, command int64
+
+	for _, commands := range requests {
+		for c, v := range commands {
+			switch c {
+			case "insert":
+				insert += int64(v)
+			case "find":
+				query += int64(v)
+			case "update":
+				update += int64(v)
+			case "delete":
+				del += int64(v)
+			case "getMore":
+				getmore += int64(v)
+			default:
+				command += int64(v)
+			}
+		}
+	}
+
+	return must.NotFail(types.NewDocument(
+		"insert", insert,
+		"query", query,
+		"update", update,
+		"delete", del,
+		"getmore", getmore,
+		"command", command,
+	))
+}
+
+// memStatus returns serverStatus' mem section with resident and virtual memory sizes in megabytes.
+//
+// On Linux, sizes are read from procfs; on other systems, the memory obtained by Go runtime is reported for both.
+func memStatus() *types.Document {
+	var resident, virtual int64
+
+	if b, err := os.ReadFile("/proc/self/statm"); err == nil {
+		// size and resident fields, in pages
+		if f := strings.Fields(string(b)); len(f) >= 2 {
+			size, _ := strconv.ParseInt(f[0], 10, 64)
+			rss, _ := strconv.ParseInt(f[1], 10, 64)
+
+			pageSize := int64(os.Getpagesize())
+			virtual = size * pageSize
+			resident = rss * pageSize
+		}
+	}
+
+	if resident == 0 {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+
+		resident = int64(ms.Sys)
+		virtual = int64(ms.Sys)
+	}
+
+	return must.NotFail(types.NewDocument(
+		"bits", int32(strconv.IntSize),
+		"resident", int32(resident>>20),
+		"virtual", int32(virtual>>20),
+		"supported", true,
+	))
+}
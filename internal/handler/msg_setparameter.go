@@ -0,0 +1,145 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// MsgSetParameter implements `setParameter` command.
+func (h *Handler) MsgSetParameter(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName != "admin" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrUnauthorized,
+			"setParameter may only be run against the admin database.",
+			command,
+		)
+	}
+
+	common.Ignored(document, h.L, "comment")
+
+	// check that all parameters exist and are settable before changing any of them
+	type setParam struct {
+		p *parameter
+		v any
+	}
+
+	var params []setParam
+	var was any
+
+	iter := document.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		switch k {
+		case command, "comment", "$db", "lsid", "$clusterTime", "$readPreference":
+			continue
+		}
+
+		p := h.parameters[k]
+		if p == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidOptions,
+				fmt.Sprintf("attempted to set unrecognized parameter [%s], use help:true to see options ", k),
+				command,
+			)
+		}
+
+		if !p.settableAtRuntime {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidOptions,
+				fmt.Sprintf("not allowed to change [%s] at runtime", k),
+				command,
+			)
+		}
+
+		if p.set == nil {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				fmt.Sprintf("setParameter for [%s] is not implemented yet", k),
+				command,
+			)
+		}
+
+		// MongoDB returns the previous value of the first parameter only
+		if len(params) == 0 {
+			was = p.get()
+		}
+
+		params = append(params, setParam{p: p, v: v})
+	}
+
+	if len(params) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"no option found to set, use help:true to see options ",
+			command,
+		)
+	}
+
+	// validate all values before applying any of them
+	applies := make([]func(), len(params))
+
+	for i, sp := range params {
+		if applies[i], err = sp.p.set(sp.v); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, apply := range applies {
+		apply()
+	}
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
+		must.NotFail(types.NewDocument(
+			"was", was,
+			"ok", float64(1),
+		)),
+	)))
+
+	return &reply, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+func TestSetParameterAtomic(t *testing.T) {
+	t.Parallel()
+
+	h := &Handler{NewOpts: &NewOpts{L: testutil.Logger(t)}}
+	h.initParameters()
+	h.SetSlowQueryThreshold(100 * time.Millisecond)
+
+	for name, pairs := range map[string][]any{
+		"InvalidSecond": {"slowms", int32(200), "featureFlagPushdown", "yes"},
+		"Overflow":      {"slowms", int32(200), "cursorTimeoutMillis", int64(1 << 53)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			pairs = append([]any{"setParameter", int32(1)}, pairs...)
+			pairs = append(pairs, "$db", "admin")
+
+			var msg wire.OpMsg
+			must.NoError(msg.SetSections(wire.MakeOpMsgSection(must.NotFail(types.NewDocument(pairs...)))))
+
+			_, err := h.MsgSetParameter(context.Background(), &msg)
+
+			var cmdErr *handlererrors.CommandError
+			require.ErrorAs(t, err, &cmdErr)
+
+			// the valid first parameter should not be changed
+			assert.Equal(t, 100*time.Millisecond, h.SlowQueryThreshold())
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/top"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// topCommand describes how the command is accounted by the `top` command.
+type topCommand struct {
+	op    top.Op
+	write bool
+}
+
+// topCommands contains commands accounted by the `top` command.
+var topCommands = map[string]topCommand{
+	"aggregate":     {op: top.Commands},
+	"count":         {op: top.Commands},
+	"delete":        {op: top.Remove, write: true},
+	"distinct":      {op: top.Commands},
+	"find":          {op: top.Queries},
+	"findAndModify": {op: top.Commands, write: true},
+	"getMore":       {op: top.GetMore},
+	"insert":        {op: top.Insert, write: true},
+	"update":        {op: top.Update, write: true},
+}
+
+// MsgTop implements `top` command.
+func (h *Handler) MsgTop(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	if dbName != "admin" {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrUnauthorized,
+			"top may only be run against the admin database.",
+			document.Command(),
+		)
+	}
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
+		must.NotFail(types.NewDocument(
+			"totals", h.top.Document(),
+			"ok", float64(1),
+		)),
+	)))
+
+	return &reply, nil
+}
+
+// withTop wraps the command handler to account the time spent on it by the `top` command.
+func (h *Handler) withTop(
+	tc topCommand,
+	handler func(context.Context, *wire.OpMsg) (*wire.OpMsg, error),
+) func(context.Context, *wire.OpMsg) (*wire.OpMsg, error) {
+	return func(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+		start := time.Now()
+		res, err := handler(ctx, msg)
+		d := time.Since(start)
+
+		if dbName, collection := topNamespace(msg); collection != "" {
+			h.top.Record(dbName, collection, tc.op, tc.write, d)
+		}
+
+		return res, err
+	}
+}
+
+// topNamespace returns the database and collection names of the command,
+// or empty strings if they could not be determined.
+func topNamespace(msg *wire.OpMsg) (string, string) {
+	document, err := msg.Document()
+	if err != nil {
+		return "", ""
+	}
+
+	field := document.Command()
+	if field == "getMore" {
+		field = "collection"
+	}
+
+	v, _ := document.Get("$db")
+	dbName, _ := v.(string)
+
+	v, _ = document.Get(field)
+	collection, _ := v.(string)
+
+	if dbName == "" || collection == "" {
+		return "", ""
+	}
+
+	return dbName, collection
+}
@@ -15,8 +15,12 @@
 package handler
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/FerretDB/FerretDB/internal/backends"
 	"github.com/FerretDB/FerretDB/internal/handler/common"
@@ -40,10 +44,18 @@ func (h *Handler) MsgUpdate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 		return nil, lazyerrors.Error(err)
 	}
 
-	// TODO https://github.com/FerretDB/FerretDB/issues/2612
-	_ = params.Ordered
+	for i := range params.Updates {
+		u := &params.Updates[i]
+		if u.Pipeline == nil {
+			continue
+		}
+
+		if u.Stages, err = newUpdatePipeline(document.Command(), u.Pipeline); err != nil {
+			return nil, err
+		}
+	}
 
-	matched, modified, upserted, err := h.updateDocument(ctx, params)
+	matched, modified, upserted, writeErrors, err := h.updateDocument(ctx, params)
 	if err != nil {
 		return nil, handleUpdateError(params.DB, params.Collection, "update", err)
 	}
@@ -57,6 +69,12 @@ func (h *Handler) MsgUpdate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 	}
 
 	res.Set("nModified", modified)
+
+	if writeErrors.Len() > 0 {
+		writeErrors.Sort()
+		res.Set("writeErrors", must.NotFail(writeErrors.Document().Get("writeErrors")))
+	}
+
 	res.Set("ok", float64(1))
 
 	var reply wire.OpMsg
@@ -68,85 +86,256 @@ func (h *Handler) MsgUpdate(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg,
 }
 
 // updateDocument iterate through all documents in collection and update them.
-func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParams) (int32, int32, *types.Array, error) {
-	var matched, modified int32
-	var upserted types.Array
-
+//
+// For unordered updates, errors of individual statements are returned as write errors.
+// For ordered updates, the first error stops execution and is returned.
+func (h *Handler) updateDocument(ctx context.Context, params *common.UpdateParams) (int32, int32, *types.Array, *handlererrors.WriteErrors, error) { //nolint:lll // for readability
 	db, err := h.b.Database(params.DB)
 	if err != nil {
 		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
 			msg := fmt.Sprintf("Invalid namespace specified '%s.%s'", params.DB, params.Collection)
-			return 0, 0, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "update")
+			return 0, 0, nil, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "update")
 		}
 
-		return 0, 0, nil, lazyerrors.Error(err)
+		return 0, 0, nil, nil, lazyerrors.Error(err)
 	}
 
-	err = db.CreateCollection(ctx, &backends.CreateCollectionParams{Name: params.Collection})
-
-	switch {
-	case err == nil:
-		// nothing
-	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionAlreadyExists):
-		// nothing
-	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid):
-		msg := fmt.Sprintf("Invalid collection name: %s", params.Collection)
-		return 0, 0, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "insert")
-	default:
-		return 0, 0, nil, lazyerrors.Error(err)
-	}
+	upsert := slices.ContainsFunc(params.Updates, func(u common.Update) bool { return u.Upsert })
 
-	for _, u := range params.Updates {
-		c, err := db.Collection(params.Collection)
-		if err != nil {
+	if upsert {
+		if _, err = db.Collection(params.Collection); err != nil {
 			if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
 				msg := fmt.Sprintf("Invalid collection name: %s", params.Collection)
-				return 0, 0, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "insert")
+				return 0, 0, nil, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "insert")
 			}
 
-			return 0, 0, nil, lazyerrors.Error(err)
+			return 0, 0, nil, nil, lazyerrors.Error(err)
 		}
 
-		var qp backends.QueryParams
-		if !h.DisablePushdown {
-			qp.Filter = u.Filter
+		if err = h.checkImplicitCollection(ctx, db, params.DB, params.Collection, "update"); err != nil {
+			return 0, 0, nil, nil, err
 		}
+	}
 
-		res, err := c.Query(ctx, &qp)
-		if err != nil {
-			return 0, 0, nil, lazyerrors.Error(err)
-		}
+	// without upserts, collection is created only if that is allowed by the policy and quota
+	create := upsert
 
-		closer := iterator.NewMultiCloser()
-		defer closer.Close()
+	if !upsert && h.implicitCollectionPolicy(params.DB) == ImplicitCollectionAllow {
+		var q *quota
+		if q, err = h.collectionQuotaExceeded(ctx, db, params.DB, params.Collection); err != nil {
+			return 0, 0, nil, nil, lazyerrors.Error(err)
+		}
 
-		closer.Add(res.Iter)
+		create = q == nil
+	}
 
-		iter := common.FilterIterator(res.Iter, closer, u.Filter)
+	if create {
+		err = db.CreateCollection(ctx, &backends.CreateCollectionParams{Name: params.Collection})
 
-		if !u.Multi {
-			iter = common.LimitIterator(iter, closer, 1)
+		switch {
+		case err == nil:
+			// nothing
+		case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionAlreadyExists):
+			// nothing
+		case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid):
+			msg := fmt.Sprintf("Invalid collection name: %s", params.Collection)
+			return 0, 0, nil, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "insert")
+		default:
+			return 0, 0, nil, nil, lazyerrors.Error(err)
 		}
+	}
+
+	v, err := h.documentValidator(ctx, db, params.Collection)
+	if err != nil {
+		return 0, 0, nil, nil, lazyerrors.Error(err)
+	}
+
+	var mu sync.Mutex
+	var matched, modified int32
+	var upserted []*types.Document
+	var writeErrors handlererrors.WriteErrors
+
+	exec := func(ctx context.Context, i int) error {
+		m, mod, id, err := h.execUpdate(ctx, db, params, &params.Updates[i], v)
+
+		mu.Lock()
+		defer mu.Unlock()
 
-		result, err := common.UpdateDocument(ctx, c, "update", iter, &u)
 		if err != nil {
-			return 0, 0, nil, lazyerrors.Error(err)
+			return err
 		}
 
-		matched += result.Matched.Count
-		modified += result.Modified.Count
+		matched += m
+		modified += mod
 
-		if result.Upserted.Doc != nil {
-			doc := result.Upserted.Doc
-			upserted.Append(must.NotFail(types.NewDocument(
-				"index", int32(upserted.Len()),
-				"_id", must.NotFail(doc.Get("_id")),
+		if id != nil {
+			upserted = append(upserted, must.NotFail(types.NewDocument(
+				"index", int32(i),
+				"_id", id,
 			)))
 
 			// in case of upsert, MongoDB sets the matched count to 1
 			matched++
 		}
+
+		return nil
+	}
+
+	// execUnordered executes a single unordered statement and records its error as write error
+	execUnordered := func(ctx context.Context, i int) error {
+		err := exec(ctx, i)
+		if err == nil {
+			return nil
+		}
+
+		err = handleUpdateError(params.DB, params.Collection, "update", err)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		var we *handlererrors.WriteErrors
+		var ce *handlererrors.CommandError
+
+		switch {
+		case errors.As(err, &we):
+			writeErrors.Merge(we, int32(i))
+		case errors.As(err, &ce):
+			writeErrors.Append(ce, int32(i))
+		default:
+			return err
+		}
+
+		return nil
+	}
+
+	switch {
+	case params.Ordered:
+		// TODO https://github.com/FerretDB/FerretDB/issues/2612
+		for i := range params.Updates {
+			if err = exec(ctx, i); err != nil {
+				break
+			}
+		}
+
+	case h.BulkWriteConcurrency > 1 && concurrentUpdates(params.Updates):
+		err = h.bulkWrite(ctx, len(params.Updates), execUnordered)
+
+	default:
+		for i := range params.Updates {
+			if err = execUnordered(ctx, i); err != nil {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		return 0, 0, nil, nil, err
+	}
+
+	slices.SortFunc(upserted, func(a, b *types.Document) int {
+		return cmp.Compare(must.NotFail(a.Get("index")).(int32), must.NotFail(b.Get("index")).(int32))
+	})
+
+	res := types.MakeArray(len(upserted))
+	for _, doc := range upserted {
+		res.Append(doc)
+	}
+
+	return matched, modified, res, &writeErrors, nil
+}
+
+// concurrentUpdates returns true if update statements could be executed concurrently.
+//
+// That is the case when each statement selects a different single document by `_id`,
+// so statements can't affect each other.
+func concurrentUpdates(updates []common.Update) bool {
+	if len(updates) < 2 {
+		return false
+	}
+
+	ids := make(map[string]struct{}, len(updates))
+
+	for _, u := range updates {
+		if u.Filter.Len() != 1 || !u.Filter.Has("_id") {
+			return false
+		}
+
+		id, ok := documentIDKey(must.NotFail(u.Filter.Get("_id")))
+		if !ok {
+			return false
+		}
+
+		if _, ok = ids[id]; ok {
+			return false
+		}
+
+		ids[id] = struct{}{}
+	}
+
+	return true
+}
+
+// execUpdate performs a single update statement.
+// Upserted and modified documents are checked by the given validator that may be nil.
+//
+// It returns the number of matched and modified documents,
+// and `_id` of the upserted document if any.
+func (h *Handler) execUpdate(ctx context.Context, db backends.Database, params *common.UpdateParams, u *common.Update, v *common.DocumentValidator) (int32, int32, any, error) { //nolint:lll // for readability
+	c, err := db.Collection(params.Collection)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid collection name: %s", params.Collection)
+			return 0, 0, nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, "insert")
+		}
+
+		return 0, 0, nil, lazyerrors.Error(err)
+	}
+
+	// coalesced updates are not checked by the validator, so they are used only without it
+	if v == nil {
+		if op, ok := h.coalesceInc(ctx, c, params.DB, params.Collection, u); ok {
+			if op.err != nil {
+				return 0, 0, nil, op.err
+			}
+
+			return op.matched, op.modified, nil, nil
+		}
+	}
+
+	var qp backends.QueryParams
+	if !h.disablePushdown.Load() {
+		qp.Filter = u.Filter
+	}
+
+	res, err := c.Query(ctx, &qp)
+	if err != nil {
+		return 0, 0, nil, lazyerrors.Error(err)
+	}
+
+	closer := iterator.NewMultiCloser()
+	defer closer.Close()
+
+	closer.Add(res.Iter)
+
+	iter := common.FilterIterator(res.Iter, closer, u.Filter)
+
+	if !u.Multi {
+		iter = common.LimitIterator(iter, closer, 1)
+	}
+
+	// inserts and updates that grow documents are checked against quotas
+	if c, err = h.withQuota(ctx, db, params.DB, params.Collection, c, "update"); err != nil {
+		return 0, 0, nil, lazyerrors.Error(err)
+	}
+
+	result, err := common.UpdateDocument(ctx, c, "update", iter, u, v)
+	if err != nil {
+		return 0, 0, nil, lazyerrors.Error(err)
+	}
+
+	if result.Upserted.Doc != nil {
+		return result.Matched.Count, result.Modified.Count, must.NotFail(result.Upserted.Doc.Get("_id")), nil
 	}
 
-	return matched, modified, &upserted, nil
+	return result.Matched.Count, result.Modified.Count, nil, nil
 }
@@ -0,0 +1,113 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/wire"
+)
+
+// MsgUpdateSearchIndex implements `updateSearchIndex` command.
+//
+// The index is dropped and created again with the new definition.
+func (h *Handler) MsgUpdateSearchIndex(ctx context.Context, msg *wire.OpMsg) (*wire.OpMsg, error) {
+	document, err := msg.Document()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	command := document.Command()
+
+	c, err := h.searchIndexesCollection(document)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := searchIndexNameParam(document)
+	if err != nil {
+		return nil, err
+	}
+
+	definition, err := common.GetRequiredParam[*types.Document](document, "definition")
+	if err != nil {
+		return nil, err
+	}
+
+	list, err := c.ListSearchIndexes(ctx, new(backends.ListSearchIndexesParams))
+
+	switch {
+	case err == nil:
+		// nothing
+	case backends.ErrorCodeIs(err, backends.ErrorCodeNotSupported):
+		return nil, searchNotSupportedError(command)
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNamespaceNotFound,
+			"Collection does not exist",
+			command,
+		)
+	default:
+		return nil, lazyerrors.Error(err)
+	}
+
+	i := slices.IndexFunc(list.Indexes, func(i backends.SearchIndexInfo) bool { return i.Name == name })
+	if i < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrIndexNotFound,
+			fmt.Sprintf("Search index %q not found", name),
+			command,
+		)
+	}
+
+	// the index type can't be changed
+	parse := parseSearchIndexDefinition
+	if list.Indexes[i].Vector() {
+		parse = parseVectorSearchIndexDefinition
+	}
+
+	index, err := parse(command, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	index.Name = name
+
+	if _, err = c.DropSearchIndexes(ctx, &backends.DropSearchIndexesParams{Indexes: []string{name}}); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	params := &backends.CreateSearchIndexesParams{Indexes: []backends.SearchIndexInfo{*index}}
+	if _, err = c.CreateSearchIndexes(ctx, params); err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	var reply wire.OpMsg
+	must.NoError(reply.SetSections(wire.MakeOpMsgSection(
+		must.NotFail(types.NewDocument(
+			"ok", float64(1),
+		)),
+	)))
+
+	return &reply, nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/logging"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// parameter represents a server parameter accessible with `getParameter` and `setParameter` commands.
+type parameter struct {
+	// get returns the current value.
+	get func() any
+
+	// set validates a new value and returns a function that applies it at runtime.
+	// Validation and application are separate so `setParameter` with several parameters
+	// changes either all of them or none.
+	// It is nil for parameters that are settable at runtime in MongoDB, but not in FerretDB yet.
+	set func(v any) (apply func(), err error)
+
+	settableAtRuntime bool
+	settableAtStartup bool
+}
+
+// initParameters initializes the registry of server parameters.
+func (h *Handler) initParameters() {
+	h.parameters = map[string]*parameter{
+		// to add a new parameter, add it there and to the documentation
+		"authenticationMechanisms": {
+			get:               func() any { return must.NotFail(types.NewArray("PLAIN")) },
+			settableAtStartup: true,
+		},
+		"authSchemaVersion": {
+			get:               func() any { return int32(5) },
+			settableAtRuntime: true,
+			settableAtStartup: true,
+		},
+		"cursorTimeoutMillis": {
+			get: func() any { return h.cursors.Timeout().Milliseconds() },
+			set: func(v any) (func(), error) {
+				// the upper bound prevents time.Duration overflow
+				ms, err := getParameterNumber("cursorTimeoutMillis", v, 0, math.MaxInt64/int64(time.Millisecond))
+				if err != nil {
+					return nil, err
+				}
+
+				return func() { h.cursors.SetTimeout(time.Duration(ms) * time.Millisecond) }, nil
+			},
+			settableAtRuntime: true,
+			settableAtStartup: true,
+		},
+		"featureCompatibilityVersion": {
+			get: func() any { return must.NotFail(types.NewDocument("version", "7.0")) },
+		},
+		"featureFlagNestedPushdown": {
+			get: func() any { return must.NotFail(types.NewDocument("value", h.enableNestedPushdown.Load())) },
+			set: func(v any) (func(), error) {
+				b, err := getParameterBool("featureFlagNestedPushdown", v)
+				if err != nil {
+					return nil, err
+				}
+
+				return func() { h.enableNestedPushdown.Store(b) }, nil
+			},
+			settableAtRuntime: true,
+			settableAtStartup: true,
+		},
+		"featureFlagPushdown": {
+			get: func() any { return must.NotFail(types.NewDocument("value", !h.disablePushdown.Load())) },
+			set: func(v any) (func(), error) {
+				b, err := getParameterBool("featureFlagPushdown", v)
+				if err != nil {
+					return nil, err
+				}
+
+				return func() { h.disablePushdown.Store(!b) }, nil
+			},
+			settableAtRuntime: true,
+			settableAtStartup: true,
+		},
+		"logLevel": {
+			get: func() any {
+				// MongoDB's log verbosity levels 1-5 are all mapped to debug level
+				if logging.Level() <= zapcore.DebugLevel {
+					return int32(1)
+				}
+
+				return int32(0)
+			},
+			set: func(v any) (func(), error) {
+				level, err := getParameterNumber("logLevel", v, 0, 5)
+				if err != nil {
+					return nil, err
+				}
+
+				return func() {
+					switch {
+					case level > 0:
+						logging.SetLevel(zapcore.DebugLevel)
+					case h.startupLogLevel > zapcore.DebugLevel:
+						logging.SetLevel(h.startupLogLevel)
+					default:
+						logging.SetLevel(zapcore.InfoLevel)
+					}
+				}, nil
+			},
+			settableAtRuntime: true,
+			settableAtStartup: true,
+		},
+		"quiet": {
+			get:               func() any { return false },
+			settableAtRuntime: true,
+			settableAtStartup: true,
+		},
+		"slowms": {
+			get: func() any { return int32(h.SlowQueryThreshold().Milliseconds()) },
+			set: func(v any) (func(), error) {
+				ms, err := getParameterNumber("slowms", v, 0, 1<<31-1)
+				if err != nil {
+					return nil, err
+				}
+
+				return func() { h.SetSlowQueryThreshold(time.Duration(ms) * time.Millisecond) }, nil
+			},
+			settableAtRuntime: true,
+			settableAtStartup: true,
+		},
+	}
+}
+
+// parameterNames returns names of all server parameters in case-insensitive alphabetical order.
+func (h *Handler) parameterNames() []string {
+	names := make([]string, 0, len(h.parameters))
+	for name := range h.parameters {
+		names = append(names, name)
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	return names
+}
+
+// parametersDocument returns a document with all server parameters and their details
+// suitable for selectParameters.
+func (h *Handler) parametersDocument() *types.Document {
+	res := types.MakeDocument(len(h.parameters))
+
+	for _, name := range h.parameterNames() {
+		p := h.parameters[name]
+
+		res.Set(name, must.NotFail(types.NewDocument(
+			"value", p.get(),
+			"settableAtRuntime", p.settableAtRuntime,
+			"settableAtStartup", p.settableAtStartup,
+		)))
+	}
+
+	return res
+}
+
+// getParameterNumber returns a whole number value of the parameter
+// if it is in the given range (inclusive), or a command error.
+func getParameterNumber(name string, v any, minValue, maxValue int64) (int64, error) {
+	n, err := handlerparams.GetWholeNumberParam(v)
+	if err == nil && n >= minValue && n <= maxValue {
+		return n, nil
+	}
+
+	return 0, handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrBadValue,
+		fmt.Sprintf(
+			"Invalid value for parameter %s: %s; expected a whole number in range [%d, %d]",
+			name, types.FormatAnyValue(v), minValue, maxValue,
+		),
+		"setParameter",
+	)
+}
+
+// getParameterBool returns a boolean value of the parameter, or a command error.
+func getParameterBool(name string, v any) (bool, error) {
+	b, ok := v.(bool)
+	if ok {
+		return b, nil
+	}
+
+	return false, handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf(
+			"Invalid value for parameter %s: expected type 'bool', got '%s'",
+			name, handlerparams.AliasFromType(v),
+		),
+		"setParameter",
+	)
+}
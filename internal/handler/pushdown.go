@@ -0,0 +1,28 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import "slices"
+
+// nestedPushdownBackends contains backends that safely push down filters with dot notation,
+// including paths that go through arrays of documents.
+var nestedPushdownBackends = []string{"postgresql"}
+
+// nestedPushdown returns true if filter conditions with dot notation should be passed to the backend.
+//
+// For other backends, that is controlled by the featureFlagNestedPushdown parameter.
+func (h *Handler) nestedPushdown() bool {
+	return h.enableNestedPushdown.Load() || slices.Contains(nestedPushdownBackends, h.BackendName)
+}
@@ -0,0 +1,354 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package querycache provides a size-bounded cache of read command results.
+//
+// Entries are invalidated on writes to their collection or database.
+// To avoid caching results of reads that run concurrently with writes,
+// callers should get the collection Version before the read and pass it to Set;
+// results are not cached if the collection was invalidated in the meantime.
+package querycache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// minVersions is the number of namespace versions that are kept without pruning.
+const minVersions = 1000
+
+// Parts of Prometheus metric names.
+const (
+	namespace = "ferretdb"
+	subsystem = "query_cache"
+)
+
+// Key identifies a cached result.
+type Key struct {
+	DB         string
+	Collection string
+
+	// Query contains all command parameters that affect the result
+	// (including the username if results could depend on it) in some canonical form.
+	Query string
+}
+
+// ns identifies a collection or, if collection is empty, a database.
+type ns struct {
+	db         string
+	collection string
+}
+
+// Version represents the version of the collection and its database.
+type Version struct {
+	db         uint64
+	collection uint64
+}
+
+// entry represents a cached result.
+type entry struct {
+	key     Key
+	docs    *types.Array
+	size    int64
+	created time.Time
+	elem    *list.Element // in the LRU list
+}
+
+// Cache stores results of read commands.
+//
+// Nil cache is disabled: it returns nothing and stores nothing.
+type Cache struct {
+	maxSize int64
+	ttl     time.Duration
+
+	m        sync.Mutex
+	entries  map[Key]*entry
+	byNS     map[ns]map[Key]*entry // entries by collection
+	lru      *list.List            // of *entry, the most recently used at front
+	size     int64
+	versions map[ns]uint64 // set to the generation of the last invalidation
+	gen      uint64        // incremented on every invalidation
+	pruned   uint64        // the maximal version of pruned namespaces, used for namespaces without version
+
+	requests      *prometheus.CounterVec
+	invalidations prometheus.Counter
+	evictions     prometheus.Counter
+	sizeBytes     prometheus.GaugeFunc
+}
+
+// New creates a new cache that stores results of up to maxSize bytes in total for up to ttl.
+//
+// TTL bounds the staleness of results if the same backend is modified by other FerretDB instances.
+// If maxSize is not positive, nil is returned.
+func New(maxSize int64, ttl time.Duration) *Cache {
+	if maxSize <= 0 {
+		return nil
+	}
+
+	c := &Cache{
+		maxSize:  maxSize,
+		ttl:      ttl,
+		entries:  map[Key]*entry{},
+		byNS:     map[ns]map[Key]*entry{},
+		lru:      list.New(),
+		versions: map[ns]uint64{},
+
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "requests_total",
+				Help:      "Total number of query cache lookups.",
+			},
+			[]string{"result"},
+		),
+		invalidations: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "invalidated_total",
+				Help:      "Total number of query cache entries invalidated by writes.",
+			},
+		),
+		evictions: prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Subsystem: subsystem,
+				Name:      "evicted_total",
+				Help:      "Total number of query cache entries evicted because of the size limit.",
+			},
+		),
+	}
+
+	c.sizeBytes = prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "size_bytes",
+			Help:      "Current size of cached results in bytes.",
+		},
+		func() float64 {
+			c.m.Lock()
+			defer c.m.Unlock()
+
+			return float64(c.size)
+		},
+	)
+
+	return c
+}
+
+// Version returns the current version of the given collection.
+func (c *Cache) Version(db, collection string) Version {
+	if c == nil {
+		return Version{}
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	return c.version(db, collection)
+}
+
+// version returns the current version of the given collection.
+//
+// c.m should be held.
+func (c *Cache) version(db, collection string) Version {
+	return Version{
+		db:         c.nsVersion(ns{db: db}),
+		collection: c.nsVersion(ns{db: db, collection: collection}),
+	}
+}
+
+// nsVersion returns the current version of the given namespace.
+//
+// c.m should be held.
+func (c *Cache) nsVersion(n ns) uint64 {
+	if v, ok := c.versions[n]; ok {
+		return v
+	}
+
+	return c.pruned
+}
+
+// Get returns the cached result.
+//
+// Returned array must not be modified.
+func (c *Cache) Get(key Key) (*types.Array, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	e := c.entries[key]
+	if e != nil && c.ttl > 0 && time.Since(e.created) > c.ttl {
+		c.remove(e)
+		e = nil
+	}
+
+	if e == nil {
+		c.requests.WithLabelValues("miss").Inc()
+		return nil, false
+	}
+
+	c.lru.MoveToFront(e.elem)
+	c.requests.WithLabelValues("hit").Inc()
+
+	return e.docs, true
+}
+
+// Set caches the result of the given size in bytes, unless the collection version differs from the given one.
+//
+// The array must not be modified after that.
+func (c *Cache) Set(key Key, v Version, docs *types.Array, size int64) {
+	if c == nil || size > c.maxSize {
+		return
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if c.version(key.DB, key.Collection) != v {
+		return
+	}
+
+	if e := c.entries[key]; e != nil {
+		c.remove(e)
+	}
+
+	for c.size+size > c.maxSize {
+		c.remove(c.lru.Back().Value.(*entry))
+		c.evictions.Inc()
+	}
+
+	e := &entry{
+		key:     key,
+		docs:    docs,
+		size:    size,
+		created: time.Now(),
+	}
+	e.elem = c.lru.PushFront(e)
+
+	c.entries[key] = e
+	c.size += size
+
+	n := ns{db: key.DB, collection: key.Collection}
+	if c.byNS[n] == nil {
+		c.byNS[n] = map[Key]*entry{}
+	}
+
+	c.byNS[n][key] = e
+}
+
+// Invalidate removes cached results of the given collection,
+// or of all collections of the given database if collection is empty.
+//
+// It should be called after every write, successful or not.
+func (c *Cache) Invalidate(db, collection string) {
+	if c == nil {
+		return
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.gen++
+	c.versions[ns{db: db, collection: collection}] = c.gen
+
+	for cn, entries := range c.byNS {
+		if cn.db != db || (collection != "" && cn.collection != collection) {
+			continue
+		}
+
+		for _, e := range entries {
+			c.remove(e)
+			c.invalidations.Inc()
+		}
+	}
+
+	if len(c.versions) > 2*len(c.byNS)+minVersions {
+		c.pruneVersions()
+	}
+}
+
+// pruneVersions removes versions of namespaces without cached results,
+// so versions of short-lived databases and collections do not accumulate.
+//
+// Removed versions are folded into c.pruned that is returned for such namespaces.
+// That value is not lower than any removed version,
+// so results of reads that started before the last invalidation are still not cached.
+//
+// c.m should be held.
+func (c *Cache) pruneVersions() {
+	for n, v := range c.versions {
+		if _, ok := c.byNS[n]; ok {
+			continue
+		}
+
+		c.pruned = max(c.pruned, v)
+		delete(c.versions, n)
+	}
+}
+
+// remove removes the entry.
+//
+// c.m should be held.
+func (c *Cache) remove(e *entry) {
+	c.lru.Remove(e.elem)
+	delete(c.entries, e.key)
+	c.size -= e.size
+
+	n := ns{db: e.key.DB, collection: e.key.Collection}
+	delete(c.byNS[n], e.key)
+
+	if len(c.byNS[n]) == 0 {
+		delete(c.byNS, n)
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Cache) Describe(ch chan<- *prometheus.Desc) {
+	if c == nil {
+		return
+	}
+
+	c.requests.Describe(ch)
+	c.invalidations.Describe(ch)
+	c.evictions.Describe(ch)
+	c.sizeBytes.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Cache) Collect(ch chan<- prometheus.Metric) {
+	if c == nil {
+		return
+	}
+
+	c.requests.Collect(ch)
+	c.invalidations.Collect(ch)
+	c.evictions.Collect(ch)
+	c.sizeBytes.Collect(ch)
+}
+
+// check interfaces
+var (
+	_ prometheus.Collector = (*Cache)(nil)
+)
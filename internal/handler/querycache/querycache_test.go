@@ -0,0 +1,189 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querycache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	c := New(100, 0)
+
+	docs := must.NotFail(types.NewArray(must.NotFail(types.NewDocument("_id", int32(1)))))
+
+	k1 := Key{DB: "db", Collection: "c1", Query: "{}"}
+	k2 := Key{DB: "db", Collection: "c2", Query: "{}"}
+	k3 := Key{DB: "other", Collection: "c1", Query: "{}"}
+
+	for _, k := range []Key{k1, k2, k3} {
+		c.Set(k, c.Version(k.DB, k.Collection), docs, 10)
+
+		actual, ok := c.Get(k)
+		require.True(t, ok)
+		assert.Same(t, docs, actual)
+	}
+
+	t.Run("Invalidate", func(t *testing.T) {
+		v := c.Version("db", "c1")
+
+		c.Invalidate("db", "c1")
+
+		_, ok := c.Get(k1)
+		assert.False(t, ok)
+
+		_, ok = c.Get(k2)
+		assert.True(t, ok)
+
+		// result of the read that was concurrent with the write is not cached
+		c.Set(k1, v, docs, 10)
+
+		_, ok = c.Get(k1)
+		assert.False(t, ok)
+
+		c.Set(k1, c.Version("db", "c1"), docs, 10)
+
+		_, ok = c.Get(k1)
+		assert.True(t, ok)
+
+		v = c.Version("db", "c1")
+
+		c.Invalidate("db", "")
+
+		for _, k := range []Key{k1, k2} {
+			_, ok = c.Get(k)
+			assert.False(t, ok)
+		}
+
+		_, ok = c.Get(k3)
+		assert.True(t, ok)
+
+		c.Set(k1, v, docs, 10)
+
+		_, ok = c.Get(k1)
+		assert.False(t, ok)
+	})
+}
+
+func TestCacheVersionsPruning(t *testing.T) {
+	t.Parallel()
+
+	c := New(100, 0)
+
+	docs := must.NotFail(types.NewArray(must.NotFail(types.NewDocument("_id", int32(1)))))
+
+	k := Key{DB: "db", Collection: "c", Query: "{}"}
+	c.Set(k, c.Version(k.DB, k.Collection), docs, 10)
+
+	// the read of a short-lived collection is concurrent with the write
+	v := c.Version("tmp", "c0")
+	c.Invalidate("tmp", "c0")
+
+	for i := range 10 * minVersions {
+		c.Invalidate("tmp", fmt.Sprintf("c%d", i))
+	}
+
+	assert.LessOrEqual(t, len(c.versions), 2*len(c.byNS)+minVersions+1)
+
+	_, ok := c.Get(k)
+	assert.True(t, ok, "namespace with cached results is not pruned")
+
+	// pruned version still differs from the version before the write
+	c.Set(Key{DB: "tmp", Collection: "c0", Query: "{}"}, v, docs, 10)
+
+	_, ok = c.Get(Key{DB: "tmp", Collection: "c0", Query: "{}"})
+	assert.False(t, ok)
+
+	c.Set(Key{DB: "tmp", Collection: "c0", Query: "{}"}, c.Version("tmp", "c0"), docs, 10)
+
+	_, ok = c.Get(Key{DB: "tmp", Collection: "c0", Query: "{}"})
+	assert.True(t, ok)
+}
+
+func TestCacheEviction(t *testing.T) {
+	t.Parallel()
+
+	c := New(30, 0)
+	docs := types.MakeArray(0)
+
+	keys := []Key{
+		{DB: "db", Collection: "c", Query: "1"},
+		{DB: "db", Collection: "c", Query: "2"},
+		{DB: "db", Collection: "c", Query: "3"},
+	}
+
+	for _, k := range keys {
+		c.Set(k, c.Version("db", "c"), docs, 10)
+	}
+
+	// make the first key the most recently used
+	_, ok := c.Get(keys[0])
+	require.True(t, ok)
+
+	c.Set(Key{DB: "db", Collection: "c", Query: "4"}, c.Version("db", "c"), docs, 10)
+
+	_, ok = c.Get(keys[0])
+	assert.True(t, ok)
+
+	_, ok = c.Get(keys[1])
+	assert.False(t, ok)
+
+	_, ok = c.Get(keys[2])
+	assert.True(t, ok)
+
+	// too large results are not cached
+	c.Set(keys[1], c.Version("db", "c"), docs, 31)
+
+	_, ok = c.Get(keys[1])
+	assert.False(t, ok)
+}
+
+func TestCacheTTL(t *testing.T) {
+	t.Parallel()
+
+	c := New(100, time.Millisecond)
+	k := Key{DB: "db", Collection: "c"}
+
+	c.Set(k, c.Version("db", "c"), types.MakeArray(0), 10)
+
+	time.Sleep(10 * time.Millisecond)
+
+	_, ok := c.Get(k)
+	assert.False(t, ok)
+}
+
+func TestCacheDisabled(t *testing.T) {
+	t.Parallel()
+
+	c := New(0, 0)
+	require.Nil(t, c)
+
+	k := Key{DB: "db", Collection: "c"}
+
+	c.Set(k, c.Version("db", "c"), types.MakeArray(0), 10)
+	c.Invalidate("db", "c")
+
+	_, ok := c.Get(k)
+	assert.False(t, ok)
+}
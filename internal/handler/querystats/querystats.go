@@ -0,0 +1,246 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package querystats provides per-shape query statistics for the `$queryStats` aggregation stage.
+package querystats
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/base64"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/types/fjson"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// DefaultMaxShapes is the default maximum number of tracked query shapes.
+const DefaultMaxShapes = 5000
+
+// Key identifies a query shape.
+type Key struct {
+	DB         string
+	Collection string
+	Command    string // "find", "aggregate", etc
+
+	// Shape contains command-specific shape fields, such as filter, sort or pipeline.
+	// Literal values should be replaced with placeholders by Shape function.
+	Shape *types.Document
+}
+
+// queryShape returns a queryShape document for the key.
+func (k *Key) queryShape() *types.Document {
+	res := must.NotFail(types.NewDocument(
+		"cmdNs", must.NotFail(types.NewDocument(
+			"db", k.DB,
+			"coll", k.Collection,
+		)),
+		"command", k.Command,
+	))
+
+	if k.Shape != nil {
+		for _, f := range k.Shape.Keys() {
+			res.Set(f, must.NotFail(k.Shape.Get(f)))
+		}
+	}
+
+	return res
+}
+
+// aggregate stores sum, max and min of some values.
+type aggregate struct {
+	sum int64
+	max int64
+	min int64
+}
+
+// add adds the given value to the aggregate.
+func (a *aggregate) add(v int64, first bool) {
+	a.sum += v
+
+	if first || v > a.max {
+		a.max = v
+	}
+
+	if first || v < a.min {
+		a.min = v
+	}
+}
+
+// document returns a document representation of the aggregate.
+func (a *aggregate) document() *types.Document {
+	return must.NotFail(types.NewDocument(
+		"sum", a.sum,
+		"max", a.max,
+		"min", a.min,
+	))
+}
+
+// entry stores statistics for a single query shape.
+type entry struct {
+	queryShape *types.Document
+	keyHash    string
+
+	execCount           int64
+	lastExecutionMicros int64
+	totalExecMicros     aggregate
+	docsExamined        aggregate
+	docsReturned        aggregate
+	firstSeen           time.Time
+	latestSeen          time.Time
+}
+
+// Execution contains metrics of a single query execution.
+type Execution struct {
+	Duration     time.Duration
+	DocsExamined int64
+	DocsReturned int64
+}
+
+// Registry stores statistics for query shapes.
+//
+// It is safe for concurrent use.
+type Registry struct {
+	maxShapes int
+
+	rw sync.RWMutex
+	m  map[string]*entry
+}
+
+// NewRegistry creates a new registry that tracks up to maxShapes query shapes.
+//
+// If maxShapes is zero or negative, DefaultMaxShapes is used.
+// When the limit is reached, the least recently seen shape is evicted.
+func NewRegistry(maxShapes int) *Registry {
+	if maxShapes <= 0 {
+		maxShapes = DefaultMaxShapes
+	}
+
+	return &Registry{
+		maxShapes: maxShapes,
+		m:         map[string]*entry{},
+	}
+}
+
+// Record adds a single query execution to the statistics of the given query shape.
+func (r *Registry) Record(key *Key, e *Execution) error {
+	queryShape := key.queryShape()
+
+	b, err := fjson.Marshal(queryShape)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	k := string(b)
+	micros := e.Duration.Microseconds()
+	now := time.Now()
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	ent := r.m[k]
+	if ent == nil {
+		if len(r.m) >= r.maxShapes {
+			r.evict()
+		}
+
+		h := sha256.Sum256(b)
+
+		ent = &entry{
+			queryShape: queryShape,
+			keyHash:    base64.StdEncoding.EncodeToString(h[:]),
+			firstSeen:  now,
+		}
+		r.m[k] = ent
+	}
+
+	first := ent.execCount == 0
+
+	ent.execCount++
+	ent.lastExecutionMicros = micros
+	ent.totalExecMicros.add(micros, first)
+	ent.docsExamined.add(e.DocsExamined, first)
+	ent.docsReturned.add(e.DocsReturned, first)
+	ent.latestSeen = now
+
+	return nil
+}
+
+// evict removes the least recently seen entry.
+//
+// It should be called with the lock held.
+func (r *Registry) evict() {
+	var oldestKey string
+	var oldest *entry
+
+	for k, ent := range r.m {
+		if oldest == nil || ent.latestSeen.Before(oldest.latestSeen) {
+			oldestKey, oldest = k, ent
+		}
+	}
+
+	delete(r.m, oldestKey)
+}
+
+// Reset removes all collected statistics.
+func (r *Registry) Reset() {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	r.m = map[string]*entry{}
+}
+
+// Documents returns `$queryStats` documents for all tracked query shapes, sorted by key hash.
+func (r *Registry) Documents() []*types.Document {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	asOf := time.Now()
+
+	entries := make([]*entry, 0, len(r.m))
+	for _, ent := range r.m {
+		entries = append(entries, ent)
+	}
+
+	slices.SortFunc(entries, func(a, b *entry) int {
+		return cmp.Compare(a.keyHash, b.keyHash)
+	})
+
+	res := make([]*types.Document, len(entries))
+
+	for i, ent := range entries {
+		res[i] = must.NotFail(types.NewDocument(
+			"key", must.NotFail(types.NewDocument(
+				"queryShape", ent.queryShape.DeepCopy(),
+			)),
+			"keyHash", ent.keyHash,
+			"metrics", must.NotFail(types.NewDocument(
+				"lastExecutionMicros", ent.lastExecutionMicros,
+				"execCount", ent.execCount,
+				"totalExecMicros", ent.totalExecMicros.document(),
+				"docsExamined", ent.docsExamined.document(),
+				"docsReturned", ent.docsReturned.document(),
+				"firstSeenTimestamp", ent.firstSeen,
+				"latestSeenTimestamp", ent.latestSeen,
+			)),
+			"asOf", asOf,
+		))
+	}
+
+	return res
+}
@@ -0,0 +1,191 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querystats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestShape(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		v        any
+		expected any
+	}{
+		"Nil": {
+			v:        nil,
+			expected: nil,
+		},
+		"Scalars": {
+			v: must.NotFail(types.NewDocument(
+				"a", int32(1),
+				"b", "foo",
+				"c", time.Now(),
+				"d", types.Null,
+				"e", types.NewObjectID(),
+			)),
+			expected: must.NotFail(types.NewDocument(
+				"a", "?number",
+				"b", "?string",
+				"c", "?date",
+				"d", "?null",
+				"e", "?objectId",
+			)),
+		},
+		"Operators": {
+			v: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gt", 42.0, "$in", must.NotFail(types.NewArray(int64(1), "x")))),
+			)),
+			expected: must.NotFail(types.NewDocument(
+				"v", must.NotFail(types.NewDocument("$gt", "?number", "$in", "?array")),
+			)),
+		},
+		"Or": {
+			v: must.NotFail(types.NewDocument(
+				"$or", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("a", "foo")),
+					must.NotFail(types.NewDocument("b", true)),
+				)),
+			)),
+			expected: must.NotFail(types.NewDocument(
+				"$or", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("a", "?string")),
+					must.NotFail(types.NewDocument("b", "?bool")),
+				)),
+			)),
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := Shape(tc.v)
+
+			if tc.expected == nil {
+				assert.Nil(t, actual)
+				return
+			}
+
+			testutil.AssertEqual(t, tc.expected.(*types.Document), actual.(*types.Document))
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(2)
+
+	key := func(v int32) *Key {
+		return &Key{
+			DB:         "db",
+			Collection: "coll",
+			Command:    "find",
+			Shape: must.NotFail(types.NewDocument(
+				"filter", Shape(must.NotFail(types.NewDocument("v", v))),
+			)),
+		}
+	}
+
+	// different literal values, same shape
+	assert.NoError(t, r.Record(key(1), &Execution{Duration: 10 * time.Microsecond, DocsExamined: 5, DocsReturned: 1}))
+	assert.NoError(t, r.Record(key(2), &Execution{Duration: 30 * time.Microsecond, DocsExamined: 3, DocsReturned: 3}))
+
+	docs := r.Documents()
+	assert.Len(t, docs, 1)
+
+	metrics := must.NotFail(docs[0].Get("metrics")).(*types.Document)
+	assert.Equal(t, int64(2), must.NotFail(metrics.Get("execCount")))
+	assert.Equal(t, int64(30), must.NotFail(metrics.Get("lastExecutionMicros")))
+
+	expected := must.NotFail(types.NewDocument("sum", int64(40), "max", int64(30), "min", int64(10)))
+	testutil.AssertEqual(t, expected, must.NotFail(metrics.Get("totalExecMicros")).(*types.Document))
+
+	expected = must.NotFail(types.NewDocument("sum", int64(8), "max", int64(5), "min", int64(3)))
+	testutil.AssertEqual(t, expected, must.NotFail(metrics.Get("docsExamined")).(*types.Document))
+
+	// the oldest shape is evicted
+	for _, cmd := range []string{"aggregate", "count"} {
+		k := key(1)
+		k.Command = cmd
+		assert.NoError(t, r.Record(k, new(Execution)))
+	}
+
+	docs = r.Documents()
+	assert.Len(t, docs, 2)
+
+	for _, doc := range docs {
+		cmd := must.NotFail(doc.GetByPath(types.NewStaticPath("key", "queryShape", "command")))
+		assert.NotEqual(t, "find", cmd)
+	}
+
+	r.Reset()
+	assert.Empty(t, r.Documents())
+}
+
+func TestTracker(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry(0)
+
+	var done int
+
+	tr := r.NewTracker(&Key{DB: "db", Collection: "coll", Command: "find"}, testutil.Logger(t), func(k *Key, e *Execution) {
+		done++
+		assert.Equal(t, int64(2), e.DocsReturned)
+	})
+
+	docs := []*types.Document{
+		must.NotFail(types.NewDocument("v", int32(1))),
+		must.NotFail(types.NewDocument("v", int32(2))),
+		must.NotFail(types.NewDocument("v", int32(3))),
+	}
+
+	closer := iterator.NewMultiCloser()
+	iter := tr.Examined(iterator.Values(iterator.ForSlice(docs)))
+	iter = tr.Returned(iterator.Values(iterator.ForSlice(must.NotFail(iterator.ConsumeValuesN(iter, 2)))))
+	closer.Add(iter)
+
+	for range 2 {
+		_, _, err := iter.Next()
+		assert.NoError(t, err)
+	}
+
+	_, _, err := iter.Next()
+	assert.ErrorIs(t, err, iterator.ErrIteratorDone)
+
+	assert.Empty(t, r.Documents(), "execution should be recorded on close")
+
+	closer.Close()
+	closer.Close()
+	assert.Equal(t, 1, done)
+
+	stats := r.Documents()
+	assert.Len(t, stats, 1)
+
+	metrics := must.NotFail(stats[0].Get("metrics")).(*types.Document)
+	assert.Equal(t, int64(1), must.NotFail(metrics.Get("execCount")))
+	assert.Equal(t, int64(2), must.NotFail(metrics.GetByPath(types.NewStaticPath("docsExamined", "sum"))))
+	assert.Equal(t, int64(2), must.NotFail(metrics.GetByPath(types.NewStaticPath("docsReturned", "sum"))))
+}
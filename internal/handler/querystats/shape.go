@@ -0,0 +1,98 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querystats
+
+import (
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Shape returns a copy of the given value with all literal values replaced by type placeholders,
+// such as "?number" or "?string".
+//
+// Field names and operators are kept as is, so queries that differ only by literal values
+// have the same shape.
+// Arrays of scalar values are replaced by a single "?array" placeholder.
+// Nil value is returned as is.
+func Shape(v any) any {
+	switch v := v.(type) {
+	case nil:
+		return nil
+
+	case *types.Document:
+		res := types.MakeDocument(v.Len())
+
+		for _, k := range v.Keys() {
+			res.Set(k, Shape(must.NotFail(v.Get(k))))
+		}
+
+		return res
+
+	case *types.Array:
+		if !hasDocuments(v) {
+			return "?array"
+		}
+
+		res := types.MakeArray(v.Len())
+
+		for i := 0; i < v.Len(); i++ {
+			res.Append(Shape(must.NotFail(v.Get(i))))
+		}
+
+		return res
+
+	default:
+		return placeholder(v)
+	}
+}
+
+// hasDocuments returns true if the given array contains at least one document.
+func hasDocuments(arr *types.Array) bool {
+	for i := 0; i < arr.Len(); i++ {
+		if _, ok := must.NotFail(arr.Get(i)).(*types.Document); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// placeholder returns a placeholder for the given scalar value.
+func placeholder(v any) string {
+	switch v.(type) {
+	case float64, int32, int64:
+		return "?number"
+	case string:
+		return "?string"
+	case types.Binary:
+		return "?binData"
+	case types.ObjectID:
+		return "?objectId"
+	case bool:
+		return "?bool"
+	case time.Time:
+		return "?date"
+	case types.NullType:
+		return "?null"
+	case types.Regex:
+		return "?regex"
+	case types.Timestamp:
+		return "?timestamp"
+	default:
+		return "?unknown"
+	}
+}
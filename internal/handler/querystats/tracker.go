@@ -0,0 +1,142 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package querystats
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+)
+
+// Tracker collects metrics of a single query execution and records them
+// to the registry when the query's result iterator is closed.
+type Tracker struct {
+	r     *Registry
+	key   *Key
+	l     *zap.Logger
+	done  func(*Key, *Execution) // may be nil
+	start time.Time
+
+	examined atomic.Int64
+	returned atomic.Int64
+	execTime atomic.Int64 // time spent in Next calls and before the first one, in nanoseconds
+
+	once sync.Once
+}
+
+// NewTracker starts tracking a new execution of the given query shape.
+//
+// Time between this call and the call of [Tracker.Returned] is counted as execution time.
+// If done is not nil, it is called after the execution is recorded.
+func (r *Registry) NewTracker(key *Key, l *zap.Logger, done func(*Key, *Execution)) *Tracker {
+	return &Tracker{
+		r:     r,
+		key:   key,
+		l:     l,
+		done:  done,
+		start: time.Now(),
+	}
+}
+
+// Examined wraps the given iterator (typically, the one returned by the backend)
+// to count documents examined by the query.
+//
+// The returned iterator does not record anything on Close.
+func (t *Tracker) Examined(iter types.DocumentsIterator) types.DocumentsIterator {
+	return &countingIterator{
+		iter:  iter,
+		count: &t.examined,
+	}
+}
+
+// Returned wraps the given iterator (typically, the last one in the chain)
+// to count documents returned to the client and the time spent in Next calls.
+//
+// Execution is recorded when the returned iterator is closed for the first time.
+func (t *Tracker) Returned(iter types.DocumentsIterator) types.DocumentsIterator {
+	t.execTime.Add(int64(time.Since(t.start)))
+
+	return &countingIterator{
+		iter:     iter,
+		count:    &t.returned,
+		execTime: &t.execTime,
+		onClose:  t.record,
+	}
+}
+
+// record records the execution to the registry once.
+func (t *Tracker) record() {
+	t.once.Do(func() {
+		e := &Execution{
+			Duration:     time.Duration(t.execTime.Load()),
+			DocsExamined: t.examined.Load(),
+			DocsReturned: t.returned.Load(),
+		}
+
+		if err := t.r.Record(t.key, e); err != nil {
+			t.l.Warn("Failed to record query stats", zap.Error(err))
+		}
+
+		if t.done != nil {
+			t.done(t.key, e)
+		}
+	})
+}
+
+// countingIterator counts documents returned by the wrapped iterator.
+type countingIterator struct {
+	iter     types.DocumentsIterator
+	count    *atomic.Int64
+	execTime *atomic.Int64 // may be nil
+	onClose  func()        // may be nil
+}
+
+// Next implements iterator.Interface.
+func (iter *countingIterator) Next() (struct{}, *types.Document, error) {
+	var start time.Time
+	if iter.execTime != nil {
+		start = time.Now()
+	}
+
+	k, v, err := iter.iter.Next()
+
+	if iter.execTime != nil {
+		iter.execTime.Add(int64(time.Since(start)))
+	}
+
+	if err == nil {
+		iter.count.Add(1)
+	}
+
+	return k, v, err
+}
+
+// Close implements iterator.Interface.
+func (iter *countingIterator) Close() {
+	iter.iter.Close()
+
+	if iter.onClose != nil {
+		iter.onClose()
+	}
+}
+
+// check interfaces
+var (
+	_ types.DocumentsIterator = (*countingIterator)(nil)
+)
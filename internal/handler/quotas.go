@@ -0,0 +1,528 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/bson2"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// quotasCollection is the name of the collection in the admin database that stores quotas.
+const quotasCollection = "system.quotas"
+
+// quotasReloadInterval is the interval after which cached quotas are reloaded,
+// so quotas set by other FerretDB instances are eventually enforced too.
+const quotasReloadInterval = 10 * time.Second
+
+// quota represents limits of the database (if collection is empty) or of the collection.
+//
+// Zero values mean no limit.
+// Limits are checked using backend statistics, so they are approximate.
+type quota struct {
+	db             string
+	collection     string
+	maxDataSize    int64
+	maxCollections int64 // for databases only
+	maxDocuments   int64
+}
+
+// quotaID returns the ID of the database (if cName is empty) or collection quota.
+func quotaID(dbName, cName string) string {
+	if cName == "" {
+		return dbName
+	}
+
+	return dbName + "." + cName
+}
+
+// document returns the quota as stored in the admin database.
+func (q *quota) document() *types.Document {
+	doc := must.NotFail(types.NewDocument(
+		"_id", quotaID(q.db, q.collection),
+		"db", q.db,
+	))
+
+	if q.collection != "" {
+		doc.Set("collection", q.collection)
+	}
+
+	doc.Set("maxDataSize", q.maxDataSize)
+
+	if q.collection == "" {
+		doc.Set("maxCollections", q.maxCollections)
+	}
+
+	doc.Set("maxDocuments", q.maxDocuments)
+
+	return doc
+}
+
+// parseQuota parses and validates the quota document (`setQuota` command or stored document).
+func parseQuota(command string, doc *types.Document, dbName, cName string) (*quota, error) {
+	q := &quota{
+		db:         dbName,
+		collection: cName,
+	}
+
+	for field, dst := range map[string]*int64{
+		"maxDataSize":    &q.maxDataSize,
+		"maxCollections": &q.maxCollections,
+		"maxDocuments":   &q.maxDocuments,
+	} {
+		v, _ := doc.Get(field)
+		if v == nil {
+			continue
+		}
+
+		var err error
+		if *dst, err = handlerparams.GetValidatedNumberParamWithMinValue(command, field, v, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	if cName != "" && q.maxCollections != 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrInvalidOptions,
+			"maxCollections is supported only for database quotas",
+			command,
+		)
+	}
+
+	return q, nil
+}
+
+// quotaTarget returns the collection name of the quota command,
+// or empty string for the database quota (command value is a number).
+func quotaTarget(document *types.Document) (string, error) {
+	command := document.Command()
+
+	switch v := must.NotFail(document.Get(command)).(type) {
+	case string:
+		if v == "" {
+			return "", handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidNamespace,
+				"Invalid collection name: ",
+				command,
+			)
+		}
+
+		return v, nil
+	case float64, int32, int64:
+		return "", nil
+	default:
+		return "", handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrTypeMismatch,
+			fmt.Sprintf(
+				"BSON field '%s' is the wrong type '%s', expected types '[string, number]'",
+				command, handlerparams.AliasFromType(v),
+			),
+			command,
+		)
+	}
+}
+
+// quotas caches quotas stored in the admin database.
+type quotas struct {
+	rw     sync.RWMutex
+	loaded time.Time
+	quotas map[string]*quota // keyed by quotaID
+}
+
+// invalidate makes quotas to be reloaded on the next use.
+func (qs *quotas) invalidate() {
+	qs.rw.Lock()
+	defer qs.rw.Unlock()
+
+	qs.loaded = time.Time{}
+}
+
+// quotasCollection returns the backend collection that stores quotas.
+func (h *Handler) quotasCollection() (backends.Collection, error) {
+	adminDB, err := h.b.Database("admin")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, err := adminDB.Collection(quotasCollection)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	return c, nil
+}
+
+// loadQuotas returns all quotas keyed by quotaID.
+func (h *Handler) loadQuotas(ctx context.Context) (map[string]*quota, error) {
+	c, err := h.quotasCollection()
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	qr, err := c.Query(ctx, nil)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	res := map[string]*quota{}
+
+	for {
+		_, doc, err := qr.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		id, _ := doc.Get("_id")
+		dbName, _ := doc.Get("db")
+
+		var cName string
+		if v, _ := doc.Get("collection"); v != nil {
+			cName = fmt.Sprint(v)
+		}
+
+		q, err := parseQuota("setQuota", doc, fmt.Sprint(dbName), cName)
+		if err != nil {
+			return nil, lazyerrors.Errorf("invalid quota %v: %w", id, err)
+		}
+
+		res[quotaID(q.db, q.collection)] = q
+	}
+
+	return res, nil
+}
+
+// quotasFor returns quotas of the given database and collection; any of them may be nil.
+func (h *Handler) quotasFor(ctx context.Context, dbName, cName string) (dbQuota, cQuota *quota, err error) {
+	h.quotas.rw.RLock()
+	all, loaded := h.quotas.quotas, h.quotas.loaded
+	h.quotas.rw.RUnlock()
+
+	if time.Since(loaded) > quotasReloadInterval {
+		if all, err = h.loadQuotas(ctx); err != nil {
+			return nil, nil, lazyerrors.Error(err)
+		}
+
+		h.quotas.rw.Lock()
+		h.quotas.quotas, h.quotas.loaded = all, time.Now()
+		h.quotas.rw.Unlock()
+	}
+
+	dbQuota = all[quotaID(dbName, "")]
+
+	if cName != "" {
+		cQuota = all[quotaID(dbName, cName)]
+	}
+
+	return dbQuota, cQuota, nil
+}
+
+// removeQuota removes the database (if cName is empty) or collection quota.
+// It returns false if there was none.
+func (h *Handler) removeQuota(ctx context.Context, dbName, cName string) (bool, error) {
+	c, err := h.quotasCollection()
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	res, err := c.DeleteAll(ctx, &backends.DeleteAllParams{IDs: []any{quotaID(dbName, cName)}})
+	if err != nil {
+		return false, lazyerrors.Error(err)
+	}
+
+	h.quotas.invalidate()
+
+	return res.Deleted > 0, nil
+}
+
+// errQuotaExceeded returns an error for the write that would exceed the given quota limit.
+func errQuotaExceeded(q *quota, limit string, value int64, command string) error {
+	msg := fmt.Sprintf("quota exceeded: %s of database %s is %d", limit, q.db, value)
+	if q.collection != "" {
+		msg = fmt.Sprintf("quota exceeded: %s of collection %s.%s is %d", limit, q.db, q.collection, value)
+	}
+
+	return handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrQuotaExceeded, msg, command)
+}
+
+// collectionQuotaExceeded returns the database quota if the given collection does not exist
+// and the database already has the maximum number of collections; otherwise, it returns nil.
+func (h *Handler) collectionQuotaExceeded(ctx context.Context, db backends.Database, dbName, cName string) (*quota, error) {
+	dbQuota, _, err := h.quotasFor(ctx, dbName, "")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if dbQuota == nil || dbQuota.maxCollections == 0 {
+		return nil, nil
+	}
+
+	list, err := db.ListCollections(ctx, new(backends.ListCollectionsParams))
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	for _, cInfo := range list.Collections {
+		if cInfo.Name == cName {
+			return nil, nil
+		}
+	}
+
+	if int64(len(list.Collections)) < dbQuota.maxCollections {
+		return nil, nil
+	}
+
+	return dbQuota, nil
+}
+
+// checkCollectionQuota should be called before a write that would create a collection
+// (explicitly or implicitly).
+//
+// It returns an error if the collection does not exist and the database has maximum number of collections.
+func (h *Handler) checkCollectionQuota(ctx context.Context, db backends.Database, dbName, cName, command string) error {
+	q, err := h.collectionQuotaExceeded(ctx, db, dbName, cName)
+	if err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if q != nil {
+		return errQuotaExceeded(q, "maxCollections", q.maxCollections, command)
+	}
+
+	return nil
+}
+
+// quotaCollection wraps a collection to check the database and collection quotas before inserts and updates.
+type quotaCollection struct {
+	backends.Collection
+	db      backends.Database
+	dbQuota *quota
+	cQuota  *quota
+	command string
+}
+
+// InsertAll implements backends.Collection interface.
+func (c *quotaCollection) InsertAll(ctx context.Context, params *backends.InsertAllParams) (*backends.InsertAllResult, error) { //nolint:lll // for readability
+	var size int64
+
+	for _, doc := range params.Docs {
+		d, err := bson2.ConvertDocument(doc)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		size += int64(d.Size())
+	}
+
+	if err := c.checkQuotas(ctx, int64(len(params.Docs)), size); err != nil {
+		return nil, err
+	}
+
+	return c.Collection.InsertAll(ctx, params)
+}
+
+// UpdateAll implements backends.Collection interface.
+//
+// It checks maxDataSize limits if updated documents are larger than stored ones.
+func (c *quotaCollection) UpdateAll(ctx context.Context, params *backends.UpdateAllParams) (*backends.UpdateAllResult, error) { //nolint:lll // for readability
+	if (c.dbQuota == nil || c.dbQuota.maxDataSize == 0) && (c.cQuota == nil || c.cQuota.maxDataSize == 0) {
+		return c.Collection.UpdateAll(ctx, params)
+	}
+
+	var growth int64
+
+	for _, doc := range params.Docs {
+		d, err := bson2.ConvertDocument(doc)
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		stored, err := c.storedSize(ctx, must.NotFail(doc.Get("_id")))
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		growth += int64(d.Size()) - stored
+	}
+
+	if growth > 0 {
+		if err := c.checkQuotas(ctx, 0, growth); err != nil {
+			return nil, err
+		}
+	}
+
+	return c.Collection.UpdateAll(ctx, params)
+}
+
+// storedSize returns the size of the stored document with the given _id, or 0 if there is none.
+func (c *quotaCollection) storedSize(ctx context.Context, id any) (int64, error) {
+	qr, err := c.Collection.Query(ctx, &backends.QueryParams{
+		Filter: must.NotFail(types.NewDocument("_id", id)),
+	})
+	if err != nil {
+		return 0, lazyerrors.Error(err)
+	}
+
+	defer qr.Iter.Close()
+
+	for {
+		_, doc, err := qr.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return 0, nil
+		}
+
+		if err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+
+		// backend filtering is not exact
+		if types.Compare(must.NotFail(doc.Get("_id")), id) != types.Equal {
+			continue
+		}
+
+		d, err := bson2.ConvertDocument(doc)
+		if err != nil {
+			return 0, lazyerrors.Error(err)
+		}
+
+		return int64(d.Size()), nil
+	}
+}
+
+// checkQuotas returns an error if adding the given number of documents and bytes
+// would exceed the collection or database quota.
+//
+// Statistics are refreshed first, because PostgreSQL does not know the number of rows
+// in tables that were never analyzed.
+func (c *quotaCollection) checkQuotas(ctx context.Context, count, size int64) error {
+	if c.cQuota != nil {
+		stats, err := c.Collection.Stats(ctx, &backends.CollectionStatsParams{Refresh: true})
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist) {
+			stats = new(backends.CollectionStatsResult)
+			err = nil
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if err = checkQuotaLimits(c.cQuota, stats.CountDocuments+count, stats.SizeCollection+size, c.command); err != nil {
+			return err
+		}
+	}
+
+	if c.dbQuota != nil {
+		stats, err := c.db.Stats(ctx, &backends.DatabaseStatsParams{Refresh: true})
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseDoesNotExist) {
+			stats = new(backends.DatabaseStatsResult)
+			err = nil
+		}
+
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if err = checkQuotaLimits(c.dbQuota, stats.CountDocuments+count, stats.SizeCollections+size, c.command); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkQuotaLimits returns an error if the number of documents or data size after the write exceed the quota.
+func checkQuotaLimits(q *quota, documents, dataSize int64, command string) error {
+	if q.maxDocuments > 0 && documents > q.maxDocuments {
+		return errQuotaExceeded(q, "maxDocuments", q.maxDocuments, command)
+	}
+
+	if q.maxDataSize > 0 && dataSize > q.maxDataSize {
+		return errQuotaExceeded(q, "maxDataSize", q.maxDataSize, command)
+	}
+
+	return nil
+}
+
+// withQuota returns the collection that checks quotas before inserts and updates
+// if the database or collection has a quota; otherwise, it returns the given collection.
+func (h *Handler) withQuota(ctx context.Context, db backends.Database, dbName, cName string, c backends.Collection, command string) (backends.Collection, error) { //nolint:lll // for readability
+	dbQuota, cQuota, err := h.quotasFor(ctx, dbName, cName)
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if dbQuota != nil && dbQuota.maxDataSize == 0 && dbQuota.maxDocuments == 0 {
+		dbQuota = nil
+	}
+
+	if cQuota != nil && cQuota.maxDataSize == 0 && cQuota.maxDocuments == 0 {
+		cQuota = nil
+	}
+
+	if dbQuota == nil && cQuota == nil {
+		return c, nil
+	}
+
+	return &quotaCollection{
+		Collection: c,
+		db:         db,
+		dbQuota:    dbQuota,
+		cQuota:     cQuota,
+		command:    command,
+	}, nil
+}
+
+// quotaUsage returns the database quota with its current usage for `dbStats`, or nil if there is none.
+// Sizes are divided by the given scale.
+func (h *Handler) quotaUsage(ctx context.Context, dbName string, collections, documents, dataSize, scale int64) (*types.Document, error) { //nolint:lll // for readability
+	dbQuota, _, err := h.quotasFor(ctx, dbName, "")
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	if dbQuota == nil {
+		return nil, nil
+	}
+
+	return must.NotFail(types.NewDocument(
+		"maxDataSize", dbQuota.maxDataSize/scale,
+		"dataSize", dataSize/scale,
+		"maxCollections", dbQuota.maxCollections,
+		"collections", collections,
+		"maxDocuments", dbQuota.maxDocuments,
+		"documents", documents,
+	)), nil
+}
+
+// check interfaces
+var (
+	_ backends.Collection = (*quotaCollection)(nil)
+)
@@ -0,0 +1,150 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/backends/sqlite"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/state"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestParseQuota(t *testing.T) {
+	t.Parallel()
+
+	doc := must.NotFail(types.NewDocument(
+		"setQuota", int32(1),
+		"maxDataSize", int64(1024),
+		"maxCollections", int32(10),
+		"maxDocuments", float64(100),
+	))
+
+	q, err := parseQuota("setQuota", doc, "app", "")
+	require.NoError(t, err)
+	assert.Equal(t, &quota{db: "app", maxDataSize: 1024, maxCollections: 10, maxDocuments: 100}, q)
+
+	// stored document is parsed back to the same quota
+	stored, err := parseQuota("setQuota", q.document(), "app", "")
+	require.NoError(t, err)
+	assert.Equal(t, q, stored)
+
+	q, err = parseQuota("setQuota", must.NotFail(types.NewDocument("maxDocuments", int32(5))), "app", "events")
+	require.NoError(t, err)
+	assert.Equal(t, "app.events", must.NotFail(q.document().Get("_id")))
+	assert.False(t, q.document().Has("maxCollections"))
+
+	for name, tc := range map[string]struct {
+		doc   *types.Document
+		cName string
+	}{
+		"Negative": {
+			doc: must.NotFail(types.NewDocument("maxDocuments", int32(-1))),
+		},
+		"WrongType": {
+			doc: must.NotFail(types.NewDocument("maxDataSize", "1GB")),
+		},
+		"CollectionsForCollection": {
+			doc:   must.NotFail(types.NewDocument("maxCollections", int32(1))),
+			cName: "events",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseQuota("setQuota", tc.doc, "app", tc.cName)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCheckQuotaLimits(t *testing.T) {
+	t.Parallel()
+
+	q := &quota{db: "app", collection: "events", maxDataSize: 100, maxDocuments: 10}
+
+	assert.NoError(t, checkQuotaLimits(q, 10, 100, "insert"))
+	assert.NoError(t, checkQuotaLimits(&quota{db: "app"}, 1000, 1000, "insert"))
+
+	var ce *handlererrors.CommandError
+
+	err := checkQuotaLimits(q, 11, 0, "insert")
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, handlererrors.ErrQuotaExceeded, ce.Code())
+	assert.Equal(t, "quota exceeded: maxDocuments of collection app.events is 10", ce.Err().Error())
+
+	err = checkQuotaLimits(q, 1, 101, "insert")
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, handlererrors.ErrQuotaExceeded, ce.Code())
+	assert.Equal(t, "quota exceeded: maxDataSize of collection app.events is 100", ce.Err().Error())
+}
+
+func TestQuotaCollectionUpdateAll(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.Ctx(t)
+
+	sp, err := state.NewProvider("")
+	require.NoError(t, err)
+
+	b, err := sqlite.NewBackend(&sqlite.NewBackendParams{URI: testutil.TestSQLiteURI(t, ""), L: testutil.Logger(t), P: sp})
+	require.NoError(t, err)
+	t.Cleanup(b.Close)
+
+	dbName, cName := testutil.DatabaseName(t), testutil.CollectionName(t)
+
+	db, err := b.Database(dbName)
+	require.NoError(t, err)
+
+	c, err := db.Collection(cName)
+	require.NoError(t, err)
+
+	_, err = c.InsertAll(ctx, &backends.InsertAllParams{Docs: []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "v", strings.Repeat("x", 100))),
+	}})
+	require.NoError(t, err)
+
+	stats, err := c.Stats(ctx, &backends.CollectionStatsParams{Refresh: true})
+	require.NoError(t, err)
+
+	qc := &quotaCollection{
+		Collection: c,
+		db:         db,
+		cQuota:     &quota{db: dbName, collection: cName, maxDataSize: stats.SizeCollection + 50},
+		command:    "update",
+	}
+
+	// shrinking is always allowed
+	_, err = qc.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "v", "x")),
+	}})
+	require.NoError(t, err)
+
+	_, err = qc.UpdateAll(ctx, &backends.UpdateAllParams{Docs: []*types.Document{
+		must.NotFail(types.NewDocument("_id", int32(1), "v", strings.Repeat("x", 200))),
+	}})
+
+	var ce *handlererrors.CommandError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, handlererrors.ErrQuotaExceeded, ce.Code())
+}
@@ -37,6 +37,7 @@ func init() {
 
 		handlerOpts := &handler.NewOpts{
 			Backend:     b,
+			BackendName: "hana",
 			TCPHost:     opts.TCPHost,
 			ReplSetName: opts.ReplSetName,
 
@@ -44,6 +45,33 @@ func init() {
 			ConnMetrics:   opts.ConnMetrics,
 			StateProvider: opts.StateProvider,
 
+			SlowQueryThreshold: opts.SlowQueryThreshold,
+
+			WriteRetryTimeout:   opts.WriteRetryTimeout,
+			WriteRetryMaxWrites: opts.WriteRetryMaxWrites,
+
+			ImplicitCollectionPolicy:           opts.ImplicitCollectionPolicy,
+			ImplicitCollectionDatabasePolicies: opts.ImplicitCollectionDatabasePolicies,
+			ImplicitCollectionTemplate:         opts.ImplicitCollectionTemplate,
+
+			DefaultMaxTime: opts.DefaultMaxTime,
+
+			CursorPrefetchMemory: opts.CursorPrefetchMemory,
+
+			QueryCacheSize: opts.QueryCacheSize,
+			QueryCacheTTL:  opts.QueryCacheTTL,
+
+			BulkWriteConcurrency: opts.BulkWriteConcurrency,
+
+			IncCoalescingWindow: opts.IncCoalescingWindow,
+
+			ArchiveInterval: opts.ArchiveInterval,
+
+			DBCheckInterval: opts.DBCheckInterval,
+
+			MaxBSONObjectSize: opts.MaxBSONObjectSize,
+			MaxMessageSize:    opts.MaxMessageSize,
+
 			DisablePushdown:         opts.DisablePushdown,
 			CappedCleanupPercentage: opts.CappedCleanupPercentage,
 			CappedCleanupInterval:   opts.CappedCleanupInterval,
@@ -25,9 +25,10 @@ import (
 func init() {
 	registry["postgresql"] = func(opts *NewHandlerOpts) (*handler.Handler, CloseBackendFunc, error) {
 		b, err := postgresql.NewBackend(&postgresql.NewBackendParams{
-			URI: opts.PostgreSQLURL,
-			L:   opts.Logger.Named("postgresql"),
-			P:   opts.StateProvider,
+			URI:             opts.PostgreSQLURL,
+			TenantIsolation: opts.PostgreSQLTenantIsolation,
+			L:               opts.Logger.Named("postgresql"),
+			P:               opts.StateProvider,
 		})
 		if err != nil {
 			return nil, nil, err
@@ -35,6 +36,7 @@ func init() {
 
 		handlerOpts := &handler.NewOpts{
 			Backend:     b,
+			BackendName: "postgresql",
 			TCPHost:     opts.TCPHost,
 			ReplSetName: opts.ReplSetName,
 
@@ -42,6 +44,33 @@ func init() {
 			ConnMetrics:   opts.ConnMetrics,
 			StateProvider: opts.StateProvider,
 
+			SlowQueryThreshold: opts.SlowQueryThreshold,
+
+			WriteRetryTimeout:   opts.WriteRetryTimeout,
+			WriteRetryMaxWrites: opts.WriteRetryMaxWrites,
+
+			ImplicitCollectionPolicy:           opts.ImplicitCollectionPolicy,
+			ImplicitCollectionDatabasePolicies: opts.ImplicitCollectionDatabasePolicies,
+			ImplicitCollectionTemplate:         opts.ImplicitCollectionTemplate,
+
+			DefaultMaxTime: opts.DefaultMaxTime,
+
+			CursorPrefetchMemory: opts.CursorPrefetchMemory,
+
+			QueryCacheSize: opts.QueryCacheSize,
+			QueryCacheTTL:  opts.QueryCacheTTL,
+
+			BulkWriteConcurrency: opts.BulkWriteConcurrency,
+
+			IncCoalescingWindow: opts.IncCoalescingWindow,
+
+			ArchiveInterval: opts.ArchiveInterval,
+
+			DBCheckInterval: opts.DBCheckInterval,
+
+			MaxBSONObjectSize: opts.MaxBSONObjectSize,
+			MaxMessageSize:    opts.MaxMessageSize,
+
 			DisablePushdown:         opts.DisablePushdown,
 			EnableNestedPushdown:    opts.EnableNestedPushdown,
 			CappedCleanupPercentage: opts.CappedCleanupPercentage,
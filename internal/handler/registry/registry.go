@@ -47,8 +47,36 @@ type NewHandlerOpts struct {
 	TCPHost       string
 	ReplSetName   string
 
+	SlowQueryThreshold time.Duration
+
+	WriteRetryTimeout   time.Duration
+	WriteRetryMaxWrites int
+
+	ImplicitCollectionPolicy           string
+	ImplicitCollectionDatabasePolicies map[string]string
+	ImplicitCollectionTemplate         string
+
+	DefaultMaxTime time.Duration
+
+	CursorPrefetchMemory int64
+
+	QueryCacheSize int64
+	QueryCacheTTL  time.Duration
+
+	BulkWriteConcurrency int
+
+	IncCoalescingWindow time.Duration
+
+	ArchiveInterval time.Duration
+
+	DBCheckInterval time.Duration
+
+	MaxBSONObjectSize int32
+	MaxMessageSize    int32
+
 	// for `postgresql` handler
-	PostgreSQLURL string
+	PostgreSQLURL             string
+	PostgreSQLTenantIsolation bool
 
 	// for `sqlite` handler
 	SQLiteURL string
@@ -35,6 +35,7 @@ func init() {
 
 		handlerOpts := &handler.NewOpts{
 			Backend:     b,
+			BackendName: "sqlite",
 			TCPHost:     opts.TCPHost,
 			ReplSetName: opts.ReplSetName,
 
@@ -42,6 +43,33 @@ func init() {
 			ConnMetrics:   opts.ConnMetrics,
 			StateProvider: opts.StateProvider,
 
+			SlowQueryThreshold: opts.SlowQueryThreshold,
+
+			WriteRetryTimeout:   opts.WriteRetryTimeout,
+			WriteRetryMaxWrites: opts.WriteRetryMaxWrites,
+
+			ImplicitCollectionPolicy:           opts.ImplicitCollectionPolicy,
+			ImplicitCollectionDatabasePolicies: opts.ImplicitCollectionDatabasePolicies,
+			ImplicitCollectionTemplate:         opts.ImplicitCollectionTemplate,
+
+			DefaultMaxTime: opts.DefaultMaxTime,
+
+			CursorPrefetchMemory: opts.CursorPrefetchMemory,
+
+			QueryCacheSize: opts.QueryCacheSize,
+			QueryCacheTTL:  opts.QueryCacheTTL,
+
+			BulkWriteConcurrency: opts.BulkWriteConcurrency,
+
+			IncCoalescingWindow: opts.IncCoalescingWindow,
+
+			ArchiveInterval: opts.ArchiveInterval,
+
+			DBCheckInterval: opts.DBCheckInterval,
+
+			MaxBSONObjectSize: opts.MaxBSONObjectSize,
+			MaxMessageSize:    opts.MaxMessageSize,
+
 			DisablePushdown:         opts.DisablePushdown,
 			EnableNestedPushdown:    opts.EnableNestedPushdown,
 			CappedCleanupPercentage: opts.CappedCleanupPercentage,
@@ -0,0 +1,166 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// schemaDefaultSampleSize is the default number of documents sampled by the schema discovery.
+const schemaDefaultSampleSize = 1000
+
+// schemaHistogram accumulates field paths and their value types over sampled documents.
+type schemaHistogram struct {
+	fields  map[string]*schemaField
+	paths   []string // in order of the first appearance
+	sampled int64
+}
+
+// schemaField represents a single field path of schemaHistogram.
+type schemaField struct {
+	types map[string]int64
+	count int64
+}
+
+// newSchemaHistogram creates a new empty schemaHistogram.
+func newSchemaHistogram() *schemaHistogram {
+	return &schemaHistogram{
+		fields: map[string]*schemaField{},
+	}
+}
+
+// add adds a sampled document to the histogram.
+func (sh *schemaHistogram) add(doc *types.Document) {
+	sh.sampled++
+	sh.addFields("", doc)
+}
+
+// addFields adds fields of the given (possibly embedded) document, using prefix for their paths.
+//
+// Embedded documents are traversed; array elements are not.
+func (sh *schemaHistogram) addFields(prefix string, doc *types.Document) {
+	iter := doc.Iterator()
+	defer iter.Close()
+
+	for {
+		k, v, err := iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			return
+		}
+
+		must.NoError(err)
+
+		path := prefix + k
+
+		f := sh.fields[path]
+		if f == nil {
+			f = &schemaField{types: map[string]int64{}}
+			sh.fields[path] = f
+			sh.paths = append(sh.paths, path)
+		}
+
+		f.count++
+		f.types[handlerparams.AliasFromType(v)]++
+
+		if embedded, ok := v.(*types.Document); ok {
+			sh.addFields(path+".", embedded)
+		}
+	}
+}
+
+// document returns the histogram in the reply format.
+//
+// Types of each field are sorted by descending count.
+func (sh *schemaHistogram) document() *types.Document {
+	fields := types.MakeArray(len(sh.paths))
+
+	for _, path := range sh.paths {
+		f := sh.fields[path]
+
+		names := make([]string, 0, len(f.types))
+		for name := range f.types {
+			names = append(names, name)
+		}
+
+		slices.SortFunc(names, func(a, b string) int {
+			if c := cmp.Compare(f.types[b], f.types[a]); c != 0 {
+				return c
+			}
+
+			return cmp.Compare(a, b)
+		})
+
+		fieldTypes := types.MakeArray(len(names))
+		for _, name := range names {
+			fieldTypes.Append(must.NotFail(types.NewDocument(
+				"type", name,
+				"count", f.types[name],
+				"probability", float64(f.types[name])/float64(f.count),
+			)))
+		}
+
+		fields.Append(must.NotFail(types.NewDocument(
+			"path", path,
+			"count", f.count,
+			"probability", float64(f.count)/float64(sh.sampled),
+			"types", fieldTypes,
+		)))
+	}
+
+	return must.NotFail(types.NewDocument(
+		"sampled", sh.sampled,
+		"fields", fields,
+	))
+}
+
+// discoverSchema samples up to sampleSize documents of the collection in the natural order
+// and returns the inferred field paths with histograms of their types.
+//
+// Non-existent collection produces an empty result.
+func discoverSchema(ctx context.Context, c backends.Collection, sampleSize int64) (*types.Document, error) {
+	res, err := c.Query(ctx, &backends.QueryParams{Limit: sampleSize})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer res.Iter.Close()
+
+	sh := newSchemaHistogram()
+
+	for {
+		_, doc, err := res.Iter.Next()
+		if errors.Is(err, iterator.ErrIteratorDone) {
+			break
+		}
+
+		if err != nil {
+			return nil, lazyerrors.Error(err)
+		}
+
+		sh.add(doc)
+	}
+
+	return sh.document(), nil
+}
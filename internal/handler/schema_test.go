@@ -0,0 +1,60 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestSchemaHistogram(t *testing.T) {
+	t.Parallel()
+
+	sh := newSchemaHistogram()
+
+	sh.add(must.NotFail(types.NewDocument("_id", int32(1), "v", "foo", "e", must.NotFail(types.NewDocument("a", int32(1))))))
+	sh.add(must.NotFail(types.NewDocument("_id", int32(2), "v", int64(42))))
+	sh.add(must.NotFail(types.NewDocument("_id", int32(3), "v", "bar", "arr", must.NotFail(types.NewArray(int32(1))))))
+	sh.add(must.NotFail(types.NewDocument("_id", int32(4), "e", types.Null)))
+
+	field := func(path string, count int64, fieldTypes ...any) *types.Document {
+		return must.NotFail(types.NewDocument(
+			"path", path,
+			"count", count,
+			"probability", float64(count)/4,
+			"types", must.NotFail(types.NewArray(fieldTypes...)),
+		))
+	}
+
+	fieldType := func(name string, count int64, probability float64) *types.Document {
+		return must.NotFail(types.NewDocument("type", name, "count", count, "probability", probability))
+	}
+
+	expected := must.NotFail(types.NewDocument(
+		"sampled", int64(4),
+		"fields", must.NotFail(types.NewArray(
+			field("_id", 4, fieldType("int", 4, 1)),
+			field("v", 3, fieldType("string", 2, 2.0/3), fieldType("long", 1, 1.0/3)),
+			field("e", 2, fieldType("null", 1, 0.5), fieldType("object", 1, 0.5)),
+			field("e.a", 1, fieldType("int", 1, 1)),
+			field("arr", 1, fieldType("array", 1, 1)),
+		)),
+	))
+
+	testutil.AssertEqual(t, expected, sh.document())
+}
@@ -0,0 +1,650 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// defaultSearchIndexName is the name of the search index used when it is not specified.
+const defaultSearchIndexName = "default"
+
+// Search index field types.
+const (
+	searchFieldString       = "string"
+	searchFieldAutocomplete = "autocomplete"
+	searchFieldDocument     = "document"
+)
+
+// searchNotSupportedError returns an error for backends without full-text and vector search support.
+func searchNotSupportedError(command string) error {
+	return handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrNotImplemented,
+		"Search is supported only by the PostgreSQL backend",
+		command,
+	)
+}
+
+// searchIndexesCollection returns the collection for search indexes management commands.
+func (h *Handler) searchIndexesCollection(document *types.Document) (backends.Collection, error) {
+	command := document.Command()
+
+	dbName, err := common.GetRequiredParam[string](document, "$db")
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := common.GetRequiredParam[string](document, command)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := h.b.Database(dbName)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeDatabaseNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s.%s'", dbName, collection)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	c, err := db.Collection(collection)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeCollectionNameIsInvalid) {
+			msg := fmt.Sprintf("Invalid namespace specified '%s.%s'", dbName, collection)
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(handlererrors.ErrInvalidNamespace, msg, command)
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	return c, nil
+}
+
+// parseSearchIndexDefinition parses search index definition document
+// in the format accepted by `createSearchIndexes` command.
+//
+// Only static and dynamic mappings of `string`, `autocomplete` and `document` fields are supported.
+// Field options other than `type` and `fields` (analyzers, tokenization, etc.) are ignored.
+func parseSearchIndexDefinition(command string, definition *types.Document) (*backends.SearchIndexInfo, error) {
+	for _, k := range definition.Keys() {
+		if k != "mappings" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				fmt.Sprintf("Search index definition field %q is not supported", k),
+				command,
+			)
+		}
+	}
+
+	v, _ := definition.Get("mappings")
+
+	mappings, ok := v.(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"Search index definition must contain 'mappings' object",
+			command,
+		)
+	}
+
+	var res backends.SearchIndexInfo
+
+	for _, k := range mappings.Keys() {
+		v := must.NotFail(mappings.Get(k))
+
+		switch k {
+		case "dynamic":
+			dynamic, ok := v.(bool)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNotImplemented,
+					fmt.Sprintf("Search index 'mappings.dynamic' of type %s is not supported", handlerparams.AliasFromType(v)),
+					command,
+				)
+			}
+
+			res.Dynamic = dynamic
+
+		case "fields":
+			fields, ok := v.(*types.Document)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf("Search index 'mappings.fields' must be an object, not %s", handlerparams.AliasFromType(v)),
+					command,
+				)
+			}
+
+			var err error
+			if res.Fields, err = parseSearchIndexFields(command, "", fields); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				fmt.Sprintf("Search index definition field %q is not supported", "mappings."+k),
+				command,
+			)
+		}
+	}
+
+	if !res.Dynamic && len(res.Fields) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"Search index definition must have dynamic mappings or at least one field",
+			command,
+		)
+	}
+
+	return &res, nil
+}
+
+// parseSearchIndexFields parses `fields` document of the search index mappings.
+//
+// Prefix is a dot-separated path of the `document` field that contains given fields.
+func parseSearchIndexFields(command, prefix string, fields *types.Document) ([]backends.SearchIndexField, error) {
+	var res []backends.SearchIndexField
+
+	for _, name := range fields.Keys() {
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		var defs []any
+
+		switch v := must.NotFail(fields.Get(name)).(type) {
+		case *types.Document:
+			defs = []any{v}
+		case *types.Array:
+			defs = must.NotFail(iterator.ConsumeValues(v.Iterator()))
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf("Search index field definition at path %q must be an object or an array", path),
+				command,
+			)
+		}
+
+		for _, v := range defs {
+			def, ok := v.(*types.Document)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf("Search index field definition at path %q must be an object", path),
+					command,
+				)
+			}
+
+			v, _ := def.Get("type")
+			typ, _ := v.(string)
+
+			switch typ {
+			case searchFieldString:
+				res = append(res, backends.SearchIndexField{Path: path})
+
+			case searchFieldAutocomplete:
+				res = append(res, backends.SearchIndexField{Path: path, Autocomplete: true})
+
+			case searchFieldDocument:
+				if dynamic, _ := def.Get("dynamic"); dynamic != nil && dynamic != false {
+					return nil, handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrNotImplemented,
+						fmt.Sprintf("Dynamic mappings of the search index field at path %q are not supported", path),
+						command,
+					)
+				}
+
+				v, _ := def.Get("fields")
+				if v == nil {
+					continue
+				}
+
+				nested, ok := v.(*types.Document)
+				if !ok {
+					return nil, handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrTypeMismatch,
+						fmt.Sprintf("Search index field 'fields' at path %q must be an object", path),
+						command,
+					)
+				}
+
+				nestedFields, err := parseSearchIndexFields(command, path, nested)
+				if err != nil {
+					return nil, err
+				}
+
+				res = append(res, nestedFields...)
+
+			case "":
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("Search index field definition at path %q must have a string 'type'", path),
+					command,
+				)
+
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNotImplemented,
+					fmt.Sprintf("Search index field type %q at path %q is not supported", typ, path),
+					command,
+				)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// searchIndexDefinition returns search index definition document for the given index.
+//
+// It is the reverse of parseSearchIndexDefinition.
+func searchIndexDefinition(index *backends.SearchIndexInfo) *types.Document {
+	fields := types.MakeDocument(len(index.Fields))
+
+	for _, f := range index.Fields {
+		typ := searchFieldString
+		if f.Autocomplete {
+			typ = searchFieldAutocomplete
+		}
+
+		setSearchIndexField(fields, strings.Split(f.Path, "."), typ)
+	}
+
+	return must.NotFail(types.NewDocument(
+		"mappings", must.NotFail(types.NewDocument(
+			"dynamic", index.Dynamic,
+			"fields", fields,
+		)),
+	))
+}
+
+// setSearchIndexField sets the field definition of the given type at the given path,
+// creating `document` fields for path prefixes as needed.
+func setSearchIndexField(fields *types.Document, path []string, typ string) {
+	if len(path) > 1 {
+		v, _ := fields.Get(path[0])
+
+		d, _ := v.(*types.Document)
+		if d == nil || !d.Has("fields") {
+			d = must.NotFail(types.NewDocument("type", searchFieldDocument, "fields", types.MakeDocument(0)))
+			fields.Set(path[0], d)
+		}
+
+		setSearchIndexField(must.NotFail(d.Get("fields")).(*types.Document), path[1:], typ)
+
+		return
+	}
+
+	def := must.NotFail(types.NewDocument("type", typ))
+
+	switch v, _ := fields.Get(path[0]); v := v.(type) {
+	case *types.Document:
+		fields.Set(path[0], must.NotFail(types.NewArray(v, def)))
+	case *types.Array:
+		v.Append(def)
+	default:
+		fields.Set(path[0], def)
+	}
+}
+
+// searchIndexDocument returns a document describing the search index in `$listSearchIndexes` stage format.
+//
+// Search indexes are built synchronously, so they are always ready and queryable.
+// Index names are unique within the collection, so they are also used as identifiers.
+func searchIndexDocument(index *backends.SearchIndexInfo) *types.Document {
+	typ, definition := searchIndexTypeSearch, searchIndexDefinition(index)
+	if index.Vector() {
+		typ, definition = searchIndexTypeVectorSearch, vectorSearchIndexDefinition(index)
+	}
+
+	return must.NotFail(types.NewDocument(
+		"id", index.Name,
+		"name", index.Name,
+		"type", typ,
+		"status", "READY",
+		"queryable", true,
+		"latestDefinition", definition,
+	))
+}
+
+// parseSearchStage parses `$search` stage document.
+//
+// Only `text`, `phrase` and `autocomplete` operators are supported.
+func parseSearchStage(stage *types.Document) (*backends.SearchParams, error) {
+	fields, ok := must.NotFail(stage.Get("$search")).(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("$search must take a nested object but found: %s", types.FormatAnyValue(stage)),
+			"$search (stage)",
+		)
+	}
+
+	res := backends.SearchParams{
+		Index: defaultSearchIndexName,
+	}
+
+	var operator *types.Document
+
+	for _, k := range fields.Keys() {
+		v := must.NotFail(fields.Get(k))
+
+		switch k {
+		case "index":
+			if res.Index, ok = v.(string); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf("$search.index must be a string, not %s", handlerparams.AliasFromType(v)),
+					"$search (stage)",
+				)
+			}
+
+		case string(backends.SearchText), string(backends.SearchPhrase), string(backends.SearchAutocomplete):
+			if operator != nil {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					"$search must contain exactly one operator",
+					"$search (stage)",
+				)
+			}
+
+			if operator, ok = v.(*types.Document); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf("$search.%s must be an object, not %s", k, handlerparams.AliasFromType(v)),
+					"$search (stage)",
+				)
+			}
+
+			res.Operator = backends.SearchOperator(k)
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				fmt.Sprintf("$search.%s is not supported", k),
+				"$search (stage)",
+			)
+		}
+	}
+
+	if operator == nil {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"$search must contain one of the operators: text, phrase, autocomplete",
+			"$search (stage)",
+		)
+	}
+
+	for _, k := range operator.Keys() {
+		v := must.NotFail(operator.Get(k))
+
+		var err error
+
+		switch k {
+		case "query":
+			// any of the query strings should match for text operator, so they could be joined
+			var query []string
+			if query, err = searchStringsParam(res.Operator, k, v, res.Operator == backends.SearchText); err != nil {
+				return nil, err
+			}
+
+			res.Query = strings.Join(query, " ")
+
+		case "path":
+			if res.Paths, err = searchStringsParam(res.Operator, k, v, true); err != nil {
+				return nil, err
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				fmt.Sprintf("$search.%s.%s is not supported", res.Operator, k),
+				"$search (stage)",
+			)
+		}
+	}
+
+	if res.Query == "" || len(res.Paths) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("$search.%s requires non-empty 'query' and 'path'", res.Operator),
+			"$search (stage)",
+		)
+	}
+
+	return &res, nil
+}
+
+// searchStringsParam returns the value of `$search` operator's field that could be
+// a string or, if allowArray is true, an array of strings.
+func searchStringsParam(operator backends.SearchOperator, field string, v any, allowArray bool) ([]string, error) {
+	switch v := v.(type) {
+	case string:
+		return []string{v}, nil
+
+	case *types.Array:
+		if !allowArray {
+			break
+		}
+
+		res := make([]string, v.Len())
+
+		for i := range res {
+			s, ok := must.NotFail(v.Get(i)).(string)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNotImplemented,
+					fmt.Sprintf("$search.%s.%s must contain only strings", operator, field),
+					"$search (stage)",
+				)
+			}
+
+			res[i] = s
+		}
+
+		return res, nil
+
+	case *types.Document:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrNotImplemented,
+			fmt.Sprintf("$search.%s.%s of type object is not supported", operator, field),
+			"$search (stage)",
+		)
+	}
+
+	return nil, handlererrors.NewCommandErrorMsgWithArgument(
+		handlererrors.ErrTypeMismatch,
+		fmt.Sprintf("$search.%s.%s has invalid type %s", operator, field, handlerparams.AliasFromType(v)),
+		"$search (stage)",
+	)
+}
+
+// processStagesSearch runs `$search`, `$vectorSearch` or `$listSearchIndexes` stage on the backend
+// and then processes the results through the rest of the stages.
+func processStagesSearch(ctx context.Context, closer *iterator.MultiCloser, c backends.Collection, stage *types.Document, stages []aggregations.Stage) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	var iter types.DocumentsIterator
+
+	list, err := c.ListSearchIndexes(ctx, new(backends.ListSearchIndexesParams))
+
+	switch {
+	case err == nil:
+		// nothing
+	case backends.ErrorCodeIs(err, backends.ErrorCodeNotSupported):
+		return nil, searchNotSupportedError("aggregate")
+	case backends.ErrorCodeIs(err, backends.ErrorCodeCollectionDoesNotExist):
+		list = new(backends.ListSearchIndexesResult)
+	default:
+		return nil, lazyerrors.Error(err)
+	}
+
+	switch stage.Command() {
+	case "$search":
+		if iter, err = searchDocuments(ctx, c, stage, list.Indexes); err != nil {
+			return nil, err
+		}
+
+	case "$vectorSearch":
+		if iter, err = vectorSearchDocuments(ctx, c, closer, stage, list.Indexes); err != nil {
+			return nil, err
+		}
+
+	case "$listSearchIndexes":
+		var docs []*types.Document
+		if docs, err = listSearchIndexesDocuments(stage, list.Indexes); err != nil {
+			return nil, err
+		}
+
+		iter = iterator.Values(iterator.ForSlice(docs))
+
+	default:
+		panic(fmt.Sprintf("unexpected stage %q", stage.Command()))
+	}
+
+	closer.Add(iter)
+
+	for _, s := range stages {
+		if iter, err = s.Process(ctx, iter, closer); err != nil {
+			return nil, err
+		}
+	}
+
+	return iter, nil
+}
+
+// searchDocuments returns documents matching `$search` stage using the given search indexes.
+//
+// Like in MongoDB Atlas, non-existing index or paths that are not indexed produce no results.
+func searchDocuments(ctx context.Context, c backends.Collection, stage *types.Document, indexes []backends.SearchIndexInfo) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	params, err := parseSearchStage(stage)
+	if err != nil {
+		return nil, err
+	}
+
+	empty := iterator.Values(iterator.ForSlice([]*types.Document{}))
+
+	i := slices.IndexFunc(indexes, func(i backends.SearchIndexInfo) bool { return i.Name == params.Index && !i.Vector() })
+	if i < 0 {
+		return empty, nil
+	}
+
+	index := indexes[i]
+
+	paths := make([]string, 0, len(params.Paths))
+
+	for _, path := range params.Paths {
+		autocomplete := params.Operator == backends.SearchAutocomplete
+
+		indexed := slices.ContainsFunc(index.Fields, func(f backends.SearchIndexField) bool {
+			return f.Path == path && f.Autocomplete == autocomplete
+		})
+
+		switch {
+		case indexed, index.Dynamic && !autocomplete:
+			paths = append(paths, path)
+		case autocomplete:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf(`"autocomplete" index field definition not present at path %s`, path),
+				"$search (stage)",
+			)
+		}
+	}
+
+	if len(paths) == 0 {
+		return empty, nil
+	}
+
+	params.Paths = paths
+
+	res, err := c.Search(ctx, params)
+	if err != nil {
+		if backends.ErrorCodeIs(err, backends.ErrorCodeNotSupported) {
+			return nil, searchNotSupportedError("aggregate")
+		}
+
+		return nil, lazyerrors.Error(err)
+	}
+
+	return res.Iter, nil
+}
+
+// listSearchIndexesDocuments returns documents for `$listSearchIndexes` stage.
+func listSearchIndexesDocuments(stage *types.Document, indexes []backends.SearchIndexInfo) ([]*types.Document, error) {
+	fields, ok := must.NotFail(stage.Get("$listSearchIndexes")).(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("$listSearchIndexes must take a nested object but found: %s", types.FormatAnyValue(stage)),
+			"$listSearchIndexes (stage)",
+		)
+	}
+
+	var name string
+
+	for _, k := range fields.Keys() {
+		v := must.NotFail(fields.Get(k))
+
+		switch k {
+		case "id", "name":
+			// index names are used as identifiers
+			if name, ok = v.(string); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf(
+						"BSON field '$listSearchIndexes.%s' is the wrong type '%s', expected type 'string'",
+						k, handlerparams.AliasFromType(v),
+					),
+					"$listSearchIndexes (stage)",
+				)
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParseInput,
+				fmt.Sprintf("BSON field '$listSearchIndexes.%s' is an unknown field.", k),
+				"$listSearchIndexes (stage)",
+			)
+		}
+	}
+
+	res := make([]*types.Document, 0, len(indexes))
+
+	for _, index := range indexes {
+		if name != "" && index.Name != name {
+			continue
+		}
+
+		res = append(res, searchIndexDocument(&index))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestSearchIndexDefinition(t *testing.T) {
+	t.Parallel()
+
+	definition := must.NotFail(types.NewDocument(
+		"mappings", must.NotFail(types.NewDocument(
+			"dynamic", true,
+			"fields", must.NotFail(types.NewDocument(
+				"title", must.NotFail(types.NewArray(
+					must.NotFail(types.NewDocument("type", "string")),
+					must.NotFail(types.NewDocument("type", "autocomplete")),
+				)),
+				"v", must.NotFail(types.NewDocument(
+					"type", "document",
+					"fields", must.NotFail(types.NewDocument(
+						"plot", must.NotFail(types.NewDocument("type", "string")),
+					)),
+				)),
+			)),
+		)),
+	))
+
+	index, err := parseSearchIndexDefinition("createSearchIndexes", definition)
+	require.NoError(t, err)
+
+	expected := &backends.SearchIndexInfo{
+		Dynamic: true,
+		Fields: []backends.SearchIndexField{
+			{Path: "title"},
+			{Path: "title", Autocomplete: true},
+			{Path: "v.plot"},
+		},
+	}
+	assert.Equal(t, expected, index)
+
+	testutil.AssertEqual(t, definition, searchIndexDefinition(index))
+
+	for name, tc := range map[string]struct {
+		definition *types.Document
+		code       handlererrors.ErrorCode
+	}{
+		"NoMappings": {
+			definition: must.NotFail(types.NewDocument()),
+			code:       handlererrors.ErrBadValue,
+		},
+		"Empty": {
+			definition: must.NotFail(types.NewDocument("mappings", must.NotFail(types.NewDocument()))),
+			code:       handlererrors.ErrBadValue,
+		},
+		"Analyzer": {
+			definition: must.NotFail(types.NewDocument("analyzer", "lucene.standard")),
+			code:       handlererrors.ErrNotImplemented,
+		},
+		"FieldType": {
+			definition: must.NotFail(types.NewDocument("mappings", must.NotFail(types.NewDocument(
+				"fields", must.NotFail(types.NewDocument(
+					"n", must.NotFail(types.NewDocument("type", "number")),
+				)),
+			)))),
+			code: handlererrors.ErrNotImplemented,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseSearchIndexDefinition("createSearchIndexes", tc.definition)
+
+			var ce *handlererrors.CommandError
+			require.ErrorAs(t, err, &ce)
+			assert.Equal(t, tc.code, ce.Code())
+		})
+	}
+}
+
+func TestParseSearchStage(t *testing.T) {
+	t.Parallel()
+
+	stage := must.NotFail(types.NewDocument("$search", must.NotFail(types.NewDocument(
+		"text", must.NotFail(types.NewDocument(
+			"query", must.NotFail(types.NewArray("quick", "fox")),
+			"path", must.NotFail(types.NewArray("title", "v.plot")),
+		)),
+	))))
+
+	params, err := parseSearchStage(stage)
+	require.NoError(t, err)
+
+	expected := &backends.SearchParams{
+		Index:    "default",
+		Operator: backends.SearchText,
+		Query:    "quick fox",
+		Paths:    []string{"title", "v.plot"},
+	}
+	assert.Equal(t, expected, params)
+
+	for name, tc := range map[string]struct {
+		stage *types.Document
+		code  handlererrors.ErrorCode
+	}{
+		"NoOperator": {
+			stage: must.NotFail(types.NewDocument("index", "default")),
+			code:  handlererrors.ErrBadValue,
+		},
+		"TwoOperators": {
+			stage: must.NotFail(types.NewDocument(
+				"text", must.NotFail(types.NewDocument("query", "a", "path", "a")),
+				"phrase", must.NotFail(types.NewDocument("query", "a", "path", "a")),
+			)),
+			code: handlererrors.ErrBadValue,
+		},
+		"Compound": {
+			stage: must.NotFail(types.NewDocument("compound", must.NotFail(types.NewDocument()))),
+			code:  handlererrors.ErrNotImplemented,
+		},
+		"PhraseArray": {
+			stage: must.NotFail(types.NewDocument(
+				"phrase", must.NotFail(types.NewDocument("query", must.NotFail(types.NewArray("a")), "path", "a")),
+			)),
+			code: handlererrors.ErrTypeMismatch,
+		},
+		"NoPath": {
+			stage: must.NotFail(types.NewDocument("text", must.NotFail(types.NewDocument("query", "a")))),
+			code:  handlererrors.ErrBadValue,
+		},
+		"Fuzzy": {
+			stage: must.NotFail(types.NewDocument(
+				"text", must.NotFail(types.NewDocument("query", "a", "path", "a", "fuzzy", must.NotFail(types.NewDocument()))),
+			)),
+			code: handlererrors.ErrNotImplemented,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseSearchStage(must.NotFail(types.NewDocument("$search", tc.stage)))
+
+			var ce *handlererrors.CommandError
+			require.ErrorAs(t, err, &ce)
+			assert.Equal(t, tc.code, ce.Code())
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/querystats"
+	"github.com/FerretDB/FerretDB/internal/types/fjson"
+)
+
+// SlowQueryThreshold returns the current slow query log threshold.
+// Zero or negative value means that the slow query log is disabled.
+func (h *Handler) SlowQueryThreshold() time.Duration {
+	return time.Duration(h.slowQueryThreshold.Load())
+}
+
+// SetSlowQueryThreshold changes the slow query log threshold at runtime.
+func (h *Handler) SetSlowQueryThreshold(d time.Duration) {
+	h.slowQueryThreshold.Store(int64(d))
+}
+
+// newQueryTracker starts tracking a query execution for query statistics and the slow query log.
+// It is used by `find`, `count`, and `aggregate` commands; write commands are not tracked.
+//
+// Backend query parameters are used to report the pushdown status.
+func (h *Handler) newQueryTracker(key *querystats.Key, qp *backends.QueryParams) *querystats.Tracker {
+	return h.queryStats.NewTracker(key, h.L, func(k *querystats.Key, e *querystats.Execution) {
+		h.logSlowQuery(k, qp, e)
+	})
+}
+
+// logSlowQuery logs a single structured record for the query execution
+// if it took longer than the slow query log threshold.
+func (h *Handler) logSlowQuery(key *querystats.Key, qp *backends.QueryParams, e *querystats.Execution) {
+	threshold := h.SlowQueryThreshold()
+	if threshold <= 0 || e.Duration < threshold {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("ns", key.DB+"."+key.Collection),
+		zap.String("command", key.Command),
+	}
+
+	if key.Shape != nil {
+		// the shape contains only placeholders instead of literal values, so it is safe to log
+		b, err := fjson.Marshal(key.Shape)
+		if err != nil {
+			h.L.Warn("Failed to marshal query shape", zap.Error(err))
+		} else {
+			fields = append(fields, zap.Any("queryShape", json.RawMessage(b)))
+		}
+	}
+
+	fields = append(
+		fields,
+		zap.Int64("durationMillis", e.Duration.Milliseconds()),
+		zap.Int64("docsExamined", e.DocsExamined),
+		zap.Int64("docsReturned", e.DocsReturned),
+		zap.Bool("filterPushdown", qp.Filter.Len() > 0),
+		zap.Bool("sortPushdown", qp.Sort.Len() > 0),
+		zap.Bool("limitPushdown", qp.Limit != 0),
+	)
+
+	h.slowQueryL.Info("Slow query", fields...)
+}
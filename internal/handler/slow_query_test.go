@@ -0,0 +1,106 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/querystats"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestLogSlowQuery(t *testing.T) {
+	t.Parallel()
+
+	filter := must.NotFail(types.NewDocument("user", "alice", "password", "secret"))
+
+	key := &querystats.Key{
+		DB:         "db",
+		Collection: "coll",
+		Command:    "find",
+		Shape:      must.NotFail(types.NewDocument("filter", querystats.Shape(filter))),
+	}
+
+	qp := &backends.QueryParams{Filter: filter, Limit: 1}
+
+	for name, tc := range map[string]struct {
+		threshold time.Duration
+		duration  time.Duration
+		logged    bool
+	}{
+		"Disabled": {
+			duration: time.Second,
+		},
+		"Fast": {
+			threshold: 100 * time.Millisecond,
+			duration:  99 * time.Millisecond,
+		},
+		"Threshold": {
+			threshold: 100 * time.Millisecond,
+			duration:  100 * time.Millisecond,
+			logged:    true,
+		},
+		"Slow": {
+			threshold: 100 * time.Millisecond,
+			duration:  time.Second,
+			logged:    true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			core, logs := observer.New(zapcore.DebugLevel)
+
+			h := &Handler{NewOpts: &NewOpts{L: zap.New(core)}, slowQueryL: zap.New(core)}
+			h.SetSlowQueryThreshold(tc.threshold)
+
+			h.logSlowQuery(key, qp, &querystats.Execution{Duration: tc.duration, DocsExamined: 10, DocsReturned: 1})
+
+			if !tc.logged {
+				assert.Zero(t, logs.Len())
+				return
+			}
+
+			entries := logs.AllUntimed()
+			require.Len(t, entries, 1)
+
+			fields := entries[0].ContextMap()
+			assert.Equal(t, "db.coll", fields["ns"])
+			assert.Equal(t, "find", fields["command"])
+			assert.Equal(t, tc.duration.Milliseconds(), fields["durationMillis"])
+			assert.Equal(t, int64(10), fields["docsExamined"])
+			assert.Equal(t, int64(1), fields["docsReturned"])
+			assert.Equal(t, true, fields["filterPushdown"])
+			assert.Equal(t, false, fields["sortPushdown"])
+			assert.Equal(t, true, fields["limitPushdown"])
+
+			// literal values are redacted
+			shape := fmt.Sprint(fields["queryShape"])
+			assert.Contains(t, shape, `"password"`)
+			assert.NotContains(t, shape, "secret")
+			assert.NotContains(t, shape, "alice")
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package top tracks time spent on operations per collection for the `top` command.
+package top
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Op represents an operation type, as reported by the `top` command.
+type Op string
+
+// Operation types.
+const (
+	Queries  Op = "queries"
+	GetMore  Op = "getmore"
+	Insert   Op = "insert"
+	Update   Op = "update"
+	Remove   Op = "remove"
+	Commands Op = "commands"
+)
+
+// ops contains all operation types in the order of `top` command's output.
+var ops = []Op{Queries, GetMore, Insert, Update, Remove, Commands}
+
+// usage represents the total time and count of operations.
+type usage struct {
+	time  time.Duration
+	count int64
+}
+
+// add records a single operation.
+func (u *usage) add(d time.Duration) {
+	u.time += d
+	u.count++
+}
+
+// document returns a document representation of usage with time in microseconds.
+func (u *usage) document() *types.Document {
+	return must.NotFail(types.NewDocument(
+		"time", u.time.Microseconds(),
+		"count", u.count,
+	))
+}
+
+// collectionUsage represents usage of a single collection.
+type collectionUsage struct {
+	total     usage
+	readLock  usage
+	writeLock usage
+	ops       map[Op]*usage
+}
+
+// Registry stores usage for all collections.
+//
+// It is safe for concurrent use.
+type Registry struct {
+	rw    sync.RWMutex
+	colls map[string]*collectionUsage // keyed by namespace
+}
+
+// NewRegistry creates a new Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		colls: map[string]*collectionUsage{},
+	}
+}
+
+// Record records a single operation of the given type that took d on the given collection.
+// Write operations are also accounted as write lock time, other operations as read lock time.
+func (r *Registry) Record(db, collection string, op Op, write bool, d time.Duration) {
+	ns := db + "." + collection
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	cu := r.colls[ns]
+	if cu == nil {
+		cu = &collectionUsage{
+			ops: make(map[Op]*usage, len(ops)),
+		}
+
+		for _, op := range ops {
+			cu.ops[op] = new(usage)
+		}
+
+		r.colls[ns] = cu
+	}
+
+	cu.total.add(d)
+
+	if write {
+		cu.writeLock.add(d)
+	} else {
+		cu.readLock.add(d)
+	}
+
+	cu.ops[op].add(d)
+}
+
+// RemoveCollection removes usage of the given collection.
+func (r *Registry) RemoveCollection(db, collection string) {
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	delete(r.colls, db+"."+collection)
+}
+
+// RemoveDatabase removes usage of all collections of the given database.
+func (r *Registry) RemoveDatabase(db string) {
+	prefix := db + "."
+
+	r.rw.Lock()
+	defer r.rw.Unlock()
+
+	for ns := range r.colls {
+		if strings.HasPrefix(ns, prefix) {
+			delete(r.colls, ns)
+		}
+	}
+}
+
+// Document returns `top` command's totals document with usage of all collections sorted by namespace.
+func (r *Registry) Document() *types.Document {
+	r.rw.RLock()
+	defer r.rw.RUnlock()
+
+	namespaces := make([]string, 0, len(r.colls))
+	for ns := range r.colls {
+		namespaces = append(namespaces, ns)
+	}
+
+	slices.Sort(namespaces)
+
+	res := must.NotFail(types.NewDocument("note", "all times in microseconds"))
+
+	for _, ns := range namespaces {
+		cu := r.colls[ns]
+
+		doc := must.NotFail(types.NewDocument(
+			"total", cu.total.document(),
+			"readLock", cu.readLock.document(),
+			"writeLock", cu.writeLock.document(),
+		))
+
+		for _, op := range ops {
+			doc.Set(string(op), cu.ops[op].document())
+		}
+
+		res.Set(ns, doc)
+	}
+
+	return res
+}
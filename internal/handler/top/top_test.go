@@ -0,0 +1,68 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package top
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+
+	r.Record("db", "b", Insert, true, 3*time.Microsecond)
+	r.Record("db", "a", Queries, false, 2*time.Microsecond)
+	r.Record("db", "a", GetMore, false, 5*time.Microsecond)
+	r.Record("other", "a", Remove, true, time.Microsecond)
+
+	doc := r.Document()
+	assert.Equal(t, []string{"note", "db.a", "db.b", "other.a"}, doc.Keys())
+
+	a := must.NotFail(doc.Get("db.a")).(*types.Document)
+	assert.Equal(t, []string{
+		"total", "readLock", "writeLock", "queries", "getmore", "insert", "update", "remove", "commands",
+	}, a.Keys())
+
+	expected := map[string][2]int64{
+		"total":     {7, 2},
+		"readLock":  {7, 2},
+		"writeLock": {0, 0},
+		"queries":   {2, 1},
+		"getmore":   {5, 1},
+		"insert":    {0, 0},
+	}
+	for k, e := range expected {
+		u := must.NotFail(a.Get(k)).(*types.Document)
+		assert.Equal(t, e[0], must.NotFail(u.Get("time")), k)
+		assert.Equal(t, e[1], must.NotFail(u.Get("count")), k)
+	}
+
+	r.RemoveCollection("db", "b")
+	assert.Equal(t, []string{"note", "db.a", "other.a"}, r.Document().Keys())
+
+	r.RemoveDatabase("db")
+	assert.Equal(t, []string{"note", "other.a"}, r.Document().Keys())
+
+	r.RemoveDatabase("other")
+	require.Equal(t, []string{"note"}, r.Document().Keys())
+}
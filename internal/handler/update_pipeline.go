@@ -0,0 +1,73 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"fmt"
+
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations"
+	"github.com/FerretDB/FerretDB/internal/handler/common/aggregations/stages"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// updatePipelineStages contains aggregation stages allowed in update pipelines.
+var updatePipelineStages = map[string]struct{}{
+	// sorted alphabetically
+	"$addFields":   {},
+	"$project":     {},
+	"$replaceRoot": {},
+	"$replaceWith": {},
+	"$set":         {},
+	"$unset":       {},
+	// please keep sorted alphabetically
+}
+
+// newUpdatePipeline creates aggregation stages of the update pipeline
+// (update expressed as an array) for the given command.
+func newUpdatePipeline(command string, pipeline *types.Array) ([]aggregations.Stage, error) {
+	values := must.NotFail(iterator.ConsumeValues(pipeline.Iterator()))
+	res := make([]aggregations.Stage, 0, len(values))
+
+	for _, v := range values {
+		d, ok := v.(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				"Each element of the 'pipeline' array must be an object",
+				command,
+			)
+		}
+
+		if _, ok = updatePipelineStages[d.Command()]; !ok && d.Len() == 1 {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrInvalidOptions,
+				fmt.Sprintf("%s is not allowed to be used within an update", d.Command()),
+				command,
+			)
+		}
+
+		s, err := stages.NewStage(d)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, s)
+	}
+
+	return res, nil
+}
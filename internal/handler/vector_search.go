@@ -0,0 +1,678 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/common"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/handler/handlerparams"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+// Search index types.
+const (
+	searchIndexTypeSearch       = "search"
+	searchIndexTypeVectorSearch = "vectorSearch"
+)
+
+// Vector search index field types.
+const (
+	vectorFieldVector = "vector"
+	vectorFieldFilter = "filter"
+)
+
+const (
+	// maxVectorDimensions is the maximal number of vector dimensions, the same as in MongoDB Atlas.
+	maxVectorDimensions = 4096
+
+	// maxVectorNumCandidates is the maximal number of nearest neighbor candidates, the same as in MongoDB Atlas.
+	maxVectorNumCandidates = 10000
+)
+
+// vectorSearchParams represents `$vectorSearch` stage parameters.
+type vectorSearchParams struct {
+	index         string
+	path          string
+	queryVector   []float64
+	numCandidates int64
+	limit         int64
+	filter        *types.Document
+	exact         bool
+}
+
+// parseVectorSearchIndexDefinition parses vector search index definition document
+// in the format accepted by `createSearchIndexes` command.
+//
+// Only `vector` and `filter` fields are supported; quantization options are not.
+func parseVectorSearchIndexDefinition(command string, definition *types.Document) (*backends.SearchIndexInfo, error) {
+	for _, k := range definition.Keys() {
+		if k != "fields" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrNotImplemented,
+				fmt.Sprintf("Vector search index definition field %q is not supported", k),
+				command,
+			)
+		}
+	}
+
+	v, _ := definition.Get("fields")
+
+	fields, ok := v.(*types.Array)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"Vector search index definition must contain 'fields' array",
+			command,
+		)
+	}
+
+	var res backends.SearchIndexInfo
+
+	for i := 0; i < fields.Len(); i++ {
+		field, ok := must.NotFail(fields.Get(i)).(*types.Document)
+		if !ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrTypeMismatch,
+				fmt.Sprintf("Vector search index field definition %d must be an object", i),
+				command,
+			)
+		}
+
+		var typ, path string
+		var vector backends.VectorSearchIndexField
+
+		for _, k := range field.Keys() {
+			v := must.NotFail(field.Get(k))
+
+			switch k {
+			case "type":
+				typ, _ = v.(string)
+
+			case "path":
+				path, _ = v.(string)
+
+			case "numDimensions":
+				dims, err := handlerparams.GetWholeNumberParam(v)
+				if err != nil || dims < 1 || dims > maxVectorDimensions {
+					return nil, handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrBadValue,
+						fmt.Sprintf(
+							"Vector search index field 'numDimensions' must be an integer between 1 and %d, got %s",
+							maxVectorDimensions, types.FormatAnyValue(v),
+						),
+						command,
+					)
+				}
+
+				vector.Dimensions = int(dims)
+
+			case "similarity":
+				similarity, _ := v.(string)
+
+				switch s := backends.VectorSimilarity(similarity); s {
+				case backends.VectorSimilarityCosine, backends.VectorSimilarityEuclidean, backends.VectorSimilarityDotProduct:
+					vector.Similarity = s
+				default:
+					return nil, handlererrors.NewCommandErrorMsgWithArgument(
+						handlererrors.ErrBadValue,
+						fmt.Sprintf(
+							"Vector search index field 'similarity' must be one of: euclidean, cosine, dotProduct; got %s",
+							types.FormatAnyValue(v),
+						),
+						command,
+					)
+				}
+
+			default:
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrNotImplemented,
+					fmt.Sprintf("Vector search index field option %q is not supported", k),
+					command,
+				)
+			}
+		}
+
+		if path == "" {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("Vector search index field definition %d must have a non-empty string 'path'", i),
+				command,
+			)
+		}
+
+		switch typ {
+		case vectorFieldVector:
+			if vector.Dimensions == 0 || vector.Similarity == "" {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("Vector search index field at path %q requires 'numDimensions' and 'similarity'", path),
+					command,
+				)
+			}
+
+			vector.Path = path
+			res.Vectors = append(res.Vectors, vector)
+
+		case vectorFieldFilter:
+			if vector.Dimensions != 0 || vector.Similarity != "" {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("Vector search index filter field at path %q must have only 'type' and 'path'", path),
+					command,
+				)
+			}
+
+			res.Filters = append(res.Filters, path)
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("Vector search index field at path %q must have type 'vector' or 'filter'", path),
+				command,
+			)
+		}
+	}
+
+	if len(res.Vectors) == 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"Vector search index definition must have at least one 'vector' field",
+			command,
+		)
+	}
+
+	return &res, nil
+}
+
+// vectorSearchIndexDefinition returns vector search index definition document for the given index.
+//
+// It is the reverse of parseVectorSearchIndexDefinition.
+func vectorSearchIndexDefinition(index *backends.SearchIndexInfo) *types.Document {
+	fields := types.MakeArray(len(index.Vectors) + len(index.Filters))
+
+	for _, v := range index.Vectors {
+		fields.Append(must.NotFail(types.NewDocument(
+			"type", vectorFieldVector,
+			"path", v.Path,
+			"numDimensions", int32(v.Dimensions),
+			"similarity", string(v.Similarity),
+		)))
+	}
+
+	for _, path := range index.Filters {
+		fields.Append(must.NotFail(types.NewDocument(
+			"type", vectorFieldFilter,
+			"path", path,
+		)))
+	}
+
+	return must.NotFail(types.NewDocument("fields", fields))
+}
+
+// parseVectorSearchStage parses `$vectorSearch` stage document.
+func parseVectorSearchStage(stage *types.Document) (*vectorSearchParams, error) {
+	fields, ok := must.NotFail(stage.Get("$vectorSearch")).(*types.Document)
+	if !ok {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			fmt.Sprintf("$vectorSearch must take a nested object but found: %s", types.FormatAnyValue(stage)),
+			"$vectorSearch (stage)",
+		)
+	}
+
+	var res vectorSearchParams
+
+	for _, k := range fields.Keys() {
+		v := must.NotFail(fields.Get(k))
+
+		var err error
+
+		switch k {
+		case "index", "path":
+			s, ok := v.(string)
+			if !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf("$vectorSearch.%s must be a string, not %s", k, handlerparams.AliasFromType(v)),
+					"$vectorSearch (stage)",
+				)
+			}
+
+			if k == "index" {
+				res.index = s
+			} else {
+				res.path = s
+			}
+
+		case "queryVector":
+			var ok bool
+			if res.queryVector, ok = vectorValue(v); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					"$vectorSearch.queryVector must be a non-empty array of finite numbers",
+					"$vectorSearch (stage)",
+				)
+			}
+
+		case "numCandidates", "limit":
+			var n int64
+			if n, err = handlerparams.GetWholeNumberParam(v); err != nil || n <= 0 {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrBadValue,
+					fmt.Sprintf("$vectorSearch.%s must be a positive integer, got %s", k, types.FormatAnyValue(v)),
+					"$vectorSearch (stage)",
+				)
+			}
+
+			if k == "limit" {
+				res.limit = n
+			} else {
+				res.numCandidates = n
+			}
+
+		case "filter":
+			if res.filter, ok = v.(*types.Document); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf("$vectorSearch.filter must be an object, not %s", handlerparams.AliasFromType(v)),
+					"$vectorSearch (stage)",
+				)
+			}
+
+		case "exact":
+			if res.exact, ok = v.(bool); !ok {
+				return nil, handlererrors.NewCommandErrorMsgWithArgument(
+					handlererrors.ErrTypeMismatch,
+					fmt.Sprintf("$vectorSearch.exact must be a boolean, not %s", handlerparams.AliasFromType(v)),
+					"$vectorSearch (stage)",
+				)
+			}
+
+		default:
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("$vectorSearch.%s is an unknown field", k),
+				"$vectorSearch (stage)",
+			)
+		}
+	}
+
+	for _, missing := range []struct {
+		field string
+		ok    bool
+	}{
+		{"index", res.index != ""},
+		{"path", res.path != ""},
+		{"queryVector", res.queryVector != nil},
+		{"limit", res.limit != 0},
+	} {
+		if !missing.ok {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrFailedToParse,
+				fmt.Sprintf("$vectorSearch.%s is required", missing.field),
+				"$vectorSearch (stage)",
+			)
+		}
+	}
+
+	switch {
+	case res.exact && res.numCandidates != 0:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$vectorSearch.numCandidates is not allowed when exact is true",
+			"$vectorSearch (stage)",
+		)
+
+	case !res.exact && res.numCandidates == 0:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrFailedToParse,
+			"$vectorSearch.numCandidates is required when exact is false",
+			"$vectorSearch (stage)",
+		)
+
+	case !res.exact && res.numCandidates < res.limit:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			"$vectorSearch.numCandidates must be greater than or equal to limit",
+			"$vectorSearch (stage)",
+		)
+
+	case res.numCandidates > maxVectorNumCandidates:
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("$vectorSearch.numCandidates must be less than or equal to %d", maxVectorNumCandidates),
+			"$vectorSearch (stage)",
+		)
+	}
+
+	return &res, nil
+}
+
+// vectorSearchDocuments returns documents matching `$vectorSearch` stage using the given search indexes.
+//
+// Approximate search is performed by the backend if possible; exact search is performed by the handler otherwise.
+// Like in MongoDB Atlas, non-existing index produces no results.
+func vectorSearchDocuments(ctx context.Context, c backends.Collection, closer *iterator.MultiCloser, stage *types.Document, indexes []backends.SearchIndexInfo) (types.DocumentsIterator, error) { //nolint:lll // for readability
+	params, err := parseVectorSearchStage(stage)
+	if err != nil {
+		return nil, err
+	}
+
+	i := slices.IndexFunc(indexes, func(i backends.SearchIndexInfo) bool { return i.Name == params.index && i.Vector() })
+	if i < 0 {
+		return iterator.Values(iterator.ForSlice([]*types.Document{})), nil
+	}
+
+	index := indexes[i]
+
+	j := slices.IndexFunc(index.Vectors, func(v backends.VectorSearchIndexField) bool { return v.Path == params.path })
+	if j < 0 {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf("Path '%s' needs to be indexed as vector", params.path),
+			"$vectorSearch (stage)",
+		)
+	}
+
+	field := index.Vectors[j]
+
+	if len(params.queryVector) != field.Dimensions {
+		return nil, handlererrors.NewCommandErrorMsgWithArgument(
+			handlererrors.ErrBadValue,
+			fmt.Sprintf(
+				"vector field is indexed with %d dimensions but queried with %d",
+				field.Dimensions, len(params.queryVector),
+			),
+			"$vectorSearch (stage)",
+		)
+	}
+
+	for _, path := range vectorSearchFilterPaths(params.filter) {
+		if !slices.Contains(index.Filters, path) {
+			return nil, handlererrors.NewCommandErrorMsgWithArgument(
+				handlererrors.ErrBadValue,
+				fmt.Sprintf("Path '%s' needs to be indexed as filter", path),
+				"$vectorSearch (stage)",
+			)
+		}
+	}
+
+	if !params.exact {
+		var res *backends.VectorSearchResult
+		res, err = c.VectorSearch(ctx, &backends.VectorSearchParams{
+			Index:       index.Name,
+			Path:        params.path,
+			QueryVector: params.queryVector,
+			Filter:      params.filter,
+			Limit:       params.numCandidates,
+		})
+
+		switch {
+		case err == nil:
+			if params.filter == nil {
+				return common.LimitIterator(res.Iter, closer, params.limit), nil
+			}
+
+			var docs []*types.Document
+			var complete bool
+
+			if docs, complete, err = filterVectorSearchCandidates(res.Iter, params); err != nil {
+				return nil, err
+			}
+
+			if complete {
+				return iterator.Values(iterator.ForSlice(docs)), nil
+			}
+
+			// fallback to exact search
+
+		case backends.ErrorCodeIs(err, backends.ErrorCodeNotSupported):
+			// fallback to exact search
+		default:
+			return nil, lazyerrors.Error(err)
+		}
+	}
+
+	docs, err := exactVectorSearch(ctx, c, params, field)
+	if err != nil {
+		return nil, err
+	}
+
+	return iterator.Values(iterator.ForSlice(docs)), nil
+}
+
+// filterVectorSearchCandidates returns up to limit nearest neighbor candidates returned by the backend
+// that match the filter, and closes the iterator.
+//
+// The backend may apply the filter only partially, so candidates that do not match it are returned,
+// and matching documents are not.
+// If that leaves fewer than limit documents while the backend returned numCandidates candidates,
+// false is returned, and exact search should be performed instead.
+func filterVectorSearchCandidates(iter types.DocumentsIterator, params *vectorSearchParams) ([]*types.Document, bool, error) { //nolint:lll // for readability
+	defer iter.Close()
+
+	var docs []*types.Document
+	var candidates int64
+
+	for int64(len(docs)) < params.limit {
+		_, doc, err := iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				return docs, candidates < params.numCandidates, nil
+			}
+
+			return nil, false, lazyerrors.Error(err)
+		}
+
+		candidates++
+
+		matches, err := common.FilterDocument(doc, params.filter)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if matches {
+			docs = append(docs, doc)
+		}
+	}
+
+	return docs, true, nil
+}
+
+// exactVectorSearch scans all collection documents matching the filter,
+// and returns up to limit documents most similar to the query vector.
+func exactVectorSearch(ctx context.Context, c backends.Collection, params *vectorSearchParams, field backends.VectorSearchIndexField) ([]*types.Document, error) { //nolint:lll // for readability
+	queryRes, err := c.Query(ctx, &backends.QueryParams{Filter: params.filter})
+	if err != nil {
+		return nil, lazyerrors.Error(err)
+	}
+
+	defer queryRes.Iter.Close()
+
+	path := must.NotFail(types.NewPathFromString(params.path))
+
+	var docs []*types.Document
+	var scores []float64
+
+	for {
+		_, doc, err := queryRes.Iter.Next()
+		if err != nil {
+			if errors.Is(err, iterator.ErrIteratorDone) {
+				break
+			}
+
+			return nil, lazyerrors.Error(err)
+		}
+
+		if params.filter != nil {
+			var matches bool
+			if matches, err = common.FilterDocument(doc, params.filter); err != nil {
+				return nil, err
+			}
+
+			if !matches {
+				continue
+			}
+		}
+
+		v, _ := doc.GetByPath(path)
+
+		vector, ok := vectorValue(v)
+		if !ok || len(vector) != field.Dimensions {
+			continue
+		}
+
+		docs = append(docs, doc)
+		scores = append(scores, vectorSimilarity(field.Similarity, vector, params.queryVector))
+	}
+
+	sort.Stable(&vectorSearchSorter{docs: docs, scores: scores})
+
+	if int64(len(docs)) > params.limit {
+		docs = docs[:params.limit]
+	}
+
+	return docs, nil
+}
+
+// vectorSearchSorter sorts documents by descending similarity scores.
+type vectorSearchSorter struct {
+	docs   []*types.Document
+	scores []float64
+}
+
+// Len implements sort.Interface.
+func (s *vectorSearchSorter) Len() int {
+	return len(s.docs)
+}
+
+// Less implements sort.Interface.
+func (s *vectorSearchSorter) Less(i, j int) bool {
+	return s.scores[i] > s.scores[j]
+}
+
+// Swap implements sort.Interface.
+func (s *vectorSearchSorter) Swap(i, j int) {
+	s.docs[i], s.docs[j] = s.docs[j], s.docs[i]
+	s.scores[i], s.scores[j] = s.scores[j], s.scores[i]
+}
+
+// vectorSearchFilterPaths returns field paths used by `$vectorSearch` filter,
+// including ones nested in `$and`, `$or` and `$nor` operators.
+func vectorSearchFilterPaths(filter *types.Document) []string {
+	if filter == nil {
+		return nil
+	}
+
+	var res []string
+
+	for _, k := range filter.Keys() {
+		switch k {
+		case "$and", "$or", "$nor":
+			arr, _ := must.NotFail(filter.Get(k)).(*types.Array)
+			if arr == nil {
+				continue
+			}
+
+			for i := 0; i < arr.Len(); i++ {
+				if d, ok := must.NotFail(arr.Get(i)).(*types.Document); ok {
+					res = append(res, vectorSearchFilterPaths(d)...)
+				}
+			}
+
+		default:
+			res = append(res, k)
+		}
+	}
+
+	return res
+}
+
+// vectorValue returns the vector stored in the given array of finite numbers.
+//
+// It returns false if the value is not a non-empty array of finite numbers.
+func vectorValue(v any) ([]float64, bool) {
+	arr, ok := v.(*types.Array)
+	if !ok || arr.Len() == 0 {
+		return nil, false
+	}
+
+	res := make([]float64, arr.Len())
+
+	for i := range res {
+		var f float64
+
+		switch e := must.NotFail(arr.Get(i)).(type) {
+		case float64:
+			f = e
+		case int32:
+			f = float64(e)
+		case int64:
+			f = float64(e)
+		default:
+			return nil, false
+		}
+
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, false
+		}
+
+		res[i] = f
+	}
+
+	return res, true
+}
+
+// vectorSimilarity returns the similarity score of two vectors of the same length
+// normalized to [0, 1] range the same way as in MongoDB Atlas.
+func vectorSimilarity(similarity backends.VectorSimilarity, a, b []float64) float64 {
+	var dot, normA, normB, dist float64
+
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+		dist += (a[i] - b[i]) * (a[i] - b[i])
+	}
+
+	switch similarity {
+	case backends.VectorSimilarityCosine:
+		if normA == 0 || normB == 0 {
+			return 0
+		}
+
+		return (1 + dot/math.Sqrt(normA*normB)) / 2
+
+	case backends.VectorSimilarityEuclidean:
+		return 1 / (1 + dist)
+
+	case backends.VectorSimilarityDotProduct:
+		return (1 + dot) / 2
+
+	default:
+		panic(fmt.Sprintf("unexpected similarity %q", similarity))
+	}
+}
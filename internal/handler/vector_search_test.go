@@ -0,0 +1,248 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/backends"
+	"github.com/FerretDB/FerretDB/internal/handler/handlererrors"
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/iterator"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+func TestVectorSearchIndexDefinition(t *testing.T) {
+	t.Parallel()
+
+	definition := must.NotFail(types.NewDocument(
+		"fields", must.NotFail(types.NewArray(
+			must.NotFail(types.NewDocument(
+				"type", "vector",
+				"path", "v.embedding",
+				"numDimensions", int32(3),
+				"similarity", "cosine",
+			)),
+			must.NotFail(types.NewDocument(
+				"type", "filter",
+				"path", "genre",
+			)),
+		)),
+	))
+
+	index, err := parseVectorSearchIndexDefinition("createSearchIndexes", definition)
+	require.NoError(t, err)
+
+	expected := &backends.SearchIndexInfo{
+		Vectors: []backends.VectorSearchIndexField{
+			{Path: "v.embedding", Dimensions: 3, Similarity: backends.VectorSimilarityCosine},
+		},
+		Filters: []string{"genre"},
+	}
+	assert.Equal(t, expected, index)
+
+	testutil.AssertEqual(t, definition, vectorSearchIndexDefinition(index))
+
+	for name, tc := range map[string]struct {
+		definition *types.Document
+		code       handlererrors.ErrorCode
+	}{
+		"NoFields": {
+			definition: must.NotFail(types.NewDocument()),
+			code:       handlererrors.ErrBadValue,
+		},
+		"OnlyFilter": {
+			definition: must.NotFail(types.NewDocument("fields", must.NotFail(types.NewArray(
+				must.NotFail(types.NewDocument("type", "filter", "path", "genre")),
+			)))),
+			code: handlererrors.ErrBadValue,
+		},
+		"Dimensions": {
+			definition: must.NotFail(types.NewDocument("fields", must.NotFail(types.NewArray(
+				must.NotFail(types.NewDocument("type", "vector", "path", "v", "numDimensions", int32(5000), "similarity", "cosine")),
+			)))),
+			code: handlererrors.ErrBadValue,
+		},
+		"Similarity": {
+			definition: must.NotFail(types.NewDocument("fields", must.NotFail(types.NewArray(
+				must.NotFail(types.NewDocument("type", "vector", "path", "v", "numDimensions", int32(3), "similarity", "l1")),
+			)))),
+			code: handlererrors.ErrBadValue,
+		},
+		"Quantization": {
+			definition: must.NotFail(types.NewDocument("fields", must.NotFail(types.NewArray(
+				must.NotFail(types.NewDocument(
+					"type", "vector", "path", "v", "numDimensions", int32(3), "similarity", "cosine", "quantization", "scalar",
+				)),
+			)))),
+			code: handlererrors.ErrNotImplemented,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseVectorSearchIndexDefinition("createSearchIndexes", tc.definition)
+
+			var ce *handlererrors.CommandError
+			require.ErrorAs(t, err, &ce)
+			assert.Equal(t, tc.code, ce.Code())
+		})
+	}
+}
+
+func TestParseVectorSearchStage(t *testing.T) {
+	t.Parallel()
+
+	stage := must.NotFail(types.NewDocument("$vectorSearch", must.NotFail(types.NewDocument(
+		"index", "vector",
+		"path", "v.embedding",
+		"queryVector", must.NotFail(types.NewArray(int32(1), 0.5, int64(-1))),
+		"numCandidates", int32(100),
+		"limit", int32(10),
+		"filter", must.NotFail(types.NewDocument("genre", "drama")),
+	))))
+
+	params, err := parseVectorSearchStage(stage)
+	require.NoError(t, err)
+
+	expected := &vectorSearchParams{
+		index:         "vector",
+		path:          "v.embedding",
+		queryVector:   []float64{1, 0.5, -1},
+		numCandidates: 100,
+		limit:         10,
+		filter:        must.NotFail(types.NewDocument("genre", "drama")),
+	}
+	assert.Equal(t, expected, params)
+
+	for name, tc := range map[string]struct {
+		stage *types.Document
+		code  handlererrors.ErrorCode
+	}{
+		"NoLimit": {
+			stage: must.NotFail(types.NewDocument(
+				"index", "vector", "path", "v", "queryVector", must.NotFail(types.NewArray(1.0)), "numCandidates", int32(10),
+			)),
+			code: handlererrors.ErrFailedToParse,
+		},
+		"NoNumCandidates": {
+			stage: must.NotFail(types.NewDocument(
+				"index", "vector", "path", "v", "queryVector", must.NotFail(types.NewArray(1.0)), "limit", int32(10),
+			)),
+			code: handlererrors.ErrFailedToParse,
+		},
+		"ExactNumCandidates": {
+			stage: must.NotFail(types.NewDocument(
+				"index", "vector", "path", "v", "queryVector", must.NotFail(types.NewArray(1.0)),
+				"numCandidates", int32(10), "limit", int32(10), "exact", true,
+			)),
+			code: handlererrors.ErrFailedToParse,
+		},
+		"NumCandidatesLessThanLimit": {
+			stage: must.NotFail(types.NewDocument(
+				"index", "vector", "path", "v", "queryVector", must.NotFail(types.NewArray(1.0)),
+				"numCandidates", int32(5), "limit", int32(10),
+			)),
+			code: handlererrors.ErrBadValue,
+		},
+		"QueryVectorString": {
+			stage: must.NotFail(types.NewDocument(
+				"index", "vector", "path", "v", "queryVector", must.NotFail(types.NewArray("a")), "limit", int32(10), "exact", true,
+			)),
+			code: handlererrors.ErrTypeMismatch,
+		},
+	} {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseVectorSearchStage(must.NotFail(types.NewDocument("$vectorSearch", tc.stage)))
+
+			var ce *handlererrors.CommandError
+			require.ErrorAs(t, err, &ce)
+			assert.Equal(t, tc.code, ce.Code())
+		})
+	}
+}
+
+func TestVectorSimilarity(t *testing.T) {
+	t.Parallel()
+
+	a, b := []float64{1, 0}, []float64{0, 1}
+
+	assert.InDelta(t, 1.0, vectorSimilarity(backends.VectorSimilarityCosine, a, a), 1e-9)
+	assert.InDelta(t, 0.5, vectorSimilarity(backends.VectorSimilarityCosine, a, b), 1e-9)
+	assert.InDelta(t, 1.0, vectorSimilarity(backends.VectorSimilarityEuclidean, a, a), 1e-9)
+	assert.InDelta(t, 1.0/3, vectorSimilarity(backends.VectorSimilarityEuclidean, a, b), 1e-9)
+	assert.InDelta(t, 1.0, vectorSimilarity(backends.VectorSimilarityDotProduct, a, a), 1e-9)
+	assert.InDelta(t, 0.5, vectorSimilarity(backends.VectorSimilarityDotProduct, a, b), 1e-9)
+}
+
+func TestFilterVectorSearchCandidates(t *testing.T) {
+	t.Parallel()
+
+	params := &vectorSearchParams{
+		numCandidates: 4,
+		limit:         2,
+		filter:        must.NotFail(types.NewDocument("v", "a")),
+	}
+
+	for name, tc := range map[string]struct {
+		candidates []string // values of v
+		expected   int
+		complete   bool
+	}{
+		"Enough": {
+			candidates: []string{"a", "b", "a", "b"},
+			expected:   2,
+			complete:   true,
+		},
+		"AllCandidates": {
+			candidates: []string{"a", "b", "b"},
+			expected:   1,
+			complete:   true,
+		},
+		"FilteredOut": {
+			candidates: []string{"a", "b", "b", "b"},
+			expected:   1,
+		},
+		"NoMatches": {
+			candidates: []string{"b", "b", "b", "b"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			docs := make([]*types.Document, len(tc.candidates))
+			for i, v := range tc.candidates {
+				docs[i] = must.NotFail(types.NewDocument("_id", int32(i), "v", v))
+			}
+
+			res, complete, err := filterVectorSearchCandidates(iterator.Values(iterator.ForSlice(docs)), params)
+			require.NoError(t, err)
+			assert.Len(t, res, tc.expected)
+			assert.Equal(t, tc.complete, complete)
+
+			for _, doc := range res {
+				assert.Equal(t, "a", must.NotFail(doc.Get("v")))
+			}
+		})
+	}
+}
@@ -58,9 +58,12 @@ import (
 	"time"
 )
 
-// MaxDocumentLen is the maximum BSON object size.
+// MaxDocumentLen is the default maximum BSON object size.
 const MaxDocumentLen = 16 * 1024 * 1024 // 16 MiB = 16777216 bytes
 
+// MaxDocumentLenLimit is the upper bound for the configurable maximum BSON object size.
+const MaxDocumentLenLimit = 256 * 1024 * 1024 // 256 MiB = 268435456 bytes
+
 // MaxSafeDouble is the maximum double value that can be represented precisely.
 const MaxSafeDouble = float64(1<<53 - 1) // 52bit mantissa max value = 9007199254740991
 
@@ -0,0 +1,123 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package coalesce provides a way to execute frequent operations with the same key together.
+package coalesce
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Flush executes all operations of the batch with the given key, storing results in them.
+//
+// The context is the one of the first operation in the batch, but without cancellation.
+type Flush[K comparable, Op any] func(ctx context.Context, key K, ops []Op)
+
+// Coalescer groups operations with the same key that arrive within a time window
+// and executes them together with a single Flush call.
+//
+// Batches with the same key are flushed sequentially, in order.
+type Coalescer[K comparable, Op any] struct {
+	window time.Duration
+	maxOps int
+	flush  Flush[K, Op]
+
+	m       sync.Mutex
+	batches map[K]*batch[Op] // the last batch for each key
+}
+
+// batch represents a group of operations with the same key.
+type batch[Op any] struct {
+	ctx    context.Context
+	ops    []Op
+	closed bool          // true if batch does not accept new operations
+	prev   *batch[Op]    // previous batch with the same key, if any
+	done   chan struct{} // closed when batch is flushed
+}
+
+// New creates a new Coalescer.
+//
+// Batches are flushed when window passes since the first operation, or when they contain maxOps operations.
+func New[K comparable, Op any](window time.Duration, maxOps int, flush Flush[K, Op]) *Coalescer[K, Op] {
+	return &Coalescer[K, Op]{
+		window:  window,
+		maxOps:  maxOps,
+		flush:   flush,
+		batches: map[K]*batch[Op]{},
+	}
+}
+
+// Do adds operation to the batch with the given key and waits until that batch is flushed.
+//
+// Operation is executed even if ctx is canceled while waiting.
+func (c *Coalescer[K, Op]) Do(ctx context.Context, key K, op Op) {
+	c.m.Lock()
+
+	b := c.batches[key]
+	if b == nil || b.closed {
+		b = &batch[Op]{
+			ctx:  context.WithoutCancel(ctx),
+			prev: b,
+			done: make(chan struct{}),
+		}
+		c.batches[key] = b
+
+		time.AfterFunc(c.window, func() { c.run(key, b) })
+	}
+
+	b.ops = append(b.ops, op)
+	full := len(b.ops) >= c.maxOps
+
+	c.m.Unlock()
+
+	if full {
+		c.run(key, b)
+	}
+
+	<-b.done
+}
+
+// run flushes the given batch once, after the previous batch with the same key.
+func (c *Coalescer[K, Op]) run(key K, b *batch[Op]) {
+	c.m.Lock()
+
+	if b.closed {
+		c.m.Unlock()
+		return
+	}
+
+	b.closed = true
+	prev := b.prev
+	b.prev = nil
+
+	c.m.Unlock()
+
+	if prev != nil {
+		<-prev.done
+	}
+
+	c.flush(b.ctx, key, b.ops)
+
+	c.m.Lock()
+
+	if c.batches[key] == b {
+		delete(c.batches, key)
+	}
+
+	c.m.Unlock()
+
+	close(b.done)
+}
@@ -0,0 +1,122 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coalesce
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/util/testutil"
+)
+
+// op is a test operation.
+type op struct {
+	v      int
+	result int
+}
+
+func TestCoalescer(t *testing.T) {
+	t.Parallel()
+
+	var m sync.Mutex
+	batches := map[string][]int{}
+
+	var inFlight atomic.Int32
+
+	c := New(200*time.Millisecond, 1000, func(ctx context.Context, key string, ops []*op) {
+		require.Equal(t, int32(1), inFlight.Add(1), "batches should not be flushed concurrently")
+		defer inFlight.Add(-1)
+
+		require.NoError(t, ctx.Err())
+
+		var sum int
+		for _, o := range ops {
+			sum += o.v
+			o.result = sum
+		}
+
+		m.Lock()
+		batches[key] = append(batches[key], len(ops))
+		m.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+	})
+
+	ctx, cancel := context.WithCancel(testutil.Ctx(t))
+	cancel()
+
+	var wg sync.WaitGroup
+	ops := make([]*op, 10)
+
+	for i := range ops {
+		ops[i] = &op{v: 1}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			// operations are executed even with canceled context
+			c.Do(ctx, "a", ops[i])
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, map[string][]int{"a": {10}}, batches)
+
+	var results []int
+	for _, o := range ops {
+		results = append(results, o.result)
+	}
+
+	assert.ElementsMatch(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, results)
+	assert.Empty(t, c.batches)
+}
+
+func TestCoalescerMaxOps(t *testing.T) {
+	t.Parallel()
+
+	var m sync.Mutex
+	var batches []int
+
+	c := New(time.Hour, 3, func(ctx context.Context, key int, ops []*op) {
+		m.Lock()
+		batches = append(batches, len(ops))
+		m.Unlock()
+	})
+
+	var wg sync.WaitGroup
+
+	for range 6 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			c.Do(testutil.Ctx(t), 42, new(op))
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, []int{3, 3}, batches)
+	assert.Empty(t, c.batches)
+}
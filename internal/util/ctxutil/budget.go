@@ -0,0 +1,75 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctxutil
+
+import (
+	"context"
+	"time"
+)
+
+// requestStartKey is a context key for the request start time.
+type requestStartKey struct{}
+
+// WithRequestStart returns a copy of ctx that stores the time when the request was received.
+//
+// It should be called once per request, as early as possible (right after the wire message was read),
+// so time spent on decoding and routing is subtracted from the request's time budget.
+func WithRequestStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, requestStartKey{}, start)
+}
+
+// RequestStart returns the request start time stored in ctx by WithRequestStart, or zero time.
+func RequestStart(ctx context.Context) time.Time {
+	start, _ := ctx.Value(requestStartKey{}).(time.Time)
+	return start
+}
+
+// Remaining returns the part of the given time budget that is not spent yet.
+// The budget is counted from the request start time stored in ctx, or from now if there is none.
+//
+// The returned value is never negative.
+func Remaining(ctx context.Context, budget time.Duration) time.Duration {
+	start := RequestStart(ctx)
+	if start.IsZero() {
+		return budget
+	}
+
+	return max(budget-time.Since(start), 0)
+}
+
+// WithBudget returns a copy of ctx that is canceled when the given time budget is exhausted.
+// The budget is counted from the request start time stored in ctx, so all stages of the request
+// (decoding, handler, backend queries) share it.
+// If it is already exhausted, the returned context is canceled immediately.
+//
+// Calling stop prevents the budget from canceling the context without canceling it,
+// so it could still be used by a cursor after the command returns.
+// Calling cancel cancels the context and releases resources.
+// Both functions could be called multiple times.
+func WithBudget(ctx context.Context, budget time.Duration) (res context.Context, stop, cancel func()) {
+	res, cancel = context.WithCancel(ctx)
+
+	remaining := Remaining(ctx, budget)
+	if remaining <= 0 {
+		cancel()
+		return res, func() {}, cancel
+	}
+
+	t := time.AfterFunc(remaining, cancel)
+
+	stop = func() { t.Stop() }
+
+	return res, stop, cancel
+}
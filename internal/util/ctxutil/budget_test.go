@@ -0,0 +1,91 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctxutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NoStart", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+
+		assert.True(t, RequestStart(ctx).IsZero())
+		assert.Equal(t, time.Second, Remaining(ctx, time.Second))
+	})
+
+	t.Run("Remaining", func(t *testing.T) {
+		t.Parallel()
+
+		start := time.Now().Add(-300 * time.Millisecond)
+		ctx := WithRequestStart(context.Background(), start)
+
+		assert.Equal(t, start, RequestStart(ctx))
+		assert.LessOrEqual(t, Remaining(ctx, time.Second), 700*time.Millisecond)
+		assert.Zero(t, Remaining(ctx, 100*time.Millisecond))
+	})
+
+	t.Run("Exhausted", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := WithRequestStart(context.Background(), time.Now().Add(-time.Second))
+
+		budgetCtx, stop, cancel := WithBudget(ctx, 500*time.Millisecond)
+		defer cancel()
+		defer stop()
+
+		assert.ErrorIs(t, budgetCtx.Err(), context.Canceled)
+	})
+
+	t.Run("Expires", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := WithRequestStart(context.Background(), time.Now())
+
+		budgetCtx, stop, cancel := WithBudget(ctx, 50*time.Millisecond)
+		defer cancel()
+		defer stop()
+
+		select {
+		case <-budgetCtx.Done():
+		case <-time.After(5 * time.Second):
+			t.Fatal("context was not canceled")
+		}
+	})
+
+	t.Run("Stopped", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := WithRequestStart(context.Background(), time.Now())
+
+		budgetCtx, stop, cancel := WithBudget(ctx, 50*time.Millisecond)
+		defer cancel()
+
+		stop()
+		time.Sleep(200 * time.Millisecond)
+		assert.NoError(t, budgetCtx.Err())
+
+		cancel()
+		assert.ErrorIs(t, budgetCtx.Err(), context.Canceled)
+	})
+}
@@ -38,7 +38,9 @@ import (
 )
 
 // RunHandler runs debug handler.
-func RunHandler(ctx context.Context, addr string, r prometheus.Registerer, l *zap.Logger) {
+//
+// If probes are not nil, /healthz and /readyz handlers are registered too.
+func RunHandler(ctx context.Context, addr string, r prometheus.Registerer, p *Probes, l *zap.Logger) {
 	stdL := must.NotFail(zap.NewStdLogAt(l, zap.WarnLevel))
 
 	http.Handle("/debug/metrics", promhttp.InstrumentMetricHandler(
@@ -66,6 +68,14 @@ func RunHandler(ctx context.Context, addr string, r prometheus.Registerer, l *za
 		"/debug/pprof": "Runtime profiling data for pprof",
 	}
 
+	if p != nil {
+		http.Handle("/healthz", p.handler("healthz", p.liveness))
+		http.Handle("/readyz", p.handler("readyz", p.readiness))
+
+		handlers["/healthz"] = "Liveness probe"
+		handlers["/readyz"] = "Readiness probe"
+	}
+
 	var page bytes.Buffer
 	must.NoError(template.Must(template.New("debug").Parse(`
 	<html>
@@ -0,0 +1,130 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Check is a single health or readiness check.
+// It returns nil if the check passed.
+type Check func(ctx context.Context) error
+
+// Probes contains checks for /healthz and /readyz HTTP handlers.
+//
+// Checks could be set after the debug handler is started.
+// Check that was not set yet fails, so a process that is still starting is not considered healthy or ready.
+type Probes struct {
+	timeout   time.Duration
+	liveness  []string
+	readiness []string
+
+	rw     sync.RWMutex
+	checks map[string]Check
+}
+
+// NewProbesOpts represents probes configuration.
+type NewProbesOpts struct {
+	LivenessChecks  []string      // names of checks for /healthz handler
+	ReadinessChecks []string      // names of checks for /readyz handler
+	Timeout         time.Duration // for all checks of a single request
+}
+
+// NewProbes creates new probes.
+func NewProbes(opts *NewProbesOpts) *Probes {
+	return &Probes{
+		timeout:   opts.Timeout,
+		liveness:  opts.LivenessChecks,
+		readiness: opts.ReadinessChecks,
+		checks:    map[string]Check{},
+	}
+}
+
+// Set sets the check with the given name.
+//
+// Checks that are not used by liveness or readiness probes are ignored.
+func (p *Probes) Set(name string, check Check) {
+	p.rw.Lock()
+	defer p.rw.Unlock()
+
+	p.checks[name] = check
+}
+
+// run runs checks with the given names and writes their results.
+// It returns true if all checks passed.
+func (p *Probes) run(ctx context.Context, names []string, w *strings.Builder) bool {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+
+		defer cancel()
+	}
+
+	ok := true
+
+	for _, name := range names {
+		p.rw.RLock()
+		check := p.checks[name]
+		p.rw.RUnlock()
+
+		var err error
+
+		if check == nil {
+			err = fmt.Errorf("not initialized yet")
+		} else {
+			err = check(ctx)
+		}
+
+		if err != nil {
+			ok = false
+
+			fmt.Fprintf(w, "[-]%s failed: %s\n", name, err)
+
+			continue
+		}
+
+		fmt.Fprintf(w, "[+]%s ok\n", name)
+	}
+
+	return ok
+}
+
+// handler returns HTTP handler that runs checks with the given names.
+//
+// It responds with 200 status code if all checks passed, and with 503 otherwise.
+func (p *Probes) handler(probe string, names []string) http.HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) {
+		var res strings.Builder
+
+		status := http.StatusOK
+
+		if p.run(req.Context(), names, &res) {
+			fmt.Fprintf(&res, "%s check passed\n", probe)
+		} else {
+			status = http.StatusServiceUnavailable
+			fmt.Fprintf(&res, "%s check failed\n", probe)
+		}
+
+		rw.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		rw.Header().Set("Cache-Control", "no-store")
+		rw.WriteHeader(status)
+		rw.Write([]byte(res.String()))
+	}
+}
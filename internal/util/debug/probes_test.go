@@ -0,0 +1,84 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debug
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbes(t *testing.T) {
+	t.Parallel()
+
+	p := NewProbes(&NewProbesOpts{
+		LivenessChecks:  []string{"listener"},
+		ReadinessChecks: []string{"listener", "backend"},
+		Timeout:         time.Second,
+	})
+
+	get := func(t *testing.T, h http.HandlerFunc) (int, string) {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		res := rec.Result()
+		defer res.Body.Close()
+
+		b, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+
+		return res.StatusCode, string(b)
+	}
+
+	healthz := p.handler("healthz", p.liveness)
+	readyz := p.handler("readyz", p.readiness)
+
+	code, body := get(t, readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "[-]listener failed: not initialized yet\n"+
+		"[-]backend failed: not initialized yet\n"+
+		"readyz check failed\n", body)
+
+	p.Set("listener", func(context.Context) error { return nil })
+	p.Set("backend", func(context.Context) error { return errors.New("connection refused") })
+
+	code, body = get(t, healthz)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "[+]listener ok\nhealthz check passed\n", body)
+
+	code, body = get(t, readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "[+]listener ok\n[-]backend failed: connection refused\nreadyz check failed\n", body)
+
+	p.Set("backend", func(ctx context.Context) error {
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+
+		return nil
+	})
+
+	code, body = get(t, readyz)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "[+]listener ok\n[+]backend ok\nreadyz check passed\n", body)
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"errors"
+	"sync"
+)
+
+// concatIterator returns values of multiple iterators one after another.
+type concatIterator[K, V any] struct {
+	m     sync.Mutex
+	iters []Interface[K, V] // remaining iterators
+}
+
+// Concat returns an iterator that returns all values of the first iterator,
+// then all values of the second one, and so on.
+//
+// Close method closes all iterators.
+func Concat[K, V any](iters ...Interface[K, V]) Interface[K, V] {
+	return &concatIterator[K, V]{
+		iters: iters,
+	}
+}
+
+// Next implements iterator.Interface.
+func (iter *concatIterator[K, V]) Next() (K, V, error) {
+	iter.m.Lock()
+	defer iter.m.Unlock()
+
+	for len(iter.iters) > 0 {
+		k, v, err := iter.iters[0].Next()
+		if !errors.Is(err, ErrIteratorDone) {
+			return k, v, err
+		}
+
+		iter.iters[0].Close()
+		iter.iters = iter.iters[1:]
+	}
+
+	var k K
+	var v V
+
+	return k, v, ErrIteratorDone
+}
+
+// Close implements iterator.Interface.
+func (iter *concatIterator[K, V]) Close() {
+	iter.m.Lock()
+	defer iter.m.Unlock()
+
+	for _, i := range iter.iters {
+		i.Close()
+	}
+
+	iter.iters = nil
+}
+
+// check interfaces
+var (
+	_ Interface[any, any] = (*concatIterator[any, any])(nil)
+	_ Closer              = (*concatIterator[any, any])(nil)
+)
@@ -90,3 +90,22 @@ func TestForFunc(t *testing.T) {
 	require.ErrorIs(t, err, ErrIteratorDone)
 	assert.Equal(t, 0, v)
 }
+
+func TestConcat(t *testing.T) {
+	iter := Concat(ForSlice([]int{1, 2}), ForSlice([]int{}), ForSlice([]int{3}))
+
+	actual, err := ConsumeValues(Values(iter))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, actual)
+
+	iter = Concat(ForSlice([]int{1, 2}), ForSlice([]int{3}))
+
+	_, v, err := iter.Next()
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	iter.Close()
+
+	_, _, err = iter.Next()
+	require.ErrorIs(t, err, ErrIteratorDone)
+}
@@ -17,7 +17,9 @@ package logging
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap/zapcore"
@@ -30,11 +32,33 @@ import (
 // and stores the last 1024 entries in circular buffer in memory.
 var RecentEntries = NewCircularBuffer(1024)
 
+// StartupWarnings stores the last 128 entries with warning level or above
+// logged before StartupFinished is called.
+var StartupWarnings = NewCircularBuffer(128)
+
+// startupFinished is set by StartupFinished.
+var startupFinished atomic.Bool
+
+// StartupFinished marks the end of the startup.
+// After that, warnings are no longer stored in StartupWarnings.
+//
+// It is safe to call it multiple times.
+func StartupFinished() {
+	startupFinished.Store(true)
+}
+
+// record represents a single log record stored in circularBuffer.
+type record struct {
+	zapcore.Entry
+	fields map[string]any
+}
+
 // circularBuffer is a storage of log records in memory.
 type circularBuffer struct {
 	mu    sync.RWMutex
-	log   []*zapcore.Entry
+	log   []*record
 	index int64
+	total int64
 }
 
 // NewCircularBuffer creates a circular buffer for log entries in memory.
@@ -44,53 +68,69 @@ func NewCircularBuffer(size int64) *circularBuffer {
 	}
 
 	return &circularBuffer{
-		log: make([]*zapcore.Entry, size),
+		log: make([]*record, size),
 	}
 }
 
-// append adds an entry in circularBuffer.
-func (l *circularBuffer) append(entry *zapcore.Entry) {
+// append adds a record in circularBuffer.
+func (l *circularBuffer) append(r *record) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
-	l.log[l.index] = entry
+	l.log[l.index] = r
 	l.index = (l.index + 1) % int64(len(l.log))
+	l.total++
 }
 
-// get returns entries from circularBuffer with level at minLevel or above.
-func (l *circularBuffer) get(minLevel zapcore.Level) []*zapcore.Entry {
+// get returns records from circularBuffer with level at minLevel or above.
+func (l *circularBuffer) get(minLevel zapcore.Level) []*record {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
 
 	n := len(l.log)
-	entries := make([]*zapcore.Entry, 0, n)
+	records := make([]*record, 0, n)
 	for i := int64(0); i < int64(len(l.log)); i++ {
 		k := (i + l.index) % int64(len(l.log))
 
 		if l.log[k] != nil && l.log[k].Level >= minLevel {
-			entries = append(entries, l.log[k])
+			records = append(records, l.log[k])
 		}
 	}
 
-	return entries
+	return records
+}
+
+// Total returns the total number of records ever added to circularBuffer,
+// including ones that were overwritten.
+func (l *circularBuffer) Total() int64 {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.total
 }
 
 // GetArray is a version of Get that returns an array as expected by mongosh.
 func (l *circularBuffer) GetArray(minLevel zapcore.Level) (*types.Array, error) {
-	entries := l.get(minLevel)
-	res := types.MakeArray(len(entries))
+	records := l.get(minLevel)
+	res := types.MakeArray(len(records))
 
-	for _, e := range entries {
-		b, err := json.Marshal(map[string]any{
+	for _, r := range records {
+		m := map[string]any{
 			"t": map[string]time.Time{
-				"$date": e.Time,
+				"$date": r.Time,
 			},
-			"l":   e.Level,
-			"ln":  e.LoggerName,
-			"msg": e.Message,
-			"c":   e.Caller,
-			"s":   e.Stack,
-		})
+			"l":   r.Level,
+			"ln":  r.LoggerName,
+			"msg": r.Message,
+			"c":   r.Caller,
+			"s":   r.Stack,
+		}
+
+		if len(r.fields) > 0 {
+			m["attr"] = r.fields
+		}
+
+		b, err := json.Marshal(m)
 		if err != nil {
 			return nil, lazyerrors.Error(err)
 		}
@@ -100,3 +140,79 @@ func (l *circularBuffer) GetArray(minLevel zapcore.Level) (*types.Array, error)
 
 	return res, nil
 }
+
+// bufferCore is a zapcore.Core that stores log records with their fields
+// in the given buffers (RecentEntries and StartupWarnings by default).
+type bufferCore struct {
+	zapcore.LevelEnabler
+	recent          *circularBuffer
+	startup         *circularBuffer
+	startupFinished *atomic.Bool
+	fields          []zapcore.Field
+}
+
+// newBufferCore creates a new bufferCore that uses RecentEntries and StartupWarnings.
+func newBufferCore(enab zapcore.LevelEnabler) *bufferCore {
+	return &bufferCore{
+		LevelEnabler:    enab,
+		recent:          RecentEntries,
+		startup:         StartupWarnings,
+		startupFinished: &startupFinished,
+	}
+}
+
+// With implements zapcore.Core.
+func (c *bufferCore) With(fields []zapcore.Field) zapcore.Core {
+	return &bufferCore{
+		LevelEnabler:    c.LevelEnabler,
+		recent:          c.recent,
+		startup:         c.startup,
+		startupFinished: c.startupFinished,
+		fields:          append(slices.Clip(c.fields), fields...),
+	}
+}
+
+// Check implements zapcore.Core.
+func (c *bufferCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(e.Level) {
+		return ce.AddCore(e, c)
+	}
+
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *bufferCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	r := &record{
+		Entry:  e,
+		fields: enc.Fields,
+	}
+
+	c.recent.append(r)
+
+	if e.Level >= zapcore.WarnLevel && !c.startupFinished.Load() {
+		c.startup.append(r)
+	}
+
+	return nil
+}
+
+// Sync implements zapcore.Core.
+func (c *bufferCore) Sync() error {
+	return nil
+}
+
+// check interfaces
+var (
+	_ zapcore.Core = (*bufferCore)(nil)
+)
@@ -16,12 +16,17 @@ package logging
 
 import (
 	"fmt"
+	"io"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+
+	"github.com/FerretDB/FerretDB/internal/util/must"
 )
 
 func TestCircularBuffer(t *testing.T) {
@@ -101,10 +106,10 @@ func TestCircularBuffer(t *testing.T) {
 		name := fmt.Sprintf("AppendGet_%d", n)
 		tc := tc
 		t.Run(name, func(t *testing.T) {
-			logram.append(&tc.inLog)
+			logram.append(&record{Entry: tc.inLog})
 			actual := logram.get(zap.DebugLevel)
 			for i, exp := range tc.expected {
-				assert.Equal(t, exp, *actual[i])
+				assert.Equal(t, exp, actual[i].Entry)
 			}
 		})
 	}
@@ -139,3 +144,48 @@ func TestCircularBuffer(t *testing.T) {
 		})
 	}
 }
+
+func TestBufferCore(t *testing.T) {
+	t.Parallel()
+
+	recent := NewCircularBuffer(16)
+	startup := NewCircularBuffer(16)
+	finished := new(atomic.Bool)
+
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(io.Discard), zap.InfoLevel)
+	logger := zap.New(zapcore.NewTee(core, &bufferCore{
+		LevelEnabler:    core,
+		recent:          recent,
+		startup:         startup,
+		startupFinished: finished,
+	})).With(zap.String("conn", "test"))
+
+	logger.Debug("Not stored")
+	logger.Warn("Warning during startup", zap.Int("n", 42))
+
+	assert.Equal(t, int64(1), recent.Total())
+
+	records := recent.get(zap.DebugLevel)
+	require.Len(t, records, 1)
+	assert.Equal(t, "Warning during startup", records[0].Message)
+	assert.Equal(t, map[string]any{"conn": "test", "n": int64(42)}, records[0].fields)
+
+	warnings := startup.get(zap.WarnLevel)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "Warning during startup", warnings[0].Message)
+
+	finished.Store(true)
+
+	logger.Warn("Warning after startup")
+
+	assert.Equal(t, int64(2), recent.Total())
+
+	warnings = startup.get(zap.WarnLevel)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "Warning during startup", warnings[0].Message)
+
+	arr, err := recent.GetArray(zap.WarnLevel)
+	require.NoError(t, err)
+	require.Equal(t, 2, arr.Len())
+	assert.Contains(t, must.NotFail(arr.Get(0)), `"attr":{"conn":"test","n":42}`)
+}
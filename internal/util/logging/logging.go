@@ -38,12 +38,20 @@ var logLevels = map[zapcore.Level]slog.Level{
 	zapcore.FatalLevel:  slog.LevelError,
 }
 
+// Levels of loggers created by Setup; they could be changed at runtime by SetLevel.
+var (
+	zapLevel  = zap.NewAtomicLevel()
+	slogLevel = new(slog.LevelVar)
+)
+
 // Setup initializes logging with a given level.
 func Setup(level zapcore.Level, encoding, uuid string) {
 	setupSlog(level, encoding)
 
+	zapLevel.SetLevel(level)
+
 	config := zap.Config{
-		Level:             zap.NewAtomicLevelAt(level),
+		Level:             zapLevel,
 		Development:       debugbuild.Enabled,
 		DisableCaller:     false,
 		DisableStacktrace: false,
@@ -83,11 +91,10 @@ func Setup(level zapcore.Level, encoding, uuid string) {
 	SetupWithZapLogger(WithHooks(logger))
 }
 
-// WithHooks returns a logger with recent entries hooks.
+// WithHooks returns a logger that also stores records in RecentEntries and StartupWarnings.
 func WithHooks(logger *zap.Logger) *zap.Logger {
-	return logger.WithOptions(zap.Hooks(func(entry zapcore.Entry) error {
-		RecentEntries.append(&entry)
-		return nil
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, newBufferCore(core))
 	}))
 }
 
@@ -100,10 +107,7 @@ func setupSlog(level zapcore.Level, encoding string) {
 	//
 	// For now, just setup slog in parallel.
 
-	slogLevel, ok := logLevels[level]
-	if !ok {
-		panic(fmt.Sprintf("invalid log level %d", level))
-	}
+	setSlogLevel(level)
 
 	slogOpts := &slog.HandlerOptions{
 		AddSource: false,
@@ -124,6 +128,27 @@ func setupSlog(level zapcore.Level, encoding string) {
 	slog.SetDefault(slog.New(slogHandler))
 }
 
+// setSlogLevel sets slog level that corresponds to the given zap level.
+func setSlogLevel(level zapcore.Level) {
+	l, ok := logLevels[level]
+	if !ok {
+		panic(fmt.Sprintf("invalid log level %d", level))
+	}
+
+	slogLevel.Set(l)
+}
+
+// Level returns the current level of loggers created by Setup.
+func Level() zapcore.Level {
+	return zapLevel.Level()
+}
+
+// SetLevel changes the level of loggers created by Setup at runtime.
+func SetLevel(level zapcore.Level) {
+	setSlogLevel(level)
+	zapLevel.SetLevel(level)
+}
+
 // SetupWithZapLogger initializes zap logging with a given logger and its level.
 func SetupWithZapLogger(logger *zap.Logger) {
 	zap.ReplaceGlobals(logger)
@@ -0,0 +1,106 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/exp/maps"
+)
+
+// ZapFromSlog returns a zap logger that writes all records to the given slog logger.
+//
+// Levels are checked by the slog logger's handler.
+func ZapFromSlog(l *slog.Logger) *zap.Logger {
+	return zap.New(&slogCore{h: l.Handler()}, zap.AddCaller())
+}
+
+// slogCore is a zapcore.Core that writes log records to slog.Handler.
+type slogCore struct {
+	h slog.Handler
+}
+
+// Enabled implements zapcore.LevelEnabler.
+func (c *slogCore) Enabled(level zapcore.Level) bool {
+	return c.h.Enabled(context.Background(), logLevels[level])
+}
+
+// With implements zapcore.Core.
+func (c *slogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &slogCore{
+		h: c.h.WithAttrs(slogAttrs(fields)),
+	}
+}
+
+// Check implements zapcore.Core.
+func (c *slogCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(e.Level) {
+		return ce.AddCore(e, c)
+	}
+
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (c *slogCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	var pc uintptr
+	if e.Caller.Defined {
+		pc = e.Caller.PC
+	}
+
+	r := slog.NewRecord(e.Time, logLevels[e.Level], e.Message, pc)
+
+	if e.LoggerName != "" {
+		r.AddAttrs(slog.String("name", e.LoggerName))
+	}
+
+	r.AddAttrs(slogAttrs(fields)...)
+
+	return c.h.Handle(context.Background(), r)
+}
+
+// Sync implements zapcore.Core.
+func (c *slogCore) Sync() error {
+	return nil
+}
+
+// slogAttrs converts zap fields to slog attributes sorted by key.
+func slogAttrs(fields []zapcore.Field) []slog.Attr {
+	enc := zapcore.NewMapObjectEncoder()
+
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	keys := maps.Keys(enc.Fields)
+	slices.Sort(keys)
+
+	res := make([]slog.Attr, len(keys))
+
+	for i, k := range keys {
+		res[i] = slog.Any(k, enc.Fields[k])
+	}
+
+	return res
+}
+
+// check interfaces
+var (
+	_ zapcore.Core = (*slogCore)(nil)
+)
@@ -0,0 +1,52 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestZapFromSlog(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})
+
+	l := ZapFromSlog(slog.New(h)).Named("test").With(zap.String("conn", "c1"))
+
+	l.Debug("debug message")
+	l.Info("info message", zap.Int("n", 42))
+
+	var actual map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &actual))
+
+	delete(actual, "time")
+
+	expected := map[string]any{
+		"level": "INFO",
+		"msg":   "info message",
+		"name":  "test",
+		"conn":  "c1",
+		"n":     float64(42),
+	}
+	assert.Equal(t, expected, actual)
+}
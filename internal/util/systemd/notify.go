@@ -0,0 +1,50 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemd
+
+import (
+	"net"
+	"os"
+)
+
+// NotifyEnabled returns true if the service manager expects notifications sent by [Notify].
+func NotifyEnabled() bool {
+	return os.Getenv("NOTIFY_SOCKET") != ""
+}
+
+// Notify sends the given state (for example, "READY=1") to the service manager (see sd_notify(3)).
+//
+// It returns false without error if NOTIFY_SOCKET environment variable is not set,
+// for example, when the process is not started by systemd or the service type is not "notify".
+func Notify(state string) (bool, error) {
+	// leading @ for the abstract namespace is handled by the net package
+	path := os.Getenv("NOTIFY_SOCKET")
+	if path == "" {
+		return false, nil
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		return true, err
+	}
+
+	defer conn.Close()
+
+	if _, err = conn.Write([]byte(state)); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package systemd provides systemd integration.
+package systemd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+)
+
+// listenFDsStart is the first file descriptor passed by systemd socket activation.
+const listenFDsStart = 3
+
+// Listeners returns listeners for sockets passed by systemd socket activation
+// (see sd_listen_fds(3)), or nil if there are none.
+//
+// LISTEN_* environment variables are unset, so sockets are not passed to child processes.
+func Listeners() ([]net.Listener, error) {
+	return listeners(listenFDsStart)
+}
+
+// listeners implements Listeners with the given first file descriptor.
+func listeners(start int) ([]net.Listener, error) {
+	pidS, fdsS := os.Getenv("LISTEN_PID"), os.Getenv("LISTEN_FDS")
+
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	if pidS == "" || fdsS == "" {
+		return nil, nil
+	}
+
+	pid, err := strconv.Atoi(pidS)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LISTEN_PID %q: %w", pidS, err)
+	}
+
+	// sockets are passed to another process
+	if pid != os.Getpid() {
+		return nil, nil
+	}
+
+	n, err := strconv.Atoi(fdsS)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid LISTEN_FDS %q", fdsS)
+	}
+
+	res := make([]net.Listener, 0, n)
+
+	for fd := start; fd < start+n; fd++ {
+		f := os.NewFile(uintptr(fd), "LISTEN_FD_"+strconv.Itoa(fd))
+
+		// the file descriptor is duplicated, so the original is closed below
+		l, err := net.FileListener(f)
+		f.Close()
+
+		if err != nil {
+			for _, l := range res {
+				l.Close()
+			}
+
+			return nil, errors.Join(fmt.Errorf("file descriptor %d is not a listening socket", fd), err)
+		}
+
+		res = append(res, l)
+	}
+
+	return res, nil
+}
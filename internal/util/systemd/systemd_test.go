@@ -0,0 +1,118 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListeners(t *testing.T) {
+	t.Run("None", func(t *testing.T) {
+		t.Setenv("LISTEN_PID", "")
+		t.Setenv("LISTEN_FDS", "")
+
+		res, err := Listeners()
+		require.NoError(t, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("OtherProcess", func(t *testing.T) {
+		t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+		t.Setenv("LISTEN_FDS", "1")
+
+		res, err := Listeners()
+		require.NoError(t, err)
+		assert.Nil(t, res)
+		assert.Empty(t, os.Getenv("LISTEN_FDS"))
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		t.Setenv("LISTEN_FDS", "many")
+
+		_, err := Listeners()
+		require.Error(t, err)
+	})
+
+	t.Run("Socket", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		t.Cleanup(func() { l.Close() })
+
+		f, err := l.(*net.TCPListener).File()
+		require.NoError(t, err)
+
+		// listeners takes ownership of the file descriptor
+		fd, err := syscall.Dup(int(f.Fd()))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()))
+		t.Setenv("LISTEN_FDS", "1")
+
+		res, err := listeners(fd)
+		require.NoError(t, err)
+		require.Len(t, res, 1)
+
+		t.Cleanup(func() { res[0].Close() })
+
+		assert.Equal(t, l.Addr().String(), res[0].Addr().String())
+
+		conn, err := net.Dial("tcp", res[0].Addr().String())
+		require.NoError(t, err)
+		conn.Close()
+	})
+}
+
+func TestNotify(t *testing.T) {
+	t.Run("NoSocket", func(t *testing.T) {
+		t.Setenv("NOTIFY_SOCKET", "")
+
+		sent, err := Notify("READY=1")
+		require.NoError(t, err)
+		assert.False(t, sent)
+	})
+
+	t.Run("Socket", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notify.sock")
+
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+		require.NoError(t, err)
+
+		t.Cleanup(func() { conn.Close() })
+
+		t.Setenv("NOTIFY_SOCKET", path)
+
+		sent, err := Notify("READY=1")
+		require.NoError(t, err)
+		assert.True(t, sent)
+
+		b := make([]byte, 64)
+		n, err := conn.Read(b)
+		require.NoError(t, err)
+		assert.Equal(t, "READY=1", string(b[:n]))
+	})
+}
@@ -55,6 +55,19 @@ func (s *Flag) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// String returns a string representation of the flag value.
+func (s Flag) String() string {
+	if s.v == nil {
+		return "undecided"
+	}
+
+	if *s.v {
+		return "enable"
+	}
+
+	return "disable"
+}
+
 // initialState returns initial telemetry state based on:
 //   - Kong flag value (including `FERRETDB_TELEMETRY` environment variable);
 //   - common DO_NOT_TRACK environment variable;
@@ -16,6 +16,7 @@ package wire
 
 import (
 	"bufio"
+	"bytes"
 	"encoding"
 	"encoding/binary"
 	"errors"
@@ -23,6 +24,7 @@ import (
 	"hash/crc32"
 	"io"
 
+	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/lazyerrors"
 )
 
@@ -44,15 +46,39 @@ type MsgBody interface {
 var ErrZeroRead = errors.New("zero bytes read")
 
 // ReadMessage reads from reader and returns wire header and body.
+// Default limits [MaxMsgLen] and [types.MaxDocumentLen] are used.
 //
 // Error is (possibly wrapped) ErrZeroRead if zero bytes was read.
 func ReadMessage(r *bufio.Reader) (*MsgHeader, MsgBody, error) {
+	return ReadMessageLimits(r, MaxMsgLen, types.MaxDocumentLen)
+}
+
+// ReadMessageLimits is a variant of [ReadMessage] with given limits.
+//
+// Messages longer than maxMsgLen are rejected with a generic error.
+// For OP_MSG messages, documents of document sequences longer than maxDocLen
+// and the body document longer than maxDocLen plus [DocumentLenOverhead]
+// are rejected with (possibly wrapped) *DocumentTooLargeError that is returned together with the header.
+//
+// Error is (possibly wrapped) ErrZeroRead if zero bytes was read.
+func ReadMessageLimits(r *bufio.Reader, maxMsgLen, maxDocLen int32) (*MsgHeader, MsgBody, error) {
 	var header MsgHeader
-	if err := header.readFrom(r); err != nil {
+	if err := header.readFrom(r, maxMsgLen); err != nil {
 		return nil, nil, lazyerrors.Error(err)
 	}
 
-	b := make([]byte, header.MessageLength-MsgHeaderLen)
+	var b []byte
+
+	// OP_MSG decoding copies all data, and OP_REPLY document is copied below, so the buffer could be reused;
+	// other messages keep references to it.
+	switch header.OpCode { //nolint:exhaustive // other opcodes are not pooled
+	case OpCodeMsg, OpCodeReply:
+		b = getBuffer(int(header.MessageLength - MsgHeaderLen))
+		defer putBuffer(b)
+	default:
+		b = make([]byte, header.MessageLength-MsgHeaderLen)
+	}
+
 	if n, err := io.ReadFull(r, b); err != nil {
 		return nil, nil, lazyerrors.Errorf("expected %d, read %d: %w", len(b), n, err)
 	}
@@ -64,6 +90,8 @@ func ReadMessage(r *bufio.Reader) (*MsgHeader, MsgBody, error) {
 			return nil, nil, lazyerrors.Error(err)
 		}
 
+		reply.document = bytes.Clone(reply.document)
+
 		return &header, &reply, nil
 
 	case OpCodeMsg:
@@ -71,8 +99,12 @@ func ReadMessage(r *bufio.Reader) (*MsgHeader, MsgBody, error) {
 			return &header, nil, lazyerrors.Error(err)
 		}
 
+		if err := checkOpMsgDocumentsLen(b, maxDocLen); err != nil {
+			return &header, nil, lazyerrors.Error(err)
+		}
+
 		var msg OpMsg
-		if err := msg.UnmarshalBinaryNocopy(b); err != nil {
+		if err := msg.unmarshalBinaryLimit(b, maxDocLen); err != nil {
 			return &header, nil, lazyerrors.Error(err)
 		}
 
@@ -108,9 +140,35 @@ func ReadMessage(r *bufio.Reader) (*MsgHeader, MsgBody, error) {
 
 // WriteMessage validates msg and headers and writes them to the writer.
 func WriteMessage(w *bufio.Writer, header *MsgHeader, msg MsgBody) error {
-	b, err := msg.MarshalBinary()
-	if err != nil {
-		return lazyerrors.Error(err)
+	var b []byte
+
+	switch msg := msg.(type) {
+	case *OpMsg:
+		// encode into the pooled buffer of the expected size
+		buf := newBuffer(int(header.MessageLength - MsgHeaderLen))
+		defer buf.release()
+
+		if err := msg.writeTo(buf); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		b = buf.b
+
+	case *OpReply:
+		buf := newBuffer(int(header.MessageLength - MsgHeaderLen))
+		defer buf.release()
+
+		if err := msg.writeTo(buf); err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		b = buf.b
+
+	default:
+		var err error
+		if b, err = msg.MarshalBinary(); err != nil {
+			return lazyerrors.Error(err)
+		}
 	}
 
 	if expected := len(b) + MsgHeaderLen; int32(expected) != header.MessageLength {
@@ -75,14 +75,17 @@ const (
 	// MsgHeaderLen is an expected len of the header.
 	MsgHeaderLen = 16
 
-	// MaxMsgLen is the maximum message length.
+	// MaxMsgLen is the default maximum message length.
 	MaxMsgLen = 48000000
+
+	// MaxMsgLenLimit is the upper bound for the configurable maximum message length.
+	MaxMsgLenLimit = 1024 * 1024 * 1024
 )
 
-// readFrom reads header.
+// readFrom reads header, checking that message length does not exceed maxLen.
 //
 // Error is ErrZeroRead if zero bytes was read.
-func (msg *MsgHeader) readFrom(r *bufio.Reader) error {
+func (msg *MsgHeader) readFrom(r *bufio.Reader, maxLen int32) error {
 	b := make([]byte, MsgHeaderLen)
 	if n, err := io.ReadFull(r, b); err != nil {
 		if err == io.EOF {
@@ -96,7 +99,7 @@ func (msg *MsgHeader) readFrom(r *bufio.Reader) error {
 	msg.ResponseTo = int32(binary.LittleEndian.Uint32(b[8:12]))
 	msg.OpCode = OpCode(binary.LittleEndian.Uint32(b[12:16]))
 
-	if msg.MessageLength < MsgHeaderLen || msg.MessageLength > MaxMsgLen {
+	if msg.MessageLength < MsgHeaderLen || msg.MessageLength > maxLen {
 		return lazyerrors.Errorf("invalid message length %d", msg.MessageLength)
 	}
 
@@ -104,12 +107,14 @@ func (msg *MsgHeader) readFrom(r *bufio.Reader) error {
 }
 
 func (msg *MsgHeader) writeTo(w *bufio.Writer) error {
-	v, err := msg.MarshalBinary()
-	if err != nil {
-		return lazyerrors.Error(err)
-	}
+	var b [MsgHeaderLen]byte
+
+	binary.LittleEndian.PutUint32(b[0:4], uint32(msg.MessageLength))
+	binary.LittleEndian.PutUint32(b[4:8], uint32(msg.RequestID))
+	binary.LittleEndian.PutUint32(b[8:12], uint32(msg.ResponseTo))
+	binary.LittleEndian.PutUint32(b[12:16], uint32(msg.OpCode))
 
-	if _, err := w.Write(v); err != nil {
+	if _, err := w.Write(b[:]); err != nil {
 		return lazyerrors.Error(err)
 	}
 
@@ -166,6 +166,12 @@ func (msg *OpMsg) msgbody() {}
 
 // UnmarshalBinaryNocopy implements [MsgBody] interface.
 func (msg *OpMsg) UnmarshalBinaryNocopy(b []byte) error {
+	return msg.unmarshalBinaryLimit(b, types.MaxDocumentLenLimit)
+}
+
+// unmarshalBinaryLimit is a variant of [OpMsg.UnmarshalBinaryNocopy]
+// that rejects documents longer than maxDocLen and the body document longer than maxDocLen plus [DocumentLenOverhead].
+func (msg *OpMsg) unmarshalBinaryLimit(b []byte, maxDocLen int32) error {
 	br := bytes.NewReader(b)
 	bufr := bufio.NewReader(br)
 
@@ -182,7 +188,7 @@ func (msg *OpMsg) UnmarshalBinaryNocopy(b []byte) error {
 		switch section.Kind {
 		case 0:
 			var doc bson.Document
-			if err := doc.ReadFrom(bufr); err != nil {
+			if err := doc.ReadFromLimit(bufr, maxDocLen+DocumentLenOverhead); err != nil {
 				return lazyerrors.Error(err)
 			}
 
@@ -221,7 +227,7 @@ func (msg *OpMsg) UnmarshalBinaryNocopy(b []byte) error {
 				}
 
 				var doc bson.Document
-				if err := doc.ReadFrom(secr); err != nil {
+				if err := doc.ReadFromLimit(secr, maxDocLen); err != nil {
 					return lazyerrors.Error(err)
 				}
 
@@ -266,18 +272,107 @@ func (msg *OpMsg) UnmarshalBinaryNocopy(b []byte) error {
 	return nil
 }
 
+// DocumentLenOverhead is the extra length allowed for the OP_MSG body document
+// on top of the maximum BSON object size, so commands could carry documents of the maximum size.
+const DocumentLenOverhead = 16 * 1024 // 16 KiB, as in MongoDB
+
+// checkOpMsgDocumentsLen checks lengths of OP_MSG documents without decoding them.
+// The body document may be up to maxLen plus [DocumentLenOverhead] bytes long,
+// documents of document sequences may be up to maxLen bytes long.
+//
+// Malformed messages are not reported there; UnmarshalBinaryNocopy does that.
+func checkOpMsgDocumentsLen(b []byte, maxLen int32) error {
+	if len(b) < 4 {
+		return nil
+	}
+
+	end := len(b)
+	if OpMsgFlags(binary.LittleEndian.Uint32(b)).FlagSet(OpMsgChecksumPresent) {
+		end -= 4
+	}
+
+	for i := 4; i < end; {
+		kind := b[i]
+		i++
+
+		if i+4 > end {
+			return nil
+		}
+
+		l := int32(binary.LittleEndian.Uint32(b[i:]))
+
+		switch kind {
+		case 0:
+			if maxBodyLen := maxLen + DocumentLenOverhead; l > maxBodyLen {
+				return &DocumentTooLargeError{Len: l, MaxLen: maxBodyLen}
+			}
+
+		case 1:
+			if l < 5 || i+int(l) > end {
+				return nil
+			}
+
+			sec := b[i+4 : i+int(l)]
+
+			j := bytes.IndexByte(sec, 0)
+			if j < 0 {
+				return nil
+			}
+
+			for j++; j+4 <= len(sec); {
+				docLen := int32(binary.LittleEndian.Uint32(sec[j:]))
+				if docLen > maxLen {
+					return &DocumentTooLargeError{Len: docLen, MaxLen: maxLen}
+				}
+
+				if docLen < 5 {
+					return nil
+				}
+
+				j += int(docLen)
+			}
+
+		default:
+			return nil
+		}
+
+		if l < 5 {
+			return nil
+		}
+
+		i += int(l)
+	}
+
+	return nil
+}
+
 // MarshalBinary writes an OpMsg to a byte array.
 func (msg *OpMsg) MarshalBinary() ([]byte, error) {
-	var buf bytes.Buffer
-	bufw := bufio.NewWriter(&buf)
+	buf := newBuffer(0)
+	defer buf.release()
 
-	if err := binary.Write(bufw, binary.LittleEndian, msg.FlagBits); err != nil {
+	if err := msg.writeTo(buf); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
+	return bytes.Clone(buf.b), nil
+}
+
+// writeTo appends an encoded OpMsg to the given buffer.
+func (msg *OpMsg) writeTo(buf *buffer) error {
+	bufw := getBufioWriter(buf)
+	defer putBufioWriter(bufw)
+
+	var flags [flagsSize]byte
+	binary.LittleEndian.PutUint32(flags[:], uint32(msg.FlagBits))
+
+	if _, err := bufw.Write(flags[:]); err != nil {
+		return lazyerrors.Error(err)
+	}
+
 	for _, section := range msg.sections {
 		if err := bufw.WriteByte(section.Kind); err != nil {
-			return nil, lazyerrors.Error(err)
+			return lazyerrors.Error(err)
 		}
 
 		switch section.Kind {
@@ -288,45 +383,20 @@ func (msg *OpMsg) MarshalBinary() ([]byte, error) {
 
 			d, err := bson.ConvertDocument(section.documents[0])
 			if err != nil {
-				return nil, lazyerrors.Error(err)
+				return lazyerrors.Error(err)
 			}
 
 			if err := d.WriteTo(bufw); err != nil {
-				return nil, lazyerrors.Error(err)
+				return lazyerrors.Error(err)
 			}
 
 		case 1:
-			var secBuf bytes.Buffer
-			secw := bufio.NewWriter(&secBuf)
-
-			if err := bson.CString(section.Identifier).WriteTo(secw); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-			for _, doc := range section.documents {
-				d, err := bson.ConvertDocument(doc)
-				if err != nil {
-					return nil, lazyerrors.Error(err)
-				}
-
-				if err := d.WriteTo(secw); err != nil {
-					return nil, lazyerrors.Error(err)
-				}
-			}
-
-			if err := secw.Flush(); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-
-			if err := binary.Write(bufw, binary.LittleEndian, int32(secBuf.Len()+4)); err != nil {
-				return nil, lazyerrors.Error(err)
-			}
-			if _, err := bufw.Write(secBuf.Bytes()); err != nil {
-				return nil, lazyerrors.Error(err)
+			if err := writeSection1(bufw, &section); err != nil {
+				return lazyerrors.Error(err)
 			}
 
 		default:
-			return nil, lazyerrors.Errorf("kind is %d", section.Kind)
+			return lazyerrors.Errorf("kind is %d", section.Kind)
 		}
 	}
 
@@ -334,15 +404,53 @@ func (msg *OpMsg) MarshalBinary() ([]byte, error) {
 		// Calculate checksum before writing it. It needs header data to be ready and available here.
 		// TODO https://github.com/FerretDB/FerretDB/issues/2690
 		if err := binary.Write(bufw, binary.LittleEndian, msg.checksum); err != nil {
-			return nil, lazyerrors.Error(err)
+			return lazyerrors.Error(err)
 		}
 	}
 
 	if err := bufw.Flush(); err != nil {
-		return nil, lazyerrors.Error(err)
+		return lazyerrors.Error(err)
+	}
+
+	return nil
+}
+
+// writeSection1 writes the size, identifier, and documents of the section of kind 1.
+func writeSection1(bufw *bufio.Writer, section *OpMsgSection) error {
+	secBuf := newBuffer(0)
+	defer secBuf.release()
+
+	secw := getBufioWriter(secBuf)
+	defer putBufioWriter(secw)
+
+	if err := bson.CString(section.Identifier).WriteTo(secw); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	for _, doc := range section.documents {
+		d, err := bson.ConvertDocument(doc)
+		if err != nil {
+			return lazyerrors.Error(err)
+		}
+
+		if err := d.WriteTo(secw); err != nil {
+			return lazyerrors.Error(err)
+		}
+	}
+
+	if err := secw.Flush(); err != nil {
+		return lazyerrors.Error(err)
 	}
 
-	return buf.Bytes(), nil
+	if err := binary.Write(bufw, binary.LittleEndian, int32(len(secBuf.b)+4)); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	if _, err := bufw.Write(secBuf.b); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	return nil
 }
 
 // String returns a string representation for logging.
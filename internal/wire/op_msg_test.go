@@ -15,9 +15,16 @@
 package wire
 
 import (
+	"bufio"
+	"bytes"
+	"errors"
 	"math"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
 	"github.com/FerretDB/FerretDB/internal/types"
 	"github.com/FerretDB/FerretDB/internal/util/must"
 	"github.com/FerretDB/FerretDB/internal/util/testutil"
@@ -481,3 +488,84 @@ func TestMsg(t *testing.T) {
 func FuzzMsg(f *testing.F) {
 	fuzzMessages(f, msgTestCases)
 }
+
+func TestReadMessageLimits(t *testing.T) {
+	t.Parallel()
+
+	marshal := func(t *testing.T, body *types.Document, docs ...*types.Document) []byte {
+		t.Helper()
+
+		msg := &OpMsg{
+			sections: []OpMsgSection{
+				MakeOpMsgSection(body),
+				{Kind: 1, Identifier: "documents", documents: docs},
+			},
+		}
+
+		b, err := msg.MarshalBinary()
+		require.NoError(t, err)
+
+		header := &MsgHeader{
+			MessageLength: int32(MsgHeaderLen + len(b)),
+			RequestID:     1,
+			OpCode:        OpCodeMsg,
+		}
+
+		var buf bytes.Buffer
+		bufw := bufio.NewWriter(&buf)
+		require.NoError(t, WriteMessage(bufw, header, msg))
+		require.NoError(t, bufw.Flush())
+
+		return buf.Bytes()
+	}
+
+	read := func(b []byte, maxMsgLen, maxDocLen int32) error {
+		_, _, err := ReadMessageLimits(bufio.NewReader(bytes.NewReader(b)), maxMsgLen, maxDocLen)
+		return err
+	}
+
+	body := must.NotFail(types.NewDocument("insert", "values", "$db", "test"))
+	doc := must.NotFail(types.NewDocument("v", strings.Repeat("x", 100)))
+	b := marshal(t, body, doc)
+
+	t.Run("Default", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, read(b, MaxMsgLen, types.MaxDocumentLen))
+	})
+
+	t.Run("MessageTooLarge", func(t *testing.T) {
+		t.Parallel()
+
+		err := read(b, int32(len(b)-1), types.MaxDocumentLen)
+		require.Error(t, err)
+
+		var tooLargeErr *DocumentTooLargeError
+		assert.False(t, errors.As(err, &tooLargeErr))
+	})
+
+	t.Run("SequenceDocumentTooLarge", func(t *testing.T) {
+		t.Parallel()
+
+		err := read(b, MaxMsgLen, 64)
+
+		var tooLargeErr *DocumentTooLargeError
+		require.ErrorAs(t, err, &tooLargeErr)
+		assert.Equal(t, int32(64), tooLargeErr.MaxLen)
+		assert.Greater(t, tooLargeErr.Len, int32(100))
+	})
+
+	t.Run("BodyOverhead", func(t *testing.T) {
+		t.Parallel()
+
+		bigBody := must.NotFail(types.NewDocument("insert", strings.Repeat("x", 1024), "$db", "test"))
+		require.NoError(t, read(marshal(t, bigBody, doc), MaxMsgLen, 512))
+
+		bigBody = must.NotFail(types.NewDocument("insert", strings.Repeat("x", DocumentLenOverhead+1024), "$db", "test"))
+		err := read(marshal(t, bigBody, doc), MaxMsgLen, 512)
+
+		var tooLargeErr *DocumentTooLargeError
+		require.ErrorAs(t, err, &tooLargeErr)
+		assert.Equal(t, int32(512+DocumentLenOverhead), tooLargeErr.MaxLen)
+	})
+}
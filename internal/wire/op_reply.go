@@ -15,6 +15,7 @@
 package wire
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/json"
 
@@ -86,24 +87,36 @@ func (reply *OpReply) UnmarshalBinaryNocopy(b []byte) error {
 
 // MarshalBinary implements [MsgBody] interface.
 func (reply *OpReply) MarshalBinary() ([]byte, error) {
-	if err := reply.check(); err != nil {
+	buf := newBuffer(20 + len(reply.document))
+	defer buf.release()
+
+	if err := reply.writeTo(buf); err != nil {
 		return nil, lazyerrors.Error(err)
 	}
 
-	b := make([]byte, 20+len(reply.document))
+	return bytes.Clone(buf.b), nil
+}
+
+// writeTo appends an encoded OpReply to the given buffer.
+func (reply *OpReply) writeTo(buf *buffer) error {
+	if err := reply.check(); err != nil {
+		return lazyerrors.Error(err)
+	}
+
+	var b [20]byte
 
 	binary.LittleEndian.PutUint32(b[0:4], uint32(reply.ResponseFlags))
 	binary.LittleEndian.PutUint64(b[4:12], uint64(reply.CursorID))
 	binary.LittleEndian.PutUint32(b[12:16], uint32(reply.StartingFrom))
 
-	if reply.document == nil {
-		binary.LittleEndian.PutUint32(b[16:20], uint32(0))
-	} else {
+	if reply.document != nil {
 		binary.LittleEndian.PutUint32(b[16:20], uint32(1))
-		copy(b[20:], reply.document)
 	}
 
-	return b, nil
+	must.NotFail(buf.Write(b[:]))
+	must.NotFail(buf.Write(reply.document))
+
+	return nil
 }
 
 // Document returns reply document.
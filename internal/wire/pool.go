@@ -0,0 +1,125 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"bufio"
+	"math/bits"
+	"sync"
+)
+
+// Pooled byte slices have capacities of powers of two
+// between 1<<minBufferClass and 1<<maxBufferClass bytes.
+// Larger slices are allocated and garbage-collected as usual.
+const (
+	minBufferClass = 10 // 1 KiB
+	maxBufferClass = 24 // 16 MiB
+)
+
+// bufferPools contains pools of *[]byte for each size class.
+var bufferPools [maxBufferClass - minBufferClass + 1]sync.Pool
+
+// bufioWriterPool contains *bufio.Writer instances used for encoding.
+var bufioWriterPool = sync.Pool{
+	New: func() any {
+		return bufio.NewWriter(nil)
+	},
+}
+
+// bufferClass returns the smallest size class for n bytes.
+// The second result is false if n is too large for pooling.
+func bufferClass(n int) (int, bool) {
+	c := max(bits.Len(uint(max(n, 1)-1)), minBufferClass)
+	return c, c <= maxBufferClass
+}
+
+// getBuffer returns a byte slice of length n with arbitrary content.
+//
+// It should be returned with putBuffer when it is no longer used (including all subslices).
+func getBuffer(n int) []byte {
+	c, ok := bufferClass(n)
+	if !ok {
+		return make([]byte, n)
+	}
+
+	if p, _ := bufferPools[c-minBufferClass].Get().(*[]byte); p != nil {
+		return (*p)[:n]
+	}
+
+	return make([]byte, n, 1<<c)
+}
+
+// putBuffer returns a byte slice obtained from getBuffer to the pool.
+//
+// Slices with capacities that do not match any size class are ignored.
+func putBuffer(b []byte) {
+	c, ok := bufferClass(cap(b))
+	if !ok || cap(b) != 1<<c {
+		return
+	}
+
+	b = b[:0]
+	bufferPools[c-minBufferClass].Put(&b)
+}
+
+// buffer is an io.Writer that appends to a pooled byte slice, growing it by size classes.
+type buffer struct {
+	b []byte
+}
+
+// newBuffer returns a new buffer with the capacity of at least n bytes.
+//
+// It should be released when it is no longer used.
+func newBuffer(n int) *buffer {
+	return &buffer{
+		b: getBuffer(n)[:0],
+	}
+}
+
+// Write implements io.Writer interface.
+func (buf *buffer) Write(p []byte) (int, error) {
+	if l := len(buf.b) + len(p); l > cap(buf.b) {
+		b := getBuffer(l)[:len(buf.b)]
+		copy(b, buf.b)
+		putBuffer(buf.b)
+		buf.b = b
+	}
+
+	buf.b = append(buf.b, p...)
+
+	return len(p), nil
+}
+
+// release returns the underlying byte slice to the pool.
+func (buf *buffer) release() {
+	putBuffer(buf.b)
+	buf.b = nil
+}
+
+// getBufioWriter returns a pooled *bufio.Writer that writes to buf.
+//
+// It should be returned with putBufioWriter after flushing.
+func getBufioWriter(buf *buffer) *bufio.Writer {
+	w := bufioWriterPool.Get().(*bufio.Writer)
+	w.Reset(buf)
+
+	return w
+}
+
+// putBufioWriter returns *bufio.Writer obtained from getBufioWriter to the pool.
+func putBufioWriter(w *bufio.Writer) {
+	w.Reset(nil)
+	bufioWriterPool.Put(w)
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 FerretDB Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wire
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/FerretDB/FerretDB/internal/types"
+	"github.com/FerretDB/FerretDB/internal/util/must"
+)
+
+func TestBufferClass(t *testing.T) {
+	t.Parallel()
+
+	for n, expected := range map[int]int{
+		0:                     minBufferClass,
+		1:                     minBufferClass,
+		1 << minBufferClass:   minBufferClass,
+		1<<minBufferClass + 1: minBufferClass + 1,
+		3000:                  12,
+		1 << maxBufferClass:   maxBufferClass,
+		1<<maxBufferClass + 1: maxBufferClass + 1,
+	} {
+		c, ok := bufferClass(n)
+		assert.Equal(t, expected, c, "n=%d", n)
+		assert.Equal(t, expected <= maxBufferClass, ok, "n=%d", n)
+	}
+}
+
+func TestBuffer(t *testing.T) {
+	t.Parallel()
+
+	b := getBuffer(3000)
+	assert.Len(t, b, 3000)
+	assert.Equal(t, 4096, cap(b))
+	putBuffer(b)
+
+	b = getBuffer(1<<maxBufferClass + 1)
+	assert.Len(t, b, 1<<maxBufferClass+1)
+	putBuffer(b) // ignored
+
+	buf := newBuffer(0)
+	assert.Equal(t, 1<<minBufferClass, cap(buf.b))
+
+	var expected []byte
+
+	for i := range 100 {
+		p := bytes.Repeat([]byte{byte(i)}, i*10)
+		expected = append(expected, p...)
+
+		n, err := buf.Write(p)
+		require.NoError(t, err)
+		assert.Equal(t, len(p), n)
+	}
+
+	assert.Equal(t, expected, buf.b)
+	assert.Equal(t, 1<<16, cap(buf.b))
+
+	buf.release()
+	assert.Nil(t, buf.b)
+}
+
+func TestOpReplyPooled(t *testing.T) {
+	t.Parallel()
+
+	var reply OpReply
+	reply.SetDocument(must.NotFail(types.NewDocument("ok", float64(1))))
+
+	body, err := reply.MarshalBinary()
+	require.NoError(t, err)
+
+	header := &MsgHeader{
+		MessageLength: int32(MsgHeaderLen + len(body)),
+		RequestID:     1,
+		OpCode:        OpCodeReply,
+	}
+
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	require.NoError(t, WriteMessage(w, header, &reply))
+	require.NoError(t, w.Flush())
+
+	_, msg, err := ReadMessage(bufio.NewReader(&b))
+	require.NoError(t, err)
+
+	// the read buffer was returned to the pool; the document should not depend on it
+	for range 10 {
+		buf := getBuffer(len(body))
+		for i := range buf {
+			buf[i] = 0xff
+		}
+		putBuffer(buf)
+	}
+
+	doc, err := msg.(*OpReply).Document()
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), must.NotFail(doc.Get("ok")))
+}
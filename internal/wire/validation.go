@@ -16,6 +16,7 @@ package wire
 
 import (
 	"errors"
+	"fmt"
 	"math"
 
 	"github.com/FerretDB/FerretDB/internal/types"
@@ -32,6 +33,17 @@ func (v *ValidationError) Error() string {
 	return v.err.Error()
 }
 
+// DocumentTooLargeError is returned when received document exceeds the maximum BSON object size.
+type DocumentTooLargeError struct {
+	Len    int32 // actual document length
+	MaxLen int32 // maximum allowed length for that document
+}
+
+// Error implements error interface.
+func (e *DocumentTooLargeError) Error() string {
+	return fmt.Sprintf("document length %d exceeds the maximum of %d bytes", e.Len, e.MaxLen)
+}
+
 // newValidationError returns new ValidationError.
 //
 // Remove and make callers use validateValue only?